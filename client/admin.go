@@ -0,0 +1,148 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Health retorna o estado da API. verbose inclui os detalhes das
+// dependências e requer um token de admin
+func (c *Client) Health(ctx context.Context, verbose bool) (*HealthReport, error) {
+	var query url.Values
+	if verbose {
+		query = url.Values{"verbose": {strconv.FormatBool(true)}}
+	}
+
+	var report HealthReport
+	if err := c.get(ctx, "/health", query, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// ErrorCodes retorna o catálogo dos códigos de erro da API
+func (c *Client) ErrorCodes(ctx context.Context) ([]ErrorDefinition, error) {
+	var codes []ErrorDefinition
+	if err := c.get(ctx, "/api/v1/errors", nil, &codes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// AdminOverview retorna a visão geral da API (admin)
+func (c *Client) AdminOverview(ctx context.Context) (*AdminOverview, error) {
+	var overview AdminOverview
+	if err := c.get(ctx, "/api/v1/admin/overview", nil, &overview); err != nil {
+		return nil, err
+	}
+	return &overview, nil
+}
+
+// Routes retorna a matriz de segurança das rotas (admin)
+func (c *Client) Routes(ctx context.Context) ([]RouteSecurity, error) {
+	var routes []RouteSecurity
+	if err := c.get(ctx, "/api/v1/admin/routes", nil, &routes); err != nil {
+		return nil, err
+	}
+	return routes, nil
+}
+
+// DisableUser desativa a conta de um usuário (admin)
+func (c *Client) DisableUser(ctx context.Context, id, reason string) (*UserResponse, error) {
+	return c.userAction(ctx, id, "disable", &DisableUserInput{Reason: reason})
+}
+
+// EnableUser reativa a conta de um usuário (admin)
+func (c *Client) EnableUser(ctx context.Context, id string) (*UserResponse, error) {
+	return c.userAction(ctx, id, "enable", nil)
+}
+
+// ForcePasswordReset exige que o usuário redefina a senha (admin)
+func (c *Client) ForcePasswordReset(ctx context.Context, id string) (*UserResponse, error) {
+	return c.userAction(ctx, id, "force-password-reset", nil)
+}
+
+// userAction executa uma ação administrativa sobre a conta de um usuário
+func (c *Client) userAction(ctx context.Context, id, action string, body interface{}) (*UserResponse, error) {
+	var user UserResponse
+	if err := c.do(ctx, http.MethodPost, "/api/v1/admin/users/"+escape(id)+"/"+action, body, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// UserLoginHistory lista as tentativas de login de um usuário (admin)
+func (c *Client) UserLoginHistory(ctx context.Context, id string, opts *ListOptions) (*Page[LoginAttempt], error) {
+	return list[LoginAttempt](ctx, c, "/api/v1/admin/users/"+escape(id)+"/login-history", opts.query())
+}
+
+// RecordingSettings retorna a configuração da gravação de requisições (admin)
+func (c *Client) RecordingSettings(ctx context.Context) (*RecordingSettings, error) {
+	var settings RecordingSettings
+	if err := c.get(ctx, "/api/v1/admin/recordings/settings", nil, &settings); err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// UpdateRecordingSettings substitui a configuração da gravação (admin)
+func (c *Client) UpdateRecordingSettings(ctx context.Context, settings *RecordingSettings) (*RecordingSettings, error) {
+	var updated RecordingSettings
+	if err := c.do(ctx, http.MethodPut, "/api/v1/admin/recordings/settings", settings, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// Recordings lista as requisições gravadas (admin)
+func (c *Client) Recordings(ctx context.Context) ([]Recording, error) {
+	var recordings []Recording
+	if err := c.get(ctx, "/api/v1/admin/recordings", nil, &recordings); err != nil {
+		return nil, err
+	}
+	return recordings, nil
+}
+
+// ReplayRecording reproduz uma requisição gravada (admin)
+func (c *Client) ReplayRecording(ctx context.Context, id string) (*ReplayResult, error) {
+	var result ReplayResult
+	if err := c.do(ctx, http.MethodPost, "/api/v1/admin/recordings/"+escape(id)+"/replay", nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// PreviewMail renderiza um template de email com os dados de exemplo
+// (version 0 usa a versão mais recente) (admin)
+func (c *Client) PreviewMail(ctx context.Context, template string, version int) (*MailPreview, error) {
+	var preview MailPreview
+	if err := c.get(ctx, "/api/v1/admin/mail/preview/"+escape(template), mailPreviewQuery(version, ""), &preview); err != nil {
+		return nil, err
+	}
+	return &preview, nil
+}
+
+// PreviewMailBody retorna apenas o corpo renderizado de um template, no
+// formato "html" ou "text" (admin)
+func (c *Client) PreviewMailBody(ctx context.Context, template string, version int, format string) (string, error) {
+	var body bytes.Buffer
+	if err := c.get(ctx, "/api/v1/admin/mail/preview/"+escape(template), mailPreviewQuery(version, format), &body); err != nil {
+		return "", err
+	}
+	return body.String(), nil
+}
+
+// mailPreviewQuery monta os parâmetros da pré-visualização
+func mailPreviewQuery(version int, format string) url.Values {
+	query := url.Values{}
+	if version > 0 {
+		query.Set("version", strconv.Itoa(version))
+	}
+	if format != "" {
+		query.Set("format", format)
+	}
+	return query
+}
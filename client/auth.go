@@ -0,0 +1,193 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Register registra um novo usuário
+func (c *Client) Register(ctx context.Context, input *RegisterUserInput) (*UserResponse, error) {
+	var user UserResponse
+	if err := c.do(ctx, http.MethodPost, "/api/v1/auth/register", input, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Login autentica o usuário. O token de acesso retornado deve ser definido
+// com WithToken para as chamadas autenticadas
+func (c *Client) Login(ctx context.Context, input *LoginInput) (*LoginResult, error) {
+	var result LoginResult
+	if err := c.do(ctx, http.MethodPost, "/api/v1/auth/login", input, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// RefreshToken troca o token de atualização por um novo par de tokens
+func (c *Client) RefreshToken(ctx context.Context, refreshToken string) (*TokenPair, error) {
+	body := map[string]string{"refresh_token": refreshToken}
+
+	var tokens TokenPair
+	if err := c.do(ctx, http.MethodPost, "/api/v1/auth/refresh", body, &tokens); err != nil {
+		return nil, err
+	}
+	return &tokens, nil
+}
+
+// ResetPassword redefine a senha com o token enviado por email
+func (c *Client) ResetPassword(ctx context.Context, input *ResetPasswordInput) error {
+	return c.do(ctx, http.MethodPost, "/api/v1/auth/password/reset", input, nil)
+}
+
+// Profile retorna o perfil do usuário autenticado
+func (c *Client) Profile(ctx context.Context) (*UserResponse, error) {
+	var user UserResponse
+	if err := c.get(ctx, "/api/v1/auth/profile", nil, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// UpdateProfile altera o nome do usuário autenticado
+func (c *Client) UpdateProfile(ctx context.Context, name string) (*UserResponse, error) {
+	var user UserResponse
+	if err := c.do(ctx, http.MethodPut, "/api/v1/auth/profile", map[string]string{"name": name}, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// PatchProfile altera apenas os campos informados do perfil
+func (c *Client) PatchProfile(ctx context.Context, input *UpdateProfileInput) (*UserResponse, error) {
+	var user UserResponse
+	if err := c.do(ctx, http.MethodPatch, "/api/v1/auth/profile", input, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// UploadAvatar envia a imagem do avatar do usuário autenticado
+func (c *Client) UploadAvatar(ctx context.Context, filename string, image io.Reader) (*UserResponse, error) {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("avatar", filename)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(part, image); err != nil {
+		return nil, fmt.Errorf("ler a imagem do avatar: %w", err)
+	}
+	if err := form.Close(); err != nil {
+		return nil, err
+	}
+
+	req := &request{
+		method:      http.MethodPut,
+		path:        "/api/v1/auth/profile/avatar",
+		body:        bytes.NewReader(body.Bytes()),
+		contentType: form.FormDataContentType(),
+	}
+
+	var user UserResponse
+	if _, err := c.send(ctx, req, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// LoginHistory lista as tentativas de login do usuário autenticado
+func (c *Client) LoginHistory(ctx context.Context, opts *ListOptions) (*Page[LoginAttempt], error) {
+	return list[LoginAttempt](ctx, c, "/api/v1/auth/login-history", opts.query())
+}
+
+// Usage retorna o uso da cota do usuário autenticado
+func (c *Client) Usage(ctx context.Context) (*QuotaUsage, error) {
+	var usage QuotaUsage
+	if err := c.get(ctx, "/api/v1/auth/usage", nil, &usage); err != nil {
+		return nil, err
+	}
+	return &usage, nil
+}
+
+// NotificationPreferences retorna as preferências de notificação
+func (c *Client) NotificationPreferences(ctx context.Context) (*NotificationPreferences, error) {
+	var prefs NotificationPreferences
+	if err := c.get(ctx, "/api/v1/auth/notifications/preferences", nil, &prefs); err != nil {
+		return nil, err
+	}
+	return &prefs, nil
+}
+
+// UpdateNotificationPreferences substitui as preferências de notificação
+func (c *Client) UpdateNotificationPreferences(ctx context.Context, input *UpdateNotificationPreferencesInput) (*NotificationPreferences, error) {
+	var prefs NotificationPreferences
+	if err := c.do(ctx, http.MethodPut, "/api/v1/auth/notifications/preferences", input, &prefs); err != nil {
+		return nil, err
+	}
+	return &prefs, nil
+}
+
+// ListNotifications lista as notificações da caixa de entrada, opcionalmente
+// apenas as não lidas
+func (c *Client) ListNotifications(ctx context.Context, unreadOnly bool, opts *ListOptions) (*Page[Notification], error) {
+	query := opts.query()
+	if unreadOnly {
+		query.Set("unread", strconv.FormatBool(true))
+	}
+	return list[Notification](ctx, c, "/api/v1/notifications", query)
+}
+
+// MarkNotificationRead marca uma notificação como lida
+func (c *Client) MarkNotificationRead(ctx context.Context, id string) (*Notification, error) {
+	var notification Notification
+	if err := c.do(ctx, http.MethodPost, "/api/v1/notifications/"+escape(id)+"/read", nil, &notification); err != nil {
+		return nil, err
+	}
+	return &notification, nil
+}
+
+// MarkAllNotificationsRead marca todas as notificações como lidas
+func (c *Client) MarkAllNotificationsRead(ctx context.Context) (*NotificationsRead, error) {
+	var result NotificationsRead
+	if err := c.do(ctx, http.MethodPost, "/api/v1/notifications/read-all", nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// WebhookDeliveries lista as entregas recentes ao webhook (webhook_id das
+// preferências de notificação)
+func (c *Client) WebhookDeliveries(ctx context.Context, webhookID string) ([]WebhookDelivery, error) {
+	var deliveries []WebhookDelivery
+	if err := c.get(ctx, "/api/v1/webhooks/"+escape(webhookID)+"/deliveries", nil, &deliveries); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// RedeliverWebhook reenvia um evento ao webhook. O resultado da nova
+// tentativa está em Status e LastError
+func (c *Client) RedeliverWebhook(ctx context.Context, webhookID, eventID string) (*WebhookDelivery, error) {
+	var delivery WebhookDelivery
+	path := "/api/v1/webhooks/" + escape(webhookID) + "/redeliver/" + escape(eventID)
+	if err := c.do(ctx, http.MethodPost, path, nil, &delivery); err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// Avatar baixa a imagem de um avatar pelo nome retornado em avatar_url
+func (c *Client) Avatar(ctx context.Context, name string) ([]byte, error) {
+	var body bytes.Buffer
+	if err := c.get(ctx, "/api/v1/avatars/"+url.PathEscape(name), nil, &body); err != nil {
+		return nil, err
+	}
+	return body.Bytes(), nil
+}
@@ -0,0 +1,212 @@
+// Package client é o cliente Go tipado da API, para que outros serviços Go
+// consumam os endpoints sem duplicar os modelos.
+//
+// As requisições pedem os recursos sem envelope (Accept com profile="bare"):
+// os métodos retornam diretamente os recursos e as listagens trazem os
+// metadados de paginação em Page. As respostas de erro são retornadas como
+// *Error, com o código estável do catálogo (ver ErrorCodes).
+//
+// Por padrão as chamadas usam o cliente de pkg/httpclient, que propaga o ID
+// da requisição e o trace do contexto e repete as falhas transitórias dos
+// métodos idempotentes.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"callable-api/internal/models"
+	"callable-api/pkg/httpclient"
+)
+
+// bareAccept pede os recursos sem o envelope models.Response
+const bareAccept = `application/json; profile="bare"`
+
+// Client chama os endpoints da API
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	token      string
+	deviceID   string
+}
+
+// New cria um cliente para a API em baseURL (ex.: https://api.example.com)
+func New(baseURL string) *Client {
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: httpclient.New(httpclient.DefaultConfig()),
+	}
+}
+
+// WithHTTPClient define o cliente HTTP usado nas chamadas
+func (c *Client) WithHTTPClient(httpClient *http.Client) *Client {
+	c.httpClient = httpClient
+	return c
+}
+
+// WithToken define o token enviado no header Authorization: o token de
+// acesso JWT ou, nos endpoints SCIM, o token do provedor de identidade
+func (c *Client) WithToken(token string) *Client {
+	c.token = token
+	return c
+}
+
+// WithDeviceID define o identificador do dispositivo (X-Device-ID), ao qual
+// o servidor vincula os tokens de atualização do login e da renovação
+func (c *Client) WithDeviceID(deviceID string) *Client {
+	c.deviceID = deviceID
+	return c
+}
+
+// Page é uma página de uma listagem
+type Page[T any] struct {
+	Items    []T
+	Page     int
+	PageSize int
+	Total    int
+}
+
+// ListOptions define a página e os filtros de uma listagem. Valores zerados
+// usam os padrões da API
+type ListOptions struct {
+	Page  int
+	Limit int
+
+	// Filters são parâmetros adicionais da listagem (ex.: value[lt]=10)
+	Filters url.Values
+}
+
+// query converte as opções nos parâmetros da requisição
+func (o *ListOptions) query() url.Values {
+	query := url.Values{}
+	if o == nil {
+		return query
+	}
+	for key, values := range o.Filters {
+		query[key] = append([]string(nil), values...)
+	}
+	if o.Page > 0 {
+		query.Set("page", strconv.Itoa(o.Page))
+	}
+	if o.Limit > 0 {
+		query.Set("limit", strconv.Itoa(o.Limit))
+	}
+	return query
+}
+
+// request descreve uma chamada à API
+type request struct {
+	method      string
+	path        string
+	query       url.Values
+	header      http.Header
+	body        io.Reader
+	contentType string
+}
+
+// jsonRequest cria uma chamada com o corpo serializado em JSON (nil envia a
+// chamada sem corpo)
+func jsonRequest(method, path string, body interface{}) (*request, error) {
+	req := &request{method: method, path: path}
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("serializar o corpo da requisição: %w", err)
+		}
+		req.body = bytes.NewReader(data)
+		req.contentType = "application/json"
+	}
+	return req, nil
+}
+
+// send executa a chamada e decodifica o corpo JSON da resposta em out (nil
+// descarta o corpo; um io.ReaderFrom recebe o corpo sem decodificação).
+// Respostas com status de erro são retornadas como *Error
+func (c *Client) send(ctx context.Context, r *request, out interface{}) (*http.Response, error) {
+	target := c.baseURL + r.path
+	if len(r.query) > 0 {
+		target += "?" + r.query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, r.method, target, r.body)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range r.header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Accept", bareAccept)
+	if r.contentType != "" {
+		req.Header.Set("Content-Type", r.contentType)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if c.deviceID != "" {
+		req.Header.Set(models.DeviceIDHeader, c.deviceID)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return resp, newError(resp)
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		io.Copy(io.Discard, resp.Body)
+		return resp, nil
+	}
+	if w, ok := out.(io.ReaderFrom); ok {
+		_, err := w.ReadFrom(resp.Body)
+		return resp, err
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil && err != io.EOF {
+		return resp, fmt.Errorf("decodificar a resposta de %s %s: %w", r.method, r.path, err)
+	}
+	return resp, nil
+}
+
+// do executa uma chamada JSON e decodifica a resposta em out
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	req, err := jsonRequest(method, path, body)
+	if err != nil {
+		return err
+	}
+	_, err = c.send(ctx, req, out)
+	return err
+}
+
+// get executa um GET com os parâmetros e decodifica a resposta em out
+func (c *Client) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	_, err := c.send(ctx, &request{method: http.MethodGet, path: path, query: query}, out)
+	return err
+}
+
+// list executa um GET de uma listagem paginada, cujos metadados vêm nos
+// headers X-Total-Count, X-Page e X-Page-Size
+func list[T any](ctx context.Context, c *Client, path string, query url.Values) (*Page[T], error) {
+	page := &Page[T]{}
+	resp, err := c.send(ctx, &request{method: http.MethodGet, path: path, query: query}, &page.Items)
+	if err != nil {
+		return nil, err
+	}
+	page.Total, _ = strconv.Atoi(resp.Header.Get("X-Total-Count"))
+	page.Page, _ = strconv.Atoi(resp.Header.Get("X-Page"))
+	page.PageSize, _ = strconv.Atoi(resp.Header.Get("X-Page-Size"))
+	return page, nil
+}
+
+// escape codifica um segmento do caminho
+func escape(segment string) string {
+	return url.PathEscape(segment)
+}
@@ -0,0 +1,183 @@
+package client_test
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"callable-api/client"
+	"callable-api/internal/filter"
+	"callable-api/internal/handlers"
+	"callable-api/internal/jobs"
+	"callable-api/internal/models"
+	"callable-api/pkg/errors"
+)
+
+// itemStore implementa o serviço de itens usado pelo handler
+type itemStore struct {
+	handlers.ItemServiceInterface
+
+	mu    sync.Mutex
+	items []models.Item
+}
+
+func (s *itemStore) GetItems(ctx context.Context, viewer models.Viewer, page, limit int, conditions ...filter.Condition) ([]models.Item, int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	start := min((page-1)*limit, len(s.items))
+	end := min(start+limit, len(s.items))
+	return append([]models.Item(nil), s.items[start:end]...), len(s.items), nil
+}
+
+func (s *itemStore) GetItemByID(ctx context.Context, viewer models.Viewer, id string) (*models.Item, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, item := range s.items {
+		if item.ID == id {
+			return &item, nil
+		}
+	}
+	return nil, errors.NewNotFoundError("Item não encontrado", nil)
+}
+
+func (s *itemStore) CreateItem(ctx context.Context, viewer models.Viewer, input *models.InputData) (*models.Item, error) {
+	if input.Value == "falha" {
+		return nil, errors.NewConflictError("Item duplicado", nil)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	item := models.Item{ID: input.Name, Name: input.Name, Value: input.Value, Email: input.Email}
+	s.items = append(s.items, item)
+	return &item, nil
+}
+
+// newServer sobe a API de itens e de jobs com os handlers reais
+func newServer(t *testing.T, async bool) (*client.Client, *itemStore) {
+	gin.SetMode(gin.TestMode)
+
+	store := &itemStore{}
+	itemHandler := handlers.NewItemHandler(store)
+
+	router := gin.New()
+	if async {
+		manager := jobs.NewManager(jobs.NewMemoryStore(), jobs.Config{Workers: 1, QueueSize: 10})
+		t.Cleanup(func() { manager.Close(context.Background()) })
+		itemHandler.WithAsyncCreate(manager, handlers.CreateModeAsync)
+		router.GET("/api/v1/jobs/:id", handlers.NewJobHandler(manager).GetJob)
+	}
+	router.GET("/api/v1/data", itemHandler.GetData)
+	router.GET("/api/v1/data/:id", itemHandler.GetDataById)
+	router.POST("/api/v1/data", itemHandler.PostData)
+	router.POST("/api/v1/data/bulk", itemHandler.PostDataBulk)
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return client.New(server.URL + "/").WithHTTPClient(server.Client()), store
+}
+
+func input(name, value string) *client.InputData {
+	return &client.InputData{Name: name, Value: value, Email: "ana@example.com"}
+}
+
+func TestClient_Items(t *testing.T) {
+	ctx := context.Background()
+	c, _ := newServer(t, true)
+
+	// Criado na requisição mesmo com o servidor no modo assíncrono
+	for _, name := range []string{"abc", "def", "ghi"} {
+		item, err := c.CreateItem(ctx, input(name, "1"))
+		require.NoError(t, err)
+		assert.Equal(t, name, item.ID)
+	}
+
+	page, err := c.ListItems(ctx, &client.ListOptions{Page: 2, Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, 3, page.Total)
+	assert.Equal(t, 2, page.Page)
+	assert.Equal(t, 2, page.PageSize)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, "ghi", page.Items[0].Name)
+
+	item, err := c.GetItem(ctx, "def")
+	require.NoError(t, err)
+	assert.Equal(t, "def", item.Name)
+
+	_, err = c.GetItem(ctx, "xyz")
+	var apiErr *client.Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, 404, apiErr.StatusCode)
+	assert.Equal(t, "ITEM_NOT_FOUND", client.ErrorCode(err))
+	assert.NotEmpty(t, apiErr.Message)
+}
+
+func TestClient_CreateItems(t *testing.T) {
+	ctx := context.Background()
+	c, store := newServer(t, false)
+
+	results, err := c.CreateItems(ctx, []client.InputData{*input("abc", "1"), *input("def", "falha")})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "abc", results[0].Item.ID)
+	assert.Equal(t, 409, results[1].Status)
+	assert.Equal(t, "CONFLICT", results[1].Code)
+	assert.Len(t, store.items, 1)
+
+	// Um elemento inválido rejeita o lote
+	_, err = c.CreateItems(ctx, []client.InputData{*input("ghi", "1"), *input("", "1")})
+	assert.Equal(t, 400, client.StatusCode(err))
+	assert.Len(t, store.items, 1)
+}
+
+func TestClient_CreateItemAsync(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	c, _ := newServer(t, true)
+
+	job, err := c.CreateItemAsync(ctx, input("abc", "1"))
+	require.NoError(t, err)
+	require.NotEmpty(t, job.ID)
+
+	job, err = c.WaitJob(ctx, job.ID, 10*time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, client.JobStateCompleted, job.State)
+
+	item, err := client.ItemResult(job)
+	require.NoError(t, err)
+	assert.Equal(t, "abc", item.Name)
+
+	// Um job que falha é retornado com um *JobError
+	job, err = c.CreateItemAsync(ctx, input("def", "falha"))
+	require.NoError(t, err)
+	job, err = c.WaitJob(ctx, job.ID, 10*time.Millisecond)
+	var jobErr *client.JobError
+	require.True(t, stderrors.As(err, &jobErr))
+	assert.Equal(t, client.JobStateFailed, job.State)
+	_, err = client.ItemResult(job)
+	assert.Error(t, err)
+
+	_, err = c.GetJob(ctx, "inexistente")
+	assert.Equal(t, 404, client.StatusCode(err))
+}
+
+func TestClient_CreateItemAsync_SyncServer(t *testing.T) {
+	ctx := context.Background()
+	c, store := newServer(t, false)
+
+	// Sem jobs no servidor, o item é criado na requisição
+	job, err := c.CreateItemAsync(ctx, input("abc", "1"))
+	require.NoError(t, err)
+	assert.Empty(t, job.ID)
+	assert.Equal(t, client.JobStateCompleted, job.State)
+
+	item, err := client.ItemResult(job)
+	require.NoError(t, err)
+	assert.Equal(t, "abc", item.Name)
+	assert.Len(t, store.items, 1)
+}
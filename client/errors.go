@@ -0,0 +1,67 @@
+package client
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxErrorBody limita a leitura do corpo das respostas de erro
+const maxErrorBody = 64 << 10
+
+// Error é uma resposta de erro da API
+type Error struct {
+	StatusCode int
+	APIError
+
+	// Body é o corpo da resposta quando ele não é um APIError (ex.: os erros
+	// SCIM ou respostas de proxies)
+	Body string
+}
+
+// Error implementa a interface error
+func (e *Error) Error() string {
+	code := e.ErrorCode
+	if code == "" {
+		code = http.StatusText(e.StatusCode)
+	}
+	message := e.Message
+	if message == "" {
+		message = e.Body
+	}
+	return fmt.Sprintf("callable-api: %d %s: %s", e.StatusCode, code, message)
+}
+
+// newError lê a resposta de erro
+func newError(resp *http.Response) *Error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	apiErr := &Error{StatusCode: resp.StatusCode}
+	if json.Unmarshal(body, &apiErr.APIError) != nil || apiErr.Status != "error" {
+		apiErr.APIError = APIError{}
+		apiErr.Body = strings.TrimSpace(string(body))
+	}
+	return apiErr
+}
+
+// ErrorCode retorna o código do catálogo de um erro da API, ou "" quando err
+// não é uma resposta de erro da API
+func ErrorCode(err error) string {
+	var apiErr *Error
+	if stderrors.As(err, &apiErr) {
+		return apiErr.ErrorCode
+	}
+	return ""
+}
+
+// StatusCode retorna o status HTTP de um erro da API, ou 0 quando err não é
+// uma resposta de erro da API
+func StatusCode(err error) int {
+	var apiErr *Error
+	if stderrors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
@@ -0,0 +1,188 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ListItems lista os itens visíveis ao usuário. Os filtros seguem os
+// operadores de GET /api/v1/data (ex.: created_at[gte], value[lt])
+func (c *Client) ListItems(ctx context.Context, opts *ListOptions) (*Page[Item], error) {
+	return list[Item](ctx, c, "/api/v1/data", opts.query())
+}
+
+// SearchItems busca os itens pelo texto
+func (c *Client) SearchItems(ctx context.Context, q string, opts *ListOptions) (*Page[Item], error) {
+	query := opts.query()
+	query.Set("q", q)
+	return list[Item](ctx, c, "/api/v1/data/search", query)
+}
+
+// ItemChanges lê o log de alterações dos itens a partir do cursor (vazio
+// para começar pela alteração mais antiga mantida). Repita com
+// page.NextCursor enquanto page.HasMore; um *Error com StatusCode 410 indica
+// que o cursor expirou e a sincronização deve recomeçar por ListItems
+func (c *Client) ItemChanges(ctx context.Context, cursor string, limit int) (*ItemChangePage, error) {
+	query := url.Values{}
+	if cursor != "" {
+		query.Set("since", cursor)
+	}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+
+	var page ItemChangePage
+	if err := c.get(ctx, "/api/v1/data/changes", query, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// GetItem retorna um item
+func (c *Client) GetItem(ctx context.Context, id string) (*Item, error) {
+	var item Item
+	if err := c.get(ctx, "/api/v1/data/"+escape(id), nil, &item); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// CreateItem cria um item na própria requisição, mesmo que o servidor crie
+// os itens em segundo plano por padrão
+func (c *Client) CreateItem(ctx context.Context, input *InputData) (*Item, error) {
+	req, err := jsonRequest(http.MethodPost, "/api/v1/data", input)
+	if err != nil {
+		return nil, err
+	}
+	req.header = http.Header{"Prefer": {"return=representation"}}
+
+	var item Item
+	if _, err := c.send(ctx, req, &item); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// CreateItems cria vários itens em uma requisição. Um elemento inválido
+// rejeita o lote inteiro; falhas na criação de itens individuais (como itens
+// duplicados) vêm no resultado de cada elemento
+func (c *Client) CreateItems(ctx context.Context, inputs []InputData) ([]BulkItemResult, error) {
+	req, err := jsonRequest(http.MethodPost, "/api/v1/data/bulk", inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []BulkItemResult
+	if _, err := c.send(ctx, req, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// CreateItemAsync enfileira a criação de um item e retorna o job, que pode
+// ser acompanhado com WaitJob. O resultado do job concluído é o item (ver
+// ItemResult). Se o servidor não executa jobs, o item é criado na própria
+// requisição e o job retornado já está concluído, sem ID
+func (c *Client) CreateItemAsync(ctx context.Context, input *InputData) (*JobStatus, error) {
+	return c.createItemAsync(ctx, input, nil)
+}
+
+// ScheduleItem agenda a criação de um item para runAt
+func (c *Client) ScheduleItem(ctx context.Context, input *InputData, runAt time.Time) (*JobStatus, error) {
+	return c.createItemAsync(ctx, input, url.Values{"run_at": {runAt.UTC().Format(time.RFC3339)}})
+}
+
+// createItemAsync implementa CreateItemAsync e ScheduleItem
+func (c *Client) createItemAsync(ctx context.Context, input *InputData, query url.Values) (*JobStatus, error) {
+	req, err := jsonRequest(http.MethodPost, "/api/v1/data", input)
+	if err != nil {
+		return nil, err
+	}
+	req.query = query
+	req.header = http.Header{"Prefer": {"respond-async"}}
+
+	var body json.RawMessage
+	resp, err := c.send(ctx, req, &body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusAccepted {
+		var item Item
+		if err := json.Unmarshal(body, &item); err != nil {
+			return nil, fmt.Errorf("decodificar o item criado: %w", err)
+		}
+		return &JobStatus{Type: "item.create", State: JobStateCompleted, Result: &item}, nil
+	}
+
+	var job JobStatus
+	if err := json.Unmarshal(body, &job); err != nil {
+		return nil, fmt.Errorf("decodificar o job: %w", err)
+	}
+	return &job, nil
+}
+
+// ItemResult retorna o item criado por um job de criação concluído
+func ItemResult(job *JobStatus) (*Item, error) {
+	var item Item
+	if err := DecodeResult(job, &item); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// UpdateItem substitui os dados de um item
+func (c *Client) UpdateItem(ctx context.Context, id string, input *InputData) (*Item, error) {
+	var item Item
+	if err := c.do(ctx, http.MethodPut, "/api/v1/data/"+escape(id), input, &item); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// ShareItem compartilha um item com um usuário ou papel
+func (c *Client) ShareItem(ctx context.Context, id string, input *ShareItemInput) (*ItemShare, error) {
+	var share ItemShare
+	if err := c.do(ctx, http.MethodPost, "/api/v1/data/"+escape(id)+"/share", input, &share); err != nil {
+		return nil, err
+	}
+	return &share, nil
+}
+
+// ListItemShares lista os compartilhamentos de um item
+func (c *Client) ListItemShares(ctx context.Context, id string) ([]ItemShare, error) {
+	var shares []ItemShare
+	if err := c.get(ctx, "/api/v1/data/"+escape(id)+"/shares", nil, &shares); err != nil {
+		return nil, err
+	}
+	return shares, nil
+}
+
+// RevokeItemShare revoga um compartilhamento
+func (c *Client) RevokeItemShare(ctx context.Context, id, shareID string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/data/"+escape(id)+"/shares/"+escape(shareID), nil, nil)
+}
+
+// ListComments lista os comentários de um item
+func (c *Client) ListComments(ctx context.Context, itemID string, opts *ListOptions) (*Page[CommentResponse], error) {
+	return list[CommentResponse](ctx, c, "/api/v1/data/"+escape(itemID)+"/comments", opts.query())
+}
+
+// AddComment comenta um item
+func (c *Client) AddComment(ctx context.Context, itemID string, input *CreateCommentInput) (*CommentResponse, error) {
+	var comment CommentResponse
+	if err := c.do(ctx, http.MethodPost, "/api/v1/data/"+escape(itemID)+"/comments", input, &comment); err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// DeleteComment remove um comentário
+func (c *Client) DeleteComment(ctx context.Context, itemID, commentID string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/data/"+escape(itemID)+"/comments/"+escape(commentID), nil, nil)
+}
@@ -0,0 +1,144 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// DefaultPollInterval é o intervalo entre as consultas de WaitJob quando
+// nenhum é informado
+const DefaultPollInterval = time.Second
+
+// JobError indica que o job terminou com falha
+type JobError struct {
+	Job *JobStatus
+}
+
+// Error implementa a interface error
+func (e *JobError) Error() string {
+	if e.Job.ErrorCode != "" {
+		return fmt.Sprintf("job %s falhou (%s): %s", e.Job.ID, e.Job.ErrorCode, e.Job.Error)
+	}
+	return fmt.Sprintf("job %s falhou: %s", e.Job.ID, e.Job.Error)
+}
+
+// GetJob retorna o estado de um job
+func (c *Client) GetJob(ctx context.Context, id string) (*JobStatus, error) {
+	job, _, err := c.getJob(ctx, id, nil, "")
+	return job, err
+}
+
+// getJob consulta o job. Com etag, o servidor responde 304 se o job não
+// mudou, e cached é retornado sem decodificar nada
+func (c *Client) getJob(ctx context.Context, id string, cached *JobStatus, etag string) (*JobStatus, string, error) {
+	req := &request{method: http.MethodGet, path: "/api/v1/jobs/" + escape(id)}
+	if cached != nil && etag != "" {
+		req.header = http.Header{"If-None-Match": {etag}}
+	}
+
+	var job JobStatus
+	resp, err := c.send(ctx, req, &job)
+	if err != nil {
+		return nil, "", err
+	}
+	if resp.StatusCode == http.StatusNotModified {
+		return cached, etag, nil
+	}
+	return &job, resp.Header.Get("ETag"), nil
+}
+
+// WaitJob consulta o job a cada interval (0 usa DefaultPollInterval) até que
+// ele termine ou ctx expire. As consultas enviam o ETag da anterior, para que
+// o servidor responda 304 sem corpo enquanto o job não mudar. Um job
+// concluído com falha é retornado junto com um *JobError
+func (c *Client) WaitJob(ctx context.Context, id string, interval time.Duration) (*JobStatus, error) {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var job *JobStatus
+	var etag string
+	for {
+		var err error
+		job, etag, err = c.getJob(ctx, id, job, etag)
+		if err != nil {
+			return nil, err
+		}
+		if job.Finished() {
+			if job.Unsuccessful() {
+				return job, &JobError{Job: job}
+			}
+			return job, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return job, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// JobLogs retorna o log de execução de um job (as linhas mais recentes)
+func (c *Client) JobLogs(ctx context.Context, id string) (*JobLogs, error) {
+	var logs JobLogs
+	if err := c.get(ctx, "/api/v1/jobs/"+escape(id)+"/logs", nil, &logs); err != nil {
+		return nil, err
+	}
+	return &logs, nil
+}
+
+// DecodeResult decodifica em v o resultado de um job concluído
+func DecodeResult(job *JobStatus, v interface{}) error {
+	switch job.State {
+	case JobStateCompleted:
+	case JobStateFailed, JobStateTimedOut:
+		return &JobError{Job: job}
+	default:
+		return fmt.Errorf("job %s ainda não terminou (%s)", job.ID, job.State)
+	}
+
+	data, err := json.Marshal(job.Result)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// JobArtifact baixa o artefato gerado por um job, retornando o conteúdo e o
+// seu tipo. Os redirecionamentos para URLs assinadas são seguidos
+func (c *Client) JobArtifact(ctx context.Context, id string) ([]byte, string, error) {
+	var body bytes.Buffer
+	resp, err := c.send(ctx, &request{method: http.MethodGet, path: "/api/v1/jobs/" + escape(id) + "/artifact"}, &body)
+	if err != nil {
+		return nil, "", err
+	}
+	return body.Bytes(), resp.Header.Get("Content-Type"), nil
+}
+
+// JobStats retorna as estatísticas dos jobs (admin)
+func (c *Client) JobStats(ctx context.Context) (*JobStats, error) {
+	var stats JobStats
+	if err := c.get(ctx, "/api/v1/admin/jobs/stats", nil, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// CleanupJobs remove os jobs terminados há mais de olderThan (admin)
+func (c *Client) CleanupJobs(ctx context.Context, olderThan time.Duration) (*JobCleanup, error) {
+	var cleanup JobCleanup
+	req := &request{method: http.MethodPost, path: "/api/v1/admin/jobs/cleanup", query: url.Values{"older_than": {olderThan.String()}}}
+	if _, err := c.send(ctx, req, &cleanup); err != nil {
+		return nil, err
+	}
+	return &cleanup, nil
+}
@@ -0,0 +1,61 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"callable-api/internal/scim"
+)
+
+// Os endpoints SCIM usam o token do provedor de identidade (ver WithToken).
+// As respostas de erro SCIM são retornadas como *Error, com o corpo em Body
+
+// CreateSCIMUser provisiona um usuário
+func (c *Client) CreateSCIMUser(ctx context.Context, user *SCIMUser) (*SCIMUser, error) {
+	var created SCIMUser
+	if err := c.do(ctx, http.MethodPost, scim.BasePath+"/Users", user, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// ListSCIMUsers lista os usuários que atendem ao filtro SCIM (ex.: userName
+// eq "ana@example.com"). startIndex e count zerados usam os padrões da API
+func (c *Client) ListSCIMUsers(ctx context.Context, filter string, startIndex, count int) (*SCIMListResponse, error) {
+	query := url.Values{}
+	if filter != "" {
+		query.Set("filter", filter)
+	}
+	if startIndex > 0 {
+		query.Set("startIndex", strconv.Itoa(startIndex))
+	}
+	if count > 0 {
+		query.Set("count", strconv.Itoa(count))
+	}
+
+	var list SCIMListResponse
+	if err := c.get(ctx, scim.BasePath+"/Users", query, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// GetSCIMUser retorna um usuário
+func (c *Client) GetSCIMUser(ctx context.Context, id string) (*SCIMUser, error) {
+	var user SCIMUser
+	if err := c.get(ctx, scim.BasePath+"/Users/"+escape(id), nil, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// PatchSCIMUser altera atributos de um usuário; active=false desativa a conta
+func (c *Client) PatchSCIMUser(ctx context.Context, id string, patch *SCIMPatchRequest) (*SCIMUser, error) {
+	var user SCIMUser
+	if err := c.do(ctx, http.MethodPatch, scim.BasePath+"/Users/"+escape(id), patch, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
@@ -0,0 +1,78 @@
+package client
+
+import (
+	"callable-api/internal/errcodes"
+	"callable-api/internal/models"
+	"callable-api/internal/scim"
+)
+
+// Os modelos da API, reexportados para os serviços fora deste módulo, que não
+// podem importar os pacotes internal
+type (
+	APIError       = models.APIError
+	FieldViolation = models.FieldViolation
+
+	Item           = models.Item
+	InputData      = models.InputData
+	ItemShare      = models.ItemShare
+	ShareItemInput = models.ShareItemInput
+	BulkItemResult = models.BulkItemResult
+	ItemChange     = models.ItemChange
+	ItemChangePage = models.ItemChangePage
+
+	CommentResponse    = models.CommentResponse
+	CommentAuthor      = models.CommentAuthor
+	CreateCommentInput = models.CreateCommentInput
+
+	RegisterUserInput  = models.RegisterUserInput
+	LoginInput         = models.LoginInput
+	TokenPair          = models.TokenPair
+	ResetPasswordInput = models.ResetPasswordInput
+	UpdateProfileInput = models.UpdateProfileInput
+	UserResponse       = models.UserResponse
+	LoginAttempt       = models.LoginAttempt
+	DisableUserInput   = models.DisableUserInput
+	QuotaUsage         = models.QuotaUsage
+
+	NotificationPreferences            = models.NotificationPreferences
+	UpdateNotificationPreferencesInput = models.UpdateNotificationPreferencesInput
+	Notification                       = models.Notification
+	NotificationsRead                  = models.NotificationsRead
+	WebhookDelivery                    = models.WebhookDelivery
+
+	JobStatus   = models.JobStatus
+	JobStep     = models.JobStep
+	JobStats    = models.JobStats
+	JobCleanup  = models.JobCleanup
+	JobLogs     = models.JobLogs
+	JobLogEntry = models.JobLogEntry
+
+	AdminOverview     = models.AdminOverview
+	RouteSecurity     = models.RouteSecurity
+	Recording         = models.Recording
+	RecordingSettings = models.RecordingSettings
+	ReplayResult      = models.ReplayResult
+	MailPreview       = models.MailPreview
+	HealthReport      = models.HealthReport
+	ErrorDefinition   = errcodes.Definition
+
+	SCIMUser         = scim.User
+	SCIMListResponse = scim.ListResponse
+	SCIMPatchRequest = scim.PatchRequest
+)
+
+// Estados dos jobs
+const (
+	JobStateScheduled = models.JobStateScheduled
+	JobStatePending   = models.JobStatePending
+	JobStateRunning   = models.JobStateRunning
+	JobStateCompleted = models.JobStateCompleted
+	JobStateFailed    = models.JobStateFailed
+	JobStateTimedOut  = models.JobStateTimedOut
+)
+
+// LoginResult é a resposta do login: os tokens e o usuário autenticado
+type LoginResult struct {
+	Tokens TokenPair    `json:"tokens"`
+	User   UserResponse `json:"user"`
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"callable-api/internal/handlers"
+	"callable-api/internal/health"
+	"callable-api/internal/middleware"
+	"callable-api/internal/search"
+	"callable-api/pkg/auth"
+	"callable-api/pkg/cloud"
+	"callable-api/pkg/config"
+	"callable-api/pkg/httpclient"
+	"callable-api/pkg/mail"
+)
+
+// checkTimeout limita cada verificação de dependência no modo --check
+const checkTimeout = 5 * time.Second
+
+// sendGridAddr é o endpoint da API do SendGrid, verificado no modo --check
+const sendGridAddr = "api.sendgrid.com:443"
+
+// runCheck carrega e valida a configuração, verifica a conexão com todas as
+// dependências configuradas e imprime o relatório em out. Retorna erro se
+// alguma verificação falhar (para uso no CI e antes de cada deploy)
+func runCheck(out io.Writer) error {
+	cfg := config.Load()
+	mailCfg := loadMailConfig()
+
+	fmt.Fprintln(out, "Configuração:")
+	failures := 0
+	for _, check := range configChecks(cfg, mailCfg) {
+		status := "ok"
+		if check.err != nil {
+			status = "FALHA: " + check.err.Error()
+			failures++
+		}
+		fmt.Fprintf(out, "  %-20s %s\n", check.name, status)
+	}
+
+	// As verificações de conexão usam o proxy configurado
+	_ = httpclient.SetEgress(loadEgressConfig())
+
+	gcp := SetupGCPServices(cfg)
+	defer closeGCPServices(gcp)
+
+	fmt.Fprintln(out, "Dependências:")
+	checkers := preflightCheckers(cfg, mailCfg, gcp)
+	if len(checkers) == 0 {
+		fmt.Fprintln(out, "  nenhuma dependência externa configurada")
+	}
+	for _, result := range health.Run(context.Background(), checkTimeout, checkers...) {
+		status := fmt.Sprintf("ok (%.0fms)", result.LatencyMs)
+		if result.Status != health.StatusUp {
+			status = fmt.Sprintf("FALHA (%.0fms): %s", result.LatencyMs, result.Error)
+			failures++
+		}
+		fmt.Fprintf(out, "  %-20s %s\n", result.Name, status)
+	}
+
+	if failures > 0 {
+		return fmt.Errorf("verificação de inicialização falhou: %d problema(s)", failures)
+	}
+	fmt.Fprintln(out, "Tudo certo")
+	return nil
+}
+
+// configCheck é o resultado da validação de um item da configuração
+type configCheck struct {
+	name string
+	err  error
+}
+
+// configChecks valida a configuração que, na inicialização normal, só geraria
+// um log de erro ou impediria a subida do servidor
+func configChecks(cfg *config.Config, mailCfg mail.Config) []configCheck {
+	m, modeErr := checkMode(cfg, mailCfg)
+	_, mailErr := mail.NewSender(mailCfg)
+	_, createModeErr := handlers.ParseCreateMode(getEnv("ITEM_CREATE_MODE", ""))
+
+	var portErr error
+	for _, port := range append([]string{cfg.Port}, loadFallbackPorts()...) {
+		if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+			portErr = fmt.Errorf("porta inválida: %q", port)
+			break
+		}
+	}
+
+	proxyErr := middleware.TrustProxies(gin.New(), loadProxyConfig())
+	egressErr := loadEgressConfig().Validate()
+	idErr := loadIDConfig().Validate()
+	_, dataKeysErr := loadDataKeys()
+	bootstrapErr := loadBootstrapConfig().Validate()
+	chaosErr := loadChaosConfig().Allowed(m)
+
+	return []configCheck{
+		{name: "mode", err: modeErr},
+		{name: "port", err: portErr},
+		{name: "trusted_proxies", err: proxyErr},
+		{name: "egress", err: egressErr},
+		{name: "id_formats", err: idErr},
+		{name: "data_encryption", err: dataKeysErr},
+		{name: "bootstrap_admin", err: bootstrapErr},
+		{name: "mail", err: mailErr},
+		{name: "item_create_mode", err: createModeErr},
+		{name: "chaos", err: chaosErr},
+	}
+}
+
+// preflightCheckers monta as verificações de conexão com as dependências
+// configuradas. A verificação do Cloud Storage grava um pequeno objeto em
+// preflight/check
+func preflightCheckers(cfg *config.Config, mailCfg mail.Config, gcp cloud.Services) []health.Checker {
+	var checkers []health.Checker
+
+	if gcp.Secrets != nil {
+		secretProvider := auth.NewSecretProvider(cfg, gcp.Secrets, gcp.Logger)
+		checkers = append(checkers, health.NewCheck("secret_manager", func(ctx context.Context) error {
+			_, err := secretProvider.GetJWTSecret(ctx)
+			return err
+		}))
+	}
+
+	if gcp.Storage != nil {
+		checkers = append(checkers, health.NewCheck("cloud_storage", func(ctx context.Context) error {
+			return gcp.Storage.UploadFile(ctx, "preflight/check", strings.NewReader(time.Now().UTC().Format(time.RFC3339)))
+		}))
+	}
+
+	if searchCfg := loadSearchConfig(); searchCfg.Enabled() {
+		checkers = append(checkers, health.NewCheck("elasticsearch", search.NewElasticsearchIndexer(searchCfg).Ping))
+	}
+
+	switch mailCfg.Provider {
+	case mail.ProviderSMTP:
+		checkers = append(checkers, dialCheck("smtp", net.JoinHostPort(mailCfg.SMTPHost, strconv.Itoa(mailCfg.SMTPPort))))
+	case mail.ProviderSendGrid:
+		checkers = append(checkers, dialCheck("sendgrid", sendGridAddr))
+	}
+
+	return checkers
+}
+
+// dialCheck verifica se é possível abrir uma conexão TCP com addr
+func dialCheck(name, addr string) health.Checker {
+	return health.NewCheck(name, func(ctx context.Context) error {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	})
+}
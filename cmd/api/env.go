@@ -0,0 +1,609 @@
+package main
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"callable-api/internal/avatar"
+	"callable-api/internal/changes"
+	"callable-api/internal/chaos"
+	"callable-api/internal/datakeys"
+	"callable-api/internal/handlers"
+	"callable-api/internal/ids"
+	"callable-api/internal/jobs"
+	"callable-api/internal/jwtkey"
+	"callable-api/internal/metrics"
+	"callable-api/internal/middleware"
+	"callable-api/internal/mode"
+	"callable-api/internal/models"
+	"callable-api/internal/notifications"
+	"callable-api/internal/pagination"
+	"callable-api/internal/quota"
+	"callable-api/internal/reporting"
+	"callable-api/internal/repository"
+	"callable-api/internal/rotation"
+	"callable-api/internal/routes"
+	"callable-api/internal/scim"
+	"callable-api/internal/search"
+	"callable-api/internal/service"
+	"callable-api/internal/stats"
+	"callable-api/internal/streams"
+	"callable-api/internal/synthetic"
+	"callable-api/internal/webapp"
+	"callable-api/pkg/cloud"
+	"callable-api/pkg/config"
+	"callable-api/pkg/httpclient"
+	"callable-api/pkg/logger"
+	"callable-api/pkg/mail"
+)
+
+// getEnv retorna o valor da variável de ambiente ou o valor padrão
+func getEnv(key, defaultValue string) string {
+	if value, exists := os.LookupEnv(key); exists && value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvInt retorna a variável de ambiente como inteiro ou o valor padrão
+func getEnvInt(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvBool retorna a variável de ambiente como booleano ou o valor padrão
+func getEnvBool(key string, defaultValue bool) bool {
+	if value, err := strconv.ParseBool(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvDuration retorna a variável de ambiente como duração (ex.: "1h", "30m") ou o valor padrão
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// loadMailConfig carrega a configuração do subsistema de email
+func loadMailConfig() mail.Config {
+	defaults := mail.DefaultConfig()
+	return mail.Config{
+		Provider:       getEnv("MAIL_PROVIDER", defaults.Provider),
+		From:           getEnv("MAIL_FROM", defaults.From),
+		SMTPHost:       getEnv("SMTP_HOST", defaults.SMTPHost),
+		SMTPPort:       getEnvInt("SMTP_PORT", defaults.SMTPPort),
+		SMTPUsername:   getEnv("SMTP_USERNAME", defaults.SMTPUsername),
+		SMTPPassword:   getEnv("SMTP_PASSWORD", defaults.SMTPPassword),
+		SendGridAPIKey: getEnv("SENDGRID_API_KEY", defaults.SendGridAPIKey),
+		Async:          getEnvBool("MAIL_ASYNC", defaults.Async),
+		TemplatesDir:   getEnv("MAIL_TEMPLATES_DIR", defaults.TemplatesDir),
+	}
+}
+
+// loadSearchConfig carrega a configuração do Elasticsearch/OpenSearch (opcional)
+func loadSearchConfig() search.Config {
+	return search.Config{
+		URL:      getEnv("ELASTICSEARCH_URL", ""),
+		Index:    getEnv("ELASTICSEARCH_INDEX", "items"),
+		Username: getEnv("ELASTICSEARCH_USERNAME", ""),
+		Password: getEnv("ELASTICSEARCH_PASSWORD", ""),
+		APIKey:   getEnv("ELASTICSEARCH_API_KEY", ""),
+	}
+}
+
+// loadPaginationConfig carrega os limites de tamanho de página das listagens
+func loadPaginationConfig() pagination.Config {
+	defaults := pagination.DefaultConfig()
+	return pagination.Config{
+		DefaultPageSize: getEnvInt("PAGINATION_DEFAULT_LIMIT", defaults.DefaultPageSize),
+		MaxPageSize:     getEnvInt("PAGINATION_MAX_LIMIT", defaults.MaxPageSize),
+	}
+}
+
+// loadAvatarConfig carrega o tamanho dos avatares e o limite de upload
+func loadAvatarConfig() avatar.Config {
+	defaults := avatar.DefaultConfig()
+	return avatar.Config{
+		Size:           getEnvInt("AVATAR_SIZE", defaults.Size),
+		MaxUploadBytes: int64(getEnvInt("AVATAR_MAX_UPLOAD_BYTES", int(defaults.MaxUploadBytes))),
+	}
+}
+
+// loadPasswordResetConfig carrega o link de redefinição de senha enviado por email
+func loadPasswordResetConfig() service.PasswordResetConfig {
+	defaults := service.DefaultPasswordResetConfig()
+	return service.PasswordResetConfig{
+		URL: getEnv("PASSWORD_RESET_URL", defaults.URL),
+		TTL: getEnvDuration("PASSWORD_RESET_TTL", defaults.TTL),
+	}
+}
+
+// loadInvitationTTL carrega a validade padrão dos convites de organização e
+// de cadastro (INVITATION_TTL, padrão 72h)
+func loadInvitationTTL() time.Duration {
+	return getEnvDuration("INVITATION_TTL", service.DefaultInvitationTTL)
+}
+
+// loadRegistrationMode carrega o modo de cadastro (REGISTRATION_MODE=open ou
+// invite). Valores inválidos mantêm o cadastro aberto
+func loadRegistrationMode() service.RegistrationMode {
+	mode, err := service.ParseRegistrationMode(os.Getenv("REGISTRATION_MODE"))
+	if err != nil {
+		logger.Error("Configuração de modo de cadastro inválida", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+	return mode
+}
+
+// loadConsentConfig carrega as versões dos termos de uso (TERMS_VERSION) e
+// da política de privacidade (PRIVACY_POLICY_VERSION). A versão obrigatória
+// (TERMS_REQUIRED_VERSION, PRIVACY_POLICY_REQUIRED_VERSION) é, por padrão, a
+// vigente; documentos sem versão não exigem aceite
+func loadConsentConfig() service.ConsentConfig {
+	var cfg service.ConsentConfig
+	for _, doc := range []struct{ name, prefix string }{
+		{models.ConsentTerms, "TERMS"},
+		{models.ConsentPrivacy, "PRIVACY_POLICY"},
+	} {
+		version := os.Getenv(doc.prefix + "_VERSION")
+		if version == "" {
+			continue
+		}
+		cfg.Documents = append(cfg.Documents, service.ConsentDocument{
+			Name:     doc.name,
+			Version:  version,
+			Required: getEnv(doc.prefix+"_REQUIRED_VERSION", version),
+		})
+	}
+	return cfg
+}
+
+// loadDeviceBindingConfig carrega o vínculo dos tokens de atualização aos
+// dispositivos (REFRESH_DEVICE_BINDING=off, lenient ou strict). Valores
+// inválidos desativam o vínculo
+func loadDeviceBindingConfig() service.DeviceBindingConfig {
+	mode, err := service.ParseDeviceBindingMode(os.Getenv("REFRESH_DEVICE_BINDING"))
+	if err != nil {
+		logger.Error("Configuração de vínculo de dispositivo inválida", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+	return service.DeviceBindingConfig{Mode: mode}
+}
+
+// loadSessionConfig carrega as durações das sessões curtas e longas que o login
+// pode solicitar (a sessão padrão segue a validade global dos tokens JWT)
+func loadSessionConfig() service.SessionConfig {
+	defaults := service.DefaultSessionConfig()
+	return service.SessionConfig{
+		Short: service.SessionLifetime{
+			AccessMinutes: getEnvInt("SESSION_SHORT_ACCESS_MINUTES", defaults.Short.AccessMinutes),
+			RefreshDays:   getEnvInt("SESSION_SHORT_REFRESH_DAYS", defaults.Short.RefreshDays),
+		},
+		Long: service.SessionLifetime{
+			AccessMinutes: getEnvInt("SESSION_LONG_ACCESS_MINUTES", defaults.Long.AccessMinutes),
+			RefreshDays:   getEnvInt("SESSION_LONG_REFRESH_DAYS", defaults.Long.RefreshDays),
+		},
+	}
+}
+
+// loadScopedTokenConfig carrega a validade dos tokens restritos emitidos por
+// POST /api/v1/auth/token/narrow
+func loadScopedTokenConfig() service.ScopedTokenConfig {
+	defaults := service.DefaultScopedTokenConfig()
+	return service.ScopedTokenConfig{
+		DefaultTTL: getEnvDuration("SCOPED_TOKEN_TTL", defaults.DefaultTTL),
+		MaxTTL:     getEnvDuration("SCOPED_TOKEN_MAX_TTL", defaults.MaxTTL),
+	}
+}
+
+// loadItemCacheConfig carrega o cache de leitura dos itens por ID
+// (ITEM_CACHE_TTL=0 desativa o cache)
+func loadItemCacheConfig() service.ItemCacheConfig {
+	defaults := service.DefaultItemCacheConfig()
+	return service.ItemCacheConfig{
+		TTL:         getEnvDuration("ITEM_CACHE_TTL", defaults.TTL),
+		NegativeTTL: getEnvDuration("ITEM_CACHE_NEGATIVE_TTL", defaults.NegativeTTL),
+		MaxEntries:  getEnvInt("ITEM_CACHE_MAX_ENTRIES", defaults.MaxEntries),
+	}
+}
+
+// loadDuplicateRule carrega a regra de itens duplicados (ITEM_DUPLICATE_RULE=none,
+// name_owner ou value). Valores inválidos desativam a verificação
+func loadDuplicateRule() service.DuplicateRule {
+	rule, err := service.ParseDuplicateRule(os.Getenv("ITEM_DUPLICATE_RULE"))
+	if err != nil {
+		logger.Error("Regra de itens duplicados inválida", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+	return rule
+}
+
+// loadBulkLimits carrega os limites de POST /api/v1/data/bulk (BULK_MAX_BYTES
+// e BULK_MAX_ITEMS)
+func loadBulkLimits() handlers.ArrayLimits {
+	defaults := handlers.DefaultArrayLimits()
+	return handlers.ArrayLimits{
+		MaxBytes: int64(getEnvInt("BULK_MAX_BYTES", int(defaults.MaxBytes))),
+		MaxItems: getEnvInt("BULK_MAX_ITEMS", defaults.MaxItems),
+	}
+}
+
+// loadUserImportLimits carrega os limites do CSV de POST
+// /api/v1/admin/users/import (USER_IMPORT_MAX_BYTES e USER_IMPORT_MAX_ROWS)
+func loadUserImportLimits() handlers.ArrayLimits {
+	defaults := handlers.DefaultArrayLimits()
+	return handlers.ArrayLimits{
+		MaxBytes: int64(getEnvInt("USER_IMPORT_MAX_BYTES", int(defaults.MaxBytes))),
+		MaxItems: getEnvInt("USER_IMPORT_MAX_ROWS", defaults.MaxItems),
+	}
+}
+
+// loadItemCreateMode carrega o modo de criação de itens usado quando o cliente
+// não envia o header Prefer (ITEM_CREATE_MODE=sync ou async). Valores
+// inválidos mantêm a criação síncrona
+func loadItemCreateMode() handlers.CreateMode {
+	mode, err := handlers.ParseCreateMode(os.Getenv("ITEM_CREATE_MODE"))
+	if err != nil {
+		logger.Error("Modo de criação de itens inválido", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+	return mode
+}
+
+// loadJobsConfig carrega a capacidade de execução dos jobs em segundo plano e
+// os limites por usuário (0 desativa o limite)
+func loadJobsConfig() jobs.Config {
+	defaults := jobs.DefaultConfig()
+	return jobs.Config{
+		Workers:           getEnvInt("JOB_WORKERS", defaults.Workers),
+		QueueSize:         getEnvInt("JOB_QUEUE_SIZE", defaults.QueueSize),
+		Timeout:           getEnvDuration("JOB_TIMEOUT", defaults.Timeout),
+		MaxRunningPerUser: getEnvInt("JOB_MAX_RUNNING_PER_USER", defaults.MaxRunningPerUser),
+		MaxQueuedPerUser:  getEnvInt("JOB_MAX_QUEUED_PER_USER", defaults.MaxQueuedPerUser),
+		Panics: jobs.PanicPolicy{
+			Threshold: getEnvInt("JOB_PANIC_THRESHOLD", defaults.Panics.Threshold),
+			Window:    getEnvDuration("JOB_PANIC_WINDOW", defaults.Panics.Window),
+			Cooldown:  getEnvDuration("JOB_PANIC_COOLDOWN", defaults.Panics.Cooldown),
+		},
+		MaxDelay:            getEnvDuration("JOB_MAX_DELAY", defaults.MaxDelay),
+		ArtifactThreshold:   getEnvInt("JOB_ARTIFACT_THRESHOLD", defaults.ArtifactThreshold),
+		ArtifactCompression: getEnvInt("JOB_ARTIFACT_GZIP_MIN_BYTES", defaults.ArtifactCompression),
+		IDs:                 loadIDConfig().Generator(ids.EntityJobs),
+		Logs: jobs.LogLimits{
+			MaxEntries:      getEnvInt("JOB_LOG_MAX_ENTRIES", defaults.Logs.MaxEntries),
+			MaxMessageBytes: getEnvInt("JOB_LOG_MAX_BYTES", defaults.Logs.MaxMessageBytes),
+		},
+	}
+}
+
+// loadExportCompression carrega o tamanho a partir do qual os downloads de
+// artefatos de texto são comprimidos com gzip na hora (EXPORT_GZIP_MIN_BYTES;
+// 0 desativa)
+func loadExportCompression() int {
+	return getEnvInt("EXPORT_GZIP_MIN_BYTES", handlers.DefaultCompressionMinBytes)
+}
+
+// loadIDConfig carrega o formato dos IDs: ID_FORMAT vale para todas as
+// entidades (uuid, uuidv7, ulid ou sequence) e ID_FORMAT_<ENTIDADE> define
+// exceções (ex.: ID_FORMAT_ITEMS=ulid). Sem ID_FORMAT, os itens usam a
+// sequência numérica e as demais entidades, UUID v4
+func loadIDConfig() ids.Config {
+	cfg := ids.DefaultConfig()
+	if format := os.Getenv("ID_FORMAT"); format != "" {
+		cfg = ids.Config{Default: format, Entities: make(map[string]string)}
+	}
+	for _, entity := range ids.Entities() {
+		if format := os.Getenv("ID_FORMAT_" + strings.ToUpper(entity)); format != "" {
+			cfg.Entities[entity] = format
+		}
+	}
+	return cfg
+}
+
+// loadSCIMConfig carrega o acesso dos provedores de identidade à API SCIM
+// (sem SCIM_TOKEN a API SCIM não é exposta)
+func loadSCIMConfig() scim.Config {
+	return scim.Config{Token: getEnv("SCIM_TOKEN", "")}
+}
+
+// loadMetricsToken carrega o token do coletor do Prometheus (sem
+// METRICS_TOKEN o endpoint /metrics não é exposto)
+func loadMetricsToken() string {
+	return getEnv("METRICS_TOKEN", "")
+}
+
+// loadQuotaConfig carrega a cota de requisições por cliente (QUOTA_LIMIT=0 desativa)
+func loadQuotaConfig() quota.Config {
+	defaults := quota.DefaultConfig()
+	return quota.Config{
+		Limit:     getEnvInt("QUOTA_LIMIT", defaults.Limit),
+		Window:    getEnvDuration("QUOTA_WINDOW", defaults.Window),
+		CostBytes: int64(getEnvInt("QUOTA_COST_BYTES", 0)),
+	}
+}
+
+// loadSyntheticConfig carrega o volume dos dados sintéticos gerados na
+// inicialização (SYNTHETIC_USERS, SYNTHETIC_ITEMS e SYNTHETIC_SEED), para os
+// testes de carga
+func loadSyntheticConfig() synthetic.Config {
+	return synthetic.Config{
+		Users: getEnvInt("SYNTHETIC_USERS", 0),
+		Items: getEnvInt("SYNTHETIC_ITEMS", 0),
+		Seed:  int64(getEnvInt("SYNTHETIC_SEED", 1)),
+	}
+}
+
+// loadFixtures carrega os dados iniciais dos repositórios: os do arquivo
+// SEED_FILE (JSON ou YAML) ou, sem arquivo, os dados de exemplo embutidos,
+// cujos itens só existem no modo demo. SEED_ENABLED=false desativa os dados
+// iniciais (retorna nil)
+func loadFixtures() (*repository.Fixtures, error) {
+	if !getEnvBool("SEED_ENABLED", true) {
+		return nil, nil
+	}
+	if path := getEnv("SEED_FILE", ""); path != "" {
+		return repository.LoadFixtures(path)
+	}
+
+	fixtures := repository.DefaultFixtures()
+	if !mode.IsDemo() {
+		fixtures.Items = nil
+	}
+	return fixtures, nil
+}
+
+// loadBootstrapConfig carrega as credenciais do primeiro administrador
+// (BOOTSTRAP_ADMIN_EMAIL, BOOTSTRAP_ADMIN_PASSWORD e BOOTSTRAP_ADMIN_NAME)
+func loadBootstrapConfig() service.BootstrapConfig {
+	return service.BootstrapConfig{
+		AdminEmail:    getEnv("BOOTSTRAP_ADMIN_EMAIL", ""),
+		AdminPassword: getEnv("BOOTSTRAP_ADMIN_PASSWORD", ""),
+		AdminName:     getEnv("BOOTSTRAP_ADMIN_NAME", ""),
+	}
+}
+
+// loadAllocationBudgetConfig carrega o orçamento de memória por requisição
+// (ALLOC_BUDGET_BYTES; 0 desativa) e a fração das requisições medidas
+// (ALLOC_BUDGET_SAMPLE=N mede uma a cada N)
+func loadAllocationBudgetConfig() stats.AllocationBudgetConfig {
+	defaults := stats.DefaultAllocationBudgetConfig()
+	return stats.AllocationBudgetConfig{
+		Bytes:       uint64(max(getEnvInt("ALLOC_BUDGET_BYTES", int(defaults.Bytes)), 0)),
+		SampleEvery: getEnvInt("ALLOC_BUDGET_SAMPLE", defaults.SampleEvery),
+	}
+}
+
+// loadServiceProfiler carrega a medição das operações dos serviços, exibida no
+// painel de operações (SERVICE_PROFILING=true). SERVICE_PROFILING_ALLOC_SAMPLE=N
+// mede também as alocações de memória de uma a cada N chamadas (0 desativa).
+// Retorna nil quando a medição está desativada
+func loadServiceProfiler(registry *metrics.Registry) *metrics.Profiler {
+	if !getEnvBool("SERVICE_PROFILING", false) {
+		return nil
+	}
+	return metrics.NewProfiler(registry, getEnvInt("SERVICE_PROFILING_ALLOC_SAMPLE", 0))
+}
+
+// loadStreamsConfig carrega a configuração das conexões de streaming:
+// intervalo de reconexão sugerido aos clientes, inclusive no encerramento
+// (STREAM_RECONNECT_AFTER), e intervalo do heartbeat (STREAM_HEARTBEAT)
+func loadStreamsConfig() streams.Config {
+	cfg := streams.DefaultConfig()
+	cfg.ReconnectAfter = getEnvDuration("STREAM_RECONNECT_AFTER", cfg.ReconnectAfter)
+	cfg.Heartbeat = getEnvDuration("STREAM_HEARTBEAT", cfg.Heartbeat)
+	return cfg
+}
+
+// loadWebhookDeliveries cria o store das entregas de webhook, mantidas para
+// reentrega pelo tempo definido em WEBHOOK_RETENTION
+func loadWebhookDeliveries() *notifications.MemoryDeliveryStore {
+	return notifications.NewMemoryDeliveryStore(getEnvDuration("WEBHOOK_RETENTION", notifications.DefaultDeliveryRetention))
+}
+
+// loadSecretCacheConfig carrega a retenção dos segredos do Secret Manager em
+// memória (SECRET_CACHE_TTL, SECRET_CACHE_MAX_ENTRIES e SECRET_CACHE_ENCRYPT)
+func loadSecretCacheConfig() cloud.SecretCacheConfig {
+	defaults := cloud.DefaultSecretCacheConfig()
+	return cloud.SecretCacheConfig{
+		TTL:        getEnvDuration("SECRET_CACHE_TTL", defaults.TTL),
+		MaxEntries: getEnvInt("SECRET_CACHE_MAX_ENTRIES", defaults.MaxEntries),
+		Encrypt:    getEnvBool("SECRET_CACHE_ENCRYPT", defaults.Encrypt),
+	}
+}
+
+// loadSecretRotationConfig carrega o acesso do Pub/Sub ao endpoint de rotação
+// de segredos (sem SECRET_ROTATION_TOKEN o endpoint não é exposto)
+func loadSecretRotationConfig() rotation.Config {
+	return rotation.Config{
+		Token:         getEnv("SECRET_ROTATION_TOKEN", ""),
+		JWTSecretName: getEnv("JWT_SECRET_NAME", rotation.DefaultJWTSecretName),
+	}
+}
+
+// loadJWTRotationGrace carrega por quanto tempo os tokens assinados com o
+// segredo anterior do JWT são aceitos após a rotação (JWT_ROTATION_GRACE)
+func loadJWTRotationGrace() time.Duration {
+	return getEnvDuration("JWT_ROTATION_GRACE", jwtkey.DefaultGracePeriod)
+}
+
+// loadDataKeys carrega o keyring da cifragem em envelope dos dados dos
+// usuários, com as chaves de dados embrulhadas pela chave mestra
+// DATA_ENCRYPTION_KEY (32 bytes em base64). Sem a chave, retorna nil
+func loadDataKeys() (*datakeys.Keyring, error) {
+	encoded := getEnv("DATA_ENCRYPTION_KEY", "")
+	if encoded == "" {
+		return nil, nil
+	}
+	master, err := datakeys.ParseMasterKey(encoded)
+	if err != nil {
+		return nil, err
+	}
+	return datakeys.NewKeyring(master, datakeys.NewMemoryStore()), nil
+}
+
+// loadChangeFeedConfig carrega o número de alterações de itens mantidas no
+// log de alterações (CHANGE_FEED_CAPACITY)
+func loadChangeFeedConfig() changes.Config {
+	return changes.Config{Capacity: getEnvInt("CHANGE_FEED_CAPACITY", changes.DefaultCapacity)}
+}
+
+// loadChaosConfig carrega as regras de injeção de falhas (CHAOS_ENABLED e CHAOS_RULES).
+// Regras inválidas desativam a injeção
+func loadChaosConfig() chaos.Config {
+	if !getEnvBool("CHAOS_ENABLED", false) {
+		return chaos.Config{}
+	}
+
+	rules, err := chaos.ParseRules(os.Getenv("CHAOS_RULES"))
+	if err != nil {
+		logger.Error("Regras de injeção de falhas inválidas", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return chaos.Config{}
+	}
+	return chaos.Config{Enabled: true, Rules: rules}
+}
+
+// loadStrictQuotaConfig carrega a cota das rotas sensíveis (login, registro),
+// mais restrita que a cota padrão
+func loadStrictQuotaConfig() quota.Config {
+	return quota.Config{
+		Limit:  getEnvInt("QUOTA_STRICT_LIMIT", 30),
+		Window: getEnvDuration("QUOTA_STRICT_WINDOW", time.Minute),
+	}
+}
+
+// loadAnonymousMode carrega o acesso sem token às rotas de leitura
+// (ANONYMOUS_READ=full, catalog ou off). Valores inválidos mantêm o acesso
+// completo
+func loadAnonymousMode() routes.AnonymousMode {
+	mode, err := routes.ParseAnonymousMode(os.Getenv("ANONYMOUS_READ"))
+	if err != nil {
+		logger.Error("Modo de acesso anônimo inválido", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+	return mode
+}
+
+// loadAnonymousQuotaConfig carrega a cota dos clientes anônimos do catálogo
+// público (ANONYMOUS_READ=catalog), mais restrita que a cota padrão
+func loadAnonymousQuotaConfig() quota.Config {
+	return quota.Config{
+		Limit:  getEnvInt("QUOTA_ANONYMOUS_LIMIT", 60),
+		Window: getEnvDuration("QUOTA_ANONYMOUS_WINDOW", time.Minute),
+	}
+}
+
+// loadReportingConfig carrega a integração com o Cloud Error Reporting. Sem
+// ERROR_REPORTING_API_KEY os panics ficam apenas nos logs
+func loadReportingConfig(cfg *config.Config) reporting.Config {
+	return reporting.Config{
+		ProjectID: getEnv("ERROR_REPORTING_PROJECT_ID", cfg.GCPProjectID),
+		APIKey:    getEnv("ERROR_REPORTING_API_KEY", ""),
+		Service:   getEnv("ERROR_REPORTING_SERVICE", "callable-api"),
+	}
+}
+
+// loadServerLimits carrega os limites de conexão do servidor HTTP
+// (SERVER_KEEP_ALIVES=false fecha a conexão após cada resposta)
+func loadServerLimits() serverLimits {
+	defaults := defaultServerLimits()
+	return serverLimits{
+		ReadHeaderTimeout: getEnvDuration("SERVER_READ_HEADER_TIMEOUT", defaults.ReadHeaderTimeout),
+		IdleTimeout:       getEnvDuration("SERVER_IDLE_TIMEOUT", defaults.IdleTimeout),
+		MaxHeaderBytes:    getEnvInt("SERVER_MAX_HEADER_BYTES", defaults.MaxHeaderBytes),
+		KeepAlives:        getEnvBool("SERVER_KEEP_ALIVES", defaults.KeepAlives),
+	}
+}
+
+// loadProxyConfig carrega os proxies confiáveis (TRUSTED_PROXIES, IPs ou
+// CIDRs separados por vírgula) e os headers com o IP real do cliente
+// (CLIENT_IP_HEADERS). Sem TRUSTED_PROXIES, vale o IP da conexão
+func loadProxyConfig() middleware.ProxyConfig {
+	cfg := middleware.DefaultProxyConfig()
+	cfg.TrustedProxies = splitList(os.Getenv("TRUSTED_PROXIES"))
+	if headers := splitList(os.Getenv("CLIENT_IP_HEADERS")); len(headers) > 0 {
+		cfg.Headers = headers
+	}
+	return cfg
+}
+
+// loadEgressConfig carrega a política das chamadas externas: o proxy
+// (EGRESS_PROXY_URL; sem ele, valem HTTP_PROXY/HTTPS_PROXY/NO_PROXY) e os
+// destinos permitidos (EGRESS_ALLOWED_HOSTS, separados por vírgula, aceitando
+// "*.dominio"). Sem lista, qualquer destino é permitido
+func loadEgressConfig() httpclient.Egress {
+	return httpclient.Egress{
+		ProxyURL:     os.Getenv("EGRESS_PROXY_URL"),
+		AllowedHosts: splitList(os.Getenv("EGRESS_ALLOWED_HOSTS")),
+	}
+}
+
+// loadCORSPolicies carrega as políticas CORS dos grupos de rotas. As origens
+// da política padrão vêm de ALLOWED_ORIGINS (padrões separados por vírgula,
+// ex.: "localhost:*,https://*.example.com"; "*" aceita qualquer origem) e as
+// de cada grupo de CORS_PUBLIC_ORIGINS, CORS_AUTH_ORIGINS e
+// CORS_ADMIN_ORIGINS, que assumem ALLOWED_ORIGINS quando vazias.
+// CORS_MAX_AGE define o cache dos preflights
+func loadCORSPolicies() map[string]middleware.CORSConfig {
+	defaults := middleware.DefaultCORSConfig()
+	defaults.MaxAge = getEnvDuration("CORS_MAX_AGE", defaults.MaxAge)
+	if origins := splitList(os.Getenv("ALLOWED_ORIGINS")); len(origins) > 0 {
+		defaults.AllowedOrigins = origins
+	}
+
+	policies := map[string]middleware.CORSConfig{routes.CORSDefault: defaults}
+	for policy, key := range map[string]string{
+		routes.CORSPublic: "CORS_PUBLIC_ORIGINS",
+		routes.CORSAuth:   "CORS_AUTH_ORIGINS",
+		routes.CORSAdmin:  "CORS_ADMIN_ORIGINS",
+	} {
+		cfg := defaults
+		if origins := splitList(os.Getenv(key)); len(origins) > 0 {
+			cfg.AllowedOrigins = origins
+		}
+		policies[policy] = cfg
+	}
+	return policies
+}
+
+// splitList separa uma lista separada por vírgulas, ignorando itens vazios
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// loadWebAppConfig carrega a hospedagem da interface web em /app/*
+// (WEBAPP_ENABLED), com os arquivos de WEBAPP_DIR ou, sem diretório, a
+// interface de demonstração embutida
+func loadWebAppConfig() webapp.Config {
+	defaults := webapp.DefaultConfig()
+	return webapp.Config{
+		Enabled:     getEnvBool("WEBAPP_ENABLED", defaults.Enabled),
+		Dir:         getEnv("WEBAPP_DIR", defaults.Dir),
+		AssetMaxAge: getEnvDuration("WEBAPP_ASSET_MAX_AGE", defaults.AssetMaxAge),
+	}
+}
+
+// loadFallbackPorts carrega as portas alternativas usadas quando a porta
+// configurada está em uso (PORT_FALLBACKS, separadas por vírgula)
+func loadFallbackPorts() []string {
+	return splitList(os.Getenv("PORT_FALLBACKS"))
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"callable-api/client"
+	"callable-api/internal/testsupport"
+	"callable-api/pkg/cloud"
+	"callable-api/pkg/config"
+)
+
+// newIntegrationServer sobe a API completa, com a execução de jobs e o
+// administrador de testsupport
+func newIntegrationServer(t *testing.T) *testsupport.Server {
+	gin.SetMode(gin.TestMode)
+	testsupport.BootstrapAdmin(t)
+
+	jobManager := SetupJobs()
+	t.Cleanup(func() { jobManager.Close(context.Background()) })
+
+	router, seed := SetupRouter(config.Load(), cloud.Services{}, nil, jobManager, nil, nil)
+	seed()
+	return testsupport.NewServer(t, router)
+}
+
+func TestIntegration_Items(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	server := newIntegrationServer(t)
+	seeded := server.Seed(t, testsupport.DefaultFixtures())
+	ana := seeded.Clients["ana@example.test"]
+	bruno := seeded.Clients["bruno@example.test"]
+	item := seeded.Items[0]
+
+	// O item gravado é lido de volta, inclusive depois de alterado (sem
+	// leituras antigas do cache)
+	found, err := ana.GetItem(ctx, item.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Caderno da Ana", found.Name)
+
+	input := client.InputData{Name: "Caderno novo", Value: "ANA-1", Email: "ana@example.test"}
+	_, err = ana.UpdateItem(ctx, item.ID, &input)
+	require.NoError(t, err)
+	found, err = ana.GetItem(ctx, item.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Caderno novo", found.Name)
+
+	// Os itens privados não são visíveis a outros usuários até o compartilhamento
+	_, err = bruno.GetItem(ctx, item.ID)
+	assert.Error(t, err)
+
+	_, err = ana.ShareItem(ctx, item.ID, &client.ShareItemInput{UserID: seeded.Users["bruno@example.test"].ID, Level: "read"})
+	require.NoError(t, err)
+	found, err = bruno.GetItem(ctx, item.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Caderno novo", found.Name)
+
+	// A criação assíncrona termina com o item gravado
+	job, err := ana.CreateItemAsync(ctx, &client.InputData{Name: "Agenda", Value: "ANA-2", Email: "ana@example.test"})
+	require.NoError(t, err)
+	job, err = ana.WaitJob(ctx, job.ID, 10*time.Millisecond)
+	require.NoError(t, err)
+	created, err := client.ItemResult(job)
+	require.NoError(t, err)
+
+	found, err = ana.GetItem(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Agenda", found.Name)
+}
+
+func TestIntegration_Admin(t *testing.T) {
+	ctx := context.Background()
+
+	server := newIntegrationServer(t)
+	seeded := server.Seed(t, testsupport.DefaultFixtures())
+	admin := server.Admin(t)
+
+	// Contas desativadas deixam de entrar
+	_, err := admin.DisableUser(ctx, seeded.Users["ana@example.test"].ID, "teste")
+	require.NoError(t, err)
+	_, err = server.Client().Login(ctx, &client.LoginInput{Email: "ana@example.test", Password: "senha-ana"})
+	assert.Equal(t, 403, client.StatusCode(err))
+
+	// Usuários comuns não acessam as rotas de administração
+	_, err = seeded.Clients["bruno@example.test"].AdminOverview(ctx)
+	assert.Equal(t, 403, client.StatusCode(err))
+}
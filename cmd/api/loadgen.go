@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"callable-api/internal/loadgen"
+	"callable-api/pkg/cloud"
+	"callable-api/pkg/config"
+	"callable-api/pkg/logger"
+)
+
+// loadgenOptions define os dados sintéticos e o usuário de uma execução do
+// gerador de carga
+type loadgenOptions struct {
+	users    int
+	items    int
+	email    string
+	password string
+}
+
+// runLoadgen executa o gerador de carga (ver o pacote loadgen). Sem --target,
+// sobe a API no próprio processo com os dados sintéticos (--users, --items) e
+// sem cota de requisições
+func runLoadgen(out io.Writer, args []string) error {
+	fs := flag.NewFlagSet("loadgen", flag.ContinueOnError)
+	cfg := loadgen.Config{}
+	opts := loadgenOptions{}
+	fs.StringVar(&cfg.Target, "target", "", "URL de uma API em execução (vazio sobe a API no próprio processo)")
+	fs.IntVar(&opts.users, "users", 100, "usuários sintéticos gerados (sem --target)")
+	fs.IntVar(&opts.items, "items", 10000, "itens sintéticos gerados (sem --target)")
+	fs.DurationVar(&cfg.Duration, "duration", 30*time.Second, "duração da carga")
+	fs.IntVar(&cfg.Concurrency, "concurrency", 8, "requisições simultâneas")
+	fs.IntVar(&cfg.Limit, "limit", 50, "itens por página nas listagens")
+	fs.StringVar(&opts.email, "email", "loadgen@example.test", "email do usuário autenticado (sem --target, o administrador criado na inicialização)")
+	fs.StringVar(&opts.password, "password", "loadgen-admin", "senha do usuário autenticado")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	if cfg.Target == "" {
+		target, stop, err := startLoadgenServer(opts)
+		if err != nil {
+			return err
+		}
+		defer stop()
+		cfg.Target = target
+	}
+
+	ctx := context.Background()
+	api, err := loadgen.Login(ctx, cfg, opts.email, opts.password)
+	if err != nil {
+		return err
+	}
+	return loadgen.Run(ctx, out, api, cfg)
+}
+
+// startLoadgenServer sobe a API no próprio processo, com os dados sintéticos,
+// o administrador de --email e --password e sem cota de requisições (a cota
+// limitaria a carga, não o desempenho)
+func startLoadgenServer(opts loadgenOptions) (string, func(), error) {
+	for key, value := range map[string]int{
+		"SYNTHETIC_USERS":    opts.users,
+		"SYNTHETIC_ITEMS":    opts.items,
+		"QUOTA_LIMIT":        0,
+		"QUOTA_STRICT_LIMIT": 0,
+	} {
+		os.Setenv(key, strconv.Itoa(value))
+	}
+	// O administrador autenticado é o primeiro administrador da API
+	os.Setenv("BOOTSTRAP_ADMIN_EMAIL", opts.email)
+	os.Setenv("BOOTSTRAP_ADMIN_PASSWORD", opts.password)
+
+	gin.SetMode(gin.ReleaseMode)
+	logger.SetLevel("warn")
+	router, seed := SetupRouter(config.Load(), cloud.Services{}, nil, nil, nil, nil)
+	seed()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", nil, err
+	}
+	server := &http.Server{Handler: router, ReadHeaderTimeout: 5 * time.Second}
+	go server.Serve(listener)
+
+	stop := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(ctx)
+	}
+	return "http://" + listener.Addr().String(), stop, nil
+}
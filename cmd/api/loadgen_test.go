@@ -0,0 +1,27 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"callable-api/internal/loadgen"
+)
+
+func TestRunLoadgen(t *testing.T) {
+	for _, key := range []string{"SYNTHETIC_USERS", "SYNTHETIC_ITEMS", "QUOTA_LIMIT", "QUOTA_STRICT_LIMIT"} {
+		t.Setenv(key, "")
+	}
+
+	var out bytes.Buffer
+	err := runLoadgen(&out, []string{"-users", "5", "-items", "300", "-duration", "300ms", "-concurrency", "3", "-limit", "20"})
+	require.NoError(t, err, out.String())
+
+	report := out.String()
+	assert.Contains(t, report, "OPERAÇÃO")
+	for _, scenario := range loadgen.Scenarios {
+		assert.Regexp(t, "(?m)^"+scenario+` +[1-9]\d* +0 `, report)
+	}
+}
@@ -2,9 +2,15 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	stderrors "errors"
+	"flag"
+	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -12,19 +18,42 @@ import (
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 
-	_ "callable-api/docs" // Para geração de documentação Swagger
+	"callable-api/docs" // Documentação Swagger gerada pelo swag
+	"callable-api/internal/admin"
+	"callable-api/internal/avatar"
+	"callable-api/internal/changes"
+	"callable-api/internal/correlation"
+	"callable-api/internal/events"
 	"callable-api/internal/handlers"
+	"callable-api/internal/health"
+	"callable-api/internal/ids"
+	"callable-api/internal/jobs"
+	"callable-api/internal/jwtkey"
+	"callable-api/internal/metrics"
 	"callable-api/internal/middleware"
+	"callable-api/internal/mode"
+	"callable-api/internal/models"
+	"callable-api/internal/notifications"
+	"callable-api/internal/quota"
+	"callable-api/internal/recorder"
+	"callable-api/internal/reporting"
 	"callable-api/internal/repository"
+	"callable-api/internal/rotation"
+	"callable-api/internal/routes"
+	"callable-api/internal/scim"
+	"callable-api/internal/search"
 	"callable-api/internal/service"
+	"callable-api/internal/stats"
+	"callable-api/internal/streams"
+	"callable-api/internal/synthetic"
+	"callable-api/internal/webapp"
+	"callable-api/pkg/auth"
+	"callable-api/pkg/cloud"
 	"callable-api/pkg/config"
 	"callable-api/pkg/errors"
+	"callable-api/pkg/httpclient"
 	"callable-api/pkg/logger"
-
-	// Importações novas para GCP
-	gcplogger "callable-api/pkg/logger" // Renomeando para evitar conflito
-	"callable-api/pkg/secrets"
-	"callable-api/pkg/storage"
+	"callable-api/pkg/mail"
 )
 
 // @title Callable API
@@ -57,78 +86,626 @@ func SetupEnv(cfg *config.Config) {
 	}
 }
 
-// SetupGCPServices configura e inicializa os serviços do GCP
-func SetupGCPServices(cfg *config.Config) (gcplogger.Logger, secrets.SecretManager, *storage.CloudStorage) {
-	ctx := context.Background()
-
-	// Inicializar o logger com suporte a GCP
-	log, err := gcplogger.NewGCPLogger(ctx, cfg.GCPProjectID, cfg.LoggingName, cfg.UseCloudLogging)
+// SetupMode define o modo de execução a partir da variável MODE. No modo real
+// os backends simulados não são aceitos: Secret Manager, Cloud Storage e um
+// provedor de email genuíno precisam estar configurados
+func SetupMode(cfg *config.Config, mailCfg mail.Config) error {
+	m, err := checkMode(cfg, mailCfg)
 	if err != nil {
-		logger.Error("Erro ao inicializar logger GCP", map[string]interface{}{
-			"error": err.Error(),
+		return err
+	}
+
+	mode.Set(m)
+	if m == mode.Demo {
+		logger.Warn("Executando em modo demo: dados de exemplo e backends simulados ativos", map[string]interface{}{
+			"mode": string(m),
 		})
-		// Continuar com o logger padrão em caso de erro
 	} else {
-		logger.Info("GCP Logger inicializado com sucesso", map[string]interface{}{
-			"useCloudLogging": cfg.UseCloudLogging,
+		logger.Info("Executando em modo real", map[string]interface{}{
+			"mode": string(m),
 		})
 	}
+	return nil
+}
 
-	// Inicializar Secret Manager se GCP estiver configurado
-	var secretManager secrets.SecretManager
-	if cfg.GCPProjectID != "" && cfg.UseSecretManager {
-		secretManager = secrets.NewGCPSecretManager(cfg.GCPProjectID)
-		logger.Info("Secret Manager inicializado", map[string]interface{}{
-			"project_id": cfg.GCPProjectID,
-		})
-	} else {
-		logger.Info("Secret Manager não configurado, usando valores locais", nil)
+// checkMode lê o modo de execução (MODE) e verifica se o modo real tem
+// todos os backends genuínos configurados
+func checkMode(cfg *config.Config, mailCfg mail.Config) (mode.Mode, error) {
+	m, err := mode.Parse(os.Getenv("MODE"))
+	if err != nil {
+		return m, err
 	}
 
-	// Inicializar Cloud Storage se bucket estiver configurado
-	var cloudStorage *storage.CloudStorage
-	if cfg.GCPStorageBucket != "" {
-		cloudStorage = storage.NewCloudStorage(cfg.GCPStorageBucket)
-		logger.Info("Cloud Storage inicializado", map[string]interface{}{
-			"bucket": cfg.GCPStorageBucket,
+	if m == mode.Real {
+		var missing []string
+		if !cfg.UseSecretManager || cfg.GCPProjectID == "" {
+			missing = append(missing, "Secret Manager (USE_SECRET_MANAGER e GCP_PROJECT_ID)")
+		}
+		if cfg.GCPStorageBucket == "" {
+			missing = append(missing, "Cloud Storage (GCP_STORAGE_BUCKET)")
+		}
+		if mailCfg.Provider == "" || mailCfg.Provider == mail.ProviderLog {
+			missing = append(missing, "provedor de email (MAIL_PROVIDER=smtp ou sendgrid)")
+		}
+		if len(missing) > 0 {
+			return m, fmt.Errorf("modo real requer backends genuínos; não configurados: %s", strings.Join(missing, ", "))
+		}
+	}
+	return m, nil
+}
+
+// SetupGCPServices configura e inicializa os serviços do GCP
+func SetupGCPServices(cfg *config.Config) cloud.Services {
+	services := cloud.New(context.Background(), cfg)
+
+	// Segredos em cache com expiração e limite de entradas; sem o cache, cada
+	// leitura vai ao Secret Manager
+	if services.Secrets != nil {
+		cache, err := cloud.NewSecretCache(services.Secrets, loadSecretCacheConfig())
+		if err != nil {
+			logger.Error("Erro ao criar cache de segredos", map[string]interface{}{
+				"error": err.Error(),
+			})
+		} else {
+			services.Secrets = cache
+		}
+	}
+	return services
+}
+
+// SetupMailer configura o subsistema de email, retornando nil se a configuração for inválida
+func SetupMailer() *mail.Mailer {
+	mailCfg := loadMailConfig()
+
+	mailer, err := mail.New(mailCfg)
+	if err != nil {
+		logger.Error("Erro ao inicializar envio de emails", map[string]interface{}{
+			"error": err.Error(),
 		})
-	} else {
-		logger.Info("Cloud Storage não configurado", nil)
+		return nil
 	}
 
-	return log, secretManager, cloudStorage
+	logger.Info("Envio de emails inicializado", map[string]interface{}{
+		"provider": mailCfg.Provider,
+	})
+	return mailer
+}
+
+// SetupRouter configures and returns the Gin router, along with the function
+// that seeds its repositories (see setupRoutes)
+func SetupRouter(cfg *config.Config, gcp cloud.Services, mailer *mail.Mailer, jobManager *jobs.Manager, inFlight *stats.InFlight, streamHub *streams.Hub) (*gin.Engine, func()) {
+	router, _, seed := setupRoutes(cfg, gcp, mailer, jobManager, inFlight, streamHub)
+	return router, seed
 }
 
-// SetupRouter configures and returns the Gin router
-func SetupRouter(cfg *config.Config, gcpLog gcplogger.Logger, secretMgr secrets.SecretManager, cloudStorage *storage.CloudStorage) *gin.Engine {
+// setupRoutes monta o router e retorna também o registry com a declaração
+// das rotas (usado pelo comando routes) e a função que grava os dados
+// iniciais, cria o primeiro administrador e gera os dados sintéticos. A
+// função é chamada apenas por quem serve a API, para que o comando routes não
+// crie dados nem tokens de configuração
+func setupRoutes(cfg *config.Config, gcp cloud.Services, mailer *mail.Mailer, jobManager *jobs.Manager, inFlight *stats.InFlight, streamHub *streams.Hub) (*gin.Engine, *routes.Registry, func()) {
 	// Initialize Gin router
 	router := gin.New()
 
+	// Apenas os proxies confiáveis informam o IP real do cliente
+	if err := middleware.TrustProxies(router, loadProxyConfig()); err != nil {
+		logger.Error("Proxies confiáveis inválidos; usando o IP da conexão", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+
 	// Adicionar middlewares
-	router.Use(errors.RecoveryMiddleware()) // Primeiro o recovery
-	router.Use(errors.ErrorMiddleware())    // Depois o tratamento de erros
-	router.Use(middleware.RequestLogger())  // Por último o logger
+	router.Use(middleware.RecoveryMiddleware(reporting.New(loadReportingConfig(cfg)))) // Primeiro o recovery
+	router.Use(errors.ErrorMiddleware())                                               // Depois o tratamento de erros
+	router.Use(middleware.RequestIDMiddleware())                                       // Identificação da requisição para os logs
+	router.Use(middleware.LocaleMiddleware())                                          // Idioma das mensagens de validação
+	router.Use(middleware.RequestLogger())                                             // Depois o logger
+	router.Use(middleware.ChaosMiddleware(loadChaosConfig()))                          // Por último a injeção de falhas (nunca no modo real)
+
+	// Estatísticas de tráfego para o painel de operações
+	requestStats := stats.NewCollector()
+	router.Use(middleware.StatsMiddleware(requestStats))
+
+	// Requisições em andamento, acompanhadas no encerramento do servidor
+	if inFlight == nil {
+		inFlight = stats.NewInFlight()
+	}
+	router.Use(middleware.InFlightMiddleware(inFlight))
+
+	// Orçamento de memória por requisição (experimental), para encontrar os
+	// handlers que leem payloads inteiros em memória
+	allocationBudget := stats.NewAllocationBudget(loadAllocationBudgetConfig())
+	router.Use(middleware.AllocationBudgetMiddleware(allocationBudget))
+
+	// Gravação de requisições para depuração, ativada por administradores.
+	// As gravações vão para o Cloud Storage, se configurado
+	var recordingStore recorder.Store = recorder.NewMemoryStore(0)
+	if gcp.Storage != nil {
+		recordingStore = recorder.NewCloudStore(gcp.Storage)
+	}
+	requestRecorder := recorder.New(recordingStore)
+	router.Use(middleware.RecordingMiddleware(requestRecorder))
+
+	// Criar as instâncias dos repositórios, todas instrumentadas com métricas
+	// de latência e de erros (exibidas no painel de operações) e logs de depuração
+	repositoryMetrics := metrics.NewRegistry()
+	instrument := repository.NewInstrumentation(repositoryMetrics)
 
-	// Criar as instâncias dos repositórios
-	itemRepo := repository.NewInMemoryItemRepository()
-	userRepo := repository.NewInMemoryUserRepository()
+	// O formato dos IDs de cada entidade vem de ID_FORMAT/ID_FORMAT_<ENTIDADE>.
+	// Os usuários e itens iniciais vêm de SEED_FILE ou dos dados de exemplo
+	idCfg := loadIDConfig()
+	memoryItemRepo := repository.NewEmptyInMemoryItemRepository().WithIDGenerator(idCfg.Generator(ids.EntityItems))
+	memoryUserRepo := repository.NewEmptyInMemoryUserRepository().WithIDGenerator(idCfg.Generator(ids.EntityUsers))
+	itemRepo := instrument.Items(memoryItemRepo)
+	userRepo := instrument.Users(memoryUserRepo)
+	notificationPrefsRepo := instrument.NotificationPreferences(repository.NewInMemoryNotificationPreferencesRepository())
+	notificationRepo := instrument.Notifications(repository.NewInMemoryNotificationRepository().WithIDGenerator(idCfg.Generator(ids.EntityNotifications)))
+	loginHistoryRepo := instrument.LoginHistory(repository.NewInMemoryLoginHistoryRepository().WithIDGenerator(idCfg.Generator(ids.EntityLoginAttempts)))
+	itemShareRepo := instrument.ItemShares(repository.NewInMemoryItemShareRepository().WithIDGenerator(idCfg.Generator(ids.EntityShares)))
+	deviceBindingRepo := instrument.DeviceBindings(repository.NewInMemoryDeviceBindingRepository())
+	sessionRepo := instrument.Sessions(repository.NewInMemorySessionRepository())
+	scopedTokenRepo := instrument.ScopedTokens(repository.NewInMemoryScopedTokenRepository())
+	commentRepo := instrument.Comments(repository.NewInMemoryCommentRepository().WithIDGenerator(idCfg.Generator(ids.EntityComments)))
+	orgRepo := instrument.Organizations(repository.NewInMemoryOrganizationRepository().WithIDGenerator(idCfg.Generator(ids.EntityOrganizations)))
+	invitationRepo := instrument.Invitations(repository.NewInMemoryInvitationRepository().WithIDGenerator(idCfg.Generator(ids.EntityInvitations)))
+	consentRepo := instrument.Consents(repository.NewInMemoryConsentRepository().WithIDGenerator(idCfg.Generator(ids.EntityConsents)))
+
+	// Versionamento dos itens e usuários gravados: os registros de versões
+	// anteriores são atualizados na leitura e pelo job de migração, que usa os
+	// repositórios sem o versionamento
+	versioning := repository.NewVersioning(repository.ItemSchema, repository.UserSchema)
+	storedItems, storedUsers := itemRepo, userRepo
+	migrateSchema := func(ctx context.Context) (*models.SchemaMigration, error) {
+		return versioning.Migrate(ctx, storedItems, storedUsers)
+	}
+	itemRepo = versioning.Items(itemRepo)
+	userRepo = versioning.Users(userRepo)
+
+	// Cifragem em envelope da descrição e do email dos itens com dono, com
+	// uma chave de dados por usuário. A chave é validada em run(), que não
+	// inicia a API com uma chave inválida
+	if dataKeys, err := loadDataKeys(); err != nil {
+		logger.Error("Cifragem dos dados dos usuários desativada: chave mestra inválida", map[string]interface{}{
+			"error": err.Error(),
+		})
+	} else if dataKeys != nil {
+		encryption := repository.NewEncryption(dataKeys)
+		itemRepo = encryption.Items(itemRepo)
+		userRepo = encryption.Users(userRepo)
+	}
+
+	// Eventos de domínio, compartilhados pelos consumidores (webhooks, Pub/Sub, WebSocket)
+	eventBus := events.NewBus()
+	eventBus.Subscribe(func(ctx context.Context, envelope *events.Envelope) {
+		logger.Debug("Evento de domínio publicado", correlation.Fields(ctx, map[string]interface{}{
+			"event":   envelope.Type,
+			"eventId": envelope.ID,
+			"version": envelope.Version,
+		}))
+	})
+	// Conexões de streaming (SSE) que acompanham os eventos
+	if streamHub != nil {
+		eventBus.Subscribe(streamHub.Publish)
+	}
+
+	// Log de alterações dos itens, para a sincronização incremental
+	changeFeed := changes.NewFeed(loadChangeFeedConfig())
+	eventBus.Subscribe(changeFeed.HandleEvent)
 
 	// Criar as instâncias dos serviços
-	itemService := service.NewItemService(itemRepo)
-	authService := service.NewAuthService(userRepo, cfg)
+	itemService := service.NewItemService(itemRepo).
+		WithEvents(eventBus).
+		WithChangeFeed(changeFeed).
+		WithSharing(itemShareRepo, userRepo).
+		WithDuplicateRule(loadDuplicateRule()).
+		WithItemCache(loadItemCacheConfig())
+
+	// Medição opcional da latência e das alocações dos serviços
+	serviceMetrics := metrics.NewRegistry()
+	if profiler := loadServiceProfiler(serviceMetrics); profiler != nil {
+		itemService.WithProfiler(profiler)
+	}
+
+	// Avatares de perfil, gravados no Cloud Storage quando configurado
+	var avatarStore avatar.Store = avatar.NewMemoryStore()
+	if gcp.Storage != nil {
+		avatarStore = avatar.NewCloudStore(gcp.Storage)
+	}
+	avatarService := avatar.NewService(avatarStore, loadAvatarConfig())
+	// Segredo vigente dos JWTs, trocado quando o Secret Manager avisa a rotação
+	jwtKeys := jwtkey.New(cfg).WithGracePeriod(loadJWTRotationGrace())
+	authService := service.NewAuthService(userRepo, cfg).
+		WithKeys(jwtKeys).
+		WithEvents(eventBus).
+		WithAvatars(avatarService).
+		WithLoginHistory(loginHistoryRepo).
+		WithDeviceBinding(deviceBindingRepo, loadDeviceBindingConfig()).
+		WithSessions(sessionRepo, loadSessionConfig()).
+		WithConsent(consentRepo, loadConsentConfig()).
+		WithScopedTokens(scopedTokenRepo, loadScopedTokenConfig())
+	if mailer != nil {
+		authService.WithPasswordReset(mailer, loadPasswordResetConfig())
+	}
+
+	// Organizações: os itens criados com uma organização ativa pertencem a ela
+	orgService := service.NewOrganizationService(orgRepo, invitationRepo, userRepo).
+		WithInvitationTTL(loadInvitationTTL())
+	// Cadastro com código de convite, obrigatório se o cadastro aberto está desativado
+	authService.WithInvitations(orgService, loadRegistrationMode())
+
+	// Primeiro administrador, criado apenas quando não há usuários. Os dados
+	// sintéticos são gravados depois, para não contarem como usuários
+	seed := func() {
+		seedFixtures(memoryUserRepo, memoryItemRepo)
+		bootstrapAdmin(authService)
+		if syntheticCfg := loadSyntheticConfig(); syntheticCfg.Enabled() {
+			seedSyntheticData(syntheticCfg, userRepo, itemRepo)
+		}
+	}
+
+	// Dependências externas verificadas pelo painel de operações
+	var dependencyChecks []health.Checker
+	var secretProvider *auth.SecretProvider
+	if gcp.Secrets != nil {
+		secretProvider = auth.NewSecretProvider(cfg, gcp.Secrets, gcp.Logger)
+		dependencyChecks = append(dependencyChecks, health.NewCheck("secret_manager", func(ctx context.Context) error {
+			_, err := secretProvider.GetJWTSecret(ctx)
+			return err
+		}))
+	}
+
+	// Espelhar itens no Elasticsearch/OpenSearch, se configurado
+	if searchCfg := loadSearchConfig(); searchCfg.Enabled() {
+		indexer := search.NewElasticsearchIndexer(searchCfg)
+		itemService.WithSearchIndexer(indexer)
+		dependencyChecks = append(dependencyChecks, health.NewCheck("elasticsearch", indexer.Ping))
+		logger.Info("Busca de itens via Elasticsearch ativada", map[string]interface{}{
+			"index": searchCfg.Index,
+		})
+	}
+
+	// Canais de notificação: in-app (caixa de entrada) e webhook; email apenas
+	// quando o envio de emails está configurado. As entregas de webhook ficam
+	// guardadas para reentrega durante a janela de retenção
+	notificationChannels := []notifications.Channel{
+		notifications.NewWebhookChannel(10 * time.Second).WithDeliveries(loadWebhookDeliveries()),
+	}
+	if mailer != nil {
+		notificationChannels = append(notificationChannels, notifications.NewEmailChannel(mailer))
+	}
+	notificationService := notifications.NewService(notificationPrefsRepo, userRepo, notificationChannels...).
+		WithInbox(notificationRepo)
+	// Job concluído e item compartilhado chegam às notificações pelo barramento
+	eventBus.Subscribe(notificationService.HandleEvent)
+	if jobManager != nil {
+		jobManager.WithNotifier(notificationService).
+			WithEvents(eventBus).
+			WithAlerts(notifications.AdminRecipients(userRepo))
+
+		// Resultados grandes dos jobs, gravados no Cloud Storage quando configurado
+		var artifactStore jobs.ArtifactStore = jobs.NewMemoryArtifactStore()
+		if gcp.Storage != nil {
+			artifactStore = jobs.NewCloudArtifactStore(gcp.Storage)
+		}
+		jobManager.WithArtifacts(artifactStore)
+	}
 
 	// Criar as instâncias dos handlers
-	itemHandler := handlers.NewItemHandler(itemService)
-	authHandler := handlers.NewAuthHandler(authService)
+	itemHandler := handlers.NewItemHandler(itemService).
+		WithTimeout(time.Duration(cfg.WriteTimeoutSecs) * time.Second).
+		WithPagination(loadPaginationConfig()).
+		WithBulkLimits(loadBulkLimits())
+	if jobManager != nil {
+		itemHandler.WithAsyncCreate(jobManager, loadItemCreateMode())
+	}
+	changesHandler := handlers.NewChangesHandler(itemService).WithPagination(loadPaginationConfig())
+	authHandler := handlers.NewAuthHandler(authService).WithPagination(loadPaginationConfig())
+	avatarHandler := handlers.NewAvatarHandler(avatarService)
+	notificationHandler := handlers.NewNotificationHandler(notificationService).WithPagination(loadPaginationConfig())
+	orgHandler := handlers.NewOrganizationHandler(orgService)
+	commentHandler := handlers.NewCommentHandler(service.NewCommentService(commentRepo, itemService, userRepo)).
+		WithPagination(loadPaginationConfig())
+
+	// Cota de requisições por usuário (ou IP, para clientes anônimos)
+	quotaTracker := quota.NewTracker(loadQuotaConfig())
+	usageHandler := handlers.NewUsageHandler(quotaTracker)
+	recordingHandler := handlers.NewRecordingHandler(requestRecorder, router)
+	healthHandler := handlers.NewHealthHandler(cfg, dependencyChecks...).
+		WithKeys(jwtKeys).
+		WithReadiness(health.NewReadiness().WithDependencies(gcp.Monitor.States))
+	adminUserHandler := handlers.NewAdminUserHandler(authService).WithPagination(loadPaginationConfig())
+	overviewService := admin.NewOverviewService(requestStats, userRepo, itemRepo, dependencyChecks...).
+		WithInFlight(inFlight).
+		WithOperationMetrics(repositoryMetrics).
+		WithServiceMetrics(serviceMetrics).
+		WithAllocationBudget(allocationBudget)
+	if jobManager != nil {
+		overviewService.WithJobCounter(jobManager).WithJobPanics(jobManager)
+	}
+	adminHandler := handlers.NewAdminHandler(overviewService)
 
 	// Criar handler de demonstração do GCP (se configurado)
-	gcpDemoHandler := handlers.NewGCPDemoHandler(cfg, gcpLog, secretMgr, cloudStorage)
+	gcpDemoHandler := handlers.NewGCPDemoHandler(cfg, gcp)
+
+	// Declaração das rotas com seus requisitos de segurança. A cadeia de
+	// middlewares de cada rota é montada pelo registry a partir da declaração
+	registry := routes.New(middleware.RequireRole).
+		WithAuth(routes.AuthJWT, middleware.ScopedTokenMiddleware(authService.AuthenticateScopedToken, middleware.JWTAuthMiddleware(jwtKeys, authService.CheckAccount))).
+		WithAuth(routes.AuthOptional, middleware.ScopedTokenMiddleware(authService.AuthenticateScopedToken, middleware.OptionalJWTAuthMiddleware(jwtKeys, authService.CheckAccount))).
+		WithScopes(middleware.RequireScope).
+		WithAuthenticated(middleware.PreferencesMiddleware(authService.Preferences)).
+		WithAuthenticated(middleware.QuotaHeadersMiddleware(quotaTracker)).
+		WithAuthenticated(middleware.OrganizationMiddleware(orgService.Membership)).
+		WithAuthenticated(middleware.ConsentMiddleware(authService.CheckConsent, "/api/v1/auth/consent")).
+		WithRateLimit(routes.RateStandard, middleware.QuotaMiddleware(quotaTracker, jwtKeys, authService.AuthenticateScopedToken)).
+		WithRateLimit(routes.RateStrict, middleware.QuotaMiddleware(quota.NewTracker(loadStrictQuotaConfig()), jwtKeys, authService.AuthenticateScopedToken)).
+		WithRateLimit(routes.RateAnonymous, middleware.QuotaMiddleware(quota.NewTracker(loadAnonymousQuotaConfig()), jwtKeys, authService.AuthenticateScopedToken)).
+		WithAnonymous(loadAnonymousMode())
+	for policy, corsCfg := range loadCORSPolicies() {
+		registry.WithCORS(policy, middleware.CORS(corsCfg))
+	}
+	adminHandler.WithRoutes(registry)
+	openAPIHandler := handlers.NewOpenAPIHandler(registry, docs.SwaggerInfo.ReadDoc())
+
+	adminOnly := []string{"admin"}
+	registry.Add(
+		// Rotas de infraestrutura
+		routes.Route{Method: http.MethodGet, Path: "/health", Handler: healthHandler.Check, RateClass: routes.RateUnlimited,
+			Description: "Health check (detalhes com verbose=true e token de admin)"},
+		routes.Route{Method: http.MethodGet, Path: "/ready", Handler: healthHandler.Ready, RateClass: routes.RateUnlimited,
+			Description: "Prontidão para o balanceador (503 durante o dreno)"},
+		routes.Route{Method: http.MethodGet, Path: "/api/test-gcp-integration", Handler: gcpIntegrationHandler(gcpDemoHandler), RateClass: routes.RateStrict,
+			Description: "Teste da integração com o GCP"},
+		routes.Route{Method: http.MethodGet, Path: "/swagger/*any", Handler: ginSwagger.WrapHandler(swaggerFiles.Handler), RateClass: routes.RateUnlimited,
+			Description: "Documentação Swagger"},
+		routes.Route{Method: http.MethodGet, Path: "/api/v1/errors", Handler: handlers.ErrorCodes, RateClass: routes.RateUnlimited,
+			Description: "Catálogo de códigos de erro"},
+		routes.Route{Method: http.MethodGet, Path: "/openapi.json", Handler: openAPIHandler.Public, RateClass: routes.RateUnlimited,
+			Description: "Documento OpenAPI sem as rotas de administração"},
+	)
+
+	// Itens, consumidos também pelos frontends de parceiros
+	registry.Add(routes.CORSGroup(routes.CORSPublic,
+		routes.Route{Method: http.MethodGet, Path: "/api/v1/data", Handler: itemHandler.GetData, Auth: routes.AuthOptional, Anonymous: true,
+			Description: "Lista itens paginados (públicos e, com token, os próprios e compartilhados)"},
+		routes.Route{Method: http.MethodGet, Path: "/api/v1/data/search", Handler: itemHandler.SearchData, Auth: routes.AuthOptional,
+			Description: "Busca itens por texto"},
+		routes.Route{Method: http.MethodGet, Path: "/api/v1/data/changes", Handler: changesHandler.GetChanges, Auth: routes.AuthOptional,
+			Description: "Log de alterações dos itens para sincronização incremental"},
+		routes.Route{Method: http.MethodGet, Path: "/api/v1/data/:id", Handler: itemHandler.GetDataById, Auth: routes.AuthOptional, Anonymous: true,
+			Description: "Retorna um item"},
+		routes.Route{Method: http.MethodPost, Path: "/api/v1/data", Handler: itemHandler.PostData, Auth: routes.AuthJWT, Strict: true,
+			Description: "Cria um item"},
+		routes.Route{Method: http.MethodPost, Path: "/api/v1/data/bulk", Handler: itemHandler.PostDataBulk, Auth: routes.AuthJWT, Strict: true,
+			Description: "Cria itens em lote (array JSON lido e validado elemento a elemento)"},
+		routes.Route{Method: http.MethodPut, Path: "/api/v1/data/:id", Handler: itemHandler.PutData, Auth: routes.AuthJWT, Strict: true,
+			Description: "Atualiza um item (acesso de escrita)"},
+		routes.Route{Method: http.MethodPost, Path: "/api/v1/data/:id/share", Handler: itemHandler.ShareData, Auth: routes.AuthJWT, Strict: true,
+			Description: "Compartilha um item com um usuário ou papel"},
+		routes.Route{Method: http.MethodGet, Path: "/api/v1/data/:id/shares", Handler: itemHandler.ListDataShares, Auth: routes.AuthJWT,
+			Description: "Lista os compartilhamentos de um item"},
+		routes.Route{Method: http.MethodDelete, Path: "/api/v1/data/:id/shares/:shareId", Handler: itemHandler.RevokeDataShare, Auth: routes.AuthJWT,
+			Description: "Revoga um compartilhamento"},
+		routes.Route{Method: http.MethodGet, Path: "/api/v1/data/:id/comments", Handler: commentHandler.ListComments, Auth: routes.AuthOptional,
+			Description: "Lista os comentários de um item"},
+		routes.Route{Method: http.MethodPost, Path: "/api/v1/data/:id/comments", Handler: commentHandler.AddComment, Auth: routes.AuthJWT, Strict: true,
+			Description: "Comenta um item ou responde a um comentário"},
+		routes.Route{Method: http.MethodDelete, Path: "/api/v1/data/:id/comments/:commentId", Handler: commentHandler.DeleteComment, Auth: routes.AuthJWT,
+			Description: "Remove um comentário próprio"},
+	)...)
+
+	// Autenticação e conta do usuário
+	registry.Add(routes.CORSGroup(routes.CORSAuth,
+		routes.Route{Method: http.MethodPost, Path: "/api/v1/auth/register", Handler: authHandler.Register, RateClass: routes.RateStrict, Strict: true,
+			Description: "Registra um usuário"},
+		routes.Route{Method: http.MethodPost, Path: "/api/v1/auth/bootstrap", Handler: authHandler.Bootstrap, RateClass: routes.RateStrict, Strict: true,
+			Description: "Cria o primeiro administrador com o token de configuração"},
+		routes.Route{Method: http.MethodPost, Path: "/api/v1/auth/login", Handler: authHandler.Login, RateClass: routes.RateStrict,
+			Description: "Autentica um usuário"},
+		routes.Route{Method: http.MethodPost, Path: "/api/v1/auth/refresh", Handler: authHandler.RefreshToken, RateClass: routes.RateStrict,
+			Description: "Renova o token de acesso"},
+		routes.Route{Method: http.MethodPost, Path: "/api/v1/auth/password/reset", Handler: authHandler.ResetPassword, RateClass: routes.RateStrict, Strict: true,
+			Description: "Redefine a senha com o token enviado por email"},
+		routes.Route{Method: http.MethodGet, Path: "/api/v1/auth/profile", Handler: authHandler.Profile, Auth: routes.AuthJWT,
+			Description: "Perfil do usuário"},
+		routes.Route{Method: http.MethodPut, Path: "/api/v1/auth/profile", Handler: authHandler.UpdateProfile, Auth: routes.AuthJWT, Strict: true,
+			Description: "Atualiza o perfil do usuário"},
+		routes.Route{Method: http.MethodPatch, Path: "/api/v1/auth/profile", Handler: authHandler.PatchProfile, Auth: routes.AuthJWT, Strict: true,
+			Description: "Atualiza parcialmente o perfil do usuário"},
+		routes.Route{Method: http.MethodPut, Path: "/api/v1/auth/profile/avatar", Handler: authHandler.UploadAvatar, Auth: routes.AuthJWT,
+			Description: "Envia o avatar do usuário"},
+		routes.Route{Method: http.MethodGet, Path: "/api/v1/avatars/:name", Handler: avatarHandler.Get,
+			Description: "Imagem de avatar"},
+		routes.Route{Method: http.MethodGet, Path: "/api/v1/auth/notifications/preferences", Handler: notificationHandler.GetPreferences, Auth: routes.AuthJWT,
+			Description: "Preferências de notificação"},
+		routes.Route{Method: http.MethodPut, Path: "/api/v1/auth/notifications/preferences", Handler: notificationHandler.UpdatePreferences, Auth: routes.AuthJWT, Strict: true,
+			Description: "Atualiza as preferências de notificação"},
+		routes.Route{Method: http.MethodGet, Path: "/api/v1/notifications", Handler: notificationHandler.ListNotifications, Auth: routes.AuthJWT,
+			Description: "Caixa de entrada de notificações in-app"},
+		routes.Route{Method: http.MethodPost, Path: "/api/v1/notifications/read-all", Handler: notificationHandler.MarkAllNotificationsRead, Auth: routes.AuthJWT,
+			Description: "Marca todas as notificações como lidas"},
+		routes.Route{Method: http.MethodPost, Path: "/api/v1/notifications/:id/read", Handler: notificationHandler.MarkNotificationRead, Auth: routes.AuthJWT,
+			Description: "Marca uma notificação como lida"},
+		routes.Route{Method: http.MethodGet, Path: "/api/v1/webhooks/:id/deliveries", Handler: notificationHandler.ListWebhookDeliveries, Auth: routes.AuthJWT,
+			Description: "Entregas recentes ao webhook do usuário"},
+		routes.Route{Method: http.MethodPost, Path: "/api/v1/webhooks/:id/redeliver/:event", Handler: notificationHandler.RedeliverWebhook, Auth: routes.AuthJWT, RateClass: routes.RateStrict,
+			Description: "Reentrega um evento ao webhook do usuário"},
+		routes.Route{Method: http.MethodGet, Path: "/api/v1/auth/login-history", Handler: authHandler.LoginHistory, Auth: routes.AuthJWT,
+			Description: "Histórico de login do usuário"},
+		routes.Route{Method: http.MethodGet, Path: "/api/v1/auth/usage", Handler: usageHandler.GetUsage, Auth: routes.AuthJWT,
+			Description: "Consumo da cota de requisições"},
+		routes.Route{Method: http.MethodPost, Path: "/api/v1/auth/token/narrow", Handler: authHandler.NarrowToken, Auth: routes.AuthJWT, RateClass: routes.RateStrict, Strict: true,
+			Description: "Emite um token restrito a parte dos escopos"},
+		routes.Route{Method: http.MethodGet, Path: "/api/v1/auth/consent", Handler: authHandler.GetConsent, Auth: routes.AuthJWT,
+			Description: "Aceites dos termos de uso e da política de privacidade"},
+		routes.Route{Method: http.MethodPost, Path: "/api/v1/auth/consent", Handler: authHandler.RecordConsent, Auth: routes.AuthJWT, Strict: true,
+			Description: "Aceita as versões vigentes dos termos"},
+		routes.Route{Method: http.MethodPost, Path: "/api/v1/orgs", Handler: orgHandler.CreateOrganization, Auth: routes.AuthJWT, Strict: true,
+			Description: "Cria uma organização"},
+		routes.Route{Method: http.MethodGet, Path: "/api/v1/orgs", Handler: orgHandler.ListOrganizations, Auth: routes.AuthJWT,
+			Description: "Organizações do usuário"},
+		routes.Route{Method: http.MethodGet, Path: "/api/v1/orgs/:id/members", Handler: orgHandler.ListMembers, Auth: routes.AuthJWT,
+			Description: "Membros da organização"},
+		routes.Route{Method: http.MethodDelete, Path: "/api/v1/orgs/:id/members/:userId", Handler: orgHandler.RemoveMember, Auth: routes.AuthJWT,
+			Description: "Remove um membro da organização"},
+		routes.Route{Method: http.MethodPost, Path: "/api/v1/orgs/:id/invitations", Handler: orgHandler.InviteMember, Auth: routes.AuthJWT, Strict: true,
+			Description: "Convida um email para a organização"},
+		routes.Route{Method: http.MethodGet, Path: "/api/v1/orgs/:id/invitations", Handler: orgHandler.ListInvitations, Auth: routes.AuthJWT,
+			Description: "Convites da organização"},
+		routes.Route{Method: http.MethodPost, Path: "/api/v1/orgs/invitations/accept", Handler: orgHandler.AcceptInvitation, Auth: routes.AuthJWT, Strict: true,
+			Description: "Aceita um convite para uma organização"},
+	)...)
+
+	// Administração, usada pelas ferramentas internas
+	registry.Add(routes.CORSGroup(routes.CORSAdmin,
+		routes.Route{Method: http.MethodGet, Path: "/api/v1/admin/overview", Handler: adminHandler.Overview, Auth: routes.AuthJWT, Roles: adminOnly,
+			Description: "Resumo operacional"},
+		routes.Route{Method: http.MethodGet, Path: "/api/v1/admin/routes", Handler: adminHandler.Routes, Auth: routes.AuthJWT, Roles: adminOnly,
+			Description: "Matriz de segurança das rotas"},
+		routes.Route{Method: http.MethodGet, Path: "/api/v1/admin/openapi.json", Handler: openAPIHandler.Full, Auth: routes.AuthJWT, Roles: adminOnly,
+			Description: "Documento OpenAPI completo, com as rotas de administração e operação"},
+		routes.Route{Method: http.MethodPost, Path: "/api/v1/admin/drain", Handler: healthHandler.Drain, Auth: routes.AuthJWT, Roles: adminOnly,
+			Description: "Retira a instância do balanceador (prontidão falhando)"},
+		routes.Route{Method: http.MethodPost, Path: "/api/v1/admin/undrain", Handler: healthHandler.Undrain, Auth: routes.AuthJWT, Roles: adminOnly,
+			Description: "Devolve a instância ao balanceador"},
+		routes.Route{Method: http.MethodPost, Path: "/api/v1/admin/users/:id/disable", Handler: adminUserHandler.DisableUser, Auth: routes.AuthJWT, Roles: adminOnly,
+			Description: "Desativa a conta de um usuário"},
+		routes.Route{Method: http.MethodPost, Path: "/api/v1/admin/users/:id/enable", Handler: adminUserHandler.EnableUser, Auth: routes.AuthJWT, Roles: adminOnly,
+			Description: "Reativa a conta de um usuário"},
+		routes.Route{Method: http.MethodPost, Path: "/api/v1/admin/users/:id/force-password-reset", Handler: adminUserHandler.ForcePasswordReset, Auth: routes.AuthJWT, Roles: adminOnly,
+			Description: "Força a redefinição de senha de um usuário"},
+		routes.Route{Method: http.MethodPost, Path: "/api/v1/admin/invitations", Handler: orgHandler.CreateInvitation, Auth: routes.AuthJWT, Roles: adminOnly, Strict: true,
+			Description: "Emite um convite de cadastro"},
+		routes.Route{Method: http.MethodGet, Path: "/api/v1/admin/invitations", Handler: orgHandler.ListRegistrationInvitations, Auth: routes.AuthJWT, Roles: adminOnly,
+			Description: "Convites de cadastro emitidos"},
+		routes.Route{Method: http.MethodGet, Path: "/api/v1/admin/users/:id/login-history", Handler: adminUserHandler.LoginHistory, Auth: routes.AuthJWT, Roles: adminOnly,
+			Description: "Histórico de login de um usuário"},
+		routes.Route{Method: http.MethodGet, Path: "/api/v1/admin/users/:id/consent", Handler: adminUserHandler.UserConsent, Auth: routes.AuthJWT, Roles: adminOnly,
+			Description: "Histórico de aceites dos termos de um usuário"},
+		routes.Route{Method: http.MethodGet, Path: "/api/v1/admin/recordings/settings", Handler: recordingHandler.GetSettings, Auth: routes.AuthJWT, Roles: adminOnly,
+			Description: "Configuração da gravação de requisições"},
+		routes.Route{Method: http.MethodPut, Path: "/api/v1/admin/recordings/settings", Handler: recordingHandler.UpdateSettings, Auth: routes.AuthJWT, Roles: adminOnly, Strict: true,
+			Description: "Configura a gravação de requisições"},
+		routes.Route{Method: http.MethodGet, Path: "/api/v1/admin/recordings", Handler: recordingHandler.ListRecordings, Auth: routes.AuthJWT, Roles: adminOnly,
+			Description: "Lista as gravações"},
+		routes.Route{Method: http.MethodPost, Path: "/api/v1/admin/recordings/:id/replay", Handler: recordingHandler.ReplayRecording, Auth: routes.AuthJWT, Roles: adminOnly,
+			Description: "Reproduz uma gravação"},
+	)...)
+
+	// Acompanhamento dos jobs em segundo plano (ex.: criação assíncrona de itens)
+	if jobManager != nil {
+		jobHandler := handlers.NewJobHandler(jobManager).WithCompression(loadExportCompression())
+		if streamHub != nil {
+			jobHandler.WithStreams(streamHub)
+			registry.Add(
+				routes.Route{Method: http.MethodGet, Path: "/api/v1/jobs/:id/events", Handler: jobHandler.JobEvents, Auth: routes.AuthJWT, CORS: routes.CORSPublic,
+					Description: "Acompanha um job por Server-Sent Events"},
+			)
+		}
+		jobAdminHandler := handlers.NewJobAdminHandler(jobManager)
+		schemaAdminHandler := handlers.NewSchemaAdminHandler(migrateSchema, jobManager)
+		adminUserHandler.WithImport(jobManager, loadUserImportLimits())
+		registry.Add(
+			routes.Route{Method: http.MethodGet, Path: "/api/v1/jobs/:id", Handler: jobHandler.GetJob, Auth: routes.AuthJWT, CORS: routes.CORSPublic,
+				Description: "Estado de um job em segundo plano"},
+			routes.Route{Method: http.MethodGet, Path: "/api/v1/jobs/:id/artifact", Handler: jobHandler.GetArtifact, Auth: routes.AuthJWT, CORS: routes.CORSPublic,
+				Description: "Download do artefato gerado por um job"},
+			routes.Route{Method: http.MethodGet, Path: "/api/v1/jobs/:id/logs", Handler: jobHandler.GetJobLogs, Auth: routes.AuthJWT, CORS: routes.CORSPublic,
+				Description: "Log de execução de um job"},
+			routes.Route{Method: http.MethodGet, Path: "/api/v1/admin/jobs/stats", Handler: jobAdminHandler.Stats, Auth: routes.AuthJWT, Roles: adminOnly, CORS: routes.CORSAdmin,
+				Description: "Estatísticas dos jobs"},
+			routes.Route{Method: http.MethodPost, Path: "/api/v1/admin/jobs/cleanup", Handler: jobAdminHandler.Cleanup, Auth: routes.AuthJWT, Roles: adminOnly, CORS: routes.CORSAdmin,
+				Description: "Remove os jobs terminados há mais tempo que older_than"},
+			routes.Route{Method: http.MethodPost, Path: "/api/v1/admin/users/import", Handler: adminUserHandler.ImportUsers, Auth: routes.AuthJWT, Roles: adminOnly, CORS: routes.CORSAdmin,
+				Description: "Enfileira a importação de usuários de um CSV, com convite para definir a senha"},
+			routes.Route{Method: http.MethodPost, Path: "/api/v1/admin/schema/migrate", Handler: schemaAdminHandler.Migrate, Auth: routes.AuthJWT, Roles: adminOnly, CORS: routes.CORSAdmin,
+				Description: "Enfileira a migração dos registros para a versão atual do modelo"},
+		)
+	}
+
+	// Pré-visualização dos templates de email
+	if mailer != nil {
+		mailAdminHandler := handlers.NewMailAdminHandler(mailer.Templates())
+		registry.Add(
+			routes.Route{Method: http.MethodGet, Path: "/api/v1/admin/mail/preview/:template", Handler: mailAdminHandler.Preview, Auth: routes.AuthJWT, Roles: adminOnly, CORS: routes.CORSAdmin,
+				Description: "Renderiza um template de email com os dados de exemplo"},
+		)
+	}
+
+	// Métricas no formato do Prometheus, coletadas com METRICS_TOKEN
+	if metricsToken := loadMetricsToken(); metricsToken != "" {
+		exporter := metrics.NewExporter().
+			WithRegistry("repository", repositoryMetrics).
+			WithRegistry("service", serviceMetrics)
+		if jobManager != nil {
+			exporter.WithJobCounter(jobManager)
+		}
+		metricsHandler := handlers.NewMetricsHandler(exporter)
+		registry.WithAuth(routes.AuthMetrics, middleware.InternalTokenMiddleware(metricsToken))
+		registry.Add(
+			routes.Route{Method: http.MethodGet, Path: "/metrics", Handler: metricsHandler.Prometheus, Auth: routes.AuthMetrics, RateClass: routes.RateUnlimited,
+				Description: "Métricas no formato do Prometheus"},
+		)
+	}
 
-	// Health check route
-	router.GET("/health", handlers.HealthCheck)
+	// Provisionamento de usuários pelos provedores de identidade (SCIM 2.0)
+	if scimCfg := loadSCIMConfig(); scimCfg.Enabled() {
+		scimHandler := handlers.NewSCIMHandler(scim.NewService(userRepo))
+		registry.WithAuth(routes.AuthSCIM, middleware.SCIMAuthMiddleware(scimCfg.Token))
+		registry.Add(
+			routes.Route{Method: http.MethodPost, Path: scim.BasePath + "/Users", Handler: scimHandler.CreateUser, Auth: routes.AuthSCIM,
+				Description: "Provisiona um usuário (SCIM)"},
+			routes.Route{Method: http.MethodGet, Path: scim.BasePath + "/Users", Handler: scimHandler.ListUsers, Auth: routes.AuthSCIM,
+				Description: "Lista usuários com filtros (SCIM)"},
+			routes.Route{Method: http.MethodGet, Path: scim.BasePath + "/Users/:id", Handler: scimHandler.GetUser, Auth: routes.AuthSCIM,
+				Description: "Retorna um usuário (SCIM)"},
+			routes.Route{Method: http.MethodPatch, Path: scim.BasePath + "/Users/:id", Handler: scimHandler.PatchUser, Auth: routes.AuthSCIM,
+				Description: "Altera ou desativa um usuário (SCIM)"},
+		)
+	}
+
+	// Notificações de rotação do Secret Manager (push do Pub/Sub)
+	if rotationCfg := loadSecretRotationConfig(); rotationCfg.Enabled() && gcp.Secrets != nil {
+		// O cache de segredos (ver SetupGCPServices) descarta o segredo rotacionado
+		invalidator, _ := gcp.Secrets.(rotation.Invalidator)
+		rotationService := rotation.NewService(invalidator).
+			OnRotate(rotationCfg.JWTSecretName, func(ctx context.Context, secret string) error {
+				// Os novos tokens passam a ser assinados com a nova versão; os
+				// antigos valem até o fim do período de transição
+				value, err := secretProvider.GetJWTSecret(ctx)
+				if err != nil {
+					return err
+				}
+				jwtKeys.Rotate(value)
+				logger.Info("Segredo do JWT recarregado após rotação", map[string]interface{}{
+					"secret": secret,
+				})
+				return nil
+			})
+		rotationHandler := handlers.NewSecretRotationHandler(rotationService)
+		registry.WithAuth(routes.AuthInternal, middleware.InternalTokenMiddleware(rotationCfg.Token))
+		registry.Add(
+			routes.Route{Method: http.MethodPost, Path: "/internal/secrets/rotation", Handler: rotationHandler.HandleNotification, Auth: routes.AuthInternal, RateClass: routes.RateUnlimited,
+				Description: "Recebe as notificações de rotação do Secret Manager (Pub/Sub)"},
+		)
+	}
 
-	// Rota para testar integração GCP
-	router.GET("/api/test-gcp-integration", func(c *gin.Context) {
+	if err := registry.Mount(router); err != nil {
+		// Declarações inconsistentes são erros de programação
+		panic(err)
+	}
+
+	// Caminhos e métodos inexistentes respondem no formato padrão de erro
+	router.HandleMethodNotAllowed = true
+	router.NoRoute(handlers.NoRoute)
+	router.NoMethod(handlers.NoMethod)
+
+	return router, registry, seed
+}
+
+// gcpIntegrationHandler adapta o handler de demonstração do GCP ao Gin
+func gcpIntegrationHandler(gcpDemoHandler *handlers.GCPDemoHandler) gin.HandlerFunc {
+	return func(c *gin.Context) {
 		if gcpDemoHandler != nil {
 			gcpDemoHandler.TestIntegration(c.Writer, c.Request)
 		} else {
@@ -137,122 +714,390 @@ func SetupRouter(cfg *config.Config, gcpLog gcplogger.Logger, secretMgr secrets.
 				"message": "GCP integration not configured",
 			})
 		}
+	}
+}
+
+// seedFixtures grava os dados iniciais nos repositórios. Um arquivo inválido
+// é registrado como erro e a aplicação inicia sem dados
+func seedFixtures(users *repository.InMemoryUserRepository, items *repository.InMemoryItemRepository) {
+	fixtures, err := loadFixtures()
+	if err != nil {
+		logger.Error("Falha ao carregar os dados iniciais; a aplicação inicia sem dados", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+	if fixtures == nil {
+		logger.Info("Dados iniciais desativados", nil)
+		return
+	}
+
+	summary, err := fixtures.Apply(context.Background(), users, items)
+	if err != nil {
+		logger.Error("Falha ao gravar os dados iniciais", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+	logger.Info("Dados iniciais gravados", map[string]interface{}{
+		"users": summary.Users,
+		"items": summary.Items,
 	})
+}
 
-	// API v1 route group
-	v1 := router.Group("/api/v1")
-	{
-		// Rotas públicas
-		v1.GET("/data", itemHandler.GetData)
-		v1.GET("/data/:id", itemHandler.GetDataById)
-
-		// Rotas de autenticação
-		auth := v1.Group("/auth")
-		{
-			auth.POST("/register", authHandler.Register)
-			auth.POST("/login", authHandler.Login)
-			auth.POST("/refresh", authHandler.RefreshToken)
-
-			// Rotas autenticadas
-			protected := auth.Group("/")
-			protected.Use(middleware.JWTAuthMiddleware(cfg))
-			{
-				protected.GET("/profile", authHandler.Profile)
-				protected.PUT("/profile", authHandler.UpdateProfile)
-			}
-		}
+// bootstrapAdmin cria o primeiro administrador com BOOTSTRAP_ADMIN_EMAIL e
+// BOOTSTRAP_ADMIN_PASSWORD ou, sem eles, imprime o token de configuração
+// exigido por POST /api/v1/auth/bootstrap. O token vai apenas para a saída de
+// erro, não para os logs
+func bootstrapAdmin(authService *service.AuthService) {
+	token, err := authService.Bootstrap(context.Background(), loadBootstrapConfig())
+	if err != nil {
+		logger.Error("Falha ao criar o primeiro administrador", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+	if token == "" {
+		return
+	}
 
-		// Rotas que exigem autenticação
-		protected := v1.Group("/")
-		protected.Use(middleware.JWTAuthMiddleware(cfg))
-		{
-			// Rotas básicas autenticadas
-			protected.POST("/data", itemHandler.PostData)
-
-			// Rotas que exigem papel de admin
-			admin := protected.Group("/admin")
-			admin.Use(middleware.RequireRole("admin"))
-			{
-				// Aqui você pode adicionar rotas administrativas
-				// Exemplo: admin.GET("/users", userHandler.ListUsers)
-			}
-		}
+	logger.Warn("Nenhum usuário cadastrado: crie o primeiro administrador em POST /api/v1/auth/bootstrap com o token de configuração", nil)
+	fmt.Fprintf(os.Stderr, "\nToken de configuração (uso único): %s\nCrie o primeiro administrador em POST /api/v1/auth/bootstrap\n\n", token)
+}
+
+// seedSyntheticData grava os dados sintéticos nos repositórios. No modo real
+// a geração é recusada, para não misturar dados falsos aos dados reais
+func seedSyntheticData(cfg synthetic.Config, users repository.UserRepository, items repository.ItemRepository) {
+	if !mode.IsDemo() {
+		logger.Error("Dados sintéticos só podem ser gerados no modo demo; geração ignorada", nil)
+		return
 	}
 
-	// Route to access Swagger documentation
-	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	start := time.Now()
+	summary, err := synthetic.Seed(context.Background(), cfg, users, items)
+	if err != nil {
+		logger.Error("Falha ao gerar os dados sintéticos", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+	logger.Info("Dados sintéticos gerados", map[string]interface{}{
+		"users":       summary.Users,
+		"items":       summary.Items,
+		"publicItems": summary.PublicItems,
+		"elapsed":     time.Since(start).String(),
+	})
+}
+
+// SetupJobs inicia a execução dos jobs em segundo plano
+func SetupJobs() *jobs.Manager {
+	jobsCfg := loadJobsConfig()
+	manager := jobs.NewManager(jobs.NewMemoryStore(), jobsCfg)
 
-	return router
+	logger.Info("Execução de jobs inicializada", map[string]interface{}{
+		"workers":           jobsCfg.Workers,
+		"queueSize":         jobsCfg.QueueSize,
+		"maxRunningPerUser": jobsCfg.MaxRunningPerUser,
+		"maxQueuedPerUser":  jobsCfg.MaxQueuedPerUser,
+	})
+	return manager
+}
+
+// SetupMailQueue envia os emails em segundo plano como jobs mail.JobType,
+// retomados após uma reinicialização, quando MAIL_ASYNC está ativo. Os jobs
+// são agrupados por destinatário nos limites por usuário do Manager
+func SetupMailQueue(manager *jobs.Manager, mailer *mail.Mailer) {
+	if mailer == nil || !loadMailConfig().Async {
+		return
+	}
+
+	manager.Register(mail.JobType, func(ctx context.Context, input json.RawMessage) (interface{}, error) {
+		var msg mail.Message
+		if err := json.Unmarshal(input, &msg); err != nil {
+			return nil, err
+		}
+		return nil, mailer.Deliver(ctx, &msg)
+	})
+	mailer.WithQueue(func(ctx context.Context, msg *mail.Message) error {
+		_, err := manager.Enqueue(ctx, "mail:"+strings.Join(msg.To, ","), mail.JobType, msg)
+		return err
+	})
+}
+
+// serverLimits define os limites de conexão do servidor HTTP, que protegem
+// contra clientes lentos (slowloris) e o acúmulo de conexões ociosas
+type serverLimits struct {
+	ReadHeaderTimeout time.Duration
+	IdleTimeout       time.Duration
+	MaxHeaderBytes    int
+	KeepAlives        bool
+}
+
+// defaultServerLimits retorna os limites padrão: 5s para os headers, 2
+// minutos de conexão ociosa, headers de até 1 MiB e keep-alive ativo
+func defaultServerLimits() serverLimits {
+	return serverLimits{
+		ReadHeaderTimeout: 5 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+		MaxHeaderBytes:    1 << 20,
+		KeepAlives:        true,
+	}
 }
 
 // SetupServer configures and returns the HTTP server
 func SetupServer(cfg *config.Config, router *gin.Engine) *http.Server {
-	return &http.Server{
-		Addr:         ":" + cfg.Port,
-		Handler:      router,
-		ReadTimeout:  time.Duration(cfg.ReadTimeoutSecs) * time.Second,
-		WriteTimeout: time.Duration(cfg.WriteTimeoutSecs) * time.Second,
+	limits := loadServerLimits()
+	server := &http.Server{
+		Addr:              ":" + cfg.Port,
+		Handler:           router,
+		ReadTimeout:       time.Duration(cfg.ReadTimeoutSecs) * time.Second,
+		ReadHeaderTimeout: limits.ReadHeaderTimeout,
+		WriteTimeout:      time.Duration(cfg.WriteTimeoutSecs) * time.Second,
+		IdleTimeout:       limits.IdleTimeout,
+		MaxHeaderBytes:    limits.MaxHeaderBytes,
 	}
+	server.SetKeepAlivesEnabled(limits.KeepAlives)
+	return server
 }
 
-// StartServer starts the HTTP server and sets up graceful shutdown
-func StartServer(server *http.Server, cfg *config.Config, gcpLog gcplogger.Logger) {
-	// Start server in a separate goroutine
+// StartServer opens the listening socket and serves until a shutdown signal
+// arrives. Bind and serve errors are returned to the caller, which is
+// responsible for releasing the remaining resources. If the configured port
+// is in use, the fallback ports are tried in order. inFlight (optional)
+// reports the requests still running during the shutdown
+func StartServer(server *http.Server, cfg *config.Config, inFlight *stats.InFlight, fallbackPorts ...string) error {
+	listener, err := listen(server.Addr, fallbackPorts)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Server started", map[string]interface{}{
+		"addr": listener.Addr().String(),
+	})
+
+	// Serve in a separate goroutine, reporting its error back
+	serveErr := make(chan error, 1)
 	go func() {
-		logger.Info("Server started", map[string]interface{}{
-			"port": cfg.Port,
-		})
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			logger.Error("Error starting server", map[string]interface{}{
-				"error": err.Error(),
-			})
-			os.Exit(1)
-		}
+		serveErr <- server.Serve(listener)
 	}()
 
 	// Graceful shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-	logger.Info("Shutting down server...", nil)
+	defer signal.Stop(quit)
+
+	select {
+	case err := <-serveErr:
+		if err != nil && err != http.ErrServerClosed {
+			return fmt.Errorf("erro no servidor HTTP: %w", err)
+		}
+		return nil
+	case <-quit:
+	}
+	fields := map[string]interface{}{}
+	if inFlight != nil {
+		fields["inFlight"] = inFlight.Count()
+	}
+	logger.Info("Shutting down server...", fields)
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.GracefulTimeoutSecs)*time.Second)
 	defer cancel()
 
 	if err := server.Shutdown(ctx); err != nil {
-		logger.Error("Server shutdown error", map[string]interface{}{
+		if inFlight != nil && stderrors.Is(err, context.DeadlineExceeded) {
+			logger.Warn("Prazo de encerramento esgotado com requisições em andamento", map[string]interface{}{
+				"inFlight": inFlight.Count(),
+				"routes":   inFlight.Routes(),
+			})
+		}
+		return fmt.Errorf("erro ao encerrar o servidor: %w", err)
+	}
+	return nil
+}
+
+// listen abre o socket em addr. Se a porta estiver em uso, tenta as portas
+// alternativas em ordem; outros erros são retornados imediatamente
+func listen(addr string, fallbackPorts []string) (net.Listener, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, fmt.Errorf("endereço inválido %q: %w", addr, err)
+	}
+
+	candidates := []string{addr}
+	for _, port := range fallbackPorts {
+		candidates = append(candidates, net.JoinHostPort(host, port))
+	}
+
+	for i, candidate := range candidates {
+		listener, err := net.Listen("tcp", candidate)
+		if err == nil {
+			return listener, nil
+		}
+		if !stderrors.Is(err, syscall.EADDRINUSE) || i == len(candidates)-1 {
+			return nil, fmt.Errorf("falha ao abrir %s: %w", candidate, err)
+		}
+		logger.Warn("Porta em uso, tentando a próxima porta alternativa", map[string]interface{}{
+			"addr": candidate,
+			"next": candidates[i+1],
+		})
+	}
+	return nil, fmt.Errorf("nenhum endereço disponível para o servidor")
+}
+
+// closeGCPServices libera os clientes do GCP, se configurados
+func closeGCPServices(gcp cloud.Services) {
+	if err := gcp.Close(); err != nil {
+		logger.Error("Error closing GCP logger", map[string]interface{}{
 			"error": err.Error(),
 		})
 	}
+}
 
-	// Fechar o logger do GCP se estiver configurado
-	if gcpLog != nil {
-		if err := gcpLog.Close(); err != nil {
-			logger.Error("Error closing GCP logger", map[string]interface{}{
-				"error": err.Error(),
-			})
-		}
+// recoverJobs retoma os jobs que não terminaram antes da última parada
+func recoverJobs(manager *jobs.Manager) {
+	resumed, err := manager.Recover(context.Background())
+	if err != nil {
+		logger.Error("Falha ao retomar jobs interrompidos", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+	if resumed > 0 {
+		logger.Info("Jobs interrompidos retomados", map[string]interface{}{
+			"count": resumed,
+		})
 	}
+}
 
-	logger.Info("Server exited gracefully", nil)
+// closeJobs aguarda a execução dos jobs que já estão na fila
+func closeJobs(manager *jobs.Manager, cfg *config.Config) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.GracefulTimeoutSecs)*time.Second)
+	defer cancel()
+	if err := manager.Close(ctx); err != nil {
+		logger.Error("Error draining job queue", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
 }
 
-func main() {
+// run inicializa e executa a API. Os recursos são liberados pelos defers
+// mesmo quando a inicialização ou o servidor falham
+func run() error {
 	// Load configuration
 	cfg := config.Load()
 
 	// Setup environment
 	SetupEnv(cfg)
 
+	// Definir o modo de execução (demo ou real) antes de inicializar os backends
+	if err := SetupMode(cfg, loadMailConfig()); err != nil {
+		return fmt.Errorf("configuração inválida para o modo de execução: %w", err)
+	}
+
+	if err := loadIDConfig().Validate(); err != nil {
+		return fmt.Errorf("formato de ID inválido: %w", err)
+	}
+
+	// Sem a chave, os dados pessoais seriam gravados em texto claro
+	if _, err := loadDataKeys(); err != nil {
+		return fmt.Errorf("chave de cifragem dos dados inválida: %w", err)
+	}
+
+	// Sem credenciais válidas, a API subiria sem o primeiro administrador
+	if err := loadBootstrapConfig().Validate(); err != nil {
+		return fmt.Errorf("credenciais do primeiro administrador inválidas: %w", err)
+	}
+
+	// Proxy e destinos permitidos das chamadas externas, antes de criar as integrações
+	if err := httpclient.SetEgress(loadEgressConfig()); err != nil {
+		return fmt.Errorf("política de saída inválida: %w", err)
+	}
+
 	// Setup GCP Services
-	gcpLog, secretMgr, cloudStorage := SetupGCPServices(cfg)
+	gcp := SetupGCPServices(cfg)
+	defer closeGCPServices(gcp)
+
+	// Setup mail delivery
+	mailer := SetupMailer()
+
+	// Setup background jobs
+	jobManager := SetupJobs()
+	defer closeJobs(jobManager, cfg)
+	SetupMailQueue(jobManager, mailer)
 
 	// Setup router with GCP services
-	router := SetupRouter(cfg, gcpLog, secretMgr, cloudStorage)
+	inFlight := stats.NewInFlight()
+	streamHub := streams.NewHub(loadStreamsConfig())
+	router, seed := SetupRouter(cfg, gcp, mailer, jobManager, inFlight, streamHub)
+
+	// Dados iniciais, primeiro administrador e dados sintéticos
+	seed()
+
+	// Retomar os jobs interrompidos, agora que os tipos de job estão registrados
+	recoverJobs(jobManager)
 
 	// Setup server
 	server := SetupServer(cfg, router)
+	// Interface web em /app/*, fora da cadeia de middlewares da API
+	if appCfg := loadWebAppConfig(); appCfg.Enabled {
+		app, err := webapp.New(appCfg)
+		if err != nil {
+			return err
+		}
+		server.Handler = webapp.Mount(server.Handler, app)
+		logger.Info("Interface web disponível em "+webapp.Prefix+"/", map[string]interface{}{
+			"dir": appCfg.Dir,
+		})
+	}
+	// No início do encerramento, as conexões de streaming são avisadas para
+	// que os clientes se reconectem a outra instância
+	server.RegisterOnShutdown(streamHub.Drain)
 
 	// Start server with graceful shutdown
-	StartServer(server, cfg, gcpLog)
+	return StartServer(server, cfg, inFlight, loadFallbackPorts()...)
+}
+
+func main() {
+	check := flag.Bool("check", false, "verifica a configuração e as dependências, imprime um relatório e encerra")
+	flag.Parse()
+
+	if *check {
+		if err := runCheck(os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if flag.Arg(0) == "routes" {
+		if err := runRoutes(os.Stdout, flag.Args()[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if flag.Arg(0) == "metrics" {
+		if err := runMetrics(os.Stdout, flag.Args()[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if flag.Arg(0) == "loadgen" {
+		if err := runLoadgen(os.Stdout, flag.Args()[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if err := run(); err != nil {
+		logger.Error("API encerrada com erro", map[string]interface{}{
+			"error": err.Error(),
+		})
+		os.Exit(1)
+	}
+	logger.Info("Server exited gracefully", nil)
 }
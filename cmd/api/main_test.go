@@ -3,6 +3,7 @@ package main
 import (
 	"bytes"
 	"encoding/json"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -12,10 +13,10 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"callable-api/internal/models"
+	"callable-api/pkg/cloud"
 	"callable-api/pkg/config"
 	"callable-api/pkg/logger"
 	"callable-api/pkg/secrets"
-	"callable-api/pkg/storage"
 )
 
 // Constantes para evitar duplicação de strings
@@ -35,21 +36,15 @@ func TestSetupRouter(t *testing.T) {
 	// Mock GCP services para teste
 	var gcpLog logger.Logger = nil
 	var secretMgr secrets.SecretManager = nil
-	var cloudStorage *storage.CloudStorage = nil
-
-	// Mock GCP services para teste
-	var gcpLog logger.Logger = nil
-	var secretMgr secrets.SecretManager = nil
-	var cloudStorage *storage.CloudStorage = nil
+	var cloudStorage cloud.Storage = nil
 
 	// Test the router setup function
-	router := SetupRouter(cfg, gcpLog, secretMgr, cloudStorage)
-	router := SetupRouter(cfg, gcpLog, secretMgr, cloudStorage)
+	router, seed := SetupRouter(cfg, cloud.Services{Logger: gcpLog, Secrets: secretMgr, Storage: cloudStorage}, nil, nil, nil, nil)
+	seed()
 	assert.NotNil(t, router)
 
 	// Test health endpoint
 	req, _ := http.NewRequest("GET", healthPath, nil)
-	req, _ := http.NewRequest("GET", healthPath, nil)
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 	assert.Equal(t, 200, w.Code)
@@ -76,9 +71,6 @@ func TestSetupServer(t *testing.T) {
 		Port:              "8080",
 		ReadTimeoutSecs:   10,
 		WriteTimeoutSecs:  10,
-		Port:              "8080",
-		ReadTimeoutSecs:   10,
-		WriteTimeoutSecs:  10,
 	}
 	router := gin.New()
 	server := SetupServer(cfg, router)
@@ -86,6 +78,9 @@ func TestSetupServer(t *testing.T) {
 	assert.Equal(t, ":8080", server.Addr)
 	assert.Equal(t, 10*time.Second, server.ReadTimeout)
 	assert.Equal(t, 10*time.Second, server.WriteTimeout)
+	assert.Equal(t, 5*time.Second, server.ReadHeaderTimeout)
+	assert.Equal(t, 2*time.Minute, server.IdleTimeout)
+	assert.Equal(t, 1<<20, server.MaxHeaderBytes)
 }
 
 func TestSetupGCPServices(t *testing.T) {
@@ -100,26 +95,7 @@ func TestSetupGCPServices(t *testing.T) {
 	// Sem configuração, os serviços não devem estar inicializados corretamente
 	// Mas a função não deve falhar
 	assert.NotPanics(t, func() {
-		_, _, _ = SetupGCPServices(minimalCfg)
-	})
-	
-	// Com config mínima, verificamos apenas se a função retorna e não falha
-	// Testes mais específicos precisariam de mocks mais elaborados
-}
-
-func TestSetupGCPServices(t *testing.T) {
-	// Test without GCP configuration
-	minimalCfg := &config.Config{
-		GCPProjectID:     "",
-		UseCloudLogging:  false,
-		UseSecretManager: false,
-		GCPStorageBucket: "",
-	}
-	
-	// Sem configuração, os serviços não devem estar inicializados corretamente
-	// Mas a função não deve falhar
-	assert.NotPanics(t, func() {
-		_, _, _ = SetupGCPServices(minimalCfg)
+		_ = SetupGCPServices(minimalCfg)
 	})
 	
 	// Com config mínima, verificamos apenas se a função retorna e não falha
@@ -134,20 +110,13 @@ func TestIntegrationHealthCheck(t *testing.T) {
 	// Mock GCP services
 	var gcpLog logger.Logger = nil
 	var secretMgr secrets.SecretManager = nil
-	var cloudStorage *storage.CloudStorage = nil
-	
-	router := SetupRouter(cfg, gcpLog, secretMgr, cloudStorage)
-	
-	// Mock GCP services
-	var gcpLog logger.Logger = nil
-	var secretMgr secrets.SecretManager = nil
-	var cloudStorage *storage.CloudStorage = nil
+	var cloudStorage cloud.Storage = nil
 	
-	router := SetupRouter(cfg, gcpLog, secretMgr, cloudStorage)
+	router, seed := SetupRouter(cfg, cloud.Services{Logger: gcpLog, Secrets: secretMgr, Storage: cloudStorage}, nil, nil, nil, nil)
+	seed()
 
 	// Test health check endpoint
 	req, _ := http.NewRequest(http.MethodGet, healthPath, nil)
-	req, _ := http.NewRequest(http.MethodGet, healthPath, nil)
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 
@@ -167,20 +136,13 @@ func TestIntegrationGetData(t *testing.T) {
 	// Mock GCP services
 	var gcpLog logger.Logger = nil
 	var secretMgr secrets.SecretManager = nil
-	var cloudStorage *storage.CloudStorage = nil
-	
-	router := SetupRouter(cfg, gcpLog, secretMgr, cloudStorage)
-	
-	// Mock GCP services
-	var gcpLog logger.Logger = nil
-	var secretMgr secrets.SecretManager = nil
-	var cloudStorage *storage.CloudStorage = nil
+	var cloudStorage cloud.Storage = nil
 	
-	router := SetupRouter(cfg, gcpLog, secretMgr, cloudStorage)
+	router, seed := SetupRouter(cfg, cloud.Services{Logger: gcpLog, Secrets: secretMgr, Storage: cloudStorage}, nil, nil, nil, nil)
+	seed()
 
 	// Test GET /api/v1/data endpoint
 	req, _ := http.NewRequest(http.MethodGet, apiV1DataPath, nil)
-	req, _ := http.NewRequest(http.MethodGet, apiV1DataPath, nil)
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 
@@ -195,19 +157,13 @@ func TestIntegrationGetDataById(t *testing.T) {
 	// Mock GCP services
 	var gcpLog logger.Logger = nil
 	var secretMgr secrets.SecretManager = nil
-	var cloudStorage *storage.CloudStorage = nil
-	
-	router := SetupRouter(cfg, gcpLog, secretMgr, cloudStorage)
+	var cloudStorage cloud.Storage = nil
 	
-	// Mock GCP services
-	var gcpLog logger.Logger = nil
-	var secretMgr secrets.SecretManager = nil
-	var cloudStorage *storage.CloudStorage = nil
-	
-	router := SetupRouter(cfg, gcpLog, secretMgr, cloudStorage)
+	router, seed := SetupRouter(cfg, cloud.Services{Logger: gcpLog, Secrets: secretMgr, Storage: cloudStorage}, nil, nil, nil, nil)
+	seed()
 
-	// Test GET /api/v1/data/:id endpoint
-	req, _ := http.NewRequest(http.MethodGet, apiV1DataPath+"/123", nil)
+	// Test GET /api/v1/data/:id endpoint com um dos itens dos dados iniciais
+	req, _ := http.NewRequest(http.MethodGet, apiV1DataPath+"/1", nil)
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 
@@ -223,7 +179,7 @@ func TestIntegrationGetDataById(t *testing.T) {
 	// Conversão segura para map[string]interface{}
 	data, ok := response.Data.(map[string]interface{})
 	assert.True(t, ok)
-	assert.Equal(t, "123", data["id"])
+	assert.Equal(t, "1", data["id"])
 }
 
 func TestIntegrationPostDataWithAuth(t *testing.T) {
@@ -234,16 +190,10 @@ func TestIntegrationPostDataWithAuth(t *testing.T) {
 	// Mock GCP services
 	var gcpLog logger.Logger = nil
 	var secretMgr secrets.SecretManager = nil
-	var cloudStorage *storage.CloudStorage = nil
-	
-	router := SetupRouter(cfg, gcpLog, secretMgr, cloudStorage)
+	var cloudStorage cloud.Storage = nil
 	
-	// Mock GCP services
-	var gcpLog logger.Logger = nil
-	var secretMgr secrets.SecretManager = nil
-	var cloudStorage *storage.CloudStorage = nil
-	
-	router := SetupRouter(cfg, gcpLog, secretMgr, cloudStorage)
+	router, seed := SetupRouter(cfg, cloud.Services{Logger: gcpLog, Secrets: secretMgr, Storage: cloudStorage}, nil, nil, nil, nil)
+	seed()
 
 	// Prepare data for POST
 	input := models.InputData{
@@ -256,17 +206,8 @@ func TestIntegrationPostDataWithAuth(t *testing.T) {
 
 	// Test POST with token
 	req, _ := http.NewRequest(http.MethodPost, apiV1DataPath, bytes.NewBuffer(jsonData))
-	req, _ := http.NewRequest(http.MethodPost, apiV1DataPath, bytes.NewBuffer(jsonData))
 	req.Header.Set("Content-Type", "application/json")
 	
-	// Como o DemoApiToken não existe na estrutura Config, vamos usar um token de teste
-	// Se seu middleware de autenticação usar uma variável de ambiente ou outra fonte,
-	// você pode precisar configurar isso aqui
-	testToken := "test-token"
-	req.Header.Set("Authorization", "Bearer "+testToken)
-	// Como o DemoApiToken não existe na estrutura Config, vamos usar um token de teste
-	// Se seu middleware de autenticação usar uma variável de ambiente ou outra fonte,
-	// você pode precisar configurar isso aqui
 	testToken := "test-token"
 	req.Header.Set("Authorization", "Bearer "+testToken)
 	
@@ -288,16 +229,10 @@ func TestIntegrationPostDataWithoutAuth(t *testing.T) {
 	// Mock GCP services
 	var gcpLog logger.Logger = nil
 	var secretMgr secrets.SecretManager = nil
-	var cloudStorage *storage.CloudStorage = nil
+	var cloudStorage cloud.Storage = nil
 	
-	router := SetupRouter(cfg, gcpLog, secretMgr, cloudStorage)
-	
-	// Mock GCP services
-	var gcpLog logger.Logger = nil
-	var secretMgr secrets.SecretManager = nil
-	var cloudStorage *storage.CloudStorage = nil
-	
-	router := SetupRouter(cfg, gcpLog, secretMgr, cloudStorage)
+	router, seed := SetupRouter(cfg, cloud.Services{Logger: gcpLog, Secrets: secretMgr, Storage: cloudStorage}, nil, nil, nil, nil)
+	seed()
 
 	// Prepare data for POST
 	input := models.InputData{
@@ -310,7 +245,6 @@ func TestIntegrationPostDataWithoutAuth(t *testing.T) {
 
 	// Test POST without token
 	req, _ := http.NewRequest(http.MethodPost, apiV1DataPath, bytes.NewBuffer(jsonData))
-	req, _ := http.NewRequest(http.MethodPost, apiV1DataPath, bytes.NewBuffer(jsonData))
 	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
@@ -326,24 +260,25 @@ func TestIntegrationGCPDemo(t *testing.T) {
 	// Mock GCP services - usando nulos para testar o comportamento padrão
 	var gcpLog logger.Logger = nil
 	var secretMgr secrets.SecretManager = nil
-	var cloudStorage *storage.CloudStorage = nil
+	var cloudStorage cloud.Storage = nil
 	
-	router := SetupRouter(cfg, gcpLog, secretMgr, cloudStorage)
+	router, seed := SetupRouter(cfg, cloud.Services{Logger: gcpLog, Secrets: secretMgr, Storage: cloudStorage}, nil, nil, nil, nil)
+	seed()
 
 	// Test GCP demo endpoint
 	req, _ := http.NewRequest(http.MethodGet, apiTestGCPPath, nil)
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 
-	// Quando não temos GCP configurado, deve retornar erro
-	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	// Sem GCP configurado, o endpoint usa o logger local e pula os serviços ausentes
+	assert.Equal(t, http.StatusOK, w.Code)
 	
 	// Verificar a resposta específica
 	var response map[string]interface{}
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
-	assert.Equal(t, "error", response["status"])
-	assert.Equal(t, "GCP integration not configured", response["message"])
+	assert.Equal(t, "success", response["status"])
+	assert.NotEmpty(t, response["tests"])
 }
 
 // Não é prático testar StartServer completamente pois envolve servidor real,
@@ -384,68 +319,24 @@ func TestStartServerSetup(t *testing.T) {
 	})
 }
 
-func TestIntegrationGCPDemo(t *testing.T) {
-	// Use the actual router setup from main.go
-	gin.SetMode(gin.TestMode)
-	cfg := config.Load()
-	
-	// Mock GCP services - usando nulos para testar o comportamento padrão
-	var gcpLog logger.Logger = nil
-	var secretMgr secrets.SecretManager = nil
-	var cloudStorage *storage.CloudStorage = nil
-	
-	router := SetupRouter(cfg, gcpLog, secretMgr, cloudStorage)
+// TestListenFallback verifica o uso da porta alternativa quando a principal está ocupada
+func TestListenFallback(t *testing.T) {
+	// Ocupar uma porta para forçar o uso da alternativa
+	busy, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer busy.Close()
 
-	// Test GCP demo endpoint
-	req, _ := http.NewRequest(http.MethodGet, apiTestGCPPath, nil)
-	w := httptest.NewRecorder()
-	router.ServeHTTP(w, req)
+	free, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	_, freePort, _ := net.SplitHostPort(free.Addr().String())
+	free.Close()
 
-	// Quando não temos GCP configurado, deve retornar erro
-	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
-	
-	// Verificar a resposta específica
-	var response map[string]interface{}
-	err := json.Unmarshal(w.Body.Bytes(), &response)
+	listener, err := listen(busy.Addr().String(), []string{freePort})
 	assert.NoError(t, err)
-	assert.Equal(t, "error", response["status"])
-	assert.Equal(t, "GCP integration not configured", response["message"])
-}
+	defer listener.Close()
+	assert.Equal(t, "127.0.0.1:"+freePort, listener.Addr().String())
 
-// Não é prático testar StartServer completamente pois envolve servidor real,
-// mas podemos testar aspectos básicos como configuração
-func TestStartServerSetup(t *testing.T) {
-	// Criar um servidor simples para teste
-	cfg := config.Load()
-	server := &http.Server{
-		Addr: ":0", // usa porta aleatória para evitar conflitos
-	}
-	
-	// Verificar que não há pânico ao iniciar a função
-	// Nota: não podemos executar completamente pois bloquearia o teste
-	assert.NotPanics(t, func() {
-		// Iniciar em goroutine para não bloquear, mas capturar pânico
-		go func() {
-			defer func() {
-				if r := recover(); r != nil {
-					t.Errorf("Panic in StartServer: %v", r)
-				}
-			}()
-			
-			// Isso vai bloquear, então precisamos ter uma maneira de sair
-			// Usar timeout pequeno para não bloquear o teste
-			c := make(chan struct{}, 1)
-			go func() {
-				time.Sleep(50 * time.Millisecond)
-				server.Close()
-				c <- struct{}{}
-			}()
-			
-			StartServer(server, cfg, nil)
-			<-c
-		}()
-		
-		// Dar tempo suficiente para tudo acontecer
-		time.Sleep(100 * time.Millisecond)
-	})
-}
\ No newline at end of file
+	// Sem alternativas, o erro de bind é retornado em vez de encerrar o processo
+	_, err = listen(busy.Addr().String(), nil)
+	assert.Error(t, err)
+}
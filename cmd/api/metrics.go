@@ -0,0 +1,35 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"callable-api/internal/metrics"
+)
+
+// runMetrics executa os subcomandos de métricas. "rules" imprime as regras
+// de gravação e de alerta recomendadas para o Prometheus, geradas a partir
+// dos mesmos nomes de métricas expostos em GET /metrics
+func runMetrics(out io.Writer, args []string) error {
+	if len(args) == 0 || args[0] != "rules" {
+		return fmt.Errorf("uso: api metrics rules [--latency-slo 250ms] [--error-rate 0.05] [--job-backlog 100] [--window 5m] [--for 10m]")
+	}
+
+	defaults := metrics.DefaultRulesConfig()
+	fs := flag.NewFlagSet("metrics rules", flag.ContinueOnError)
+	cfg := metrics.RulesConfig{}
+	fs.DurationVar(&cfg.LatencySLO, "latency-slo", defaults.LatencySLO, "latência média máxima de cada operação")
+	fs.Float64Var(&cfg.ErrorRate, "error-rate", defaults.ErrorRate, "fração máxima de chamadas com falha de cada operação")
+	fs.IntVar(&cfg.JobBacklog, "job-backlog", defaults.JobBacklog, "quantidade máxima de jobs aguardando execução")
+	fs.DurationVar(&cfg.Window, "window", defaults.Window, "janela das taxas das regras de gravação")
+	fs.DurationVar(&cfg.For, "for", defaults.For, "duração da condição antes de o alerta disparar")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if cfg.LatencySLO <= 0 || cfg.Window <= 0 || cfg.For < 0 || cfg.JobBacklog < 0 || cfg.ErrorRate <= 0 || cfg.ErrorRate >= 1 {
+		return fmt.Errorf("latency-slo e window devem ser positivos, for e job-backlog não podem ser negativos e error-rate deve estar entre 0 e 1")
+	}
+
+	return metrics.WriteRules(out, metrics.Rules(cfg))
+}
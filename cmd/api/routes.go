@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/gin-gonic/gin"
+
+	"callable-api/pkg/cloud"
+	"callable-api/pkg/config"
+)
+
+// runRoutes imprime todas as rotas declaradas com o método, o caminho, o
+// modo de autenticação, os papéis exigidos e a classe de limite, para auditar
+// a exposição da API. Com --json, imprime a mesma matriz de
+// GET /api/v1/admin/routes
+func runRoutes(out io.Writer, args []string) error {
+	fs := flag.NewFlagSet("routes", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "imprime a matriz em JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	gin.SetMode(gin.ReleaseMode)
+	cfg := config.Load()
+
+	// As rotas de jobs só são declaradas com a execução de jobs ativa
+	jobManager := SetupJobs()
+	defer jobManager.Close(context.Background())
+
+	_, registry, _ := setupRoutes(cfg, cloud.Services{}, nil, jobManager, nil, nil)
+	matrix := registry.Matrix()
+
+	if *asJSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(matrix)
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "MÉTODO\tCAMINHO\tAUTENTICAÇÃO\tPAPÉIS\tLIMITE\tDESCRIÇÃO")
+	for _, route := range matrix {
+		roles := strings.Join(route.Roles, ",")
+		if roles == "" {
+			roles = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", route.Method, route.Path, route.Auth, roles, route.RateClass, route.Description)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,169 @@
+// Package admin reúne as funcionalidades operacionais expostas aos
+// administradores, como o resumo do estado da aplicação.
+package admin
+
+import (
+	"context"
+	"time"
+
+	"callable-api/internal/health"
+	"callable-api/internal/models"
+	"callable-api/internal/repository"
+	"callable-api/internal/stats"
+	"callable-api/internal/version"
+	"callable-api/pkg/logger"
+)
+
+// dependencyTimeout limita a verificação de cada dependência no resumo
+const dependencyTimeout = 2 * time.Second
+
+// JobCounter informa quantos jobs existem em cada estado
+type JobCounter interface {
+	CountByState(ctx context.Context) (map[string]int, error)
+}
+
+// OverviewService agrega as estatísticas do painel de operações
+type OverviewService struct {
+	stats    *stats.Collector
+	users    repository.UserRepository
+	items    repository.ItemRepository
+	checkers []health.Checker
+	jobs     JobCounter
+	panics   JobPanicReporter
+	metrics  OperationMetrics
+	services OperationMetrics
+	inFlight InFlightCounter
+	budget   AllocationBudgetReporter
+}
+
+// InFlightCounter informa quantas requisições estão em andamento (ver stats.InFlight)
+type InFlightCounter interface {
+	Count() int64
+}
+
+// AllocationBudgetReporter informa as rotas que excederam o orçamento de
+// alocações por requisição (ver stats.AllocationBudget)
+type AllocationBudgetReporter interface {
+	OverBudget() []models.RouteAllocationStats
+}
+
+// JobPanicReporter informa os panics de cada tipo de job (ver jobs.Manager)
+type JobPanicReporter interface {
+	PanicStats() []models.JobPanicStats
+}
+
+// OperationMetrics fornece as estatísticas das operações internas (ver metrics.Registry)
+type OperationMetrics interface {
+	Snapshot() []models.OperationStats
+}
+
+// NewOverviewService cria um novo serviço de resumo operacional
+func NewOverviewService(collector *stats.Collector, users repository.UserRepository, items repository.ItemRepository, checkers ...health.Checker) *OverviewService {
+	return &OverviewService{
+		stats:    collector,
+		users:    users,
+		items:    items,
+		checkers: checkers,
+	}
+}
+
+// WithJobCounter inclui no resumo a contagem de jobs por estado
+func (s *OverviewService) WithJobCounter(jobs JobCounter) *OverviewService {
+	s.jobs = jobs
+	return s
+}
+
+// WithJobPanics inclui no resumo os panics por tipo de job e os tipos desativados
+func (s *OverviewService) WithJobPanics(panics JobPanicReporter) *OverviewService {
+	s.panics = panics
+	return s
+}
+
+// WithOperationMetrics inclui no resumo a latência e a taxa de erros dos repositórios
+func (s *OverviewService) WithOperationMetrics(metrics OperationMetrics) *OverviewService {
+	s.metrics = metrics
+	return s
+}
+
+// WithServiceMetrics inclui no resumo a latência e as alocações das operações
+// dos serviços (ver metrics.Profiler)
+func (s *OverviewService) WithServiceMetrics(metrics OperationMetrics) *OverviewService {
+	s.services = metrics
+	return s
+}
+
+// WithAllocationBudget inclui no resumo as rotas acima do orçamento de alocações
+func (s *OverviewService) WithAllocationBudget(budget AllocationBudgetReporter) *OverviewService {
+	s.budget = budget
+	return s
+}
+
+// WithInFlight inclui no resumo o número de requisições em andamento
+func (s *OverviewService) WithInFlight(inFlight InFlightCounter) *OverviewService {
+	s.inFlight = inFlight
+	return s
+}
+
+// Overview monta o resumo operacional. Falhas ao consultar uma das fontes são
+// registradas em log e não impedem a montagem do restante do resumo
+func (s *OverviewService) Overview(ctx context.Context) (*models.AdminOverview, error) {
+	overview := &models.AdminOverview{
+		GeneratedAt:   time.Now().UTC(),
+		UptimeSeconds: int64(version.Uptime().Seconds()),
+		Requests:      s.stats.Snapshot(),
+		Jobs:          map[string]int{},
+		RecentErrors:  s.stats.RecentErrors(),
+		Dependencies:  health.Run(ctx, dependencyTimeout, s.checkers...),
+	}
+
+	if _, total, err := s.users.List(1, 1); err != nil {
+		logger.Warn("Falha ao contar usuários para o resumo", map[string]interface{}{
+			"error": err.Error(),
+		})
+	} else {
+		overview.Users = total
+	}
+
+	_, total, err := s.items.FindAll(ctx, models.ItemAccess{All: true}, 1, 1)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		logger.Warn("Falha ao contar itens para o resumo", map[string]interface{}{
+			"error": err.Error(),
+		})
+	} else {
+		overview.Items = total
+	}
+
+	if s.inFlight != nil {
+		overview.InFlightRequests = s.inFlight.Count()
+	}
+
+	if s.metrics != nil {
+		overview.Repositories = s.metrics.Snapshot()
+	}
+	if s.services != nil {
+		overview.Services = s.services.Snapshot()
+	}
+	if s.budget != nil {
+		overview.OverBudgetRoutes = s.budget.OverBudget()
+	}
+
+	if s.panics != nil {
+		overview.JobPanics = s.panics.PanicStats()
+	}
+
+	if s.jobs != nil {
+		counts, err := s.jobs.CountByState(ctx)
+		if err != nil {
+			logger.Warn("Falha ao contar jobs para o resumo", map[string]interface{}{
+				"error": err.Error(),
+			})
+		} else {
+			overview.Jobs = counts
+		}
+	}
+
+	return overview, nil
+}
@@ -0,0 +1,46 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"callable-api/internal/health"
+	"callable-api/internal/repository"
+	"callable-api/internal/repository/testsupport"
+	"callable-api/internal/stats"
+)
+
+type fakeJobCounter map[string]int
+
+func (f fakeJobCounter) CountByState(ctx context.Context) (map[string]int, error) {
+	return f, nil
+}
+
+func TestOverview(t *testing.T) {
+	collector := stats.NewCollector()
+	collector.Observe("GET", "/api/v1/data", 200, time.Millisecond, "")
+	collector.Observe("POST", "/api/v1/data", 500, time.Millisecond, "falha")
+
+	users := testsupport.NewUserRepository()
+	_, totalUsers, _ := users.List(1, 1)
+
+	service := NewOverviewService(collector, users, repository.NewInMemoryItemRepository(),
+		health.NewCheck("search", func(ctx context.Context) error { return nil }),
+		health.NewCheck("secrets", func(ctx context.Context) error { return errors.New("indisponível") }),
+	).WithJobCounter(fakeJobCounter{"running": 2, "failed": 1})
+
+	overview, err := service.Overview(context.Background())
+	assert.NoError(t, err)
+
+	assert.Equal(t, int64(2), overview.Requests.Total)
+	assert.Len(t, overview.RecentErrors, 1)
+	assert.Equal(t, totalUsers, overview.Users)
+	assert.Equal(t, 10, overview.Items)
+	assert.Equal(t, 2, overview.Jobs["running"])
+	assert.Len(t, overview.Dependencies, 2)
+	assert.Equal(t, health.StatusDown, overview.Dependencies[1].Status)
+}
@@ -0,0 +1,90 @@
+// Package avatar processa e armazena as imagens de perfil dos usuários. As
+// imagens enviadas são recortadas e redimensionadas para um quadrado de
+// tamanho fixo antes de serem gravadas
+package avatar
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"time"
+
+	"callable-api/pkg/errors"
+)
+
+// URLPrefix é o caminho público pelo qual os avatares são servidos
+const URLPrefix = "/api/v1/avatars/"
+
+// namePattern restringe os nomes aceitos ao formato gerado por Upload
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9-]+\.jpg$`)
+
+// Config define o tamanho final dos avatares e o limite de upload
+type Config struct {
+	Size           int   // lado do avatar em pixels
+	MaxUploadBytes int64 // tamanho máximo do arquivo enviado
+}
+
+// DefaultConfig retorna a configuração padrão: avatares de 256px e uploads de até 5 MB
+func DefaultConfig() Config {
+	return Config{
+		Size:           256,
+		MaxUploadBytes: 5 << 20,
+	}
+}
+
+// Service redimensiona e armazena avatares
+type Service struct {
+	store Store
+	cfg   Config
+	now   func() time.Time
+}
+
+// NewService cria um Service
+func NewService(store Store, cfg Config) *Service {
+	defaults := DefaultConfig()
+	if cfg.Size <= 0 {
+		cfg.Size = defaults.Size
+	}
+	if cfg.MaxUploadBytes <= 0 {
+		cfg.MaxUploadBytes = defaults.MaxUploadBytes
+	}
+	return &Service{store: store, cfg: cfg, now: time.Now}
+}
+
+// Config retorna a configuração do serviço
+func (s *Service) Config() Config {
+	return s.cfg
+}
+
+// Upload redimensiona a imagem e a grava, retornando a URL pública do avatar.
+// Cada upload gera um nome novo, para que caches do avatar anterior não sejam reaproveitados
+func (s *Service) Upload(ctx context.Context, userID string, r io.Reader) (string, error) {
+	upload, err := io.ReadAll(io.LimitReader(r, s.cfg.MaxUploadBytes+1))
+	if err != nil {
+		return "", errors.NewBadRequestError("Não foi possível ler a imagem enviada", err)
+	}
+	if int64(len(upload)) > s.cfg.MaxUploadBytes {
+		return "", errors.NewBadRequestError(fmt.Sprintf("A imagem excede o tamanho máximo de %d bytes", s.cfg.MaxUploadBytes), nil)
+	}
+
+	data, err := Resize(bytes.NewReader(upload), s.cfg.Size)
+	if err != nil {
+		return "", err
+	}
+
+	name := fmt.Sprintf("%s-%d.jpg", userID, s.now().UnixNano())
+	if err := s.store.Put(ctx, name, data); err != nil {
+		return "", err
+	}
+	return URLPrefix + name, nil
+}
+
+// Open retorna o avatar gravado com o nome informado
+func (s *Service) Open(ctx context.Context, name string) (*Object, error) {
+	if !namePattern.MatchString(name) {
+		return nil, errors.NewNotFoundError("Avatar não encontrado", nil)
+	}
+	return s.store.Get(ctx, name)
+}
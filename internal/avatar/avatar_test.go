@@ -0,0 +1,82 @@
+package avatar
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testPNG gera uma imagem PNG com a metade esquerda vermelha e a direita azul
+func testPNG(t *testing.T, width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := color.RGBA{R: 255, A: 255}
+			if x >= width/2 {
+				c = color.RGBA{B: 255, A: 255}
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestResize(t *testing.T) {
+	data, err := Resize(bytes.NewReader(testPNG(t, 600, 300)), 64)
+	assert.NoError(t, err)
+
+	img, err := jpeg.Decode(bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 64, 64), img.Bounds())
+
+	// O recorte central mantém as duas metades da imagem original
+	r, _, b, _ := img.At(2, 32).RGBA()
+	assert.Greater(t, r, b)
+	r, _, b, _ = img.At(61, 32).RGBA()
+	assert.Greater(t, b, r)
+
+	// Imagens menores que o avatar são ampliadas
+	data, err = Resize(bytes.NewReader(testPNG(t, 10, 20)), 64)
+	assert.NoError(t, err)
+	img, err = jpeg.Decode(bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, 64, img.Bounds().Dx())
+
+	_, err = Resize(strings.NewReader("not an image"), 64)
+	assert.Error(t, err)
+}
+
+func TestServiceUploadAndOpen(t *testing.T) {
+	store := NewMemoryStore()
+	service := NewService(store, Config{Size: 32, MaxUploadBytes: 1 << 20})
+	service.now = func() time.Time { return time.Unix(0, 42) }
+
+	url, err := service.Upload(context.Background(), "user-1", bytes.NewReader(testPNG(t, 100, 100)))
+	assert.NoError(t, err)
+	assert.Equal(t, URLPrefix+"user-1-42.jpg", url)
+
+	object, err := service.Open(context.Background(), "user-1-42.jpg")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, object.Data)
+
+	_, err = service.Open(context.Background(), "../secrets.jpg")
+	assert.Error(t, err)
+	_, err = service.Open(context.Background(), "missing.jpg")
+	assert.Error(t, err)
+
+	// Uploads acima do limite são rejeitados antes da decodificação
+	small := NewService(store, Config{Size: 32, MaxUploadBytes: 100})
+	_, err = small.Upload(context.Background(), "user-1", bytes.NewReader(testPNG(t, 100, 100)))
+	assert.Error(t, err)
+}
@@ -0,0 +1,99 @@
+package avatar
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+
+	// Formatos aceitos no upload
+	_ "image/gif"
+	_ "image/png"
+
+	"callable-api/pkg/errors"
+)
+
+// maxSourcePixels limita as dimensões da imagem enviada, evitando que imagens
+// pequenas em bytes mas enormes em pixels esgotem a memória na decodificação
+const maxSourcePixels = 25_000_000
+
+// jpegQuality é a qualidade usada ao gravar os avatares redimensionados
+const jpegQuality = 85
+
+// Resize decodifica uma imagem JPEG, PNG ou GIF, recorta o quadrado central e
+// o redimensiona para size x size pixels, retornando o resultado em JPEG
+func Resize(r io.Reader, size int) ([]byte, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, errors.NewBadRequestError("Não foi possível ler a imagem enviada", err)
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.NewBadRequestError("Formato de imagem não suportado (use JPEG, PNG ou GIF)", err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxSourcePixels {
+		return nil, errors.NewBadRequestError("Dimensões da imagem fora do limite permitido", nil)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.NewBadRequestError("Imagem corrompida ou inválida", err)
+	}
+
+	var out bytes.Buffer
+	if err := jpeg.Encode(&out, scale(centerSquare(src.Bounds()), src, size), &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return nil, errors.NewInternalServerError("Erro ao gerar avatar", err)
+	}
+	return out.Bytes(), nil
+}
+
+// centerSquare retorna o maior quadrado centralizado dentro de bounds
+func centerSquare(bounds image.Rectangle) image.Rectangle {
+	side := bounds.Dx()
+	if bounds.Dy() < side {
+		side = bounds.Dy()
+	}
+	x := bounds.Min.X + (bounds.Dx()-side)/2
+	y := bounds.Min.Y + (bounds.Dy()-side)/2
+	return image.Rect(x, y, x+side, y+side)
+}
+
+// scale redimensiona a região area de src para size x size pela média das
+// áreas de origem (box filter), o que evita serrilhado nas reduções
+func scale(area image.Rectangle, src image.Image, size int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	side := area.Dx()
+
+	for dy := 0; dy < size; dy++ {
+		y0 := area.Min.Y + dy*side/size
+		y1 := area.Min.Y + (dy+1)*side/size
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+		for dx := 0; dx < size; dx++ {
+			x0 := area.Min.X + dx*side/size
+			x1 := area.Min.X + (dx+1)*side/size
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+
+			var r, g, b, a, n uint64
+			for y := y0; y < y1; y++ {
+				for x := x0; x < x1; x++ {
+					cr, cg, cb, ca := src.At(x, y).RGBA()
+					r, g, b, a = r+uint64(cr), g+uint64(cg), b+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			dst.SetRGBA(dx, dy, color.RGBA{
+				R: uint8(r / n >> 8),
+				G: uint8(g / n >> 8),
+				B: uint8(b / n >> 8),
+				A: uint8(a / n >> 8),
+			})
+		}
+	}
+	return dst
+}
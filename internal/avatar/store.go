@@ -0,0 +1,93 @@
+package avatar
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+
+	"callable-api/pkg/cloud"
+	"callable-api/pkg/errors"
+	"callable-api/pkg/retry"
+)
+
+// signedURLTTL é a validade das URLs assinadas geradas a cada acesso ao avatar
+const signedURLTTL = 15 * time.Minute
+
+// Object é um avatar armazenado: o conteúdo, quando mantido localmente, ou a
+// URL para onde o cliente deve ser redirecionado
+type Object struct {
+	Data        []byte
+	RedirectURL string
+}
+
+// Store persiste as imagens de avatar
+type Store interface {
+	Put(ctx context.Context, name string, data []byte) error
+	Get(ctx context.Context, name string) (*Object, error)
+}
+
+// MemoryStore mantém os avatares em memória (modo demo e testes)
+type MemoryStore struct {
+	objects map[string][]byte
+	mutex   sync.RWMutex
+}
+
+// NewMemoryStore cria um MemoryStore vazio
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{objects: make(map[string][]byte)}
+}
+
+// Put implementa Store
+func (s *MemoryStore) Put(ctx context.Context, name string, data []byte) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.objects[name] = data
+	return nil
+}
+
+// Get implementa Store
+func (s *MemoryStore) Get(ctx context.Context, name string) (*Object, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	data, exists := s.objects[name]
+	if !exists {
+		return nil, errors.NewNotFoundError("Avatar não encontrado", nil)
+	}
+	return &Object{Data: data}, nil
+}
+
+// CloudStore grava os avatares no Cloud Storage em avatars/<nome>. O acesso
+// é feito por URLs assinadas de curta duração, geradas a cada requisição
+type CloudStore struct {
+	storage cloud.Storage
+}
+
+// NewCloudStore cria um CloudStore
+func NewCloudStore(cloudStorage cloud.Storage) *CloudStore {
+	return &CloudStore{storage: cloudStorage}
+}
+
+// Put implementa Store
+func (s *CloudStore) Put(ctx context.Context, name string, data []byte) error {
+	err := retry.Do(ctx, retry.DefaultPolicy(), func(ctx context.Context) error {
+		return s.storage.UploadFile(ctx, "avatars/"+name, bytes.NewReader(data))
+	})
+	if err != nil {
+		return errors.NewInternalServerError("Erro ao gravar avatar", err)
+	}
+	return nil
+}
+
+// Get implementa Store
+func (s *CloudStore) Get(ctx context.Context, name string) (*Object, error) {
+	url, err := retry.DoValue(ctx, retry.DefaultPolicy(), func(ctx context.Context) (string, error) {
+		return s.storage.GetSignedURL(ctx, "avatars/"+name, signedURLTTL)
+	})
+	if err != nil {
+		return nil, errors.NewInternalServerError("Erro ao gerar URL do avatar", err)
+	}
+	return &Object{RedirectURL: url}, nil
+}
@@ -0,0 +1,149 @@
+// Package changes mantém o log ordenado das alterações dos itens, montado a
+// partir dos eventos de domínio, para que integrações sincronizem de forma
+// incremental (GET /api/v1/data/changes?since=<cursor>) sem depender de
+// webhooks. Cada alteração recebe um número de sequência crescente, que serve
+// de cursor para a próxima leitura.
+package changes
+
+import (
+	"context"
+	stderrors "errors"
+	"sort"
+	"strconv"
+	"sync"
+
+	"callable-api/internal/correlation"
+	"callable-api/internal/events"
+	"callable-api/internal/models"
+	"callable-api/pkg/logger"
+)
+
+// ErrCursorExpired indica um cursor anterior às alterações ainda mantidas no
+// log, ou emitido por outra instância do log (ex.: antes de um reinício): o
+// cliente deve ressincronizar pela listagem completa
+var ErrCursorExpired = stderrors.New("cursor fora do log de alterações")
+
+// ErrInvalidCursor indica um cursor malformado
+var ErrInvalidCursor = stderrors.New("cursor inválido")
+
+// DefaultCapacity é o número padrão de alterações mantidas no log
+const DefaultCapacity = 10000
+
+// Config define o tamanho do log de alterações
+type Config struct {
+	// Capacity é o número de alterações mantidas; as mais antigas são
+	// descartadas e os cursores anteriores a elas expiram
+	Capacity int
+}
+
+// DefaultConfig retorna a configuração padrão
+func DefaultConfig() Config {
+	return Config{Capacity: DefaultCapacity}
+}
+
+// Feed é o log de alterações dos itens, em memória
+type Feed struct {
+	capacity int
+
+	mu      sync.RWMutex
+	entries []models.ItemChange
+	lastSeq int64
+}
+
+// NewFeed cria um log de alterações vazio
+func NewFeed(cfg Config) *Feed {
+	if cfg.Capacity <= 0 {
+		cfg.Capacity = DefaultCapacity
+	}
+	return &Feed{capacity: cfg.Capacity}
+}
+
+// HandleEvent é o consumidor do barramento de eventos (events.Subscriber)
+// que registra a criação e a alteração dos itens e, na revogação de um
+// compartilhamento, a remoção do item para quem o recebeu. O barramento
+// entrega os eventos na ordem de publicação, que é a ordem das sequências
+func (f *Feed) HandleEvent(ctx context.Context, envelope *events.Envelope) {
+	switch envelope.Type {
+	case events.TypeItemCreated, events.TypeItemUpdated, events.TypeItemUnshared:
+	default:
+		return
+	}
+
+	decoded, err := events.Decode(envelope)
+	if err != nil {
+		logger.Warn("Evento ignorado pelo log de alterações", correlation.Fields(ctx, map[string]interface{}{
+			"event":   envelope.Type,
+			"eventId": envelope.ID,
+			"error":   err.Error(),
+		}))
+		return
+	}
+
+	change := models.ItemChange{EventID: envelope.ID, OccurredAt: envelope.OccurredAt}
+	switch e := decoded.(type) {
+	case *events.ItemCreated:
+		change.Operation, change.ItemID, change.OwnerID, change.OrgID = models.ItemChangeCreated, e.ItemID, e.OwnerID, e.OrgID
+	case *events.ItemUpdated:
+		change.Operation, change.ItemID, change.OwnerID, change.OrgID = models.ItemChangeUpdated, e.ItemID, e.OwnerID, e.OrgID
+	case *events.ItemUnshared:
+		change.Operation, change.ItemID, change.OwnerID, change.OrgID = models.ItemChangeRemoved, e.ItemID, e.OwnerID, e.OrgID
+		change.ViewerID, change.ViewerRole = e.UserID, e.Role
+	}
+	f.append(change)
+}
+
+// append registra a alteração com a próxima sequência
+func (f *Feed) append(change models.ItemChange) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.lastSeq++
+	change.Seq = f.lastSeq
+	f.entries = append(f.entries, change)
+	if excess := len(f.entries) - f.capacity; excess > 0 {
+		// Copia para que o array antigo possa ser liberado
+		f.entries = append([]models.ItemChange(nil), f.entries[excess:]...)
+	}
+}
+
+// ParseCursor converte o cursor recebido do cliente; vazio equivale ao início
+// do log
+func ParseCursor(cursor string) (int64, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	seq, err := strconv.ParseInt(cursor, 10, 64)
+	if err != nil || seq < 0 {
+		return 0, ErrInvalidCursor
+	}
+	return seq, nil
+}
+
+// Since retorna até limit alterações posteriores à sequência since aceitas
+// por visible, em ordem. O cursor retornado avança também sobre as alterações
+// não visíveis, para que a próxima leitura continue de onde esta parou
+func (f *Feed) Since(since int64, limit int, visible func(change models.ItemChange) bool) (*models.ItemChangePage, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if since > f.lastSeq {
+		return nil, ErrCursorExpired
+	}
+	start := sort.Search(len(f.entries), func(i int) bool { return f.entries[i].Seq > since })
+	if len(f.entries) > 0 && f.entries[0].Seq > since+1 {
+		return nil, ErrCursorExpired
+	}
+
+	page := &models.ItemChangePage{Changes: []models.ItemChange{}}
+	next := since
+	i := start
+	for ; i < len(f.entries) && len(page.Changes) < limit; i++ {
+		next = f.entries[i].Seq
+		if visible == nil || visible(f.entries[i]) {
+			page.Changes = append(page.Changes, f.entries[i])
+		}
+	}
+	page.NextCursor = strconv.FormatInt(next, 10)
+	page.HasMore = i < len(f.entries)
+	return page, nil
+}
@@ -0,0 +1,78 @@
+package changes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"callable-api/internal/events"
+	"callable-api/internal/models"
+)
+
+func TestFeed(t *testing.T) {
+	ctx := context.Background()
+	bus := events.NewBus()
+	feed := NewFeed(Config{Capacity: 3})
+	bus.Subscribe(feed.HandleEvent)
+
+	bus.Publish(ctx, events.ItemCreated{ItemID: "1", OwnerID: "u1"})
+	bus.Publish(ctx, events.ItemShared{ItemID: "1", UserID: "u2"})
+	bus.Publish(ctx, events.ItemCreated{ItemID: "2", OwnerID: "u2"})
+	bus.Publish(ctx, events.ItemUpdated{ItemID: "1", OwnerID: "u1"})
+
+	page, err := feed.Since(0, 10, nil)
+	require.NoError(t, err)
+	require.Len(t, page.Changes, 3)
+	assert.Equal(t, int64(1), page.Changes[0].Seq)
+	assert.Equal(t, models.ItemChangeCreated, page.Changes[0].Operation)
+	assert.Equal(t, models.ItemChangeUpdated, page.Changes[2].Operation)
+	assert.Equal(t, "3", page.NextCursor)
+	assert.False(t, page.HasMore)
+
+	// O cursor avança também sobre as alterações não visíveis
+	ownedByU1 := func(change models.ItemChange) bool { return change.OwnerID == "u1" }
+	page, err = feed.Since(0, 1, ownedByU1)
+	require.NoError(t, err)
+	require.Len(t, page.Changes, 1)
+	assert.True(t, page.HasMore)
+	page, err = feed.Since(1, 1, ownedByU1)
+	require.NoError(t, err)
+	require.Len(t, page.Changes, 1)
+	assert.Equal(t, "1", page.Changes[0].ItemID)
+	assert.Equal(t, "3", page.NextCursor)
+	assert.False(t, page.HasMore)
+
+	// Com o cursor no fim, a leitura é vazia e o cursor se mantém
+	page, err = feed.Since(3, 10, nil)
+	require.NoError(t, err)
+	assert.Empty(t, page.Changes)
+	assert.Equal(t, "3", page.NextCursor)
+
+	// Acima da capacidade, as alterações mais antigas são descartadas e os
+	// cursores anteriores a elas expiram, assim como cursores futuros
+	bus.Publish(ctx, events.ItemCreated{ItemID: "3"})
+	_, err = feed.Since(0, 10, nil)
+	assert.ErrorIs(t, err, ErrCursorExpired)
+	page, err = feed.Since(1, 10, nil)
+	require.NoError(t, err)
+	assert.Len(t, page.Changes, 3)
+	_, err = feed.Since(5, 10, nil)
+	assert.ErrorIs(t, err, ErrCursorExpired)
+}
+
+func TestParseCursor(t *testing.T) {
+	seq, err := ParseCursor("")
+	require.NoError(t, err)
+	assert.Zero(t, seq)
+
+	seq, err = ParseCursor("42")
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), seq)
+
+	for _, cursor := range []string{"abc", "-1"} {
+		_, err = ParseCursor(cursor)
+		assert.ErrorIs(t, err, ErrInvalidCursor, cursor)
+	}
+}
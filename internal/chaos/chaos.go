@@ -0,0 +1,183 @@
+// Package chaos descreve falhas artificiais (latência, erros e conexões
+// derrubadas) injetadas em uma fração das requisições de cada rota, para
+// testar clientes e lógicas de retry contra falhas realistas.
+//
+// As regras são lidas de uma string no formato
+//
+//	"GET /api/v1/data=latency:25:1500ms;POST /api/v1/data=error:10:503;*=drop:1"
+//
+// onde cada regra é "<método> <rota>=<falha>:<percentual>[:<parâmetro>]". A rota
+// é o template registrado no Gin (ex.: /api/v1/data/:id) e "*" casa com todas
+// as rotas. O parâmetro é a latência (para latency) ou o status HTTP (para error).
+package chaos
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"callable-api/internal/mode"
+)
+
+// Tipos de falha suportados
+const (
+	FaultLatency = "latency"
+	FaultError   = "error"
+	FaultDrop    = "drop"
+)
+
+// Valores padrão dos parâmetros das falhas
+const (
+	defaultLatency    = time.Second
+	defaultStatusCode = 503
+	anyRoute          = "*"
+)
+
+// Rule define uma falha injetada em um percentual das requisições de uma rota
+type Rule struct {
+	Method     string
+	Route      string
+	Fault      string
+	Percentage float64
+	Latency    time.Duration
+	StatusCode int
+}
+
+// Matches indica se a regra se aplica ao método e à rota informados
+func (r Rule) Matches(method, route string) bool {
+	if r.Route == anyRoute {
+		return true
+	}
+	return strings.EqualFold(r.Method, method) && r.Route == route
+}
+
+// Config define a injeção de falhas
+type Config struct {
+	Enabled bool
+	Rules   []Rule
+}
+
+// Allowed recusa a injeção de falhas ativa no modo de execução real
+func (c Config) Allowed(m mode.Mode) error {
+	if c.Enabled && m == mode.Real {
+		return fmt.Errorf("injeção de falhas (CHAOS_ENABLED) não é permitida no modo real")
+	}
+	return nil
+}
+
+// ParseRules interpreta a lista de regras no formato descrito no pacote
+func ParseRules(spec string) ([]Rule, error) {
+	var rules []Rule
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		rule, err := parseRule(entry)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// parseRule interpreta uma única regra
+func parseRule(entry string) (Rule, error) {
+	target, spec, found := strings.Cut(entry, "=")
+	if !found {
+		return Rule{}, fmt.Errorf("regra de chaos inválida %q: falta '='", entry)
+	}
+
+	var rule Rule
+	target = strings.TrimSpace(target)
+	if target == anyRoute {
+		rule.Route = anyRoute
+	} else {
+		method, route, found := strings.Cut(target, " ")
+		if !found || strings.TrimSpace(route) == "" {
+			return Rule{}, fmt.Errorf("regra de chaos inválida %q: use \"<método> <rota>\" ou \"*\"", entry)
+		}
+		rule.Method = strings.ToUpper(method)
+		rule.Route = strings.TrimSpace(route)
+	}
+
+	parts := strings.Split(strings.TrimSpace(spec), ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return Rule{}, fmt.Errorf("regra de chaos inválida %q: use <falha>:<percentual>[:<parâmetro>]", entry)
+	}
+
+	rule.Fault = strings.ToLower(parts[0])
+	percentage, err := strconv.ParseFloat(parts[1], 64)
+	if err != nil || percentage < 0 || percentage > 100 {
+		return Rule{}, fmt.Errorf("regra de chaos inválida %q: percentual deve estar entre 0 e 100", entry)
+	}
+	rule.Percentage = percentage
+
+	param := ""
+	if len(parts) == 3 {
+		param = parts[2]
+	}
+
+	switch rule.Fault {
+	case FaultLatency:
+		rule.Latency = defaultLatency
+		if param != "" {
+			if rule.Latency, err = time.ParseDuration(param); err != nil || rule.Latency < 0 {
+				return Rule{}, fmt.Errorf("regra de chaos inválida %q: latência inválida", entry)
+			}
+		}
+	case FaultError:
+		rule.StatusCode = defaultStatusCode
+		if param != "" {
+			if rule.StatusCode, err = strconv.Atoi(param); err != nil || rule.StatusCode < 400 || rule.StatusCode > 599 {
+				return Rule{}, fmt.Errorf("regra de chaos inválida %q: status deve ser 4xx ou 5xx", entry)
+			}
+		}
+	case FaultDrop:
+	default:
+		return Rule{}, fmt.Errorf("regra de chaos inválida %q: falha deve ser latency, error ou drop", entry)
+	}
+
+	return rule, nil
+}
+
+// Injector sorteia, para cada requisição, qual falha deve ser injetada
+type Injector struct {
+	rules  []Rule
+	mutex  sync.Mutex
+	random func() float64
+}
+
+// NewInjector cria um Injector com as regras informadas
+func NewInjector(rules []Rule) *Injector {
+	return &Injector{
+		rules:  rules,
+		random: rand.New(rand.NewSource(time.Now().UnixNano())).Float64,
+	}
+}
+
+// Pick retorna a primeira regra da rota sorteada para a requisição, ou nil se
+// nenhuma falha deve ser injetada
+func (i *Injector) Pick(method, route string) *Rule {
+	for idx := range i.rules {
+		rule := &i.rules[idx]
+		if !rule.Matches(method, route) {
+			continue
+		}
+		if i.roll() < rule.Percentage {
+			return rule
+		}
+	}
+	return nil
+}
+
+// roll sorteia um valor em [0, 100)
+func (i *Injector) roll() float64 {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	return i.random() * 100
+}
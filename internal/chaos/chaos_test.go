@@ -0,0 +1,64 @@
+package chaos
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"callable-api/internal/mode"
+)
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules("GET /api/v1/data=latency:25:1500ms; post /api/v1/data=error:10:502;*=drop:1")
+	assert.NoError(t, err)
+	assert.Len(t, rules, 3)
+
+	assert.Equal(t, Rule{Method: "GET", Route: "/api/v1/data", Fault: FaultLatency, Percentage: 25, Latency: 1500 * time.Millisecond}, rules[0])
+	assert.Equal(t, Rule{Method: "POST", Route: "/api/v1/data", Fault: FaultError, Percentage: 10, StatusCode: 502}, rules[1])
+	assert.Equal(t, Rule{Route: "*", Fault: FaultDrop, Percentage: 1}, rules[2])
+
+	// Parâmetros omitidos usam os valores padrão
+	rules, err = ParseRules("*=error:5")
+	assert.NoError(t, err)
+	assert.Equal(t, 503, rules[0].StatusCode)
+
+	for _, spec := range []string{
+		"GET /api/v1/data",
+		"/api/v1/data=error:10",
+		"*=timeout:10",
+		"*=error:150",
+		"*=error:10:200",
+		"*=latency:10:abc",
+	} {
+		_, err := ParseRules(spec)
+		assert.Error(t, err, spec)
+	}
+
+	// A injeção ativa é recusada no modo real
+	cfg := Config{Enabled: true, Rules: rules}
+	assert.NoError(t, cfg.Allowed(mode.Demo))
+	assert.Error(t, cfg.Allowed(mode.Real))
+	assert.NoError(t, Config{}.Allowed(mode.Real))
+}
+
+func TestInjector_Pick(t *testing.T) {
+	rules, _ := ParseRules("GET /api/v1/data/:id=error:50;*=latency:10:1ms")
+	injector := NewInjector(rules)
+
+	// Sorteio abaixo do percentual da primeira regra
+	injector.random = func() float64 { return 0.3 }
+	rule := injector.Pick("GET", "/api/v1/data/:id")
+	assert.NotNil(t, rule)
+	assert.Equal(t, FaultError, rule.Fault)
+
+	// Sorteio acima de todos os percentuais
+	injector.random = func() float64 { return 0.9 }
+	assert.Nil(t, injector.Pick("GET", "/api/v1/data/:id"))
+
+	// Rotas sem regra específica só são afetadas pela regra "*"
+	injector.random = func() float64 { return 0.05 }
+	rule = injector.Pick("POST", "/api/v1/data")
+	assert.NotNil(t, rule)
+	assert.Equal(t, FaultLatency, rule.Fault)
+}
@@ -0,0 +1,59 @@
+// Package clock abstrai a leitura da hora atual, para que a lógica que
+// depende do tempo (expiração de tokens e sessões, agendamento de jobs) possa
+// ser testada com uma hora controlada.
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock fornece a hora atual
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock lê a hora do sistema
+type systemClock struct{}
+
+// Now implementa Clock
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// System retorna o relógio do sistema, usado por padrão pelos serviços
+func System() Clock {
+	return systemClock{}
+}
+
+// Fake é um relógio parado numa hora definida pelo teste
+type Fake struct {
+	mutex sync.Mutex
+	now   time.Time
+}
+
+// NewFake cria um relógio parado na hora informada
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+// Now implementa Clock
+func (f *Fake) Now() time.Time {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.now
+}
+
+// Set muda a hora do relógio
+func (f *Fake) Set(now time.Time) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.now = now
+}
+
+// Advance adianta o relógio pelo intervalo informado
+func (f *Fake) Advance(d time.Duration) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.now = f.now.Add(d)
+}
@@ -0,0 +1,54 @@
+// Package correlation propaga pelo context.Context os identificadores da
+// requisição HTTP ou do job em execução, para que os logs das camadas
+// internas possam ser relacionados à operação que os originou.
+package correlation
+
+import (
+	"context"
+
+	"callable-api/internal/redact"
+)
+
+type contextKey int
+
+const (
+	requestIDKey contextKey = iota
+	jobIDKey
+)
+
+// WithRequestID retorna um contexto com o ID da requisição
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+// RequestID retorna o ID da requisição do contexto ("" se não houver)
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// WithJobID retorna um contexto com o ID do job
+func WithJobID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, jobIDKey, id)
+}
+
+// JobID retorna o ID do job do contexto ("" se não houver)
+func JobID(ctx context.Context) string {
+	id, _ := ctx.Value(jobIDKey).(string)
+	return id
+}
+
+// Fields adiciona aos campos de log os identificadores presentes no contexto e
+// remove dos valores os campos marcados com redact:"true" (ver redact.Value)
+func Fields(ctx context.Context, fields map[string]interface{}) map[string]interface{} {
+	if fields == nil {
+		fields = make(map[string]interface{})
+	}
+	if id := RequestID(ctx); id != "" {
+		fields["requestId"] = id
+	}
+	if id := JobID(ctx); id != "" {
+		fields["jobId"] = id
+	}
+	return redact.Fields(fields)
+}
@@ -0,0 +1,31 @@
+package correlation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTraceParent(t *testing.T) {
+	parent := NewTraceParent()
+	assert.True(t, ValidTraceParent(parent))
+
+	for _, invalid := range []string{
+		"",
+		"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+	} {
+		assert.False(t, ValidTraceParent(invalid), invalid)
+	}
+
+	// Sem trace no contexto, as chamadas não propagam nada
+	assert.Empty(t, ChildTraceParent(context.Background()))
+
+	child := ChildTraceParent(WithTraceParent(context.Background(), parent))
+	assert.True(t, ValidTraceParent(child))
+	assert.Equal(t, parent[:35], child[:35])
+	assert.NotEqual(t, parent, child)
+}
@@ -0,0 +1,71 @@
+package correlation
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+)
+
+// TraceParentHeader é o header W3C Trace Context que propaga o trace entre serviços
+const TraceParentHeader = "traceparent"
+
+type traceKey struct{}
+
+// WithTraceParent retorna um contexto com o traceparent da operação
+func WithTraceParent(ctx context.Context, traceParent string) context.Context {
+	return context.WithValue(ctx, traceKey{}, traceParent)
+}
+
+// TraceParent retorna o traceparent do contexto ("" se não houver)
+func TraceParent(ctx context.Context) string {
+	traceParent, _ := ctx.Value(traceKey{}).(string)
+	return traceParent
+}
+
+// NewTraceParent inicia um trace, com IDs de trace e de span aleatórios
+func NewTraceParent() string {
+	return "00-" + randomHex(16) + "-" + randomHex(8) + "-01"
+}
+
+// ChildTraceParent retorna o traceparent das chamadas feitas pela operação:
+// o mesmo trace, com um novo span. Retorna "" se o contexto não tiver trace
+func ChildTraceParent(ctx context.Context) string {
+	parts := strings.Split(TraceParent(ctx), "-")
+	if len(parts) != 4 {
+		return ""
+	}
+	return parts[0] + "-" + parts[1] + "-" + randomHex(8) + "-" + parts[3]
+}
+
+// ValidTraceParent verifica o formato do traceparent na versão 00
+// (00-<trace-id>-<parent-id>-<flags>, com IDs diferentes de zero)
+func ValidTraceParent(value string) bool {
+	parts := strings.Split(value, "-")
+	if len(parts) != 4 || parts[0] != "00" {
+		return false
+	}
+	for i, size := range []int{2, 32, 16, 2} {
+		if len(parts[i]) != size || !isLowerHex(parts[i]) {
+			return false
+		}
+	}
+	return strings.Trim(parts[1], "0") != "" && strings.Trim(parts[2], "0") != ""
+}
+
+// isLowerHex indica se s contém apenas dígitos hexadecimais minúsculos
+func isLowerHex(s string) bool {
+	for _, r := range s {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// randomHex gera n bytes aleatórios em hexadecimal
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,252 @@
+// Package datakeys implementa a cifragem em envelope dos dados dos usuários:
+// cada usuário tem a sua chave de dados (DEK), guardada apenas embrulhada
+// pela chave mestra (ou por um KMS, ver KeyWrapper). Os valores cifrados
+// levam o prefixo "enc:v1:" e ficam vinculados ao dono; apagar a chave do
+// usuário torna os seus dados ilegíveis (crypto-shredding).
+package datakeys
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	stderrors "errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// prefix marca os valores cifrados
+const prefix = "enc:v1:"
+
+// keySize é o tamanho das chaves de dados e da chave mestra (AES-256)
+const keySize = 32
+
+// ErrKeyNotFound indica que o usuário não tem chave de dados: ela nunca foi
+// criada ou foi apagada junto com a conta
+var ErrKeyNotFound = stderrors.New("chave de dados do usuário não encontrada")
+
+// KeyWrapper embrulha e desembrulha as chaves de dados (chave mestra ou KMS)
+type KeyWrapper interface {
+	Wrap(ctx context.Context, key []byte) ([]byte, error)
+	Unwrap(ctx context.Context, wrapped []byte) ([]byte, error)
+}
+
+// MasterKey embrulha as chaves de dados com uma chave mestra local (AES-GCM)
+type MasterKey struct {
+	aead cipher.AEAD
+}
+
+// Verificação em tempo de compilação
+var _ KeyWrapper = (*MasterKey)(nil)
+
+// ParseMasterKey cria a chave mestra a partir de 32 bytes em base64
+func ParseMasterKey(encoded string) (*MasterKey, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("chave mestra inválida: %w", err)
+	}
+	if len(key) != keySize {
+		return nil, fmt.Errorf("chave mestra deve ter %d bytes, tem %d", keySize, len(key))
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	return &MasterKey{aead: aead}, nil
+}
+
+// Wrap implementa KeyWrapper
+func (m *MasterKey) Wrap(_ context.Context, key []byte) ([]byte, error) {
+	return seal(m.aead, key, nil)
+}
+
+// Unwrap implementa KeyWrapper
+func (m *MasterKey) Unwrap(_ context.Context, wrapped []byte) ([]byte, error) {
+	return open(m.aead, wrapped, nil)
+}
+
+// Store guarda as chaves de dados embrulhadas, por usuário
+type Store interface {
+	// Get retorna a chave embrulhada do usuário ou ErrKeyNotFound
+	Get(ctx context.Context, userID string) ([]byte, error)
+	Put(ctx context.Context, userID string, wrapped []byte) error
+	Delete(ctx context.Context, userID string) error
+}
+
+// MemoryStore implementa Store em memória
+type MemoryStore struct {
+	mu   sync.RWMutex
+	keys map[string][]byte
+}
+
+// NewMemoryStore cria um MemoryStore vazio
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{keys: make(map[string][]byte)}
+}
+
+// Get implementa Store
+func (s *MemoryStore) Get(_ context.Context, userID string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	wrapped, ok := s.keys[userID]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	return wrapped, nil
+}
+
+// Put implementa Store
+func (s *MemoryStore) Put(_ context.Context, userID string, wrapped []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys[userID] = wrapped
+	return nil
+}
+
+// Delete implementa Store
+func (s *MemoryStore) Delete(_ context.Context, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.keys, userID)
+	return nil
+}
+
+// Keyring cifra e decifra os valores com a chave de dados de cada usuário,
+// criada no primeiro uso. As chaves desembrulhadas ficam em memória para
+// evitar uma chamada ao KMS a cada leitura
+type Keyring struct {
+	wrapper KeyWrapper
+	store   Store
+
+	mu   sync.Mutex
+	keys map[string]cipher.AEAD
+}
+
+// NewKeyring cria um Keyring
+func NewKeyring(wrapper KeyWrapper, store Store) *Keyring {
+	return &Keyring{wrapper: wrapper, store: store, keys: make(map[string]cipher.AEAD)}
+}
+
+// Encrypted indica se o valor foi cifrado por um Keyring
+func Encrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+// Encrypt cifra o valor com a chave do usuário. Valores vazios não são cifrados
+func (k *Keyring) Encrypt(ctx context.Context, userID, value string) (string, error) {
+	if value == "" || Encrypted(value) {
+		return value, nil
+	}
+	aead, err := k.key(ctx, userID, true)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := seal(aead, []byte(value), []byte(userID))
+	if err != nil {
+		return "", err
+	}
+	return prefix + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decifra o valor com a chave do usuário. Valores não cifrados
+// (gravados antes da cifragem) são retornados como estão; sem a chave do
+// usuário retorna ErrKeyNotFound
+func (k *Keyring) Decrypt(ctx context.Context, userID, value string) (string, error) {
+	if !Encrypted(value) {
+		return value, nil
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(value, prefix))
+	if err != nil {
+		return "", fmt.Errorf("valor cifrado malformado: %w", err)
+	}
+	aead, err := k.key(ctx, userID, false)
+	if err != nil {
+		return "", err
+	}
+	plain, err := open(aead, sealed, []byte(userID))
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
+
+// DeleteKey apaga a chave do usuário: os valores cifrados com ela não podem
+// mais ser lidos
+func (k *Keyring) DeleteKey(ctx context.Context, userID string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	delete(k.keys, userID)
+	return k.store.Delete(ctx, userID)
+}
+
+// key retorna a chave do usuário, criando-a se create for true
+func (k *Keyring) key(ctx context.Context, userID string, create bool) (cipher.AEAD, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if aead, ok := k.keys[userID]; ok {
+		return aead, nil
+	}
+
+	var key []byte
+	wrapped, err := k.store.Get(ctx, userID)
+	switch {
+	case err == nil:
+		if key, err = k.wrapper.Unwrap(ctx, wrapped); err != nil {
+			return nil, fmt.Errorf("desembrulhar chave de dados: %w", err)
+		}
+	case stderrors.Is(err, ErrKeyNotFound) && create:
+		key = make([]byte, keySize)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("gerar chave de dados: %w", err)
+		}
+		if wrapped, err = k.wrapper.Wrap(ctx, key); err != nil {
+			return nil, fmt.Errorf("embrulhar chave de dados: %w", err)
+		}
+		if err := k.store.Put(ctx, userID, wrapped); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	k.keys[userID] = aead
+	return aead, nil
+}
+
+// newAEAD cria o AES-GCM da chave
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal cifra os dados, retornando o nonce seguido do texto cifrado
+func seal(aead cipher.AEAD, data, additional []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("gerar nonce: %w", err)
+	}
+	return aead.Seal(nonce, nonce, data, additional), nil
+}
+
+// open decifra os dados gerados por seal
+func open(aead cipher.AEAD, sealed, additional []byte) ([]byte, error) {
+	size := aead.NonceSize()
+	if len(sealed) < size {
+		return nil, stderrors.New("valor cifrado corrompido")
+	}
+	plain, err := aead.Open(nil, sealed[:size], sealed[size:], additional)
+	if err != nil {
+		return nil, fmt.Errorf("decifrar valor: %w", err)
+	}
+	return plain, nil
+}
@@ -0,0 +1,64 @@
+package datakeys
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyring(t *testing.T) {
+	ctx := context.Background()
+	master, err := ParseMasterKey(base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", 32))))
+	require.NoError(t, err)
+	store := NewMemoryStore()
+	keys := NewKeyring(master, store)
+
+	sealed, err := keys.Encrypt(ctx, "ana", "ana@example.com")
+	require.NoError(t, err)
+	assert.True(t, Encrypted(sealed))
+	assert.NotContains(t, sealed, "ana@example.com")
+
+	// A chave fica guardada apenas embrulhada
+	wrapped, err := store.Get(ctx, "ana")
+	require.NoError(t, err)
+	assert.Len(t, wrapped, 12+32+16)
+
+	plain, err := keys.Decrypt(ctx, "ana", sealed)
+	require.NoError(t, err)
+	assert.Equal(t, "ana@example.com", plain)
+
+	// Um novo keyring sobre o mesmo store lê os valores já gravados
+	plain, err = NewKeyring(master, store).Decrypt(ctx, "ana", sealed)
+	require.NoError(t, err)
+	assert.Equal(t, "ana@example.com", plain)
+
+	// O valor fica vinculado ao dono
+	_, err = keys.Encrypt(ctx, "bruno", "x")
+	require.NoError(t, err)
+	_, err = keys.Decrypt(ctx, "bruno", sealed)
+	assert.Error(t, err)
+
+	// Valores vazios e gravados antes da cifragem não mudam
+	empty, err := keys.Encrypt(ctx, "ana", "")
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+	plain, err = keys.Decrypt(ctx, "ana", "texto")
+	require.NoError(t, err)
+	assert.Equal(t, "texto", plain)
+
+	// Sem a chave, os valores não podem mais ser lidos
+	require.NoError(t, keys.DeleteKey(ctx, "ana"))
+	_, err = keys.Decrypt(ctx, "ana", sealed)
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+}
+
+func TestParseMasterKey(t *testing.T) {
+	_, err := ParseMasterKey("não é base64")
+	assert.Error(t, err)
+	_, err = ParseMasterKey(base64.StdEncoding.EncodeToString([]byte("curta")))
+	assert.Error(t, err)
+}
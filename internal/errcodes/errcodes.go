@@ -0,0 +1,259 @@
+// Package errcodes mantém o catálogo de códigos de erro estáveis da API. Cada
+// resposta de erro inclui um campo "code" legível por máquina, que os SDKs
+// podem usar com segurança: os códigos do catálogo nunca mudam de significado.
+package errcodes
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"sort"
+
+	"callable-api/pkg/cloud"
+	"callable-api/pkg/errors"
+)
+
+// Códigos genéricos, usados quando o handler não indica um código específico
+const (
+	BadRequest       = "BAD_REQUEST"
+	InvalidInput     = "INVALID_INPUT"
+	ValidationFailed = "VALIDATION_FAILED"
+	Unauthorized     = "UNAUTHORIZED"
+	Forbidden        = "FORBIDDEN"
+	NotFound         = "NOT_FOUND"
+	RouteNotFound    = "ROUTE_NOT_FOUND"
+	MethodNotAllowed = "METHOD_NOT_ALLOWED"
+	Conflict         = "CONFLICT"
+	RequestTimeout   = "REQUEST_TIMEOUT"
+	PayloadTooLarge  = "PAYLOAD_TOO_LARGE"
+	QuotaExceeded    = "QUOTA_EXCEEDED"
+	InternalError    = "INTERNAL_ERROR"
+	InjectedFault    = "INJECTED_FAULT"
+	ServerDraining   = "SERVER_DRAINING"
+	DependencyDown   = "DEPENDENCY_DOWN"
+)
+
+// Códigos específicos de domínio
+const (
+	ItemNotFound          = "ITEM_NOT_FOUND"
+	UserNotFound          = "USER_NOT_FOUND"
+	EmailInUse            = "EMAIL_IN_USE"
+	InvalidCredentials    = "INVALID_CREDENTIALS"
+	TokenInvalid          = "TOKEN_INVALID"
+	RecordingNotFound     = "RECORDING_NOT_FOUND"
+	SearchQueryRequired   = "SEARCH_QUERY_REQUIRED"
+	InvalidFilter         = "INVALID_FILTER"
+	InvalidImage          = "INVALID_IMAGE"
+	AvatarNotFound        = "AVATAR_NOT_FOUND"
+	AccountDisabled       = "ACCOUNT_DISABLED"
+	PasswordResetRequired = "PASSWORD_RESET_REQUIRED"
+	ResetTokenInvalid     = "RESET_TOKEN_INVALID"
+	DeviceMismatch        = "DEVICE_MISMATCH"
+	CommentNotFound       = "COMMENT_NOT_FOUND"
+	NotificationNotFound  = "NOTIFICATION_NOT_FOUND"
+	DuplicateItem         = "DUPLICATE_ITEM"
+	JobNotFound           = "JOB_NOT_FOUND"
+	JobQueueFull          = "JOB_QUEUE_FULL"
+	JobQuotaExceeded      = "JOB_QUOTA_EXCEEDED"
+	JobTypeDisabled       = "JOB_TYPE_DISABLED"
+	JobTimeout            = "JOB_TIMEOUT"
+	InvalidRunAt          = "INVALID_RUN_AT"
+	JobArtifactNotFound   = "JOB_ARTIFACT_NOT_FOUND"
+	WebhookNotFound       = "WEBHOOK_NOT_FOUND"
+	WebhookEventNotFound  = "WEBHOOK_EVENT_NOT_FOUND"
+	InvalidChangeCursor   = "INVALID_CHANGE_CURSOR"
+	ChangeCursorExpired   = "CHANGE_CURSOR_EXPIRED"
+	InvalidSecretEvent    = "INVALID_SECRET_EVENT"
+	InvalidUserImport     = "INVALID_USER_IMPORT"
+	OrgNotFound           = "ORG_NOT_FOUND"
+	OrgMemberNotFound     = "ORG_MEMBER_NOT_FOUND"
+	OrgAccessDenied       = "ORG_ACCESS_DENIED"
+	LastOrgOwner          = "LAST_ORG_OWNER"
+	InvitationInvalid     = "INVITATION_INVALID"
+	RegistrationClosed    = "REGISTRATION_CLOSED"
+	ConsentRequired       = "CONSENT_REQUIRED"
+	InsufficientScope     = "INSUFFICIENT_SCOPE"
+	BootstrapCompleted    = "BOOTSTRAP_COMPLETED"
+	BootstrapTokenInvalid = "BOOTSTRAP_TOKEN_INVALID"
+)
+
+// Tipos de AppError definidos em pkg/errors
+const (
+	TypeBadRequest   = "BAD_REQUEST"
+	TypeUnauthorized = "UNAUTHORIZED"
+	TypeForbidden    = "FORBIDDEN"
+	TypeNotFound     = "NOT_FOUND"
+	TypeConflict     = "CONFLICT"
+	TypeInternal     = "INTERNAL_SERVER"
+)
+
+// Definition documenta um código de erro
+type Definition struct {
+	Code        string `json:"code" example:"ITEM_NOT_FOUND"`
+	Status      int    `json:"status" example:"404"`
+	Description string `json:"description" example:"O item solicitado não existe"`
+}
+
+// catalog contém todos os códigos que a API pode retornar
+var catalog = map[string]Definition{}
+
+func init() {
+	for _, def := range []Definition{
+		{BadRequest, http.StatusBadRequest, "Requisição inválida"},
+		{InvalidInput, http.StatusBadRequest, "Corpo da requisição malformado ou com campos inválidos (ver violations)"},
+		{ValidationFailed, http.StatusBadRequest, "Os dados informados não passaram na validação (ver field_errors)"},
+		{Unauthorized, http.StatusUnauthorized, "Autenticação ausente ou inválida"},
+		{Forbidden, http.StatusForbidden, "O usuário não tem permissão para o recurso"},
+		{NotFound, http.StatusNotFound, "Recurso não encontrado"},
+		{RouteNotFound, http.StatusNotFound, "Nenhuma rota corresponde ao caminho da requisição"},
+		{MethodNotAllowed, http.StatusMethodNotAllowed, "O caminho existe, mas não aceita o método (ver allowed_methods e o header Allow)"},
+		{Conflict, http.StatusConflict, "Conflito com o estado atual do recurso"},
+		{RequestTimeout, http.StatusRequestTimeout, "A requisição excedeu o prazo de processamento"},
+		{PayloadTooLarge, http.StatusRequestEntityTooLarge, "O corpo excede o tamanho ou o número de elementos aceito pela rota"},
+		{QuotaExceeded, http.StatusTooManyRequests, "Cota de requisições da janela atual esgotada"},
+		{InternalError, http.StatusInternalServerError, "Erro interno do servidor"},
+		{InjectedFault, http.StatusServiceUnavailable, "Falha injetada pelo modo de caos (apenas fora de produção)"},
+		{ServerDraining, http.StatusServiceUnavailable, "A instância está em encerramento; reconecte após Retry-After"},
+		{DependencyDown, http.StatusServiceUnavailable, "Uma integração externa (GCP) usada pela funcionalidade está indisponível; o restante da API segue atendendo. Tente novamente após Retry-After"},
+		{ItemNotFound, http.StatusNotFound, "O item solicitado não existe"},
+		{UserNotFound, http.StatusNotFound, "O usuário não existe"},
+		{EmailInUse, http.StatusConflict, "Já existe um usuário com este email"},
+		{InvalidCredentials, http.StatusUnauthorized, "Email ou senha incorretos"},
+		{TokenInvalid, http.StatusUnauthorized, "Token inválido ou expirado"},
+		{RecordingNotFound, http.StatusNotFound, "A gravação solicitada não existe"},
+		{SearchQueryRequired, http.StatusBadRequest, "O parâmetro de busca q é obrigatório"},
+		{InvalidFilter, http.StatusBadRequest, "Filtro com operador desconhecido ou valor incompatível com o campo (ex.: value[lt]=10)"},
+		{InvalidImage, http.StatusBadRequest, "A imagem enviada é inválida, grande demais ou de formato não suportado"},
+		{AvatarNotFound, http.StatusNotFound, "O avatar solicitado não existe"},
+		{AccountDisabled, http.StatusForbidden, "A conta foi desativada por um administrador"},
+		{PasswordResetRequired, http.StatusForbidden, "A conta exige redefinição de senha pelo link enviado por email"},
+		{ResetTokenInvalid, http.StatusBadRequest, "Token de redefinição de senha inválido ou expirado"},
+		{DeviceMismatch, http.StatusUnauthorized, "O token de atualização foi emitido para outro dispositivo"},
+		{CommentNotFound, http.StatusNotFound, "O comentário solicitado não existe"},
+		{NotificationNotFound, http.StatusNotFound, "A notificação não existe ou pertence a outro usuário"},
+		{DuplicateItem, http.StatusConflict, "Já existe um item equivalente (ver resource); administradores podem forçar com force=true"},
+		{JobNotFound, http.StatusNotFound, "O job solicitado não existe"},
+		{JobQueueFull, http.StatusServiceUnavailable, "A fila de jobs está cheia; tente novamente após Retry-After"},
+		{JobQuotaExceeded, http.StatusTooManyRequests, "O usuário atingiu o limite de jobs aguardando execução"},
+		{JobTypeDisabled, http.StatusServiceUnavailable, "O tipo de job foi desativado temporariamente após panics repetidos; tente novamente após Retry-After"},
+		{JobTimeout, http.StatusGatewayTimeout, "O job excedeu o prazo de execução e foi interrompido (estado timed_out)"},
+		{InvalidRunAt, http.StatusBadRequest, "run_at (RFC 3339) ou delay inválido, ou além do agendamento máximo"},
+		{JobArtifactNotFound, http.StatusNotFound, "O job não existe ou não gerou um artefato para download"},
+		{WebhookNotFound, http.StatusNotFound, "O webhook não existe ou pertence a outro usuário"},
+		{WebhookEventNotFound, http.StatusNotFound, "O evento não foi entregue ao webhook ou já saiu da janela de retenção"},
+		{InvalidChangeCursor, http.StatusBadRequest, "O cursor since não é um next_cursor do log de alterações"},
+		{ChangeCursorExpired, http.StatusGone, "O cursor é anterior às alterações mantidas no log; ressincronize pela listagem completa"},
+		{InvalidSecretEvent, http.StatusBadRequest, "A notificação do Secret Manager não informa o segredo (atributo secretId)"},
+		{InvalidUserImport, http.StatusBadRequest, "O CSV de importação de usuários é malformado ou não tem as colunas email e name"},
+		{OrgNotFound, http.StatusNotFound, "A organização não existe ou o usuário não é membro dela"},
+		{OrgMemberNotFound, http.StatusNotFound, "O usuário não é membro da organização"},
+		{OrgAccessDenied, http.StatusForbidden, "O usuário não é membro da organização informada em X-Organization-ID ou no claim org_id"},
+		{LastOrgOwner, http.StatusConflict, "A organização precisa de ao menos um dono; promova outro membro antes"},
+		{InvitationInvalid, http.StatusBadRequest, "Convite inexistente, expirado ou já aceito"},
+		{RegistrationClosed, http.StatusForbidden, "O cadastro aberto está desativado; o registro exige um invitation_code válido para o email"},
+		{ConsentRequired, http.StatusForbidden, "O usuário não aceitou a versão obrigatória dos termos de uso ou da política de privacidade; aceite em POST /api/v1/auth/consent"},
+		{InsufficientScope, http.StatusForbidden, "O token restrito não concede o escopo da rota (<recurso>:read ou <recurso>:write); peça um token com o escopo em POST /api/v1/auth/token/narrow"},
+		{BootstrapCompleted, http.StatusConflict, "O primeiro administrador já foi criado; a configuração inicial não está mais disponível"},
+		{BootstrapTokenInvalid, http.StatusForbidden, "O token de configuração não confere com o exibido no log da primeira execução"},
+	} {
+		Register(def)
+	}
+}
+
+// Register adiciona um código ao catálogo. Registrar o mesmo código duas vezes
+// é um erro de programação
+func Register(def Definition) {
+	if _, exists := catalog[def.Code]; exists {
+		panic("errcodes: código duplicado " + def.Code)
+	}
+	catalog[def.Code] = def
+}
+
+// Lookup retorna a definição de um código
+func Lookup(code string) (Definition, bool) {
+	def, ok := catalog[code]
+	return def, ok
+}
+
+// All retorna o catálogo ordenado por código
+func All() []Definition {
+	defs := make([]Definition, 0, len(catalog))
+	for _, def := range catalog {
+		defs = append(defs, def)
+	}
+	sort.Slice(defs, func(i, j int) bool {
+		return defs[i].Code < defs[j].Code
+	})
+	return defs
+}
+
+// Override troca o código genérico de um erro por um código específico. O
+// erro é reconhecido pelo tipo do AppError ou pela causa encapsulada
+type Override struct {
+	Type  string
+	Cause error
+	Code  string
+}
+
+// When cria um Override: erros do tipo errType passam a usar o código code
+func When(errType, code string) Override {
+	return Override{Type: errType, Code: code}
+}
+
+// WhenCause cria um Override: erros causados por cause (inclusive quando
+// encapsulados em um AppError) passam a usar o código code
+func WhenCause(cause error, code string) Override {
+	return Override{Cause: cause, Code: code}
+}
+
+// FromError determina o código de um erro, aplicando os overrides informados
+func FromError(err error, overrides ...Override) string {
+	if stderrors.Is(err, context.DeadlineExceeded) {
+		return RequestTimeout
+	}
+	if stderrors.Is(err, cloud.ErrUnavailable) {
+		return DependencyDown
+	}
+
+	var appErr *errors.AppError
+	isAppErr := stderrors.As(err, &appErr)
+
+	for _, override := range overrides {
+		if override.Cause == nil {
+			continue
+		}
+		if stderrors.Is(err, override.Cause) || (isAppErr && appErr.Err != nil && stderrors.Is(appErr.Err, override.Cause)) {
+			return override.Code
+		}
+	}
+
+	var validationErr *errors.ValidationError
+	if stderrors.As(err, &validationErr) {
+		return ValidationFailed
+	}
+
+	if !isAppErr {
+		return InternalError
+	}
+
+	for _, override := range overrides {
+		if override.Type != "" && override.Type == appErr.Type {
+			return override.Code
+		}
+	}
+
+	switch appErr.Type {
+	case TypeBadRequest:
+		return BadRequest
+	case TypeUnauthorized:
+		return Unauthorized
+	case TypeForbidden:
+		return Forbidden
+	case TypeNotFound:
+		return NotFound
+	case TypeConflict:
+		return Conflict
+	default:
+		return InternalError
+	}
+}
@@ -0,0 +1,77 @@
+package errcodes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"callable-api/internal/models"
+	"callable-api/pkg/cloud"
+	"callable-api/pkg/errors"
+)
+
+func TestFromError(t *testing.T) {
+	notFound := errors.NewNotFoundError("Item não encontrado", nil)
+
+	assert.Equal(t, NotFound, FromError(notFound))
+	assert.Equal(t, ItemNotFound, FromError(notFound, When(TypeNotFound, ItemNotFound)))
+	assert.Equal(t, NotFound, FromError(notFound, When(TypeConflict, EmailInUse)))
+	assert.Equal(t, Conflict, FromError(errors.NewConflictError("duplicado", nil)))
+	assert.Equal(t, ValidationFailed, FromError(errors.NewValidationError("inválido")))
+	assert.Equal(t, RequestTimeout, FromError(fmt.Errorf("busca: %w", context.DeadlineExceeded)))
+	assert.Equal(t, DependencyDown, FromError(errors.NewInternalServerError("Erro ao gravar avatar",
+		&cloud.UnavailableError{Service: cloud.ServiceStorage, Err: fmt.Errorf("timeout")})))
+	assert.Equal(t, InternalError, FromError(fmt.Errorf("falha inesperada")))
+}
+
+func TestCatalog(t *testing.T) {
+	def, ok := Lookup(EmailInUse)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusConflict, def.Status)
+
+	_, ok = Lookup("UNKNOWN_CODE")
+	assert.False(t, ok)
+
+	all := All()
+	for i := 1; i < len(all); i++ {
+		assert.Less(t, all[i-1].Code, all[i].Code)
+	}
+
+	assert.Panics(t, func() { Register(Definition{Code: ItemNotFound}) })
+}
+
+func TestRespond(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	respond := func(err error, overrides ...Override) (int, models.APIError) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		Respond(c, err, overrides...)
+		var body models.APIError
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		return w.Code, body
+	}
+
+	status, body := respond(errors.NewNotFoundError("Item não encontrado", nil), When(TypeNotFound, ItemNotFound))
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Equal(t, models.APIError{Status: "error", ErrorCode: ItemNotFound, Message: "Item não encontrado"}, body)
+
+	validationErr := errors.NewValidationError("Dados inválidos")
+	validationErr.AddFieldError("name", "Campo obrigatório")
+	status, body = respond(validationErr)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, ValidationFailed, body.ErrorCode)
+	assert.Equal(t, map[string]string{"name": "Campo obrigatório"}, body.FieldErrors)
+
+	// Erros inesperados não expõem a mensagem original
+	status, body = respond(fmt.Errorf("falha inesperada"))
+	assert.Equal(t, http.StatusInternalServerError, status)
+	assert.Equal(t, InternalError, body.ErrorCode)
+	assert.Equal(t, models.ErrInternalServer.Message, body.Message)
+}
@@ -0,0 +1,91 @@
+package errcodes
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"callable-api/internal/models"
+	"callable-api/pkg/cloud"
+	"callable-api/pkg/errors"
+)
+
+// dependencyRetryAfter é o intervalo sugerido para repetir as requisições que
+// falharam por uma integração externa indisponível
+const dependencyRetryAfter = 30 * time.Second
+
+// Respond responde com o erro no formato padrão da API, incluindo o campo
+// "code" do catálogo. Prazos expirados viram 408 e falhas das integrações
+// com o GCP viram 503; os demais erros são convertidos por apiError
+func Respond(c *gin.Context, err error, overrides ...Override) {
+	code := FromError(err, overrides...)
+
+	if stderrors.Is(err, context.DeadlineExceeded) {
+		c.AbortWithStatusJSON(models.ErrRequestTimeout.Code, models.ErrRequestTimeout)
+		return
+	}
+
+	var unavailable *cloud.UnavailableError
+	if stderrors.As(err, &unavailable) {
+		RespondRetry(c, models.ErrDependencyDown.WithDetails(unavailable.Service), dependencyRetryAfter)
+		return
+	}
+
+	apiErr := apiError(err, code)
+	c.AbortWithStatusJSON(apiErr.Code, apiErr)
+}
+
+// statusByType é o status HTTP de cada tipo de errors.AppError
+var statusByType = map[string]int{
+	TypeBadRequest:   http.StatusBadRequest,
+	TypeUnauthorized: http.StatusUnauthorized,
+	TypeForbidden:    http.StatusForbidden,
+	TypeNotFound:     http.StatusNotFound,
+	TypeConflict:     http.StatusConflict,
+	TypeInternal:     http.StatusInternalServerError,
+}
+
+// apiError converte o erro no corpo padrão da API com o código informado.
+// Erros de validação trazem a primeira mensagem de cada campo em FieldErrors;
+// erros que não são da aplicação viram models.ErrInternalServer
+func apiError(err error, code string) models.APIError {
+	var validationErr *errors.ValidationError
+	if stderrors.As(err, &validationErr) {
+		apiErr := models.ErrInvalidInput
+		apiErr.ErrorCode = code
+		apiErr.Message = validationErr.Error()
+		if len(validationErr.FieldErrors) > 0 {
+			apiErr.FieldErrors = make(map[string]string, len(validationErr.FieldErrors))
+			for _, fieldErr := range validationErr.FieldErrors {
+				if _, exists := apiErr.FieldErrors[fieldErr.Field]; !exists {
+					apiErr.FieldErrors[fieldErr.Field] = fieldErr.Message
+				}
+			}
+		}
+		return apiErr
+	}
+
+	apiErr := models.ErrInternalServer
+	apiErr.ErrorCode = code
+	var appErr *errors.AppError
+	if stderrors.As(err, &appErr) {
+		if status, ok := statusByType[appErr.Type]; ok {
+			apiErr.Code = status
+		}
+		apiErr.Message = appErr.Message
+	}
+	return apiErr
+}
+
+// RespondRetry responde um erro temporário (429 ou 503) com o header
+// Retry-After e a política de backoff correspondente no corpo, para que os
+// clientes repitam a requisição de forma consistente
+func RespondRetry(c *gin.Context, apiErr models.APIError, retryAfter time.Duration) {
+	apiErr = apiErr.WithBackoff(retryAfter)
+	c.Header("Retry-After", strconv.Itoa(apiErr.Backoff.RetryAfterSeconds))
+	c.AbortWithStatusJSON(apiErr.Code, apiErr)
+}
@@ -0,0 +1,72 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"callable-api/internal/correlation"
+	"callable-api/pkg/logger"
+)
+
+// Publisher publica eventos de domínio (implementado por Bus)
+type Publisher interface {
+	Publish(ctx context.Context, event Event)
+}
+
+// Subscriber recebe os eventos publicados. É chamado de forma síncrona por
+// Publish: entregas demoradas devem ser feitas em segundo plano
+type Subscriber func(ctx context.Context, envelope *Envelope)
+
+// Bus distribui os eventos publicados a todos os consumidores inscritos
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers []Subscriber
+}
+
+// NewBus cria um barramento de eventos sem consumidores
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe inscreve um consumidor em todos os eventos
+func (b *Bus) Subscribe(subscriber Subscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers = append(b.subscribers, subscriber)
+}
+
+// Publish implementa Publisher. Falhas de serialização e panics dos
+// consumidores são registrados sem afetar quem publicou o evento
+func (b *Bus) Publish(ctx context.Context, event Event) {
+	envelope, err := NewEnvelope(ctx, event)
+	if err != nil {
+		logger.Error("Falha ao publicar evento", correlation.Fields(ctx, map[string]interface{}{
+			"event": event.EventType(),
+			"error": err.Error(),
+		}))
+		return
+	}
+
+	b.mu.RLock()
+	subscribers := b.subscribers
+	b.mu.RUnlock()
+
+	for _, subscriber := range subscribers {
+		b.deliver(ctx, subscriber, envelope)
+	}
+}
+
+// deliver entrega o envelope a um consumidor, isolando seus panics
+func (b *Bus) deliver(ctx context.Context, subscriber Subscriber, envelope *Envelope) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("Consumidor de eventos falhou", correlation.Fields(ctx, map[string]interface{}{
+				"event":   envelope.Type,
+				"eventId": envelope.ID,
+				"error":   fmt.Sprint(r),
+			}))
+		}
+	}()
+	subscriber(ctx, envelope)
+}
@@ -0,0 +1,208 @@
+// Package events define os eventos de domínio publicados pelos serviços e o
+// envelope JSON versionado compartilhado pelos consumidores (webhooks,
+// Pub/Sub, WebSocket), para que todos recebam o mesmo contrato.
+//
+// Mudanças incompatíveis no formato de um evento exigem incrementar a sua
+// versão; campos novos e opcionais mantêm a versão atual.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"time"
+
+	"callable-api/internal/correlation"
+
+	"github.com/google/uuid"
+)
+
+// Tipos dos eventos publicados
+const (
+	TypeItemCreated    = "item.created"
+	TypeItemUpdated    = "item.updated"
+	TypeItemShared     = "item.shared"
+	TypeItemUnshared   = "item.unshared"
+	TypeUserRegistered = "user.registered"
+	TypeJobCompleted   = "job.completed"
+	TypeJobFailed      = "job.failed"
+)
+
+// Erros retornados por Decode
+var (
+	ErrUnknownEvent       = stderrors.New("tipo de evento desconhecido")
+	ErrUnsupportedVersion = stderrors.New("versão de evento não suportada")
+)
+
+// Event é um evento de domínio
+type Event interface {
+	// EventType retorna o tipo do evento (ex.: item.created)
+	EventType() string
+
+	// SchemaVersion retorna a versão do formato do evento
+	SchemaVersion() int
+}
+
+// ItemCreated é publicado quando um item é criado
+type ItemCreated struct {
+	ItemID  string `json:"item_id"`
+	OwnerID string `json:"owner_id,omitempty"`
+	OrgID   string `json:"org_id,omitempty"`
+	Name    string `json:"name"`
+}
+
+// EventType implementa Event
+func (ItemCreated) EventType() string { return TypeItemCreated }
+
+// SchemaVersion implementa Event
+func (ItemCreated) SchemaVersion() int { return 1 }
+
+// ItemUpdated é publicado quando os dados de um item são alterados
+type ItemUpdated struct {
+	ItemID  string `json:"item_id"`
+	OwnerID string `json:"owner_id,omitempty"`
+	OrgID   string `json:"org_id,omitempty"`
+	Name    string `json:"name"`
+}
+
+// EventType implementa Event
+func (ItemUpdated) EventType() string { return TypeItemUpdated }
+
+// SchemaVersion implementa Event
+func (ItemUpdated) SchemaVersion() int { return 1 }
+
+// ItemShared é publicado quando um item é compartilhado com um usuário ou
+// papel (UserID vazio quando o compartilhamento é com um papel)
+type ItemShared struct {
+	ItemID    string `json:"item_id"`
+	ItemName  string `json:"item_name"`
+	ShareID   string `json:"share_id"`
+	UserID    string `json:"user_id,omitempty"`
+	Role      string `json:"role,omitempty"`
+	Level     string `json:"level"`
+	GrantedBy string `json:"granted_by"`
+}
+
+// EventType implementa Event
+func (ItemShared) EventType() string { return TypeItemShared }
+
+// SchemaVersion implementa Event
+func (ItemShared) SchemaVersion() int { return 1 }
+
+// ItemUnshared é publicado quando um compartilhamento é revogado. O dono e a
+// organização do item permitem saber se o usuário ou papel ainda o lê
+type ItemUnshared struct {
+	ItemID    string `json:"item_id"`
+	OwnerID   string `json:"owner_id,omitempty"`
+	OrgID     string `json:"org_id,omitempty"`
+	ShareID   string `json:"share_id"`
+	UserID    string `json:"user_id,omitempty"`
+	Role      string `json:"role,omitempty"`
+	RevokedBy string `json:"revoked_by"`
+}
+
+// EventType implementa Event
+func (ItemUnshared) EventType() string { return TypeItemUnshared }
+
+// SchemaVersion implementa Event
+func (ItemUnshared) SchemaVersion() int { return 1 }
+
+// UserRegistered é publicado quando um usuário se registra
+type UserRegistered struct {
+	UserID string `json:"user_id"`
+	Email  string `json:"email"`
+	Name   string `json:"name"`
+}
+
+// EventType implementa Event
+func (UserRegistered) EventType() string { return TypeUserRegistered }
+
+// SchemaVersion implementa Event
+func (UserRegistered) SchemaVersion() int { return 1 }
+
+// JobCompleted é publicado quando um job em segundo plano é concluído com
+// sucesso (as etapas de workflows não publicam: o evento vem ao fim do workflow)
+type JobCompleted struct {
+	JobID  string `json:"job_id"`
+	Kind   string `json:"kind"`
+	UserID string `json:"user_id,omitempty"`
+}
+
+// EventType implementa Event
+func (JobCompleted) EventType() string { return TypeJobCompleted }
+
+// SchemaVersion implementa Event
+func (JobCompleted) SchemaVersion() int { return 1 }
+
+// JobFailed é publicado quando um job em segundo plano falha definitivamente
+type JobFailed struct {
+	JobID    string `json:"job_id"`
+	Kind     string `json:"kind"`
+	UserID   string `json:"user_id,omitempty"`
+	Error    string `json:"error"`
+	Attempts int    `json:"attempts"`
+}
+
+// EventType implementa Event
+func (JobFailed) EventType() string { return TypeJobFailed }
+
+// SchemaVersion implementa Event
+func (JobFailed) SchemaVersion() int { return 1 }
+
+// decoders cria, para cada tipo conhecido, o valor em que o evento é decodificado
+var decoders = map[string]func() Event{
+	TypeItemCreated:    func() Event { return &ItemCreated{} },
+	TypeItemUpdated:    func() Event { return &ItemUpdated{} },
+	TypeItemShared:     func() Event { return &ItemShared{} },
+	TypeItemUnshared:   func() Event { return &ItemUnshared{} },
+	TypeUserRegistered: func() Event { return &UserRegistered{} },
+	TypeJobCompleted:   func() Event { return &JobCompleted{} },
+	TypeJobFailed:      func() Event { return &JobFailed{} },
+}
+
+// Envelope é a representação JSON de um evento entregue aos consumidores
+type Envelope struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	Version    int             `json:"version"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	RequestID  string          `json:"request_id,omitempty"`
+	JobID      string          `json:"job_id,omitempty"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// NewEnvelope serializa o evento, associando-o à requisição ou ao job do contexto
+func NewEnvelope(ctx context.Context, event Event) (*Envelope, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar evento %s: %w", event.EventType(), err)
+	}
+	return &Envelope{
+		ID:         uuid.New().String(),
+		Type:       event.EventType(),
+		Version:    event.SchemaVersion(),
+		OccurredAt: time.Now().UTC(),
+		RequestID:  correlation.RequestID(ctx),
+		JobID:      correlation.JobID(ctx),
+		Data:       data,
+	}, nil
+}
+
+// Decode converte o envelope de volta no evento tipado (um ponteiro, ex.:
+// *ItemCreated). Versões mais novas do que as conhecidas são recusadas
+func Decode(envelope *Envelope) (Event, error) {
+	newEvent, ok := decoders[envelope.Type]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEvent, envelope.Type)
+	}
+
+	event := newEvent()
+	if envelope.Version < 1 || envelope.Version > event.SchemaVersion() {
+		return nil, fmt.Errorf("%w: %s v%d", ErrUnsupportedVersion, envelope.Type, envelope.Version)
+	}
+	if err := json.Unmarshal(envelope.Data, event); err != nil {
+		return nil, fmt.Errorf("falha ao decodificar evento %s: %w", envelope.Type, err)
+	}
+	return event, nil
+}
@@ -0,0 +1,55 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"callable-api/internal/correlation"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvelopeRoundTrip(t *testing.T) {
+	ctx := correlation.WithRequestID(context.Background(), "req-1")
+	envelope, err := NewEnvelope(ctx, ItemCreated{ItemID: "42", OwnerID: "u1", Name: "Item"})
+	require.NoError(t, err)
+	assert.Equal(t, TypeItemCreated, envelope.Type)
+	assert.Equal(t, 1, envelope.Version)
+	assert.Equal(t, "req-1", envelope.RequestID)
+	assert.NotEmpty(t, envelope.ID)
+
+	// O envelope sobrevive à serialização feita pelos consumidores
+	raw, err := json.Marshal(envelope)
+	require.NoError(t, err)
+	var decoded Envelope
+	require.NoError(t, json.Unmarshal(raw, &decoded))
+
+	event, err := Decode(&decoded)
+	require.NoError(t, err)
+	assert.Equal(t, &ItemCreated{ItemID: "42", OwnerID: "u1", Name: "Item"}, event)
+
+	// Tipos desconhecidos e versões futuras são recusados
+	_, err = Decode(&Envelope{Type: "item.unknown", Version: 1, Data: decoded.Data})
+	assert.ErrorIs(t, err, ErrUnknownEvent)
+	_, err = Decode(&Envelope{Type: TypeItemCreated, Version: 2, Data: decoded.Data})
+	assert.ErrorIs(t, err, ErrUnsupportedVersion)
+}
+
+func TestBus(t *testing.T) {
+	bus := NewBus()
+	var received []*Envelope
+	bus.Subscribe(func(ctx context.Context, envelope *Envelope) {
+		panic("consumidor com defeito")
+	})
+	bus.Subscribe(func(ctx context.Context, envelope *Envelope) {
+		received = append(received, envelope)
+	})
+
+	// Um consumidor com falha não impede a entrega aos demais
+	bus.Publish(context.Background(), UserRegistered{UserID: "u1", Email: "a@example.com"})
+	require.Len(t, received, 1)
+	assert.Equal(t, TypeUserRegistered, received[0].Type)
+	assert.JSONEq(t, `{"user_id":"u1","email":"a@example.com","name":""}`, string(received[0].Data))
+}
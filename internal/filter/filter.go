@@ -0,0 +1,181 @@
+// Package filter representa os filtros com operadores das listagens, como
+// created_at[gte]=2024-01-01T00:00:00Z ou value[lt]=10. A leitura dos
+// parâmetros da query string fica na camada HTTP (ver internal/handlers); os
+// repositórios traduzem as condições em predicados em memória (Match) ou em
+// cláusulas WHERE (SQL).
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Op é o operador de comparação de uma condição
+type Op string
+
+// Operadores suportados
+const (
+	Eq  Op = "eq"
+	Ne  Op = "ne"
+	Gt  Op = "gt"
+	Gte Op = "gte"
+	Lt  Op = "lt"
+	Lte Op = "lte"
+)
+
+// sqlOps são os operadores SQL equivalentes
+var sqlOps = map[Op]string{
+	Eq:  "=",
+	Ne:  "<>",
+	Gt:  ">",
+	Gte: ">=",
+	Lt:  "<",
+	Lte: "<=",
+}
+
+// Kind define como os valores de um campo são comparados
+type Kind int
+
+// Tipos de campo
+const (
+	String Kind = iota // comparação lexicográfica
+	Number             // comparação numérica
+	Time               // datas RFC 3339
+)
+
+// Field declara um campo filtrável e o tipo dos seus valores
+type Field struct {
+	Name string
+	Kind Kind
+}
+
+// Condition compara um campo com um valor
+type Condition struct {
+	Field string
+	Kind  Kind
+	Op    Op
+	Value string
+
+	number float64
+	time   time.Time
+}
+
+// NewCondition valida o operador e o valor de acordo com o tipo do campo
+func NewCondition(field Field, op Op, value string) (Condition, error) {
+	if _, ok := sqlOps[op]; !ok {
+		return Condition{}, fmt.Errorf("operador desconhecido em %s: %q", field.Name, op)
+	}
+
+	cond := Condition{Field: field.Name, Kind: field.Kind, Op: op, Value: value}
+	switch field.Kind {
+	case Number:
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return Condition{}, fmt.Errorf("%s deve ser numérico: %q", field.Name, value)
+		}
+		cond.number = n
+	case Time:
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return Condition{}, fmt.Errorf("%s deve estar no formato RFC 3339: %q", field.Name, value)
+		}
+		cond.time = t
+	}
+	return cond, nil
+}
+
+// Match indica se o valor do campo (no formato textual do registro) satisfaz
+// a condição. Valores que não podem ser interpretados no tipo do campo não
+// satisfazem nenhuma condição
+func (c Condition) Match(raw string) bool {
+	var cmp int
+	switch c.Kind {
+	case Number:
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return false
+		}
+		cmp = compare(n, c.number)
+	case Time:
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return false
+		}
+		cmp = t.Compare(c.time)
+	default:
+		cmp = strings.Compare(raw, c.Value)
+	}
+
+	switch c.Op {
+	case Eq:
+		return cmp == 0
+	case Ne:
+		return cmp != 0
+	case Gt:
+		return cmp > 0
+	case Gte:
+		return cmp >= 0
+	case Lt:
+		return cmp < 0
+	case Lte:
+		return cmp <= 0
+	}
+	return false
+}
+
+// arg retorna o valor da condição no tipo usado pelos drivers SQL
+func (c Condition) arg() interface{} {
+	switch c.Kind {
+	case Number:
+		return c.number
+	case Time:
+		return c.time
+	default:
+		return c.Value
+	}
+}
+
+// Conditions é a conjunção (AND) de condições
+type Conditions []Condition
+
+// Match indica se o registro satisfaz todas as condições. value retorna o
+// valor textual de um campo do registro
+func (cs Conditions) Match(value func(field string) string) bool {
+	for _, c := range cs {
+		if !c.Match(value(c.Field)) {
+			return false
+		}
+	}
+	return true
+}
+
+// SQL traduz as condições em uma cláusula WHERE com placeholders (?) e seus
+// argumentos. columns mapeia cada campo para a coluna (ou expressão) do banco;
+// campos sem coluna são um erro. Retorna "" quando não há condições
+func (cs Conditions) SQL(columns map[string]string) (string, []interface{}, error) {
+	clauses := make([]string, 0, len(cs))
+	args := make([]interface{}, 0, len(cs))
+	for _, c := range cs {
+		column, ok := columns[c.Field]
+		if !ok {
+			return "", nil, fmt.Errorf("filter: campo sem coluna: %s", c.Field)
+		}
+		clauses = append(clauses, column+" "+sqlOps[c.Op]+" ?")
+		args = append(args, c.arg())
+	}
+	return strings.Join(clauses, " AND "), args, nil
+}
+
+// compare compara dois números como strings.Compare
+func compare(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
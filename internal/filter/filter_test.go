@@ -0,0 +1,76 @@
+package filter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	createdAt = Field{Name: "created_at", Kind: Time}
+	value     = Field{Name: "value", Kind: Number}
+	name      = Field{Name: "name", Kind: String}
+)
+
+func TestCondition(t *testing.T) {
+	gte, err := NewCondition(createdAt, Gte, "2024-01-01T00:00:00Z")
+	require.NoError(t, err)
+	assert.True(t, gte.Match("2024-01-01T00:00:00Z"))
+	assert.True(t, gte.Match("2024-06-01T09:30:00-03:00"))
+	assert.False(t, gte.Match("2023-12-31T23:59:59Z"))
+	assert.False(t, gte.Match("ontem"))
+
+	// Comparação numérica, não lexicográfica
+	lt, err := NewCondition(value, Lt, "10")
+	require.NoError(t, err)
+	assert.True(t, lt.Match("9"))
+	assert.True(t, lt.Match("-2.5"))
+	assert.False(t, lt.Match("10"))
+	assert.False(t, lt.Match("Value-1"))
+
+	ne, err := NewCondition(name, Ne, "Item 1")
+	require.NoError(t, err)
+	assert.True(t, ne.Match("Item 2"))
+	assert.False(t, ne.Match("Item 1"))
+
+	for _, invalid := range []struct {
+		field Field
+		op    Op
+		value string
+	}{
+		{value, "like", "1"},
+		{value, Lt, "dez"},
+		{createdAt, Gte, "2024-01-01"},
+	} {
+		_, err := NewCondition(invalid.field, invalid.op, invalid.value)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestConditions(t *testing.T) {
+	from, _ := NewCondition(createdAt, Gte, "2024-01-01T00:00:00Z")
+	below, _ := NewCondition(value, Lt, "10")
+	conditions := Conditions{from, below}
+
+	record := map[string]string{"created_at": "2024-03-01T00:00:00Z", "value": "5"}
+	get := func(field string) string { return record[field] }
+	assert.True(t, conditions.Match(get))
+	record["value"] = "50"
+	assert.False(t, conditions.Match(get))
+	assert.True(t, Conditions(nil).Match(get))
+
+	where, args, err := conditions.SQL(map[string]string{"created_at": "created_at", "value": "CAST(value AS NUMERIC)"})
+	require.NoError(t, err)
+	assert.Equal(t, "created_at >= ? AND CAST(value AS NUMERIC) < ?", where)
+	assert.Equal(t, []interface{}{time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 10.0}, args)
+
+	_, _, err = conditions.SQL(map[string]string{"created_at": "created_at"})
+	assert.Error(t, err)
+
+	where, args, err = Conditions(nil).SQL(nil)
+	require.NoError(t, err)
+	assert.Empty(t, where)
+	assert.Empty(t, args)
+}
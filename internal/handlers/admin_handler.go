@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"callable-api/internal/admin"
+	"callable-api/internal/models"
+	"callable-api/internal/routes"
+)
+
+// AdminHandler processa as requisições do painel de operações
+type AdminHandler struct {
+	overview *admin.OverviewService
+	routes   *routes.Registry
+}
+
+// NewAdminHandler cria um novo handler administrativo
+func NewAdminHandler(overview *admin.OverviewService) *AdminHandler {
+	return &AdminHandler{
+		overview: overview,
+	}
+}
+
+// WithRoutes habilita a exportação da matriz de segurança das rotas
+func (h *AdminHandler) WithRoutes(registry *routes.Registry) *AdminHandler {
+	h.routes = registry
+	return h
+}
+
+// Overview retorna o resumo operacional da aplicação
+// @Summary Resumo operacional
+// @Description Taxa de requisições, jobs por estado, contagem de usuários e itens, saúde das dependências e erros recentes
+// @Tags admin
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} models.Response{data=models.AdminOverview}
+// @Failure 401 {object} models.APIError
+// @Failure 403 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /api/v1/admin/overview [get]
+func (h *AdminHandler) Overview(c *gin.Context) {
+	overview, err := h.overview.Overview(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, "Visão geral recuperada com sucesso", overview)
+}
+
+// Routes exporta a matriz de segurança das rotas para revisão
+// @Summary Matriz de segurança das rotas
+// @Description Lista cada rota com o modo de autenticação, os papéis exigidos e a classe de limite de requisições
+// @Tags admin
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} models.Response{data=[]models.RouteSecurity}
+// @Failure 401 {object} models.APIError
+// @Failure 403 {object} models.APIError
+// @Router /api/v1/admin/routes [get]
+func (h *AdminHandler) Routes(c *gin.Context) {
+	if h.routes == nil {
+		respond(c, http.StatusOK, "Rotas recuperadas com sucesso", []models.RouteSecurity{})
+		return
+	}
+
+	respond(c, http.StatusOK, "Rotas recuperadas com sucesso", h.routes.Matrix())
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"callable-api/internal/errcodes"
+	"callable-api/internal/models"
+)
+
+// CreateInvitation emite um convite de cadastro
+// @Summary Emitir convite de cadastro
+// @Description Gera um código de convite para um email ainda não cadastrado, com o papel da conta e a validade (expires_at, padrão INVITATION_TTL). O código é retornado uma única vez e deve ser informado como invitation_code em POST /api/v1/auth/register
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body models.CreateInvitationInput true "Convidado, papel e validade"
+// @Success 201 {object} models.Response{data=models.Invitation}
+// @Failure 400 {object} models.APIError
+// @Failure 401 {object} models.APIError
+// @Failure 403 {object} models.APIError
+// @Failure 409 {object} models.APIError
+// @Router /api/v1/admin/invitations [post]
+func (h *OrganizationHandler) CreateInvitation(c *gin.Context) {
+	var input models.CreateInvitationInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	invitation, err := h.orgs.CreateInvitation(c.Request.Context(), viewerFrom(c), &input)
+	if err != nil {
+		handleError(c, err, errcodes.When(errcodes.TypeConflict, errcodes.EmailInUse))
+		return
+	}
+
+	respond(c, http.StatusCreated, "Convite criado com sucesso", invitation)
+}
+
+// ListRegistrationInvitations lista os convites de cadastro
+// @Summary Convites de cadastro
+// @Description Convites emitidos pelos administradores, aceitos ou não; os códigos não são exibidos
+// @Tags admin
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} models.Response{data=[]models.Invitation}
+// @Failure 401 {object} models.APIError
+// @Failure 403 {object} models.APIError
+// @Router /api/v1/admin/invitations [get]
+func (h *OrganizationHandler) ListRegistrationInvitations(c *gin.Context) {
+	invitations, err := h.orgs.ListRegistrationInvitations(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, "Convites recuperados com sucesso", invitations)
+}
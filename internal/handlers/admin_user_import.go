@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"callable-api/internal/errcodes"
+	"callable-api/internal/jobs"
+	"callable-api/internal/models"
+	"callable-api/pkg/errors"
+)
+
+// userImportJobType identifica os jobs de importação de usuários
+const userImportJobType = "users.import"
+
+// Colunas do CSV de importação de usuários
+const (
+	importColumnEmail = "email"
+	importColumnName  = "name"
+	importColumnRole  = "role"
+)
+
+// WithImport habilita a importação de usuários por CSV, processada como job.
+// MaxItems limita o número de linhas do arquivo
+func (h *AdminUserHandler) WithImport(scheduler JobScheduler, limits ArrayLimits) *AdminUserHandler {
+	h.jobs = scheduler
+	h.importLimits = limits
+	return h
+}
+
+// ImportUsers enfileira a importação de um CSV de usuários
+// @Summary Importar usuários
+// @Description Recebe um CSV com cabeçalho e as colunas email, name e role (opcional, user ou admin) e enfileira um job que cria as contas com a redefinição de senha obrigatória, enviando a cada usuário o convite com o link para escolher a senha. O resultado do job traz o relatório de cada linha; linhas inválidas ou com email em uso não interrompem a importação
+// @Tags admin
+// @Accept text/csv
+// @Produce json
+// @Security Bearer
+// @Param request body string true "CSV de usuários (email,name,role)"
+// @Success 202 {object} models.Response{data=models.JobStatus}
+// @Failure 400 {object} models.APIError
+// @Failure 401 {object} models.APIError
+// @Failure 403 {object} models.APIError
+// @Failure 413 {object} models.APIError
+// @Failure 503 {object} models.APIError
+// @Router /api/v1/admin/users/import [post]
+func (h *AdminUserHandler) ImportUsers(c *gin.Context) {
+	limits := h.importLimits
+	if limits.MaxBytes > 0 && c.Request.ContentLength > limits.MaxBytes {
+		abortTooLarge(c, limits)
+		return
+	}
+
+	body := io.Reader(c.Request.Body)
+	if limits.MaxBytes > 0 {
+		body = http.MaxBytesReader(c.Writer, c.Request.Body, limits.MaxBytes)
+	}
+	rows, err := readUserImport(body, limits.MaxItems)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if stderrors.As(err, &maxBytesErr) || stderrors.Is(err, errTooManyElements) {
+			abortTooLarge(c, limits)
+			return
+		}
+		handleError(c, errors.NewBadRequestError(err.Error(), err), errcodes.When(errcodes.TypeBadRequest, errcodes.InvalidUserImport))
+		return
+	}
+
+	actorID := c.GetString("userID")
+	job, err := h.jobs.ScheduleJob(c.Request.Context(), actorID, userImportJobType, func(ctx context.Context) (interface{}, error) {
+		jobs.Log(ctx, jobs.LogInfo, "Importando usuários", map[string]interface{}{"rows": len(rows)})
+		report, err := h.service.ImportUsers(ctx, actorID, rows)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range report.Rows {
+			if row.Error != "" {
+				jobs.Log(ctx, jobs.LogWarn, "Falha na linha da importação", map[string]interface{}{
+					"line":  row.Line,
+					"error": row.Error,
+				})
+			}
+		}
+		jobs.Log(ctx, jobs.LogInfo, "Usuários importados", map[string]interface{}{
+			"created": report.Created,
+			"failed":  report.Failed,
+		})
+		return report, nil
+	})
+	if err != nil {
+		respondScheduleError(c, err)
+		return
+	}
+
+	c.Header("Location", "/api/v1/jobs/"+job.ID)
+	respond(c, http.StatusAccepted, "Importação de usuários enfileirada", job)
+}
+
+// readUserImport lê as linhas do CSV de importação. O cabeçalho define a
+// ordem das colunas (email e name são obrigatórias; colunas desconhecidas são
+// ignoradas). Arquivos com mais de maxRows linhas retornam errTooManyElements
+func readUserImport(body io.Reader, maxRows int) ([]models.UserImportRow, error) {
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("CSV vazio: informe o cabeçalho %s,%s,%s", importColumnEmail, importColumnName, importColumnRole)
+	}
+	if err != nil {
+		return nil, wrapCSVError(err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		columns[name] = i
+	}
+	for _, required := range []string{importColumnEmail, importColumnName} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("o cabeçalho do CSV não tem a coluna %s", required)
+		}
+	}
+
+	field := func(record []string, column string) string {
+		if i, ok := columns[column]; ok && i < len(record) {
+			return record[i]
+		}
+		return ""
+	}
+
+	var rows []models.UserImportRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, wrapCSVError(err)
+		}
+		if maxRows > 0 && len(rows) >= maxRows {
+			return nil, errTooManyElements
+		}
+		line, _ := reader.FieldPos(0)
+		rows = append(rows, models.UserImportRow{
+			Line:  line,
+			Email: field(record, importColumnEmail),
+			Name:  field(record, importColumnName),
+			Role:  field(record, importColumnRole),
+		})
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("o CSV não tem linhas de usuários")
+	}
+	return rows, nil
+}
+
+// wrapCSVError descreve o erro de leitura do CSV, preservando os erros de
+// tamanho do corpo
+func wrapCSVError(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	if stderrors.As(err, &maxBytesErr) {
+		return err
+	}
+	return fmt.Errorf("CSV malformado: %w", err)
+}
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"callable-api/internal/errcodes"
+	"callable-api/internal/models"
+	"callable-api/internal/pagination"
+	"callable-api/internal/service"
+)
+
+// AdminUserHandler permite aos administradores bloquear contas comprometidas
+// e importar contas em lote
+type AdminUserHandler struct {
+	service    *service.AuthService
+	pagination pagination.Config
+
+	// Importação de usuários por CSV (ver WithImport)
+	jobs         JobScheduler
+	importLimits ArrayLimits
+}
+
+// NewAdminUserHandler cria um novo AdminUserHandler
+func NewAdminUserHandler(service *service.AuthService) *AdminUserHandler {
+	return &AdminUserHandler{service: service, pagination: pagination.DefaultConfig(), importLimits: DefaultArrayLimits()}
+}
+
+// WithPagination define a política de paginação das listagens
+func (h *AdminUserHandler) WithPagination(cfg pagination.Config) *AdminUserHandler {
+	h.pagination = cfg
+	return h
+}
+
+// userNotFound identifica usuários inexistentes nas respostas de erro
+var userNotFound = errcodes.When(errcodes.TypeNotFound, errcodes.UserNotFound)
+
+// DisableUser desativa a conta do usuário
+// @Summary Desativar usuário
+// @Description Bloqueia login, renovação de tokens e o uso de tokens já emitidos
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "ID do usuário"
+// @Param request body models.DisableUserInput false "Motivo da desativação"
+// @Success 200 {object} models.Response{data=models.UserResponse}
+// @Failure 400 {object} models.APIError
+// @Failure 401 {object} models.APIError
+// @Failure 403 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Router /api/v1/admin/users/{id}/disable [post]
+func (h *AdminUserHandler) DisableUser(c *gin.Context) {
+	var input models.DisableUserInput
+	if c.Request.ContentLength != 0 && !bindJSON(c, &input) {
+		return
+	}
+
+	user, err := h.service.DisableUser(c.GetString("userID"), c.Param("id"), input.Reason)
+	if err != nil {
+		handleError(c, err, userNotFound)
+		return
+	}
+
+	respond(c, http.StatusOK, "Usuário atualizado com sucesso", user)
+}
+
+// EnableUser reativa a conta do usuário
+// @Summary Reativar usuário
+// @Tags admin
+// @Produce json
+// @Security Bearer
+// @Param id path string true "ID do usuário"
+// @Success 200 {object} models.Response{data=models.UserResponse}
+// @Failure 401 {object} models.APIError
+// @Failure 403 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Router /api/v1/admin/users/{id}/enable [post]
+func (h *AdminUserHandler) EnableUser(c *gin.Context) {
+	user, err := h.service.EnableUser(c.GetString("userID"), c.Param("id"))
+	if err != nil {
+		handleError(c, err, userNotFound)
+		return
+	}
+
+	respond(c, http.StatusOK, "Usuário atualizado com sucesso", user)
+}
+
+// ForcePasswordReset exige que o usuário redefina a senha
+// @Summary Forçar redefinição de senha
+// @Description Bloqueia a conta e envia ao usuário um link de redefinição de senha por email
+// @Tags admin
+// @Produce json
+// @Security Bearer
+// @Param id path string true "ID do usuário"
+// @Success 200 {object} models.Response{data=models.UserResponse}
+// @Failure 401 {object} models.APIError
+// @Failure 403 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /api/v1/admin/users/{id}/force-password-reset [post]
+func (h *AdminUserHandler) ForcePasswordReset(c *gin.Context) {
+	user, err := h.service.ForcePasswordReset(c.Request.Context(), c.GetString("userID"), c.Param("id"))
+	if err != nil {
+		handleError(c, err, userNotFound)
+		return
+	}
+
+	respond(c, http.StatusOK, "Usuário atualizado com sucesso", user)
+}
+
+// LoginHistory retorna o histórico de login de um usuário
+// @Summary Histórico de login do usuário
+// @Description Lista as tentativas de login da conta, das mais recentes para as mais antigas
+// @Tags admin
+// @Produce json
+// @Security Bearer
+// @Param id path string true "ID do usuário"
+// @Param page query int false "Página" default(1)
+// @Param limit query int false "Itens por página" default(10)
+// @Success 200 {object} models.ListResponse{data=[]models.LoginAttempt}
+// @Failure 401 {object} models.APIError
+// @Failure 403 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Router /api/v1/admin/users/{id}/login-history [get]
+func (h *AdminUserHandler) LoginHistory(c *gin.Context) {
+	p := h.pagination.Parse(c)
+
+	attempts, total, err := h.service.LoginHistory(c.Request.Context(), c.Param("id"), p.Page, p.Limit)
+	if err != nil {
+		handleError(c, err, userNotFound)
+		return
+	}
+
+	respondList(c, "Histórico de login recuperado com sucesso", attempts, p, total)
+}
+
+// UserConsent retorna a situação e o histórico de aceites de um usuário
+// @Summary Aceites do usuário
+// @Description Retorna as versões dos termos de uso e da política de privacidade aceitas pelo usuário, com data, origem, IP e user agent de cada aceite, para auditorias
+// @Tags admin
+// @Produce json
+// @Security Bearer
+// @Param id path string true "ID do usuário"
+// @Success 200 {object} models.Response{data=models.ConsentStatus}
+// @Failure 401 {object} models.APIError
+// @Failure 403 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Router /api/v1/admin/users/{id}/consent [get]
+func (h *AdminUserHandler) UserConsent(c *gin.Context) {
+	status, err := h.service.ConsentStatus(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handleError(c, err, userNotFound)
+		return
+	}
+
+	respond(c, http.StatusOK, "Aceites recuperados com sucesso", status)
+}
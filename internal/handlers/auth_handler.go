@@ -1,7 +1,11 @@
 package handlers
 
 import (
+	"callable-api/internal/errcodes"
+	"callable-api/internal/middleware"
 	"callable-api/internal/models"
+	"callable-api/internal/pagination"
+	"callable-api/internal/repository"
 	"callable-api/internal/service"
 	"callable-api/pkg/errors"
 	"net/http"
@@ -11,45 +15,102 @@ import (
 
 // AuthHandler processa requisições relacionadas a autenticação
 type AuthHandler struct {
-	service *service.AuthService
+	service    *service.AuthService
+	pagination pagination.Config
+}
+
+// accountStateCodes identifica os bloqueios de conta nas respostas de erro
+var accountStateCodes = []errcodes.Override{
+	errcodes.WhenCause(repository.ErrAccountDisabled, errcodes.AccountDisabled),
+	errcodes.WhenCause(repository.ErrPasswordResetRequired, errcodes.PasswordResetRequired),
 }
 
 // NewAuthHandler cria um novo handler de autenticação
 func NewAuthHandler(service *service.AuthService) *AuthHandler {
 	return &AuthHandler{
-		service: service,
+		service:    service,
+		pagination: pagination.DefaultConfig(),
+	}
+}
+
+// WithPagination define a política de paginação das listagens
+func (h *AuthHandler) WithPagination(cfg pagination.Config) *AuthHandler {
+	h.pagination = cfg
+	return h
+}
+
+// clientInfo extrai o IP, o user agent e o identificador do dispositivo do
+// cliente da requisição
+func clientInfo(c *gin.Context) models.ClientInfo {
+	return models.ClientInfo{
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		DeviceID:  c.GetHeader(models.DeviceIDHeader),
 	}
 }
 
 // Register registra um novo usuário
 // @Summary Registrar um novo usuário
-// @Description Cria uma nova conta de usuário no sistema
+// @Description Cria uma nova conta de usuário no sistema. Com invitation_code, a conta recebe o papel do convite e entra na organização que convidou; o código é obrigatório quando o cadastro aberto está desativado (REGISTRATION_MODE=invite). Com os termos configurados, terms_version e privacy_version devem trazer as versões vigentes aceitas
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param request body models.RegisterUserInput true "Dados de registro"
-// @Success 201 {object} models.UserResponse
+// @Success 201 {object} models.Response{data=models.UserResponse}
 // @Failure 400 {object} models.APIError
+// @Failure 403 {object} models.APIError
 // @Failure 409 {object} models.APIError
 // @Failure 500 {object} models.APIError
 // @Router /api/v1/auth/register [post]
 func (h *AuthHandler) Register(c *gin.Context) {
 	var input models.RegisterUserInput
 
-	if err := c.ShouldBindJSON(&input); err != nil {
-		validationErr := errors.NewValidationError("Dados de registro inválidos")
-		validationErr.AddFieldError("request", "Formato de dados inválido")
-		errors.HandleErrors(c, validationErr)
+	if !bindJSON(c, &input) {
 		return
 	}
 
 	user, err := h.service.Register(&input)
 	if err != nil {
-		errors.HandleErrors(c, err)
+		handleError(c, err,
+			errcodes.WhenCause(service.ErrRegistrationClosed, errcodes.RegistrationClosed),
+			errcodes.WhenCause(repository.ErrInvitationNotFound, errcodes.InvitationInvalid),
+			errcodes.When(errcodes.TypeConflict, errcodes.EmailInUse))
+		return
+	}
+
+	respond(c, http.StatusCreated, "Usuário registrado com sucesso", user)
+}
+
+// Bootstrap cria o primeiro administrador
+// @Summary Configuração inicial
+// @Description Cria o primeiro administrador com o token de configuração exibido no log da primeira execução (sem usuários e sem BOOTSTRAP_ADMIN_EMAIL/BOOTSTRAP_ADMIN_PASSWORD). O token vale uma única vez
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.BootstrapInput true "Token de configuração e dados do administrador"
+// @Success 201 {object} models.Response{data=models.UserResponse}
+// @Failure 400 {object} models.APIError
+// @Failure 403 {object} models.APIError
+// @Failure 409 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /api/v1/auth/bootstrap [post]
+func (h *AuthHandler) Bootstrap(c *gin.Context) {
+	var input models.BootstrapInput
+
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	user, err := h.service.CompleteBootstrap(c.Request.Context(), &input)
+	if err != nil {
+		handleError(c, err,
+			errcodes.WhenCause(service.ErrBootstrapCompleted, errcodes.BootstrapCompleted),
+			errcodes.WhenCause(service.ErrBootstrapTokenInvalid, errcodes.BootstrapTokenInvalid),
+			errcodes.When(errcodes.TypeConflict, errcodes.EmailInUse))
 		return
 	}
 
-	c.JSON(http.StatusCreated, user)
+	respond(c, http.StatusCreated, "Administrador criado com sucesso", user)
 }
 
 // Login autentica um usuário
@@ -58,29 +119,28 @@ func (h *AuthHandler) Register(c *gin.Context) {
 // @Tags auth
 // @Accept json
 // @Produce json
+// @Param X-Device-ID header string false "Identificador do dispositivo, vinculado ao token de atualização"
 // @Param request body models.LoginInput true "Credenciais de login"
-// @Success 200 {object} models.TokenPair
+// @Success 200 {object} models.Response{data=models.TokenPair}
 // @Failure 400 {object} models.APIError
 // @Failure 401 {object} models.APIError
+// @Failure 403 {object} models.APIError
 // @Failure 500 {object} models.APIError
 // @Router /api/v1/auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	var input models.LoginInput
 
-	if err := c.ShouldBindJSON(&input); err != nil {
-		validationErr := errors.NewValidationError("Dados de login inválidos")
-		validationErr.AddFieldError("request", "Formato de dados inválido")
-		errors.HandleErrors(c, validationErr)
+	if !bindJSON(c, &input) {
 		return
 	}
 
-	tokens, user, err := h.service.Login(&input)
+	tokens, user, err := h.service.Login(&input, clientInfo(c))
 	if err != nil {
-		errors.HandleErrors(c, err)
+		handleError(c, err, append(accountStateCodes, errcodes.When(errcodes.TypeUnauthorized, errcodes.InvalidCredentials))...)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	respond(c, http.StatusOK, "Login realizado com sucesso", gin.H{
 		"tokens": tokens,
 		"user":   user,
 	})
@@ -88,14 +148,16 @@ func (h *AuthHandler) Login(c *gin.Context) {
 
 // RefreshToken renova os tokens JWT
 // @Summary Atualizar tokens
-// @Description Renova os tokens JWT usando um token de atualização
+// @Description Renova os tokens JWT usando um token de atualização. Com o vínculo de dispositivo ativo, envie o mesmo X-Device-ID usado no login
 // @Tags auth
 // @Accept json
 // @Produce json
+// @Param X-Device-ID header string false "Identificador do dispositivo"
 // @Param request body map[string]string true "Token de atualização"
-// @Success 200 {object} models.TokenPair
+// @Success 200 {object} models.Response{data=models.TokenPair}
 // @Failure 400 {object} models.APIError
 // @Failure 401 {object} models.APIError
+// @Failure 403 {object} models.APIError
 // @Failure 500 {object} models.APIError
 // @Router /api/v1/auth/refresh [post]
 func (h *AuthHandler) RefreshToken(c *gin.Context) {
@@ -103,20 +165,44 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		RefreshToken string `json:"refresh_token" binding:"required"`
 	}
 
-	if err := c.ShouldBindJSON(&request); err != nil {
-		validationErr := errors.NewValidationError("Dados inválidos")
-		validationErr.AddFieldError("refresh_token", "Token de atualização é obrigatório")
-		errors.HandleErrors(c, validationErr)
+	if !bindJSON(c, &request) {
 		return
 	}
 
-	tokens, err := h.service.RefreshToken(request.RefreshToken)
+	tokens, err := h.service.RefreshToken(request.RefreshToken, clientInfo(c))
 	if err != nil {
-		errors.HandleErrors(c, err)
+		handleError(c, err, append(accountStateCodes,
+			errcodes.WhenCause(service.ErrDeviceMismatch, errcodes.DeviceMismatch),
+			errcodes.When(errcodes.TypeUnauthorized, errcodes.TokenInvalid))...)
 		return
 	}
 
-	c.JSON(http.StatusOK, tokens)
+	respond(c, http.StatusOK, "Token atualizado com sucesso", tokens)
+}
+
+// ResetPassword define uma nova senha com o token recebido por email
+// @Summary Redefinir senha
+// @Description Usa o token do link enviado por email quando um administrador força a redefinição de senha
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.ResetPasswordInput true "Token e nova senha"
+// @Success 200 {object} models.Response
+// @Failure 400 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /api/v1/auth/password/reset [post]
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var input models.ResetPasswordInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	if err := h.service.ResetPassword(&input); err != nil {
+		handleError(c, err, errcodes.When(errcodes.TypeBadRequest, errcodes.ResetTokenInvalid))
+		return
+	}
+
+	respond(c, http.StatusOK, "Senha redefinida com sucesso", nil)
 }
 
 // Profile retorna o perfil do usuário autenticado
@@ -125,7 +211,7 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 // @Tags auth
 // @Produce json
 // @Security Bearer
-// @Success 200 {object} models.UserResponse
+// @Success 200 {object} models.Response{data=models.UserResponse}
 // @Failure 401 {object} models.APIError
 // @Failure 404 {object} models.APIError
 // @Failure 500 {object} models.APIError
@@ -135,13 +221,13 @@ func (h *AuthHandler) Profile(c *gin.Context) {
 	userIDStr, ok := userID.(string)
 	if !ok {
 		err := errors.NewUnauthorizedError("ID de usuário inválido", nil)
-		errors.HandleErrors(c, err)
+		handleError(c, err)
 		return
 	}
 
 	profile, err := h.service.GetUserProfile(userIDStr)
 	if err != nil {
-		errors.HandleErrors(c, err)
+		handleError(c, err, errcodes.When(errcodes.TypeNotFound, errcodes.UserNotFound))
 		return
 	}
 
@@ -156,7 +242,7 @@ func (h *AuthHandler) Profile(c *gin.Context) {
 // @Produce json
 // @Security Bearer
 // @Param request body map[string]string true "Dados para atualização do perfil"
-// @Success 200 {object} models.UserResponse
+// @Success 200 {object} models.Response{data=models.UserResponse}
 // @Failure 400 {object} models.APIError
 // @Failure 401 {object} models.APIError
 // @Failure 404 {object} models.APIError
@@ -167,7 +253,7 @@ func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 	userIDStr, ok := userID.(string)
 	if !ok {
 		err := errors.NewUnauthorizedError("ID de usuário inválido", nil)
-		errors.HandleErrors(c, err)
+		handleError(c, err)
 		return
 	}
 
@@ -175,18 +261,202 @@ func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 		Name string `json:"name" binding:"required"`
 	}
 
-	if err := c.ShouldBindJSON(&request); err != nil {
-		validationErr := errors.NewValidationError("Dados inválidos")
-		validationErr.AddFieldError("name", "Nome é obrigatório")
-		errors.HandleErrors(c, validationErr)
+	if !bindJSON(c, &request) {
 		return
 	}
 
 	profile, err := h.service.UpdateUserProfile(userIDStr, request.Name)
 	if err != nil {
-		errors.HandleErrors(c, err)
+		handleError(c, err, errcodes.When(errcodes.TypeNotFound, errcodes.UserNotFound))
 		return
 	}
 
 	c.JSON(http.StatusOK, profile)
-}
\ No newline at end of file
+}
+
+// PatchProfile atualiza parcialmente o perfil do usuário
+// @Summary Atualizar perfil parcialmente
+// @Description Altera apenas os campos enviados (nome, bio, telefone, idioma e fuso horário). Texto vazio remove bio, telefone, idioma e fuso horário
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body models.UpdateProfileInput true "Campos do perfil a alterar"
+// @Success 200 {object} models.Response{data=models.UserResponse}
+// @Failure 400 {object} models.APIError
+// @Failure 401 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /api/v1/auth/profile [patch]
+func (h *AuthHandler) PatchProfile(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	userIDStr, ok := userID.(string)
+	if !ok {
+		err := errors.NewUnauthorizedError("ID de usuário inválido", nil)
+		handleError(c, err)
+		return
+	}
+
+	var input models.UpdateProfileInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	profile, err := h.service.PatchUserProfile(userIDStr, &input)
+	if err != nil {
+		handleError(c, err, errcodes.When(errcodes.TypeNotFound, errcodes.UserNotFound))
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// UploadAvatar atualiza o avatar do usuário
+// @Summary Enviar avatar
+// @Description Recebe uma imagem JPEG, PNG ou GIF no campo "avatar", recortada no centro e redimensionada para 256x256
+// @Tags auth
+// @Accept multipart/form-data
+// @Produce json
+// @Security Bearer
+// @Param avatar formData file true "Imagem do avatar"
+// @Success 200 {object} models.Response{data=models.UserResponse}
+// @Failure 400 {object} models.APIError
+// @Failure 401 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /api/v1/auth/profile/avatar [put]
+func (h *AuthHandler) UploadAvatar(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	userIDStr, ok := userID.(string)
+	if !ok {
+		err := errors.NewUnauthorizedError("ID de usuário inválido", nil)
+		handleError(c, err)
+		return
+	}
+
+	invalidImage := errcodes.When(errcodes.TypeBadRequest, errcodes.InvalidImage)
+
+	fileHeader, err := c.FormFile("avatar")
+	if err != nil {
+		handleError(c, errors.NewBadRequestError("Envie a imagem no campo 'avatar'", err), invalidImage)
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		handleError(c, errors.NewBadRequestError("Não foi possível ler a imagem enviada", err), invalidImage)
+		return
+	}
+	defer file.Close()
+
+	profile, err := h.service.UpdateAvatar(c.Request.Context(), userIDStr, file)
+	if err != nil {
+		handleError(c, err, invalidImage, errcodes.When(errcodes.TypeNotFound, errcodes.UserNotFound))
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// LoginHistory retorna o histórico de login do usuário autenticado
+// @Summary Histórico de login
+// @Description Lista as tentativas de login (bem-sucedidas e com falha) da conta, das mais recentes para as mais antigas
+// @Tags auth
+// @Produce json
+// @Security Bearer
+// @Param page query int false "Página" default(1)
+// @Param limit query int false "Itens por página" default(10)
+// @Success 200 {object} models.ListResponse{data=[]models.LoginAttempt}
+// @Failure 401 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /api/v1/auth/login-history [get]
+func (h *AuthHandler) LoginHistory(c *gin.Context) {
+	p := h.pagination.Parse(c)
+
+	attempts, total, err := h.service.LoginHistory(c.Request.Context(), c.GetString("userID"), p.Page, p.Limit)
+	if err != nil {
+		handleError(c, err, userNotFound)
+		return
+	}
+
+	respondList(c, "Histórico de login recuperado com sucesso", attempts, p, total)
+}
+
+// GetConsent retorna a situação dos aceites do usuário autenticado
+// @Summary Consultar aceites
+// @Description Retorna, para os termos de uso e a política de privacidade, a versão vigente, a versão obrigatória e a última aceita pelo usuário, além do histórico de aceites
+// @Tags auth
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} models.Response{data=models.ConsentStatus}
+// @Failure 401 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /api/v1/auth/consent [get]
+func (h *AuthHandler) GetConsent(c *gin.Context) {
+	status, err := h.service.ConsentStatus(c.Request.Context(), c.GetString("userID"))
+	if err != nil {
+		handleError(c, err, userNotFound)
+		return
+	}
+
+	respond(c, http.StatusOK, "Aceites recuperados com sucesso", status)
+}
+
+// RecordConsent registra o aceite das versões vigentes dos documentos
+// @Summary Aceitar termos
+// @Description Registra o aceite das versões vigentes dos termos de uso e/ou da política de privacidade. Enquanto houver versão obrigatória pendente, as demais rotas autenticadas respondem 403 com o código CONSENT_REQUIRED
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body models.ConsentInput true "Versões aceitas"
+// @Success 200 {object} models.Response{data=models.ConsentStatus}
+// @Failure 400 {object} models.APIError
+// @Failure 401 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /api/v1/auth/consent [post]
+func (h *AuthHandler) RecordConsent(c *gin.Context) {
+	var input models.ConsentInput
+
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	status, err := h.service.RecordConsent(c.Request.Context(), c.GetString("userID"), &input, clientInfo(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, "Aceite registrado com sucesso", status)
+}
+
+// NarrowToken troca o token de acesso por um token restrito
+// @Summary Restringir token
+// @Description Emite um token de curta duração restrito a parte dos escopos do token atual, para repassar a contextos de navegador ou plugins de terceiros. O escopo de cada rota é "<recurso>:read" (GET) ou "<recurso>:write" (demais métodos), com o recurso igual ao primeiro segmento após /api/v1 (ex.: data:read); write inclui read. O token restrito não pode ser renovado e nunca vale mais que o token atual
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body models.NarrowTokenInput true "Escopos e validade"
+// @Success 201 {object} models.Response{data=models.ScopedToken}
+// @Failure 400 {object} models.APIError
+// @Failure 401 {object} models.APIError
+// @Failure 403 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /api/v1/auth/token/narrow [post]
+func (h *AuthHandler) NarrowToken(c *gin.Context) {
+	var input models.NarrowTokenInput
+
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	token, err := h.service.NarrowToken(c.Request.Context(), middleware.CurrentToken(c), &input)
+	if err != nil {
+		handleError(c, err, errcodes.When(errcodes.TypeForbidden, errcodes.InsufficientScope))
+		return
+	}
+
+	respond(c, http.StatusCreated, "Token restrito emitido com sucesso", token)
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"callable-api/internal/avatar"
+	"callable-api/internal/errcodes"
+)
+
+// AvatarHandler serve as imagens de avatar dos usuários
+type AvatarHandler struct {
+	avatars *avatar.Service
+}
+
+// NewAvatarHandler cria um novo AvatarHandler
+func NewAvatarHandler(avatars *avatar.Service) *AvatarHandler {
+	return &AvatarHandler{avatars: avatars}
+}
+
+// Get retorna a imagem do avatar, ou redireciona para uma URL assinada quando
+// os avatares estão no Cloud Storage
+// @Summary Imagem de avatar
+// @Description Cada upload gera um nome novo, por isso a imagem pode ficar em cache indefinidamente
+// @Tags auth
+// @Produce jpeg
+// @Param name path string true "Nome do avatar, como retornado em avatar_url"
+// @Success 200 {file} binary
+// @Success 302
+// @Failure 404 {object} models.APIError
+// @Router /api/v1/avatars/{name} [get]
+func (h *AvatarHandler) Get(c *gin.Context) {
+	object, err := h.avatars.Open(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		handleError(c, err, errcodes.When(errcodes.TypeNotFound, errcodes.AvatarNotFound))
+		return
+	}
+
+	if object.RedirectURL != "" {
+		c.Redirect(http.StatusFound, object.RedirectURL)
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	c.Data(http.StatusOK, "image/jpeg", object.Data)
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+
+	"callable-api/internal/messages"
+	"callable-api/internal/models"
+	"callable-api/internal/routes"
+	"callable-api/internal/validation"
+)
+
+// unknownFieldPrefix inicia o erro do encoding/json para campos desconhecidos
+const unknownFieldPrefix = "json: unknown field "
+
+// bindJSON lê o corpo JSON da requisição em obj aplicando as regras das tags
+// binding. Nas rotas estritas (ver routes.Strict), campos desconhecidos são
+// rejeitados. Em caso de falha responde 400 com as regras violadas por campo
+// (campo, regra e mensagem, no idioma da requisição) e retorna false
+func bindJSON(c *gin.Context, obj interface{}) bool {
+	var err error
+	if routes.Strict(c) {
+		err = decodeStrict(c, obj)
+	} else {
+		err = c.ShouldBindJSON(obj)
+	}
+	if err == nil {
+		return true
+	}
+
+	violations := bindingViolations(messages.Locale(c.Request.Context()), err)
+	c.AbortWithStatusJSON(models.ErrInvalidInput.Code, models.ErrInvalidInput.WithViolations(violations))
+	return false
+}
+
+// bindingViolations descreve o erro de decodificação ou de validação como
+// violações por campo, no idioma informado
+func bindingViolations(locale string, err error) []models.FieldViolation {
+	if field, ok := unknownField(err); ok {
+		return []models.FieldViolation{{
+			Field:   field,
+			Rule:    messages.UnknownField,
+			Message: messages.Text(locale, messages.UnknownField, field),
+		}}
+	}
+	if errs, ok := validation.Translate(err); ok {
+		violations := make([]models.FieldViolation, 0, len(errs))
+		for _, fe := range errs.Localize(locale) {
+			violations = append(violations, models.FieldViolation{
+				Field:   fe.Field,
+				Rule:    fe.Rule,
+				Message: fe.Message,
+			})
+		}
+		return violations
+	}
+
+	// JSON malformado ou com tipos incompatíveis
+	return []models.FieldViolation{{
+		Field:   "request",
+		Rule:    messages.InvalidFormat,
+		Message: messages.Text(locale, messages.InvalidFormat, "request"),
+	}}
+}
+
+// decodeStrict lê o corpo como ShouldBindJSON, mas rejeitando campos que não
+// existem em obj
+func decodeStrict(c *gin.Context, obj interface{}) error {
+	decoder := json.NewDecoder(c.Request.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(obj); err != nil {
+		return err
+	}
+	return binding.Validator.ValidateStruct(obj)
+}
+
+// unknownField extrai o nome do campo desconhecido do erro do decoder
+func unknownField(err error) (string, bool) {
+	quoted, ok := strings.CutPrefix(err.Error(), unknownFieldPrefix)
+	if !ok {
+		return "", false
+	}
+	field, unquoteErr := strconv.Unquote(quoted)
+	if unquoteErr != nil {
+		return quoted, true
+	}
+	return field, true
+}
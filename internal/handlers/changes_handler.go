@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"callable-api/internal/changes"
+	"callable-api/internal/errcodes"
+	"callable-api/internal/models"
+	"callable-api/internal/pagination"
+	"callable-api/pkg/errors"
+)
+
+// ItemChangeReader lê o log de alterações dos itens (ver service.ItemService)
+type ItemChangeReader interface {
+	ItemChanges(ctx context.Context, viewer models.Viewer, since int64, limit int) (*models.ItemChangePage, error)
+}
+
+// ChangesHandler processa a leitura do log de alterações dos itens
+type ChangesHandler struct {
+	changes    ItemChangeReader
+	pagination pagination.Config
+}
+
+// NewChangesHandler cria um novo handler do log de alterações
+func NewChangesHandler(changes ItemChangeReader) *ChangesHandler {
+	return &ChangesHandler{
+		changes:    changes,
+		pagination: pagination.DefaultConfig(),
+	}
+}
+
+// WithPagination define o número padrão e máximo de alterações por leitura
+func (h *ChangesHandler) WithPagination(cfg pagination.Config) *ChangesHandler {
+	h.pagination = cfg
+	return h
+}
+
+// GetChanges retorna as alterações dos itens posteriores ao cursor
+// @Summary Log de alterações dos itens
+// @Description Retorna, em ordem, as criações e alterações dos itens visíveis ao usuário posteriores ao cursor since, cada uma com seu número de sequência. Quando a revogação de um compartilhamento tira do usuário o acesso a um item, o log traz a remoção do item (op removed). Sincronize repetindo a leitura com since=next_cursor enquanto has_more for true. Sem since, a leitura começa pela alteração mais antiga mantida. Cursores anteriores às alterações mantidas (ou de antes de um reinício do servidor) respondem 410: ressincronize pela listagem completa
+// @Tags items
+// @Produce json
+// @Param since query string false "Cursor (next_cursor da leitura anterior)"
+// @Param limit query int false "Número máximo de alterações"
+// @Success 200 {object} models.Response{data=models.ItemChangePage}
+// @Failure 400 {object} models.APIError
+// @Failure 410 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /api/v1/data/changes [get]
+func (h *ChangesHandler) GetChanges(c *gin.Context) {
+	since, err := changes.ParseCursor(c.Query("since"))
+	if err != nil {
+		handleError(c, errors.NewBadRequestError("since deve ser o next_cursor de uma leitura anterior", err),
+			errcodes.WhenCause(changes.ErrInvalidCursor, errcodes.InvalidChangeCursor))
+		return
+	}
+
+	page, err := h.changes.ItemChanges(c.Request.Context(), viewerFrom(c), since, h.pagination.Parse(c).Limit)
+	if stderrors.Is(err, changes.ErrCursorExpired) {
+		c.AbortWithStatusJSON(http.StatusGone, models.APIError{
+			Status:    "error",
+			ErrorCode: errcodes.ChangeCursorExpired,
+			Message:   "Cursor is no longer in the change log; resync from GET /api/v1/data",
+		})
+		return
+	}
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, "Changes retrieved successfully", page)
+}
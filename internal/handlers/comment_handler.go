@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"callable-api/internal/errcodes"
+	"callable-api/internal/models"
+	"callable-api/internal/pagination"
+	"callable-api/internal/repository"
+	"callable-api/internal/service"
+)
+
+// CommentHandler processa requisições relacionadas aos comentários dos itens
+type CommentHandler struct {
+	comments   *service.CommentService
+	pagination pagination.Config
+}
+
+// NewCommentHandler cria um novo handler de comentários
+func NewCommentHandler(comments *service.CommentService) *CommentHandler {
+	return &CommentHandler{
+		comments:   comments,
+		pagination: pagination.DefaultConfig(),
+	}
+}
+
+// WithPagination define a política de paginação da listagem de comentários
+func (h *CommentHandler) WithPagination(cfg pagination.Config) *CommentHandler {
+	h.pagination = cfg
+	return h
+}
+
+// AddComment adiciona um comentário (ou uma resposta) a um item
+// @Summary Comentar item
+// @Description Adiciona um comentário ao item ou, com parent_id, responde a outro comentário do mesmo item
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "ID do item"
+// @Param request body models.CreateCommentInput true "Comentário"
+// @Success 201 {object} models.Response{data=models.CommentResponse}
+// @Failure 400 {object} models.APIError
+// @Failure 401 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Router /api/v1/data/{id}/comments [post]
+func (h *CommentHandler) AddComment(c *gin.Context) {
+	var input models.CreateCommentInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	comment, err := h.comments.AddComment(c.Request.Context(), viewerFrom(c), c.Param("id"), &input)
+	if err != nil {
+		handleError(c, err, itemNotFound)
+		return
+	}
+
+	respond(c, http.StatusCreated, "Comment created successfully", comment)
+}
+
+// ListComments lista as conversas de um item
+// @Summary Comentários do item
+// @Description Paginado pelos comentários que iniciam cada conversa; as respostas vêm aninhadas em replies
+// @Tags comments
+// @Produce json
+// @Param id path string true "ID do item"
+// @Param page query int false "Número da página" default(1)
+// @Param limit query int false "Itens por página" default(10)
+// @Success 200 {object} models.ListResponse{data=[]models.CommentResponse}
+// @Failure 404 {object} models.APIError
+// @Router /api/v1/data/{id}/comments [get]
+func (h *CommentHandler) ListComments(c *gin.Context) {
+	p := h.pagination.Parse(c)
+
+	comments, total, err := h.comments.ListComments(c.Request.Context(), viewerFrom(c), c.Param("id"), p.Page, p.Limit)
+	if err != nil {
+		handleError(c, err, itemNotFound)
+		return
+	}
+
+	respondList(c, "Comments retrieved successfully", comments, p, total)
+}
+
+// DeleteComment remove um comentário do próprio usuário
+// @Summary Remover comentário
+// @Description Apenas o autor ou um administrador pode remover. Comentários com respostas continuam na conversa, marcados como removidos
+// @Tags comments
+// @Security Bearer
+// @Param id path string true "ID do item"
+// @Param commentId path string true "ID do comentário"
+// @Success 204
+// @Failure 401 {object} models.APIError
+// @Failure 403 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Router /api/v1/data/{id}/comments/{commentId} [delete]
+func (h *CommentHandler) DeleteComment(c *gin.Context) {
+	err := h.comments.DeleteComment(c.Request.Context(), viewerFrom(c), c.Param("id"), c.Param("commentId"))
+	if err != nil {
+		handleError(c, err, errcodes.WhenCause(repository.ErrCommentNotFound, errcodes.CommentNotFound), itemNotFound)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"callable-api/internal/jobs"
+)
+
+// DefaultCompressionMinBytes é o tamanho padrão a partir do qual os
+// downloads de texto são comprimidos para os clientes que aceitam gzip
+const DefaultCompressionMinBytes = 1 << 10
+
+// acceptsGzip indica se o cliente aceita respostas comprimidas com gzip
+// (Accept-Encoding), respeitando q=0 ("gzip;q=0" ou "*;q=0" recusam)
+func acceptsGzip(c *gin.Context) bool {
+	accepted := false
+	for _, part := range strings.Split(c.GetHeader("Accept-Encoding"), ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+
+		q := 1.0
+		if name, value, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(name) == "q" {
+			if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				q = parsed
+			}
+		}
+		// gzip explícito prevalece sobre o curinga
+		if coding == "gzip" {
+			return q > 0
+		}
+		accepted = q > 0
+	}
+	return accepted
+}
+
+// writeFile responde com o conteúdo do arquivo, usando gzip quando o cliente
+// aceita: arquivos gravados comprimidos são enviados como estão e os demais,
+// se forem texto a partir de minBytes (0 desativa), são comprimidos na hora.
+// Quem não aceita gzip recebe o conteúdo original
+func writeFile(c *gin.Context, file *jobs.File, minBytes int) {
+	gzipOK := acceptsGzip(c)
+	if file.Encoding != "" || (minBytes > 0 && jobs.Compressible(file.ContentType)) {
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+	}
+
+	switch {
+	case file.Encoding == jobs.EncodingGzip && gzipOK:
+		c.Header("Content-Encoding", jobs.EncodingGzip)
+		c.Data(http.StatusOK, file.ContentType, file.Data)
+
+	case file.Encoding != "":
+		data, err := file.Decoded()
+		if err != nil {
+			handleError(c, err)
+			return
+		}
+		c.Data(http.StatusOK, file.ContentType, data)
+
+	case gzipOK && minBytes > 0 && len(file.Data) >= minBytes && jobs.Compressible(file.ContentType):
+		c.Header("Content-Encoding", jobs.EncodingGzip)
+		c.Header("Content-Type", file.ContentType)
+		c.Status(http.StatusOK)
+		writer := gzip.NewWriter(c.Writer)
+		_, _ = writer.Write(file.Data)
+		_ = writer.Close()
+
+	default:
+		c.Data(http.StatusOK, file.ContentType, file.Data)
+	}
+}
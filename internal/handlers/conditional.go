@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// representationETag calcula um ETag forte para o recurso na representação
+// pedida pelo cliente (com ou sem envelope, ver wantsEnvelope)
+func representationETag(c *gin.Context, resource interface{}) (string, error) {
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return "", err
+	}
+
+	hash := fnv.New64a()
+	hash.Write(data)
+	if wantsEnvelope(c) {
+		hash.Write([]byte("envelope"))
+	}
+	return fmt.Sprintf(`"%016x"`, hash.Sum64()), nil
+}
+
+// notModified envia os validadores do recurso (ETag e, quando informado,
+// Last-Modified) e responde 304 se a cópia do cliente ainda é a atual, pelo
+// If-None-Match ou, na falta dele, pelo If-Modified-Since (RFC 9110). Retorna
+// true se a resposta 304 foi enviada. O Last-Modified tem precisão de
+// segundos: se o recurso mudou no segundo corrente, outra alteração ainda
+// pode ocorrer no mesmo segundo, por isso ele só é enviado depois
+func notModified(c *gin.Context, etag string, modified time.Time) bool {
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "private, no-cache")
+
+	lastModified := modified.UTC().Truncate(time.Second)
+	sendLastModified := !modified.IsZero() && time.Now().UTC().Truncate(time.Second).After(lastModified)
+	if sendLastModified {
+		c.Header("Last-Modified", lastModified.Format(http.TimeFormat))
+	}
+
+	if match := c.GetHeader("If-None-Match"); match != "" {
+		if !etagMatches(match, etag) {
+			return false
+		}
+	} else {
+		since, err := http.ParseTime(c.GetHeader("If-Modified-Since"))
+		if err != nil || !sendLastModified || lastModified.After(since) {
+			return false
+		}
+	}
+
+	varyAccept(c)
+	c.Status(http.StatusNotModified)
+	c.Writer.WriteHeaderNow()
+	return true
+}
+
+// etagMatches compara os ETags do If-None-Match com o atual (comparação
+// fraca, como exige o RFC 9110 para o If-None-Match)
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// jsonContentType é o Content-Type das respostas JSON, o mesmo de c.JSON
+const jsonContentType = "application/json; charset=utf-8"
+
+// maxPooledBuffer limita o tamanho dos buffers devolvidos ao pool, para que
+// uma resposta grande não fique retida em memória
+const maxPooledBuffer = 1 << 20
+
+// jsonBuffers reaproveita os buffers em que as respostas são serializadas
+var jsonBuffers = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+// writeJSON escreve v como JSON, como c.JSON, mas serializa em um buffer
+// reaproveitado entre as requisições em vez de alocar o corpo a cada resposta
+func writeJSON(c *gin.Context, status int, v interface{}) {
+	buf := jsonBuffers.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer func() {
+		if buf.Cap() <= maxPooledBuffer {
+			jsonBuffers.Put(buf)
+		}
+	}()
+
+	if err := json.NewEncoder(buf).Encode(v); err != nil {
+		_ = c.Error(err)
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	// Encode termina o documento com uma quebra de linha, que c.JSON não envia
+	c.Data(status, jsonContentType, bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"callable-api/internal/errcodes"
+	"callable-api/internal/models"
+)
+
+// ErrorCodes lista os códigos de erro estáveis que a API pode retornar
+// @Summary Catálogo de códigos de erro
+// @Description Lista os valores possíveis do campo "code" das respostas de erro, com o status HTTP de cada um
+// @Tags meta
+// @Produce json
+// @Success 200 {object} models.Response{data=[]errcodes.Definition}
+// @Router /api/v1/errors [get]
+func ErrorCodes(c *gin.Context) {
+	respond(c, http.StatusOK, "Códigos de erro recuperados com sucesso", errcodes.All())
+}
+
+// NoRoute responde às requisições para caminhos inexistentes com o formato
+// padrão de erro, no lugar do 404 em texto do Gin
+func NoRoute(c *gin.Context) {
+	c.AbortWithStatusJSON(models.ErrRouteNotFound.Code, models.ErrRouteNotFound.WithDetails(c.Request.Method+" "+c.Request.URL.Path))
+}
+
+// NoMethod responde às requisições com método não suportado pelo caminho com
+// o formato padrão de erro e os métodos aceitos (os mesmos do header Allow,
+// preenchido pelo Gin). Requer gin.Engine.HandleMethodNotAllowed
+func NoMethod(c *gin.Context) {
+	var allowed []string
+	for _, method := range strings.Split(c.Writer.Header().Get("Allow"), ",") {
+		if method = strings.TrimSpace(method); method != "" {
+			allowed = append(allowed, method)
+		}
+	}
+
+	apiErr := models.ErrMethodNotAllowed.
+		WithDetails(c.Request.Method + " " + c.Request.URL.Path).
+		WithAllowedMethods(allowed)
+	c.AbortWithStatusJSON(apiErr.Code, apiErr)
+}
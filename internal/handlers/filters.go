@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"callable-api/internal/filter"
+	"callable-api/pkg/errors"
+)
+
+// itemFilterFields são os campos das listagens de itens que aceitam filtros
+var itemFilterFields = []filter.Field{
+	{Name: "created_at", Kind: filter.Time},
+	{Name: "value", Kind: filter.Number},
+	{Name: "name", Kind: filter.String},
+}
+
+// parseFilters lê da query string as condições no formato campo[op]=valor
+// (ex.: created_at[gte]=2024-01-01T00:00:00Z, value[lt]=10) para os campos
+// informados. Os demais parâmetros são ignorados; operadores desconhecidos ou
+// valores incompatíveis com o tipo do campo resultam em BadRequest. Retorna
+// nil quando nenhum filtro foi enviado
+func parseFilters(c *gin.Context, fields []filter.Field) (filter.Conditions, error) {
+	byName := make(map[string]filter.Field, len(fields))
+	for _, field := range fields {
+		byName[field.Name] = field
+	}
+
+	query := c.Request.URL.Query()
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var conditions filter.Conditions
+	for _, key := range keys {
+		name, op, ok := splitFilterKey(key)
+		if !ok {
+			continue
+		}
+		field, known := byName[name]
+		if !known {
+			continue
+		}
+		for _, value := range query[key] {
+			cond, err := filter.NewCondition(field, filter.Op(op), value)
+			if err != nil {
+				return nil, errors.NewBadRequestError("Filtro inválido: "+err.Error(), err)
+			}
+			conditions = append(conditions, cond)
+		}
+	}
+	return conditions, nil
+}
+
+// splitFilterKey separa "campo[op]" em campo e operador
+func splitFilterKey(key string) (string, string, bool) {
+	name, rest, ok := strings.Cut(key, "[")
+	if !ok || name == "" || !strings.HasSuffix(rest, "]") {
+		return "", "", false
+	}
+	return name, strings.TrimSuffix(rest, "]"), true
+}
@@ -2,40 +2,39 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"time"
 
 	"callable-api/pkg/auth"
+	"callable-api/pkg/cloud"
 	"callable-api/pkg/config"
-	"callable-api/pkg/logger"
-	"callable-api/pkg/secrets"
-	"callable-api/pkg/storage"
+	"callable-api/pkg/retry"
 )
 
 // GCPDemoHandler demonstra a integração com GCP
 type GCPDemoHandler struct {
 	config      *config.Config
-	logger      logger.Logger
-	secretMgr   secrets.SecretManager
-	storage     *storage.CloudStorage
+	logger      cloud.Logger
+	secretMgr   cloud.Secrets
+	storage     cloud.Storage
 	jwtProvider *auth.SecretProvider
 }
 
 // NewGCPDemoHandler cria um novo handler de demonstração
-func NewGCPDemoHandler(
-	cfg *config.Config,
-	log logger.Logger,
-	secretMgr secrets.SecretManager,
-	storage *storage.CloudStorage,
-) *GCPDemoHandler {
+func NewGCPDemoHandler(cfg *config.Config, gcp cloud.Services) *GCPDemoHandler {
+	log := gcp.Logger
+	if log == nil {
+		log = cloud.LocalLogger()
+	}
 	return &GCPDemoHandler{
 		config:      cfg,
 		logger:      log,
-		secretMgr:   secretMgr,
-		storage:     storage,
-		jwtProvider: auth.NewSecretProvider(cfg, secretMgr, log),
+		secretMgr:   gcp.Secrets,
+		storage:     gcp.Storage,
+		jwtProvider: auth.NewSecretProvider(cfg, gcp.Secrets, gcp.Logger),
 	}
 }
 
@@ -61,7 +60,7 @@ func (h *GCPDemoHandler) TestIntegration(w http.ResponseWriter, r *http.Request)
 	}
 
 	// 2. Testar Secret Manager (com fallback)
-	jwtSecret, err := h.jwtProvider.GetJWTSecret(ctx)
+	jwtSecret, err := retry.DoValue(ctx, retry.DefaultPolicy(), h.jwtProvider.GetJWTSecret)
 	if err != nil {
 		h.logger.Error("Falha no teste de Secret Manager", err)
 		response["tests"].(map[string]interface{})["secret_manager"] = map[string]interface{}{
@@ -91,7 +90,9 @@ func (h *GCPDemoHandler) TestIntegration(w http.ResponseWriter, r *http.Request)
 		testData := []byte("Teste de integração com Cloud Storage - " + time.Now().Format(time.RFC3339))
 		objectName := fmt.Sprintf("demo/test-%s.txt", time.Now().Format("20060102-150405"))
 
-		err := h.storage.UploadFile(ctx, objectName, bytes.NewReader(testData))
+		err := retry.Do(ctx, retry.DefaultPolicy(), func(ctx context.Context) error {
+			return h.storage.UploadFile(ctx, objectName, bytes.NewReader(testData))
+		})
 		if err != nil {
 			h.logger.Error("Erro no upload para Cloud Storage", err)
 			response["tests"].(map[string]interface{})["storage"] = map[string]interface{}{
@@ -100,7 +101,9 @@ func (h *GCPDemoHandler) TestIntegration(w http.ResponseWriter, r *http.Request)
 				"error":   err.Error(),
 			}
 		} else {
-			signedURL, urlErr := h.storage.GetSignedURL(ctx, objectName, 15*time.Minute)
+			signedURL, urlErr := retry.DoValue(ctx, retry.DefaultPolicy(), func(ctx context.Context) (string, error) {
+				return h.storage.GetSignedURL(ctx, objectName, 15*time.Minute)
+			})
 			signedURLStatus := "success"
 			signedURLMsg := ""
 
@@ -1,106 +1,215 @@
 package handlers
 
 import (
+	"context"
+	stderrors "errors"
 	"net/http"
 	"strconv"
+	"time"
 	"github.com/gin-gonic/gin"
+	"callable-api/internal/errcodes"
+	"callable-api/internal/filter"
 	"callable-api/internal/models"
+	"callable-api/internal/pagination"
+	"callable-api/internal/routes"
+	"callable-api/internal/service"
 	"callable-api/pkg/errors"
 )
 
+// defaultHandlerTimeout é o prazo padrão aplicado a cada requisição de itens
+const defaultHandlerTimeout = 10 * time.Second
+
 // ItemServiceInterface define os métodos que o handler espera do serviço de itens
 type ItemServiceInterface interface {
-	GetItems(page, limit int) ([]models.Item, int, error)
-	GetItemByID(id string) (*models.Item, error)
-	CreateItem(input *models.InputData) (*models.Item, error)
+	GetItems(ctx context.Context, viewer models.Viewer, page, limit int, conditions ...filter.Condition) ([]models.Item, int, error)
+	GetItemByID(ctx context.Context, viewer models.Viewer, id string) (*models.Item, error)
+	CreateItem(ctx context.Context, viewer models.Viewer, input *models.InputData) (*models.Item, error)
+	UpdateItem(ctx context.Context, viewer models.Viewer, id string, input *models.InputData) (*models.Item, error)
+	SearchItems(ctx context.Context, viewer models.Viewer, query string, page, limit int) ([]models.Item, int, error)
+	ShareItem(ctx context.Context, viewer models.Viewer, id string, input *models.ShareItemInput) (*models.ItemShare, error)
+	ListShares(ctx context.Context, viewer models.Viewer, id string) ([]models.ItemShare, error)
+	RevokeShare(ctx context.Context, viewer models.Viewer, id, shareID string) error
 }
 
 // ItemHandler gerencia as requisições HTTP relacionadas a itens
 type ItemHandler struct {
-	itemService ItemServiceInterface
+	itemService    ItemServiceInterface
+	handlerTimeout time.Duration
+	pagination     pagination.Config
+	jobs           JobScheduler
+	createMode     CreateMode
+	bulkLimits     ArrayLimits
 }
 
 // NewItemHandler cria uma nova instância de ItemHandler
 func NewItemHandler(itemService ItemServiceInterface) *ItemHandler {
 	return &ItemHandler{
-		itemService: itemService,
+		itemService:    itemService,
+		handlerTimeout: defaultHandlerTimeout,
+		pagination:     pagination.DefaultConfig(),
+		createMode:     CreateModeSync,
+		bulkLimits:     DefaultArrayLimits(),
 	}
 }
 
-// GetData retorna uma lista paginada de itens
-// (Mantendo a assinatura original para compatibilidade com swagger)
+// WithTimeout define o prazo máximo de cada requisição (0 desativa o prazo)
+func (h *ItemHandler) WithTimeout(timeout time.Duration) *ItemHandler {
+	h.handlerTimeout = timeout
+	return h
+}
+
+// WithPagination define a política de paginação das listagens
+func (h *ItemHandler) WithPagination(cfg pagination.Config) *ItemHandler {
+	h.pagination = cfg
+	return h
+}
+
+// requestContext deriva do contexto da requisição HTTP um contexto com o prazo do handler
+func (h *ItemHandler) requestContext(c *gin.Context) (context.Context, context.CancelFunc) {
+	if h.handlerTimeout <= 0 {
+		return context.WithCancel(c.Request.Context())
+	}
+	return context.WithTimeout(c.Request.Context(), h.handlerTimeout)
+}
+
+// handleError responde com o erro e seu código do catálogo (408 quando o prazo
+// da requisição expirou). Os overrides trocam o código genérico de um tipo de
+// erro pelo código específico do handler
+func handleError(c *gin.Context, err error, overrides ...errcodes.Override) {
+	errcodes.Respond(c, err, overrides...)
+}
+
+// viewerFrom retorna o usuário autenticado da requisição (ou um cliente
+// anônimo nas rotas de autenticação opcional)
+func viewerFrom(c *gin.Context) models.Viewer {
+	return models.Viewer{
+		UserID: c.GetString("userID"),
+		Role:   c.GetString("userRole"),
+		OrgID:  c.GetString("orgID"),
+	}
+}
+
+// GetData retorna uma lista paginada de itens, opcionalmente filtrada por
+// created_at, value e name com operadores (ex.: created_at[gte]=..., value[lt]=10)
+// (Mantendo a assinatura original para compatibilidade com swagger). Os
+// clientes anônimos do catálogo recebem apenas os campos públicos
 func (h *ItemHandler) GetData(c *gin.Context) {
-	// Parse query parameters for pagination
-	pageStr := c.DefaultQuery("page", "1")
-	limitStr := c.DefaultQuery("limit", "10")
+	p := h.pagination.Parse(c)
 	
-	page, err := strconv.Atoi(pageStr)
-	if err != nil || page < 1 {
-		page = 1
+	conditions, err := parseFilters(c, itemFilterFields)
+	if err != nil {
+		handleError(c, err, errcodes.When(errcodes.TypeBadRequest, errcodes.InvalidFilter))
+		return
 	}
 	
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit < 1 || limit > 100 {
-		limit = 10
+	ctx, cancel := h.requestContext(c)
+	defer cancel()
+	
+	items, total, err := h.itemService.GetItems(ctx, viewerFrom(c), p.Page, p.Limit, conditions...)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	if routes.Anonymous(c) {
+		for i := range items {
+			items[i] = items[i].Catalog()
+		}
 	}
 	
-	items, total, err := h.itemService.GetItems(page, limit)
+	respondList(c, "Data retrieved successfully", items, p, total)
+}
+
+// SearchData busca itens por texto completo, ordenados por relevância
+func (h *ItemHandler) SearchData(c *gin.Context) {
+	p := h.pagination.Parse(c)
+	
+	ctx, cancel := h.requestContext(c)
+	defer cancel()
+	
+	items, total, err := h.itemService.SearchItems(ctx, viewerFrom(c), c.Query("q"), p.Page, p.Limit)
 	if err != nil {
-		errors.HandleErrors(c, err)
+		handleError(c, err, errcodes.When(errcodes.TypeBadRequest, errcodes.SearchQueryRequired))
 		return
 	}
 	
-	c.JSON(http.StatusOK, models.Response{
-		Status:  "success",
-		Message: "Data retrieved successfully",
-		Data: map[string]interface{}{
-			"items": items,
-			"meta": map[string]interface{}{
-				"page":  page,
-				"limit": limit,
-				"total": total,
-			},
-		},
-	})
+	respondList(c, "Data retrieved successfully", items, p, total)
 }
 
-// GetDataById retorna um item específico pelo ID
+// GetDataById retorna um item específico pelo ID (apenas os campos públicos
+// para os clientes anônimos do catálogo)
 func (h *ItemHandler) GetDataById(c *gin.Context) {
 	id := c.Param("id")
 	
-	item, err := h.itemService.GetItemByID(id)
+	ctx, cancel := h.requestContext(c)
+	defer cancel()
+	
+	item, err := h.itemService.GetItemByID(ctx, viewerFrom(c), id)
 	if err != nil {
-		errors.HandleErrors(c, err)
+		handleError(c, err, itemNotFound)
 		return
 	}
+	if routes.Anonymous(c) {
+		catalog := item.Catalog()
+		item = &catalog
+	}
 	
-	c.JSON(http.StatusOK, models.Response{
-		Status:  "success",
-		Message: "Data retrieved successfully",
-		Data:    item,
-	})
+	respond(c, http.StatusOK, "Data retrieved successfully", item)
 }
 
-// PostData cria um novo item
+// PostData cria um novo item. Com ?force=true, administradores ignoram a
+// verificação de itens duplicados. Com Prefer: respond-async (ou no modo
+// assíncrono configurado), responde 202 com o job que criará o item. Com
+// ?run_at ou ?delay, o job é agendado para o horário pedido
 func (h *ItemHandler) PostData(c *gin.Context) {
 	var input models.InputData
 	
-	if err := c.ShouldBindJSON(&input); err != nil {
-		errors.HandleErrors(c, errors.NewBadRequestError("Invalid input data", err))
+	if !bindJSON(c, &input) {
 		return
 	}
+	input.Force, _ = strconv.ParseBool(c.Query("force"))
 	
-	item, err := h.itemService.CreateItem(&input)
+	schedule, err := parseRunAt(c)
+	if err == nil && schedule != nil && h.jobs == nil {
+		err = errors.NewBadRequestError("Agendamento de itens indisponível", nil)
+	}
 	if err != nil {
-		errors.HandleErrors(c, err)
+		handleError(c, err, errcodes.When(errcodes.TypeBadRequest, errcodes.InvalidRunAt))
 		return
 	}
 	
-	c.JSON(http.StatusCreated, models.Response{
-		Status:  "success",
-		Message: "Data created successfully",
-		Data:    item,
-	})
+	if schedule != nil || h.wantsAsync(c) {
+		h.createAsync(c, &input, schedule...)
+		return
+	}
+	
+	ctx, cancel := h.requestContext(c)
+	defer cancel()
+	
+	item, err := h.itemService.CreateItem(ctx, viewerFrom(c), &input)
+	if err != nil {
+		respondDuplicate(c, err)
+		return
+	}
+	
+	respond(c, http.StatusCreated, "Data created successfully", item)
+}
+
+// respondDuplicate responde ao erro de criação, apontando o item existente
+// (header Location e campo resource) quando o novo item é duplicado
+func respondDuplicate(c *gin.Context, err error) {
+	var duplicate *service.DuplicateItemError
+	if !stderrors.As(err, &duplicate) || duplicate.ItemID == "" {
+		handleError(c, err, errcodes.WhenCause(service.ErrDuplicateItem, errcodes.DuplicateItem))
+		return
+	}
+	
+	path := "/api/v1/data/" + duplicate.ItemID
+	c.Header("Location", path)
+	c.AbortWithStatusJSON(http.StatusConflict, models.APIError{
+		Status:    "error",
+		ErrorCode: errcodes.DuplicateItem,
+		Message:   "Já existe um item equivalente",
+	}.WithResource(path))
 }
 
 // HealthCheck responde com informações de status da API
@@ -1,18 +1,38 @@
 package handlers_test
 
 import (
+    "bufio"
     "bytes"
+    "compress/gzip"
+    "context"
     "encoding/json"
+    "errors"
+    "io"
     "net/http"
     "net/http/httptest"
+    "strings"
     "testing"
+    "time"
 
     "github.com/gin-gonic/gin"
     "github.com/stretchr/testify/assert"
     "github.com/stretchr/testify/mock"
 
+    "callable-api/internal/events"
+    "callable-api/internal/filter"
     "callable-api/internal/handlers"
+    "callable-api/internal/health"
+    "callable-api/internal/jobs"
     "callable-api/internal/models"
+    "callable-api/internal/pagination"
+    "callable-api/internal/repository/testsupport"
+    "callable-api/internal/routes"
+    "callable-api/internal/service"
+    "callable-api/internal/streams"
+    "callable-api/pkg/auth"
+    "callable-api/pkg/config"
+    apperrors "callable-api/pkg/errors"
+    "callable-api/pkg/mail"
 )
 
 // Mock do ItemService implementando a interface ItemServiceInterface
@@ -23,27 +43,61 @@ type MockItemService struct {
 // Verificação de conformidade com a interface
 var _ handlers.ItemServiceInterface = (*MockItemService)(nil)
 
-func (m *MockItemService) GetItems(page, limit int) ([]models.Item, int, error) {
-    args := m.Called(page, limit)
+func (m *MockItemService) GetItems(ctx context.Context, viewer models.Viewer, page, limit int, conditions ...filter.Condition) ([]models.Item, int, error) {
+    arguments := []interface{}{ctx, viewer, page, limit}
+    if len(conditions) > 0 {
+        arguments = append(arguments, filter.Conditions(conditions))
+    }
+    args := m.Called(arguments...)
     return args.Get(0).([]models.Item), args.Int(1), args.Error(2)
 }
 
-func (m *MockItemService) GetItemByID(id string) (*models.Item, error) {
-    args := m.Called(id)
+func (m *MockItemService) GetItemByID(ctx context.Context, viewer models.Viewer, id string) (*models.Item, error) {
+    args := m.Called(ctx, viewer, id)
+    if args.Get(0) == nil {
+        return nil, args.Error(1)
+    }
+    return args.Get(0).(*models.Item), args.Error(1)
+}
+
+func (m *MockItemService) CreateItem(ctx context.Context, viewer models.Viewer, input *models.InputData) (*models.Item, error) {
+    args := m.Called(ctx, viewer, input)
     if args.Get(0) == nil {
         return nil, args.Error(1)
     }
     return args.Get(0).(*models.Item), args.Error(1)
 }
 
-func (m *MockItemService) CreateItem(input *models.InputData) (*models.Item, error) {
-    args := m.Called(input)
+func (m *MockItemService) UpdateItem(ctx context.Context, viewer models.Viewer, id string, input *models.InputData) (*models.Item, error) {
+    args := m.Called(ctx, viewer, id, input)
     if args.Get(0) == nil {
         return nil, args.Error(1)
     }
     return args.Get(0).(*models.Item), args.Error(1)
 }
 
+func (m *MockItemService) ShareItem(ctx context.Context, viewer models.Viewer, id string, input *models.ShareItemInput) (*models.ItemShare, error) {
+    args := m.Called(ctx, viewer, id, input)
+    if args.Get(0) == nil {
+        return nil, args.Error(1)
+    }
+    return args.Get(0).(*models.ItemShare), args.Error(1)
+}
+
+func (m *MockItemService) ListShares(ctx context.Context, viewer models.Viewer, id string) ([]models.ItemShare, error) {
+    args := m.Called(ctx, viewer, id)
+    return args.Get(0).([]models.ItemShare), args.Error(1)
+}
+
+func (m *MockItemService) RevokeShare(ctx context.Context, viewer models.Viewer, id, shareID string) error {
+    return m.Called(ctx, viewer, id, shareID).Error(0)
+}
+
+func (m *MockItemService) SearchItems(ctx context.Context, viewer models.Viewer, query string, page, limit int) ([]models.Item, int, error) {
+    args := m.Called(ctx, viewer, query, page, limit)
+    return args.Get(0).([]models.Item), args.Int(1), args.Error(2)
+}
+
 func TestHealthCheck(t *testing.T) {
     // Set Gin to test mode
     gin.SetMode(gin.TestMode)
@@ -73,6 +127,55 @@ func TestHealthCheck(t *testing.T) {
     assert.Equal(t, "Callable API is up and running", response["message"])
 }
 
+func TestHealthCheckVerbose(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+
+    cfg := &config.Config{
+        JWTSecret:                "test-secret",
+        JWTExpirationMinutes:     15,
+        JWTRefreshExpirationDays: 7,
+    }
+    handler := handlers.NewHealthHandler(cfg,
+        health.NewCheck("search", func(ctx context.Context) error { return nil }),
+        health.NewCheck("secrets", func(ctx context.Context) error { return errors.New("timeout") }),
+    )
+
+    r := gin.New()
+    r.GET("/health", handler.Check)
+
+    serve := func(url string, user *models.User) *httptest.ResponseRecorder {
+        req, _ := http.NewRequest(http.MethodGet, url, nil)
+        if user != nil {
+            tokens, err := auth.GenerateTokenPair(user, cfg)
+            assert.NoError(t, err)
+            req.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
+        }
+        w := httptest.NewRecorder()
+        r.ServeHTTP(w, req)
+        return w
+    }
+
+    // Sem verbose a resposta continua estática
+    w := serve("/health", nil)
+    assert.Equal(t, http.StatusOK, w.Code)
+    assert.NotContains(t, w.Body.String(), "dependencies")
+
+    // Detalhes exigem token de administrador
+    assert.Equal(t, http.StatusUnauthorized, serve("/health?verbose=true", nil).Code)
+    assert.Equal(t, http.StatusForbidden, serve("/health?verbose=true", &models.User{ID: "1", Role: "user"}).Code)
+
+    w = serve("/health?verbose=true", &models.User{ID: "2", Role: "admin"})
+    assert.Equal(t, http.StatusOK, w.Code)
+
+    var report models.HealthReport
+    err := json.Unmarshal(w.Body.Bytes(), &report)
+    assert.NoError(t, err)
+    assert.Equal(t, "degraded", report.Status)
+    assert.Len(t, report.Dependencies, 2)
+    assert.Equal(t, "down", report.Dependencies[1].Status)
+    assert.NotEmpty(t, report.Version.GoVersion)
+}
+
 func TestGetData(t *testing.T) {
     // Set Gin to test mode
     gin.SetMode(gin.TestMode)
@@ -85,7 +188,7 @@ func TestGetData(t *testing.T) {
         {ID: "1", Name: "Item 1", Value: "Value 1"},
         {ID: "2", Name: "Item 2", Value: "Value 2"},
     }
-    mockService.On("GetItems", 1, 10).Return(items, 2, nil)
+    mockService.On("GetItems", mock.Anything, mock.Anything, 1, 10).Return(items, 2, nil)
     
     // Criar handler com mock
     handler := handlers.NewItemHandler(mockService)
@@ -105,6 +208,9 @@ func TestGetData(t *testing.T) {
     // Verify the status code
     assert.Equal(t, http.StatusOK, w.Code)
 
+    // Página única: o header Link aponta apenas para a primeira e a última
+    assert.Equal(t, `</api/v1/data?limit=10&page=1>; rel="first", </api/v1/data?limit=10&page=1>; rel="last"`, w.Header().Get("Link"))
+
     // Verify the response body
     var response models.Response
     err = json.Unmarshal(w.Body.Bytes(), &response)
@@ -119,6 +225,72 @@ func TestGetData(t *testing.T) {
     mockService.AssertExpectations(t)
 }
 
+func TestGetData_PaginationPolicy(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+
+    // O limite solicitado acima do máximo configurado deve ser reduzido ao máximo
+    mockService := new(MockItemService)
+    items := []models.Item{{ID: "1", Name: "Item 1", Value: "Value 1"}}
+    mockService.On("GetItems", mock.Anything, mock.Anything, 2, 20).Return(items, 21, nil)
+
+    handler := handlers.NewItemHandler(mockService).
+        WithPagination(pagination.Config{DefaultPageSize: 5, MaxPageSize: 20})
+
+    r := gin.New()
+    r.GET("/api/v1/data", handler.GetData)
+
+    req, err := http.NewRequest(http.MethodGet, "/api/v1/data?page=2&limit=500", nil)
+    assert.NoError(t, err)
+
+    w := httptest.NewRecorder()
+    r.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+
+    var response models.ListResponse
+    err = json.Unmarshal(w.Body.Bytes(), &response)
+    assert.NoError(t, err)
+    assert.Equal(t, "success", response.Status)
+    assert.Equal(t, 2, response.Page)
+    assert.Equal(t, 20, response.PageSize)
+    assert.Equal(t, 21, response.TotalRows)
+    assert.Equal(t, 2, response.GetTotalPages())
+
+    mockService.AssertExpectations(t)
+}
+
+func TestGetData_Filters(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+
+    // Os filtros com operadores chegam ao serviço como condições tipadas
+    mockService := new(MockItemService)
+    items := []models.Item{{ID: "1", Name: "Item 1", Value: "5"}}
+    from, _ := filter.NewCondition(filter.Field{Name: "created_at", Kind: filter.Time}, filter.Gte, "2024-01-01T00:00:00Z")
+    below, _ := filter.NewCondition(filter.Field{Name: "value", Kind: filter.Number}, filter.Lt, "10")
+    mockService.On("GetItems", mock.Anything, mock.Anything, 1, 10, filter.Conditions{from, below}).Return(items, 1, nil)
+
+    r := gin.New()
+    r.GET("/api/v1/data", handlers.NewItemHandler(mockService).GetData)
+
+    get := func(query string) *httptest.ResponseRecorder {
+        req, _ := http.NewRequest(http.MethodGet, "/api/v1/data?"+query, nil)
+        w := httptest.NewRecorder()
+        r.ServeHTTP(w, req)
+        return w
+    }
+
+    w := get("created_at%5Bgte%5D=2024-01-01T00:00:00Z&value%5Blt%5D=10&page=1")
+    assert.Equal(t, http.StatusOK, w.Code)
+    mockService.AssertExpectations(t)
+
+    // Operador desconhecido ou valor incompatível com o campo
+    for _, query := range []string{"value%5Blike%5D=1", "value%5Blt%5D=dez", "created_at%5Bgte%5D=2024-01-01"} {
+        w = get(query)
+        assert.Equal(t, http.StatusBadRequest, w.Code, query)
+        assert.Contains(t, w.Body.String(), "INVALID_FILTER", query)
+    }
+}
+
 func TestGetDataById(t *testing.T) {
     // Set Gin to test mode
     gin.SetMode(gin.TestMode)
@@ -128,7 +300,7 @@ func TestGetDataById(t *testing.T) {
     
     // Configurar expectativa do mock
     item := &models.Item{ID: "123", Name: "Test Item", Value: "Test Value"}
-    mockService.On("GetItemByID", "123").Return(item, nil)
+    mockService.On("GetItemByID", mock.Anything, mock.Anything, "123").Return(item, nil)
     
     // Criar handler com mock
     handler := handlers.NewItemHandler(mockService)
@@ -187,7 +359,7 @@ func TestPostData(t *testing.T) {
     }
     
     // Configurar expectativa do mock
-    mockService.On("CreateItem", mock.AnythingOfType("*models.InputData")).Return(createdItem, nil)
+    mockService.On("CreateItem", mock.Anything, mock.Anything, mock.AnythingOfType("*models.InputData")).Return(createdItem, nil)
     
     // Criar handler com mock
     handler := handlers.NewItemHandler(mockService)
@@ -256,7 +428,839 @@ func TestPostDataInvalid(t *testing.T) {
 
     // Verify the error was returned correctly
     assert.Equal(t, http.StatusBadRequest, w.Code)
-    
+
+    // Os erros de binding devem vir estruturados por campo, sem a mensagem bruta do validador
+    var apiErr models.APIError
+    err = json.Unmarshal(w.Body.Bytes(), &apiErr)
+    assert.NoError(t, err)
+    assert.NotContains(t, w.Body.String(), "Key: 'InputData")
+    assert.Equal(t, "Campo obrigatório", apiErr.FieldErrors["name"])
+
+    rules := map[string]string{}
+    for _, v := range apiErr.Violations {
+        rules[v.Field] = v.Rule
+    }
+    assert.Equal(t, "required", rules["name"])
+    assert.Equal(t, "required", rules["value"])
+    assert.Equal(t, "required", rules["email"])
+
+    // JSON malformado é reportado no campo "request"
+    req, _ = http.NewRequest(http.MethodPost, "/api/v1/data", bytes.NewBufferString(`{"name":`))
+    req.Header.Set("Content-Type", "application/json")
+    w = httptest.NewRecorder()
+    r.ServeHTTP(w, req)
+    assert.Equal(t, http.StatusBadRequest, w.Code)
+    assert.Contains(t, w.Body.String(), `"field":"request"`)
+
     // Não verificamos o mock aqui porque esperamos que a validação falhe
     // antes mesmo de chamar o serviço
-}
\ No newline at end of file
+}
+
+func TestPostData_Strict(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+
+    mockService := new(MockItemService)
+    mockService.On("CreateItem", mock.Anything, mock.Anything, mock.AnythingOfType("*models.InputData")).
+        Return(&models.Item{ID: "new-id", Name: "Test Item"}, nil)
+    handler := handlers.NewItemHandler(mockService)
+
+    // A mesma rota declarada com e sem a validação estrita
+    registry := routes.New(nil)
+    registry.Add(
+        routes.Route{Method: http.MethodPost, Path: "/strict", Handler: handler.PostData, RateClass: routes.RateUnlimited, Strict: true},
+        routes.Route{Method: http.MethodPost, Path: "/lenient", Handler: handler.PostData, RateClass: routes.RateUnlimited},
+    )
+    r := gin.New()
+    assert.NoError(t, registry.Mount(r))
+
+    post := func(path, body string) *httptest.ResponseRecorder {
+        req, _ := http.NewRequest(http.MethodPost, path, bytes.NewBufferString(body))
+        req.Header.Set("Content-Type", "application/json")
+        w := httptest.NewRecorder()
+        r.ServeHTTP(w, req)
+        return w
+    }
+
+    // Campo com erro de digitação (descripton) é rejeitado apenas na rota estrita
+    typo := `{"name":"Test Item","value":"ABC123","email":"test@example.com","descripton":"x"}`
+    w := post("/strict", typo)
+    assert.Equal(t, http.StatusBadRequest, w.Code)
+    var apiErr models.APIError
+    assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &apiErr))
+    assert.Equal(t, []models.FieldViolation{{Field: "descripton", Rule: "unknown_field", Message: "Campo não reconhecido"}}, apiErr.Violations)
+
+    assert.Equal(t, http.StatusCreated, post("/lenient", typo).Code)
+
+    // As regras das tags continuam valendo na rota estrita
+    w = post("/strict", `{"name":"","value":"ABC123","email":"test@example.com"}`)
+    assert.Equal(t, http.StatusBadRequest, w.Code)
+    assert.Contains(t, w.Body.String(), `"rule":"required"`)
+
+    assert.Equal(t, http.StatusCreated, post("/strict", `{"name":"Test Item","value":"ABC123","email":"test@example.com"}`).Code)
+}
+
+func TestGetDataTimeout(t *testing.T) {
+    // Set Gin to test mode
+    gin.SetMode(gin.TestMode)
+
+    // Criar mock do serviço que espera o prazo do contexto expirar
+    mockService := new(MockItemService)
+    mockService.On("GetItems", mock.Anything, mock.Anything, 1, 10).
+        Run(func(args mock.Arguments) {
+            ctx := args.Get(0).(context.Context)
+            <-ctx.Done()
+        }).
+        Return([]models.Item{}, 0, context.DeadlineExceeded)
+
+    // Criar handler com prazo curto
+    handler := handlers.NewItemHandler(mockService).WithTimeout(10 * time.Millisecond)
+
+    // Create a test router
+    r := gin.New()
+    r.GET("/api/v1/data", handler.GetData)
+
+    // Create a test request
+    req, err := http.NewRequest(http.MethodGet, "/api/v1/data", nil)
+    assert.NoError(t, err)
+
+    // Record the response
+    w := httptest.NewRecorder()
+    r.ServeHTTP(w, req)
+
+    // O prazo expirado deve resultar em 408
+    assert.Equal(t, http.StatusRequestTimeout, w.Code)
+
+    var apiErr models.APIError
+    err = json.Unmarshal(w.Body.Bytes(), &apiErr)
+    assert.NoError(t, err)
+    assert.Equal(t, "error", apiErr.Status)
+    assert.Equal(t, "REQUEST_TIMEOUT", apiErr.ErrorCode)
+
+    mockService.AssertExpectations(t)
+}
+
+func TestSearchData(t *testing.T) {
+    // Set Gin to test mode
+    gin.SetMode(gin.TestMode)
+
+    // Criar mock do serviço
+    mockService := new(MockItemService)
+    items := []models.Item{{ID: "7", Name: "Item 7", Value: "Value 7"}}
+    mockService.On("SearchItems", mock.Anything, mock.Anything, "item 7", 1, 10).Return(items, 1, nil)
+
+    handler := handlers.NewItemHandler(mockService)
+
+    // A rota estática de busca deve conviver com a rota parametrizada por ID
+    r := gin.New()
+    r.GET("/api/v1/data/search", handler.SearchData)
+    r.GET("/api/v1/data/:id", handler.GetDataById)
+
+    req, err := http.NewRequest(http.MethodGet, "/api/v1/data/search?q=item+7", nil)
+    assert.NoError(t, err)
+
+    w := httptest.NewRecorder()
+    r.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+
+    var response models.Response
+    err = json.Unmarshal(w.Body.Bytes(), &response)
+    assert.NoError(t, err)
+    assert.Equal(t, "success", response.Status)
+
+    mockService.AssertExpectations(t)
+}
+
+func TestGetDataById_ErrorCode(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+
+    mockService := new(MockItemService)
+    mockService.On("GetItemByID", mock.Anything, mock.Anything, "999").
+        Return(nil, apperrors.NewNotFoundError("Item não encontrado", nil))
+
+    handler := handlers.NewItemHandler(mockService)
+
+    r := gin.New()
+    r.GET("/api/v1/data/:id", handler.GetDataById)
+
+    req, err := http.NewRequest(http.MethodGet, "/api/v1/data/999", nil)
+    assert.NoError(t, err)
+
+    w := httptest.NewRecorder()
+    r.ServeHTTP(w, req)
+
+    // O código genérico NOT_FOUND deve ser trocado pelo código específico do handler
+    assert.Equal(t, http.StatusNotFound, w.Code)
+
+    var body map[string]interface{}
+    assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+    assert.Equal(t, "ITEM_NOT_FOUND", body["code"])
+
+    mockService.AssertExpectations(t)
+}
+
+func TestPostData_Duplicate(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+
+    mockService := new(MockItemService)
+    mockService.On("CreateItem", mock.Anything, mock.Anything, mock.MatchedBy(func(input *models.InputData) bool {
+        return !input.Force
+    })).Return(nil, apperrors.NewConflictError("Já existe um item equivalente", &service.DuplicateItemError{ItemID: "7"}))
+
+    handler := handlers.NewItemHandler(mockService)
+
+    r := gin.New()
+    r.POST("/api/v1/data", handler.PostData)
+
+    body := `{"name":"Test Item","value":"ABC123","email":"test@example.com"}`
+    req, err := http.NewRequest(http.MethodPost, "/api/v1/data", bytes.NewBufferString(body))
+    assert.NoError(t, err)
+    req.Header.Set("Content-Type", "application/json")
+
+    w := httptest.NewRecorder()
+    r.ServeHTTP(w, req)
+
+    // A resposta aponta o item existente
+    assert.Equal(t, http.StatusConflict, w.Code)
+    assert.Equal(t, "/api/v1/data/7", w.Header().Get("Location"))
+
+    var response map[string]interface{}
+    assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+    assert.Equal(t, "DUPLICATE_ITEM", response["code"])
+    assert.Equal(t, "/api/v1/data/7", response["resource"])
+
+    mockService.AssertExpectations(t)
+}
+
+func TestPostDataBulk(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+
+    mockService := new(MockItemService)
+    mockService.On("CreateItem", mock.Anything, mock.Anything, mock.MatchedBy(func(input *models.InputData) bool {
+        return input.Name == "Duplicado"
+    })).Return(nil, apperrors.NewConflictError("Já existe um item equivalente", &service.DuplicateItemError{ItemID: "7"}))
+    mockService.On("CreateItem", mock.Anything, mock.Anything, mock.AnythingOfType("*models.InputData")).
+        Return(&models.Item{ID: "new-id", Name: "Test Item"}, nil)
+
+    handler := handlers.NewItemHandler(mockService).WithBulkLimits(handlers.ArrayLimits{MaxBytes: 1024, MaxItems: 3})
+    registry := routes.New(nil)
+    registry.Add(routes.Route{Method: http.MethodPost, Path: "/api/v1/data/bulk", Handler: handler.PostDataBulk, RateClass: routes.RateUnlimited, Strict: true})
+    r := gin.New()
+    assert.NoError(t, registry.Mount(r))
+
+    post := func(body string) *httptest.ResponseRecorder {
+        req, _ := http.NewRequest(http.MethodPost, "/api/v1/data/bulk", bytes.NewBufferString(body))
+        req.Header.Set("Content-Type", "application/json")
+        w := httptest.NewRecorder()
+        r.ServeHTTP(w, req)
+        return w
+    }
+    item := func(name string) string {
+        return `{"name":"` + name + `","value":"ABC123","email":"test@example.com"}`
+    }
+
+    // Todos os itens criados
+    w := post("[" + item("Primeiro") + "," + item("Segundo") + "]")
+    assert.Equal(t, http.StatusCreated, w.Code)
+    var response struct {
+        Data []models.BulkItemResult `json:"data"`
+    }
+    assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+    assert.Len(t, response.Data, 2)
+    assert.Equal(t, "new-id", response.Data[1].Item.ID)
+
+    // Falhas na criação aparecem no resultado do elemento
+    w = post("[" + item("Primeiro") + "," + item("Duplicado") + "]")
+    assert.Equal(t, http.StatusMultiStatus, w.Code)
+    assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+    assert.Equal(t, http.StatusCreated, response.Data[0].Status)
+    assert.Equal(t, http.StatusConflict, response.Data[1].Status)
+    assert.Equal(t, "DUPLICATE_ITEM", response.Data[1].Code)
+    mockService.AssertNumberOfCalls(t, "CreateItem", 4)
+
+    // Um elemento inválido rejeita o lote inteiro, apontando o índice
+    w = post("[" + item("Primeiro") + `,{"name":"","value":"ABC123","email":"test@example.com"}]`)
+    assert.Equal(t, http.StatusBadRequest, w.Code)
+    var apiErr models.APIError
+    assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &apiErr))
+    assert.Equal(t, "[1].name", apiErr.Violations[0].Field)
+
+    w = post("[" + item("Primeiro") + `,{"name":"Test Item","value":"ABC123","email":"test@example.com","descripton":"x"}]`)
+    assert.Equal(t, http.StatusBadRequest, w.Code)
+    assert.Contains(t, w.Body.String(), `"field":"[1].descripton"`)
+
+    assert.Equal(t, http.StatusBadRequest, post(item("Primeiro")).Code)
+    assert.Equal(t, http.StatusBadRequest, post("[" + item("Primeiro")).Code)
+
+    // Itens demais ou corpo grande demais
+    w = post("[" + item("Item 1") + "," + item("Item 2") + "," + item("Item 3") + "," + item("Item 4") + "]")
+    assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+    assert.Contains(t, w.Body.String(), `"code":"PAYLOAD_TOO_LARGE"`)
+
+    large := `[{"name":"Test Item","value":"ABC123","email":"test@example.com","description":"` + strings.Repeat("x", 2048) + `"}]`
+    assert.Equal(t, http.StatusRequestEntityTooLarge, post(large).Code)
+
+    // Sem Content-Length, o limite vale durante a leitura
+    req, _ := http.NewRequest(http.MethodPost, "/api/v1/data/bulk", io.MultiReader(strings.NewReader(large)))
+    w = httptest.NewRecorder()
+    r.ServeHTTP(w, req)
+    assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+
+    mockService.AssertNumberOfCalls(t, "CreateItem", 4)
+}
+
+// fakeScheduler registra os jobs enfileirados sem executá-los
+type fakeScheduler struct {
+    fns  []jobs.Func
+    opts [][]jobs.Option
+}
+
+func (s *fakeScheduler) ScheduleJob(ctx context.Context, userID, jobType string, fn jobs.Func, opts ...jobs.Option) (*models.JobStatus, error) {
+    s.fns = append(s.fns, fn)
+    s.opts = append(s.opts, opts)
+    return &models.JobStatus{ID: "job-1", Type: jobType, State: models.JobStatePending, UserID: userID}, nil
+}
+
+func TestPostData_AsyncMode(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+
+    createdItem := &models.Item{ID: "new-id", Name: "Test Item"}
+    mockService := new(MockItemService)
+    mockService.On("CreateItem", mock.Anything, mock.Anything, mock.AnythingOfType("*models.InputData")).Return(createdItem, nil)
+
+    post := func(handler *handlers.ItemHandler, prefer string) *httptest.ResponseRecorder {
+        r := gin.New()
+        r.POST("/api/v1/data", handler.PostData)
+
+        body := `{"name":"Test Item","value":"ABC123","email":"test@example.com"}`
+        req, err := http.NewRequest(http.MethodPost, "/api/v1/data", bytes.NewBufferString(body))
+        assert.NoError(t, err)
+        req.Header.Set("Content-Type", "application/json")
+        if prefer != "" {
+            req.Header.Set("Prefer", prefer)
+        }
+
+        w := httptest.NewRecorder()
+        r.ServeHTTP(w, req)
+        return w
+    }
+
+    // No modo síncrono, o cliente pode pedir um job com Prefer: respond-async
+    scheduler := &fakeScheduler{}
+    handler := handlers.NewItemHandler(mockService).WithAsyncCreate(scheduler, handlers.CreateModeSync)
+    assert.Equal(t, http.StatusCreated, post(handler, "").Code)
+
+    w := post(handler, "respond-async, wait=10")
+    assert.Equal(t, http.StatusAccepted, w.Code)
+    assert.Equal(t, "/api/v1/jobs/job-1", w.Header().Get("Location"))
+    assert.Equal(t, "respond-async", w.Header().Get("Preference-Applied"))
+
+    var response struct {
+        Data models.JobStatus `json:"data"`
+    }
+    assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+    assert.Equal(t, "job-1", response.Data.ID)
+    assert.Equal(t, models.JobStatePending, response.Data.State)
+
+    // O job cria o item
+    assert.Len(t, scheduler.fns, 1)
+    result, err := scheduler.fns[0](context.Background())
+    assert.NoError(t, err)
+    assert.Equal(t, createdItem, result)
+
+    // No modo assíncrono, o cliente pode pedir o item com Prefer: return=representation
+    handler = handlers.NewItemHandler(mockService).WithAsyncCreate(scheduler, handlers.CreateModeAsync)
+    assert.Equal(t, http.StatusAccepted, post(handler, "").Code)
+    assert.Equal(t, http.StatusCreated, post(handler, "return=representation").Code)
+}
+
+func TestImportUsers(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+
+    cfg := &config.Config{JWTSecret: "test-secret", JWTExpirationMinutes: 15, JWTRefreshExpirationDays: 7}
+    authService := service.NewAuthService(testsupport.NewUserRepository(), cfg)
+    scheduler := &fakeScheduler{}
+    handler := handlers.NewAdminUserHandler(authService).WithImport(scheduler, handlers.ArrayLimits{MaxBytes: 1 << 20, MaxItems: 4})
+
+    r := gin.New()
+    r.POST("/api/v1/admin/users/import", func(c *gin.Context) {
+        c.Set("userID", "1")
+        handler.ImportUsers(c)
+    })
+    post := func(body string) *httptest.ResponseRecorder {
+        req, err := http.NewRequest(http.MethodPost, "/api/v1/admin/users/import", strings.NewReader(body))
+        assert.NoError(t, err)
+        req.Header.Set("Content-Type", "text/csv")
+        w := httptest.NewRecorder()
+        r.ServeHTTP(w, req)
+        return w
+    }
+
+    csv := "name,email,role,department\n" +
+        "Maria Silva,maria@example.com,\n" +
+        "João Souza,joao@example.com,ADMIN,TI\n" +
+        "Sem Email,invalido,user\n" +
+        "Duplicado,user@example.com,\n"
+    w := post(csv)
+    assert.Equal(t, http.StatusAccepted, w.Code)
+    assert.Equal(t, "/api/v1/jobs/job-1", w.Header().Get("Location"))
+
+    // O job cria as contas e reporta cada linha
+    assert.Len(t, scheduler.fns, 1)
+    result, err := scheduler.fns[0](context.Background())
+    assert.NoError(t, err)
+    report := result.(*models.UserImportReport)
+    assert.Equal(t, 4, report.Total)
+    assert.Equal(t, 2, report.Created)
+    assert.Equal(t, 2, report.Failed)
+    assert.Equal(t, []int{2, 3, 4, 5}, []int{report.Rows[0].Line, report.Rows[1].Line, report.Rows[2].Line, report.Rows[3].Line})
+    assert.Equal(t, models.UserImportCreated, report.Rows[1].Status)
+    assert.Equal(t, models.UserImportFailed, report.Rows[2].Status)
+    assert.Contains(t, report.Rows[2].Error, "email")
+    assert.Equal(t, "Email já está em uso", report.Rows[3].Error)
+
+    // As contas exigem a definição da senha antes do primeiro login
+    assert.Error(t, authService.CheckAccount(report.Rows[0].UserID))
+
+    // Arquivos malformados ou acima do limite são rejeitados na requisição
+    for _, body := range []string{"", "email,role\nmaria@example.com,user\n", "email,name\n", "email,name\n\"aberto,Maria\n"} {
+        w := post(body)
+        assert.Equal(t, http.StatusBadRequest, w.Code, body)
+        assert.Contains(t, w.Body.String(), "INVALID_USER_IMPORT", body)
+    }
+    assert.Equal(t, http.StatusRequestEntityTooLarge, post(csv+"Extra,extra@example.com,user\n").Code)
+    assert.Len(t, scheduler.fns, 1)
+}
+
+func TestPostData_Scheduled(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+
+    post := func(handler *handlers.ItemHandler, query string) *httptest.ResponseRecorder {
+        r := gin.New()
+        r.POST("/api/v1/data", handler.PostData)
+
+        body := `{"name":"Test Item","value":"ABC123","email":"test@example.com"}`
+        req, err := http.NewRequest(http.MethodPost, "/api/v1/data?"+query, bytes.NewBufferString(body))
+        assert.NoError(t, err)
+        req.Header.Set("Content-Type", "application/json")
+
+        w := httptest.NewRecorder()
+        r.ServeHTTP(w, req)
+        return w
+    }
+
+    mockService := new(MockItemService)
+
+    // O agendamento dispensa o header Prefer, mesmo no modo síncrono
+    scheduler := &fakeScheduler{}
+    handler := handlers.NewItemHandler(mockService).WithAsyncCreate(scheduler, handlers.CreateModeSync)
+    assert.Equal(t, http.StatusAccepted, post(handler, "delay=90m").Code)
+    assert.Equal(t, http.StatusAccepted, post(handler, "run_at=2030-01-01T00:00:00Z").Code)
+    assert.Len(t, scheduler.opts, 2)
+    assert.Len(t, scheduler.opts[0], 1)
+
+    for _, query := range []string{"run_at=amanha", "delay=-5m", "delay=1h&run_at=2030-01-01T00:00:00Z"} {
+        w := post(handler, query)
+        assert.Equal(t, http.StatusBadRequest, w.Code, query)
+        assert.Contains(t, w.Body.String(), "INVALID_RUN_AT", query)
+    }
+    assert.Len(t, scheduler.fns, 2)
+
+    // Sem jobs configurados, o agendamento não é aceito
+    w := post(handlers.NewItemHandler(mockService), "delay=1h")
+    assert.Equal(t, http.StatusBadRequest, w.Code)
+    mockService.AssertNotCalled(t, "CreateItem", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReadiness_Drain(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+
+    handler := handlers.NewHealthHandler(&config.Config{})
+    r := gin.New()
+    r.GET("/ready", handler.Ready)
+    r.GET("/health", handler.Check)
+    r.POST("/api/v1/admin/drain", handler.Drain)
+    r.POST("/api/v1/admin/undrain", handler.Undrain)
+
+    serve := func(method, url string) *httptest.ResponseRecorder {
+        req, _ := http.NewRequest(method, url, nil)
+        w := httptest.NewRecorder()
+        r.ServeHTTP(w, req)
+        return w
+    }
+
+    assert.Equal(t, http.StatusOK, serve(http.MethodGet, "/ready").Code)
+
+    // Em dreno, apenas a prontidão falha: as demais requisições são atendidas
+    w := serve(http.MethodPost, "/api/v1/admin/drain")
+    assert.Equal(t, http.StatusOK, w.Code)
+    assert.Contains(t, w.Body.String(), `"draining":true`)
+    w = serve(http.MethodGet, "/ready")
+    assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+    assert.Contains(t, w.Body.String(), "SERVER_DRAINING")
+    assert.Equal(t, http.StatusOK, serve(http.MethodGet, "/health").Code)
+
+    w = serve(http.MethodPost, "/api/v1/admin/undrain")
+    assert.Equal(t, http.StatusOK, w.Code)
+    assert.Contains(t, w.Body.String(), `"draining":false`)
+    assert.Equal(t, http.StatusOK, serve(http.MethodGet, "/ready").Code)
+}
+
+func TestGetJob_Conditional(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+
+    ctx := context.Background()
+    store := jobs.NewMemoryStore()
+    updated := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+    job := &models.JobStatus{ID: "job-1", Type: "items.create", State: models.JobStateRunning, CreatedAt: updated, UpdatedAt: updated}
+    assert.NoError(t, store.Save(ctx, job))
+
+    manager := jobs.NewManager(store, jobs.Config{Workers: 1, QueueSize: 1})
+    defer manager.Close(ctx)
+
+    r := gin.New()
+    r.GET("/api/v1/jobs/:id", handlers.NewJobHandler(manager).GetJob)
+
+    get := func(header, value string) *httptest.ResponseRecorder {
+        req, err := http.NewRequest(http.MethodGet, "/api/v1/jobs/job-1", nil)
+        assert.NoError(t, err)
+        if header != "" {
+            req.Header.Set(header, value)
+        }
+        w := httptest.NewRecorder()
+        r.ServeHTTP(w, req)
+        return w
+    }
+
+    w := get("", "")
+    assert.Equal(t, http.StatusOK, w.Code)
+    etag := w.Header().Get("ETag")
+    assert.NotEmpty(t, etag)
+    assert.Equal(t, updated.Format(http.TimeFormat), w.Header().Get("Last-Modified"))
+
+    // Enquanto o job não muda, a consulta condicional responde 304 sem corpo
+    for _, tc := range [][2]string{
+        {"If-None-Match", etag},
+        {"If-None-Match", `"outro", W/` + etag},
+        {"If-None-Match", "*"},
+        {"If-Modified-Since", updated.Format(http.TimeFormat)},
+        {"If-Modified-Since", updated.Add(time.Minute).Format(http.TimeFormat)},
+    } {
+        w := get(tc[0], tc[1])
+        assert.Equal(t, http.StatusNotModified, w.Code, tc)
+        assert.Empty(t, w.Body.String(), tc)
+        assert.Equal(t, etag, w.Header().Get("ETag"), tc)
+    }
+
+    // O If-None-Match tem precedência sobre o If-Modified-Since
+    req, _ := http.NewRequest(http.MethodGet, "/api/v1/jobs/job-1", nil)
+    req.Header.Set("If-None-Match", `"outro"`)
+    req.Header.Set("If-Modified-Since", updated.Format(http.TimeFormat))
+    w = httptest.NewRecorder()
+    r.ServeHTTP(w, req)
+    assert.Equal(t, http.StatusOK, w.Code)
+
+    assert.Equal(t, http.StatusOK, get("If-Modified-Since", updated.Add(-time.Second).Format(http.TimeFormat)).Code)
+
+    // A representação sem envelope tem outro ETag
+    bare := get("Accept", `application/json; profile="bare"`)
+    assert.NotEqual(t, etag, bare.Header().Get("ETag"))
+
+    // Uma mudança de estado invalida a cópia do cliente
+    job.State = models.JobStateCompleted
+    job.UpdatedAt = updated.Add(time.Minute)
+    assert.NoError(t, store.Save(ctx, job))
+    w = get("If-None-Match", etag)
+    assert.Equal(t, http.StatusOK, w.Code)
+    assert.NotEqual(t, etag, w.Header().Get("ETag"))
+    assert.Equal(t, http.StatusOK, get("If-Modified-Since", updated.Format(http.TimeFormat)).Code)
+}
+
+func TestJobEvents(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+
+    ctx := context.Background()
+    store := jobs.NewMemoryStore()
+    job := &models.JobStatus{ID: "job-1", Type: "items.create", State: models.JobStateRunning}
+    assert.NoError(t, store.Save(ctx, job))
+    manager := jobs.NewManager(store, jobs.Config{Workers: 1, QueueSize: 1})
+    defer manager.Close(ctx)
+
+    hub := streams.NewHub(streams.Config{ReconnectAfter: 2 * time.Second})
+    r := gin.New()
+    r.GET("/api/v1/jobs/:id/events", handlers.NewJobHandler(manager).WithStreams(hub).JobEvents)
+    server := httptest.NewServer(r)
+    defer server.Close()
+
+    // open conecta ao stream e retorna as linhas recebidas
+    open := func() (*http.Response, <-chan string) {
+        resp, err := server.Client().Get(server.URL + "/api/v1/jobs/job-1/events")
+        assert.NoError(t, err)
+        lines := make(chan string, 16)
+        go func() {
+            defer close(lines)
+            scanner := bufio.NewScanner(resp.Body)
+            for scanner.Scan() {
+                if line := scanner.Text(); line != "" {
+                    lines <- line
+                }
+            }
+        }()
+        return resp, lines
+    }
+    next := func(lines <-chan string) string {
+        select {
+        case line := <-lines:
+            return line
+        case <-time.After(5 * time.Second):
+            t.Fatal("stream sem eventos")
+            return ""
+        }
+    }
+
+    resp, lines := open()
+    defer resp.Body.Close()
+    assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+    assert.Equal(t, "retry: 2000", next(lines))
+    assert.Equal(t, "event: status", next(lines))
+    assert.Contains(t, next(lines), `"state":"running"`)
+
+    // O término do job é enviado e encerra o stream
+    job.State = models.JobStateCompleted
+    assert.NoError(t, store.Save(ctx, job))
+    envelope, err := events.NewEnvelope(ctx, events.JobCompleted{JobID: "job-1"})
+    assert.NoError(t, err)
+    hub.Publish(ctx, envelope)
+    assert.Equal(t, "event: status", next(lines))
+    assert.Contains(t, next(lines), `"state":"completed"`)
+    _, open2 := <-lines
+    assert.False(t, open2)
+
+    // No encerramento do servidor, as conexões abertas são avisadas
+    job.State = models.JobStateRunning
+    assert.NoError(t, store.Save(ctx, job))
+    resp, lines = open()
+    defer resp.Body.Close()
+    next(lines)
+    next(lines)
+    next(lines)
+    hub.Drain()
+    assert.Equal(t, "retry: 2000", next(lines))
+    assert.Equal(t, "event: server.draining", next(lines))
+    assert.Contains(t, next(lines), `"reconnect_after_ms":2000`)
+    _, open2 = <-lines
+    assert.False(t, open2)
+
+    // e as novas conexões, recusadas
+    resp, err = server.Client().Get(server.URL + "/api/v1/jobs/job-1/events")
+    assert.NoError(t, err)
+    defer resp.Body.Close()
+    assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+    assert.Equal(t, "2", resp.Header.Get("Retry-After"))
+    body, _ := io.ReadAll(resp.Body)
+    assert.Contains(t, string(body), "SERVER_DRAINING")
+}
+
+func TestErrorCodes(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+
+    r := gin.New()
+    r.GET("/api/v1/errors", handlers.ErrorCodes)
+
+    req, err := http.NewRequest(http.MethodGet, "/api/v1/errors", nil)
+    assert.NoError(t, err)
+
+    w := httptest.NewRecorder()
+    r.ServeHTTP(w, req)
+
+    assert.Equal(t, http.StatusOK, w.Code)
+
+    var response struct {
+        Data []struct {
+            Code   string `json:"code"`
+            Status int    `json:"status"`
+        } `json:"data"`
+    }
+    assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+    statuses := make(map[string]int, len(response.Data))
+    for _, def := range response.Data {
+        statuses[def.Code] = def.Status
+    }
+    assert.Equal(t, http.StatusNotFound, statuses["ITEM_NOT_FOUND"])
+    assert.Equal(t, http.StatusConflict, statuses["EMAIL_IN_USE"])
+}
+
+func TestResponseEnvelope(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+
+    mockService := new(MockItemService)
+    item := &models.Item{ID: "1", Name: "Item 1", Value: "V1"}
+    mockService.On("GetItemByID", mock.Anything, mock.Anything, "1").Return(item, nil)
+
+    r := gin.New()
+    r.GET("/api/v1/data/:id", handlers.NewItemHandler(mockService).GetDataById)
+
+    get := func(query, accept string) *httptest.ResponseRecorder {
+        req, err := http.NewRequest(http.MethodGet, "/api/v1/data/1"+query, nil)
+        assert.NoError(t, err)
+        if accept != "" {
+            req.Header.Set("Accept", accept)
+        }
+        w := httptest.NewRecorder()
+        r.ServeHTTP(w, req)
+        return w
+    }
+
+    // Por padrão, o recurso vem dentro do envelope
+    var envelope models.Response
+    assert.NoError(t, json.Unmarshal(get("", "").Body.Bytes(), &envelope))
+    assert.Equal(t, "success", envelope.Status)
+    assert.NotNil(t, envelope.Data)
+
+    // Com ?envelope=false ou o profile "bare", apenas o recurso
+    for _, w := range []*httptest.ResponseRecorder{
+        get("?envelope=false", ""),
+        get("", `application/json; profile="bare"`),
+    } {
+        assert.Equal(t, http.StatusOK, w.Code)
+        var bare models.Item
+        assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &bare))
+        assert.Equal(t, *item, bare)
+    }
+}
+
+func TestNoRouteAndNoMethod(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+
+    r := gin.New()
+    r.HandleMethodNotAllowed = true
+    r.NoRoute(handlers.NoRoute)
+    r.NoMethod(handlers.NoMethod)
+    r.GET("/api/v1/data", func(c *gin.Context) { c.Status(http.StatusOK) })
+    r.POST("/api/v1/data", func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+    // Caminho inexistente
+    w := httptest.NewRecorder()
+    r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/nada", nil))
+    assert.Equal(t, http.StatusNotFound, w.Code)
+    var apiErr models.APIError
+    assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &apiErr))
+    assert.Equal(t, "error", apiErr.Status)
+    assert.Equal(t, "ROUTE_NOT_FOUND", apiErr.ErrorCode)
+    assert.Equal(t, "GET /api/v1/nada", apiErr.Details)
+
+    // Método não suportado pelo caminho: lista os métodos aceitos
+    w = httptest.NewRecorder()
+    r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/v1/data", nil))
+    assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+    apiErr = models.APIError{}
+    assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &apiErr))
+    assert.Equal(t, "METHOD_NOT_ALLOWED", apiErr.ErrorCode)
+    assert.ElementsMatch(t, []string{"GET", "POST"}, apiErr.AllowedMethods)
+    assert.NotEmpty(t, w.Header().Get("Allow"))
+}
+
+func TestMailPreview(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+
+    templates, err := mail.DefaultTemplates()
+    assert.NoError(t, err)
+
+    r := gin.New()
+    r.GET("/api/v1/admin/mail/preview/:template", handlers.NewMailAdminHandler(templates).Preview)
+
+    w := httptest.NewRecorder()
+    r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/admin/mail/preview/password_reset", nil))
+    assert.Equal(t, http.StatusOK, w.Code)
+    var response struct {
+        Data models.MailPreview `json:"data"`
+    }
+    assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+    assert.Equal(t, "password_reset", response.Data.Template)
+    assert.Equal(t, 1, response.Data.Version)
+    assert.Equal(t, "Redefinição de senha", response.Data.Subject)
+    assert.Contains(t, response.Data.HTMLBody, "Maria Silva")
+
+    // Apenas o corpo HTML, sem permitir scripts
+    w = httptest.NewRecorder()
+    r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/admin/mail/preview/password_reset?format=html", nil))
+    assert.Equal(t, http.StatusOK, w.Code)
+    assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+    assert.Contains(t, w.Header().Get("Content-Security-Policy"), "default-src 'none'")
+
+    w = httptest.NewRecorder()
+    r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/admin/mail/preview/inexistente", nil))
+    assert.Equal(t, http.StatusNotFound, w.Code)
+
+    w = httptest.NewRecorder()
+    r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/admin/mail/preview/password_reset?version=0", nil))
+    assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetArtifact_Compression(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+
+    ctx := context.Background()
+    csv := "id,name\n" + strings.Repeat("1,abc\n", 300)
+    var compressed bytes.Buffer
+    gz := gzip.NewWriter(&compressed)
+    _, _ = gz.Write([]byte(csv))
+    assert.NoError(t, gz.Close())
+
+    store := jobs.NewMemoryStore()
+    artifacts := jobs.NewMemoryArtifactStore()
+    files := map[string]*jobs.File{
+        "plain":  {Name: "items.csv", ContentType: "text/csv", Data: []byte(csv)},
+        "stored": {Name: "items.csv", ContentType: "text/csv", Data: compressed.Bytes(), Encoding: jobs.EncodingGzip},
+        "small":  {Name: "items.csv", ContentType: "text/csv", Data: []byte("id\n")},
+    }
+    for id, file := range files {
+        assert.NoError(t, store.Save(ctx, &models.JobStatus{ID: id, Type: "export", State: models.JobStateCompleted, Result: &models.JobArtifact{Name: file.Name}}))
+        assert.NoError(t, artifacts.Put(ctx, id, file))
+    }
+
+    manager := jobs.NewManager(store, jobs.Config{Workers: 1, QueueSize: 1}).WithArtifacts(artifacts)
+    defer manager.Close(ctx)
+
+    r := gin.New()
+    r.GET("/api/v1/jobs/:id/artifact", handlers.NewJobHandler(manager).WithCompression(1024).GetArtifact)
+
+    get := func(id, acceptEncoding string) *httptest.ResponseRecorder {
+        req, _ := http.NewRequest(http.MethodGet, "/api/v1/jobs/"+id+"/artifact", nil)
+        if acceptEncoding != "" {
+            req.Header.Set("Accept-Encoding", acceptEncoding)
+        }
+        w := httptest.NewRecorder()
+        r.ServeHTTP(w, req)
+        return w
+    }
+    gunzip := func(w *httptest.ResponseRecorder) string {
+        reader, err := gzip.NewReader(w.Body)
+        assert.NoError(t, err)
+        body, err := io.ReadAll(reader)
+        assert.NoError(t, err)
+        return string(body)
+    }
+
+    // Comprimido na hora para quem aceita gzip
+    for _, id := range []string{"plain", "stored"} {
+        w := get(id, "deflate, gzip")
+        assert.Equal(t, http.StatusOK, w.Code, id)
+        assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"), id)
+        assert.Contains(t, w.Header().Values("Vary"), "Accept-Encoding", id)
+        assert.Equal(t, csv, gunzip(w), id)
+
+        // Sem gzip (ou com q=0), o conteúdo original
+        for _, acceptEncoding := range []string{"", "gzip;q=0", "br"} {
+            w := get(id, acceptEncoding)
+            assert.Empty(t, w.Header().Get("Content-Encoding"), id)
+            assert.Equal(t, csv, w.Body.String(), id)
+        }
+    }
+
+    // Arquivos pequenos não compensam a compressão
+    w := get("small", "gzip")
+    assert.Empty(t, w.Header().Get("Content-Encoding"))
+    assert.Equal(t, "id\n", w.Body.String())
+}
@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"callable-api/internal/errcodes"
+	"callable-api/internal/health"
+	"callable-api/internal/jwtkey"
+	"callable-api/internal/models"
+	"callable-api/internal/version"
+	"callable-api/pkg/config"
+	"callable-api/pkg/errors"
+	"callable-api/pkg/logger"
+)
+
+// healthCheckTimeout limita a verificação de cada dependência no health check detalhado
+const healthCheckTimeout = 2 * time.Second
+
+// HealthHandler responde ao health check, com detalhes das dependências para administradores
+type HealthHandler struct {
+	cfg       *config.Config
+	keys      *jwtkey.Keys
+	checkers  []health.Checker
+	readiness *health.Readiness
+}
+
+// NewHealthHandler cria um novo handler de health check
+func NewHealthHandler(cfg *config.Config, checkers ...health.Checker) *HealthHandler {
+	return &HealthHandler{
+		cfg:       cfg,
+		keys:      jwtkey.New(cfg),
+		checkers:  checkers,
+		readiness: health.NewReadiness(),
+	}
+}
+
+// WithKeys define o segredo vigente dos JWTs, trocado na rotação (padrão: o
+// da configuração)
+func (h *HealthHandler) WithKeys(keys *jwtkey.Keys) *HealthHandler {
+	h.keys = keys
+	return h
+}
+
+// WithReadiness define o estado de prontidão consultado pelo balanceador
+// (Ready) e alterado pelo dreno manual (Drain e Undrain)
+func (h *HealthHandler) WithReadiness(readiness *health.Readiness) *HealthHandler {
+	h.readiness = readiness
+	return h
+}
+
+// Ready responde à verificação de prontidão do balanceador de carga
+// @Summary Prontidão
+// @Description Verificação de prontidão para o balanceador de carga: 503 enquanto a instância está em dreno manual (POST /api/v1/admin/drain), para que deixe de receber tráfego novo. Diferente de /health, que indica apenas que o processo está no ar. Inclui o estado das integrações com o GCP: integrações degradadas mudam o status para degraded, mas não tiram a instância do balanceador, pois apenas as funcionalidades que dependem delas respondem 503
+// @Tags health
+// @Produce json
+// @Success 200 {object} models.ReadinessReport
+// @Failure 503 {object} models.APIError
+// @Router /ready [get]
+func (h *HealthHandler) Ready(c *gin.Context) {
+	report := h.readiness.Report()
+	if report.Draining {
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, models.APIError{
+			Status:    "error",
+			ErrorCode: errcodes.ServerDraining,
+			Message:   "Instance is draining and not accepting new traffic",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// Drain retira a instância do balanceador
+// @Summary Drenar instância
+// @Description Faz a verificação de prontidão (/ready) falhar, para que o balanceador deixe de enviar tráfego novo à instância, que continua atendendo as requisições em andamento e as que ainda chegarem. Usado na rotação manual dos nós; desfeito por POST /api/v1/admin/undrain
+// @Tags admin
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} models.Response{data=models.DrainStatus}
+// @Failure 401 {object} models.APIError
+// @Failure 403 {object} models.APIError
+// @Router /api/v1/admin/drain [post]
+func (h *HealthHandler) Drain(c *gin.Context) {
+	status := h.readiness.Drain()
+	logger.Warn("Instância em dreno: prontidão falhando", map[string]interface{}{
+		"userId": c.GetString("userID"),
+	})
+
+	respond(c, http.StatusOK, "Instância em dreno", status)
+}
+
+// Undrain devolve a instância ao balanceador
+// @Summary Encerrar dreno
+// @Description Faz a verificação de prontidão (/ready) voltar a responder 200, devolvendo a instância ao balanceador
+// @Tags admin
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} models.Response{data=models.DrainStatus}
+// @Failure 401 {object} models.APIError
+// @Failure 403 {object} models.APIError
+// @Router /api/v1/admin/undrain [post]
+func (h *HealthHandler) Undrain(c *gin.Context) {
+	status := h.readiness.Undrain()
+	logger.Info("Dreno encerrado: instância pronta", map[string]interface{}{
+		"userId": c.GetString("userID"),
+	})
+
+	respond(c, http.StatusOK, "Dreno encerrado", status)
+}
+
+// Check responde com o status da API. Com ?verbose=true e um token de
+// administrador, inclui versão, uptime e a latência de cada dependência
+// @Summary Health check
+// @Description Status da API; com verbose=true (token de admin) inclui dependências, uptime e versão
+// @Tags health
+// @Produce json
+// @Param verbose query bool false "Incluir detalhes das dependências (requer token de admin)"
+// @Success 200 {object} models.HealthReport
+// @Failure 401 {object} models.APIError
+// @Failure 403 {object} models.APIError
+// @Router /health [get]
+func (h *HealthHandler) Check(c *gin.Context) {
+	verbose, _ := strconv.ParseBool(c.Query("verbose"))
+	if !verbose {
+		HealthCheck(c)
+		return
+	}
+
+	if !h.isAdmin(c) {
+		return
+	}
+
+	dependencies := health.Run(c.Request.Context(), healthCheckTimeout, h.checkers...)
+	report := models.HealthReport{
+		Status:        "available",
+		Message:       "Callable API is up and running",
+		Version:       version.Get(),
+		StartedAt:     version.StartedAt().UTC(),
+		UptimeSeconds: int64(version.Uptime().Seconds()),
+		Dependencies:  dependencies,
+	}
+	if !health.Healthy(dependencies) {
+		report.Status = "degraded"
+		report.Message = "One or more dependencies are unavailable"
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// isAdmin verifica se a requisição traz um token JWT válido de administrador,
+// respondendo 401/403 caso contrário
+func (h *HealthHandler) isAdmin(c *gin.Context) bool {
+	token, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !found || token == "" {
+		handleError(c, errors.NewUnauthorizedError("Token de autenticação não fornecido", nil))
+		return false
+	}
+
+	claims, err := h.keys.ValidateToken(token, false)
+	if err != nil {
+		handleError(c, errors.NewUnauthorizedError("Token inválido ou expirado", nil), errcodes.When(errcodes.TypeUnauthorized, errcodes.TokenInvalid))
+		return false
+	}
+	if claims.Role != "admin" {
+		handleError(c, errors.NewForbiddenError("Você não tem permissão para acessar este recurso", nil))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"callable-api/internal/errcodes"
+	"callable-api/internal/jobs"
+	"callable-api/internal/models"
+	"callable-api/pkg/errors"
+)
+
+// itemCreateJobType identifica os jobs de criação assíncrona de itens
+const itemCreateJobType = "item.create"
+
+// CreateMode define como POST /api/v1/data cria os itens quando o cliente
+// não expressa preferência
+type CreateMode string
+
+const (
+	// CreateModeSync cria o item na requisição e responde 201 com o item
+	CreateModeSync CreateMode = "sync"
+	// CreateModeAsync enfileira um job e responde 202 com o job
+	CreateModeAsync CreateMode = "async"
+)
+
+// ParseCreateMode interpreta o modo de criação ("" equivale a sync)
+func ParseCreateMode(value string) (CreateMode, error) {
+	switch mode := CreateMode(strings.ToLower(strings.TrimSpace(value))); mode {
+	case "", CreateModeSync:
+		return CreateModeSync, nil
+	case CreateModeAsync:
+		return mode, nil
+	default:
+		return CreateModeSync, fmt.Errorf("modo de criação de itens desconhecido: %q", value)
+	}
+}
+
+// JobScheduler enfileira tarefas em segundo plano (ver jobs.Manager)
+type JobScheduler interface {
+	ScheduleJob(ctx context.Context, userID, jobType string, fn jobs.Func, opts ...jobs.Option) (*models.JobStatus, error)
+}
+
+// WithAsyncCreate permite a criação assíncrona de itens. mode é usado quando
+// o cliente não envia o header Prefer
+func (h *ItemHandler) WithAsyncCreate(scheduler JobScheduler, mode CreateMode) *ItemHandler {
+	h.jobs = scheduler
+	h.createMode = mode
+	return h
+}
+
+// wantsAsync decide o modo de criação a partir do header Prefer (RFC 7240):
+// respond-async pede um job; return=representation pede o item na resposta.
+// Sem preferência, vale o modo configurado
+func (h *ItemHandler) wantsAsync(c *gin.Context) bool {
+	if h.jobs == nil {
+		return false
+	}
+
+	representation := false
+	for _, header := range c.Request.Header.Values("Prefer") {
+		for _, preference := range strings.Split(header, ",") {
+			token, _, _ := strings.Cut(preference, ";")
+			switch strings.ToLower(strings.ReplaceAll(token, " ", "")) {
+			case "respond-async":
+				return true
+			case "return=representation":
+				representation = true
+			}
+		}
+	}
+	return !representation && h.createMode == CreateModeAsync
+}
+
+// parseRunAt interpreta o agendamento da criação: ?run_at (RFC 3339) ou
+// ?delay (duração, como 90m). Retorna nil quando nenhum dos dois foi enviado
+func parseRunAt(c *gin.Context) ([]jobs.Option, error) {
+	runAt, delay := c.Query("run_at"), c.Query("delay")
+	switch {
+	case runAt != "" && delay != "":
+		return nil, errors.NewBadRequestError("Informe run_at ou delay, não ambos", nil)
+	case runAt != "":
+		t, err := time.Parse(time.RFC3339, runAt)
+		if err != nil {
+			return nil, errors.NewBadRequestError("run_at deve estar no formato RFC 3339", err)
+		}
+		return []jobs.Option{jobs.RunAt(t)}, nil
+	case delay != "":
+		d, err := time.ParseDuration(delay)
+		if err != nil || d < 0 {
+			return nil, errors.NewBadRequestError("delay deve ser uma duração positiva, como 90m", err)
+		}
+		return []jobs.Option{jobs.Delay(d)}, nil
+	default:
+		return nil, nil
+	}
+}
+
+// createAsync enfileira (ou agenda) a criação do item e responde 202 com o
+// job, cujo estado pode ser acompanhado pelo header Location
+func (h *ItemHandler) createAsync(c *gin.Context, input *models.InputData, opts ...jobs.Option) {
+	viewer := viewerFrom(c)
+	job, err := h.jobs.ScheduleJob(c.Request.Context(), viewer.UserID, itemCreateJobType, func(ctx context.Context) (interface{}, error) {
+		jobs.Log(ctx, jobs.LogInfo, "Criando item", map[string]interface{}{"name": input.Name})
+		item, err := h.itemService.CreateItem(ctx, viewer, input)
+		if err != nil {
+			return nil, err
+		}
+		jobs.Log(ctx, jobs.LogInfo, "Item criado", map[string]interface{}{"itemId": item.ID})
+		return item, nil
+	}, opts...)
+	if err != nil {
+		respondScheduleError(c, err)
+		return
+	}
+
+	c.Header("Preference-Applied", "respond-async")
+	c.Header("Location", "/api/v1/jobs/"+job.ID)
+	respond(c, http.StatusAccepted, "Data creation accepted", job)
+}
+
+// scheduleRetryAfter é o intervalo sugerido para reenviar um job recusado pela
+// fila cheia ou pela cota de jobs aguardando do usuário
+const scheduleRetryAfter = 5 * time.Second
+
+// respondScheduleError responde às falhas ao enfileirar um job: a cota de
+// jobs aguardando do usuário (429), o tipo de job desativado, a fila cheia ou
+// o encerramento do servidor (503) são temporários
+func respondScheduleError(c *gin.Context, err error) {
+	if stderrors.Is(err, jobs.ErrRunAtTooFar) {
+		handleError(c, errors.NewBadRequestError(err.Error(), err), errcodes.When(errcodes.TypeBadRequest, errcodes.InvalidRunAt))
+		return
+	}
+	if stderrors.Is(err, jobs.ErrUserQueueFull) {
+		errcodes.RespondRetry(c, models.APIError{
+			Code:      http.StatusTooManyRequests,
+			Status:    "error",
+			ErrorCode: errcodes.JobQuotaExceeded,
+			Message:   "Limite de jobs aguardando atingido; aguarde a conclusão dos anteriores",
+		}, scheduleRetryAfter)
+		return
+	}
+	var disabled *jobs.DisabledError
+	if stderrors.As(err, &disabled) {
+		errcodes.RespondRetry(c, models.APIError{
+			Code:      http.StatusServiceUnavailable,
+			Status:    "error",
+			ErrorCode: errcodes.JobTypeDisabled,
+			Message:   "Processamento temporariamente desativado após falhas repetidas",
+		}, time.Until(disabled.Until))
+		return
+	}
+	if !stderrors.Is(err, jobs.ErrQueueFull) && !stderrors.Is(err, jobs.ErrClosed) {
+		handleError(c, err)
+		return
+	}
+
+	errcodes.RespondRetry(c, models.APIError{
+		Code:      http.StatusServiceUnavailable,
+		Status:    "error",
+		ErrorCode: errcodes.JobQueueFull,
+		Message:   "Fila de processamento cheia; tente novamente em instantes",
+	}, scheduleRetryAfter)
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"callable-api/internal/errcodes"
+	"callable-api/internal/models"
+	"callable-api/internal/service"
+	"callable-api/pkg/errors"
+)
+
+// WithBulkLimits define o tamanho máximo do corpo e o número máximo de itens
+// de POST /api/v1/data/bulk
+func (h *ItemHandler) WithBulkLimits(limits ArrayLimits) *ItemHandler {
+	h.bulkLimits = limits
+	return h
+}
+
+// PostDataBulk cria vários itens a partir de um array JSON. O array é lido e
+// validado elemento a elemento: um elemento inválido, um corpo grande demais
+// ou itens demais rejeitam a requisição inteira antes que algum item seja
+// criado. Os itens válidos são então criados em ordem, com um resultado por
+// elemento; falhas na criação (como itens duplicados) não impedem os demais
+// @Summary Criar itens em lote
+// @Description Cria os itens de um array JSON, validando cada elemento assim que é lido. Responde 201 quando todos os itens foram criados e 207 com o resultado de cada elemento caso contrário
+// @Tags items
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body []models.InputData true "Itens a criar"
+// @Success 201 {object} models.Response{data=[]models.BulkItemResult}
+// @Success 207 {object} models.Response{data=[]models.BulkItemResult}
+// @Failure 400 {object} models.APIError
+// @Failure 401 {object} models.APIError
+// @Failure 413 {object} models.APIError
+// @Router /api/v1/data/bulk [post]
+func (h *ItemHandler) PostDataBulk(c *gin.Context) {
+	var inputs []models.InputData
+	if !decodeArray(c, h.bulkLimits, func(_ int, input *models.InputData) {
+		inputs = append(inputs, *input)
+	}) {
+		return
+	}
+
+	ctx, cancel := h.requestContext(c)
+	defer cancel()
+
+	viewer := viewerFrom(c)
+	results := make([]models.BulkItemResult, len(inputs))
+	status := http.StatusCreated
+	for i := range inputs {
+		results[i].Index = i
+		item, err := h.itemService.CreateItem(ctx, viewer, &inputs[i])
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				handleError(c, ctxErr)
+				return
+			}
+			results[i].Status, results[i].Code, results[i].Error = bulkItemError(err)
+			status = http.StatusMultiStatus
+			continue
+		}
+		results[i].Status = http.StatusCreated
+		results[i].Item = item
+	}
+
+	respond(c, status, "Bulk creation processed", results)
+}
+
+// bulkItemError descreve a falha na criação de um dos itens
+func bulkItemError(err error) (int, string, string) {
+	code := errcodes.FromError(err, errcodes.WhenCause(service.ErrDuplicateItem, errcodes.DuplicateItem))
+	def, _ := errcodes.Lookup(code)
+
+	message := def.Description
+	var appErr *errors.AppError
+	if stderrors.As(err, &appErr) && appErr.Type != errcodes.TypeInternal {
+		message = appErr.Message
+	}
+	return def.Status, code, message
+}
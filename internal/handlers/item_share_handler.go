@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"callable-api/internal/errcodes"
+	"callable-api/internal/models"
+	"callable-api/internal/repository"
+)
+
+// itemNotFound identifica itens inexistentes (ou invisíveis ao usuário) nas respostas de erro
+var itemNotFound = errcodes.When(errcodes.TypeNotFound, errcodes.ItemNotFound)
+
+// PutData substitui os dados de um item
+// @Summary Atualizar item
+// @Description Exige acesso de escrita: dono, administrador ou compartilhamento de nível write
+// @Tags items
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "ID do item"
+// @Param request body models.InputData true "Dados do item"
+// @Success 200 {object} models.Response{data=models.Item}
+// @Failure 400 {object} models.APIError
+// @Failure 401 {object} models.APIError
+// @Failure 403 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Router /api/v1/data/{id} [put]
+func (h *ItemHandler) PutData(c *gin.Context) {
+	var input models.InputData
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	ctx, cancel := h.requestContext(c)
+	defer cancel()
+
+	item, err := h.itemService.UpdateItem(ctx, viewerFrom(c), c.Param("id"), &input)
+	if err != nil {
+		handleError(c, err, itemNotFound)
+		return
+	}
+
+	respond(c, http.StatusOK, "Data updated successfully", item)
+}
+
+// ShareData compartilha um item com um usuário ou papel
+// @Summary Compartilhar item
+// @Description Concede acesso de leitura ou escrita a um usuário ou a todos os usuários de um papel. Apenas o dono do item (ou um administrador) pode compartilhar
+// @Tags items
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "ID do item"
+// @Param request body models.ShareItemInput true "Destinatário e nível de acesso"
+// @Success 200 {object} models.Response{data=models.ItemShare}
+// @Failure 400 {object} models.APIError
+// @Failure 401 {object} models.APIError
+// @Failure 403 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Router /api/v1/data/{id}/share [post]
+func (h *ItemHandler) ShareData(c *gin.Context) {
+	var input models.ShareItemInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	ctx, cancel := h.requestContext(c)
+	defer cancel()
+
+	share, err := h.itemService.ShareItem(ctx, viewerFrom(c), c.Param("id"), &input)
+	if err != nil {
+		handleError(c, err, itemNotFound)
+		return
+	}
+
+	respond(c, http.StatusOK, "Item shared successfully", share)
+}
+
+// ListDataShares lista os compartilhamentos de um item
+// @Summary Compartilhamentos do item
+// @Tags items
+// @Produce json
+// @Security Bearer
+// @Param id path string true "ID do item"
+// @Success 200 {object} models.Response{data=[]models.ItemShare}
+// @Failure 401 {object} models.APIError
+// @Failure 403 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Router /api/v1/data/{id}/shares [get]
+func (h *ItemHandler) ListDataShares(c *gin.Context) {
+	ctx, cancel := h.requestContext(c)
+	defer cancel()
+
+	shares, err := h.itemService.ListShares(ctx, viewerFrom(c), c.Param("id"))
+	if err != nil {
+		handleError(c, err, itemNotFound)
+		return
+	}
+
+	respond(c, http.StatusOK, "Shares retrieved successfully", shares)
+}
+
+// RevokeDataShare remove um compartilhamento de um item
+// @Summary Revogar compartilhamento
+// @Tags items
+// @Security Bearer
+// @Param id path string true "ID do item"
+// @Param shareId path string true "ID do compartilhamento"
+// @Success 204
+// @Failure 401 {object} models.APIError
+// @Failure 403 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Router /api/v1/data/{id}/shares/{shareId} [delete]
+func (h *ItemHandler) RevokeDataShare(c *gin.Context) {
+	ctx, cancel := h.requestContext(c)
+	defer cancel()
+
+	if err := h.itemService.RevokeShare(ctx, viewerFrom(c), c.Param("id"), c.Param("shareId")); err != nil {
+		handleError(c, err, errcodes.WhenCause(repository.ErrShareNotFound, errcodes.NotFound), itemNotFound)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
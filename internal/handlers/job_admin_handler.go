@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"callable-api/internal/models"
+	"callable-api/pkg/errors"
+)
+
+// JobMaintainer resume e limpa os jobs em segundo plano (ver jobs.Manager)
+type JobMaintainer interface {
+	Stats(ctx context.Context) (*models.JobStats, error)
+	Cleanup(ctx context.Context, olderThan time.Duration) (*models.JobCleanup, error)
+}
+
+// JobAdminHandler processa a administração dos jobs
+type JobAdminHandler struct {
+	jobs JobMaintainer
+}
+
+// NewJobAdminHandler cria um novo handler de administração dos jobs
+func NewJobAdminHandler(jobs JobMaintainer) *JobAdminHandler {
+	return &JobAdminHandler{jobs: jobs}
+}
+
+// Stats retorna o resumo dos jobs
+// @Summary Estatísticas dos jobs
+// @Description Quantidade de jobs por estado, criação do job pendente mais antigo e duração média de execução dos jobs terminados
+// @Tags admin
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} models.Response{data=models.JobStats}
+// @Failure 401 {object} models.APIError
+// @Failure 403 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /api/v1/admin/jobs/stats [get]
+func (h *JobAdminHandler) Stats(c *gin.Context) {
+	stats, err := h.jobs.Stats(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, "Estatísticas dos jobs recuperadas com sucesso", stats)
+}
+
+// Cleanup remove os jobs terminados há mais tempo que older_than
+// @Summary Limpeza dos jobs
+// @Description Remove os jobs concluídos ou com falha que terminaram há mais tempo que older_than (duração, como 24h). Jobs pendentes, em execução e as etapas de workflows em andamento são mantidos
+// @Tags admin
+// @Produce json
+// @Security Bearer
+// @Param older_than query string true "Idade mínima dos jobs removidos (ex.: 24h)"
+// @Success 200 {object} models.Response{data=models.JobCleanup}
+// @Failure 400 {object} models.APIError
+// @Failure 401 {object} models.APIError
+// @Failure 403 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /api/v1/admin/jobs/cleanup [post]
+func (h *JobAdminHandler) Cleanup(c *gin.Context) {
+	olderThan, err := time.ParseDuration(c.Query("older_than"))
+	if err != nil || olderThan <= 0 {
+		handleError(c, errors.NewBadRequestError("older_than deve ser uma duração positiva, como 24h", err))
+		return
+	}
+
+	cleanup, err := h.jobs.Cleanup(c.Request.Context(), olderThan)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, "Jobs removidos com sucesso", cleanup)
+}
@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"context"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"callable-api/internal/errcodes"
+	"callable-api/internal/events"
+	"callable-api/internal/jobs"
+	"callable-api/internal/models"
+	"callable-api/internal/streams"
+)
+
+// JobReader consulta os jobs em segundo plano e seus artefatos (ver jobs.Manager)
+type JobReader interface {
+	GetJob(ctx context.Context, viewer models.Viewer, id string) (*models.JobStatus, error)
+	Artifact(ctx context.Context, viewer models.Viewer, id string) (*jobs.Artifact, error)
+	Logs(ctx context.Context, viewer models.Viewer, id string) (*models.JobLogs, error)
+}
+
+// JobHandler processa as consultas ao estado dos jobs
+type JobHandler struct {
+	jobs        JobReader
+	streams     *streams.Hub
+	compressMin int
+}
+
+// NewJobHandler cria um novo handler de jobs
+func NewJobHandler(jobs JobReader) *JobHandler {
+	return &JobHandler{jobs: jobs, compressMin: DefaultCompressionMinBytes}
+}
+
+// WithCompression define o tamanho a partir do qual os artefatos de texto
+// (exportações CSV e JSON) são comprimidos na hora para os clientes que
+// enviam Accept-Encoding: gzip (0 desativa)
+func (h *JobHandler) WithCompression(minBytes int) *JobHandler {
+	h.compressMin = minBytes
+	return h
+}
+
+// WithStreams habilita o acompanhamento dos jobs por Server-Sent Events
+// (JobEvents). O hub deve receber os eventos de domínio (events.Bus)
+func (h *JobHandler) WithStreams(hub *streams.Hub) *JobHandler {
+	h.streams = hub
+	return h
+}
+
+// GetJob retorna o estado de um job. A resposta traz ETag e Last-Modified,
+// para que os clientes que acompanham o job repitam a consulta com
+// If-None-Match ou If-Modified-Since e recebam 304 enquanto nada mudar
+// @Summary Estado do job
+// @Description Retorna o estado de um job em segundo plano (scheduled, pending, running, completed, failed ou timed_out) e, quando concluído, o resultado. Resultados grandes trazem o link de download do artefato. Com If-None-Match (ETag) ou If-Modified-Since, responde 304 se o job não mudou
+// @Tags jobs
+// @Produce json
+// @Param id path string true "ID do job"
+// @Param If-None-Match header string false "ETag da última resposta"
+// @Param If-Modified-Since header string false "Last-Modified da última resposta"
+// @Success 200 {object} models.Response{data=models.JobStatus}
+// @Success 304 "O job não mudou"
+// @Failure 404 {object} models.APIError
+// @Router /api/v1/jobs/{id} [get]
+func (h *JobHandler) GetJob(c *gin.Context) {
+	job, err := h.jobs.GetJob(c.Request.Context(), viewerFrom(c), c.Param("id"))
+	if err != nil {
+		handleError(c, err, errcodes.When(errcodes.TypeNotFound, errcodes.JobNotFound))
+		return
+	}
+
+	etag, err := representationETag(c, job)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	if notModified(c, etag, job.UpdatedAt) {
+		return
+	}
+
+	respond(c, http.StatusOK, "Job retrieved successfully", job)
+}
+
+// GetArtifact baixa o artefato gerado pelo job
+// @Summary Artefato do job
+// @Description Baixa o resultado do job gravado como artefato (exportações, relatórios e resultados grandes). Com Accept-Encoding: gzip, exportações de texto grandes são enviadas comprimidas (Content-Encoding: gzip). Com o Cloud Storage, redireciona para uma URL assinada de curta duração; artefatos gravados comprimidos (content_encoding no status do job) são entregues como gzip
+// @Tags jobs
+// @Produce octet-stream
+// @Param id path string true "ID do job"
+// @Param Accept-Encoding header string false "gzip para receber o artefato comprimido"
+// @Success 200 {file} binary
+// @Success 302 "Redirecionamento para a URL assinada"
+// @Failure 404 {object} models.APIError
+// @Router /api/v1/jobs/{id}/artifact [get]
+func (h *JobHandler) GetArtifact(c *gin.Context) {
+	artifact, err := h.jobs.Artifact(c.Request.Context(), viewerFrom(c), c.Param("id"))
+	if err != nil {
+		handleError(c, err, errcodes.When(errcodes.TypeNotFound, errcodes.JobArtifactNotFound))
+		return
+	}
+
+	if artifact.RedirectURL != "" {
+		c.Redirect(http.StatusFound, artifact.RedirectURL)
+		return
+	}
+
+	if artifact.Name != "" {
+		c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": artifact.Name}))
+	}
+	writeFile(c, &artifact.File, h.compressMin)
+}
+
+// GetJobLogs retorna o log de execução do job
+// @Summary Log do job
+// @Description Retorna as linhas mais recentes do log de execução do job (início, falhas e mensagens registradas pela tarefa), para investigar por que um job falhou. As linhas além do limite por job são descartadas e contadas em dropped
+// @Tags jobs
+// @Produce json
+// @Param id path string true "ID do job"
+// @Success 200 {object} models.Response{data=models.JobLogs}
+// @Failure 404 {object} models.APIError
+// @Router /api/v1/jobs/{id}/logs [get]
+func (h *JobHandler) GetJobLogs(c *gin.Context) {
+	logs, err := h.jobs.Logs(c.Request.Context(), viewerFrom(c), c.Param("id"))
+	if err != nil {
+		handleError(c, err, errcodes.When(errcodes.TypeNotFound, errcodes.JobNotFound))
+		return
+	}
+
+	respond(c, http.StatusOK, "Job logs retrieved successfully", logs)
+}
+
+// JobEvents acompanha um job por Server-Sent Events
+// @Summary Acompanhar job (SSE)
+// @Description Stream text/event-stream com o estado do job (evento status): o atual ao conectar e o final quando o job termina, encerrando o stream. Quando o servidor inicia o encerramento, envia o evento server.draining com o intervalo sugerido para a reconexão (reconnect_after_ms) e fecha a conexão
+// @Tags jobs
+// @Produce text/event-stream
+// @Param id path string true "ID do job"
+// @Success 200 {object} models.JobStatus "Eventos status"
+// @Failure 404 {object} models.APIError
+// @Failure 503 {object} models.APIError
+// @Router /api/v1/jobs/{id}/events [get]
+func (h *JobHandler) JobEvents(c *gin.Context) {
+	ctx := c.Request.Context()
+	viewer := viewerFrom(c)
+	id := c.Param("id")
+
+	if h.streams == nil {
+		NoRoute(c)
+		return
+	}
+
+	sub := subscribeSSE(c, h.streams, func(envelope *events.Envelope) bool {
+		return jobEventID(envelope) == id
+	})
+	if sub == nil {
+		return
+	}
+	defer sub.Close()
+
+	job, err := h.jobs.GetJob(ctx, viewer, id)
+	if err != nil {
+		handleError(c, err, errcodes.When(errcodes.TypeNotFound, errcodes.JobNotFound))
+		return
+	}
+	w := startSSE(c, h.streams.Config().ReconnectAfter)
+	if w.event("status", job) != nil || job.Finished() {
+		return
+	}
+
+	var heartbeat <-chan time.Time
+	if every := h.streams.Config().Heartbeat; every > 0 {
+		ticker := time.NewTicker(every)
+		defer ticker.Stop()
+		heartbeat = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sub.Draining():
+			w.draining(h.streams.Config().ReconnectAfter)
+			return
+		case <-heartbeat:
+			if w.heartbeat() != nil {
+				return
+			}
+		case <-sub.Events():
+			if job, err = h.jobs.GetJob(ctx, viewer, id); err != nil {
+				return
+			}
+			if w.event("status", job) != nil || job.Finished() {
+				return
+			}
+		}
+	}
+}
+
+// jobEventID retorna o job de um evento de término de job
+func jobEventID(envelope *events.Envelope) string {
+	event, err := events.Decode(envelope)
+	if err != nil {
+		return ""
+	}
+	switch event := event.(type) {
+	case *events.JobCompleted:
+		return event.JobID
+	case *events.JobFailed:
+		return event.JobID
+	}
+	return ""
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"callable-api/internal/models"
+	"callable-api/pkg/errors"
+	"callable-api/pkg/mail"
+)
+
+// previewCSP impede que a pré-visualização HTML execute scripts ou carregue
+// recursos além de estilos e imagens
+const previewCSP = "default-src 'none'; style-src 'unsafe-inline'; img-src * data:"
+
+// MailTemplates renderiza os templates de email (ver mail.Templates)
+type MailTemplates interface {
+	Versions(name string) []int
+	Preview(name string, version int) (*mail.Message, int, error)
+}
+
+// MailAdminHandler processa a administração dos templates de email
+type MailAdminHandler struct {
+	templates MailTemplates
+}
+
+// NewMailAdminHandler cria um novo handler de administração dos emails
+func NewMailAdminHandler(templates MailTemplates) *MailAdminHandler {
+	return &MailAdminHandler{templates: templates}
+}
+
+// Preview renderiza um template com os dados de exemplo
+// @Summary Pré-visualização de template de email
+// @Description Renderiza o template com os dados de exemplo (<template>.sample.json), sem enviar. Por padrão usa a versão mais recente e responde em JSON; format=html ou format=text retornam apenas o corpo correspondente
+// @Tags admin
+// @Produce json,html,plain
+// @Security Bearer
+// @Param template path string true "Nome do template (ex.: password_reset)"
+// @Param version query int false "Versão do template (padrão: a mais recente)"
+// @Param format query string false "Formato da resposta" Enums(json, html, text)
+// @Success 200 {object} models.Response{data=models.MailPreview}
+// @Failure 400 {object} models.APIError
+// @Failure 401 {object} models.APIError
+// @Failure 403 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /api/v1/admin/mail/preview/{template} [get]
+func (h *MailAdminHandler) Preview(c *gin.Context) {
+	name := c.Param("template")
+
+	version := 0
+	if raw := c.Query("version"); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil || v <= 0 {
+			handleError(c, errors.NewBadRequestError("version deve ser um inteiro positivo", err))
+			return
+		}
+		version = v
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "html" && format != "text" {
+		handleError(c, errors.NewBadRequestError("format deve ser json, html ou text", nil))
+		return
+	}
+
+	msg, rendered, err := h.templates.Preview(name, version)
+	if err != nil {
+		if stderrors.Is(err, mail.ErrTemplateNotFound) {
+			handleError(c, errors.NewNotFoundError("Template de email não encontrado", err))
+			return
+		}
+		handleError(c, errors.NewInternalServerError("Erro ao renderizar o template de email", err))
+		return
+	}
+
+	switch format {
+	case "html":
+		if msg.HTMLBody == "" {
+			handleError(c, errors.NewNotFoundError("Template de email sem versão HTML", nil))
+			return
+		}
+		c.Header("Content-Security-Policy", previewCSP)
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(msg.HTMLBody))
+	case "text":
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(msg.TextBody))
+	default:
+		respond(c, http.StatusOK, "Template de email renderizado com sucesso", &models.MailPreview{
+			Template: name,
+			Version:  rendered,
+			Versions: h.templates.Versions(name),
+			Subject:  msg.Subject,
+			TextBody: msg.TextBody,
+			HTMLBody: msg.HTMLBody,
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"callable-api/internal/metrics"
+)
+
+// MetricsHandler expõe as métricas para a coleta do Prometheus
+type MetricsHandler struct {
+	exporter *metrics.Exporter
+}
+
+// NewMetricsHandler cria um novo MetricsHandler
+func NewMetricsHandler(exporter *metrics.Exporter) *MetricsHandler {
+	return &MetricsHandler{exporter: exporter}
+}
+
+// Prometheus responde as métricas no formato de texto do Prometheus. As
+// regras de alerta recomendadas são geradas por `api metrics rules`
+func (h *MetricsHandler) Prometheus(c *gin.Context) {
+	var body bytes.Buffer
+	if err := h.exporter.Write(c.Request.Context(), &body); err != nil {
+		handleError(c, err)
+		return
+	}
+	c.Data(http.StatusOK, metrics.PrometheusContentType, body.Bytes())
+}
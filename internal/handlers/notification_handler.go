@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"callable-api/internal/errcodes"
+	"callable-api/internal/models"
+	"callable-api/internal/notifications"
+	"callable-api/internal/pagination"
+	"callable-api/internal/repository"
+	"callable-api/pkg/errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationHandler processa requisições relacionadas às notificações do usuário
+type NotificationHandler struct {
+	service    *notifications.Service
+	pagination pagination.Config
+}
+
+// NewNotificationHandler cria um novo handler de notificações
+func NewNotificationHandler(service *notifications.Service) *NotificationHandler {
+	return &NotificationHandler{
+		service:    service,
+		pagination: pagination.DefaultConfig(),
+	}
+}
+
+// WithPagination define os limites de tamanho de página da caixa de entrada
+func (h *NotificationHandler) WithPagination(cfg pagination.Config) *NotificationHandler {
+	h.pagination = cfg
+	return h
+}
+
+// currentUserID obtém o ID do usuário autenticado armazenado pelo JWTAuthMiddleware
+func currentUserID(c *gin.Context) (string, bool) {
+	userID, _ := c.Get("userID")
+	userIDStr, ok := userID.(string)
+	if !ok || userIDStr == "" {
+		handleError(c, errors.NewUnauthorizedError("ID de usuário inválido", nil))
+		return "", false
+	}
+	return userIDStr, true
+}
+
+// GetPreferences retorna as preferências de notificação do usuário
+// @Summary Preferências de notificação
+// @Description Retorna, para cada tipo de evento, os canais pelos quais o usuário é notificado
+// @Tags notifications
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} models.Response{data=models.NotificationPreferences}
+// @Failure 401 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /api/v1/auth/notifications/preferences [get]
+func (h *NotificationHandler) GetPreferences(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	prefs, err := h.service.GetPreferences(c.Request.Context(), userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, "Preferências recuperadas com sucesso", prefs)
+}
+
+// UpdatePreferences substitui as preferências de notificação do usuário
+// @Summary Atualizar preferências de notificação
+// @Description Define os canais (email, webhook, in_app) usados para cada tipo de evento
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body models.UpdateNotificationPreferencesInput true "Preferências de notificação"
+// @Success 200 {object} models.Response{data=models.NotificationPreferences}
+// @Failure 400 {object} models.APIError
+// @Failure 401 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /api/v1/auth/notifications/preferences [put]
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var input models.UpdateNotificationPreferencesInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	prefs, err := h.service.UpdatePreferences(c.Request.Context(), userID, &input)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, "Preferências atualizadas com sucesso", prefs)
+}
+
+// ListNotifications retorna a caixa de entrada in-app do usuário
+// @Summary Notificações in-app
+// @Description Lista as notificações do usuário (job concluído, item compartilhado), das mais recentes para as mais antigas. Com unread=true, apenas as não lidas: total_rows (ou X-Total-Count) é a contagem exibida no sino
+// @Tags notifications
+// @Produce json
+// @Security Bearer
+// @Param unread query bool false "Apenas notificações não lidas"
+// @Param page query int false "Página"
+// @Param limit query int false "Itens por página"
+// @Success 200 {object} models.ListResponse{data=[]models.Notification}
+// @Failure 400 {object} models.APIError
+// @Failure 401 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /api/v1/notifications [get]
+func (h *NotificationHandler) ListNotifications(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	unreadOnly := false
+	if raw := c.Query("unread"); raw != "" {
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			handleError(c, errors.NewBadRequestError("unread deve ser true ou false", err))
+			return
+		}
+		unreadOnly = value
+	}
+
+	p := h.pagination.Parse(c)
+	list, total, err := h.service.ListNotifications(c.Request.Context(), userID, unreadOnly, p.Page, p.Limit)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	respondList(c, "Notificações recuperadas com sucesso", list, p, total)
+}
+
+// MarkNotificationRead marca uma notificação como lida
+// @Summary Marcar notificação como lida
+// @Tags notifications
+// @Produce json
+// @Security Bearer
+// @Param id path string true "ID da notificação"
+// @Success 200 {object} models.Response{data=models.Notification}
+// @Failure 401 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /api/v1/notifications/{id}/read [post]
+func (h *NotificationHandler) MarkNotificationRead(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	notification, err := h.service.MarkRead(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		handleError(c, err, errcodes.WhenCause(repository.ErrNotificationNotFound, errcodes.NotificationNotFound))
+		return
+	}
+
+	respond(c, http.StatusOK, "Notificação marcada como lida", notification)
+}
+
+// MarkAllNotificationsRead marca todas as notificações do usuário como lidas
+// @Summary Marcar todas as notificações como lidas
+// @Tags notifications
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} models.Response{data=models.NotificationsRead}
+// @Failure 401 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /api/v1/notifications/read-all [post]
+func (h *NotificationHandler) MarkAllNotificationsRead(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.MarkAllRead(c.Request.Context(), userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, "Notificações marcadas como lidas", result)
+}
+
+// ListWebhookDeliveries lista as entregas ao webhook do usuário
+// @Summary Entregas de webhook
+// @Description Lista os eventos entregues ao webhook do usuário (com sucesso ou não) ainda dentro da janela de retenção, das mais recentes para as mais antigas, com o payload enviado
+// @Tags notifications
+// @Produce json
+// @Security Bearer
+// @Param id path string true "ID do webhook (webhook_id das preferências)"
+// @Success 200 {object} models.Response{data=[]models.WebhookDelivery}
+// @Failure 401 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /api/v1/webhooks/{id}/deliveries [get]
+func (h *NotificationHandler) ListWebhookDeliveries(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	list, err := h.service.ListWebhookDeliveries(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		handleError(c, err, errcodes.WhenCause(notifications.ErrWebhookNotFound, errcodes.WebhookNotFound))
+		return
+	}
+
+	respond(c, http.StatusOK, "Entregas recuperadas com sucesso", list)
+}
+
+// RedeliverWebhook reenvia um evento ao webhook do usuário
+// @Summary Reentregar evento de webhook
+// @Description Reenvia à URL atual do webhook o payload guardado do evento, com o mesmo X-Callable-Event-Id e o header X-Callable-Redelivery. Permite recuperar eventos perdidos durante uma indisponibilidade sem reconciliar por polling. O resultado da nova tentativa está em status e last_error
+// @Tags notifications
+// @Produce json
+// @Security Bearer
+// @Param id path string true "ID do webhook (webhook_id das preferências)"
+// @Param event path string true "ID do evento (event_id da entrega)"
+// @Success 200 {object} models.Response{data=models.WebhookDelivery}
+// @Failure 401 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Failure 429 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /api/v1/webhooks/{id}/redeliver/{event} [post]
+func (h *NotificationHandler) RedeliverWebhook(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.service.RedeliverWebhook(c.Request.Context(), userID, c.Param("id"), c.Param("event"))
+	if err != nil {
+		handleError(c, err,
+			errcodes.WhenCause(notifications.ErrWebhookNotFound, errcodes.WebhookNotFound),
+			errcodes.WhenCause(notifications.ErrDeliveryNotFound, errcodes.WebhookEventNotFound))
+		return
+	}
+
+	respond(c, http.StatusOK, "Evento reentregue", delivery)
+}
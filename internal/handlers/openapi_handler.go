@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+
+	"callable-api/internal/openapi"
+	"callable-api/internal/routes"
+)
+
+// OpenAPIHandler serve o documento OpenAPI gerado a partir do registry de
+// rotas, com ou sem as rotas de administração conforme o escopo
+type OpenAPIHandler struct {
+	registry *routes.Registry
+	base     string
+
+	once sync.Once
+	docs map[openapi.Scope]map[string]interface{}
+	err  error
+}
+
+// NewOpenAPIHandler cria o handler. base é o documento gerado pelo swag
+// (docs.SwaggerInfo.ReadDoc()). Os documentos são gerados na primeira
+// requisição, quando todas as rotas já foram declaradas
+func NewOpenAPIHandler(registry *routes.Registry, base string) *OpenAPIHandler {
+	return &OpenAPIHandler{registry: registry, base: base}
+}
+
+// Public retorna o documento sem as rotas de administração e operação
+// @Summary Documento OpenAPI público
+// @Description Documento OpenAPI (Swagger 2.0) gerado a partir das rotas declaradas, sem as rotas de administração e operação
+// @Tags docs
+// @Produce json
+// @Success 200 {object} object
+// @Router /openapi.json [get]
+func (h *OpenAPIHandler) Public(c *gin.Context) {
+	h.serve(c, openapi.ScopePublic)
+}
+
+// Full retorna o documento com todas as rotas, inclusive as de administração
+// @Summary Documento OpenAPI completo
+// @Description Documento OpenAPI (Swagger 2.0) com todas as rotas declaradas, incluindo as de administração e operação
+// @Tags admin
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} object
+// @Failure 401 {object} models.APIError
+// @Failure 403 {object} models.APIError
+// @Router /api/v1/admin/openapi.json [get]
+func (h *OpenAPIHandler) Full(c *gin.Context) {
+	h.serve(c, openapi.ScopeFull)
+}
+
+// serve responde com o documento do escopo
+func (h *OpenAPIHandler) serve(c *gin.Context, scope openapi.Scope) {
+	h.once.Do(func() {
+		matrix := h.registry.Matrix()
+		h.docs = make(map[openapi.Scope]map[string]interface{}, 2)
+		for _, s := range []openapi.Scope{openapi.ScopePublic, openapi.ScopeFull} {
+			doc, err := openapi.Build(h.base, matrix, s)
+			if err != nil {
+				h.err = err
+				return
+			}
+			h.docs[s] = doc
+		}
+	})
+	if h.err != nil {
+		handleError(c, h.err)
+		return
+	}
+
+	writeJSON(c, http.StatusOK, h.docs[scope])
+}
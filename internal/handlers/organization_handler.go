@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"callable-api/internal/errcodes"
+	"callable-api/internal/models"
+	"callable-api/internal/repository"
+	"callable-api/internal/service"
+)
+
+// orgNotFound identifica organizações inexistentes (ou de que o usuário não é membro)
+var orgNotFound = errcodes.When(errcodes.TypeNotFound, errcodes.OrgNotFound)
+
+// OrganizationHandler processa requisições relacionadas às organizações, aos
+// seus membros e aos convites
+type OrganizationHandler struct {
+	orgs *service.OrganizationService
+}
+
+// NewOrganizationHandler cria um novo handler de organizações
+func NewOrganizationHandler(orgs *service.OrganizationService) *OrganizationHandler {
+	return &OrganizationHandler{orgs: orgs}
+}
+
+// CreateOrganization cria uma organização
+// @Summary Criar organização
+// @Description Cria a organização com o usuário autenticado como seu primeiro dono
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body models.CreateOrganizationInput true "Organização"
+// @Success 201 {object} models.Response{data=models.Organization}
+// @Failure 400 {object} models.APIError
+// @Failure 401 {object} models.APIError
+// @Router /api/v1/orgs [post]
+func (h *OrganizationHandler) CreateOrganization(c *gin.Context) {
+	var input models.CreateOrganizationInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	org, err := h.orgs.CreateOrganization(c.Request.Context(), viewerFrom(c), &input)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	respond(c, http.StatusCreated, "Organização criada com sucesso", org)
+}
+
+// ListOrganizations lista as organizações do usuário
+// @Summary Minhas organizações
+// @Description Organizações de que o usuário é membro, com o seu papel em cada uma
+// @Tags organizations
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} models.Response{data=[]models.Organization}
+// @Failure 401 {object} models.APIError
+// @Router /api/v1/orgs [get]
+func (h *OrganizationHandler) ListOrganizations(c *gin.Context) {
+	orgs, err := h.orgs.ListOrganizations(c.Request.Context(), viewerFrom(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, "Organizações recuperadas com sucesso", orgs)
+}
+
+// ListMembers lista os membros de uma organização
+// @Summary Membros da organização
+// @Tags organizations
+// @Produce json
+// @Security Bearer
+// @Param id path string true "ID da organização"
+// @Success 200 {object} models.Response{data=[]models.Membership}
+// @Failure 401 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Router /api/v1/orgs/{id}/members [get]
+func (h *OrganizationHandler) ListMembers(c *gin.Context) {
+	members, err := h.orgs.ListMembers(c.Request.Context(), viewerFrom(c), c.Param("id"))
+	if err != nil {
+		handleError(c, err, orgNotFound)
+		return
+	}
+
+	respond(c, http.StatusOK, "Membros recuperados com sucesso", members)
+}
+
+// RemoveMember remove um membro de uma organização
+// @Summary Remover membro
+// @Description Donos removem qualquer membro; os demais membros podem apenas sair (informando o próprio ID). O último dono não pode ser removido
+// @Tags organizations
+// @Security Bearer
+// @Param id path string true "ID da organização"
+// @Param userId path string true "ID do usuário"
+// @Success 204
+// @Failure 401 {object} models.APIError
+// @Failure 403 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Failure 409 {object} models.APIError
+// @Router /api/v1/orgs/{id}/members/{userId} [delete]
+func (h *OrganizationHandler) RemoveMember(c *gin.Context) {
+	if err := h.orgs.RemoveMember(c.Request.Context(), viewerFrom(c), c.Param("id"), c.Param("userId")); err != nil {
+		handleError(c, err,
+			errcodes.WhenCause(repository.ErrMembershipNotFound, errcodes.OrgMemberNotFound),
+			errcodes.WhenCause(service.ErrLastOwner, errcodes.LastOrgOwner),
+			orgNotFound)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// InviteMember convida um email para uma organização
+// @Summary Convidar membro
+// @Description Apenas donos convidam. O token do convite é retornado uma única vez e deve ser enviado ao convidado, que o aceita autenticado com a conta do email convidado
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "ID da organização"
+// @Param request body models.InviteMemberInput true "Convidado e papel"
+// @Success 201 {object} models.Response{data=models.Invitation}
+// @Failure 400 {object} models.APIError
+// @Failure 401 {object} models.APIError
+// @Failure 403 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Failure 409 {object} models.APIError
+// @Router /api/v1/orgs/{id}/invitations [post]
+func (h *OrganizationHandler) InviteMember(c *gin.Context) {
+	var input models.InviteMemberInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	invitation, err := h.orgs.InviteMember(c.Request.Context(), viewerFrom(c), c.Param("id"), &input)
+	if err != nil {
+		handleError(c, err, orgNotFound)
+		return
+	}
+
+	respond(c, http.StatusCreated, "Convite criado com sucesso", invitation)
+}
+
+// ListInvitations lista os convites de uma organização
+// @Summary Convites da organização
+// @Tags organizations
+// @Produce json
+// @Security Bearer
+// @Param id path string true "ID da organização"
+// @Success 200 {object} models.Response{data=[]models.Invitation}
+// @Failure 401 {object} models.APIError
+// @Failure 403 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Router /api/v1/orgs/{id}/invitations [get]
+func (h *OrganizationHandler) ListInvitations(c *gin.Context) {
+	invitations, err := h.orgs.ListInvitations(c.Request.Context(), viewerFrom(c), c.Param("id"))
+	if err != nil {
+		handleError(c, err, orgNotFound)
+		return
+	}
+
+	respond(c, http.StatusOK, "Convites recuperados com sucesso", invitations)
+}
+
+// AcceptInvitation aceita um convite para uma organização
+// @Summary Aceitar convite
+// @Description Adiciona o usuário autenticado à organização do convite. O convite vale uma única vez e apenas para a conta com o email convidado
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body models.AcceptInvitationInput true "Token do convite"
+// @Success 200 {object} models.Response{data=models.Membership}
+// @Failure 400 {object} models.APIError
+// @Failure 401 {object} models.APIError
+// @Failure 403 {object} models.APIError
+// @Router /api/v1/orgs/invitations/accept [post]
+func (h *OrganizationHandler) AcceptInvitation(c *gin.Context) {
+	var input models.AcceptInvitationInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	member, err := h.orgs.AcceptInvitation(c.Request.Context(), viewerFrom(c), input.Token)
+	if err != nil {
+		handleError(c, err, errcodes.WhenCause(repository.ErrInvitationNotFound, errcodes.InvitationInvalid))
+		return
+	}
+
+	respond(c, http.StatusOK, "Convite aceito com sucesso", member)
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"callable-api/internal/errcodes"
+	"callable-api/internal/models"
+	"callable-api/internal/recorder"
+)
+
+// RecordingHandler expõe aos administradores a gravação e o replay de requisições
+type RecordingHandler struct {
+	recorder *recorder.Recorder
+	router   http.Handler
+}
+
+// NewRecordingHandler cria um novo handler de gravações. O router é usado para
+// reproduzir as requisições contra o código atual
+func NewRecordingHandler(rec *recorder.Recorder, router http.Handler) *RecordingHandler {
+	return &RecordingHandler{
+		recorder: rec,
+		router:   router,
+	}
+}
+
+// GetSettings retorna a configuração atual da gravação
+// @Summary Configuração da gravação de requisições
+// @Tags admin
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} models.Response{data=models.RecordingSettings}
+// @Failure 401 {object} models.APIError
+// @Failure 403 {object} models.APIError
+// @Router /api/v1/admin/recordings/settings [get]
+func (h *RecordingHandler) GetSettings(c *gin.Context) {
+	respond(c, http.StatusOK, "Configuração recuperada com sucesso", h.recorder.Settings())
+}
+
+// UpdateSettings ativa ou desativa a gravação e define as rotas gravadas
+// @Summary Configurar gravação de requisições
+// @Description Define as rotas gravadas, no formato "POST /api/v1/data" ou "/api/v1/data" (todos os métodos)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body models.RecordingSettings true "Configuração da gravação"
+// @Success 200 {object} models.Response{data=models.RecordingSettings}
+// @Failure 400 {object} models.APIError
+// @Failure 401 {object} models.APIError
+// @Failure 403 {object} models.APIError
+// @Router /api/v1/admin/recordings/settings [put]
+func (h *RecordingHandler) UpdateSettings(c *gin.Context) {
+	var settings models.RecordingSettings
+	if !bindJSON(c, &settings) {
+		return
+	}
+
+	h.recorder.Configure(settings)
+	respond(c, http.StatusOK, "Configuração atualizada com sucesso", h.recorder.Settings())
+}
+
+// ListRecordings lista as gravações, mais recentes primeiro
+// @Summary Listar gravações de requisições
+// @Tags admin
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} models.Response{data=[]models.Recording}
+// @Failure 401 {object} models.APIError
+// @Failure 403 {object} models.APIError
+// @Router /api/v1/admin/recordings [get]
+func (h *RecordingHandler) ListRecordings(c *gin.Context) {
+	recordings, err := h.recorder.List(c.Request.Context())
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, "Gravações recuperadas com sucesso", recordings)
+}
+
+// ReplayRecording reproduz uma gravação contra o código atual e compara as respostas
+// @Summary Reproduzir gravação
+// @Description Reenvia a requisição gravada usando as credenciais de quem pede o replay
+// @Tags admin
+// @Produce json
+// @Security Bearer
+// @Param id path string true "ID da gravação"
+// @Success 200 {object} models.Response{data=models.ReplayResult}
+// @Failure 401 {object} models.APIError
+// @Failure 403 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Router /api/v1/admin/recordings/{id}/replay [post]
+func (h *RecordingHandler) ReplayRecording(c *gin.Context) {
+	result, err := h.recorder.Replay(c.Request.Context(), c.Param("id"), h.router, c.GetHeader("Authorization"))
+	if err != nil {
+		handleError(c, err, errcodes.When(errcodes.TypeNotFound, errcodes.RecordingNotFound))
+		return
+	}
+
+	respond(c, http.StatusOK, "Gravação reproduzida com sucesso", result)
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"callable-api/internal/models"
+	"callable-api/internal/pagination"
+	"callable-api/internal/redact"
+)
+
+// envelopeParam é o parâmetro que desativa o envelope (?envelope=false)
+const envelopeParam = "envelope"
+
+// bareProfile é o profile do Accept que pede o recurso sem envelope
+// (Accept: application/json; profile="bare")
+const bareProfile = "bare"
+
+// respond responde com o recurso dentro do envelope padrão (models.Response),
+// ou apenas com o recurso se o cliente optou por não usar o envelope. Os
+// campos marcados com redact:"true" nunca são serializados
+func respond(c *gin.Context, status int, message string, data interface{}) {
+	varyAccept(c)
+	if !wantsEnvelope(c) {
+		if data == nil {
+			c.Status(status)
+			return
+		}
+		writeJSON(c, status, redact.Value(data))
+		return
+	}
+
+	writeJSON(c, status, models.Response{
+		Status:  "success",
+		Message: message,
+		Data:    redact.Value(data),
+	})
+}
+
+// varyAccept indica aos caches que a resposta depende do Accept (com ou sem
+// envelope), preservando outros Vary já definidos, como o Origin do CORS
+func varyAccept(c *gin.Context) {
+	c.Writer.Header().Add("Vary", "Accept")
+}
+
+// respondList responde com uma ListResponse contendo os metadados de
+// paginação, e com o header Link para as páginas vizinhas. Sem envelope, os
+// metadados vão nos headers X-Total-Count, X-Page e X-Page-Size
+func respondList[T any](c *gin.Context, message string, data []T, p pagination.Params, total int) {
+	varyAccept(c)
+	c.Header("Link", pagination.Links(c.Request.URL, p, total))
+	if !wantsEnvelope(c) {
+		c.Header("X-Total-Count", strconv.Itoa(total))
+		c.Header("X-Page", strconv.Itoa(p.Page))
+		c.Header("X-Page-Size", strconv.Itoa(p.Limit))
+		writeJSON(c, http.StatusOK, redact.Value(data))
+		return
+	}
+
+	// Tipos com campos marcados com redact:"true" passam pela cópia sem esses
+	// campos; os demais são serializados diretamente no envelope tipado
+	if redact.Applies(reflect.TypeFor[T]()) {
+		writeJSON(c, http.StatusOK, models.ListResponse{
+			Status:    "success",
+			Message:   message,
+			Data:      redact.Value(data),
+			Page:      p.Page,
+			PageSize:  p.Limit,
+			TotalRows: total,
+		})
+		return
+	}
+
+	writeJSON(c, http.StatusOK, listEnvelope[T]{
+		Status:    "success",
+		Message:   message,
+		Data:      data,
+		Page:      p.Page,
+		PageSize:  p.Limit,
+		TotalRows: total,
+	})
+}
+
+// listEnvelope é a models.ListResponse com o tipo dos itens conhecido, para
+// que a lista seja serializada sem passar por interface{}
+type listEnvelope[T any] struct {
+	Status    string `json:"status"`
+	Message   string `json:"message"`
+	Data      []T    `json:"data"`
+	Page      int    `json:"page"`
+	PageSize  int    `json:"page_size"`
+	TotalRows int    `json:"total_rows"`
+}
+
+// wantsEnvelope indica se a resposta deve usar o envelope: o padrão, exceto
+// com ?envelope=false ou com o profile "bare" no Accept
+func wantsEnvelope(c *gin.Context) bool {
+	if enabled, err := strconv.ParseBool(c.Query(envelopeParam)); err == nil {
+		return enabled
+	}
+
+	for _, accepted := range strings.Split(c.GetHeader("Accept"), ",") {
+		_, params, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err == nil && params["profile"] == bareProfile {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,71 @@
+package handlers_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"callable-api/internal/filter"
+	"callable-api/internal/handlers"
+	"callable-api/internal/models"
+)
+
+// fixedItems responde GetItems sempre com a mesma página, para que os
+// benchmarks meçam apenas o handler e a serialização da resposta
+type fixedItems struct {
+	handlers.ItemServiceInterface
+	items []models.Item
+}
+
+func (s fixedItems) GetItems(ctx context.Context, viewer models.Viewer, page, limit int, conditions ...filter.Condition) ([]models.Item, int, error) {
+	return s.items, 1000, nil
+}
+
+// BenchmarkGetData mede a listagem de itens, com e sem o envelope:
+//
+//	go test -run '^$' -bench GetData -benchmem ./internal/handlers/
+func BenchmarkGetData(b *testing.B) {
+	gin.SetMode(gin.TestMode)
+
+	items := make([]models.Item, 50)
+	for i := range items {
+		items[i] = models.Item{
+			ID:          fmt.Sprintf("5f8d0e6e-6c0a-4f0a-8e0a-%012d", i),
+			Name:        fmt.Sprintf("Item %d", i),
+			Value:       fmt.Sprintf("BENCH-%04d", i),
+			Description: "Item usado nos benchmarks da listagem",
+			Email:       "bench@example.com",
+			OwnerID:     "1f0c2a4e-3b5d-4c6e-8f0a-1b2c3d4e5f6a",
+			CreatedAt:   "2023-05-22T14:56:32Z",
+		}
+	}
+
+	router := gin.New()
+	router.GET("/api/v1/data", handlers.NewItemHandler(fixedItems{items: items}).GetData)
+
+	for _, tc := range []struct {
+		name   string
+		accept string
+	}{
+		{"envelope", "application/json"},
+		{"bare", `application/json; profile="bare"`},
+	} {
+		b.Run(tc.name, func(b *testing.B) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/data?page=2&limit=50", nil)
+			req.Header.Set("Accept", tc.accept)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				if w.Code != http.StatusOK {
+					b.Fatalf("status %d", w.Code)
+				}
+			}
+		})
+	}
+}
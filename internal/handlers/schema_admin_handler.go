@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"callable-api/internal/jobs"
+	"callable-api/internal/models"
+)
+
+// schemaMigrationJobType é o tipo dos jobs de migração dos registros
+const schemaMigrationJobType = "schema.migrate"
+
+// SchemaMigrateFunc grava no formato atual os registros de versões
+// anteriores (ver repository.Versioning.Migrate)
+type SchemaMigrateFunc func(ctx context.Context) (*models.SchemaMigration, error)
+
+// SchemaAdminHandler processa a migração dos registros gravados
+type SchemaAdminHandler struct {
+	migrate SchemaMigrateFunc
+	jobs    JobScheduler
+}
+
+// NewSchemaAdminHandler cria um novo handler de migração dos registros
+func NewSchemaAdminHandler(migrate SchemaMigrateFunc, scheduler JobScheduler) *SchemaAdminHandler {
+	return &SchemaAdminHandler{migrate: migrate, jobs: scheduler}
+}
+
+// Migrate enfileira o job de migração dos registros
+// @Summary Migração dos registros
+// @Description Enfileira um job que grava no formato atual os itens e usuários gravados em versões anteriores do modelo. Os registros antigos já são atualizados na leitura; o job conclui a migração dos que não foram alterados desde então
+// @Tags admin
+// @Produce json
+// @Security Bearer
+// @Success 202 {object} models.Response{data=models.JobStatus}
+// @Failure 401 {object} models.APIError
+// @Failure 403 {object} models.APIError
+// @Failure 503 {object} models.APIError
+// @Router /api/v1/admin/schema/migrate [post]
+func (h *SchemaAdminHandler) Migrate(c *gin.Context) {
+	job, err := h.jobs.ScheduleJob(c.Request.Context(), viewerFrom(c).UserID, schemaMigrationJobType, func(ctx context.Context) (interface{}, error) {
+		jobs.Log(ctx, jobs.LogInfo, "Migrando registros para a versão atual", nil)
+		result, err := h.migrate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		jobs.Log(ctx, jobs.LogInfo, "Registros migrados", map[string]interface{}{
+			"items": result.Items.Migrated,
+			"users": result.Users.Migrated,
+		})
+		return result, nil
+	})
+	if err != nil {
+		respondScheduleError(c, err)
+		return
+	}
+
+	c.Header("Location", "/api/v1/jobs/"+job.ID)
+	respond(c, http.StatusAccepted, "Migração dos registros enfileirada", job)
+}
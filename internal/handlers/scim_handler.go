@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"callable-api/internal/scim"
+)
+
+// SCIMHandler expõe a API SCIM 2.0 de provisionamento de usuários
+type SCIMHandler struct {
+	service *scim.Service
+}
+
+// NewSCIMHandler cria um novo SCIMHandler
+func NewSCIMHandler(service *scim.Service) *SCIMHandler {
+	return &SCIMHandler{service: service}
+}
+
+// respondSCIM responde no tipo de mídia do SCIM
+func respondSCIM(c *gin.Context, status int, body interface{}) {
+	c.Header("Content-Type", scim.ContentType)
+	c.JSON(status, body)
+}
+
+// respondSCIMError responde com o erro no formato SCIM
+func respondSCIMError(c *gin.Context, err error) {
+	scimErr, ok := err.(*scim.Error)
+	if !ok {
+		scimErr = scim.NewError(http.StatusInternalServerError, "", "Erro interno do servidor")
+	}
+	c.Header("Content-Type", scim.ContentType)
+	c.AbortWithStatusJSON(scimErr.StatusCode(), scimErr)
+}
+
+// bindSCIM lê o corpo JSON da requisição, respondendo 400 se for inválido
+func bindSCIM(c *gin.Context, obj interface{}) bool {
+	if err := c.ShouldBindJSON(obj); err != nil {
+		respondSCIMError(c, scim.NewError(http.StatusBadRequest, "invalidSyntax", "Corpo da requisição inválido"))
+		return false
+	}
+	return true
+}
+
+// CreateUser provisiona um usuário
+// @Summary Provisionar usuário (SCIM)
+// @Tags scim
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body scim.User true "Recurso SCIM do usuário"
+// @Success 201 {object} scim.User
+// @Failure 400 {object} scim.Error
+// @Failure 401 {object} scim.Error
+// @Failure 409 {object} scim.Error
+// @Router /scim/v2/Users [post]
+func (h *SCIMHandler) CreateUser(c *gin.Context) {
+	var resource scim.User
+	if !bindSCIM(c, &resource) {
+		return
+	}
+
+	user, err := h.service.Create(&resource)
+	if err != nil {
+		respondSCIMError(c, err)
+		return
+	}
+
+	c.Header("Location", user.Meta.Location)
+	respondSCIM(c, http.StatusCreated, user)
+}
+
+// ListUsers lista os usuários, com filtro opcional
+// @Summary Listar usuários (SCIM)
+// @Tags scim
+// @Produce json
+// @Security Bearer
+// @Param filter query string false "Filtro SCIM (ex.: userName eq \"ana@example.com\")"
+// @Param startIndex query int false "Posição do primeiro resultado" default(1)
+// @Param count query int false "Resultados por página" default(100)
+// @Success 200 {object} scim.ListResponse
+// @Failure 400 {object} scim.Error
+// @Failure 401 {object} scim.Error
+// @Router /scim/v2/Users [get]
+func (h *SCIMHandler) ListUsers(c *gin.Context) {
+	startIndex, err := strconv.Atoi(c.DefaultQuery("startIndex", "1"))
+	if err != nil {
+		respondSCIMError(c, scim.NewError(http.StatusBadRequest, "invalidValue", "startIndex inválido"))
+		return
+	}
+	count, err := strconv.Atoi(c.DefaultQuery("count", strconv.Itoa(scim.DefaultCount)))
+	if err != nil {
+		respondSCIMError(c, scim.NewError(http.StatusBadRequest, "invalidValue", "count inválido"))
+		return
+	}
+
+	list, err := h.service.List(c.Query("filter"), startIndex, count)
+	if err != nil {
+		respondSCIMError(c, err)
+		return
+	}
+
+	respondSCIM(c, http.StatusOK, list)
+}
+
+// GetUser retorna um usuário
+// @Summary Consultar usuário (SCIM)
+// @Tags scim
+// @Produce json
+// @Security Bearer
+// @Param id path string true "ID do usuário"
+// @Success 200 {object} scim.User
+// @Failure 401 {object} scim.Error
+// @Failure 404 {object} scim.Error
+// @Router /scim/v2/Users/{id} [get]
+func (h *SCIMHandler) GetUser(c *gin.Context) {
+	user, err := h.service.Get(c.Param("id"))
+	if err != nil {
+		respondSCIMError(c, err)
+		return
+	}
+
+	respondSCIM(c, http.StatusOK, user)
+}
+
+// PatchUser altera atributos do usuário; active=false desativa a conta
+// @Summary Alterar usuário (SCIM)
+// @Tags scim
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "ID do usuário"
+// @Param request body scim.PatchRequest true "Operações PATCH"
+// @Success 200 {object} scim.User
+// @Failure 400 {object} scim.Error
+// @Failure 401 {object} scim.Error
+// @Failure 404 {object} scim.Error
+// @Failure 409 {object} scim.Error
+// @Router /scim/v2/Users/{id} [patch]
+func (h *SCIMHandler) PatchUser(c *gin.Context) {
+	var patch scim.PatchRequest
+	if !bindSCIM(c, &patch) {
+		return
+	}
+
+	user, err := h.service.Patch(c.Param("id"), &patch)
+	if err != nil {
+		respondSCIMError(c, err)
+		return
+	}
+
+	respondSCIM(c, http.StatusOK, user)
+}
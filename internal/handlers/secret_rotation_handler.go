@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"callable-api/internal/errcodes"
+	"callable-api/internal/rotation"
+	"callable-api/pkg/errors"
+	"callable-api/pkg/logger"
+)
+
+// SecretRotationHandler recebe as notificações de rotação do Secret Manager
+type SecretRotationHandler struct {
+	service *rotation.Service
+}
+
+// NewSecretRotationHandler cria um novo SecretRotationHandler
+func NewSecretRotationHandler(service *rotation.Service) *SecretRotationHandler {
+	return &SecretRotationHandler{service: service}
+}
+
+// HandleNotification processa uma entrega push do Pub/Sub
+// @Summary Notificação de rotação de segredo
+// @Description Recebe as notificações do Secret Manager entregues por push do Pub/Sub (token da assinatura no header Authorization ou no parâmetro token). O segredo é descartado do cache e, em rotações e novas versões, os consumidores (ex.: o segredo do JWT) recarregam a nova versão. Falhas ao recarregar respondem 500 para que o Pub/Sub entregue de novo
+// @Tags internal
+// @Accept json
+// @Produce json
+// @Param notification body rotation.PushRequest true "Entrega push do Pub/Sub"
+// @Success 200 {object} models.Response{data=models.SecretRotation}
+// @Failure 400 {object} models.APIError
+// @Failure 401 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /internal/secrets/rotation [post]
+func (h *SecretRotationHandler) HandleNotification(c *gin.Context) {
+	var push rotation.PushRequest
+	if !bindJSON(c, &push) {
+		return
+	}
+
+	result, err := h.service.Handle(c.Request.Context(), push.Message)
+	if stderrors.Is(err, rotation.ErrInvalidNotification) {
+		handleError(c, errors.NewBadRequestError("Notificação sem o atributo secretId", err),
+			errcodes.WhenCause(rotation.ErrInvalidNotification, errcodes.InvalidSecretEvent))
+		return
+	}
+	if err != nil {
+		logger.Error("Falha ao processar rotação de segredo", map[string]interface{}{
+			"messageId": push.Message.MessageID,
+			"error":     err.Error(),
+		})
+		handleError(c, errors.NewInternalServerError("Falha ao recarregar o segredo rotacionado", err))
+		return
+	}
+
+	logger.Info("Notificação de segredo processada", map[string]interface{}{
+		"secret":      result.Secret,
+		"eventType":   result.EventType,
+		"invalidated": result.Invalidated,
+		"reloaded":    result.Reloaded,
+	})
+	respond(c, http.StatusOK, "Notificação de segredo processada", result)
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"callable-api/internal/errcodes"
+	"callable-api/internal/events"
+	"callable-api/internal/models"
+	"callable-api/internal/streams"
+)
+
+// drainingEvent é o evento SSE enviado às conexões abertas quando o servidor
+// inicia o encerramento
+const drainingEvent = "server.draining"
+
+// sseWriter escreve eventos Server-Sent Events na resposta
+type sseWriter struct {
+	c *gin.Context
+}
+
+// subscribeSSE registra a conexão no hub antes que a resposta comece, para
+// que nenhum evento se perca entre a consulta inicial e o início do stream.
+// Se o servidor está em encerramento, responde 503 com Retry-After e retorna nil
+func subscribeSSE(c *gin.Context, hub *streams.Hub, filter func(envelope *events.Envelope) bool) *streams.Subscription {
+	sub, err := hub.Subscribe(filter)
+	if stderrors.Is(err, streams.ErrDraining) {
+		errcodes.RespondRetry(c, models.APIError{
+			Code:      http.StatusServiceUnavailable,
+			Status:    "error",
+			ErrorCode: errcodes.ServerDraining,
+			Message:   "Server is draining; reconnect to another instance",
+		}, hub.Config().ReconnectAfter)
+		return nil
+	}
+	return sub
+}
+
+// startSSE envia os headers do stream e o intervalo de reconexão sugerido
+func startSSE(c *gin.Context, reconnectAfter time.Duration) *sseWriter {
+	// O stream dura mais que o WriteTimeout do servidor
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no") // Sem buffer nos proxies (nginx)
+	c.Status(http.StatusOK)
+
+	w := &sseWriter{c: c}
+	w.retry(reconnectAfter)
+	return w
+}
+
+// retry envia o intervalo de reconexão sugerido ao cliente
+func (w *sseWriter) retry(after time.Duration) {
+	if after > 0 {
+		fmt.Fprintf(w.c.Writer, "retry: %d\n\n", after.Milliseconds())
+		w.c.Writer.Flush()
+	}
+}
+
+// event envia um evento com os dados serializados em JSON
+func (w *sseWriter) event(name string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w.c.Writer, "event: %s\ndata: %s\n\n", name, payload); err != nil {
+		return err
+	}
+	w.c.Writer.Flush()
+	return nil
+}
+
+// heartbeat envia um comentário, que mantém a conexão aberta nos proxies
+func (w *sseWriter) heartbeat() error {
+	if _, err := fmt.Fprint(w.c.Writer, ": ping\n\n"); err != nil {
+		return err
+	}
+	w.c.Writer.Flush()
+	return nil
+}
+
+// draining avisa o cliente do encerramento do servidor, com o intervalo
+// sugerido para se reconectar (a outra instância, pelo balanceador)
+func (w *sseWriter) draining(reconnectAfter time.Duration) {
+	w.retry(reconnectAfter)
+	_ = w.event(drainingEvent, gin.H{
+		"message":            "Server is draining; reconnect to another instance",
+		"reconnect_after_ms": reconnectAfter.Milliseconds(),
+	})
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+
+	"callable-api/internal/messages"
+	"callable-api/internal/models"
+	"callable-api/internal/routes"
+)
+
+// ArrayLimits limita os corpos das rotas que recebem listas de elementos
+type ArrayLimits struct {
+	// MaxBytes é o tamanho máximo do corpo
+	MaxBytes int64
+
+	// MaxItems é o número máximo de elementos do array
+	MaxItems int
+}
+
+// DefaultArrayLimits retorna os limites padrão: 5 MiB e 500 elementos
+func DefaultArrayLimits() ArrayLimits {
+	return ArrayLimits{MaxBytes: 5 << 20, MaxItems: 500}
+}
+
+// errTooManyElements indica um array com mais elementos que ArrayLimits.MaxItems
+var errTooManyElements = stderrors.New("número máximo de elementos excedido")
+
+// elementError identifica o elemento do array que falhou na decodificação ou
+// na validação
+type elementError struct {
+	index int
+	err   error
+}
+
+func (e *elementError) Error() string {
+	return fmt.Sprintf("elemento %d: %v", e.index, e.err)
+}
+
+func (e *elementError) Unwrap() error {
+	return e.err
+}
+
+// decodeArray lê o corpo como um array JSON, um elemento por vez, validando
+// cada elemento pelas tags binding assim que é lido e repassando-o a fn. O
+// corpo nunca é lido inteiro em memória: corpos maiores que limits.MaxBytes
+// (pelo Content-Length ou durante a leitura), arrays com mais de
+// limits.MaxItems elementos e o primeiro elemento inválido encerram a leitura.
+// Nesses casos responde 413 ou 400 (com as violações prefixadas pelo índice,
+// ex.: "[3].name") e retorna false. Nas rotas estritas, campos desconhecidos
+// são rejeitados
+func decodeArray[T any](c *gin.Context, limits ArrayLimits, fn func(index int, element *T)) bool {
+	if limits.MaxBytes > 0 && c.Request.ContentLength > limits.MaxBytes {
+		abortTooLarge(c, limits)
+		return false
+	}
+
+	err := streamArray(c, limits, fn)
+	if err == nil {
+		return true
+	}
+
+	var maxBytesErr *http.MaxBytesError
+	if stderrors.As(err, &maxBytesErr) || stderrors.Is(err, errTooManyElements) {
+		abortTooLarge(c, limits)
+		return false
+	}
+
+	var elemErr *elementError
+	if !stderrors.As(err, &elemErr) {
+		// O corpo não é um array JSON
+		locale := messages.Locale(c.Request.Context())
+		c.AbortWithStatusJSON(models.ErrInvalidInput.Code, models.ErrInvalidInput.WithViolations([]models.FieldViolation{{
+			Field:   "request",
+			Rule:    messages.InvalidFormat,
+			Message: messages.Text(locale, messages.InvalidFormat, "request"),
+		}}))
+		return false
+	}
+
+	violations := bindingViolations(messages.Locale(c.Request.Context()), elemErr.err)
+	for i := range violations {
+		violations[i].Field = fmt.Sprintf("[%d].%s", elemErr.index, violations[i].Field)
+	}
+	c.AbortWithStatusJSON(models.ErrInvalidInput.Code, models.ErrInvalidInput.WithViolations(violations))
+	return false
+}
+
+// streamArray implementa decodeArray
+func streamArray[T any](c *gin.Context, limits ArrayLimits, fn func(index int, element *T)) error {
+	body := io.Reader(c.Request.Body)
+	if limits.MaxBytes > 0 {
+		body = http.MaxBytesReader(c.Writer, c.Request.Body, limits.MaxBytes)
+	}
+
+	decoder := json.NewDecoder(body)
+	if routes.Strict(c) {
+		decoder.DisallowUnknownFields()
+	}
+
+	if token, err := decoder.Token(); err != nil {
+		return err
+	} else if token != json.Delim('[') {
+		return fmt.Errorf("esperado um array JSON, recebido %v", token)
+	}
+
+	for index := 0; decoder.More(); index++ {
+		if limits.MaxItems > 0 && index >= limits.MaxItems {
+			return errTooManyElements
+		}
+
+		var element T
+		if err := decoder.Decode(&element); err != nil {
+			var syntaxErr *json.SyntaxError
+			var maxBytesErr *http.MaxBytesError
+			if stderrors.As(err, &syntaxErr) || stderrors.As(err, &maxBytesErr) || stderrors.Is(err, io.ErrUnexpectedEOF) {
+				return err
+			}
+			return &elementError{index: index, err: err}
+		}
+		if err := binding.Validator.ValidateStruct(&element); err != nil {
+			return &elementError{index: index, err: err}
+		}
+		fn(index, &element)
+	}
+
+	// Fecha o array; conteúdo depois dele é rejeitado
+	if _, err := decoder.Token(); err != nil {
+		return err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return fmt.Errorf("conteúdo após o array JSON")
+	}
+	return nil
+}
+
+// abortTooLarge responde 413 informando os limites da rota
+func abortTooLarge(c *gin.Context, limits ArrayLimits) {
+	c.AbortWithStatusJSON(models.ErrPayloadTooLarge.Code, models.ErrPayloadTooLarge.WithDetails(
+		fmt.Sprintf("máximo de %d bytes e %d elementos", limits.MaxBytes, limits.MaxItems)))
+}
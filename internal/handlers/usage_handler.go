@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"callable-api/internal/middleware"
+	"callable-api/internal/quota"
+)
+
+// UsageHandler expõe o consumo da cota de requisições do usuário
+type UsageHandler struct {
+	tracker *quota.Tracker
+}
+
+// NewUsageHandler cria um novo handler de consumo de cota
+func NewUsageHandler(tracker *quota.Tracker) *UsageHandler {
+	return &UsageHandler{
+		tracker: tracker,
+	}
+}
+
+// GetUsage retorna o consumo da cota do usuário autenticado na janela atual
+// @Summary Consumo da cota
+// @Description Retorna o limite de requisições, o total usado e quando a janela atual termina
+// @Tags auth
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} models.Response{data=models.QuotaUsage}
+// @Failure 401 {object} models.APIError
+// @Router /api/v1/auth/usage [get]
+func (h *UsageHandler) GetUsage(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	respond(c, http.StatusOK, "Uso recuperado com sucesso", h.tracker.Usage(middleware.QuotaKey(userID)))
+}
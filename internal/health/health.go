@@ -0,0 +1,85 @@
+// Package health verifica a disponibilidade das dependências externas da
+// aplicação (busca, segredos, armazenamento) medindo a latência de cada uma.
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"callable-api/internal/models"
+)
+
+// Estados possíveis de uma dependência
+const (
+	StatusUp       = "up"
+	StatusDown     = "down"
+	StatusDegraded = "degraded" // integração configurada, mas falhando (ver cloud.StateDegraded)
+)
+
+// Checker verifica uma dependência
+type Checker interface {
+	Name() string
+	Check(ctx context.Context) error
+}
+
+// checkFunc adapta uma função a Checker
+type checkFunc struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+func (c checkFunc) Name() string                    { return c.name }
+func (c checkFunc) Check(ctx context.Context) error { return c.fn(ctx) }
+
+// NewCheck cria um Checker a partir de uma função
+func NewCheck(name string, fn func(ctx context.Context) error) Checker {
+	return checkFunc{name: name, fn: fn}
+}
+
+// Run executa as verificações em paralelo, cada uma limitada pelo timeout, e
+// retorna os resultados na ordem dos checkers
+func Run(ctx context.Context, timeout time.Duration, checkers ...Checker) []models.DependencyStatus {
+	results := make([]models.DependencyStatus, len(checkers))
+
+	var wg sync.WaitGroup
+	for i, checker := range checkers {
+		wg.Add(1)
+		go func(i int, checker Checker) {
+			defer wg.Done()
+			results[i] = run(ctx, timeout, checker)
+		}(i, checker)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// run executa uma única verificação
+func run(ctx context.Context, timeout time.Duration, checker Checker) models.DependencyStatus {
+	checkCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := checker.Check(checkCtx)
+	result := models.DependencyStatus{
+		Name:      checker.Name(),
+		Status:    StatusUp,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// Healthy indica se todas as dependências estão disponíveis
+func Healthy(results []models.DependencyStatus) bool {
+	for _, result := range results {
+		if result.Status != StatusUp {
+			return false
+		}
+	}
+	return true
+}
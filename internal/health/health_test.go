@@ -0,0 +1,63 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"callable-api/internal/clock"
+	"callable-api/internal/models"
+)
+
+func TestRun(t *testing.T) {
+	results := Run(context.Background(), 50*time.Millisecond,
+		NewCheck("ok", func(ctx context.Context) error { return nil }),
+		NewCheck("failing", func(ctx context.Context) error { return errors.New("connection refused") }),
+		NewCheck("slow", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}),
+	)
+
+	assert.Len(t, results, 3)
+	assert.Equal(t, "ok", results[0].Name)
+	assert.Equal(t, StatusUp, results[0].Status)
+	assert.Equal(t, StatusDown, results[1].Status)
+	assert.Equal(t, "connection refused", results[1].Error)
+	assert.Equal(t, StatusDown, results[2].Status)
+	assert.GreaterOrEqual(t, results[2].LatencyMs, float64(50))
+
+	assert.False(t, Healthy(results))
+	assert.True(t, Healthy(results[:1]))
+}
+
+func TestReadiness(t *testing.T) {
+	now := clock.NewFake(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	readiness := NewReadiness().WithClock(now)
+	assert.False(t, readiness.Status().Draining)
+
+	status := readiness.Drain()
+	assert.True(t, status.Draining)
+	assert.Equal(t, now.Now(), *status.Since)
+
+	// Drenar de novo mantém o horário do primeiro dreno
+	now.Advance(time.Minute)
+	assert.Equal(t, *status.Since, *readiness.Drain().Since)
+
+	status = readiness.Undrain()
+	assert.False(t, status.Draining)
+	assert.Nil(t, status.Since)
+
+	// Integrações degradadas não tiram a instância do balanceador
+	assert.Equal(t, "ready", readiness.Report().Status)
+	readiness.WithDependencies(func() []models.ServiceState {
+		return []models.ServiceState{{Name: "cloud_storage", State: StatusDegraded}}
+	})
+	report := readiness.Report()
+	assert.Equal(t, StatusDegraded, report.Status)
+	assert.False(t, report.Draining)
+	assert.Len(t, report.Dependencies, 1)
+}
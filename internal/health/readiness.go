@@ -0,0 +1,94 @@
+package health
+
+import (
+	"sync"
+	"time"
+
+	"callable-api/internal/clock"
+	"callable-api/internal/models"
+)
+
+// Readiness indica se a instância deve receber tráfego novo do balanceador.
+// Durante o dreno manual, usado na rotação dos nós, a verificação de
+// prontidão falha para que o balanceador deixe de enviar requisições, mas as
+// que chegarem (em andamento ou repetidas pelos clientes) continuam sendo
+// atendidas normalmente
+type Readiness struct {
+	clock        clock.Clock
+	dependencies func() []models.ServiceState
+
+	mu        sync.RWMutex
+	drainedAt time.Time
+}
+
+// NewReadiness cria uma Readiness pronta para receber tráfego
+func NewReadiness() *Readiness {
+	return &Readiness{clock: clock.System()}
+}
+
+// WithClock define o relógio usado no horário do dreno
+func (r *Readiness) WithClock(c clock.Clock) *Readiness {
+	r.clock = c
+	return r
+}
+
+// WithDependencies define a origem do estado das integrações externas
+// incluído em Report (ex.: cloud.Monitor.States)
+func (r *Readiness) WithDependencies(states func() []models.ServiceState) *Readiness {
+	r.dependencies = states
+	return r
+}
+
+// Drain inicia o dreno. Chamadas repetidas mantêm o horário do primeiro
+func (r *Readiness) Drain() models.DrainStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.drainedAt.IsZero() {
+		r.drainedAt = r.clock.Now().UTC()
+	}
+	return r.status()
+}
+
+// Undrain encerra o dreno: a instância volta a receber tráfego
+func (r *Readiness) Undrain() models.DrainStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.drainedAt = time.Time{}
+	return r.status()
+}
+
+// Status retorna o estado atual do dreno
+func (r *Readiness) Status() models.DrainStatus {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.status()
+}
+
+// Report retorna o estado do dreno e das integrações externas. A instância
+// está pronta enquanto não estiver em dreno; integrações degradadas apenas
+// mudam o status para "degraded"
+func (r *Readiness) Report() models.ReadinessReport {
+	drain := r.Status()
+	report := models.ReadinessReport{Status: "ready", Draining: drain.Draining, Since: drain.Since}
+	if drain.Draining {
+		report.Status = "draining"
+	}
+	if r.dependencies != nil {
+		report.Dependencies = r.dependencies()
+	}
+	for _, dependency := range report.Dependencies {
+		if dependency.State == StatusDegraded && !drain.Draining {
+			report.Status = StatusDegraded
+		}
+	}
+	return report
+}
+
+// status monta o estado; deve ser chamado com o mutex travado
+func (r *Readiness) status() models.DrainStatus {
+	if r.drainedAt.IsZero() {
+		return models.DrainStatus{}
+	}
+	since := r.drainedAt
+	return models.DrainStatus{Draining: true, Since: &since}
+}
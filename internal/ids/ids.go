@@ -0,0 +1,201 @@
+// Package ids gera os identificadores das entidades. O formato é
+// configurável por entidade: UUID v4 (aleatório), UUID v7 e ULID (ordenáveis
+// pela hora de criação, o que mantém os índices dos bancos SQL compactos) ou
+// uma sequência numérica, usada pelos itens de demonstração.
+package ids
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Formatos de ID suportados
+const (
+	FormatUUID     = "uuid"
+	FormatUUIDv7   = "uuidv7"
+	FormatULID     = "ulid"
+	FormatSequence = "sequence"
+)
+
+// Entidades com formato de ID configurável
+const (
+	EntityItems         = "items"
+	EntityUsers         = "users"
+	EntityComments      = "comments"
+	EntityShares        = "shares"
+	EntityJobs          = "jobs"
+	EntityLoginAttempts = "login_attempts"
+	EntityNotifications = "notifications"
+	EntityOrganizations = "organizations"
+	EntityInvitations   = "invitations"
+	EntityConsents      = "consents"
+)
+
+// Generator gera novos IDs. As implementações são seguras para uso concorrente
+type Generator interface {
+	NewID() string
+}
+
+// Func adapta uma função a Generator
+type Func func() string
+
+// NewID implementa Generator
+func (f Func) NewID() string {
+	return f()
+}
+
+// New retorna o gerador do formato informado
+func New(format string) (Generator, error) {
+	switch format {
+	case FormatUUID:
+		return UUID(), nil
+	case FormatUUIDv7:
+		return UUIDv7(), nil
+	case FormatULID:
+		return ULID(), nil
+	case FormatSequence:
+		return Sequence(), nil
+	}
+	return nil, fmt.Errorf("formato de ID desconhecido: %q", format)
+}
+
+// UUID gera UUIDs v4 (aleatórios)
+func UUID() Generator {
+	return Func(func() string { return uuid.New().String() })
+}
+
+// UUIDv7 gera UUIDs v7, que começam pela hora de criação em milissegundos
+func UUIDv7() Generator {
+	return Func(func() string {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return uuid.New().String()
+		}
+		return id.String()
+	})
+}
+
+// Sequence gera números sequenciais a partir de 1
+func Sequence() Generator {
+	var last atomic.Int64
+	return Func(func() string { return strconv.FormatInt(last.Add(1), 10) })
+}
+
+// crockford é o alfabeto Base32 de Crockford usado pelos ULIDs
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ulidGenerator gera ULIDs monotônicos: IDs gerados no mesmo milissegundo
+// incrementam a parte aleatória do anterior, mantendo a ordem
+type ulidGenerator struct {
+	mutex   sync.Mutex
+	lastMs  uint64
+	entropy [10]byte
+}
+
+// ULID gera ULIDs (26 caracteres, ordenáveis pela hora de criação)
+func ULID() Generator {
+	return &ulidGenerator{}
+}
+
+// NewID implementa Generator
+func (g *ulidGenerator) NewID() string {
+	g.mutex.Lock()
+	ms := uint64(time.Now().UnixMilli())
+	if ms <= g.lastMs {
+		ms = g.lastMs
+		increment(g.entropy[:])
+	} else {
+		g.lastMs = ms
+		_, _ = rand.Read(g.entropy[:])
+	}
+
+	var id [16]byte
+	binary.BigEndian.PutUint16(id[0:2], uint16(ms>>32))
+	binary.BigEndian.PutUint32(id[2:6], uint32(ms))
+	copy(id[6:], g.entropy[:])
+	g.mutex.Unlock()
+
+	return encodeULID(id)
+}
+
+// increment soma 1 ao número big-endian em b
+func increment(b []byte) {
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i]++
+		if b[i] != 0 {
+			return
+		}
+	}
+}
+
+// encodeULID codifica os 128 bits do ULID em 26 caracteres Base32
+func encodeULID(id [16]byte) string {
+	hi := binary.BigEndian.Uint64(id[:8])
+	lo := binary.BigEndian.Uint64(id[8:])
+
+	var out [26]byte
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i] = crockford[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:])
+}
+
+// Config define o formato de ID de cada entidade
+type Config struct {
+	Default  string            // formato das entidades sem configuração própria
+	Entities map[string]string // formato por entidade (ex.: "items": "ulid")
+}
+
+// DefaultConfig retorna a configuração padrão: UUID v4, exceto os itens, que
+// usam a sequência numérica dos dados de exemplo
+func DefaultConfig() Config {
+	return Config{
+		Default:  FormatUUID,
+		Entities: map[string]string{EntityItems: FormatSequence},
+	}
+}
+
+// Entities lista as entidades com formato de ID configurável
+func Entities() []string {
+	return []string{EntityItems, EntityUsers, EntityComments, EntityShares, EntityJobs, EntityLoginAttempts, EntityNotifications, EntityOrganizations, EntityInvitations, EntityConsents}
+}
+
+// Format retorna o formato de ID da entidade
+func (c Config) Format(entity string) string {
+	if format, ok := c.Entities[entity]; ok && format != "" {
+		return format
+	}
+	if c.Default != "" {
+		return c.Default
+	}
+	return FormatUUID
+}
+
+// Validate verifica os formatos configurados
+func (c Config) Validate() error {
+	for _, entity := range Entities() {
+		if _, err := New(c.Format(entity)); err != nil {
+			return fmt.Errorf("%s: %w", entity, err)
+		}
+	}
+	return nil
+}
+
+// Generator retorna um novo gerador para a entidade. Formatos inválidos
+// (recusados por Validate na inicialização) usam UUID v4
+func (c Config) Generator(entity string) Generator {
+	gen, err := New(c.Format(entity))
+	if err != nil {
+		return UUID()
+	}
+	return gen
+}
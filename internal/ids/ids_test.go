@@ -0,0 +1,52 @@
+package ids
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerators(t *testing.T) {
+	v7, err := uuid.Parse(UUIDv7().NewID())
+	require.NoError(t, err)
+	assert.Equal(t, uuid.Version(7), v7.Version())
+
+	seq := Sequence()
+	assert.Equal(t, "1", seq.NewID())
+	assert.Equal(t, "2", seq.NewID())
+
+	_, err = New("snowflake")
+	assert.Error(t, err)
+}
+
+func TestULID(t *testing.T) {
+	gen := ULID()
+
+	// IDs gerados em sequência, mesmo no mesmo milissegundo, já saem ordenados
+	generated := make([]string, 1000)
+	for i := range generated {
+		generated[i] = gen.NewID()
+	}
+	assert.True(t, sort.StringsAreSorted(generated))
+	assert.Len(t, generated[0], 26)
+	assert.NotEqual(t, generated[0], generated[1])
+
+	// O prefixo codifica a hora: 48 bits em 10 caracteres
+	var zero [16]byte
+	assert.Equal(t, "00000000000000000000000000", encodeULID(zero))
+	zero[5] = 1
+	assert.Equal(t, "00000000010000000000000000", encodeULID(zero))
+}
+
+func TestConfig(t *testing.T) {
+	cfg := DefaultConfig()
+	assert.Equal(t, FormatSequence, cfg.Format(EntityItems))
+	assert.Equal(t, FormatUUID, cfg.Format(EntityUsers))
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Entities[EntityJobs] = "snowflake"
+	assert.Error(t, cfg.Validate())
+}
@@ -0,0 +1,228 @@
+package jobs
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+	"sync"
+	"time"
+
+	"callable-api/internal/models"
+	"callable-api/pkg/cloud"
+	"callable-api/pkg/errors"
+	"callable-api/pkg/retry"
+)
+
+// artifactURLTTL é a validade das URLs assinadas geradas a cada download
+const artifactURLTTL = 15 * time.Minute
+
+// ErrNoArtifactStore é retornado quando um job produz um File sem que haja
+// armazenamento de artefatos configurado
+var ErrNoArtifactStore = stderrors.New("armazenamento de artefatos não configurado")
+
+// EncodingGzip indica um File com o conteúdo comprimido com gzip
+const EncodingGzip = "gzip"
+
+// File é um resultado de job gravado como artefato, como uma exportação ou um
+// relatório. O status do job traz apenas o link de download (models.JobArtifact)
+type File struct {
+	Name        string
+	ContentType string
+	Data        []byte
+
+	// Encoding é EncodingGzip quando Data está comprimido (ver
+	// Config.ArtifactCompression); vazio para o conteúdo original
+	Encoding string
+}
+
+// Decoded retorna o conteúdo original do arquivo, descomprimindo-o se necessário
+func (f *File) Decoded() ([]byte, error) {
+	if f.Encoding != EncodingGzip {
+		return f.Data, nil
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(f.Data))
+	if err != nil {
+		return nil, fmt.Errorf("artefato gzip inválido: %w", err)
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+// Compressible indica se o tipo de conteúdo é texto que se beneficia da
+// compressão (CSV, JSON, XML), ao contrário de imagens e arquivos já comprimidos
+func Compressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	switch {
+	case strings.HasPrefix(mediaType, "text/"),
+		strings.HasSuffix(mediaType, "+json"),
+		strings.HasSuffix(mediaType, "+xml"):
+		return true
+	}
+	switch mediaType {
+	case "application/json", "application/x-ndjson", "application/xml", "application/csv":
+		return true
+	}
+	return false
+}
+
+// compress retorna uma cópia do arquivo com o conteúdo comprimido com gzip
+func compress(file *File) (*File, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(file.Data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	compressed := *file
+	compressed.Data = buf.Bytes()
+	compressed.Encoding = EncodingGzip
+	return &compressed, nil
+}
+
+// Artifact é um artefato armazenado: o conteúdo, quando mantido localmente,
+// ou a URL para onde o cliente deve ser redirecionado
+type Artifact struct {
+	File
+	RedirectURL string
+}
+
+// ArtifactStore persiste os artefatos dos jobs, identificados pelo ID do job
+type ArtifactStore interface {
+	Put(ctx context.Context, jobID string, file *File) error
+	Get(ctx context.Context, jobID string) (*Artifact, error)
+}
+
+// MemoryArtifactStore mantém os artefatos em memória (modo demo e testes)
+type MemoryArtifactStore struct {
+	mu    sync.RWMutex
+	files map[string]File
+}
+
+// NewMemoryArtifactStore cria um MemoryArtifactStore vazio
+func NewMemoryArtifactStore() *MemoryArtifactStore {
+	return &MemoryArtifactStore{files: make(map[string]File)}
+}
+
+// Put implementa ArtifactStore
+func (s *MemoryArtifactStore) Put(ctx context.Context, jobID string, file *File) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files[jobID] = *file
+	return nil
+}
+
+// Get implementa ArtifactStore
+func (s *MemoryArtifactStore) Get(ctx context.Context, jobID string) (*Artifact, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	file, exists := s.files[jobID]
+	if !exists {
+		return nil, errors.NewNotFoundError("Artefato não encontrado", nil)
+	}
+	return &Artifact{File: file}, nil
+}
+
+// CloudArtifactStore grava os artefatos no Cloud Storage em jobs/<id>. O
+// download é feito por URLs assinadas de curta duração, geradas a cada acesso
+type CloudArtifactStore struct {
+	storage cloud.Storage
+}
+
+// NewCloudArtifactStore cria um CloudArtifactStore
+func NewCloudArtifactStore(cloudStorage cloud.Storage) *CloudArtifactStore {
+	return &CloudArtifactStore{storage: cloudStorage}
+}
+
+// Put implementa ArtifactStore
+func (s *CloudArtifactStore) Put(ctx context.Context, jobID string, file *File) error {
+	err := retry.Do(ctx, retry.DefaultPolicy(), func(ctx context.Context) error {
+		return s.storage.UploadFile(ctx, "jobs/"+jobID, bytes.NewReader(file.Data))
+	})
+	if err != nil {
+		return errors.NewInternalServerError("Erro ao gravar artefato do job", err)
+	}
+	return nil
+}
+
+// Get implementa ArtifactStore
+func (s *CloudArtifactStore) Get(ctx context.Context, jobID string) (*Artifact, error) {
+	url, err := retry.DoValue(ctx, retry.DefaultPolicy(), func(ctx context.Context) (string, error) {
+		return s.storage.GetSignedURL(ctx, "jobs/"+jobID, artifactURLTTL)
+	})
+	if err != nil {
+		return nil, errors.NewInternalServerError("Erro ao gerar URL do artefato", err)
+	}
+	return &Artifact{RedirectURL: url}, nil
+}
+
+// WithArtifacts grava no armazenamento informado os resultados do tipo File e
+// os resultados cuja serialização passe de Config.ArtifactThreshold
+func (m *Manager) WithArtifacts(store ArtifactStore) *Manager {
+	m.artifacts = store
+	return m
+}
+
+// Artifact retorna o artefato do job se o usuário puder consultá-lo
+func (m *Manager) Artifact(ctx context.Context, viewer models.Viewer, id string) (*Artifact, error) {
+	job, err := m.GetJob(ctx, viewer, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := job.Result.(*models.JobArtifact); !ok || m.artifacts == nil {
+		return nil, errors.NewNotFoundError("Artefato não encontrado", nil)
+	}
+	return m.artifacts.Get(ctx, id)
+}
+
+// storeResult grava o resultado como artefato quando necessário, retornando
+// o valor exposto em JobStatus.Result
+func (m *Manager) storeResult(ctx context.Context, jobID string, result interface{}) (interface{}, error) {
+	file, isFile := result.(*File)
+	if !isFile {
+		if m.artifacts == nil || m.artifactThreshold <= 0 || result == nil {
+			return result, nil
+		}
+		data, err := json.Marshal(result)
+		if err != nil || len(data) <= m.artifactThreshold {
+			return result, nil
+		}
+		file = &File{Name: jobID + ".json", ContentType: "application/json", Data: data}
+	}
+
+	if m.artifacts == nil {
+		return nil, ErrNoArtifactStore
+	}
+
+	// Exportações grandes de texto são gravadas comprimidas
+	size := len(file.Data)
+	if m.artifactCompression > 0 && file.Encoding == "" && size >= m.artifactCompression && Compressible(file.ContentType) {
+		compressed, err := compress(file)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao comprimir artefato: %w", err)
+		}
+		file = compressed
+	}
+	// O artefato é gravado mesmo que o prazo do job tenha expirado
+	if err := m.artifacts.Put(context.WithoutCancel(ctx), jobID, file); err != nil {
+		return nil, err
+	}
+	return &models.JobArtifact{
+		URL:             "/api/v1/jobs/" + jobID + "/artifact",
+		Name:            file.Name,
+		ContentType:     file.ContentType,
+		ContentEncoding: file.Encoding,
+		Size:            size,
+	}, nil
+}
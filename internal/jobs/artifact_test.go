@@ -0,0 +1,112 @@
+package jobs
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"callable-api/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManagerArtifacts(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager(NewMemoryStore(), Config{Workers: 1, QueueSize: 10, ArtifactThreshold: 64}).
+		WithArtifacts(NewMemoryArtifactStore())
+	defer m.Close(ctx)
+	owner := models.Viewer{UserID: "u1"}
+
+	run := func(result interface{}) *models.JobStatus {
+		job, err := m.ScheduleJob(ctx, "u1", "export", func(ctx context.Context) (interface{}, error) {
+			return result, nil
+		})
+		require.NoError(t, err)
+		return waitFinished(t, m, owner, job.ID)
+	}
+
+	// Arquivos sempre viram artefatos; o status traz apenas o link de download
+	export := run(&File{Name: "items.csv", ContentType: "text/csv", Data: []byte("id,name\n1,a\n")})
+	require.Equal(t, models.JobStateCompleted, export.State)
+	ref, ok := export.Result.(*models.JobArtifact)
+	require.True(t, ok)
+	assert.Equal(t, "/api/v1/jobs/"+export.ID+"/artifact", ref.URL)
+	assert.Equal(t, 12, ref.Size)
+
+	artifact, err := m.Artifact(ctx, owner, export.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "text/csv", artifact.ContentType)
+	assert.Equal(t, "id,name\n1,a\n", string(artifact.Data))
+
+	_, err = m.Artifact(ctx, models.Viewer{UserID: "u2"}, export.ID)
+	assert.Error(t, err)
+
+	// Resultados acima do limite vão para o armazenamento; os menores ficam no status
+	large := run(map[string]string{"report": strings.Repeat("x", 100)})
+	ref, ok = large.Result.(*models.JobArtifact)
+	require.True(t, ok)
+	assert.Equal(t, "application/json", ref.ContentType)
+
+	small := run(map[string]string{"report": "x"})
+	assert.Equal(t, map[string]string{"report": "x"}, small.Result)
+	_, err = m.Artifact(ctx, owner, small.ID)
+	assert.Error(t, err)
+
+	// Sem armazenamento configurado, arquivos fazem o job falhar
+	plain := NewManager(NewMemoryStore(), Config{Workers: 1, QueueSize: 10})
+	defer plain.Close(ctx)
+	job, err := plain.ScheduleJob(ctx, "u1", "export", func(ctx context.Context) (interface{}, error) {
+		return &File{Name: "items.csv", Data: []byte("id")}, nil
+	})
+	require.NoError(t, err)
+	failed := waitFinished(t, plain, owner, job.ID)
+	assert.Equal(t, models.JobStateFailed, failed.State)
+	assert.Equal(t, ErrNoArtifactStore.Error(), failed.Error)
+}
+
+func TestManagerArtifacts_Compression(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager(NewMemoryStore(), Config{Workers: 1, QueueSize: 10, ArtifactCompression: 100}).
+		WithArtifacts(NewMemoryArtifactStore())
+	defer m.Close(ctx)
+	owner := models.Viewer{UserID: "u1"}
+
+	run := func(file *File) (*models.JobArtifact, *Artifact) {
+		job, err := m.ScheduleJob(ctx, "u1", "export", func(ctx context.Context) (interface{}, error) {
+			return file, nil
+		})
+		require.NoError(t, err)
+		ref, ok := waitFinished(t, m, owner, job.ID).Result.(*models.JobArtifact)
+		require.True(t, ok)
+		artifact, err := m.Artifact(ctx, owner, job.ID)
+		require.NoError(t, err)
+		return ref, artifact
+	}
+
+	// Exportações de texto grandes são gravadas comprimidas
+	csv := "id,name\n" + strings.Repeat("1,abc\n", 100)
+	ref, artifact := run(&File{Name: "items.csv", ContentType: "text/csv; charset=utf-8", Data: []byte(csv)})
+	assert.Equal(t, EncodingGzip, ref.ContentEncoding)
+	assert.Equal(t, len(csv), ref.Size)
+	assert.Equal(t, EncodingGzip, artifact.Encoding)
+	assert.Less(t, len(artifact.Data), len(csv))
+	decoded, err := artifact.Decoded()
+	require.NoError(t, err)
+	assert.Equal(t, csv, string(decoded))
+
+	// Arquivos pequenos e binários não
+	ref, _ = run(&File{Name: "small.csv", ContentType: "text/csv", Data: []byte("id\n")})
+	assert.Empty(t, ref.ContentEncoding)
+	ref, _ = run(&File{Name: "image.png", ContentType: "image/png", Data: make([]byte, 200)})
+	assert.Empty(t, ref.ContentEncoding)
+}
+
+func TestCompressible(t *testing.T) {
+	for _, contentType := range []string{"text/csv", "application/json", "application/problem+json", "text/plain; charset=utf-8"} {
+		assert.True(t, Compressible(contentType), contentType)
+	}
+	for _, contentType := range []string{"image/png", "application/gzip", "application/octet-stream", ""} {
+		assert.False(t, Compressible(contentType), contentType)
+	}
+}
@@ -0,0 +1,58 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+)
+
+// ErrNotInJob é retornado pelos checkpoints chamados fora da execução de um job
+var ErrNotInJob = stderrors.New("checkpoint fora da execução de um job")
+
+type checkpointKey struct{}
+
+// checkpointer grava os checkpoints do job em execução
+type checkpointer struct {
+	store Store
+	jobID string
+}
+
+// withCheckpointer associa ao contexto do job o acesso aos seus checkpoints
+func withCheckpointer(ctx context.Context, store Store, jobID string) context.Context {
+	return context.WithValue(ctx, checkpointKey{}, &checkpointer{store: store, jobID: jobID})
+}
+
+// SaveCheckpoint grava o progresso do job em execução sob o nome informado
+// (ex.: a última linha importada). Se o job for interrompido, a próxima
+// execução pode retomá-lo com LoadCheckpoint
+func SaveCheckpoint(ctx context.Context, name string, value interface{}) error {
+	cp, ok := ctx.Value(checkpointKey{}).(*checkpointer)
+	if !ok {
+		return ErrNotInJob
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("falha ao serializar checkpoint %s: %w", name, err)
+	}
+	return cp.store.SaveCheckpoint(ctx, cp.jobID, name, data)
+}
+
+// LoadCheckpoint lê em value o último checkpoint gravado com o nome
+// informado, retornando false se o job ainda não o gravou
+func LoadCheckpoint(ctx context.Context, name string, value interface{}) (bool, error) {
+	cp, ok := ctx.Value(checkpointKey{}).(*checkpointer)
+	if !ok {
+		return false, ErrNotInJob
+	}
+
+	data, err := cp.store.Checkpoint(ctx, cp.jobID, name)
+	if err != nil || data == nil {
+		return false, err
+	}
+	if err := json.Unmarshal(data, value); err != nil {
+		return false, fmt.Errorf("falha ao ler checkpoint %s: %w", name, err)
+	}
+	return true, nil
+}
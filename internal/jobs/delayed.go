@@ -0,0 +1,183 @@
+package jobs
+
+import (
+	"container/heap"
+	"context"
+	stderrors "errors"
+	"sync"
+	"time"
+
+	"callable-api/internal/correlation"
+	"callable-api/internal/models"
+	"callable-api/pkg/logger"
+)
+
+// ErrRunAtTooFar é retornado ao agendar um job para além de Config.MaxDelay
+var ErrRunAtTooFar = stderrors.New("horário de execução do job muito distante")
+
+// Option ajusta o agendamento de um job em ScheduleJob e Enqueue
+type Option func(*options)
+
+// options reúne as opções de agendamento de um job
+type options struct {
+	runAt time.Time
+	delay time.Duration
+}
+
+// RunAt adia a execução do job até o horário informado. Horários no passado
+// executam o job imediatamente
+func RunAt(t time.Time) Option {
+	return func(o *options) {
+		o.runAt, o.delay = t, 0
+	}
+}
+
+// Delay adia a execução do job pelo intervalo informado
+func Delay(d time.Duration) Option {
+	return func(o *options) {
+		o.runAt, o.delay = time.Time{}, d
+	}
+}
+
+// newOptions aplica as opções de agendamento, resolvendo Delay a partir da
+// hora atual do Manager
+func (m *Manager) newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.delay > 0 {
+		o.runAt = m.clock.Now().Add(o.delay)
+	}
+	return o
+}
+
+// delayedHeap ordena os jobs agendados pelo horário de execução
+type delayedHeap []*task
+
+func (h delayedHeap) Len() int           { return len(h) }
+func (h delayedHeap) Less(i, j int) bool { return h[i].job.RunAt.Before(*h[j].job.RunAt) }
+func (h delayedHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *delayedHeap) Push(x any)        { *h = append(*h, x.(*task)) }
+
+func (h *delayedHeap) Pop() any {
+	old := *h
+	t := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return t
+}
+
+// delayQueue guarda os jobs agendados até o seu horário de execução. Os jobs
+// também ficam no Store (estado scheduled), de onde Recover os retoma após
+// uma reinicialização
+type delayQueue struct {
+	mu    sync.Mutex
+	tasks delayedHeap
+	wake  chan struct{}
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+// newDelayQueue cria uma fila de jobs agendados vazia
+func newDelayQueue() *delayQueue {
+	return &delayQueue{
+		wake: make(chan struct{}, 1),
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+}
+
+// add agenda o job e acorda o despachante, que recalcula o próximo horário
+func (q *delayQueue) add(t *task) {
+	q.mu.Lock()
+	heap.Push(&q.tasks, t)
+	q.mu.Unlock()
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// due remove e retorna os jobs cujo horário chegou, além do horário do
+// próximo job agendado (zero se não houver)
+func (q *delayQueue) due(now time.Time) ([]*task, time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var ready []*task
+	for q.tasks.Len() > 0 && !q.tasks[0].job.RunAt.After(now) {
+		ready = append(ready, heap.Pop(&q.tasks).(*task))
+	}
+	if q.tasks.Len() == 0 {
+		return ready, time.Time{}
+	}
+	return ready, *q.tasks[0].job.RunAt
+}
+
+// close para o despachante e aguarda sua saída. Os jobs ainda não vencidos
+// permanecem no Store para serem retomados por Recover
+func (q *delayQueue) close() {
+	select {
+	case <-q.stop:
+	default:
+		close(q.stop)
+	}
+	<-q.done
+}
+
+// dispatch coloca na fila de execução os jobs agendados quando chega o seu horário
+func (m *Manager) dispatch() {
+	defer close(m.delayed.done)
+
+	for {
+		ready, next := m.delayed.due(m.clock.Now())
+		for _, t := range ready {
+			m.release(t)
+		}
+
+		var timer *time.Timer
+		var fire <-chan time.Time
+		if !next.IsZero() {
+			timer = time.NewTimer(next.Sub(m.clock.Now()))
+			fire = timer.C
+		}
+
+		select {
+		case <-m.delayed.stop:
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+		case <-m.delayed.wake:
+		case <-fire:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
+
+// release move o job agendado para a fila de execução. Os limites por
+// usuário não se aplicam: o job já foi aceito no agendamento
+func (m *Manager) release(t *task) {
+	ctx := correlation.WithJobID(context.Background(), t.job.ID)
+	if t.requestID != "" {
+		ctx = correlation.WithRequestID(ctx, t.requestID)
+	}
+
+	if err := m.panics.check(t.job.Type); err != nil {
+		m.finish(ctx, &t.job, nil, err)
+		return
+	}
+
+	t.job.State = models.JobStatePending
+	if err := m.enqueue(ctx, t); err != nil {
+		logger.Warn("Falha ao liberar job agendado", correlation.Fields(ctx, map[string]interface{}{
+			"jobId": t.job.ID,
+			"type":  t.job.Type,
+			"error": err.Error(),
+		}))
+	}
+}
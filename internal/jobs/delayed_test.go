@@ -0,0 +1,109 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"callable-api/internal/clock"
+	"callable-api/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManagerDelayed(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager(NewMemoryStore(), Config{Workers: 2, QueueSize: 10, MaxDelay: time.Hour})
+	defer m.Close(ctx)
+
+	ran := make(chan time.Time, 2)
+	fn := func(ctx context.Context) (interface{}, error) {
+		ran <- time.Now()
+		return "ok", nil
+	}
+
+	// O job agendado fica no Store até o horário e só então é executado
+	start := time.Now()
+	later, err := m.ScheduleJob(ctx, "u1", "test", fn, Delay(80*time.Millisecond))
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStateScheduled, later.State)
+	require.NotNil(t, later.RunAt)
+
+	sooner, err := m.ScheduleJob(ctx, "u1", "test", fn, RunAt(start.Add(20*time.Millisecond)))
+	require.NoError(t, err)
+
+	owner := models.Viewer{UserID: "u1"}
+	stored, err := m.GetJob(ctx, owner, later.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStateScheduled, stored.State)
+
+	assert.Equal(t, models.JobStateCompleted, waitFinished(t, m, owner, sooner.ID).State)
+	assert.Equal(t, models.JobStateCompleted, waitFinished(t, m, owner, later.ID).State)
+	assert.GreaterOrEqual(t, (<-ran).Sub(start), 20*time.Millisecond)
+	assert.GreaterOrEqual(t, (<-ran).Sub(start), 80*time.Millisecond)
+
+	// Horários no passado executam imediatamente; além de MaxDelay são recusados
+	now, err := m.ScheduleJob(ctx, "u1", "test", fn, RunAt(start.Add(-time.Hour)))
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStatePending, now.State)
+	assert.Nil(t, now.RunAt)
+
+	_, err = m.ScheduleJob(ctx, "u1", "test", fn, Delay(2*time.Hour))
+	assert.ErrorIs(t, err, ErrRunAtTooFar)
+}
+
+func TestManagerRecoverDelayed(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	// Um job agendado antes da reinicialização aguarda o seu horário
+	runAt := time.Now().Add(50 * time.Millisecond).UTC()
+	scheduled := newJob("u1", "import", json.RawMessage(`{}`), time.Now())
+	scheduled.State = models.JobStateScheduled
+	scheduled.RunAt = &runAt
+	require.NoError(t, store.Save(ctx, &scheduled))
+
+	m := NewManager(store, Config{Workers: 1, QueueSize: 10})
+	defer m.Close(ctx)
+	m.Register("import", func(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+		return time.Now(), nil
+	})
+
+	resumed, err := m.Recover(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, resumed)
+
+	job, err := m.GetJob(ctx, models.Viewer{UserID: "u1"}, scheduled.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStateScheduled, job.State)
+
+	done := waitFinished(t, m, models.Viewer{UserID: "u1"}, scheduled.ID)
+	assert.Equal(t, models.JobStateCompleted, done.State)
+	assert.False(t, done.StartedAt.Before(runAt))
+}
+
+func TestManagerClock(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	clk := clock.NewFake(now)
+	m := NewManager(NewMemoryStore(), Config{Workers: 1, QueueSize: 10, MaxDelay: time.Hour, Clock: clk})
+	defer m.Close(ctx)
+
+	fn := func(ctx context.Context) (interface{}, error) { return "ok", nil }
+
+	// Criação e horário de execução seguem o relógio do Manager
+	job, err := m.ScheduleJob(ctx, "u1", "test", fn, Delay(30*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, now, job.CreatedAt)
+	require.NotNil(t, job.RunAt)
+	assert.Equal(t, now.Add(30*time.Minute), *job.RunAt)
+
+	// O limite de agendamento também
+	_, err = m.ScheduleJob(ctx, "u1", "test", fn, RunAt(now.Add(2*time.Hour)))
+	assert.ErrorIs(t, err, ErrRunAtTooFar)
+	clk.Advance(90 * time.Minute)
+	_, err = m.ScheduleJob(ctx, "u1", "test", fn, RunAt(now.Add(2*time.Hour)))
+	assert.NoError(t, err)
+}
@@ -0,0 +1,130 @@
+package jobs
+
+import (
+	"context"
+	"unicode/utf8"
+
+	"callable-api/internal/correlation"
+	"callable-api/internal/models"
+	"callable-api/internal/redact"
+	"callable-api/pkg/logger"
+)
+
+// Níveis das linhas do log dos jobs
+const (
+	LogDebug = "debug"
+	LogInfo  = "info"
+	LogWarn  = "warn"
+	LogError = "error"
+)
+
+// LogLimits limita o log de execução guardado para cada job
+type LogLimits struct {
+	// MaxEntries é o número de linhas mantidas por job; as mais antigas são
+	// descartadas (0 desativa o log)
+	MaxEntries int
+
+	// MaxMessageBytes limita o tamanho da mensagem e de cada campo textual
+	MaxMessageBytes int
+}
+
+// DefaultLogLimits retorna os limites padrão: 100 linhas de até 1 KiB
+func DefaultLogLimits() LogLimits {
+	return LogLimits{MaxEntries: 100, MaxMessageBytes: 1 << 10}
+}
+
+type jobLogKey struct{}
+
+// jobLog grava as linhas do log do job em execução
+type jobLog struct {
+	m     *Manager
+	jobID string
+}
+
+// withJobLog associa ao contexto do job o acesso ao seu log
+func withJobLog(ctx context.Context, m *Manager, jobID string) context.Context {
+	return context.WithValue(ctx, jobLogKey{}, &jobLog{m: m, jobID: jobID})
+}
+
+// Log registra uma linha no log da aplicação e, durante a execução de um job,
+// também no log do job, que o usuário consulta em GET /api/v1/jobs/:id/logs.
+// Os campos ficam visíveis ao dono do job: não inclua dados sensíveis
+func Log(ctx context.Context, level, message string, fields map[string]interface{}) {
+	writeLog(level, message, correlation.Fields(ctx, copyFields(fields)))
+
+	if log, ok := ctx.Value(jobLogKey{}).(*jobLog); ok {
+		log.m.appendLog(ctx, log.jobID, level, message, fields)
+	}
+}
+
+// appendLog grava a linha no log do job, respeitando os limites. Falhas ao
+// gravar não interrompem o job
+func (m *Manager) appendLog(ctx context.Context, jobID, level, message string, fields map[string]interface{}) {
+	if m.logLimits.MaxEntries <= 0 {
+		return
+	}
+
+	entry := models.JobLogEntry{
+		Time:    m.clock.Now().UTC(),
+		Level:   level,
+		Message: truncate(message, m.logLimits.MaxMessageBytes),
+	}
+	if len(fields) > 0 {
+		entry.Fields = redact.Fields(redact.Map(fields))
+		for key, value := range entry.Fields {
+			if text, ok := value.(string); ok {
+				entry.Fields[key] = truncate(text, m.logLimits.MaxMessageBytes)
+			}
+		}
+	}
+
+	if err := m.store.AppendLog(context.WithoutCancel(ctx), jobID, entry, m.logLimits.MaxEntries); err != nil {
+		logger.Warn("Falha ao gravar o log do job", correlation.Fields(ctx, map[string]interface{}{
+			"jobId": jobID,
+			"error": err.Error(),
+		}))
+	}
+}
+
+// Logs retorna o log de execução do job, se visível ao usuário
+func (m *Manager) Logs(ctx context.Context, viewer models.Viewer, id string) (*models.JobLogs, error) {
+	if _, err := m.GetJob(ctx, viewer, id); err != nil {
+		return nil, err
+	}
+	return m.store.Logs(ctx, id)
+}
+
+// writeLog escreve a linha no log da aplicação com o nível informado
+func writeLog(level, message string, fields map[string]interface{}) {
+	switch level {
+	case LogDebug:
+		logger.Debug(message, fields)
+	case LogWarn:
+		logger.Warn(message, fields)
+	case LogError:
+		logger.Error(message, fields)
+	default:
+		logger.Info(message, fields)
+	}
+}
+
+// copyFields copia os campos, para que correlation.Fields não altere os do chamador
+func copyFields(fields map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(fields)+2)
+	for key, value := range fields {
+		copied[key] = value
+	}
+	return copied
+}
+
+// truncate limita o texto a max bytes, sem cortar caracteres UTF-8 ao meio
+func truncate(text string, max int) string {
+	if max <= 0 || len(text) <= max {
+		return text
+	}
+	cut := max
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	return text[:cut] + "…"
+}
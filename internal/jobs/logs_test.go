@@ -0,0 +1,86 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"callable-api/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManagerLogs(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager(NewMemoryStore(), Config{Workers: 1, QueueSize: 10, Logs: DefaultLogLimits()})
+	defer m.Close(ctx)
+	owner := models.Viewer{UserID: "u1"}
+
+	job, err := m.ScheduleJob(ctx, "u1", "test", func(ctx context.Context) (interface{}, error) {
+		Log(ctx, LogInfo, "Importando", map[string]interface{}{"linha": 3, "password": "segredo"})
+		return nil, errors.New("linha 3 inválida")
+	})
+	require.NoError(t, err)
+	waitFinished(t, m, owner, job.ID)
+
+	logs, err := m.Logs(ctx, owner, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, job.ID, logs.JobID)
+	require.Len(t, logs.Entries, 3)
+	assert.Equal(t, "Job iniciado", logs.Entries[0].Message)
+	assert.Equal(t, "Importando", logs.Entries[1].Message)
+	assert.Equal(t, 3, logs.Entries[1].Fields["linha"])
+	assert.NotEqual(t, "segredo", logs.Entries[1].Fields["password"])
+	assert.Equal(t, LogWarn, logs.Entries[2].Level)
+	assert.Equal(t, "linha 3 inválida", logs.Entries[2].Fields["error"])
+
+	// O log segue a visibilidade do job
+	_, err = m.Logs(ctx, models.Viewer{UserID: "u2"}, job.ID)
+	assert.Error(t, err)
+
+	// Fora de um job, Log apenas escreve no log da aplicação
+	Log(ctx, LogInfo, "Fora de job", nil)
+}
+
+func TestManagerLogs_Limits(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager(NewMemoryStore(), Config{Workers: 1, QueueSize: 10, Logs: LogLimits{MaxEntries: 5, MaxMessageBytes: 8}})
+	defer m.Close(ctx)
+	owner := models.Viewer{UserID: "u1"}
+
+	job, err := m.ScheduleJob(ctx, "u1", "test", func(ctx context.Context) (interface{}, error) {
+		for i := 0; i < 10; i++ {
+			Log(ctx, LogDebug, fmt.Sprintf("linha %d", i), nil)
+		}
+		Log(ctx, LogInfo, strings.Repeat("ação", 5), map[string]interface{}{"detalhe": strings.Repeat("x", 20)})
+		return "ok", nil
+	})
+	require.NoError(t, err)
+	waitFinished(t, m, owner, job.ID)
+
+	// Apenas as linhas mais recentes são mantidas, com as mensagens truncadas
+	logs, err := m.Logs(ctx, owner, job.ID)
+	require.NoError(t, err)
+	require.Len(t, logs.Entries, 5)
+	assert.Equal(t, 8, logs.Dropped)
+	assert.Equal(t, "linha 7", logs.Entries[0].Message)
+	assert.Equal(t, "açãoa…", logs.Entries[3].Message)
+	assert.Equal(t, "xxxxxxxx…", logs.Entries[3].Fields["detalhe"])
+	assert.Equal(t, "Job conc…", logs.Entries[4].Message)
+
+	// Sem limite de linhas, o log fica desativado
+	disabled := NewManager(NewMemoryStore(), Config{Workers: 1, QueueSize: 10})
+	defer disabled.Close(ctx)
+	job, err = disabled.ScheduleJob(ctx, "u1", "test", func(ctx context.Context) (interface{}, error) {
+		Log(ctx, LogInfo, "ignorada", nil)
+		return nil, nil
+	})
+	require.NoError(t, err)
+	waitFinished(t, disabled, owner, job.ID)
+	logs, err = disabled.Logs(ctx, owner, job.ID)
+	require.NoError(t, err)
+	assert.Empty(t, logs.Entries)
+}
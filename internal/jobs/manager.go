@@ -0,0 +1,647 @@
+// Package jobs executa tarefas em segundo plano com um pool de workers,
+// registrando o estado de cada job para que os clientes possam acompanhá-lo
+// (GET /api/v1/jobs/{id}).
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"callable-api/internal/clock"
+	"callable-api/internal/correlation"
+	"callable-api/internal/errcodes"
+	"callable-api/internal/events"
+	"callable-api/internal/ids"
+	"callable-api/internal/models"
+	"callable-api/internal/notifications"
+	"callable-api/pkg/errors"
+	"callable-api/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+// Erros retornados por ScheduleJob e Enqueue
+var (
+	ErrQueueFull      = stderrors.New("fila de jobs cheia")
+	ErrUserQueueFull  = stderrors.New("limite de jobs aguardando do usuário atingido")
+	ErrClosed         = stderrors.New("execução de jobs finalizada")
+	ErrUnknownJobType = stderrors.New("tipo de job não registrado")
+	ErrInterrupted    = stderrors.New("job interrompido pela reinicialização do servidor")
+	ErrTimeout        = stderrors.New("job excedeu o prazo de execução")
+)
+
+// Func é a tarefa executada por um job. O resultado é exposto em JobStatus.Result
+type Func func(ctx context.Context) (interface{}, error)
+
+// Handler executa os jobs de um tipo registrado a partir da sua entrada
+// persistida. Jobs longos devem gravar checkpoints (SaveCheckpoint) e
+// consultá-los ao iniciar (LoadCheckpoint), pois podem ser retomados após uma
+// reinicialização
+type Handler func(ctx context.Context, input json.RawMessage) (interface{}, error)
+
+// Notifier publica notificações em segundo plano (ver notifications.Service)
+type Notifier interface {
+	Publish(event notifications.Event)
+}
+
+// AlertRecipients retorna os usuários avisados quando um tipo de job é
+// desativado (ver notifications.AdminRecipients)
+type AlertRecipients func(ctx context.Context) ([]string, error)
+
+// Config define a capacidade de execução dos jobs
+type Config struct {
+	Workers   int           // jobs executados em paralelo
+	QueueSize int           // jobs aguardando execução
+	Timeout   time.Duration // prazo de cada job
+
+	// Limites por usuário (0 = sem limite). Os jobs além de MaxRunningPerUser
+	// aguardam na fila; além de MaxQueuedPerUser são recusados
+	MaxRunningPerUser int
+	MaxQueuedPerUser  int
+
+	// Panics define quando um tipo de job é desativado por panics repetidos
+	Panics PanicPolicy
+
+	// MaxDelay limita o agendamento de jobs para o futuro (0 = sem limite)
+	MaxDelay time.Duration
+
+	// ArtifactThreshold é o tamanho, em bytes, a partir do qual o resultado
+	// serializado é gravado como artefato (ver WithArtifacts; 0 desativa)
+	ArtifactThreshold int
+
+	// ArtifactCompression é o tamanho, em bytes, a partir do qual os
+	// artefatos de texto (CSV, JSON) são gravados comprimidos com gzip (0
+	// desativa). O download descomprime para quem não aceita gzip
+	ArtifactCompression int
+
+	// Logs limita o log de execução guardado para cada job (ver Log)
+	Logs LogLimits
+
+	// Clock fornece a hora de criação, execução e agendamento dos jobs (nil
+	// usa o relógio do sistema)
+	Clock clock.Clock
+
+	// IDs gera os IDs dos jobs (nil usa UUID v4)
+	IDs ids.Generator
+}
+
+// DefaultConfig retorna a configuração padrão: 4 workers, 100 jobs na fila,
+// 5 minutos por job, por usuário 2 jobs em execução e 20 aguardando,
+// agendamentos de até 30 dias, resultados de até 64 KiB no status e as
+// últimas 100 linhas do log de cada job
+func DefaultConfig() Config {
+	return Config{
+		Workers:           4,
+		QueueSize:         100,
+		Timeout:           5 * time.Minute,
+		MaxRunningPerUser: 2,
+		MaxQueuedPerUser:  20,
+		Panics:            DefaultPanicPolicy(),
+		MaxDelay:          30 * 24 * time.Hour,
+		ArtifactThreshold: 64 << 10,
+		Logs:              DefaultLogLimits(),
+	}
+}
+
+// task é um job enfileirado
+type task struct {
+	job       models.JobStatus
+	fn        Func
+	requestID string
+}
+
+// Manager enfileira e executa os jobs, mantendo seu estado no Store
+type Manager struct {
+	store    Store
+	timeout  time.Duration
+	notifier Notifier
+	events   events.Publisher
+	alerts   AlertRecipients
+	panics   *panicTracker
+	maxDelay time.Duration
+	clock    clock.Clock
+	ids      ids.Generator
+
+	artifacts           ArtifactStore
+	artifactThreshold   int
+	artifactCompression int
+
+	logLimits LogLimits
+
+	handlersMu sync.RWMutex
+	handlers   map[string]Handler
+	workflows  map[string][]string
+
+	// workflowMu serializa as atualizações dos workflows a partir das etapas
+	workflowMu sync.Mutex
+
+	sched   *scheduler
+	delayed *delayQueue
+	wg      sync.WaitGroup
+}
+
+// NewManager cria um Manager e inicia seus workers e o despachante dos jobs agendados
+func NewManager(store Store, cfg Config) *Manager {
+	if cfg.Workers < 1 {
+		cfg.Workers = 1
+	}
+	if cfg.QueueSize < 1 {
+		cfg.QueueSize = 1
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = clock.System()
+	}
+	if cfg.IDs == nil {
+		cfg.IDs = ids.UUID()
+	}
+
+	m := &Manager{
+		store:     store,
+		timeout:   cfg.Timeout,
+		handlers:  make(map[string]Handler),
+		workflows: make(map[string][]string),
+		panics:    newPanicTracker(cfg.Panics),
+		maxDelay:  cfg.MaxDelay,
+		clock:     cfg.Clock,
+		ids:       cfg.IDs,
+		sched:     newScheduler(cfg.QueueSize, cfg.MaxRunningPerUser, cfg.MaxQueuedPerUser),
+		delayed:   newDelayQueue(),
+
+		artifactThreshold:   cfg.ArtifactThreshold,
+		artifactCompression: cfg.ArtifactCompression,
+		logLimits:           cfg.Logs,
+	}
+	m.panics.now = cfg.Clock.Now
+	go m.dispatch()
+	for i := 0; i < cfg.Workers; i++ {
+		m.wg.Add(1)
+		go m.worker()
+	}
+	return m
+}
+
+// WithNotifier envia os alertas de tipos de job desativados (ver WithAlerts)
+func (m *Manager) WithNotifier(notifier Notifier) *Manager {
+	m.notifier = notifier
+	return m
+}
+
+// WithEvents publica os eventos job.completed e job.failed ao fim dos jobs
+func (m *Manager) WithEvents(publisher events.Publisher) *Manager {
+	m.events = publisher
+	return m
+}
+
+// WithAlerts avisa os usuários informados (em geral, os administradores)
+// quando um tipo de job é desativado por panics repetidos
+func (m *Manager) WithAlerts(recipients AlertRecipients) *Manager {
+	m.alerts = recipients
+	return m
+}
+
+// PanicStats implementa admin.JobPanicReporter
+func (m *Manager) PanicStats() []models.JobPanicStats {
+	return m.panics.snapshot()
+}
+
+// Register registra o handler de um tipo de job, permitindo enfileirá-lo com
+// Enqueue e retomá-lo com Recover
+func (m *Manager) Register(jobType string, handler Handler) *Manager {
+	m.handlersMu.Lock()
+	defer m.handlersMu.Unlock()
+	m.handlers[jobType] = handler
+	return m
+}
+
+// handler retorna o handler registrado para o tipo de job
+func (m *Manager) handler(jobType string) (Handler, bool) {
+	m.handlersMu.RLock()
+	defer m.handlersMu.RUnlock()
+	handler, ok := m.handlers[jobType]
+	return handler, ok
+}
+
+// ScheduleJob enfileira a tarefa e retorna o job no estado pending (ou
+// scheduled, com RunAt ou Delay). O job é associado ao usuário (vazio para
+// clientes anônimos) e ao ID da requisição. A tarefa só existe na memória: se
+// o servidor reiniciar antes de ela terminar, o job falha com ErrInterrupted
+// (use Enqueue para jobs retomáveis)
+func (m *Manager) ScheduleJob(ctx context.Context, userID, jobType string, fn Func, opts ...Option) (*models.JobStatus, error) {
+	return m.schedule(ctx, m.newJob(userID, jobType, nil), fn, m.newOptions(opts))
+}
+
+// Enqueue enfileira um job de tipo registrado, persistindo sua entrada para
+// que ele possa ser retomado após uma reinicialização
+func (m *Manager) Enqueue(ctx context.Context, userID, jobType string, input interface{}, opts ...Option) (*models.JobStatus, error) {
+	handler, ok := m.handler(jobType)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownJobType, jobType)
+	}
+
+	data, err := json.Marshal(input)
+	if err != nil {
+		return nil, errors.NewInternalServerError("Falha ao serializar entrada do job", err)
+	}
+
+	return m.schedule(ctx, m.newJob(userID, jobType, data), bind(handler, data), m.newOptions(opts))
+}
+
+// Recover retoma os jobs que não terminaram antes da reinicialização: os de
+// tipos registrados voltam para a fila, a partir do último checkpoint; os
+// demais são marcados como falhos. Jobs agendados para o futuro aguardam o
+// seu horário. Deve ser chamado após os Register
+func (m *Manager) Recover(ctx context.Context) (int, error) {
+	unfinished, err := m.store.ListUnfinished(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	resumed := 0
+	var workflows []*models.JobStatus
+	for i := range unfinished {
+		job := unfinished[i]
+		if job.Steps != nil {
+			workflows = append(workflows, &unfinished[i])
+			continue
+		}
+
+		handler, ok := m.handler(job.Type)
+		if !ok || job.Input == nil {
+			m.finish(ctx, &job, nil, ErrInterrupted)
+			continue
+		}
+
+		t := &task{job: job, fn: bind(handler, job.Input)}
+		if job.State == models.JobStateScheduled && job.RunAt != nil {
+			m.delayed.add(t)
+			resumed++
+			continue
+		}
+
+		t.job.State = models.JobStatePending
+		if err := m.enqueue(ctx, t); err != nil {
+			return resumed, err
+		}
+		resumed++
+	}
+
+	for _, workflow := range workflows {
+		m.resumeWorkflow(ctx, workflow)
+	}
+	return resumed, nil
+}
+
+// newJob cria o estado inicial de um job com o ID e a hora do Manager
+func (m *Manager) newJob(userID, jobType string, input json.RawMessage) models.JobStatus {
+	job := newJob(userID, jobType, input, m.clock.Now())
+	job.ID = m.ids.NewID()
+	return job
+}
+
+// newJob cria o estado inicial de um job, criado em now
+func newJob(userID, jobType string, input json.RawMessage, now time.Time) models.JobStatus {
+	return models.JobStatus{
+		ID:        uuid.New().String(),
+		Type:      jobType,
+		State:     models.JobStatePending,
+		UserID:    userID,
+		CreatedAt: now.UTC(),
+		Input:     input,
+	}
+}
+
+// bind associa o handler à entrada do job
+func bind(handler Handler, input json.RawMessage) Func {
+	return func(ctx context.Context) (interface{}, error) {
+		return handler(ctx, input)
+	}
+}
+
+// enqueue grava um job retomado e o coloca na fila, sem aplicar os limites
+func (m *Manager) enqueue(ctx context.Context, t *task) error {
+	if err := m.persist(ctx, &t.job); err != nil {
+		return errors.NewInternalServerError("Falha ao registrar job", err)
+	}
+	return m.sched.push(t, false)
+}
+
+// schedule grava o job e o coloca na fila sem bloquear, recusando-o se a
+// fila ou a cota de jobs aguardando do usuário estiverem cheias. Jobs com
+// horário futuro aguardam no Store até o despachante liberá-los
+func (m *Manager) schedule(ctx context.Context, job models.JobStatus, fn Func, opts options) (*models.JobStatus, error) {
+	if m.sched.isClosed() {
+		return nil, ErrClosed
+	}
+	if err := m.panics.check(job.Type); err != nil {
+		return nil, err
+	}
+	if err := m.checkRunAt(opts); err != nil {
+		return nil, err
+	}
+
+	t := &task{
+		job:       job,
+		fn:        fn,
+		requestID: correlation.RequestID(ctx),
+	}
+	if opts.runAt.After(m.clock.Now()) {
+		runAt := opts.runAt.UTC()
+		t.job.State = models.JobStateScheduled
+		t.job.RunAt = &runAt
+	}
+	if err := m.persist(ctx, &t.job); err != nil {
+		return nil, errors.NewInternalServerError("Falha ao registrar job", err)
+	}
+
+	if t.job.State == models.JobStateScheduled {
+		scheduled := t.job
+		m.delayed.add(t)
+		return &scheduled, nil
+	}
+
+	// A cópia retornada é feita antes de o job chegar aos workers
+	scheduled := t.job
+	if err := m.sched.push(t, true); err != nil {
+		// O job não será executado: registrá-lo como falho para quem já tiver o ID
+		m.finish(context.WithoutCancel(ctx), &t.job, nil, err)
+		return nil, err
+	}
+	return &scheduled, nil
+}
+
+// checkRunAt recusa agendamentos além de MaxDelay
+func (m *Manager) checkRunAt(opts options) error {
+	if delay := opts.runAt.Sub(m.clock.Now()); m.maxDelay > 0 && delay > m.maxDelay {
+		return fmt.Errorf("%w: máximo de %s", ErrRunAtTooFar, m.maxDelay)
+	}
+	return nil
+}
+
+// GetJob retorna o job se o usuário puder consultá-lo; jobs de outros
+// usuários são tratados como inexistentes
+func (m *Manager) GetJob(ctx context.Context, viewer models.Viewer, id string) (*models.JobStatus, error) {
+	job, err := m.store.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !job.VisibleTo(viewer) {
+		return nil, errors.NewNotFoundError("Job não encontrado", nil)
+	}
+	return job, nil
+}
+
+// CountByState implementa admin.JobCounter
+func (m *Manager) CountByState(ctx context.Context) (map[string]int, error) {
+	return m.store.CountByState(ctx)
+}
+
+// Stats retorna o resumo dos jobs para a administração
+func (m *Manager) Stats(ctx context.Context) (*models.JobStats, error) {
+	return m.store.Stats(ctx)
+}
+
+// Cleanup remove os jobs terminados há mais de olderThan, com seus
+// checkpoints. Os artefatos gerados continuam no armazenamento de artefatos
+func (m *Manager) Cleanup(ctx context.Context, olderThan time.Duration) (*models.JobCleanup, error) {
+	before := m.clock.Now().UTC().Add(-olderThan)
+	deleted, err := m.store.DeleteFinished(ctx, before)
+	if err != nil {
+		return nil, err
+	}
+	return &models.JobCleanup{Deleted: deleted, Before: before}, nil
+}
+
+// Close para de aceitar jobs e aguarda a execução dos que já estão na fila
+// até o prazo do contexto. Os jobs agendados para depois continuam no Store
+func (m *Manager) Close(ctx context.Context) error {
+	m.delayed.close()
+	m.sched.close()
+
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// worker consome a fila e executa os jobs
+func (m *Manager) worker() {
+	defer m.wg.Done()
+
+	for {
+		t, ok := m.sched.pop()
+		if !ok {
+			return
+		}
+		m.run(t)
+		m.sched.done(t.job.UserID)
+	}
+}
+
+// run executa um job, registrando o início e o resultado
+func (m *Manager) run(t *task) {
+	ctx := correlation.WithJobID(context.Background(), t.job.ID)
+	if t.requestID != "" {
+		ctx = correlation.WithRequestID(ctx, t.requestID)
+	}
+	if m.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.timeout)
+		defer cancel()
+	}
+
+	job := &t.job
+	ctx = withJobLog(ctx, m, job.ID)
+
+	// Jobs enfileirados antes da desativação do tipo não são executados
+	if err := m.panics.check(job.Type); err != nil {
+		m.finish(ctx, job, nil, err)
+		return
+	}
+
+	started := m.clock.Now().UTC()
+	job.State = models.JobStateRunning
+	job.StartedAt = &started
+	job.Attempts++
+	m.save(ctx, job)
+	m.syncStep(ctx, job, nil)
+	m.appendLog(ctx, job.ID, LogInfo, "Job iniciado", map[string]interface{}{"attempt": job.Attempts})
+
+	result, err := execute(withCheckpointer(ctx, m.store, job.ID), t.fn)
+	var panicErr *PanicError
+	if err != nil && !stderrors.As(err, &panicErr) && stderrors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("%w (%s): %v", ErrTimeout, m.timeout, err)
+	}
+	m.finish(ctx, job, result, err)
+}
+
+// execute chama a tarefa, convertendo panics em PanicError
+func execute(ctx context.Context, fn Func) (result interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			result, err = nil, &PanicError{Class: classifyPanic(r), Value: r, Stack: debug.Stack()}
+		}
+	}()
+	return fn(ctx)
+}
+
+// finish registra o resultado do job e avisa os interessados
+func (m *Manager) finish(ctx context.Context, job *models.JobStatus, result interface{}, err error) {
+	finished := m.clock.Now().UTC()
+	job.FinishedAt = &finished
+
+	// Resultados grandes ficam no armazenamento de artefatos, não no status
+	stored := result
+	if err == nil {
+		stored, err = m.storeResult(ctx, job.ID, result)
+	}
+
+	fields := correlation.Fields(ctx, map[string]interface{}{
+		"jobId": job.ID,
+		"type":  job.Type,
+	})
+
+	if err != nil {
+		job.State = models.JobStateFailed
+		job.Error = err.Error()
+		job.ErrorCode = errcodes.FromError(err)
+		fields["error"] = err.Error()
+
+		var stepErr *StepError
+		if stderrors.As(err, &stepErr) && stepErr.Code != "" {
+			job.ErrorCode = stepErr.Code
+		}
+		if stderrors.Is(err, ErrTimeout) {
+			job.State = models.JobStateTimedOut
+			job.ErrorCode = errcodes.JobTimeout
+		}
+	}
+
+	var panicErr *PanicError
+	switch {
+	case stderrors.As(err, &panicErr):
+		fields["panicClass"] = panicErr.Class
+		// A pilha fica apenas no log da aplicação
+		m.appendLog(ctx, job.ID, LogError, "Job entrou em panic", map[string]interface{}{
+			"error":      job.Error,
+			"panicClass": panicErr.Class,
+		})
+		fields["stack"] = string(panicErr.Stack)
+		logger.Error("Job entrou em panic", fields)
+
+		if m.panics.record(job.Type, panicErr) {
+			m.alertDisabled(ctx, job.Type, panicErr)
+		}
+	case err != nil:
+		m.appendLog(ctx, job.ID, LogWarn, "Job falhou", map[string]interface{}{
+			"error":     job.Error,
+			"errorCode": job.ErrorCode,
+		})
+		logger.Warn("Job falhou", fields)
+	default:
+		job.State = models.JobStateCompleted
+		job.Result = stored
+		m.appendLog(ctx, job.ID, LogInfo, "Job concluído", nil)
+		logger.Info("Job concluído", fields)
+	}
+
+	// O estado final é gravado mesmo que o prazo do job tenha expirado. Os
+	// checkpoints só servem para retomar jobs inacabados
+	m.save(context.WithoutCancel(ctx), job)
+	if err := m.store.DeleteCheckpoints(context.WithoutCancel(ctx), job.ID); err != nil {
+		logger.Warn("Falha ao remover checkpoints do job", correlation.Fields(ctx, map[string]interface{}{
+			"jobId": job.ID,
+			"error": err.Error(),
+		}))
+	}
+
+	if err != nil && m.events != nil {
+		m.events.Publish(ctx, events.JobFailed{
+			JobID:    job.ID,
+			Kind:     job.Type,
+			UserID:   job.UserID,
+			Error:    job.Error,
+			Attempts: job.Attempts,
+		})
+	}
+	// As etapas de um workflow não publicam: o aviso vem ao fim do workflow
+	if err == nil && m.events != nil && job.ParentID == "" {
+		m.events.Publish(ctx, events.JobCompleted{
+			JobID:  job.ID,
+			Kind:   job.Type,
+			UserID: job.UserID,
+		})
+	}
+
+	m.syncStep(ctx, job, result)
+}
+
+// alertDisabled avisa que o tipo de job foi desativado por panics repetidos
+func (m *Manager) alertDisabled(ctx context.Context, jobType string, panicErr *PanicError) {
+	disabled := m.panics.check(jobType)
+	logger.Error("Tipo de job desativado por panics repetidos", correlation.Fields(ctx, map[string]interface{}{
+		"type":  jobType,
+		"error": fmt.Sprint(disabled),
+	}))
+
+	if m.notifier == nil || m.alerts == nil {
+		return
+	}
+	recipients, err := m.alerts(context.WithoutCancel(ctx))
+	if err != nil {
+		logger.Error("Falha ao buscar destinatários do alerta de jobs", correlation.Fields(ctx, map[string]interface{}{
+			"type":  jobType,
+			"error": err.Error(),
+		}))
+		return
+	}
+
+	data := map[string]interface{}{
+		"type":        jobType,
+		"panic_class": panicErr.Class,
+		"last_panic":  fmt.Sprint(panicErr.Value),
+	}
+	var disabledErr *DisabledError
+	if stderrors.As(disabled, &disabledErr) {
+		data["disabled_until"] = disabledErr.Until
+	}
+	for _, userID := range recipients {
+		m.notifier.Publish(notifications.Event{
+			Type:   models.NotificationEventJobTypeDisabled,
+			UserID: userID,
+			Title:  "Tipo de job desativado",
+			Body:   fmt.Sprintf("Os jobs %s foram desativados temporariamente após panics repetidos (%s)", jobType, panicErr.Class),
+			Data:   data,
+		})
+	}
+}
+
+// persist grava o estado do job, marcando o horário da alteração (usado no
+// Last-Modified das consultas ao job)
+func (m *Manager) persist(ctx context.Context, job *models.JobStatus) error {
+	job.UpdatedAt = m.clock.Now().UTC()
+	return m.store.Save(ctx, job)
+}
+
+// save grava o estado do job, registrando as falhas sem interromper a execução
+func (m *Manager) save(ctx context.Context, job *models.JobStatus) {
+	if err := m.persist(ctx, job); err != nil {
+		logger.Error("Falha ao gravar estado do job", correlation.Fields(ctx, map[string]interface{}{
+			"jobId": job.ID,
+			"state": job.State,
+			"error": err.Error(),
+		}))
+	}
+}
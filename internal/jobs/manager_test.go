@@ -0,0 +1,235 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"callable-api/internal/clock"
+	"callable-api/internal/errcodes"
+	"callable-api/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// waitFinished aguarda o job terminar
+func waitFinished(t *testing.T, m *Manager, viewer models.Viewer, id string) *models.JobStatus {
+	var job *models.JobStatus
+	require.Eventually(t, func() bool {
+		var err error
+		job, err = m.GetJob(context.Background(), viewer, id)
+		return err == nil && job.Finished()
+	}, time.Second, 5*time.Millisecond)
+	return job
+}
+
+func TestManager(t *testing.T) {
+	m := NewManager(NewMemoryStore(), Config{Workers: 2, QueueSize: 10, Timeout: time.Second})
+	defer m.Close(context.Background())
+	owner := models.Viewer{UserID: "u1"}
+
+	job, err := m.ScheduleJob(context.Background(), "u1", "test", func(ctx context.Context) (interface{}, error) {
+		return "ok", nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStatePending, job.State)
+
+	done := waitFinished(t, m, owner, job.ID)
+	assert.Equal(t, models.JobStateCompleted, done.State)
+	assert.Equal(t, "ok", done.Result)
+	assert.NotNil(t, done.StartedAt)
+
+	// Erros e panics viram jobs falhos
+	failed, err := m.ScheduleJob(context.Background(), "u1", "test", func(ctx context.Context) (interface{}, error) {
+		return nil, errors.New("falhou")
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "falhou", waitFinished(t, m, owner, failed.ID).Error)
+
+	panicked, err := m.ScheduleJob(context.Background(), "u1", "test", func(ctx context.Context) (interface{}, error) {
+		panic("boom")
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStateFailed, waitFinished(t, m, owner, panicked.ID).State)
+
+	// Outros usuários não enxergam o job
+	_, err = m.GetJob(context.Background(), models.Viewer{UserID: "u2"}, job.ID)
+	assert.Error(t, err)
+	_, err = m.GetJob(context.Background(), models.Viewer{UserID: "u2", Role: "admin"}, job.ID)
+	assert.NoError(t, err)
+
+	counts, err := m.CountByState(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{models.JobStateCompleted: 1, models.JobStateFailed: 2}, counts)
+}
+
+func TestManagerTimeout(t *testing.T) {
+	m := NewManager(NewMemoryStore(), Config{Workers: 1, QueueSize: 10, Timeout: 20 * time.Millisecond})
+	defer m.Close(context.Background())
+	owner := models.Viewer{UserID: "u1"}
+
+	// O job que excede o prazo termina como timed_out, não como falha comum
+	job, err := m.ScheduleJob(context.Background(), "u1", "test", func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	require.NoError(t, err)
+	done := waitFinished(t, m, owner, job.ID)
+	assert.Equal(t, models.JobStateTimedOut, done.State)
+	assert.Equal(t, errcodes.JobTimeout, done.ErrorCode)
+	assert.Contains(t, done.Error, ErrTimeout.Error())
+	assert.True(t, done.Unsuccessful())
+
+	// Erros anteriores ao prazo continuam sendo falhas
+	failed, err := m.ScheduleJob(context.Background(), "u1", "test", func(ctx context.Context) (interface{}, error) {
+		return nil, errors.New("falhou")
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStateFailed, waitFinished(t, m, owner, failed.ID).State)
+}
+
+func TestManagerQueueFull(t *testing.T) {
+	m := NewManager(NewMemoryStore(), Config{Workers: 1, QueueSize: 1})
+	release := make(chan struct{})
+	blocking := func(ctx context.Context) (interface{}, error) {
+		<-release
+		return nil, nil
+	}
+
+	// Um job em execução e outro na fila esgotam a capacidade
+	first, err := m.ScheduleJob(context.Background(), "u1", "test", blocking)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		job, _ := m.GetJob(context.Background(), models.Viewer{UserID: "u1"}, first.ID)
+		return job.State == models.JobStateRunning
+	}, time.Second, 5*time.Millisecond)
+	_, err = m.ScheduleJob(context.Background(), "u1", "test", blocking)
+	require.NoError(t, err)
+
+	_, err = m.ScheduleJob(context.Background(), "u1", "test", blocking)
+	assert.ErrorIs(t, err, ErrQueueFull)
+
+	// Close aguarda os jobs pendentes; depois dele nenhum job é aceito
+	close(release)
+	require.NoError(t, m.Close(context.Background()))
+	_, err = m.ScheduleJob(context.Background(), "u1", "test", blocking)
+	assert.ErrorIs(t, err, ErrClosed)
+}
+
+func TestManagerRecover(t *testing.T) {
+	store := NewMemoryStore()
+
+	// Estado deixado por uma execução interrompida: um job retomável com
+	// checkpoint e um job sem tipo registrado
+	ctx := context.Background()
+	interrupted := newJob("u1", "import", json.RawMessage(`{"rows":5}`), time.Now())
+	interrupted.State = models.JobStateRunning
+	require.NoError(t, store.Save(ctx, &interrupted))
+	require.NoError(t, store.SaveCheckpoint(ctx, interrupted.ID, "row", json.RawMessage(`3`)))
+	adhoc := newJob("u1", "adhoc", nil, time.Now())
+	require.NoError(t, store.Save(ctx, &adhoc))
+
+	type importInput struct {
+		Rows int `json:"rows"`
+	}
+	var processed []int
+	m := NewManager(store, Config{Workers: 1, QueueSize: 10})
+	defer m.Close(ctx)
+	m.Register("import", func(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+		var input importInput
+		if err := json.Unmarshal(raw, &input); err != nil {
+			return nil, err
+		}
+		start := 0
+		if _, err := LoadCheckpoint(ctx, "row", &start); err != nil {
+			return nil, err
+		}
+		for row := start; row < input.Rows; row++ {
+			processed = append(processed, row)
+			if err := SaveCheckpoint(ctx, "row", row+1); err != nil {
+				return nil, err
+			}
+		}
+		return len(processed), nil
+	})
+
+	resumed, err := m.Recover(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, resumed)
+
+	// O job retomado continua do checkpoint, e os checkpoints são removidos ao terminar
+	owner := models.Viewer{UserID: "u1"}
+	done := waitFinished(t, m, owner, interrupted.ID)
+	assert.Equal(t, models.JobStateCompleted, done.State)
+	assert.Equal(t, []int{3, 4}, processed)
+	data, err := store.Checkpoint(ctx, interrupted.ID, "row")
+	require.NoError(t, err)
+	assert.Nil(t, data)
+
+	failed := waitFinished(t, m, owner, adhoc.ID)
+	assert.Equal(t, ErrInterrupted.Error(), failed.Error)
+
+	// Jobs registrados também podem ser enfileirados diretamente
+	job, err := m.Enqueue(ctx, "u1", "import", importInput{Rows: 1})
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStateCompleted, waitFinished(t, m, owner, job.ID).State)
+
+	_, err = m.Enqueue(ctx, "u1", "export", nil)
+	assert.ErrorIs(t, err, ErrUnknownJobType)
+
+	// Fora de um job não há checkpoints
+	assert.ErrorIs(t, SaveCheckpoint(ctx, "row", 1), ErrNotInJob)
+}
+
+func TestManagerStatsAndCleanup(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	m := NewManager(store, Config{Workers: 1, QueueSize: 10, Clock: clock.NewFake(now)})
+	defer m.Close(ctx)
+
+	at := func(d time.Duration) *time.Time {
+		v := now.Add(d)
+		return &v
+	}
+	old := newJob("u1", "test", nil, now.Add(-72*time.Hour))
+	old.State, old.StartedAt, old.FinishedAt = models.JobStateCompleted, at(-72*time.Hour), at(-72*time.Hour+100*time.Millisecond)
+	recent := newJob("u1", "test", nil, now.Add(-time.Hour))
+	recent.State, recent.StartedAt, recent.FinishedAt = models.JobStateFailed, at(-time.Hour), at(-time.Hour+300*time.Millisecond)
+	pending := newJob("u1", "test", nil, now.Add(-2*time.Hour))
+	pending.State = models.JobStatePending
+
+	// Etapa antiga de um workflow ainda em andamento
+	workflow := newJob("u1", "flow", nil, now.Add(-96*time.Hour))
+	workflow.State = models.JobStateRunning
+	step := newJob("u1", "test", nil, now.Add(-96*time.Hour))
+	step.ParentID = workflow.ID
+	step.State, step.FinishedAt = models.JobStateCompleted, at(-96*time.Hour)
+
+	for _, job := range []*models.JobStatus{&old, &recent, &pending, &workflow, &step} {
+		require.NoError(t, store.Save(ctx, job))
+	}
+
+	stats, err := m.Stats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 5, stats.Total)
+	assert.Equal(t, map[string]int{models.JobStateCompleted: 2, models.JobStateFailed: 1, models.JobStatePending: 1, models.JobStateRunning: 1}, stats.ByState)
+	require.NotNil(t, stats.OldestPendingAt)
+	assert.Equal(t, pending.CreatedAt, *stats.OldestPendingAt)
+	assert.Equal(t, 200.0, stats.AverageDurationMs)
+
+	cleanup, err := m.Cleanup(ctx, 24*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 1, cleanup.Deleted)
+	assert.Equal(t, now.Add(-24*time.Hour), cleanup.Before)
+
+	_, err = store.Get(ctx, old.ID)
+	assert.Error(t, err)
+	for _, id := range []string{recent.ID, pending.ID, workflow.ID, step.ID} {
+		_, err = store.Get(ctx, id)
+		assert.NoError(t, err)
+	}
+}
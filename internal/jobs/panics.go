@@ -0,0 +1,195 @@
+package jobs
+
+import (
+	stderrors "errors"
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"callable-api/internal/models"
+)
+
+// ErrJobTypeDisabled é a causa dos erros de tipos de job desativados por panics repetidos
+var ErrJobTypeDisabled = stderrors.New("tipo de job desativado por falhas repetidas")
+
+// Classes de panic contabilizadas por tipo de job
+const (
+	PanicClassNilPointer    = "nil_pointer"
+	PanicClassOutOfRange    = "out_of_range"
+	PanicClassTypeAssertion = "type_assertion"
+	PanicClassRuntime       = "runtime"
+	PanicClassError         = "error"
+	PanicClassValue         = "value"
+)
+
+// PanicPolicy define quando um tipo de job é desativado (circuit breaker)
+type PanicPolicy struct {
+	Threshold int           // panics na janela que desativam o tipo (0 nunca desativa)
+	Window    time.Duration // janela de contagem dos panics
+	Cooldown  time.Duration // tempo em que o tipo fica desativado
+}
+
+// DefaultPanicPolicy retorna a política padrão: 3 panics em 10 minutos
+// desativam o tipo de job por 15 minutos
+func DefaultPanicPolicy() PanicPolicy {
+	return PanicPolicy{
+		Threshold: 3,
+		Window:    10 * time.Minute,
+		Cooldown:  15 * time.Minute,
+	}
+}
+
+// PanicError é o erro de um job que entrou em panic
+type PanicError struct {
+	Class string
+	Value interface{}
+	Stack []byte `redact:"true"`
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic no job (%s): %v", e.Class, e.Value)
+}
+
+// DisabledError indica que o tipo de job está desativado até Until
+type DisabledError struct {
+	Type  string
+	Until time.Time
+}
+
+func (e *DisabledError) Error() string {
+	return fmt.Sprintf("%s: %s até %s", ErrJobTypeDisabled, e.Type, e.Until.Format(time.RFC3339))
+}
+
+// Is permite reconhecer o erro com errors.Is(err, ErrJobTypeDisabled)
+func (e *DisabledError) Is(target error) bool {
+	return target == ErrJobTypeDisabled
+}
+
+// classifyPanic agrupa os valores de panic pelas causas mais comuns
+func classifyPanic(r interface{}) string {
+	if runtimeErr, ok := r.(runtime.Error); ok {
+		msg := runtimeErr.Error()
+		switch {
+		case strings.Contains(msg, "nil pointer") || strings.Contains(msg, "nil map"):
+			return PanicClassNilPointer
+		case strings.Contains(msg, "out of range"):
+			return PanicClassOutOfRange
+		case strings.Contains(msg, "interface conversion"):
+			return PanicClassTypeAssertion
+		default:
+			return PanicClassRuntime
+		}
+	}
+	if _, ok := r.(error); ok {
+		return PanicClassError
+	}
+	return PanicClassValue
+}
+
+// typePanics acompanha os panics de um tipo de job
+type typePanics struct {
+	stats  models.JobPanicStats
+	recent []time.Time // panics dentro da janela da política
+}
+
+// panicTracker contabiliza os panics por tipo de job e desativa os tipos que
+// ultrapassam a política
+type panicTracker struct {
+	mu     sync.Mutex
+	policy PanicPolicy
+	types  map[string]*typePanics
+	now    func() time.Time
+}
+
+// newPanicTracker cria um panicTracker com a política informada
+func newPanicTracker(policy PanicPolicy) *panicTracker {
+	return &panicTracker{
+		policy: policy,
+		types:  make(map[string]*typePanics),
+		now:    time.Now,
+	}
+}
+
+// record registra o panic, retornando true se ele desativou o tipo de job
+func (t *panicTracker) record(jobType string, p *PanicError) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now().UTC()
+	tp, ok := t.types[jobType]
+	if !ok {
+		tp = &typePanics{stats: models.JobPanicStats{Type: jobType, ByClass: make(map[string]int64)}}
+		t.types[jobType] = tp
+	}
+
+	tp.stats.Total++
+	tp.stats.ByClass[p.Class]++
+	tp.stats.LastPanic = fmt.Sprint(p.Value)
+	tp.stats.LastPanicAt = &now
+
+	if t.policy.Threshold <= 0 {
+		return false
+	}
+
+	// Manter apenas os panics dentro da janela
+	recent := tp.recent[:0]
+	for _, at := range tp.recent {
+		if t.policy.Window <= 0 || now.Sub(at) < t.policy.Window {
+			recent = append(recent, at)
+		}
+	}
+	tp.recent = append(recent, now)
+
+	if len(tp.recent) < t.policy.Threshold || t.disabled(tp, now) {
+		return false
+	}
+
+	until := now.Add(t.policy.Cooldown)
+	tp.stats.DisabledUntil = &until
+	tp.recent = nil
+	return true
+}
+
+// check retorna um DisabledError se o tipo de job estiver desativado
+func (t *panicTracker) check(jobType string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tp, ok := t.types[jobType]
+	if !ok || !t.disabled(tp, t.now()) {
+		return nil
+	}
+	return &DisabledError{Type: jobType, Until: *tp.stats.DisabledUntil}
+}
+
+// disabled indica se o tipo está desativado. Chamado com o mutex travado
+func (t *panicTracker) disabled(tp *typePanics, now time.Time) bool {
+	return tp.stats.DisabledUntil != nil && now.Before(*tp.stats.DisabledUntil)
+}
+
+// snapshot retorna as estatísticas de todos os tipos, ordenadas pelo tipo
+func (t *panicTracker) snapshot() []models.JobPanicStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	result := make([]models.JobPanicStats, 0, len(t.types))
+	for _, tp := range t.types {
+		stats := tp.stats
+		stats.ByClass = make(map[string]int64, len(tp.stats.ByClass))
+		for class, count := range tp.stats.ByClass {
+			stats.ByClass[class] = count
+		}
+		if !t.disabled(tp, now) {
+			stats.DisabledUntil = nil
+		}
+		result = append(result, stats)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Type < result[j].Type
+	})
+	return result
+}
@@ -0,0 +1,114 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"callable-api/internal/models"
+	"callable-api/internal/notifications"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recoverClass classifica o panic provocado por fn
+func recoverClass(fn func()) (class string) {
+	defer func() {
+		class = classifyPanic(recover())
+	}()
+	fn()
+	return ""
+}
+
+func TestClassifyPanic(t *testing.T) {
+	var nilMap map[string]int
+	var values []int
+	var value interface{} = "texto"
+
+	assert.Equal(t, PanicClassNilPointer, recoverClass(func() { nilMap["a"] = 1 }))
+	assert.Equal(t, PanicClassOutOfRange, recoverClass(func() { _ = values[len(values)] }))
+	assert.Equal(t, PanicClassTypeAssertion, recoverClass(func() { _ = value.(int) }))
+	assert.Equal(t, PanicClassError, recoverClass(func() { panic(errors.New("falhou")) }))
+	assert.Equal(t, PanicClassValue, recoverClass(func() { panic("falhou") }))
+}
+
+func TestPanicTracker(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	tracker := newPanicTracker(PanicPolicy{Threshold: 2, Window: time.Minute, Cooldown: 10 * time.Minute})
+	tracker.now = func() time.Time { return now }
+	p := &PanicError{Class: PanicClassValue, Value: "boom"}
+
+	// Panics fora da janela não se acumulam
+	assert.False(t, tracker.record("import", p))
+	now = now.Add(2 * time.Minute)
+	assert.False(t, tracker.record("import", p))
+	assert.NoError(t, tracker.check("import"))
+
+	// Dois panics na janela desativam o tipo até o fim do cooldown
+	now = now.Add(10 * time.Second)
+	assert.True(t, tracker.record("import", p))
+	assert.ErrorIs(t, tracker.check("import"), ErrJobTypeDisabled)
+	assert.NoError(t, tracker.check("export"))
+
+	stats := tracker.snapshot()
+	require.Len(t, stats, 1)
+	assert.Equal(t, int64(3), stats[0].Total)
+	assert.Equal(t, int64(3), stats[0].ByClass[PanicClassValue])
+	assert.NotNil(t, stats[0].DisabledUntil)
+
+	now = now.Add(10 * time.Minute)
+	assert.NoError(t, tracker.check("import"))
+	assert.Nil(t, tracker.snapshot()[0].DisabledUntil)
+}
+
+// fakeNotifier registra as notificações publicadas
+type fakeNotifier struct {
+	mu     sync.Mutex
+	events []notifications.Event
+}
+
+func (n *fakeNotifier) Publish(event notifications.Event) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.events = append(n.events, event)
+}
+
+func TestManagerDisablesPanickingJobType(t *testing.T) {
+	cfg := Config{Workers: 1, QueueSize: 10, Panics: PanicPolicy{Threshold: 2, Window: time.Minute, Cooldown: time.Minute}}
+	notifier := &fakeNotifier{}
+	m := NewManager(NewMemoryStore(), cfg).
+		WithNotifier(notifier).
+		WithAlerts(func(ctx context.Context) ([]string, error) { return []string{"admin-1"}, nil })
+	defer m.Close(context.Background())
+
+	owner := models.Viewer{UserID: "u1"}
+	for i := 0; i < 2; i++ {
+		job, err := m.ScheduleJob(context.Background(), "u1", "import", func(ctx context.Context) (interface{}, error) {
+			var values []int
+			return values[1], nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, models.JobStateFailed, waitFinished(t, m, owner, job.ID).State)
+	}
+
+	// O tipo desativado recusa novos jobs, e os administradores são avisados
+	_, err := m.ScheduleJob(context.Background(), "u1", "import", func(ctx context.Context) (interface{}, error) {
+		return nil, nil
+	})
+	var disabled *DisabledError
+	require.ErrorAs(t, err, &disabled)
+	assert.Equal(t, "import", disabled.Type)
+
+	notifier.mu.Lock()
+	defer notifier.mu.Unlock()
+	require.Len(t, notifier.events, 1)
+	assert.Equal(t, models.NotificationEventJobTypeDisabled, notifier.events[0].Type)
+	assert.Equal(t, "admin-1", notifier.events[0].UserID)
+
+	stats := m.PanicStats()
+	require.Len(t, stats, 1)
+	assert.Equal(t, int64(2), stats[0].ByClass[PanicClassOutOfRange])
+}
@@ -0,0 +1,131 @@
+package jobs
+
+import "sync"
+
+// scheduler mantém uma fila por usuário e entrega os jobs aos workers em
+// rodízio entre os usuários, para que um cliente com muitos jobs não atrase
+// os dos demais. Usuários no limite de jobs em execução são pulados até que
+// um dos seus jobs termine
+type scheduler struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+
+	queues  map[string][]*task // jobs aguardando, por usuário
+	order   []string           // usuários com jobs aguardando, na ordem do rodízio
+	running map[string]int     // jobs em execução, por usuário
+	queued  int
+
+	capacity   int // limite de jobs aguardando, somando todos os usuários
+	maxRunning int // limite de jobs em execução por usuário (0 = sem limite)
+	maxQueued  int // limite de jobs aguardando por usuário (0 = sem limite)
+	closed     bool
+}
+
+// newScheduler cria um scheduler com os limites informados
+func newScheduler(capacity, maxRunning, maxQueued int) *scheduler {
+	s := &scheduler{
+		queues:     make(map[string][]*task),
+		running:    make(map[string]int),
+		capacity:   capacity,
+		maxRunning: maxRunning,
+		maxQueued:  maxQueued,
+	}
+	s.cond = sync.NewCond(&s.mu)
+	return s
+}
+
+// push enfileira o job. Com enforce=false os limites são ignorados (jobs
+// retomados já tinham sido aceitos antes da reinicialização)
+func (s *scheduler) push(t *task, enforce bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user := t.job.UserID
+	switch {
+	case s.closed:
+		return ErrClosed
+	case enforce && s.queued >= s.capacity:
+		return ErrQueueFull
+	case enforce && s.maxQueued > 0 && len(s.queues[user]) >= s.maxQueued:
+		return ErrUserQueueFull
+	}
+
+	if len(s.queues[user]) == 0 {
+		s.order = append(s.order, user)
+	}
+	s.queues[user] = append(s.queues[user], t)
+	s.queued++
+	s.cond.Signal()
+	return nil
+}
+
+// pop aguarda o próximo job a executar, retornando false quando o scheduler
+// foi fechado e não restam jobs
+func (s *scheduler) pop() (*task, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for {
+		if t := s.next(); t != nil {
+			return t, true
+		}
+		if s.closed && s.queued == 0 {
+			return nil, false
+		}
+		s.cond.Wait()
+	}
+}
+
+// next retira o job do primeiro usuário do rodízio abaixo do limite de
+// execução, movendo-o para o fim do rodízio. Chamado com o mutex travado
+func (s *scheduler) next() *task {
+	for i, user := range s.order {
+		if s.maxRunning > 0 && s.running[user] >= s.maxRunning {
+			continue
+		}
+
+		queue := s.queues[user]
+		t := queue[0]
+		queue[0] = nil
+		s.queues[user] = queue[1:]
+		s.queued--
+		s.running[user]++
+
+		s.order = append(s.order[:i], s.order[i+1:]...)
+		if len(s.queues[user]) > 0 {
+			s.order = append(s.order, user)
+		} else {
+			delete(s.queues, user)
+		}
+		return t
+	}
+	return nil
+}
+
+// done registra o fim de um job do usuário, liberando seus jobs aguardando
+func (s *scheduler) done(user string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.running[user]--
+	if s.running[user] <= 0 {
+		delete(s.running, user)
+	}
+	s.cond.Broadcast()
+}
+
+// close para de aceitar jobs; os workers terminam quando a fila esvaziar
+func (s *scheduler) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	s.cond.Broadcast()
+}
+
+// isClosed indica se o scheduler já foi fechado
+func (s *scheduler) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
@@ -0,0 +1,58 @@
+package jobs
+
+import (
+	"testing"
+
+	"callable-api/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func userTask(user, id string) *task {
+	return &task{job: models.JobStatus{ID: id, UserID: user}}
+}
+
+func TestSchedulerFairness(t *testing.T) {
+	s := newScheduler(10, 1, 3)
+
+	// Um usuário com muitos jobs não passa à frente dos demais
+	for _, id := range []string{"a1", "a2", "a3"} {
+		require.NoError(t, s.push(userTask("a", id), true))
+	}
+	require.NoError(t, s.push(userTask("b", "b1"), true))
+
+	// Além da cota de jobs aguardando, o job é recusado
+	assert.ErrorIs(t, s.push(userTask("a", "a4"), true), ErrUserQueueFull)
+
+	first, _ := s.pop()
+	second, _ := s.pop()
+	assert.Equal(t, "a1", first.job.ID)
+	assert.Equal(t, "b1", second.job.ID)
+
+	// O usuário a só volta a executar quando o job anterior termina
+	assert.Nil(t, s.next())
+	s.done("a")
+	third, _ := s.pop()
+	assert.Equal(t, "a2", third.job.ID)
+
+	// Fechado, o scheduler entrega os jobs restantes e depois para
+	s.done("a")
+	s.close()
+	assert.ErrorIs(t, s.push(userTask("c", "c1"), true), ErrClosed)
+	last, ok := s.pop()
+	assert.True(t, ok)
+	assert.Equal(t, "a3", last.job.ID)
+	s.done("a")
+	_, ok = s.pop()
+	assert.False(t, ok)
+}
+
+func TestSchedulerCapacity(t *testing.T) {
+	s := newScheduler(1, 0, 0)
+	require.NoError(t, s.push(userTask("a", "a1"), true))
+	assert.ErrorIs(t, s.push(userTask("b", "b1"), true), ErrQueueFull)
+
+	// Jobs retomados não são recusados
+	assert.NoError(t, s.push(userTask("b", "b1"), false))
+}
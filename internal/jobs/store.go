@@ -0,0 +1,279 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"callable-api/internal/models"
+	"callable-api/pkg/errors"
+)
+
+// Store persiste o estado dos jobs
+type Store interface {
+	// Save cria ou substitui o estado do job
+	Save(ctx context.Context, job *models.JobStatus) error
+
+	// Get retorna o job pelo ID (NotFound se não existir)
+	Get(ctx context.Context, id string) (*models.JobStatus, error)
+
+	// CountByState retorna quantos jobs existem em cada estado
+	CountByState(ctx context.Context) (map[string]int, error)
+
+	// ListUnfinished retorna os jobs pendentes ou em execução, na ordem de criação
+	ListUnfinished(ctx context.Context) ([]models.JobStatus, error)
+
+	// Stats retorna o resumo dos jobs por estado, o pendente mais antigo e a
+	// duração média dos terminados
+	Stats(ctx context.Context) (*models.JobStats, error)
+
+	// DeleteFinished remove os jobs terminados antes de before, com seus
+	// checkpoints, e retorna quantos foram removidos. As etapas de workflows
+	// ainda em andamento são mantidas
+	DeleteFinished(ctx context.Context, before time.Time) (int, error)
+
+	// SaveCheckpoint grava um checkpoint do job, substituindo o anterior de mesmo nome
+	SaveCheckpoint(ctx context.Context, jobID, name string, data json.RawMessage) error
+
+	// Checkpoint retorna o checkpoint do job (nil se não houver)
+	Checkpoint(ctx context.Context, jobID, name string) (json.RawMessage, error)
+
+	// DeleteCheckpoints remove os checkpoints do job, quando ele termina
+	DeleteCheckpoints(ctx context.Context, jobID string) error
+
+	// AppendLog acrescenta uma linha ao log do job, mantendo apenas as
+	// maxEntries mais recentes (0 = sem limite)
+	AppendLog(ctx context.Context, jobID string, entry models.JobLogEntry, maxEntries int) error
+
+	// Logs retorna o log do job (vazio se o job ainda não registrou nada).
+	// O log é removido junto com o job por DeleteFinished
+	Logs(ctx context.Context, jobID string) (*models.JobLogs, error)
+}
+
+// MemoryStore mantém os jobs em memória
+type MemoryStore struct {
+	mu          sync.RWMutex
+	jobs        map[string]models.JobStatus
+	checkpoints map[string]map[string]json.RawMessage
+	logs        map[string]*models.JobLogs
+}
+
+// NewMemoryStore cria um MemoryStore vazio
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		jobs:        make(map[string]models.JobStatus),
+		checkpoints: make(map[string]map[string]json.RawMessage),
+		logs:        make(map[string]*models.JobLogs),
+	}
+}
+
+// Save implementa Store
+func (s *MemoryStore) Save(ctx context.Context, job *models.JobStatus) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// As etapas são copiadas para que o workflow gravado não mude com o job
+	saved := *job
+	saved.Steps = slices.Clone(job.Steps)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[job.ID] = saved
+	return nil
+}
+
+// Get implementa Store
+func (s *MemoryStore) Get(ctx context.Context, id string) (*models.JobStatus, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	job, exists := s.jobs[id]
+	if !exists {
+		return nil, errors.NewNotFoundError("Job não encontrado", nil)
+	}
+	job.Steps = slices.Clone(job.Steps)
+	return &job, nil
+}
+
+// CountByState implementa Store
+func (s *MemoryStore) CountByState(ctx context.Context) (map[string]int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	counts := make(map[string]int)
+	for _, job := range s.jobs {
+		counts[job.State]++
+	}
+	return counts, nil
+}
+
+// ListUnfinished implementa Store
+func (s *MemoryStore) ListUnfinished(ctx context.Context) ([]models.JobStatus, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	unfinished := make([]models.JobStatus, 0)
+	for _, job := range s.jobs {
+		if !job.Finished() {
+			unfinished = append(unfinished, job)
+		}
+	}
+	sort.Slice(unfinished, func(i, j int) bool {
+		return unfinished[i].CreatedAt.Before(unfinished[j].CreatedAt)
+	})
+	return unfinished, nil
+}
+
+// Stats implementa Store
+func (s *MemoryStore) Stats(ctx context.Context) (*models.JobStats, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	stats := &models.JobStats{ByState: make(map[string]int)}
+	var total time.Duration
+	var timed int
+	for _, job := range s.jobs {
+		stats.Total++
+		stats.ByState[job.State]++
+
+		if job.State == models.JobStatePending && (stats.OldestPendingAt == nil || job.CreatedAt.Before(*stats.OldestPendingAt)) {
+			createdAt := job.CreatedAt
+			stats.OldestPendingAt = &createdAt
+		}
+		if job.Finished() && job.StartedAt != nil && job.FinishedAt != nil {
+			total += job.FinishedAt.Sub(*job.StartedAt)
+			timed++
+		}
+	}
+	if timed > 0 {
+		stats.AverageDurationMs = float64(total) / float64(timed) / float64(time.Millisecond)
+	}
+	return stats, nil
+}
+
+// DeleteFinished implementa Store
+func (s *MemoryStore) DeleteFinished(ctx context.Context, before time.Time) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	deleted := 0
+	for id, job := range s.jobs {
+		if !job.Finished() || job.FinishedAt == nil || !job.FinishedAt.Before(before) {
+			continue
+		}
+		if parent, exists := s.jobs[job.ParentID]; exists && !parent.Finished() {
+			continue
+		}
+		delete(s.jobs, id)
+		delete(s.checkpoints, id)
+		delete(s.logs, id)
+		deleted++
+	}
+	return deleted, nil
+}
+
+// SaveCheckpoint implementa Store
+func (s *MemoryStore) SaveCheckpoint(ctx context.Context, jobID, name string, data json.RawMessage) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.jobs[jobID]; !exists {
+		return errors.NewNotFoundError("Job não encontrado", nil)
+	}
+	if s.checkpoints[jobID] == nil {
+		s.checkpoints[jobID] = make(map[string]json.RawMessage)
+	}
+	s.checkpoints[jobID][name] = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// Checkpoint implementa Store
+func (s *MemoryStore) Checkpoint(ctx context.Context, jobID, name string) (json.RawMessage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	data, exists := s.checkpoints[jobID][name]
+	if !exists {
+		return nil, nil
+	}
+	return append(json.RawMessage(nil), data...), nil
+}
+
+// DeleteCheckpoints implementa Store
+func (s *MemoryStore) DeleteCheckpoints(ctx context.Context, jobID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.checkpoints, jobID)
+	return nil
+}
+
+// AppendLog implementa Store
+func (s *MemoryStore) AppendLog(ctx context.Context, jobID string, entry models.JobLogEntry, maxEntries int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.jobs[jobID]; !exists {
+		return errors.NewNotFoundError("Job não encontrado", nil)
+	}
+	logs := s.logs[jobID]
+	if logs == nil {
+		logs = &models.JobLogs{JobID: jobID}
+		s.logs[jobID] = logs
+	}
+	logs.Entries = append(logs.Entries, entry)
+	if maxEntries > 0 && len(logs.Entries) > maxEntries {
+		excess := len(logs.Entries) - maxEntries
+		logs.Entries = slices.Delete(logs.Entries, 0, excess)
+		logs.Dropped += excess
+	}
+	return nil
+}
+
+// Logs implementa Store
+func (s *MemoryStore) Logs(ctx context.Context, jobID string) (*models.JobLogs, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	logs, exists := s.logs[jobID]
+	if !exists {
+		return &models.JobLogs{JobID: jobID, Entries: []models.JobLogEntry{}}, nil
+	}
+	copied := *logs
+	copied.Entries = slices.Clone(logs.Entries)
+	return &copied, nil
+}
@@ -0,0 +1,234 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+
+	"callable-api/internal/correlation"
+	"callable-api/internal/models"
+	"callable-api/pkg/errors"
+	"callable-api/pkg/logger"
+)
+
+// ErrUnknownWorkflow é retornado por StartWorkflow para workflows não registrados
+var ErrUnknownWorkflow = stderrors.New("workflow não registrado")
+
+// StepError é o erro de um workflow cuja etapa falhou. O código de erro da
+// etapa é mantido no workflow
+type StepError struct {
+	Step    string
+	JobID   string
+	Message string
+	Code    string
+}
+
+func (e *StepError) Error() string {
+	return fmt.Sprintf("etapa %s falhou: %s", e.Step, e.Message)
+}
+
+// RegisterWorkflow registra um workflow: uma sequência de tipos de job
+// registrados em que o resultado de cada etapa é a entrada da seguinte
+// (por exemplo import → validate → index)
+func (m *Manager) RegisterWorkflow(name string, steps ...string) *Manager {
+	m.handlersMu.Lock()
+	defer m.handlersMu.Unlock()
+	m.workflows[name] = append([]string(nil), steps...)
+	return m
+}
+
+// workflow retorna as etapas do workflow registrado
+func (m *Manager) workflow(name string) ([]string, bool) {
+	m.handlersMu.RLock()
+	defer m.handlersMu.RUnlock()
+	steps, ok := m.workflows[name]
+	return steps, ok && len(steps) > 0
+}
+
+// StartWorkflow inicia o workflow com a entrada da primeira etapa. O job
+// retornado é o do workflow: seu estado agrega o das etapas (Steps), cada uma
+// executada como um job próprio. As opções valem para a primeira etapa
+func (m *Manager) StartWorkflow(ctx context.Context, userID, name string, input interface{}, opts ...Option) (*models.JobStatus, error) {
+	steps, ok := m.workflow(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownWorkflow, name)
+	}
+	handlers := make([]Handler, len(steps))
+	for i, step := range steps {
+		if handlers[i], ok = m.handler(step); !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownJobType, step)
+		}
+	}
+
+	o := m.newOptions(opts)
+	if err := m.checkRunAt(o); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(input)
+	if err != nil {
+		return nil, errors.NewInternalServerError("Falha ao serializar entrada do workflow", err)
+	}
+
+	parent := m.newJob(userID, name, data)
+	parent.Steps = make([]models.JobStep, len(steps))
+	for i, step := range steps {
+		parent.Steps[i] = models.JobStep{Type: step, State: models.JobStatePending}
+	}
+	if o.runAt.After(m.clock.Now()) {
+		runAt := o.runAt.UTC()
+		parent.State = models.JobStateScheduled
+		parent.RunAt = &runAt
+	}
+	first := m.newJob(userID, steps[0], data)
+	first.ParentID = parent.ID
+	parent.Steps[0].JobID = first.ID
+
+	m.workflowMu.Lock()
+	err = m.persist(ctx, &parent)
+	m.workflowMu.Unlock()
+	if err != nil {
+		return nil, errors.NewInternalServerError("Falha ao registrar workflow", err)
+	}
+
+	// Falhas da primeira etapa também encerram o workflow
+	if _, err := m.schedule(ctx, first, bind(handlers[0], data), o); err != nil {
+		m.failWorkflow(context.WithoutCancel(ctx), parent.ID, err)
+		return nil, err
+	}
+
+	m.workflowMu.Lock()
+	defer m.workflowMu.Unlock()
+	return m.store.Get(ctx, parent.ID)
+}
+
+// failWorkflow encerra como falho o workflow que ainda não terminou
+func (m *Manager) failWorkflow(ctx context.Context, id string, err error) {
+	m.workflowMu.Lock()
+	defer m.workflowMu.Unlock()
+
+	parent, getErr := m.store.Get(ctx, id)
+	if getErr != nil || parent.Finished() {
+		return
+	}
+	m.finish(ctx, parent, nil, err)
+}
+
+// syncStep reflete no workflow o estado da etapa e, quando ela é concluída,
+// inicia a etapa seguinte com o seu resultado
+func (m *Manager) syncStep(ctx context.Context, step *models.JobStatus, result interface{}) {
+	if step.ParentID == "" {
+		return
+	}
+
+	next, err := m.advance(ctx, step, result)
+	if err != nil {
+		logger.Warn("Falha ao atualizar workflow", correlation.Fields(ctx, map[string]interface{}{
+			"jobId":      step.ID,
+			"workflowId": step.ParentID,
+			"error":      err.Error(),
+		}))
+		return
+	}
+	if next == nil {
+		return
+	}
+
+	handler, ok := m.handler(next.job.Type)
+	if !ok {
+		m.finish(ctx, &next.job, nil, fmt.Errorf("%w: %s", ErrUnknownJobType, next.job.Type))
+		return
+	}
+	next.fn = bind(handler, next.job.Input)
+
+	// As etapas seguintes não passam pelos limites: o workflow já foi aceito
+	if err := m.panics.check(next.job.Type); err != nil {
+		m.finish(ctx, &next.job, nil, err)
+		return
+	}
+	if err := m.enqueue(ctx, next); err != nil {
+		m.finish(ctx, &next.job, nil, err)
+	}
+}
+
+// advance atualiza o workflow da etapa, retornando a próxima etapa a enfileirar
+func (m *Manager) advance(ctx context.Context, step *models.JobStatus, result interface{}) (*task, error) {
+	ctx = context.WithoutCancel(ctx)
+
+	m.workflowMu.Lock()
+	defer m.workflowMu.Unlock()
+
+	parent, err := m.store.Get(ctx, step.ParentID)
+	if err != nil {
+		return nil, err
+	}
+	if parent.Finished() {
+		return nil, nil
+	}
+
+	index := -1
+	for i := range parent.Steps {
+		if parent.Steps[i].JobID == step.ID {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return nil, fmt.Errorf("etapa %s não pertence ao workflow", step.ID)
+	}
+	parent.Steps[index].State = step.State
+
+	switch step.State {
+	case models.JobStateFailed, models.JobStateTimedOut:
+		m.finish(ctx, parent, nil, &StepError{Step: step.Type, JobID: step.ID, Message: step.Error, Code: step.ErrorCode})
+		return nil, nil
+	case models.JobStateCompleted:
+		if index == len(parent.Steps)-1 {
+			m.finish(ctx, parent, result, nil)
+			return nil, nil
+		}
+	default:
+		if parent.State != models.JobStateRunning && step.State == models.JobStateRunning {
+			parent.State = models.JobStateRunning
+			parent.StartedAt = step.StartedAt
+		}
+		return nil, m.persist(ctx, parent)
+	}
+
+	// O resultado da etapa concluída é a entrada da seguinte
+	data, err := json.Marshal(result)
+	if err != nil {
+		m.finish(ctx, parent, nil, errors.NewInternalServerError("Falha ao serializar resultado da etapa", err))
+		return nil, nil
+	}
+	next := &task{job: m.newJob(parent.UserID, parent.Steps[index+1].Type, data)}
+	next.job.ParentID = parent.ID
+	next.requestID = correlation.RequestID(ctx)
+	parent.Steps[index+1].JobID = next.job.ID
+	if err := m.persist(ctx, parent); err != nil {
+		return nil, err
+	}
+	return next, nil
+}
+
+// resumeWorkflow retoma, após uma reinicialização, o workflow cuja etapa atual
+// terminou sem que a seguinte fosse iniciada. Etapas inacabadas são retomadas
+// por Recover como os demais jobs
+func (m *Manager) resumeWorkflow(ctx context.Context, parent *models.JobStatus) {
+	var current string
+	for _, step := range parent.Steps {
+		if step.JobID != "" {
+			current = step.JobID
+		}
+	}
+
+	step, err := m.store.Get(ctx, current)
+	if err != nil {
+		m.failWorkflow(ctx, parent.ID, ErrInterrupted)
+		return
+	}
+	if step.Finished() {
+		m.syncStep(ctx, step, step.Result)
+	}
+}
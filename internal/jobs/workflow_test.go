@@ -0,0 +1,117 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"callable-api/internal/models"
+	"callable-api/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newWorkflowManager registra o workflow import → validate → index
+func newWorkflowManager(t *testing.T, store Store) *Manager {
+	m := NewManager(store, Config{Workers: 2, QueueSize: 10})
+	t.Cleanup(func() { m.Close(context.Background()) })
+
+	m.Register("import", func(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+		var rows []string
+		if err := json.Unmarshal(raw, &rows); err != nil {
+			return nil, err
+		}
+		return rows, nil
+	})
+	m.Register("validate", func(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+		var rows []string
+		if err := json.Unmarshal(raw, &rows); err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			if row == "" {
+				return nil, errors.NewBadRequestError("Linha vazia", nil)
+			}
+		}
+		return len(rows), nil
+	})
+	m.Register("index", func(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+		var count int
+		if err := json.Unmarshal(raw, &count); err != nil {
+			return nil, err
+		}
+		return map[string]int{"indexed": count}, nil
+	})
+	return m.RegisterWorkflow("bulk-import", "import", "validate", "index")
+}
+
+func TestWorkflow(t *testing.T) {
+	ctx := context.Background()
+	m := newWorkflowManager(t, NewMemoryStore())
+	owner := models.Viewer{UserID: "u1"}
+
+	workflow, err := m.StartWorkflow(ctx, "u1", "bulk-import", []string{"a", "b"})
+	require.NoError(t, err)
+	assert.Equal(t, "bulk-import", workflow.Type)
+	require.Len(t, workflow.Steps, 3)
+	assert.NotEmpty(t, workflow.Steps[0].JobID)
+
+	// Cada etapa recebe o resultado da anterior; o workflow termina com o da última
+	done := waitFinished(t, m, owner, workflow.ID)
+	assert.Equal(t, models.JobStateCompleted, done.State)
+	assert.Equal(t, map[string]int{"indexed": 2}, done.Result)
+	for _, step := range done.Steps {
+		assert.Equal(t, models.JobStateCompleted, step.State, step.Type)
+
+		job, err := m.GetJob(ctx, owner, step.JobID)
+		require.NoError(t, err)
+		assert.Equal(t, workflow.ID, job.ParentID)
+	}
+
+	// A falha de uma etapa encerra o workflow com o código de erro da etapa
+	workflow, err = m.StartWorkflow(ctx, "u1", "bulk-import", []string{"a", ""})
+	require.NoError(t, err)
+	failed := waitFinished(t, m, owner, workflow.ID)
+	assert.Equal(t, models.JobStateFailed, failed.State)
+	assert.Equal(t, "BAD_REQUEST", failed.ErrorCode)
+	assert.Equal(t, []string{models.JobStateCompleted, models.JobStateFailed, models.JobStatePending},
+		[]string{failed.Steps[0].State, failed.Steps[1].State, failed.Steps[2].State})
+	assert.Empty(t, failed.Steps[2].JobID)
+
+	_, err = m.StartWorkflow(ctx, "u1", "export", nil)
+	assert.ErrorIs(t, err, ErrUnknownWorkflow)
+	m.RegisterWorkflow("broken", "import", "publish")
+	_, err = m.StartWorkflow(ctx, "u1", "broken", nil)
+	assert.True(t, stderrors.Is(err, ErrUnknownJobType))
+}
+
+func TestWorkflowRecover(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	// O servidor parou depois de concluir a primeira etapa e antes de iniciar a segunda
+	workflow := newJob("u1", "bulk-import", json.RawMessage(`["a"]`), time.Now())
+	workflow.State = models.JobStateRunning
+	imported := newJob("u1", "import", workflow.Input, time.Now())
+	imported.ParentID = workflow.ID
+	imported.State = models.JobStateCompleted
+	imported.Result = []string{"a"}
+	workflow.Steps = []models.JobStep{
+		{Type: "import", JobID: imported.ID, State: models.JobStateRunning},
+		{Type: "validate", State: models.JobStatePending},
+		{Type: "index", State: models.JobStatePending},
+	}
+	require.NoError(t, store.Save(ctx, &workflow))
+	require.NoError(t, store.Save(ctx, &imported))
+
+	m := newWorkflowManager(t, store)
+	_, err := m.Recover(ctx)
+	require.NoError(t, err)
+
+	done := waitFinished(t, m, models.Viewer{UserID: "u1"}, workflow.ID)
+	assert.Equal(t, models.JobStateCompleted, done.State)
+	assert.Equal(t, map[string]int{"indexed": 1}, done.Result)
+}
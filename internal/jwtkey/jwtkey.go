@@ -0,0 +1,93 @@
+// Package jwtkey guarda o segredo usado para assinar e validar os JWTs. O
+// segredo pode ser trocado com o servidor em execução (ver Keys.Rotate),
+// quando o Secret Manager avisa que ele foi rotacionado; os tokens assinados
+// com o segredo anterior continuam valendo durante o período de transição.
+package jwtkey
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"callable-api/internal/clock"
+	"callable-api/pkg/auth"
+	"callable-api/pkg/config"
+)
+
+// DefaultGracePeriod é por quanto tempo o segredo anterior continua válido
+// após a rotação, para que as sessões abertas não sejam encerradas de uma vez
+const DefaultGracePeriod = 15 * time.Minute
+
+// state é a configuração vigente e a anterior, trocadas juntas
+type state struct {
+	current       *config.Config
+	previous      *config.Config
+	previousUntil time.Time
+}
+
+// Keys fornece a configuração dos JWTs com o segredo vigente
+type Keys struct {
+	state atomic.Pointer[state]
+	mutex sync.Mutex // serializa as rotações
+	grace time.Duration
+	clock clock.Clock
+}
+
+// New cria um Keys com o segredo de cfg
+func New(cfg *config.Config) *Keys {
+	k := &Keys{grace: DefaultGracePeriod, clock: clock.System()}
+	k.state.Store(&state{current: cfg})
+	return k
+}
+
+// WithGracePeriod define por quanto tempo o segredo anterior é aceito após a
+// rotação (0 recusa os tokens antigos imediatamente)
+func (k *Keys) WithGracePeriod(d time.Duration) *Keys {
+	k.grace = d
+	return k
+}
+
+// WithClock define o relógio do período de transição (padrão: o do sistema)
+func (k *Keys) WithClock(c clock.Clock) *Keys {
+	k.clock = c
+	return k
+}
+
+// Config retorna a configuração com o segredo vigente, usada para assinar os
+// tokens. A configuração retornada não deve ser alterada
+func (k *Keys) Config() *config.Config {
+	return k.state.Load().current
+}
+
+// Rotate passa a assinar os tokens com o novo segredo. O segredo anterior
+// ainda valida tokens durante o período de transição
+func (k *Keys) Rotate(secret string) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	old := k.state.Load()
+	if secret == old.current.JWTSecret {
+		return
+	}
+	next := *old.current
+	next.JWTSecret = secret
+	next.JWTConfig.SecretKey = secret
+	k.state.Store(&state{
+		current:       &next,
+		previous:      old.current,
+		previousUntil: k.clock.Now().Add(k.grace),
+	})
+}
+
+// ValidateToken valida o token com o segredo vigente ou, durante o período
+// de transição, com o anterior
+func (k *Keys) ValidateToken(token string, refresh bool) (*auth.Claims, error) {
+	s := k.state.Load()
+	claims, err := auth.ValidateToken(token, refresh, s.current)
+	if err != nil && s.previous != nil && k.clock.Now().Before(s.previousUntil) {
+		if previous, prevErr := auth.ValidateToken(token, refresh, s.previous); prevErr == nil {
+			return previous, nil
+		}
+	}
+	return claims, err
+}
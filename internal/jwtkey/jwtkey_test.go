@@ -0,0 +1,42 @@
+package jwtkey
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"callable-api/internal/clock"
+	"callable-api/internal/models"
+	"callable-api/pkg/auth"
+	"callable-api/pkg/config"
+)
+
+func TestKeys_Rotate(t *testing.T) {
+	clk := clock.NewFake(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+	cfg := &config.Config{JWTSecret: "antigo", JWTConfig: config.JWTConfig{SecretKey: "antigo"}, JWTExpirationMinutes: 15, JWTRefreshExpirationDays: 7}
+	keys := New(cfg).WithClock(clk).WithGracePeriod(time.Minute)
+	user := &models.User{ID: "u1", Email: "u1@example.com", Role: "user"}
+
+	old, err := auth.GenerateTokenPair(user, keys.Config())
+	require.NoError(t, err)
+
+	keys.Rotate("novo")
+	assert.Equal(t, "novo", keys.Config().JWTSecret)
+	assert.Equal(t, "novo", keys.Config().JWTConfig.SecretKey)
+	assert.Equal(t, "antigo", cfg.JWTSecret, "a configuração original não é alterada")
+
+	fresh, err := auth.GenerateTokenPair(user, keys.Config())
+	require.NoError(t, err)
+	claims, err := keys.ValidateToken(fresh.AccessToken, false)
+	require.NoError(t, err)
+	assert.Equal(t, "u1", claims.UserID)
+
+	// O segredo anterior vale apenas durante o período de transição
+	_, err = keys.ValidateToken(old.AccessToken, false)
+	assert.NoError(t, err)
+	clk.Advance(2 * time.Minute)
+	_, err = keys.ValidateToken(old.AccessToken, false)
+	assert.Error(t, err)
+}
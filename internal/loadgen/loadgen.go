@@ -0,0 +1,182 @@
+// Package loadgen exercita as listagens, a busca e a leitura de itens de uma
+// API em execução por um período e imprime a latência de cada operação, para
+// medir regressões de desempenho na paginação e no cache (ver o comando
+// loadgen em cmd/api).
+package loadgen
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"callable-api/client"
+	"callable-api/internal/synthetic"
+)
+
+// Scenarios são as operações exercitadas pelo gerador de carga
+var Scenarios = []string{"list", "search", "get"}
+
+// API são as operações exercitadas pela carga (implementada por *client.Client)
+type API interface {
+	ListItems(ctx context.Context, opts *client.ListOptions) (*client.Page[client.Item], error)
+	SearchItems(ctx context.Context, q string, opts *client.ListOptions) (*client.Page[client.Item], error)
+	GetItem(ctx context.Context, id string) (*client.Item, error)
+}
+
+// Config define uma execução do gerador de carga
+type Config struct {
+	Target      string // URL da API, informada no relatório
+	Duration    time.Duration
+	Concurrency int // requisições simultâneas
+	Limit       int // itens por página nas listagens
+}
+
+// Validate verifica se a duração, a concorrência e o tamanho da página são positivos
+func (c Config) Validate() error {
+	if c.Concurrency < 1 || c.Duration <= 0 || c.Limit < 1 {
+		return fmt.Errorf("concurrency, duration e limit devem ser positivos")
+	}
+	return nil
+}
+
+// Login autentica o usuário da carga em cfg.Target. O cliente HTTP não faz as
+// novas tentativas do cliente padrão, que esconderiam as falhas e
+// distorceriam as latências
+func Login(ctx context.Context, cfg Config, email, password string) (*client.Client, error) {
+	httpClient := &http.Client{
+		Timeout:   30 * time.Second,
+		Transport: &http.Transport{MaxIdleConnsPerHost: cfg.Concurrency},
+	}
+
+	result, err := client.New(cfg.Target).WithHTTPClient(httpClient).Login(ctx, &client.LoginInput{Email: email, Password: password})
+	if err != nil {
+		return nil, fmt.Errorf("login de %s: %w", email, err)
+	}
+	return client.New(cfg.Target).WithHTTPClient(httpClient).WithToken(result.Tokens.AccessToken), nil
+}
+
+// Run executa a carga por cfg.Duration e imprime o relatório em out. Retorna
+// erro se nenhum item estiver visível ou se alguma requisição falhar
+func Run(ctx context.Context, out io.Writer, api API, cfg Config) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	// IDs lidos na operação get, que exercita o cache dos itens
+	first, err := api.ListItems(ctx, &client.ListOptions{Page: 1, Limit: 100})
+	if err != nil {
+		return fmt.Errorf("listar os itens: %w", err)
+	}
+	if first.Total == 0 || len(first.Items) == 0 {
+		return fmt.Errorf("nenhum item visível")
+	}
+	ids := make([]string, len(first.Items))
+	for i, item := range first.Items {
+		ids[i] = item.ID
+	}
+	pages := (first.Total + cfg.Limit - 1) / cfg.Limit
+
+	fmt.Fprintf(out, "Carga em %s: %d itens visíveis, %d requisições simultâneas por %s\n\n",
+		cfg.Target, first.Total, cfg.Concurrency, cfg.Duration)
+
+	stats := newStats()
+	words := synthetic.Words()
+	ctx, cancel := context.WithTimeout(ctx, cfg.Duration)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for w := 0; w < cfg.Concurrency; w++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(int64(worker) + 1))
+			for i := worker; ctx.Err() == nil; i++ {
+				scenario := Scenarios[i%len(Scenarios)]
+				start := time.Now()
+				var err error
+				switch scenario {
+				case "list":
+					_, err = api.ListItems(ctx, &client.ListOptions{Page: rng.Intn(pages) + 1, Limit: cfg.Limit})
+				case "search":
+					_, err = api.SearchItems(ctx, words[rng.Intn(len(words))], &client.ListOptions{Limit: cfg.Limit})
+				case "get":
+					_, err = api.GetItem(ctx, ids[rng.Intn(len(ids))])
+				}
+				if ctx.Err() != nil {
+					return // a requisição interrompida pelo fim da carga não conta
+				}
+				stats.record(scenario, time.Since(start), err)
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	return stats.print(out, cfg.Duration)
+}
+
+// stats acumula as latências e os erros de cada operação
+type stats struct {
+	mu        sync.Mutex
+	latencies map[string][]time.Duration
+	errors    map[string]int
+}
+
+func newStats() *stats {
+	return &stats{
+		latencies: make(map[string][]time.Duration),
+		errors:    make(map[string]int),
+	}
+}
+
+// record registra uma requisição
+func (s *stats) record(scenario string, latency time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latencies[scenario] = append(s.latencies[scenario], latency)
+	if err != nil {
+		s.errors[scenario]++
+	}
+}
+
+// print imprime o relatório. Retorna erro se alguma requisição falhou
+func (s *stats) print(out io.Writer, duration time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "OPERAÇÃO\tREQUISIÇÕES\tERROS\tREQ/S\tP50\tP95\tP99\tMÁX")
+	failed := 0
+	for _, scenario := range Scenarios {
+		latencies := s.latencies[scenario]
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+		failed += s.errors[scenario]
+		fmt.Fprintf(w, "%s\t%d\t%d\t%.1f\t%s\t%s\t%s\t%s\n", scenario, len(latencies), s.errors[scenario],
+			float64(len(latencies))/duration.Seconds(),
+			percentile(latencies, 50), percentile(latencies, 95), percentile(latencies, 99), percentile(latencies, 100))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d requisições falharam", failed)
+	}
+	return nil
+}
+
+// percentile retorna o percentil p das latências ordenadas
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	index := (len(sorted)*p+99)/100 - 1
+	if index < 0 {
+		index = 0
+	}
+	return sorted[index].Round(time.Microsecond)
+}
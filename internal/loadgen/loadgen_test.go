@@ -0,0 +1,70 @@
+package loadgen
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"callable-api/client"
+)
+
+// fakeAPI responde às operações da carga com três itens, falhando as buscas
+// quando failSearch está ativo
+type fakeAPI struct {
+	failSearch bool
+	gets       atomic.Int64
+}
+
+func (f *fakeAPI) ListItems(ctx context.Context, opts *client.ListOptions) (*client.Page[client.Item], error) {
+	return &client.Page[client.Item]{Items: []client.Item{{ID: "1"}, {ID: "2"}, {ID: "3"}}, Page: opts.Page, Total: 3}, nil
+}
+
+func (f *fakeAPI) SearchItems(ctx context.Context, q string, opts *client.ListOptions) (*client.Page[client.Item], error) {
+	if f.failSearch {
+		return nil, errors.New("indisponível")
+	}
+	return &client.Page[client.Item]{}, nil
+}
+
+func (f *fakeAPI) GetItem(ctx context.Context, id string) (*client.Item, error) {
+	f.gets.Add(1)
+	return &client.Item{ID: id}, nil
+}
+
+func TestRun(t *testing.T) {
+	cfg := Config{Target: "http://api.test", Duration: 50 * time.Millisecond, Concurrency: 3, Limit: 2}
+
+	var out bytes.Buffer
+	api := &fakeAPI{}
+	require.NoError(t, Run(context.Background(), &out, api, cfg), out.String())
+	assert.Contains(t, out.String(), "Carga em http://api.test: 3 itens visíveis")
+	for _, scenario := range Scenarios {
+		assert.Regexp(t, "(?m)^"+scenario+` +[1-9]\d* +0 `, out.String())
+	}
+	assert.Positive(t, api.gets.Load())
+
+	// As falhas aparecem no relatório e no erro retornado
+	out.Reset()
+	err := Run(context.Background(), &out, &fakeAPI{failSearch: true}, cfg)
+	assert.ErrorContains(t, err, "requisições falharam")
+	assert.Regexp(t, `(?m)^search +([1-9]\d*) +[1-9]\d* `, out.String())
+
+	assert.Error(t, Run(context.Background(), &out, &fakeAPI{}, Config{Duration: time.Second}))
+}
+
+func TestPercentile(t *testing.T) {
+	latencies := make([]time.Duration, 100)
+	for i := range latencies {
+		latencies[i] = time.Duration(i+1) * time.Millisecond
+	}
+	assert.Equal(t, 50*time.Millisecond, percentile(latencies, 50))
+	assert.Equal(t, 99*time.Millisecond, percentile(latencies, 99))
+	assert.Equal(t, 100*time.Millisecond, percentile(latencies, 100))
+	assert.Zero(t, percentile(nil, 50))
+}
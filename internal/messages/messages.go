@@ -0,0 +1,174 @@
+// Package messages mantém o catálogo das mensagens exibidas aos usuários nas
+// falhas de validação, indexadas pelo código da regra (a tag de validação ou
+// um código de domínio) e, opcionalmente, pelo campo.
+//
+// Os textos existem em todos os idiomas suportados; o idioma de cada
+// requisição é negociado pelo header Accept-Language e propagado pelo
+// context.Context (ver WithLocale). Os testes devem comparar os códigos das
+// regras, não os textos, que podem mudar livremente no catálogo.
+package messages
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Idiomas suportados
+const (
+	PtBR          = "pt-BR"
+	En            = "en"
+	DefaultLocale = PtBR
+)
+
+// Códigos de domínio, para as regras que não vêm das tags de validação
+const (
+	InvalidFormat    = "json"
+	UnknownEventType = "unknown_event_type"
+	UnknownChannel   = "unknown_channel"
+	EgressDenied     = "egress_denied"
+	UnknownField     = "unknown_field"
+)
+
+// Key identifica uma mensagem: o código da regra e o campo ("" vale para
+// qualquer campo sem mensagem específica)
+type Key struct {
+	Code  string
+	Field string
+}
+
+// catalog contém as mensagens de cada idioma. Os argumentos seguem a ordem
+// dos parâmetros da regra
+var catalog = map[string]map[Key]string{
+	PtBR: {
+		{"required", ""}:              "Campo obrigatório",
+		{"required", "webhook_url"}:   "URL de webhook é obrigatória para o canal webhook",
+		{"min", ""}:                   "Deve ter pelo menos %s caracteres",
+		{"min", "password"}:           "Senha deve ter pelo menos %s caracteres",
+		{"max", ""}:                   "Deve ter no máximo %s caracteres",
+		{"valid_email", ""}:           "Email inválido",
+		{"email", ""}:                 "Email inválido",
+		{"rfc3339", ""}:               "Data inválida, use o formato RFC3339 (ex.: 2023-05-22T14:56:32Z)",
+		{"not_blank", ""}:             "Não pode conter apenas espaços",
+		{"url", ""}:                   "URL inválida",
+		{"oneof", ""}:                 "Deve ser um destes valores: %s",
+		{"required_without", ""}:      "Campo obrigatório quando %s não é informado",
+		{"excluded_with", ""}:         "Não pode ser informado junto com %s",
+		{"phone", ""}:                 "Telefone inválido, use o formato E.164 (ex.: +5511999998888)",
+		{"locale", ""}:                "Idioma inválido, use uma tag BCP 47 (ex.: pt-BR)",
+		{"tz", ""}:                    "Fuso horário inválido, use um nome IANA (ex.: America/Sao_Paulo)",
+		{InvalidFormat, ""}:           "Formato de dados inválido",
+		{UnknownEventType, ""}:        "Tipo de evento desconhecido",
+		{UnknownChannel, ""}:          "Canal desconhecido: %s",
+		{EgressDenied, "webhook_url"}: "Destino do webhook não permitido pela política de saída",
+		{UnknownField, ""}:            "Campo não reconhecido",
+		{"", ""}:                      "Valor inválido",
+	},
+	En: {
+		{"required", ""}:              "Required field",
+		{"required", "webhook_url"}:   "A webhook URL is required for the webhook channel",
+		{"min", ""}:                   "Must have at least %s characters",
+		{"min", "password"}:           "Password must have at least %s characters",
+		{"max", ""}:                   "Must have at most %s characters",
+		{"valid_email", ""}:           "Invalid email",
+		{"email", ""}:                 "Invalid email",
+		{"rfc3339", ""}:               "Invalid date, use the RFC3339 format (e.g. 2023-05-22T14:56:32Z)",
+		{"not_blank", ""}:             "Must not contain only spaces",
+		{"url", ""}:                   "Invalid URL",
+		{"oneof", ""}:                 "Must be one of: %s",
+		{"required_without", ""}:      "Required when %s is not provided",
+		{"excluded_with", ""}:         "Must not be provided together with %s",
+		{"phone", ""}:                 "Invalid phone number, use the E.164 format (e.g. +5511999998888)",
+		{"locale", ""}:                "Invalid language, use a BCP 47 tag (e.g. en-US)",
+		{"tz", ""}:                    "Invalid time zone, use an IANA name (e.g. America/Sao_Paulo)",
+		{InvalidFormat, ""}:           "Invalid data format",
+		{UnknownEventType, ""}:        "Unknown event type",
+		{UnknownChannel, ""}:          "Unknown channel: %s",
+		{EgressDenied, "webhook_url"}: "Webhook destination not allowed by the egress policy",
+		{UnknownField, ""}:            "Unknown field",
+		{"", ""}:                      "Invalid value",
+	},
+}
+
+// Text retorna a mensagem do código para o campo no idioma, preferindo a
+// mensagem específica do campo. Idiomas não suportados usam o padrão e
+// códigos desconhecidos usam a mensagem genérica
+func Text(locale, code, field string, args ...interface{}) string {
+	messages, ok := catalog[locale]
+	if !ok {
+		messages = catalog[DefaultLocale]
+	}
+
+	format, ok := messages[Key{code, field}]
+	if !ok {
+		format, ok = messages[Key{code, ""}]
+	}
+	if !ok {
+		return messages[Key{}]
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// Locales lista os idiomas suportados, começando pelo padrão
+func Locales() []string {
+	return []string{PtBR, En}
+}
+
+// Negotiate escolhe o idioma a partir do header Accept-Language, respeitando
+// os pesos (q) e aceitando o idioma base ("en-US" usa "en", "pt" usa "pt-BR")
+func Negotiate(acceptLanguage string) string {
+	best, bestWeight := DefaultLocale, 0.0
+	for _, entry := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(entry), ";")
+		weight := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			weight = parsed
+		}
+
+		locale := match(tag)
+		if locale != "" && weight > bestWeight {
+			best, bestWeight = locale, weight
+		}
+	}
+	return best
+}
+
+// match retorna o idioma suportado correspondente à tag ("" se nenhum)
+func match(tag string) string {
+	base, _, _ := strings.Cut(tag, "-")
+	for _, locale := range Locales() {
+		if strings.EqualFold(tag, locale) {
+			return locale
+		}
+	}
+	for _, locale := range Locales() {
+		localeBase, _, _ := strings.Cut(locale, "-")
+		if strings.EqualFold(base, localeBase) {
+			return locale
+		}
+	}
+	return ""
+}
+
+type localeKey struct{}
+
+// WithLocale retorna um contexto com o idioma das mensagens
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// Locale retorna o idioma do contexto (o padrão se não houver)
+func Locale(ctx context.Context) string {
+	if locale, ok := ctx.Value(localeKey{}).(string); ok {
+		return locale
+	}
+	return DefaultLocale
+}
@@ -0,0 +1,51 @@
+package messages
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCatalogComplete(t *testing.T) {
+	// Todos os idiomas traduzem as mesmas mensagens
+	for _, locale := range Locales() {
+		assert.Len(t, catalog[locale], len(catalog[DefaultLocale]), locale)
+		for key := range catalog[DefaultLocale] {
+			_, ok := catalog[locale][key]
+			assert.True(t, ok, "%s: %v", locale, key)
+		}
+	}
+}
+
+func TestText(t *testing.T) {
+	assert.Equal(t, "Campo obrigatório", Text(PtBR, "required", "name"))
+	assert.Equal(t, "Required field", Text(En, "required", "name"))
+
+	// Mensagens específicas do campo têm precedência
+	assert.Equal(t, "Password must have at least 6 characters", Text(En, "min", "password", "6"))
+	assert.Equal(t, "Must have at least 3 characters", Text(En, "min", "name", "3"))
+
+	// Idioma e código desconhecidos
+	assert.Equal(t, "Campo obrigatório", Text("fr", "required", "name"))
+	assert.Equal(t, "Invalid value", Text(En, "unknown", "name"))
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := map[string]string{
+		"":                       PtBR,
+		"en":                     En,
+		"en-US,en;q=0.9":         En,
+		"pt":                     PtBR,
+		"fr-FR, en;q=0.5":        En,
+		"en;q=0.4, pt-BR;q=0.8":  PtBR,
+		"fr, de":                 PtBR,
+		"en;q=invalid, pt;q=0.1": PtBR,
+	}
+	for header, expected := range tests {
+		assert.Equal(t, expected, Negotiate(header), header)
+	}
+
+	assert.Equal(t, DefaultLocale, Locale(context.Background()))
+	assert.Equal(t, En, Locale(WithLocale(context.Background(), En)))
+}
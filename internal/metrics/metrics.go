@@ -0,0 +1,125 @@
+// Package metrics acumula em memória a latência e a taxa de erros das
+// operações internas (como as chamadas aos repositórios) para o painel de
+// operações. O Profiler mede também, por amostragem, as alocações de memória
+// das operações dos serviços.
+package metrics
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"callable-api/internal/models"
+)
+
+// operationKey identifica uma operação de um componente
+type operationKey struct {
+	component string
+	operation string
+}
+
+// counters acumula as chamadas de uma operação
+type counters struct {
+	calls        int64
+	errors       int64
+	totalLatency time.Duration
+	maxLatency   time.Duration
+
+	// Alocações medidas nas chamadas amostradas (ver Profiler)
+	allocSamples int64
+	allocs       uint64
+	allocBytes   uint64
+}
+
+// Registry acumula as estatísticas de cada operação desde o início do processo
+type Registry struct {
+	mutex      sync.Mutex
+	operations map[operationKey]*counters
+}
+
+// NewRegistry cria um Registry vazio
+func NewRegistry() *Registry {
+	return &Registry{operations: make(map[operationKey]*counters)}
+}
+
+// Observe registra uma chamada concluída. failed indica uma falha inesperada
+// (erros esperados, como registros inexistentes, não devem contar)
+func (r *Registry) Observe(component, operation string, latency time.Duration, failed bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	c := r.counters(component, operation)
+	c.calls++
+	c.totalLatency += latency
+	if latency > c.maxLatency {
+		c.maxLatency = latency
+	}
+	if failed {
+		c.errors++
+	}
+}
+
+// ObserveAllocations registra as alocações de memória de uma chamada
+// amostrada. A chamada em si é registrada por Observe
+func (r *Registry) ObserveAllocations(component, operation string, allocs, bytes uint64) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	c := r.counters(component, operation)
+	c.allocSamples++
+	c.allocs += allocs
+	c.allocBytes += bytes
+}
+
+// counters retorna os contadores da operação, criando-os na primeira chamada.
+// Exige o mutex
+func (r *Registry) counters(component, operation string) *counters {
+	key := operationKey{component: component, operation: operation}
+	c, exists := r.operations[key]
+	if !exists {
+		c = &counters{}
+		r.operations[key] = c
+	}
+	return c
+}
+
+// Snapshot retorna as estatísticas de todas as operações, ordenadas por
+// componente e operação
+func (r *Registry) Snapshot() []models.OperationStats {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	result := make([]models.OperationStats, 0, len(r.operations))
+	for key, c := range r.operations {
+		if c.calls == 0 {
+			continue
+		}
+		stats := models.OperationStats{
+			Component:    key.component,
+			Operation:    key.operation,
+			Calls:        c.calls,
+			Errors:       c.errors,
+			ErrorRate:    float64(c.errors) / float64(c.calls),
+			AvgLatencyMs: milliseconds(c.totalLatency) / float64(c.calls),
+			MaxLatencyMs: milliseconds(c.maxLatency),
+		}
+		if c.allocSamples > 0 {
+			stats.AllocSamples = c.allocSamples
+			stats.AvgAllocs = float64(c.allocs) / float64(c.allocSamples)
+			stats.AvgAllocBytes = float64(c.allocBytes) / float64(c.allocSamples)
+		}
+		result = append(result, stats)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Component != result[j].Component {
+			return result[i].Component < result[j].Component
+		}
+		return result[i].Operation < result[j].Operation
+	})
+	return result
+}
+
+// milliseconds converte a duração em milissegundos fracionários
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
@@ -0,0 +1,127 @@
+package metrics
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry()
+	registry.Observe("items", "FindByID", 2*time.Millisecond, false)
+	registry.Observe("items", "FindByID", 4*time.Millisecond, true)
+	registry.Observe("items", "Create", time.Millisecond, false)
+	registry.Observe("comments", "Create", time.Millisecond, false)
+
+	snapshot := registry.Snapshot()
+	assert.Len(t, snapshot, 3)
+	assert.Equal(t, "comments", snapshot[0].Component)
+	assert.Equal(t, "Create", snapshot[1].Operation)
+
+	findByID := snapshot[2]
+	assert.Equal(t, int64(2), findByID.Calls)
+	assert.Equal(t, int64(1), findByID.Errors)
+	assert.InDelta(t, 0.5, findByID.ErrorRate, 0.0001)
+	assert.InDelta(t, 3.0, findByID.AvgLatencyMs, 0.0001)
+	assert.InDelta(t, 4.0, findByID.MaxLatencyMs, 0.0001)
+}
+
+func TestRegistry_Allocations(t *testing.T) {
+	registry := NewRegistry()
+	registry.Observe("items", "GetItems", time.Millisecond, false)
+	registry.Observe("items", "GetItems", time.Millisecond, false)
+	registry.ObserveAllocations("items", "GetItems", 100, 4096)
+	registry.Observe("items", "CreateItem", time.Millisecond, false)
+
+	snapshot := registry.Snapshot()
+	assert.Len(t, snapshot, 2)
+
+	// Operações sem amostras não informam alocações
+	assert.Zero(t, snapshot[0].AllocSamples)
+
+	getItems := snapshot[1]
+	assert.Equal(t, int64(2), getItems.Calls)
+	assert.Equal(t, int64(1), getItems.AllocSamples)
+	assert.InDelta(t, 100.0, getItems.AvgAllocs, 0.0001)
+	assert.InDelta(t, 4096.0, getItems.AvgAllocBytes, 0.0001)
+}
+
+func TestProfiler(t *testing.T) {
+	registry := NewRegistry()
+	profiler := NewProfiler(registry, 2)
+
+	var sink [][]byte
+	for i := 0; i < 4; i++ {
+		done := profiler.Start("items", "CreateItem")
+		sink = append(sink, make([]byte, 1<<16))
+		done(i == 3)
+	}
+	assert.Len(t, sink, 4)
+
+	// Uma a cada duas chamadas tem as alocações medidas
+	stats := registry.Snapshot()[0]
+	assert.Equal(t, int64(4), stats.Calls)
+	assert.Equal(t, int64(1), stats.Errors)
+	assert.Equal(t, int64(2), stats.AllocSamples)
+	assert.GreaterOrEqual(t, stats.AvgAllocBytes, float64(1<<16))
+
+	// Sem amostragem, apenas a latência é registrada
+	registry = NewRegistry()
+	NewProfiler(registry, 0).Start("items", "GetItems")(false)
+	assert.Zero(t, registry.Snapshot()[0].AllocSamples)
+}
+
+type fakeJobCounter map[string]int
+
+func (f fakeJobCounter) CountByState(ctx context.Context) (map[string]int, error) {
+	return f, nil
+}
+
+func TestExporter(t *testing.T) {
+	registry := NewRegistry()
+	registry.Observe("items", "FindByID", 2*time.Millisecond, false)
+	registry.Observe("items", "FindByID", 4*time.Millisecond, true)
+
+	var out strings.Builder
+	err := NewExporter().
+		WithRegistry("repository", registry).
+		WithJobCounter(fakeJobCounter{"pending": 3, "running": 1}).
+		Write(context.Background(), &out)
+	assert.NoError(t, err)
+
+	labels := `{layer="repository",component="items",operation="FindByID"}`
+	assert.Contains(t, out.String(), "# TYPE "+MetricOperationCalls+" counter\n")
+	assert.Contains(t, out.String(), MetricOperationCalls+labels+" 2\n")
+	assert.Contains(t, out.String(), MetricOperationErrors+labels+" 1\n")
+	assert.Contains(t, out.String(), MetricOperationLatencySum+labels+" 0.006\n")
+	assert.Contains(t, out.String(), MetricOperationLatencyMax+labels+" 0.004\n")
+	assert.Contains(t, out.String(), MetricJobs+`{state="pending"} 3`+"\n")
+}
+
+func TestRules(t *testing.T) {
+	var out strings.Builder
+	assert.NoError(t, WriteRules(&out, Rules(DefaultRulesConfig())))
+	assert.Contains(t, out.String(), "      - alert: CallableAPIOperationLatencySLO\n")
+	assert.Contains(t, out.String(), `        expr: "callable_api:operation_latency_seconds:avg > 0.25"`)
+	assert.Contains(t, out.String(), "        for: 10m\n")
+	assert.Contains(t, out.String(), `[5m]`)
+
+	// As regras usam apenas métricas expostas pelo Exporter ou gravadas por outras regras
+	exported := regexp.MustCompile(`callable_api[a-z_:]*`)
+	known := map[string]bool{
+		MetricOperationCalls: true, MetricOperationErrors: true, MetricOperationLatencySum: true,
+		MetricOperationLatencyMax: true, MetricJobs: true,
+		RecordOperationLatency: true, RecordOperationErrorRatio: true,
+	}
+	for _, group := range Rules(DefaultRulesConfig()) {
+		for _, rule := range group.Rules {
+			for _, name := range exported.FindAllString(rule.Expr, -1) {
+				assert.True(t, known[name], name)
+			}
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"runtime"
+	"sync/atomic"
+	"time"
+)
+
+// Profiler mede a latência das operações dos serviços e, por amostragem, as
+// alocações de memória de cada chamada. As alocações vêm de
+// runtime.ReadMemStats, que pausa brevemente o processo, por isso só uma a
+// cada sampleEvery chamadas é medida. Os números são aproximados: a contagem
+// inclui o que outras goroutines alocaram durante a chamada amostrada
+type Profiler struct {
+	registry    *Registry
+	sampleEvery int64
+	calls       atomic.Int64
+}
+
+// NewProfiler cria um Profiler que registra as operações no registry.
+// sampleEvery 0 mede apenas a latência; 1 mede as alocações de todas as chamadas
+func NewProfiler(registry *Registry, sampleEvery int) *Profiler {
+	if sampleEvery < 0 {
+		sampleEvery = 0
+	}
+	return &Profiler{registry: registry, sampleEvery: int64(sampleEvery)}
+}
+
+// Start marca o início de uma operação. A função retornada registra o
+// resultado; failed indica uma falha inesperada, como em Registry.Observe
+func (p *Profiler) Start(component, operation string) func(failed bool) {
+	sampled := p.sampleEvery > 0 && p.calls.Add(1)%p.sampleEvery == 0
+
+	var mallocs, bytes uint64
+	if sampled {
+		mallocs, bytes = ReadAllocations()
+	}
+	start := time.Now()
+
+	return func(failed bool) {
+		latency := time.Since(start)
+		p.registry.Observe(component, operation, latency, failed)
+		if !sampled {
+			return
+		}
+
+		afterMallocs, afterBytes := ReadAllocations()
+		p.registry.ObserveAllocations(component, operation, afterMallocs-mallocs, afterBytes-bytes)
+	}
+}
+
+// ReadAllocations retorna o total de alocações e de bytes alocados desde o
+// início do processo (pausa brevemente o processo)
+func ReadAllocations() (mallocs, bytes uint64) {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.Mallocs, stats.TotalAlloc
+}
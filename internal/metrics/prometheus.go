@@ -0,0 +1,143 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Nomes das métricas expostas no formato do Prometheus. As regras de alerta
+// (ver Rules) usam as mesmas constantes, para que não fiquem desatualizadas
+const (
+	MetricOperationCalls      = "callable_api_operation_calls_total"
+	MetricOperationErrors     = "callable_api_operation_errors_total"
+	MetricOperationLatencySum = "callable_api_operation_latency_seconds_sum"
+	MetricOperationLatencyMax = "callable_api_operation_latency_seconds_max"
+	MetricJobs                = "callable_api_jobs"
+)
+
+// PrometheusContentType é o tipo de mídia do formato de texto do Prometheus
+const PrometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// JobCounter informa quantos jobs existem em cada estado
+type JobCounter interface {
+	CountByState(ctx context.Context) (map[string]int, error)
+}
+
+// layerRegistry associa um Registry à camada que ele mede (ex.: repository)
+type layerRegistry struct {
+	layer    string
+	registry *Registry
+}
+
+// Exporter expõe as estatísticas dos Registry e a fila de jobs no formato de
+// texto do Prometheus
+type Exporter struct {
+	layers []layerRegistry
+	jobs   JobCounter
+}
+
+// NewExporter cria um Exporter sem fontes
+func NewExporter() *Exporter {
+	return &Exporter{}
+}
+
+// WithRegistry inclui as operações do registry, identificadas pelo label layer
+func (e *Exporter) WithRegistry(layer string, registry *Registry) *Exporter {
+	e.layers = append(e.layers, layerRegistry{layer: layer, registry: registry})
+	return e
+}
+
+// WithJobCounter inclui a quantidade de jobs em cada estado
+func (e *Exporter) WithJobCounter(jobs JobCounter) *Exporter {
+	e.jobs = jobs
+	return e
+}
+
+// operationSample é o valor dos contadores de uma operação em uma camada
+type operationSample struct {
+	labels   string
+	counters counters
+}
+
+// Write escreve as métricas no formato de texto do Prometheus
+func (e *Exporter) Write(ctx context.Context, w io.Writer) error {
+	var samples []operationSample
+	for _, source := range e.layers {
+		for key, c := range source.registry.counterSnapshot() {
+			samples = append(samples, operationSample{
+				labels:   formatLabels("layer", source.layer, "component", key.component, "operation", key.operation),
+				counters: c,
+			})
+		}
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i].labels < samples[j].labels })
+
+	var b strings.Builder
+	writeFamily(&b, MetricOperationCalls, "counter", "Chamadas às operações internas", samples, func(c counters) float64 {
+		return float64(c.calls)
+	})
+	writeFamily(&b, MetricOperationErrors, "counter", "Chamadas às operações internas com falha inesperada", samples, func(c counters) float64 {
+		return float64(c.errors)
+	})
+	writeFamily(&b, MetricOperationLatencySum, "counter", "Soma da latência das operações internas, em segundos", samples, func(c counters) float64 {
+		return c.totalLatency.Seconds()
+	})
+	writeFamily(&b, MetricOperationLatencyMax, "gauge", "Maior latência observada de cada operação interna, em segundos", samples, func(c counters) float64 {
+		return c.maxLatency.Seconds()
+	})
+
+	if e.jobs != nil {
+		byState, err := e.jobs.CountByState(ctx)
+		if err != nil {
+			return fmt.Errorf("contagem de jobs: %w", err)
+		}
+		states := make([]string, 0, len(byState))
+		for state := range byState {
+			states = append(states, state)
+		}
+		sort.Strings(states)
+
+		fmt.Fprintf(&b, "# HELP %s Jobs em cada estado\n# TYPE %s gauge\n", MetricJobs, MetricJobs)
+		for _, state := range states {
+			fmt.Fprintf(&b, "%s%s %d\n", MetricJobs, formatLabels("state", state), byState[state])
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeFamily escreve uma família de métricas das operações
+func writeFamily(b *strings.Builder, name, kind, help string, samples []operationSample, value func(c counters) float64) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+	for _, sample := range samples {
+		fmt.Fprintf(b, "%s%s %g\n", name, sample.labels, value(sample.counters))
+	}
+}
+
+// formatLabels formata os pares nome/valor como labels do Prometheus
+func formatLabels(pairs ...string) string {
+	escaper := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	parts := make([]string, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		parts = append(parts, pairs[i]+`="`+escaper.Replace(pairs[i+1])+`"`)
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+// counterSnapshot copia os contadores de todas as operações com chamadas
+func (r *Registry) counterSnapshot() map[operationKey]counters {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	result := make(map[operationKey]counters, len(r.operations))
+	for key, c := range r.operations {
+		if c.calls > 0 {
+			result[key] = *c
+		}
+	}
+	return result
+}
@@ -0,0 +1,196 @@
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"callable-api/internal/models"
+)
+
+// Nomes das regras de gravação, usados também pelos alertas
+const (
+	RecordOperationLatency    = "callable_api:operation_latency_seconds:avg"
+	RecordOperationErrorRatio = "callable_api:operation_error_ratio"
+)
+
+// RulesConfig define os limites das regras de alerta recomendadas
+type RulesConfig struct {
+	// LatencySLO é a latência média máxima de cada operação interna
+	LatencySLO time.Duration
+
+	// ErrorRate é a fração máxima de chamadas com falha de cada operação
+	ErrorRate float64
+
+	// JobBacklog é a quantidade máxima de jobs aguardando execução
+	JobBacklog int
+
+	// Window é a janela das taxas calculadas pelas regras de gravação
+	Window time.Duration
+
+	// For é por quanto tempo a condição precisa persistir para o alerta disparar
+	For time.Duration
+}
+
+// DefaultRulesConfig retorna os limites recomendados
+func DefaultRulesConfig() RulesConfig {
+	return RulesConfig{
+		LatencySLO: 250 * time.Millisecond,
+		ErrorRate:  0.05,
+		JobBacklog: 100,
+		Window:     5 * time.Minute,
+		For:        10 * time.Minute,
+	}
+}
+
+// Rule é uma regra de gravação (Record) ou de alerta (Alert) do Prometheus
+type Rule struct {
+	Record      string
+	Alert       string
+	Expr        string
+	For         time.Duration
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+// RuleGroup é um grupo de regras avaliadas em conjunto
+type RuleGroup struct {
+	Name  string
+	Rules []Rule
+}
+
+// Rules gera as regras recomendadas a partir das métricas expostas pelo
+// Exporter: latência e taxa de erros das operações internas e fila de jobs
+func Rules(cfg RulesConfig) []RuleGroup {
+	window := promDuration(cfg.Window)
+	byOperation := "sum by (layer, component, operation)"
+
+	return []RuleGroup{
+		{
+			Name: "callable-api-operations",
+			Rules: []Rule{
+				{
+					Record: RecordOperationLatency,
+					Expr: fmt.Sprintf("%s (rate(%s[%s])) / %s (rate(%s[%s]))",
+						byOperation, MetricOperationLatencySum, window, byOperation, MetricOperationCalls, window),
+				},
+				{
+					Record: RecordOperationErrorRatio,
+					Expr: fmt.Sprintf("%s (rate(%s[%s])) / %s (rate(%s[%s]))",
+						byOperation, MetricOperationErrors, window, byOperation, MetricOperationCalls, window),
+				},
+				{
+					Alert:  "CallableAPIOperationLatencySLO",
+					Expr:   fmt.Sprintf("%s > %s", RecordOperationLatency, formatFloat(cfg.LatencySLO.Seconds())),
+					For:    cfg.For,
+					Labels: map[string]string{"severity": "warning"},
+					Annotations: map[string]string{
+						"summary":     "Latência acima do SLO em {{ $labels.layer }}/{{ $labels.component }}.{{ $labels.operation }}",
+						"description": fmt.Sprintf("A latência média da operação é {{ $value | humanizeDuration }}; o SLO é %s.", cfg.LatencySLO),
+					},
+				},
+				{
+					Alert:  "CallableAPIOperationErrorRate",
+					Expr:   fmt.Sprintf("%s > %s", RecordOperationErrorRatio, formatFloat(cfg.ErrorRate)),
+					For:    cfg.For,
+					Labels: map[string]string{"severity": "critical"},
+					Annotations: map[string]string{
+						"summary":     "Taxa de erros alta em {{ $labels.layer }}/{{ $labels.component }}.{{ $labels.operation }}",
+						"description": fmt.Sprintf("{{ $value | humanizePercentage }} das chamadas falharam; o limite é %s.", formatFloat(cfg.ErrorRate*100)+"%"),
+					},
+				},
+			},
+		},
+		{
+			Name: "callable-api-jobs",
+			Rules: []Rule{
+				{
+					Alert:  "CallableAPIJobBacklog",
+					Expr:   fmt.Sprintf(`sum(%s{state="%s"}) > %d`, MetricJobs, models.JobStatePending, cfg.JobBacklog),
+					For:    cfg.For,
+					Labels: map[string]string{"severity": "warning"},
+					Annotations: map[string]string{
+						"summary":     "Fila de jobs acumulada",
+						"description": fmt.Sprintf("{{ $value }} jobs aguardam execução; o limite é %d.", cfg.JobBacklog),
+					},
+				},
+			},
+		},
+	}
+}
+
+// WriteRules escreve os grupos no formato YAML dos arquivos de regras do
+// Prometheus (rule_files). Os textos são escritos entre aspas duplas, com o
+// escape do JSON, que é válido em YAML
+func WriteRules(w io.Writer, groups []RuleGroup) error {
+	var b strings.Builder
+	b.WriteString("# Regras geradas por `api metrics rules`; as métricas vêm de GET /metrics\n")
+	b.WriteString("groups:\n")
+	for _, group := range groups {
+		fmt.Fprintf(&b, "  - name: %s\n    rules:\n", quote(group.Name))
+		for _, rule := range group.Rules {
+			if rule.Record != "" {
+				fmt.Fprintf(&b, "      - record: %s\n", rule.Record)
+			} else {
+				fmt.Fprintf(&b, "      - alert: %s\n", rule.Alert)
+			}
+			fmt.Fprintf(&b, "        expr: %s\n", quote(rule.Expr))
+			if rule.For > 0 {
+				fmt.Fprintf(&b, "        for: %s\n", promDuration(rule.For))
+			}
+			writeMap(&b, "labels", rule.Labels)
+			writeMap(&b, "annotations", rule.Annotations)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeMap escreve um mapa YAML com as chaves ordenadas
+func writeMap(b *strings.Builder, name string, values map[string]string) {
+	if len(values) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fmt.Fprintf(b, "        %s:\n", name)
+	for _, key := range keys {
+		fmt.Fprintf(b, "          %s: %s\n", key, quote(values[key]))
+	}
+}
+
+// quote escreve o texto como string YAML entre aspas duplas
+func quote(value string) string {
+	var b strings.Builder
+	encoder := json.NewEncoder(&b)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(value)
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// formatFloat formata o número sem expoente nem zeros à direita
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// promDuration formata a duração na notação do Prometheus (ex.: 5m, 30s)
+func promDuration(d time.Duration) string {
+	switch {
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	case d%time.Second == 0:
+		return fmt.Sprintf("%ds", d/time.Second)
+	}
+	return fmt.Sprintf("%dms", d.Milliseconds())
+}
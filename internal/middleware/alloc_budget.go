@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"callable-api/internal/correlation"
+	"callable-api/internal/stats"
+	"callable-api/pkg/logger"
+)
+
+// AllocationBudgetMiddleware mede, por amostragem, a memória alocada pelas
+// requisições e registra em log as que excedem o orçamento (experimental).
+// As rotas acima do orçamento aparecem no painel de operações. Sem orçamento
+// configurado, o middleware apenas repassa a requisição
+func AllocationBudgetMiddleware(budget *stats.AllocationBudget) gin.HandlerFunc {
+	if !budget.Enabled() {
+		return passThrough
+	}
+
+	return func(c *gin.Context) {
+		done := budget.Start()
+		if done == nil {
+			c.Next()
+			return
+		}
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = c.Request.URL.Path
+		}
+		route = c.Request.Method + " " + route
+		if bytes, exceeded := done(route); exceeded {
+			logger.Warn("Requisição excedeu o orçamento de alocações", correlation.Fields(c.Request.Context(), map[string]interface{}{
+				"route":  route,
+				"bytes":  bytes,
+				"budget": budget.Budget(),
+			}))
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"callable-api/internal/chaos"
+	"callable-api/internal/errcodes"
+	"callable-api/internal/mode"
+	"callable-api/internal/models"
+	"callable-api/pkg/logger"
+)
+
+// ChaosMiddleware injeta as falhas configuradas (latência, erros ou conexões
+// derrubadas) em parte das requisições. Nunca é ativado no modo real (ver
+// chaos.Config.Allowed): nesse caso, ou sem configuração, o middleware apenas
+// repassa a requisição
+func ChaosMiddleware(cfg chaos.Config) gin.HandlerFunc {
+	if !cfg.Enabled || len(cfg.Rules) == 0 {
+		return passThrough
+	}
+	if err := cfg.Allowed(mode.Current()); err != nil {
+		logger.Warn("Injeção de falhas recusada", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return passThrough
+	}
+
+	logger.Warn("Injeção de falhas ativada", map[string]interface{}{
+		"rules": len(cfg.Rules),
+	})
+
+	injector := chaos.NewInjector(cfg.Rules)
+	return func(c *gin.Context) {
+		rule := injector.Pick(c.Request.Method, c.FullPath())
+		if rule == nil {
+			c.Next()
+			return
+		}
+
+		c.Header("X-Chaos-Fault", rule.Fault)
+		switch rule.Fault {
+		case chaos.FaultLatency:
+			select {
+			case <-time.After(rule.Latency):
+			case <-c.Request.Context().Done():
+			}
+			c.Next()
+		case chaos.FaultError:
+			c.AbortWithStatusJSON(rule.StatusCode, models.APIError{
+				Code:      rule.StatusCode,
+				Status:    "error",
+				ErrorCode: errcodes.InjectedFault,
+				Message:   "Injected fault",
+			})
+		case chaos.FaultDrop:
+			dropConnection(c)
+		default:
+			c.Next()
+		}
+	}
+}
+
+// passThrough é o handler usado quando a injeção de falhas está desativada
+func passThrough(c *gin.Context) {
+	c.Next()
+}
+
+// dropConnection encerra a conexão TCP sem resposta. Se a conexão não puder
+// ser assumida (ex.: HTTP/2), responde 503 sem corpo
+func dropConnection(c *gin.Context) {
+	c.Abort()
+
+	// O Hijack do Gin assume que o writer original suporta a operação
+	if unwrapper, ok := c.Writer.(interface{ Unwrap() http.ResponseWriter }); ok {
+		if _, ok := unwrapper.Unwrap().(http.Hijacker); ok {
+			if conn, _, err := c.Writer.Hijack(); err == nil {
+				conn.Close()
+				return
+			}
+		}
+	}
+	c.Writer.WriteHeader(http.StatusServiceUnavailable)
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+
+	"callable-api/internal/errcodes"
+	"callable-api/internal/repository"
+)
+
+// ConsentChecker retorna um erro se o usuário não aceitou as versões
+// obrigatórias dos termos de uso e da política de privacidade
+type ConsentChecker func(ctx context.Context, userID string) error
+
+// ConsentMiddleware bloqueia os usuários autenticados que não aceitaram as
+// versões obrigatórias dos documentos, exceto nas rotas exempt (as que
+// consultam e registram o aceite). Deve rodar após a autenticação;
+// requisições anônimas seguem sem alteração
+func ConsentMiddleware(check ConsentChecker, exempt ...string) gin.HandlerFunc {
+	skip := make(map[string]bool, len(exempt))
+	for _, path := range exempt {
+		skip[path] = true
+	}
+
+	return func(c *gin.Context) {
+		userID := c.GetString("userID")
+		if userID == "" || skip[c.FullPath()] {
+			c.Next()
+			return
+		}
+
+		if err := check(c.Request.Context(), userID); err != nil {
+			errcodes.Respond(c, err, errcodes.WhenCause(repository.ErrConsentRequired, errcodes.ConsentRequired))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,147 @@
+package middleware
+
+import (
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"callable-api/internal/models"
+)
+
+// corsAllowedHeaders são os headers que o navegador pode enviar nas
+// requisições de outras origens
+var corsAllowedHeaders = strings.Join([]string{
+	"Accept", "Accept-Language", "Authorization", "Cache-Control", "Content-Type",
+	"If-Modified-Since", "If-None-Match", "Prefer",
+	"X-Requested-With", models.DeviceIDHeader, models.RequestIDHeader, models.OrganizationHeader,
+}, ", ")
+
+// corsExposedHeaders são os headers da resposta que o navegador deixa o
+// JavaScript da outra origem ler
+var corsExposedHeaders = strings.Join([]string{
+	"ETag", "Last-Modified", "Link", "Location", "Retry-After",
+	"X-Total-Count", "X-Page", "X-Page-Size", models.RequestIDHeader,
+	"X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", RequestCostHeader,
+}, ", ")
+
+// CORSConfig define a política CORS de um grupo de rotas
+type CORSConfig struct {
+	// AllowedOrigins são os padrões das origens aceitas. Padrões sem esquema
+	// valem para o host[:porta] da origem (ex.: "localhost:*",
+	// "*.example.com"); com esquema, para a origem inteira (ex.:
+	// "https://app.example.com"). "*" aceita qualquer origem. Sem padrões,
+	// nenhuma requisição de outra origem é aceita
+	AllowedOrigins []string
+
+	// AllowCredentials permite cookies e o header Authorization. Com "*", as
+	// credenciais nunca são permitidas
+	AllowCredentials bool
+
+	// MaxAge é por quanto tempo o navegador guarda a resposta do preflight
+	MaxAge time.Duration
+}
+
+// DefaultCORSConfig retorna a política padrão: apenas acesso local, com
+// credenciais
+func DefaultCORSConfig() CORSConfig {
+	return CORSConfig{
+		AllowedOrigins:   []string{"localhost:*", "127.0.0.1:*"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	}
+}
+
+// Allows indica se a política aceita a origem
+func (c CORSConfig) Allows(origin string) bool {
+	host := origin
+	if i := strings.Index(origin, "://"); i >= 0 {
+		host = origin[i+3:]
+	}
+
+	for _, pattern := range c.AllowedOrigins {
+		if pattern == "*" {
+			return true
+		}
+		target := host
+		if strings.Contains(pattern, "://") {
+			target = origin
+		}
+		if matched, _ := path.Match(pattern, target); matched {
+			return true
+		}
+		// "localhost:*" também aceita a origem sem porta
+		if base, ok := strings.CutSuffix(pattern, ":*"); ok && base == target {
+			return true
+		}
+	}
+	return false
+}
+
+// anyOrigin indica se a política aceita qualquer origem
+func (c CORSConfig) anyOrigin() bool {
+	for _, pattern := range c.AllowedOrigins {
+		if pattern == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// CORS aplica a política CORS às rotas em que é montado. Requisições de
+// origens aceitas recebem os headers Access-Control-*; os preflights (OPTIONS
+// com Access-Control-Request-Method) são respondidos aqui com 204, ou 403 se
+// a origem não for aceita. Requisições sem Origin seguem sem alteração
+func CORS(cfg CORSConfig) gin.HandlerFunc {
+	credentials := cfg.AllowCredentials && !cfg.anyOrigin()
+	maxAge := strconv.Itoa(int(cfg.MaxAge.Seconds()))
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
+
+		header := c.Writer.Header()
+		header.Add("Vary", "Origin")
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+
+		if !cfg.Allows(origin) {
+			if preflight {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			// O navegador bloqueia a leitura da resposta sem os headers CORS
+			c.Next()
+			return
+		}
+
+		if credentials {
+			header.Set("Access-Control-Allow-Origin", origin)
+			header.Set("Access-Control-Allow-Credentials", "true")
+		} else if cfg.anyOrigin() {
+			header.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			header.Set("Access-Control-Allow-Origin", origin)
+		}
+
+		if !preflight {
+			header.Set("Access-Control-Expose-Headers", corsExposedHeaders)
+			c.Next()
+			return
+		}
+
+		header.Add("Vary", "Access-Control-Request-Method")
+		header.Add("Vary", "Access-Control-Request-Headers")
+		header.Set("Access-Control-Allow-Methods", "GET, HEAD, POST, PUT, PATCH, DELETE, OPTIONS")
+		header.Set("Access-Control-Allow-Headers", corsAllowedHeaders)
+		if cfg.MaxAge > 0 {
+			header.Set("Access-Control-Max-Age", maxAge)
+		}
+		c.AbortWithStatus(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"callable-api/internal/stats"
+)
+
+// InFlightMiddleware conta as requisições em andamento por rota, para que o
+// encerramento do servidor informe quantas ainda não terminaram
+func InFlightMiddleware(tracker *stats.InFlight) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		if route == "" {
+			route = c.Request.URL.Path
+		}
+
+		done := tracker.Start(c.Request.Method + " " + route)
+		defer done()
+		c.Next()
+	}
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"callable-api/internal/errcodes"
+	"callable-api/internal/models"
+	"callable-api/pkg/logger"
+)
+
+// InternalTokenMiddleware autentica as integrações internas (ex.: push do
+// Pub/Sub) pelo token compartilhado, informado no header Authorization
+// (Bearer) ou no parâmetro token da URL, já que as assinaturas push não
+// enviam headers personalizados
+func InternalTokenMiddleware(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		provided, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !found {
+			provided = c.Query("token")
+		}
+		if token == "" || provided == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			logger.Warn("Requisição interna com token inválido", map[string]interface{}{
+				"ip":   c.ClientIP(),
+				"path": c.FullPath(),
+			})
+			c.AbortWithStatusJSON(http.StatusUnauthorized, models.APIError{
+				Status:    "error",
+				ErrorCode: errcodes.Unauthorized,
+				Message:   "Token inválido",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
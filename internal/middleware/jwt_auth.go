@@ -1,8 +1,9 @@
 package middleware
 
 import (
-	"callable-api/pkg/auth"
-	"callable-api/pkg/config"
+	"callable-api/internal/errcodes"
+	"callable-api/internal/jwtkey"
+	"callable-api/internal/repository"
 	"callable-api/pkg/errors"
 	"callable-api/pkg/logger"
 	"strings"
@@ -10,14 +11,25 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-// JWTAuthMiddleware verifica a validade do token JWT
-func JWTAuthMiddleware(cfg *config.Config) gin.HandlerFunc {
+// AccountChecker verifica se a conta do usuário do token ainda pode acessar a
+// API (por exemplo, se não foi desativada depois da emissão do token)
+type AccountChecker func(userID string) error
+
+// accountStateCodes identifica os bloqueios de conta nas respostas de erro
+var accountStateCodes = []errcodes.Override{
+	errcodes.WhenCause(repository.ErrAccountDisabled, errcodes.AccountDisabled),
+	errcodes.WhenCause(repository.ErrPasswordResetRequired, errcodes.PasswordResetRequired),
+}
+
+// JWTAuthMiddleware verifica a validade do token JWT com o segredo vigente de
+// keys e, se informados, o estado atual da conta do usuário
+func JWTAuthMiddleware(keys *jwtkey.Keys, checkers ...AccountChecker) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Obter o token Authorization do header
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
 			err := errors.NewUnauthorizedError("Token de autenticação não fornecido", nil)
-			errors.HandleErrors(c, err)
+			errcodes.Respond(c, err)
 			c.Abort()
 			return
 		}
@@ -26,7 +38,7 @@ func JWTAuthMiddleware(cfg *config.Config) gin.HandlerFunc {
 		headerParts := strings.Split(authHeader, " ")
 		if len(headerParts) != 2 || headerParts[0] != "Bearer" {
 			err := errors.NewUnauthorizedError("Formato de token inválido", nil)
-			errors.HandleErrors(c, err)
+			errcodes.Respond(c, err)
 			c.Abort()
 			return
 		}
@@ -34,22 +46,35 @@ func JWTAuthMiddleware(cfg *config.Config) gin.HandlerFunc {
 		tokenString := headerParts[1]
 
 		// Validar o token
-		claims, err := auth.ValidateToken(tokenString, false, cfg)
+		claims, err := keys.ValidateToken(tokenString, false)
 		if err != nil {
 			logger.Error("Falha na validação do token", map[string]interface{}{
 				"error": err.Error(),
 			})
 			err := errors.NewUnauthorizedError("Token inválido ou expirado", nil)
-			errors.HandleErrors(c, err)
+			errcodes.Respond(c, err, errcodes.When(errcodes.TypeUnauthorized, errcodes.TokenInvalid))
 			c.Abort()
 			return
 		}
 
+		// Verificar o estado da conta a cada requisição, para que bloqueios
+		// valham imediatamente para tokens já emitidos
+		for _, check := range checkers {
+			if err := check(claims.UserID); err != nil {
+				errcodes.Respond(c, err, accountStateCodes...)
+				c.Abort()
+				return
+			}
+		}
+
 		// Armazenar os claims no contexto para uso posterior
 		c.Set("userID", claims.UserID)
 		c.Set("userEmail", claims.Email)
 		c.Set("userName", claims.Name)
 		c.Set("userRole", claims.Role)
+		if claims.ExpiresAt != nil {
+			c.Set(TokenExpiresAtContextKey, claims.ExpiresAt.Time)
+		}
 
 		c.Next()
 	}
@@ -61,7 +86,7 @@ func RequireRole(roles ...string) gin.HandlerFunc {
 		userRole, exists := c.Get("userRole")
 		if !exists {
 			err := errors.NewForbiddenError("Acesso negado", nil)
-			errors.HandleErrors(c, err)
+			errcodes.Respond(c, err)
 			c.Abort()
 			return
 		}
@@ -82,11 +107,24 @@ func RequireRole(roles ...string) gin.HandlerFunc {
 				"method":        c.Request.Method,
 			})
 			err := errors.NewForbiddenError("Você não tem permissão para acessar este recurso", nil)
-			errors.HandleErrors(c, err)
+			errcodes.Respond(c, err)
 			c.Abort()
 			return
 		}
 
 		c.Next()
 	}
-}
\ No newline at end of file
+}
+// OptionalJWTAuthMiddleware autentica o usuário apenas quando a requisição traz
+// o header Authorization; sem ele, a requisição segue como anônima. Tokens
+// inválidos continuam sendo rejeitados
+func OptionalJWTAuthMiddleware(keys *jwtkey.Keys, checkers ...AccountChecker) gin.HandlerFunc {
+	required := JWTAuthMiddleware(keys, checkers...)
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.Next()
+			return
+		}
+		required(c)
+	}
+}
@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"callable-api/internal/messages"
+)
+
+// LocaleMiddleware negocia o idioma das mensagens pelo header
+// Accept-Language e o guarda no contexto da requisição, para as mensagens de
+// validação geradas pelos handlers e services (ver pacote messages)
+func LocaleMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		locale := messages.Negotiate(c.GetHeader("Accept-Language"))
+		c.Header("Content-Language", locale)
+		c.Request = c.Request.WithContext(messages.WithLocale(c.Request.Context(), locale))
+		c.Next()
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"callable-api/internal/correlation"
 	"callable-api/internal/models"
 	"callable-api/pkg/logger"
 )
@@ -31,14 +32,14 @@ func LoggerMiddleware() gin.HandlerFunc {
 		clientIP := c.ClientIP()
 		
 		// Registra com logger estruturado
-		logger.Info("Requisição processada", map[string]interface{}{
+		logger.Info("Requisição processada", correlation.Fields(c.Request.Context(), map[string]interface{}{
 			"timestamp":  endTime.Format("2006/01/02 - 15:04:05"),
 			"status":     statusCode,
 			"latency_ms": latency.Milliseconds(),
 			"client_ip":  clientIP,
 			"method":     method,
 			"path":       requestPath,
-		})
+		}))
 	}
 }
 
@@ -113,7 +114,7 @@ func CORSMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, "+models.DeviceIDHeader+", "+models.RequestIDHeader)
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
 		
 		if c.Request.Method == "OPTIONS" {
@@ -4,9 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"strconv"
 	"testing"
 	"time"
 
@@ -15,10 +17,22 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
+	"callable-api/internal/chaos"
+	"callable-api/internal/correlation"
+	"callable-api/internal/jwtkey"
+	"callable-api/internal/messages"
 	"callable-api/internal/middleware"
+	"callable-api/internal/mode"
 	"callable-api/internal/models"
+	"callable-api/internal/pagination"
+	"callable-api/internal/quota"
+	"callable-api/internal/reporting"
+	"callable-api/internal/rotation"
+	"callable-api/internal/repository"
+	"callable-api/internal/stats"
 	"callable-api/pkg/auth"
 	"callable-api/pkg/config"
+	apperrors "callable-api/pkg/errors"
 	"callable-api/pkg/logger"
 )
 
@@ -88,7 +102,7 @@ func testJWTAuthMiddleware(_ *auth.SecretProvider) gin.HandlerFunc {
     }
 
     // Retorna o middleware real com a config
-    return middleware.JWTAuthMiddleware(cfg)
+    return middleware.JWTAuthMiddleware(jwtkey.New(cfg))
 }
 
 func TestJWTAuthMiddleware(t *testing.T) {
@@ -340,6 +354,33 @@ func TestLoggerMiddleware(t *testing.T) {
 	})
 }
 
+func TestRequestIDMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(middleware.RequestIDMiddleware())
+	router.GET("/id", func(c *gin.Context) {
+		c.String(http.StatusOK, correlation.RequestID(c.Request.Context()))
+	})
+
+	// IDs válidos enviados pelo cliente são reaproveitados
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/id", nil)
+	req.Header.Set(models.RequestIDHeader, "lb-123.abc")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, "lb-123.abc", w.Header().Get(models.RequestIDHeader))
+	assert.Equal(t, "lb-123.abc", w.Body.String())
+
+	// IDs ausentes ou inválidos são substituídos por um ID gerado
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/id", nil)
+	req.Header.Set(models.RequestIDHeader, "id com espaços\n")
+	router.ServeHTTP(w, req)
+	generated := w.Header().Get(models.RequestIDHeader)
+	assert.Len(t, generated, 36)
+	assert.Equal(t, generated, w.Body.String())
+}
+
 func TestCORSMiddleware(t *testing.T) {
 	// Configuração para testes
 	gin.SetMode(gin.TestMode)
@@ -382,4 +423,634 @@ func TestCORSMiddleware(t *testing.T) {
 		assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
 		assert.Contains(t, w.Header().Get("Access-Control-Allow-Methods"), "OPTIONS")
 	})
-}
\ No newline at end of file
+}
+func TestCORS(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	serve := func(cfg middleware.CORSConfig, method, origin string, preflight bool) *httptest.ResponseRecorder {
+		router := gin.New()
+		router.Use(middleware.CORS(cfg))
+		router.Handle(method, "/cors-test", func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+
+		req := httptest.NewRequest(method, "/cors-test", nil)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		if preflight {
+			req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	partners := middleware.CORSConfig{
+		AllowedOrigins:   []string{"https://*.parceiro.com", "localhost:*"},
+		AllowCredentials: true,
+		MaxAge:           time.Minute,
+	}
+
+	// Origem aceita: a própria origem é devolvida, com credenciais
+	w := serve(partners, http.MethodGet, "https://app.parceiro.com", false)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "https://app.parceiro.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Contains(t, w.Header().Get("Access-Control-Expose-Headers"), "ETag")
+	assert.Contains(t, w.Header().Values("Vary"), "Origin")
+
+	for _, origin := range []string{"http://localhost:3000", "http://localhost"} {
+		assert.Equal(t, origin, serve(partners, http.MethodGet, origin, false).Header().Get("Access-Control-Allow-Origin"), origin)
+	}
+
+	// O preflight é respondido pelo middleware
+	w = serve(partners, http.MethodOptions, "https://app.parceiro.com", true)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Contains(t, w.Header().Get("Access-Control-Allow-Methods"), "POST")
+	assert.Contains(t, w.Header().Get("Access-Control-Allow-Headers"), "Authorization")
+	assert.Equal(t, "60", w.Header().Get("Access-Control-Max-Age"))
+
+	// Origem recusada: sem headers CORS e preflight negado
+	for _, origin := range []string{"https://parceiro.com.evil.io", "http://app.parceiro.com"} {
+		w = serve(partners, http.MethodGet, origin, false)
+		assert.Equal(t, http.StatusOK, w.Code, origin)
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"), origin)
+		assert.Equal(t, http.StatusForbidden, serve(partners, http.MethodOptions, origin, true).Code, origin)
+	}
+
+	// Sem Origin, a requisição segue sem alteração
+	w = serve(partners, http.MethodGet, "", false)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, w.Header().Values("Vary"))
+
+	// Com "*", qualquer origem é aceita, mas nunca com credenciais
+	w = serve(middleware.CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true}, http.MethodGet, "https://qualquer.io", false)
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+
+	// Sem origens configuradas, nenhuma é aceita
+	assert.Empty(t, serve(middleware.CORSConfig{}, http.MethodGet, "http://localhost:3000", false).Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestQuotaMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tracker := quota.NewTracker(quota.Config{Limit: 2, Window: time.Hour})
+	router := gin.New()
+	router.Use(middleware.QuotaMiddleware(tracker, nil, nil))
+	router.GET("/test", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+
+	request := func() *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := request()
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2", w.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "1", w.Header().Get("X-RateLimit-Remaining"))
+	assert.NotEmpty(t, w.Header().Get("X-RateLimit-Reset"))
+
+	w = request()
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+
+	// A terceira requisição na mesma janela excede a cota
+	w = request()
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+	var apiErr models.APIError
+	err := json.Unmarshal(w.Body.Bytes(), &apiErr)
+	assert.NoError(t, err)
+	assert.Equal(t, "error", apiErr.Status)
+
+	// O corpo traz a política de backoff a partir do mesmo Retry-After
+	if assert.NotNil(t, apiErr.Backoff) {
+		assert.Equal(t, w.Header().Get("Retry-After"), strconv.Itoa(apiErr.Backoff.RetryAfterSeconds))
+		assert.Equal(t, models.BackoffStrategyExponential, apiErr.Backoff.Strategy)
+	}
+
+	assert.Equal(t, 3, tracker.Usage("ip:10.0.0.1").Used)
+}
+
+func TestQuotaMiddleware_Cost(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tracker := quota.NewTracker(quota.Config{Limit: 10, Window: time.Hour, CostBytes: 100})
+	router := gin.New()
+	router.Use(middleware.QuotaMiddleware(tracker, nil, nil))
+	router.POST("/test", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	// Rota sem cota própria, com o usuário já autenticado
+	unlimited := gin.New()
+	unlimited.GET("/unlimited", func(c *gin.Context) {
+		c.Set("userID", "42")
+		c.Next()
+	}, middleware.QuotaHeadersMiddleware(tracker), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	req, _ := http.NewRequest(http.MethodPost, "/test", strings.NewReader(strings.Repeat("x", 250)))
+	req.RemoteAddr = "10.0.0.1:1234"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, "3", w.Header().Get(middleware.RequestCostHeader))
+	assert.Equal(t, "7", w.Header().Get("X-RateLimit-Remaining"))
+
+	// Sem Content-Length (chunked), o custo é acertado pelos bytes lidos
+	chunked := gin.New()
+	chunked.Use(middleware.QuotaMiddleware(tracker, nil, nil))
+	chunked.POST("/test", func(c *gin.Context) {
+		_, _ = io.Copy(io.Discard, c.Request.Body)
+		c.Status(http.StatusNoContent)
+	})
+	req, _ = http.NewRequest(http.MethodPost, "/test", io.NopCloser(strings.NewReader(strings.Repeat("x", 250))))
+	req.ContentLength = -1
+	req.RemoteAddr = "10.0.0.2:1234"
+	w = httptest.NewRecorder()
+	chunked.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, 3, tracker.Usage("ip:10.0.0.2").Used)
+
+	tracker.ConsumeN(middleware.QuotaKey("42"), 4)
+	req, _ = http.NewRequest(http.MethodGet, "/unlimited", nil)
+	w = httptest.NewRecorder()
+	unlimited.ServeHTTP(w, req)
+	assert.Equal(t, "10", w.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "6", w.Header().Get("X-RateLimit-Remaining"))
+	assert.NotEmpty(t, w.Header().Get("X-RateLimit-Reset"))
+}
+
+func TestTrustProxies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	clientIP := func(router *gin.Engine, remoteAddr, forwardedFor string) string {
+		router.GET("/ip", func(c *gin.Context) {
+			c.String(http.StatusOK, c.ClientIP())
+		})
+		req, _ := http.NewRequest(http.MethodGet, "/ip", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Body.String()
+	}
+
+	// Sem proxies confiáveis, o X-Forwarded-For forjado é ignorado
+	router := gin.New()
+	assert.NoError(t, middleware.TrustProxies(router, middleware.DefaultProxyConfig()))
+	assert.Equal(t, "203.0.113.7", clientIP(router, "203.0.113.7:1234", "1.2.3.4"))
+
+	// Atrás de um proxy confiável, vale o IP informado por ele
+	cfg := middleware.DefaultProxyConfig()
+	cfg.TrustedProxies = []string{"10.0.0.0/8"}
+	router = gin.New()
+	assert.NoError(t, middleware.TrustProxies(router, cfg))
+	assert.Equal(t, "1.2.3.4", clientIP(router, "10.1.2.3:1234", "1.2.3.4"))
+
+	// CIDRs inválidos são recusados e nenhum proxy é confiável
+	cfg.TrustedProxies = []string{"10.0.0.0/99"}
+	router = gin.New()
+	assert.Error(t, middleware.TrustProxies(router, cfg))
+	assert.Equal(t, "10.1.2.3", clientIP(router, "10.1.2.3:1234", "1.2.3.4"))
+}
+
+func TestChaosMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	rules, err := chaos.ParseRules("GET /fail=error:100:502;GET /drop=drop:100")
+	assert.NoError(t, err)
+
+	router := gin.New()
+	router.Use(middleware.ChaosMiddleware(chaos.Config{Enabled: true, Rules: rules}))
+	for _, path := range []string{"/fail", "/drop", "/ok"} {
+		router.GET(path, func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{"status": "ok"})
+		})
+	}
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve("/fail")
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+	assert.Equal(t, "error", w.Header().Get("X-Chaos-Fault"))
+
+	// Sem conexão real para derrubar, a resposta é 503 sem corpo
+	w = serve("/drop")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Empty(t, w.Body.String())
+
+	w = serve("/ok")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("X-Chaos-Fault"))
+
+	// No modo real as regras são recusadas, qualquer que seja o modo do Gin
+	mode.Set(mode.Real)
+	defer mode.Set(mode.Demo)
+	realMode := gin.New()
+	realMode.Use(middleware.ChaosMiddleware(chaos.Config{Enabled: true, Rules: rules}))
+	realMode.GET("/fail", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	req, _ := http.NewRequest(http.MethodGet, "/fail", nil)
+	w = httptest.NewRecorder()
+	realMode.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestAllocationBudgetMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	budget := stats.NewAllocationBudget(stats.AllocationBudgetConfig{Bytes: 1 << 20, SampleEvery: 1})
+	router := gin.New()
+	router.Use(middleware.AllocationBudgetMiddleware(budget))
+	router.GET("/buffer/:size", func(c *gin.Context) {
+		// Simula um handler que lê o payload inteiro em memória
+		size := 1 << 10
+		if c.Param("size") == "large" {
+			size = 4 << 20
+		}
+		c.Data(http.StatusOK, "application/octet-stream", make([]byte, size))
+	})
+
+	for _, path := range []string{"/buffer/small", "/buffer/large"} {
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+
+	over := budget.OverBudget()
+	if assert.Len(t, over, 1) {
+		assert.Equal(t, "GET /buffer/:size", over[0].Route)
+		assert.Equal(t, int64(2), over[0].Samples)
+		assert.Equal(t, int64(1), over[0].OverBudget)
+	}
+}
+
+func TestRecoveryMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	reports := make(chan reporting.Report, 1)
+	reporter := reporting.ReporterFunc(func(ctx context.Context, report reporting.Report) error {
+		reports <- report
+		return nil
+	})
+
+	router := gin.New()
+	router.Use(middleware.RecoveryMiddleware(reporter))
+	router.POST("/panic", func(c *gin.Context) {
+		var input map[string]interface{}
+		assert.NoError(t, c.ShouldBindJSON(&input))
+		panic("falha inesperada")
+	})
+
+	req, _ := http.NewRequest(http.MethodPost, "/panic", strings.NewReader(`{"name":"item","password":"segredo"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer token-secreto")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	var apiErr models.APIError
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &apiErr))
+	assert.NotEmpty(t, apiErr.ErrorID)
+	assert.Equal(t, apiErr.ErrorID, w.Header().Get(middleware.ErrorIDHeader))
+
+	select {
+	case report := <-reports:
+		assert.Equal(t, apiErr.ErrorID, report.ErrorID)
+		assert.Equal(t, "falha inesperada", report.Message)
+		assert.Contains(t, report.Stack, "goroutine")
+		assert.Equal(t, "/panic", report.Request.Route)
+		assert.Equal(t, []string{"[REDACTED]"}, report.Request.Headers["Authorization"])
+		assert.Contains(t, report.Request.Body, `"name":"item"`)
+		assert.NotContains(t, report.Request.Body, "segredo")
+	case <-time.After(time.Second):
+		t.Fatal("relatório de erro não foi encaminhado")
+	}
+}
+
+func TestJWTAuthMiddleware_AccountState(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cfg := &config.Config{
+		JWTSecret:                "test-secret",
+		JWTExpirationMinutes:     15,
+		JWTRefreshExpirationDays: 7,
+	}
+
+	// Apenas o usuário "blocked" está desativado
+	checker := func(userID string) error {
+		if userID == "blocked" {
+			return apperrors.NewForbiddenError("Conta desativada", repository.ErrAccountDisabled)
+		}
+		return nil
+	}
+
+	router := gin.New()
+	router.GET("/test", middleware.JWTAuthMiddleware(jwtkey.New(cfg), checker), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+
+	serve := func(userID string) *httptest.ResponseRecorder {
+		tokens, err := auth.GenerateTokenPair(&models.User{ID: userID, Email: userID + "@example.com", Role: "user"}, cfg)
+		assert.NoError(t, err)
+		req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, serve("active").Code)
+
+	// Tokens emitidos antes da desativação deixam de valer
+	w := serve("blocked")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	var body map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "ACCOUNT_DISABLED", body["code"])
+}
+
+func TestPreferencesMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	prefs := map[string]*models.UserPreferences{
+		"u1": {PageSize: 25, Locale: "en"},
+	}
+	lookup := func(userID string) (*models.UserPreferences, error) {
+		if p, ok := prefs[userID]; ok {
+			return p, nil
+		}
+		return nil, apperrors.NewNotFoundError("Usuário não encontrado", nil)
+	}
+
+	r := gin.New()
+	r.Use(middleware.LocaleMiddleware())
+	r.Use(func(c *gin.Context) {
+		c.Set("userID", c.GetHeader("X-Test-User"))
+		c.Next()
+	})
+	r.Use(middleware.PreferencesMiddleware(lookup))
+	r.GET("/test", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"locale": messages.Locale(c.Request.Context()),
+			"limit":  pagination.DefaultConfig().Parse(c).Limit,
+		})
+	})
+
+	call := func(userID, query string) (map[string]interface{}, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(http.MethodGet, "/test"+query, nil)
+		req.Header.Set("Accept-Language", "pt-BR")
+		req.Header.Set("X-Test-User", userID)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		var body map[string]interface{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		return body, w
+	}
+
+	// As preferências salvas prevalecem sobre o Accept-Language e o padrão
+	body, w := call("u1", "")
+	assert.Equal(t, "en", body["locale"])
+	assert.Equal(t, float64(25), body["limit"])
+	assert.Equal(t, "en", w.Header().Get("Content-Language"))
+
+	// O limit informado na requisição continua valendo
+	body, _ = call("u1", "?limit=5")
+	assert.Equal(t, float64(5), body["limit"])
+
+	// Anônimos e falhas na leitura mantêm a negociação padrão
+	for _, userID := range []string{"", "desconhecido"} {
+		body, _ = call(userID, "")
+		assert.Equal(t, "pt-BR", body["locale"])
+		assert.Equal(t, float64(pagination.DefaultPageSize), body["limit"])
+	}
+}
+
+func TestOrganizationMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	lookup := func(ctx context.Context, orgID, userID string) (*models.Membership, error) {
+		if orgID == "org-1" && userID == "u1" {
+			return &models.Membership{OrgID: orgID, UserID: userID, Role: models.OrgRoleOwner}, nil
+		}
+		return nil, apperrors.NewNotFoundError("Usuário não é membro da organização", repository.ErrMembershipNotFound)
+	}
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("userID", c.GetHeader("X-Test-User"))
+		c.Next()
+	})
+	r.Use(middleware.OrganizationMiddleware(lookup))
+	r.GET("/test", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"org": c.GetString("orgID"), "role": c.GetString("orgRole")})
+	})
+
+	call := func(userID, header, claim string) (map[string]interface{}, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set("X-Test-User", userID)
+		if header != "" {
+			req.Header.Set(models.OrganizationHeader, header)
+		}
+		if claim != "" {
+			token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{models.OrganizationClaim: claim}).SignedString([]byte("segredo"))
+			assert.NoError(t, err)
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		var body map[string]interface{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		return body, w
+	}
+
+	// A organização vem do header ou, na falta dele, do claim do token
+	body, w := call("u1", "org-1", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "org-1", body["org"])
+	assert.Equal(t, models.OrgRoleOwner, body["role"])
+
+	body, _ = call("u1", "", "org-1")
+	assert.Equal(t, "org-1", body["org"])
+
+	// O header prevalece sobre o claim
+	_, w = call("u1", "org-2", "org-1")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "ORG_ACCESS_DENIED")
+
+	// Sem organização, ou sem usuário, a requisição segue sem alteração
+	for _, userID := range []string{"u1", ""} {
+		body, w = call(userID, "", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "", body["org"])
+	}
+	_, w = call("", "org-2", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestConsentMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	check := func(ctx context.Context, userID string) error {
+		if userID == "pendente" {
+			return apperrors.NewForbiddenError("Aceite pendente", repository.ErrConsentRequired)
+		}
+		return nil
+	}
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("userID", c.GetHeader("X-Test-User"))
+		c.Next()
+	})
+	r.Use(middleware.ConsentMiddleware(check, "/consent"))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.GET("/test", ok)
+	r.POST("/consent", ok)
+
+	call := func(method, path, userID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("X-Test-User", userID)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := call(http.MethodGet, "/test", "pendente")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "CONSENT_REQUIRED")
+
+	// A rota de aceite, os usuários em dia e as requisições anônimas seguem
+	assert.Equal(t, http.StatusOK, call(http.MethodPost, "/consent", "pendente").Code)
+	assert.Equal(t, http.StatusOK, call(http.MethodGet, "/test", "u1").Code)
+	assert.Equal(t, http.StatusOK, call(http.MethodGet, "/test", "").Code)
+}
+
+func TestScopedTokenMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	expiresAt := time.Now().Add(time.Minute)
+	lookup := func(ctx context.Context, token string) (*models.TokenGrant, error) {
+		if token == models.ScopedTokenPrefix+"valido" {
+			return &models.TokenGrant{UserID: "u1", Role: "user", Scopes: []string{"data:write"}, ExpiresAt: expiresAt}, nil
+		}
+		return nil, apperrors.NewUnauthorizedError("Token inválido ou expirado", nil)
+	}
+	jwtAuth := func(c *gin.Context) {
+		c.Set("userID", "jwt-user")
+		c.Next()
+	}
+
+	r := gin.New()
+	r.Use(middleware.ScopedTokenMiddleware(lookup, jwtAuth))
+	handler := func(c *gin.Context) {
+		grant := middleware.CurrentToken(c)
+		c.JSON(http.StatusOK, gin.H{"user": grant.UserID, "scopes": grant.Scopes})
+	}
+	r.GET("/data", middleware.RequireScope("data:read"), handler)
+	r.DELETE("/admin", middleware.RequireScope("admin:write"), handler)
+
+	call := func(method, path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// O escopo write inclui o read do mesmo recurso
+	w := call(http.MethodGet, "/data", models.ScopedTokenPrefix+"valido")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"user":"u1","scopes":["data:write"]}`, w.Body.String())
+
+	w = call(http.MethodDelete, "/admin", models.ScopedTokenPrefix+"valido")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "INSUFFICIENT_SCOPE")
+
+	w = call(http.MethodGet, "/data", models.ScopedTokenPrefix+"expirado")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "TOKEN_INVALID")
+
+	// Os demais tokens seguem para a autenticação JWT, sem restrição de escopo
+	w = call(http.MethodDelete, "/admin", "jwt")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "jwt-user")
+
+	// A cota de um token restrito é a do usuário, e o token validado pela cota
+	// não é consultado de novo na autenticação
+	lookups := 0
+	counting := func(ctx context.Context, token string) (*models.TokenGrant, error) {
+		lookups++
+		return lookup(ctx, token)
+	}
+	tracker := quota.NewTracker(quota.Config{Limit: 10, Window: time.Hour})
+	quoted := gin.New()
+	quoted.Use(middleware.QuotaMiddleware(tracker, nil, counting), middleware.ScopedTokenMiddleware(counting, jwtAuth))
+	quoted.GET("/data", handler)
+	req := httptest.NewRequest(http.MethodGet, "/data", nil)
+	req.Header.Set("Authorization", "Bearer "+models.ScopedTokenPrefix+"valido")
+	w = httptest.NewRecorder()
+	quoted.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 1, lookups)
+	assert.Equal(t, 1, tracker.Usage(middleware.QuotaKey("u1")).Used)
+}
+
+func TestJWTAuthMiddleware_SecretRotation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.Config{JWTSecret: "segredo-antigo", JWTConfig: config.JWTConfig{SecretKey: "segredo-antigo"}, JWTExpirationMinutes: 15, JWTRefreshExpirationDays: 7}
+	keys := jwtkey.New(cfg).WithGracePeriod(0)
+
+	// A notificação do Secret Manager troca o segredo, como no hook de cmd/api
+	rotations := rotation.NewService(nil).OnRotate("jwt-secret", func(_ context.Context, _ string) error {
+		keys.Rotate("segredo-novo")
+		return nil
+	})
+
+	router := gin.New()
+	router.GET("/test", middleware.JWTAuthMiddleware(keys), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	serve := func(secret string) int {
+		signing := *cfg
+		signing.JWTSecret, signing.JWTConfig.SecretKey = secret, secret
+		tokens, err := auth.GenerateTokenPair(&models.User{ID: "u1", Email: "u1@example.com", Role: "user"}, &signing)
+		assert.NoError(t, err)
+		req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, serve("segredo-antigo"))
+	assert.Equal(t, http.StatusUnauthorized, serve("segredo-novo"))
+
+	_, err := rotations.Handle(context.Background(), rotation.PushMessage{MessageID: "1", Attributes: map[string]string{
+		"eventType": rotation.EventSecretVersionAdd,
+		"secretId":  "projects/demo/secrets/jwt-secret",
+	}})
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, serve("segredo-novo"))
+	assert.Equal(t, http.StatusUnauthorized, serve("segredo-antigo"))
+	assert.Equal(t, "segredo-novo", keys.Config().JWTSecret)
+}
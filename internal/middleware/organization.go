@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"context"
+	stderrors "errors"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+
+	"callable-api/internal/correlation"
+	"callable-api/internal/errcodes"
+	"callable-api/internal/models"
+	"callable-api/internal/repository"
+	"callable-api/pkg/errors"
+	"callable-api/pkg/logger"
+)
+
+// MembershipLookup retorna a participação do usuário na organização
+// (repository.ErrMembershipNotFound se ele não é membro)
+type MembershipLookup func(ctx context.Context, orgID, userID string) (*models.Membership, error)
+
+// OrganizationMiddleware ativa a organização da requisição, informada pelo
+// header X-Organization-ID ou, na falta dele, pelo claim org_id do token. O
+// usuário precisa ser membro da organização (403 caso contrário); a
+// organização e o papel do usuário nela ficam em "orgID" e "orgRole". Deve
+// rodar após a autenticação, que já validou o token; requisições anônimas e
+// sem organização seguem sem alteração
+func OrganizationMiddleware(lookup MembershipLookup) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetString("userID")
+		if userID == "" {
+			c.Next()
+			return
+		}
+
+		orgID := strings.TrimSpace(c.GetHeader(models.OrganizationHeader))
+		if orgID == "" {
+			orgID = organizationClaim(c.GetHeader("Authorization"))
+		}
+		if orgID == "" {
+			c.Next()
+			return
+		}
+
+		member, err := lookup(c.Request.Context(), orgID, userID)
+		if err != nil {
+			if stderrors.Is(err, repository.ErrMembershipNotFound) {
+				logger.Warn("Organização ativa recusada: usuário não é membro", correlation.Fields(c.Request.Context(), map[string]interface{}{
+					"orgId":  orgID,
+					"userId": userID,
+				}))
+				err = errors.NewForbiddenError("Você não é membro da organização informada", err)
+			}
+			errcodes.Respond(c, err, errcodes.WhenCause(repository.ErrMembershipNotFound, errcodes.OrgAccessDenied))
+			c.Abort()
+			return
+		}
+
+		c.Set("orgID", member.OrgID)
+		c.Set("orgRole", member.Role)
+		c.Next()
+	}
+}
+
+// organizationClaim lê o claim org_id do token Bearer. A assinatura não é
+// verificada aqui: o middleware de autenticação já validou o mesmo token
+func organizationClaim(authHeader string) string {
+	tokenString, found := strings.CutPrefix(authHeader, "Bearer ")
+	if !found {
+		return ""
+	}
+
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(tokenString, claims); err != nil {
+		return ""
+	}
+	orgID, _ := claims[models.OrganizationClaim].(string)
+	return strings.TrimSpace(orgID)
+}
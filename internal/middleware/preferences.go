@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"callable-api/internal/correlation"
+	"callable-api/internal/messages"
+	"callable-api/internal/models"
+	"callable-api/internal/pagination"
+	"callable-api/pkg/logger"
+)
+
+// PreferencesLookup retorna as preferências salvas do usuário
+type PreferencesLookup func(userID string) (*models.UserPreferences, error)
+
+// PreferencesMiddleware aplica as preferências do usuário autenticado às
+// requisições que não as informam: o idioma salvo prevalece sobre o
+// Accept-Language e o tamanho de página vale para as listagens sem limit.
+// Deve rodar após a autenticação; requisições anônimas seguem sem alteração,
+// assim como as de usuários cujas preferências não puderam ser lidas
+func PreferencesMiddleware(lookup PreferencesLookup) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetString("userID")
+		if userID == "" {
+			c.Next()
+			return
+		}
+
+		prefs, err := lookup(userID)
+		if err != nil {
+			logger.Warn("Falha ao carregar preferências do usuário", correlation.Fields(c.Request.Context(), map[string]interface{}{
+				"userId": userID,
+				"error":  err.Error(),
+			}))
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		if prefs.Locale != "" {
+			locale := messages.Negotiate(prefs.Locale)
+			c.Header("Content-Language", locale)
+			ctx = messages.WithLocale(ctx, locale)
+		}
+		if prefs.PageSize > 0 {
+			ctx = pagination.WithPageSize(ctx, prefs.PageSize)
+		}
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
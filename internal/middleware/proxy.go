@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// ProxyConfig define quais proxies podem informar o IP real do cliente. Sem
+// proxies confiáveis, o IP é sempre o da conexão e headers como
+// X-Forwarded-For são ignorados, pois qualquer cliente pode forjá-los
+type ProxyConfig struct {
+	TrustedProxies []string // IPs ou CIDRs dos proxies (ex.: 10.0.0.0/8)
+	Headers        []string // headers com o IP do cliente, consultados em ordem
+}
+
+// DefaultProxyConfig retorna a configuração padrão: nenhum proxy confiável
+func DefaultProxyConfig() ProxyConfig {
+	return ProxyConfig{Headers: []string{"X-Forwarded-For", "X-Real-IP"}}
+}
+
+// TrustProxies configura o router para que c.ClientIP() (usado nos logs, na
+// cota de requisições e no histórico de login) só aceite o IP informado pelos
+// proxies confiáveis. Retorna erro se algum IP ou CIDR for inválido; nesse
+// caso nenhum proxy é confiável
+func TrustProxies(router *gin.Engine, cfg ProxyConfig) error {
+	router.ForwardedByClientIP = len(cfg.TrustedProxies) > 0
+	router.RemoteIPHeaders = cfg.Headers
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		router.ForwardedByClientIP = false
+		_ = router.SetTrustedProxies(nil)
+		return err
+	}
+	return nil
+}
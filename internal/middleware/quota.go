@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"callable-api/internal/errcodes"
+	"callable-api/internal/jwtkey"
+	"callable-api/internal/models"
+	"callable-api/internal/quota"
+	"callable-api/pkg/logger"
+)
+
+// QuotaKeyContextKey é a chave do contexto Gin com o identificador usado na cota
+const QuotaKeyContextKey = "quotaKey"
+
+// QuotaKey retorna o identificador de cota de um usuário autenticado
+func QuotaKey(userID string) string {
+	return "user:" + userID
+}
+
+// RequestCostHeader informa quanto a requisição consumiu da cota, quando o
+// custo depende do tamanho do corpo (quota.Config.CostBytes)
+const RequestCostHeader = "X-Request-Cost"
+
+// QuotaMiddleware contabiliza as requisições de cada cliente e responde 429
+// quando a cota da janela é excedida. Clientes com token JWT ou token
+// restrito válido são identificados pelo usuário; os demais, pelo IP (scoped
+// pode ser nil quando a API não aceita tokens restritos). Requisições com
+// corpo grande podem consumir mais de uma unidade da cota (ver
+// quota.Config.Cost). Quando o tamanho do corpo não é informado (upload
+// chunked), a requisição consome uma unidade na entrada e a diferença é
+// cobrada pelos bytes lidos depois do handler
+func QuotaMiddleware(tracker *quota.Tracker, keys *jwtkey.Keys, scoped ScopedTokenLookup) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := quotaKey(c, keys, scoped)
+		c.Set(QuotaKeyContextKey, key)
+
+		cost := tracker.Config().Cost(c.Request.ContentLength)
+		usage, allowed := tracker.ConsumeN(key, cost)
+		setQuotaHeaders(c, usage)
+		if tracker.Config().CostBytes > 0 {
+			c.Header(RequestCostHeader, strconv.Itoa(cost))
+		}
+
+		if !allowed {
+			retryAfter := time.Until(usage.ResetAt) + time.Second
+			logger.Warn("Cota de requisições excedida", map[string]interface{}{
+				"key":    key,
+				"limit":  usage.Limit,
+				"path":   c.Request.URL.Path,
+				"method": c.Request.Method,
+			})
+			errcodes.RespondRetry(c, models.ErrQuotaExceeded, retryAfter)
+			return
+		}
+
+		var body *countingReader
+		if c.Request.ContentLength < 0 && c.Request.Body != nil && tracker.Config().CostBytes > 0 {
+			body = &countingReader{ReadCloser: c.Request.Body}
+			c.Request.Body = body
+		}
+
+		c.Next()
+
+		if body != nil {
+			if extra := tracker.Config().Cost(body.read) - cost; extra > 0 {
+				tracker.ConsumeN(key, extra)
+			}
+		}
+	}
+}
+
+// countingReader conta os bytes lidos do corpo da requisição
+type countingReader struct {
+	io.ReadCloser
+	read int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.read += int64(n)
+	return n, err
+}
+
+// QuotaHeadersMiddleware informa o consumo da cota do usuário autenticado nas
+// rotas que não contabilizam requisições (RateUnlimited), para que todas as
+// respostas autenticadas tragam os headers X-RateLimit-*. As rotas com cota
+// própria mantêm os headers do QuotaMiddleware
+func QuotaHeadersMiddleware(tracker *quota.Tracker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetString("userID")
+		if userID != "" && c.Writer.Header().Get("X-RateLimit-Limit") == "" {
+			setQuotaHeaders(c, tracker.Usage(QuotaKey(userID)))
+		}
+		c.Next()
+	}
+}
+
+// setQuotaHeaders informa o limite, o saldo e o fim da janela da cota
+func setQuotaHeaders(c *gin.Context, usage models.QuotaUsage) {
+	if usage.Limit <= 0 {
+		return
+	}
+	c.Header("X-RateLimit-Limit", strconv.Itoa(usage.Limit))
+	c.Header("X-RateLimit-Remaining", strconv.Itoa(usage.Remaining))
+	c.Header("X-RateLimit-Reset", strconv.FormatInt(usage.ResetAt.Unix(), 10))
+}
+
+// quotaKey identifica o cliente pelo usuário do token JWT ou do token
+// restrito ou, na ausência de um token válido, pelo IP. A autenticação em si
+// continua a cargo do JWTAuthMiddleware e do ScopedTokenMiddleware, que
+// reaproveita o token restrito já validado aqui
+func quotaKey(c *gin.Context, keys *jwtkey.Keys, scoped ScopedTokenLookup) string {
+	authHeader := c.GetHeader("Authorization")
+	if token, found := strings.CutPrefix(authHeader, "Bearer "); found && token != "" {
+		if strings.HasPrefix(token, models.ScopedTokenPrefix) {
+			if scoped != nil {
+				if grant, err := scoped(c.Request.Context(), token); err == nil {
+					c.Set(scopedGrantContextKey, grant)
+					return QuotaKey(grant.UserID)
+				}
+			}
+		} else if keys != nil {
+			if claims, err := keys.ValidateToken(token, false); err == nil {
+				return QuotaKey(claims.UserID)
+			}
+		}
+	}
+	return "ip:" + c.ClientIP()
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"callable-api/internal/recorder"
+)
+
+// recordingWriter copia o corpo da resposta, até o limite de gravação
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+// Write implementa io.Writer copiando o corpo para a gravação
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	if remaining := recorder.MaxBodySize - w.body.Len(); remaining > 0 {
+		if len(data) > remaining {
+			w.body.Write(data[:remaining])
+		} else {
+			w.body.Write(data)
+		}
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString implementa gin.ResponseWriter copiando o corpo para a gravação
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// RecordingMiddleware grava as requisições das rotas selecionadas no Recorder
+func RecordingMiddleware(rec *recorder.Recorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		if !rec.ShouldRecord(c.Request, route) {
+			c.Next()
+			return
+		}
+
+		// Ler o corpo da requisição e devolvê-lo intacto aos handlers
+		var requestBody []byte
+		if c.Request.Body != nil {
+			data, err := io.ReadAll(c.Request.Body)
+			c.Request.Body.Close()
+			c.Request.Body = io.NopCloser(bytes.NewReader(data))
+			if err == nil {
+				requestBody = data
+				if len(requestBody) > recorder.MaxBodySize {
+					requestBody = requestBody[:recorder.MaxBodySize]
+				}
+			}
+		}
+
+		writer := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		start := time.Now()
+		c.Next()
+
+		rec.Record(c.Request, route, requestBody, writer.Status(), writer.Header(), writer.body.Bytes(), time.Since(start))
+	}
+}
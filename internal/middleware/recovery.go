@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"callable-api/internal/models"
+	"callable-api/internal/redact"
+	"callable-api/internal/reporting"
+	"callable-api/pkg/logger"
+)
+
+// ErrorIDHeader devolve ao cliente o identificador da falha registrada
+const ErrorIDHeader = "X-Error-ID"
+
+// maxSnapshotBody limita o corpo da requisição incluído no relatório de erro
+const maxSnapshotBody = 16 * 1024
+
+// reportTimeout limita o envio de cada relatório à integração de erros
+const reportTimeout = 10 * time.Second
+
+// RecoveryMiddleware recupera panics dos handlers. Cada falha recebe um ID, que
+// é registrado no log com o stack trace e um retrato sanitizado da requisição,
+// devolvido ao cliente no corpo do 500 e encaminhado ao reporter
+func RecoveryMiddleware(reporter reporting.Reporter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body := peekBody(c.Request)
+
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			// http.ErrAbortHandler interrompe a resposta de propósito
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+
+			report := reporting.Report{
+				ErrorID:    uuid.New().String(),
+				Message:    fmt.Sprint(recovered),
+				Stack:      string(debug.Stack()),
+				Request:    snapshot(c, body),
+				OccurredAt: time.Now(),
+			}
+
+			logger.Error("Panic recuperado", map[string]interface{}{
+				"error_id": report.ErrorID,
+				"panic":    report.Message,
+				"stack":    report.Stack,
+				"request":  report.Request,
+			})
+
+			go func() {
+				ctx, cancel := context.WithTimeout(context.Background(), reportTimeout)
+				defer cancel()
+				if err := reporter.Report(ctx, report); err != nil {
+					logger.Warn("Falha ao encaminhar relatório de erro", map[string]interface{}{
+						"error_id": report.ErrorID,
+						"error":    err.Error(),
+					})
+				}
+			}()
+
+			// Se a resposta já começou a ser enviada, não há como trocá-la por um 500
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+
+			c.Header(ErrorIDHeader, report.ErrorID)
+			apiErr := models.ErrInternalServer.WithErrorID(report.ErrorID)
+			c.AbortWithStatusJSON(apiErr.Code, apiErr)
+		}()
+
+		c.Next()
+	}
+}
+
+// peekBody lê o início do corpo da requisição para o relatório, devolvendo-o
+// intacto aos handlers
+func peekBody(req *http.Request) []byte {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+
+	head, _ := io.ReadAll(io.LimitReader(req.Body, maxSnapshotBody))
+	req.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(head), req.Body), req.Body}
+	return head
+}
+
+// snapshot retrata a requisição sem credenciais nem campos sensíveis
+func snapshot(c *gin.Context, body []byte) reporting.Request {
+	return reporting.Request{
+		Method:    c.Request.Method,
+		URL:       c.Request.URL.String(),
+		Route:     c.FullPath(),
+		Headers:   redact.Headers(c.Request.Header),
+		Body:      redact.Body(body),
+		ClientIP:  c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+}
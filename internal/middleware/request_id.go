@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"callable-api/internal/correlation"
+	"callable-api/internal/models"
+)
+
+// maxRequestIDLength limita o tamanho dos IDs de requisição enviados pelos clientes
+const maxRequestIDLength = 64
+
+// RequestIDMiddleware identifica cada requisição, reaproveitando o header
+// X-Request-ID enviado pelo cliente (ou pelo balanceador) quando válido. O ID
+// volta no mesmo header da resposta e fica no contexto da requisição, para os
+// logs das camadas internas (ver pacote correlation). O trace W3C recebido
+// (traceparent) também fica no contexto, ou um novo é iniciado, para ser
+// propagado nas chamadas externas (ver pacote httpclient)
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(models.RequestIDHeader)
+		if !validRequestID(id) {
+			id = uuid.New().String()
+		}
+
+		c.Set("requestID", id)
+		c.Header(models.RequestIDHeader, id)
+
+		traceParent := c.GetHeader(correlation.TraceParentHeader)
+		if !correlation.ValidTraceParent(traceParent) {
+			traceParent = correlation.NewTraceParent()
+		}
+
+		ctx := correlation.WithRequestID(c.Request.Context(), id)
+		c.Request = c.Request.WithContext(correlation.WithTraceParent(ctx, traceParent))
+
+		c.Next()
+	}
+}
+
+// validRequestID aceita apenas IDs curtos com letras, dígitos, '-', '_' e '.',
+// para que valores arbitrários não cheguem aos logs
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
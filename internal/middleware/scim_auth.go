@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"callable-api/internal/scim"
+	"callable-api/pkg/logger"
+)
+
+// SCIMAuthMiddleware autentica o provedor de identidade pelo token Bearer
+// compartilhado, respondendo no formato de erro do SCIM
+func SCIMAuthMiddleware(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		provided, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !found || token == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			logger.Warn("Requisição SCIM com token inválido", map[string]interface{}{
+				"ip": c.ClientIP(),
+			})
+			c.Header("Content-Type", scim.ContentType)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, scim.NewError(http.StatusUnauthorized, "", "Token SCIM inválido"))
+			return
+		}
+
+		c.Next()
+	}
+}
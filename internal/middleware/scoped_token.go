@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"callable-api/internal/errcodes"
+	"callable-api/internal/models"
+	"callable-api/pkg/errors"
+	"callable-api/pkg/logger"
+)
+
+// Chaves do contexto Gin com os escopos e a validade do token da requisição
+const (
+	TokenScopesContextKey    = "tokenScopes"
+	TokenExpiresAtContextKey = "tokenExpiresAt"
+)
+
+// scopedGrantContextKey guarda o token restrito já validado pelo
+// QuotaMiddleware, para que ele não seja consultado duas vezes
+const scopedGrantContextKey = "scopedTokenGrant"
+
+// ScopedTokenLookup valida um token restrito e retorna os escopos concedidos
+// com os dados atuais do usuário
+type ScopedTokenLookup func(ctx context.Context, token string) (*models.TokenGrant, error)
+
+// ScopedTokenMiddleware autentica os tokens restritos (prefixo
+// models.ScopedTokenPrefix) e delega os demais ao middleware de autenticação
+// informado (JWT obrigatório ou opcional)
+func ScopedTokenMiddleware(lookup ScopedTokenLookup, next gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !found || !strings.HasPrefix(token, models.ScopedTokenPrefix) {
+			next(c)
+			return
+		}
+
+		var grant *models.TokenGrant
+		var err error
+		if cached, exists := c.Get(scopedGrantContextKey); exists {
+			grant = cached.(*models.TokenGrant)
+		} else {
+			grant, err = lookup(c.Request.Context(), token)
+		}
+		if err != nil {
+			logger.Warn("Token restrito recusado", map[string]interface{}{
+				"error": err.Error(),
+				"path":  c.Request.URL.Path,
+			})
+			errcodes.Respond(c, err, append(accountStateCodes, errcodes.When(errcodes.TypeUnauthorized, errcodes.TokenInvalid))...)
+			c.Abort()
+			return
+		}
+
+		c.Set("userID", grant.UserID)
+		c.Set("userEmail", grant.Email)
+		c.Set("userName", grant.Name)
+		c.Set("userRole", grant.Role)
+		c.Set(TokenScopesContextKey, grant.Scopes)
+		c.Set(TokenExpiresAtContextKey, grant.ExpiresAt)
+		c.Next()
+	}
+}
+
+// CurrentToken retorna o usuário, os escopos (nil para tokens sem restrição)
+// e a validade do token da requisição autenticada
+func CurrentToken(c *gin.Context) models.TokenGrant {
+	grant := models.TokenGrant{
+		UserID: c.GetString("userID"),
+		Email:  c.GetString("userEmail"),
+		Name:   c.GetString("userName"),
+		Role:   c.GetString("userRole"),
+	}
+	if scopes, exists := c.Get(TokenScopesContextKey); exists {
+		grant.Scopes, _ = scopes.([]string)
+	}
+	if expiresAt, exists := c.Get(TokenExpiresAtContextKey); exists {
+		grant.ExpiresAt, _ = expiresAt.(time.Time)
+	}
+	return grant
+}
+
+// RequireScope recusa com 403 as requisições de tokens restritos que não
+// concedem o escopo da rota. Tokens sem restrição e requisições anônimas seguem
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scopes, restricted := c.Get(TokenScopesContextKey)
+		if !restricted {
+			c.Next()
+			return
+		}
+		granted, _ := scopes.([]string)
+		if granted == nil {
+			granted = []string{}
+		}
+		if !models.ScopeAllows(granted, scope) {
+			err := errors.NewForbiddenError("O token não concede o escopo "+scope, nil)
+			errcodes.Respond(c, err, errcodes.When(errcodes.TypeForbidden, errcodes.InsufficientScope))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"callable-api/internal/stats"
+)
+
+// StatsMiddleware registra cada requisição no coletor de estatísticas
+func StatsMiddleware(collector *stats.Collector) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = c.Request.URL.Path
+		}
+		collector.Observe(c.Request.Method, route, c.Writer.Status(), time.Since(start), c.Errors.String())
+	}
+}
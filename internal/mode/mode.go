@@ -0,0 +1,56 @@
+// Package mode define o modo de execução da aplicação. No modo demo os
+// comportamentos simulados (dados de exemplo, emails apenas registrados em
+// log, segredos locais, storage ausente) são permitidos; no modo real a
+// aplicação exige backends genuínos e se recusa a iniciar sem eles.
+package mode
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Mode é o modo de execução da aplicação
+type Mode string
+
+// Modos suportados
+const (
+	Demo Mode = "demo"
+	Real Mode = "real"
+)
+
+var (
+	mutex   sync.RWMutex
+	current = Demo
+)
+
+// Parse interpreta o valor da variável MODE. Valor vazio equivale a demo
+func Parse(value string) (Mode, error) {
+	switch Mode(strings.ToLower(strings.TrimSpace(value))) {
+	case "", Demo:
+		return Demo, nil
+	case Real:
+		return Real, nil
+	default:
+		return "", fmt.Errorf("modo de execução inválido %q: use demo ou real", value)
+	}
+}
+
+// Set define o modo de execução do processo
+func Set(m Mode) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	current = m
+}
+
+// Current retorna o modo de execução do processo (demo se nunca definido)
+func Current() Mode {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return current
+}
+
+// IsDemo indica se os comportamentos simulados estão ativos
+func IsDemo() bool {
+	return Current() == Demo
+}
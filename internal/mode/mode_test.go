@@ -0,0 +1,34 @@
+package mode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := map[string]Mode{
+		"":      Demo,
+		"demo":  Demo,
+		" Real": Real,
+		"REAL":  Real,
+	}
+	for value, expected := range tests {
+		m, err := Parse(value)
+		assert.NoError(t, err, value)
+		assert.Equal(t, expected, m, value)
+	}
+
+	_, err := Parse("production")
+	assert.Error(t, err)
+}
+
+func TestSetCurrent(t *testing.T) {
+	defer Set(Demo)
+
+	assert.True(t, IsDemo())
+
+	Set(Real)
+	assert.Equal(t, Real, Current())
+	assert.False(t, IsDemo())
+}
@@ -0,0 +1,77 @@
+package models
+
+import "time"
+
+// RequestStats resume o tráfego recente da API
+type RequestStats struct {
+	Total            int64   `json:"total" example:"15230"`
+	LastMinute       int     `json:"last_minute" example:"120"`
+	LastFiveMinutes  int     `json:"last_five_minutes" example:"610"`
+	PerSecond        float64 `json:"per_second" example:"2"`
+	ErrorsLastMinute int     `json:"errors_last_minute" example:"1"`
+	AvgLatencyMs     float64 `json:"avg_latency_ms" example:"12.5"`
+}
+
+// RequestError descreve uma requisição recente que terminou em erro do servidor
+type RequestError struct {
+	Time    time.Time `json:"time"`
+	Method  string    `json:"method" example:"POST"`
+	Route   string    `json:"route" example:"/api/v1/data"`
+	Status  int       `json:"status" example:"500"`
+	Message string    `json:"message,omitempty"`
+}
+
+// DependencyStatus é o resultado da verificação de uma dependência externa
+type DependencyStatus struct {
+	Name      string  `json:"name" example:"elasticsearch"`
+	Status    string  `json:"status" example:"up"`
+	LatencyMs float64 `json:"latency_ms" example:"3.2"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// OperationStats resume as chamadas a uma operação interna (por exemplo, um
+// método de repositório). Erros esperados, como NotFound, não contam como falha
+type OperationStats struct {
+	Component    string  `json:"component" example:"items"`
+	Operation    string  `json:"operation" example:"FindByID"`
+	Calls        int64   `json:"calls" example:"5120"`
+	Errors       int64   `json:"errors" example:"2"`
+	ErrorRate    float64 `json:"error_rate" example:"0.0004"`
+	AvgLatencyMs float64 `json:"avg_latency_ms" example:"0.08"`
+	MaxLatencyMs float64 `json:"max_latency_ms" example:"3.1"`
+
+	// Alocações de memória por chamada, medidas por amostragem apenas nas
+	// operações dos serviços com SERVICE_PROFILING_ALLOC_SAMPLE ativo
+	AllocSamples  int64   `json:"alloc_samples,omitempty" example:"51"`
+	AvgAllocs     float64 `json:"avg_allocs,omitempty" example:"212.5"`
+	AvgAllocBytes float64 `json:"avg_alloc_bytes,omitempty" example:"18432"`
+}
+
+// RouteAllocationStats resume a memória alocada pelas requisições medidas de
+// uma rota que excedeu o orçamento de alocações (ver ALLOC_BUDGET_BYTES)
+type RouteAllocationStats struct {
+	Route       string  `json:"route" example:"POST /api/v1/items"`
+	Samples     int64   `json:"samples" example:"40"`
+	OverBudget  int64   `json:"over_budget" example:"12"`
+	AvgAllocs   float64 `json:"avg_allocs" example:"5120"`
+	AvgBytes    float64 `json:"avg_bytes" example:"2097152"`
+	MaxBytes    uint64  `json:"max_bytes" example:"8388608"`
+	BudgetBytes uint64  `json:"budget_bytes" example:"1048576"`
+}
+
+// AdminOverview agrega as estatísticas operacionais exibidas no painel de operações
+type AdminOverview struct {
+	GeneratedAt      time.Time              `json:"generated_at"`
+	UptimeSeconds    int64                  `json:"uptime_seconds" example:"86400"`
+	Requests         RequestStats           `json:"requests"`
+	InFlightRequests int64                  `json:"in_flight_requests" example:"3"`
+	Jobs             map[string]int         `json:"jobs"`
+	Users            int                    `json:"users" example:"42"`
+	Items            int                    `json:"items" example:"1024"`
+	Dependencies     []DependencyStatus     `json:"dependencies"`
+	RecentErrors     []RequestError         `json:"recent_errors"`
+	Repositories     []OperationStats       `json:"repositories,omitempty"`
+	Services         []OperationStats       `json:"services,omitempty"`
+	OverBudgetRoutes []RouteAllocationStats `json:"over_budget_routes,omitempty"`
+	JobPanics        []JobPanicStats        `json:"job_panics,omitempty"`
+}
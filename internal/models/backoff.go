@@ -0,0 +1,57 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+// Backoff defaults advertised to clients on retryable errors
+const (
+	BackoffStrategyExponential = "exponential"
+	BackoffJitterFull          = "full"
+	BackoffMultiplier          = 2.0
+	BackoffMaxAttempts         = 5
+	BackoffMaxDelay            = 5 * time.Minute
+)
+
+// BackoffPolicy tells clients how to retry a request rejected with 429 or
+// 503: wait RetryAfterSeconds (the Retry-After header) before the first
+// retry, then multiply the delay by Multiplier on every new rejection, up to
+// MaxDelaySeconds, giving up after MaxAttempts. With full jitter, each delay
+// is a random value between zero and the computed delay
+type BackoffPolicy struct {
+	Strategy          string  `json:"strategy" example:"exponential"`
+	RetryAfterSeconds int     `json:"retry_after_seconds" example:"5"`
+	Multiplier        float64 `json:"multiplier" example:"2"`
+	MaxDelaySeconds   int     `json:"max_delay_seconds" example:"300"`
+	MaxAttempts       int     `json:"max_attempts" example:"5"`
+	Jitter            string  `json:"jitter" example:"full"`
+}
+
+// NewBackoffPolicy creates the exponential backoff policy starting at
+// retryAfter (rounded up to whole seconds, at least one)
+func NewBackoffPolicy(retryAfter time.Duration) BackoffPolicy {
+	seconds := max(int(math.Ceil(retryAfter.Seconds())), 1)
+	return BackoffPolicy{
+		Strategy:          BackoffStrategyExponential,
+		RetryAfterSeconds: seconds,
+		Multiplier:        BackoffMultiplier,
+		MaxDelaySeconds:   max(seconds, int(BackoffMaxDelay.Seconds())),
+		MaxAttempts:       BackoffMaxAttempts,
+		Jitter:            BackoffJitterFull,
+	}
+}
+
+// Delay returns the wait before the given retry attempt (1 for the first
+// retry), without jitter
+func (p BackoffPolicy) Delay(attempt int) time.Duration {
+	delay := float64(p.RetryAfterSeconds) * math.Pow(p.Multiplier, float64(max(attempt, 1)-1))
+	return time.Duration(min(delay, float64(p.MaxDelaySeconds))) * time.Second
+}
+
+// WithBackoff adds the retry policy starting at retryAfter to the error
+func (e APIError) WithBackoff(retryAfter time.Duration) APIError {
+	policy := NewBackoffPolicy(retryAfter)
+	e.Backoff = &policy
+	return e
+}
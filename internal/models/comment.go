@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// Comment é um comentário em um item. Respostas apontam para o comentário
+// respondido (ParentID) e pertencem à mesma conversa (ThreadID, o ID do
+// comentário que a iniciou)
+type Comment struct {
+	ID        string
+	ItemID    string
+	ParentID  string
+	ThreadID  string
+	AuthorID  string
+	Body      string
+	Deleted   bool
+	CreatedAt time.Time
+}
+
+// CreateCommentInput representa um novo comentário ou resposta
+type CreateCommentInput struct {
+	Body     string `json:"body" binding:"required,not_blank,max=2000" example:"Ótimo item!"`
+	ParentID string `json:"parent_id,omitempty" example:"5f8d0e6e-6c0a-4f0a-8e0a-6c0a4f0a8e0a"`
+}
+
+// CommentAuthor contém os dados públicos do autor de um comentário
+type CommentAuthor struct {
+	ID        string `json:"id"`
+	Name      string `json:"name" example:"Regular User"`
+	AvatarURL string `json:"avatar_url,omitempty"`
+}
+
+// CommentResponse representa um comentário e suas respostas nas respostas da API.
+// Comentários removidos que têm respostas continuam na conversa, sem autor e texto
+type CommentResponse struct {
+	ID        string            `json:"id"`
+	ItemID    string            `json:"item_id"`
+	ParentID  string            `json:"parent_id,omitempty"`
+	Author    *CommentAuthor    `json:"author,omitempty"`
+	Body      string            `json:"body"`
+	Deleted   bool              `json:"deleted,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	Replies   []CommentResponse `json:"replies"`
+}
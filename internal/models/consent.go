@@ -0,0 +1,67 @@
+package models
+
+import "time"
+
+// Documentos cujo aceite é registrado
+const (
+	ConsentTerms   = "terms"
+	ConsentPrivacy = "privacy"
+)
+
+// Origens do aceite
+const (
+	ConsentSourceRegistration = "registration"
+	ConsentSourceAPI          = "api"
+)
+
+// Consent registra o aceite de uma versão de um documento (termos de uso ou
+// política de privacidade) por um usuário. Os aceites nunca são alterados nem
+// removidos: o histórico serve de evidência para auditorias
+type Consent struct {
+	ID         string    `json:"id" example:"9a8b7c6d-5e4f-4a3b-2c1d-0e9f8a7b6c5d"`
+	UserID     string    `json:"user_id"`
+	Document   string    `json:"document" example:"terms"`
+	Version    string    `json:"version" example:"2024-06-01"`
+	Source     string    `json:"source" example:"api"`
+	IP         string    `json:"ip,omitempty" example:"203.0.113.10"`
+	UserAgent  string    `json:"user_agent,omitempty"`
+	AcceptedAt time.Time `json:"accepted_at"`
+}
+
+// ConsentInput representa o aceite das versões vigentes dos documentos; basta
+// informar os documentos aceitos
+type ConsentInput struct {
+	TermsVersion   string `json:"terms_version" example:"2024-06-01"`
+	PrivacyVersion string `json:"privacy_version" example:"2024-01-15"`
+}
+
+// Versions retorna as versões aceitas, por documento
+func (i ConsentInput) Versions() map[string]string {
+	versions := make(map[string]string, 2)
+	if i.TermsVersion != "" {
+		versions[ConsentTerms] = i.TermsVersion
+	}
+	if i.PrivacyVersion != "" {
+		versions[ConsentPrivacy] = i.PrivacyVersion
+	}
+	return versions
+}
+
+// ConsentDocumentStatus descreve a situação do usuário em relação a um documento
+type ConsentDocumentStatus struct {
+	Document        string     `json:"document" example:"terms"`
+	CurrentVersion  string     `json:"current_version" example:"2024-06-01"`
+	RequiredVersion string     `json:"required_version" example:"2024-06-01"`
+	AcceptedVersion string     `json:"accepted_version,omitempty" example:"2024-01-01"`
+	AcceptedAt      *time.Time `json:"accepted_at,omitempty"`
+
+	// Pending indica que a versão obrigatória não foi aceita: o uso da API
+	// fica bloqueado até o aceite
+	Pending bool `json:"pending"`
+}
+
+// ConsentStatus reúne a situação de cada documento e o histórico de aceites do usuário
+type ConsentStatus struct {
+	Documents []ConsentDocumentStatus `json:"documents"`
+	History   []Consent               `json:"history"`
+}
@@ -6,9 +6,22 @@ import "net/http"
 type APIError struct {
 	Code        int               `json:"-"`                   // HTTP code (not exposed in response)
 	Status      string            `json:"status"`              // Always "error"
+	ErrorCode   string            `json:"code,omitempty"`      // Stable machine-readable code (see GET /api/v1/errors)
 	Message     string            `json:"message"`             // User-friendly message
 	Details     string            `json:"details,omitempty"`   // Technical details (optional)
 	FieldErrors map[string]string `json:"field_errors,omitempty"` // Validation field errors
+	Violations  []FieldViolation  `json:"violations,omitempty"`   // Violated validation rules
+	ErrorID     string            `json:"error_id,omitempty"`     // Identifier of the logged failure, for support requests
+	Resource    string            `json:"resource,omitempty"`     // Path of the related resource (e.g. the existing item on conflicts)
+	AllowedMethods []string       `json:"allowed_methods,omitempty"` // Methods supported by the path, on 405 responses
+	Backoff     *BackoffPolicy    `json:"backoff,omitempty"`      // Retry policy, on 429 and 503 responses (see Retry-After)
+}
+
+// FieldViolation describes a validation rule violated by a request field
+type FieldViolation struct {
+	Field   string `json:"field" example:"name"`
+	Rule    string `json:"rule" example:"min"`
+	Message string `json:"message" example:"Deve ter pelo menos 3 caracteres"`
 }
 
 // WithDetails adds details to the error
@@ -17,35 +30,112 @@ func (e APIError) WithDetails(details string) APIError {
 	return e
 }
 
+// WithErrorID adds the identifier under which the failure was logged
+func (e APIError) WithErrorID(errorID string) APIError {
+	e.ErrorID = errorID
+	return e
+}
+
+// WithResource adds the path of the resource the error refers to
+func (e APIError) WithResource(path string) APIError {
+	e.Resource = path
+	return e
+}
+
+// WithAllowedMethods adds the methods supported by the requested path
+func (e APIError) WithAllowedMethods(methods []string) APIError {
+	e.AllowedMethods = methods
+	return e
+}
+
 // WithFieldErrors adds field validation errors
 func (e APIError) WithFieldErrors(fieldErrors map[string]string) APIError {
 	e.FieldErrors = fieldErrors
 	return e
 }
 
+// WithViolations adds the violated validation rules, also filling FieldErrors
+// with the first message of each field
+func (e APIError) WithViolations(violations []FieldViolation) APIError {
+	e.Violations = violations
+	e.FieldErrors = make(map[string]string, len(violations))
+	for _, v := range violations {
+		if _, exists := e.FieldErrors[v.Field]; !exists {
+			e.FieldErrors[v.Field] = v.Message
+		}
+	}
+	return e
+}
+
 // Common predefined errors
 var (
 	ErrInvalidInput = APIError{
-		Code:    http.StatusBadRequest,
-		Status:  "error",
-		Message: "Invalid input data",
+		Code:      http.StatusBadRequest,
+		Status:    "error",
+		ErrorCode: "INVALID_INPUT",
+		Message:   "Invalid input data",
 	}
 
 	ErrResourceNotFound = APIError{
-		Code:    http.StatusNotFound,
-		Status:  "error",
-		Message: "Resource not found",
+		Code:      http.StatusNotFound,
+		Status:    "error",
+		ErrorCode: "NOT_FOUND",
+		Message:   "Resource not found",
+	}
+
+	ErrRouteNotFound = APIError{
+		Code:      http.StatusNotFound,
+		Status:    "error",
+		ErrorCode: "ROUTE_NOT_FOUND",
+		Message:   "Route not found",
+	}
+
+	ErrMethodNotAllowed = APIError{
+		Code:      http.StatusMethodNotAllowed,
+		Status:    "error",
+		ErrorCode: "METHOD_NOT_ALLOWED",
+		Message:   "Method not allowed",
 	}
 
 	ErrUnauthorized = APIError{
-		Code:    http.StatusUnauthorized,
-		Status:  "error",
-		Message: "Authentication required",
+		Code:      http.StatusUnauthorized,
+		Status:    "error",
+		ErrorCode: "UNAUTHORIZED",
+		Message:   "Authentication required",
+	}
+
+	ErrRequestTimeout = APIError{
+		Code:      http.StatusRequestTimeout,
+		Status:    "error",
+		ErrorCode: "REQUEST_TIMEOUT",
+		Message:   "Request timed out",
+	}
+
+	ErrPayloadTooLarge = APIError{
+		Code:      http.StatusRequestEntityTooLarge,
+		Status:    "error",
+		ErrorCode: "PAYLOAD_TOO_LARGE",
+		Message:   "Request body too large",
+	}
+
+	ErrQuotaExceeded = APIError{
+		Code:      http.StatusTooManyRequests,
+		Status:    "error",
+		ErrorCode: "QUOTA_EXCEEDED",
+		Message:   "Request quota exceeded",
+	}
+
+	ErrDependencyDown = APIError{
+		Code:      http.StatusServiceUnavailable,
+		Status:    "error",
+		ErrorCode: "DEPENDENCY_DOWN",
+		Message:   "A required external service is unavailable",
 	}
 
 	ErrInternalServer = APIError{
-		Code:    http.StatusInternalServerError,
-		Status:  "error",
-		Message: "Internal server error",
+		Code:      http.StatusInternalServerError,
+		Status:    "error",
+		ErrorCode: "INTERNAL_ERROR",
+		Message:   "Internal server error",
 	}
 )
\ No newline at end of file
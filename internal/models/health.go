@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"callable-api/internal/version"
+)
+
+// HealthReport é a resposta detalhada do health check (?verbose=true)
+type HealthReport struct {
+	Status        string             `json:"status" example:"available"`
+	Message       string             `json:"message" example:"Callable API is up and running"`
+	Version       version.Info       `json:"version"`
+	StartedAt     time.Time          `json:"started_at"`
+	UptimeSeconds int64              `json:"uptime_seconds" example:"86400"`
+	Dependencies  []DependencyStatus `json:"dependencies"`
+}
+
+// DrainStatus indica se a instância está em dreno manual (retirada do
+// balanceador pela verificação de prontidão) e desde quando
+type DrainStatus struct {
+	Draining bool       `json:"draining" example:"true"`
+	Since    *time.Time `json:"since,omitempty"`
+}
+
+// ServiceState é o estado de uma integração com o GCP: up, degraded
+// (configurada, mas falhando) ou disabled (não configurada)
+type ServiceState struct {
+	Name  string     `json:"name" example:"cloud_storage"`
+	State string     `json:"state" example:"degraded"`
+	Error string     `json:"error,omitempty"`
+	Since *time.Time `json:"since,omitempty"`
+}
+
+// ReadinessReport é a resposta da verificação de prontidão. Integrações
+// degradadas não tiram a instância do balanceador: apenas as funcionalidades
+// que dependem delas respondem 503
+type ReadinessReport struct {
+	Status       string         `json:"status" example:"ready"`
+	Draining     bool           `json:"draining" example:"false"`
+	Since        *time.Time     `json:"since,omitempty"`
+	Dependencies []ServiceState `json:"dependencies,omitempty"`
+}
@@ -0,0 +1,11 @@
+package models
+
+// BulkItemResult é o resultado da criação de um dos itens de
+// POST /api/v1/data/bulk, na ordem do array enviado
+type BulkItemResult struct {
+	Index  int    `json:"index" example:"0"`
+	Status int    `json:"status" example:"201"`
+	Item   *Item  `json:"item,omitempty"`
+	Code   string `json:"code,omitempty" example:"DUPLICATE_ITEM"`
+	Error  string `json:"error,omitempty" example:"Já existe um item equivalente"`
+}
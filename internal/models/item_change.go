@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// Operações registradas no log de alterações dos itens
+const (
+	ItemChangeCreated = "created"
+	ItemChangeUpdated = "updated"
+	ItemChangeRemoved = "removed"
+)
+
+// ItemChange é uma entrada do log de alterações dos itens. A sequência é
+// crescente e serve de cursor para a leitura seguinte
+type ItemChange struct {
+	Seq        int64     `json:"seq" example:"42"`
+	Operation  string    `json:"op" example:"updated"`
+	ItemID     string    `json:"item_id" example:"1"`
+	OwnerID    string    `json:"-"`
+	OrgID      string    `json:"-"`
+	ViewerID   string    `json:"-"` // usuário que perdeu o acesso (remoções)
+	ViewerRole string    `json:"-"` // papel que perdeu o acesso (remoções)
+	EventID    string    `json:"event_id"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// RemovedFor indica se a remoção foi causada pela revogação de um
+// compartilhamento com o usuário ou com o seu papel
+func (c ItemChange) RemovedFor(viewer Viewer) bool {
+	return c.Operation == ItemChangeRemoved &&
+		((c.ViewerID != "" && c.ViewerID == viewer.UserID) || (c.ViewerRole != "" && c.ViewerRole == viewer.Role))
+}
+
+// ItemChangePage é uma leitura do log de alterações: as alterações visíveis
+// ao usuário e o cursor para continuar a sincronização
+type ItemChangePage struct {
+	Changes    []ItemChange `json:"changes"`
+	NextCursor string       `json:"next_cursor" example:"42"`
+	HasMore    bool         `json:"has_more"`
+}
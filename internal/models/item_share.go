@@ -0,0 +1,63 @@
+package models
+
+import "time"
+
+// Níveis de acesso concedidos pelo compartilhamento de um item
+const (
+	ShareLevelRead  = "read"
+	ShareLevelWrite = "write"
+)
+
+// ItemShare concede a um usuário ou a todos os usuários de um papel acesso a um item
+type ItemShare struct {
+	ID        string    `json:"id" example:"5f8d0e6e-6c0a-4f0a-8e0a-6c0a4f0a8e0a"`
+	ItemID    string    `json:"item_id" example:"1"`
+	UserID    string    `json:"user_id,omitempty" example:"1f0c2a4e-3b5d-4c6e-8f0a-1b2c3d4e5f6a"`
+	Role      string    `json:"role,omitempty" example:"user"`
+	Level     string    `json:"level" example:"read"`
+	GrantedBy string    `json:"granted_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Grants indica se o compartilhamento concede ao menos o nível informado
+func (s *ItemShare) Grants(level string) bool {
+	return s.Level == ShareLevelWrite || level == ShareLevelRead
+}
+
+// ShareItemInput representa o compartilhamento de um item com um usuário ou papel.
+// Compartilhar novamente com o mesmo destinatário altera o nível de acesso
+type ShareItemInput struct {
+	UserID string `json:"user_id" binding:"required_without=Role,excluded_with=Role" example:"1f0c2a4e-3b5d-4c6e-8f0a-1b2c3d4e5f6a"`
+	Role   string `json:"role" binding:"omitempty,oneof=admin user" example:"user"`
+	Level  string `json:"level" binding:"required,oneof=read write" example:"write"`
+}
+
+// Viewer identifica quem acessa os itens; o valor zero representa um cliente anônimo.
+// OrgID é a organização ativa da requisição, já verificada como uma das
+// organizações do usuário (ver middleware.OrganizationMiddleware)
+type Viewer struct {
+	UserID string
+	Role   string
+	OrgID  string
+}
+
+// IsAdmin indica se o usuário é administrador
+func (v Viewer) IsAdmin() bool {
+	return v.Role == "admin"
+}
+
+// ItemAccess define quais itens um usuário pode ler: itens sem dono (públicos),
+// os próprios itens, os compartilhados com ele e os da organização ativa.
+// Administradores leem todos
+type ItemAccess struct {
+	All       bool
+	UserID    string
+	OrgID     string
+	SharedIDs map[string]bool
+}
+
+// CanRead indica se o item é visível
+func (a ItemAccess) CanRead(item *Item) bool {
+	return a.All || item.OwnerID == "" || (a.UserID != "" && item.OwnerID == a.UserID) ||
+		(a.OrgID != "" && item.OrgID == a.OrgID) || a.SharedIDs[item.ID]
+}
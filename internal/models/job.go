@@ -0,0 +1,125 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Estados de um job em segundo plano
+const (
+	JobStateScheduled = "scheduled"
+	JobStatePending   = "pending"
+	JobStateRunning   = "running"
+	JobStateCompleted = "completed"
+	JobStateFailed    = "failed"
+	JobStateTimedOut  = "timed_out" // excedeu o prazo de execução (ver jobs.Config.Timeout)
+)
+
+// JobStatus representa o estado de um job em segundo plano, consultado pelo
+// cliente em GET /api/v1/jobs/{id}
+type JobStatus struct {
+	ID         string      `json:"id" example:"0b6f1a5e-3c1d-4f7b-9a57-2f0c1f4f8b11"`
+	Type       string      `json:"type" example:"item.create"`
+	State      string      `json:"state" example:"pending"`
+	UserID     string      `json:"-"`
+	Result     interface{} `json:"result,omitempty"`
+	Error      string      `json:"error,omitempty"`
+	ErrorCode  string      `json:"error_code,omitempty" example:"DUPLICATE_ITEM"`
+	Attempts   int         `json:"attempts" example:"1"`
+	CreatedAt  time.Time   `json:"created_at"`
+	RunAt      *time.Time  `json:"run_at,omitempty"`
+	StartedAt  *time.Time  `json:"started_at,omitempty"`
+	FinishedAt *time.Time  `json:"finished_at,omitempty"`
+	UpdatedAt  time.Time   `json:"updated_at"`
+
+	// ParentID identifica o workflow de uma etapa; Steps, as etapas de um workflow
+	ParentID string    `json:"parent_id,omitempty"`
+	Steps    []JobStep `json:"steps,omitempty"`
+
+	// Input é a entrada dos jobs de tipos registrados, que podem ser retomados
+	// após a reinicialização do servidor
+	Input json.RawMessage `json:"-"`
+}
+
+// JobLogEntry é uma linha do log de execução de um job
+type JobLogEntry struct {
+	Time    time.Time              `json:"time"`
+	Level   string                 `json:"level" example:"warn"`
+	Message string                 `json:"message" example:"Job falhou"`
+	Fields  map[string]interface{} `json:"fields,omitempty"`
+}
+
+// JobLogs é o log de execução de um job. Apenas as linhas mais recentes são
+// mantidas; Dropped conta as descartadas pelo limite
+type JobLogs struct {
+	JobID   string        `json:"job_id" example:"0b6f1a5e-3c1d-4f7b-9a57-2f0c1f4f8b11"`
+	Entries []JobLogEntry `json:"entries"`
+	Dropped int           `json:"dropped,omitempty" example:"12"`
+}
+
+// JobArtifact é o resultado de um job gravado no armazenamento de artefatos
+// (exportações, relatórios e resultados grandes), baixado em URL. Size é o
+// tamanho do conteúdo original, sem compressão
+type JobArtifact struct {
+	URL         string `json:"url" example:"/api/v1/jobs/0b6f1a5e-3c1d-4f7b-9a57-2f0c1f4f8b11/artifact"`
+	Name        string `json:"name" example:"items.csv"`
+	ContentType string `json:"content_type" example:"text/csv"`
+	Size        int    `json:"size" example:"1048576"`
+
+	// ContentEncoding é "gzip" quando o artefato foi gravado comprimido. O
+	// download pela API descomprime para quem não envia Accept-Encoding: gzip;
+	// as URLs assinadas do Cloud Storage entregam o arquivo comprimido
+	ContentEncoding string `json:"content_encoding,omitempty" example:"gzip"`
+}
+
+// JobStep é uma etapa de um workflow, executada como um job próprio (JobID)
+// depois que a etapa anterior é concluída
+type JobStep struct {
+	Type  string `json:"type" example:"validate"`
+	JobID string `json:"job_id,omitempty" example:"5e2b4c1a-8f0d-4a3e-b6a1-0c9d7e2f1a34"`
+	State string `json:"state" example:"pending"`
+}
+
+// JobStats resume os jobs para a administração: quantos existem em cada
+// estado, desde quando o job pendente mais antigo aguarda e a duração média
+// de execução dos jobs terminados
+type JobStats struct {
+	Total             int            `json:"total" example:"42"`
+	ByState           map[string]int `json:"by_state"`
+	OldestPendingAt   *time.Time     `json:"oldest_pending_at,omitempty"`
+	AverageDurationMs float64        `json:"average_duration_ms" example:"125.5"`
+}
+
+// JobCleanup é o resultado da remoção dos jobs terminados antes de Before
+type JobCleanup struct {
+	Deleted int       `json:"deleted" example:"17"`
+	Before  time.Time `json:"before"`
+}
+
+// JobPanicStats resume os panics de um tipo de job e o estado do seu circuit
+// breaker: tipos com panics repetidos ficam desativados até DisabledUntil
+type JobPanicStats struct {
+	Type          string           `json:"type" example:"item.create"`
+	Total         int64            `json:"total" example:"3"`
+	ByClass       map[string]int64 `json:"by_class"`
+	LastPanic     string           `json:"last_panic,omitempty" example:"runtime error: invalid memory address or nil pointer dereference"`
+	LastPanicAt   *time.Time       `json:"last_panic_at,omitempty"`
+	DisabledUntil *time.Time       `json:"disabled_until,omitempty"`
+}
+
+// Finished indica se o job terminou, com sucesso ou não
+func (j *JobStatus) Finished() bool {
+	return j.State == JobStateCompleted || j.Unsuccessful()
+}
+
+// Unsuccessful indica se o job terminou sem sucesso: com falha ou por exceder
+// o prazo de execução
+func (j *JobStatus) Unsuccessful() bool {
+	return j.State == JobStateFailed || j.State == JobStateTimedOut
+}
+
+// VisibleTo indica se o usuário pode consultar o job: jobs de clientes
+// anônimos são acessíveis a quem conhece o ID
+func (j *JobStatus) VisibleTo(viewer Viewer) bool {
+	return j.UserID == "" || j.UserID == viewer.UserID || viewer.IsAdmin()
+}
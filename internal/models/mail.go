@@ -0,0 +1,11 @@
+package models
+
+// MailPreview é um template de email renderizado com os dados de exemplo
+type MailPreview struct {
+	Template string `json:"template" example:"password_reset"`
+	Version  int    `json:"version" example:"2"`
+	Versions []int  `json:"versions" example:"1,2"`
+	Subject  string `json:"subject" example:"Redefinição de senha"`
+	TextBody string `json:"text_body"`
+	HTMLBody string `json:"html_body,omitempty"`
+}
@@ -1,11 +1,14 @@
 package models
 
 import (
-	"fmt"
-	"strings"
 	"time"
+
+	"callable-api/internal/validation"
 )
 
+// RequestIDHeader carries the request identifier in requests and responses
+const RequestIDHeader = "X-Request-ID"
+
 // Response represents the standard API response format
 type Response struct {
 	Status  string      `json:"status" example:"success"`
@@ -62,7 +65,13 @@ type Item struct {
 	Value       string `json:"value" example:"ABC123"`
 	Description string `json:"description,omitempty" example:"Detailed item description"`
 	Email       string `json:"email,omitempty" example:"user@example.com"`
+	OwnerID     string `json:"owner_id,omitempty" example:"1f0c2a4e-3b5d-4c6e-8f0a-1b2c3d4e5f6a"` // empty for public items
+	OrgID       string `json:"org_id,omitempty" example:"3c1f7a52-9d4e-4b8a-a1f2-6e5d4c3b2a10"`   // organization the item belongs to, if any
 	CreatedAt   string `json:"created_at" example:"2023-05-22T14:56:32Z"`
+
+	// SchemaVersion is the format version of the stored item (see
+	// repository.ItemSchema); it is not part of the API
+	SchemaVersion int `json:"-"`
 }
 
 // HasDescription returns true if the item has a non-empty description
@@ -75,42 +84,38 @@ func (i *Item) HasEmail() bool {
 	return i.Email != ""
 }
 
+// Catalog returns a copy of the item with only the public catalog fields,
+// served to anonymous clients
+func (i Item) Catalog() Item {
+	return Item{ID: i.ID, Name: i.Name, Value: i.Value, CreatedAt: i.CreatedAt}
+}
+
 // GetCreatedAtTime attempts to parse the CreatedAt field as a time.Time
 func (i *Item) GetCreatedAtTime() (time.Time, error) {
 	return time.Parse(time.RFC3339, i.CreatedAt)
 }
 
-// InputData represents API input data with enhanced validation
+// InputData represents API input data with enhanced validation.
+// As tags `binding` são a única fonte das regras: valem para o binding do Gin,
+// para Validate e para o ItemService (ver pacote validation)
 type InputData struct {
-	Name        string `json:"name" binding:"required,min=3,max=50" example:"Item Name"`
+	Name        string `json:"name" binding:"required,not_blank,min=3,max=50" example:"Item Name"`
 	Value       string `json:"value" binding:"required,min=1" example:"123ABC"`
 	Description string `json:"description" binding:"omitempty,max=200" example:"Detailed item description"`
-	Email       string `json:"email" binding:"omitempty,email" example:"user@example.com"`
-	CreatedAt   string `json:"created_at" binding:"omitempty,datetime=2006-01-02T15:04:05Z07:00" example:"2023-05-22T14:56:32Z"`
+	Email       string `json:"email" binding:"required,valid_email" example:"user@example.com"`
+	CreatedAt   string `json:"created_at" binding:"omitempty,rfc3339" example:"2023-05-22T14:56:32Z"`
+
+	// OwnerID is set by the service from the authenticated user, never from the request body
+	OwnerID string `json:"-"`
+
+	// OrgID is set by the service from the active organization of the request
+	OrgID string `json:"-"`
+
+	// Force skips the duplicate check on creation (admins only); set by the handler from ?force=true
+	Force bool `json:"-"`
 }
 
-// Validate performs basic validation on the input data
+// Validate validates the input data using the rules declared in the binding tags
 func (i *InputData) Validate() error {
-	if len(i.Name) < 3 || len(i.Name) > 50 {
-		return fmt.Errorf("name must be between 3 and 50 characters")
-	}
-	if i.Value == "" {
-		return fmt.Errorf("value is required")
-	}
-	if len(i.Description) > 200 {
-		return fmt.Errorf("description must not exceed 200 characters")
-	}
-	if i.Email != "" {
-		// Basic email validation
-		if !strings.Contains(i.Email, "@") || !strings.Contains(i.Email, ".") {
-			return fmt.Errorf("invalid email format")
-		}
-	}
-	if i.CreatedAt != "" {
-		_, err := time.Parse(time.RFC3339, i.CreatedAt)
-		if err != nil {
-			return fmt.Errorf("invalid date format (should be RFC3339): %v", err)
-		}
-	}
-	return nil
-}
\ No newline at end of file
+	return validation.Validate(i)
+}
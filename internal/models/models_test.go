@@ -334,6 +334,36 @@ func TestInputDataValidate(t *testing.T) {
 		}
 		err := input.Validate()
 		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "date")
+		assert.Contains(t, err.Error(), "created_at")
 	})
-}
\ No newline at end of file
+}
+func TestBackoffPolicy(t *testing.T) {
+	policy := models.NewBackoffPolicy(1500 * time.Millisecond)
+	assert.Equal(t, 2, policy.RetryAfterSeconds)
+	assert.Equal(t, 2*time.Second, policy.Delay(1))
+	assert.Equal(t, 8*time.Second, policy.Delay(3))
+	assert.Equal(t, models.BackoffMaxDelay, policy.Delay(20))
+
+	// Intervalos nulos ou maiores que o teto continuam válidos
+	assert.Equal(t, 1, models.NewBackoffPolicy(0).RetryAfterSeconds)
+	long := models.NewBackoffPolicy(10 * time.Minute)
+	assert.Equal(t, 600, long.MaxDelaySeconds)
+	assert.Equal(t, 10*time.Minute, long.Delay(2))
+
+	apiErr := models.ErrQuotaExceeded.WithBackoff(5 * time.Second)
+	body, err := json.Marshal(apiErr)
+	assert.NoError(t, err)
+	assert.Contains(t, string(body), `"backoff":{"strategy":"exponential","retry_after_seconds":5`)
+	assert.Nil(t, models.ErrQuotaExceeded.Backoff)
+}
+
+func TestRouteScope(t *testing.T) {
+	assert.Equal(t, "data:read", models.RouteScope("GET", "/api/v1/data/:id"))
+	assert.Equal(t, "auth:write", models.RouteScope("POST", "/api/v1/auth/token/narrow"))
+	assert.Equal(t, "", models.RouteScope("GET", "/health"))
+
+	assert.True(t, models.ScopeAllows(nil, "admin:write"))
+	assert.True(t, models.ScopeAllows([]string{"data:write"}, "data:read"))
+	assert.False(t, models.ScopeAllows([]string{"data:read"}, "data:write"))
+	assert.False(t, models.ScopeAllows([]string{}, "data:read"))
+}
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Tipos de evento que podem gerar notificações
+const (
+	NotificationEventJobCompleted    = "job.completed"
+	NotificationEventItemShared      = "item.shared"
+	NotificationEventJobTypeDisabled = "job.type_disabled"
+)
+
+// Canais de entrega de notificações
+const (
+	NotificationChannelEmail   = "email"
+	NotificationChannelWebhook = "webhook"
+	NotificationChannelInApp   = "in_app"
+)
+
+// NotificationEventTypes lista os tipos de evento suportados
+var NotificationEventTypes = []string{
+	NotificationEventJobCompleted,
+	NotificationEventItemShared,
+	NotificationEventJobTypeDisabled,
+}
+
+// NotificationChannels lista os canais de entrega suportados
+var NotificationChannels = []string{
+	NotificationChannelEmail,
+	NotificationChannelWebhook,
+	NotificationChannelInApp,
+}
+
+// NotificationPreferences representa as preferências de notificação de um usuário:
+// para cada tipo de evento, os canais pelos quais ele deseja ser avisado
+type NotificationPreferences struct {
+	UserID     string              `json:"-"`
+	Channels   map[string][]string `json:"channels"`
+	WebhookURL string              `json:"webhook_url,omitempty" example:"https://example.com/hooks/callable"`
+	WebhookID  string              `json:"webhook_id,omitempty" example:"5f0c6a52-8d1e-4c36-9b0e-2f1a7c3d9e41"`
+	UpdatedAt  time.Time           `json:"updated_at"`
+}
+
+// WantsChannel retorna true se o usuário deseja receber o evento pelo canal informado
+func (p *NotificationPreferences) WantsChannel(eventType, channel string) bool {
+	for _, c := range p.Channels[eventType] {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultNotificationPreferences retorna as preferências aplicadas a usuários que
+// ainda não configuraram as suas
+func DefaultNotificationPreferences(userID string) *NotificationPreferences {
+	return &NotificationPreferences{
+		UserID: userID,
+		Channels: map[string][]string{
+			NotificationEventJobCompleted:    {NotificationChannelInApp},
+			NotificationEventItemShared:      {NotificationChannelInApp, NotificationChannelEmail},
+			NotificationEventJobTypeDisabled: {NotificationChannelInApp, NotificationChannelEmail},
+		},
+	}
+}
+
+// UpdateNotificationPreferencesInput representa os dados para atualizar as preferências
+type UpdateNotificationPreferencesInput struct {
+	Channels   map[string][]string `json:"channels" binding:"required"`
+	WebhookURL string              `json:"webhook_url" binding:"omitempty,url"`
+}
+
+// Notification representa uma notificação entregue a um usuário
+type Notification struct {
+	ID        string                 `json:"id"`
+	UserID    string                 `json:"-"`
+	Type      string                 `json:"type" example:"job.completed"`
+	Title     string                 `json:"title"`
+	Body      string                 `json:"body"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+	Read      bool                   `json:"read"`
+	ReadAt    *time.Time             `json:"read_at,omitempty"`
+}
+
+// NotificationsRead é o resultado da marcação de todas as notificações como lidas
+type NotificationsRead struct {
+	Updated int `json:"updated" example:"3"`
+}
+
+// Resultados da entrega de um evento ao webhook do usuário
+const (
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// WebhookDelivery registra a entrega de um evento ao webhook do usuário. O
+// payload é mantido durante a janela de retenção, para que o evento possa ser
+// reentregue a quem ficou fora do ar
+type WebhookDelivery struct {
+	EventID       string          `json:"event_id" example:"0b6f3c1e-2a4d-4f7e-9c8b-1d2e3f4a5b6c"`
+	WebhookID     string          `json:"webhook_id"`
+	UserID        string          `json:"-"`
+	Type          string          `json:"type" example:"job.completed"`
+	Payload       json.RawMessage `json:"payload" swaggertype:"object"`
+	Status        string          `json:"status" example:"failed"`
+	Attempts      int             `json:"attempts" example:"1"`
+	LastError     string          `json:"last_error,omitempty" example:"webhook respondeu com status 503"`
+	CreatedAt     time.Time       `json:"created_at"`
+	LastAttemptAt time.Time       `json:"last_attempt_at"`
+	ExpiresAt     time.Time       `json:"expires_at"`
+}
@@ -0,0 +1,92 @@
+package models
+
+import "time"
+
+// Papéis dos membros de uma organização
+const (
+	OrgRoleOwner  = "owner"
+	OrgRoleMember = "member"
+)
+
+// OrganizationHeader é o cabeçalho que ativa uma organização na requisição.
+// Sem ele, vale o claim OrganizationClaim do token, se houver
+const OrganizationHeader = "X-Organization-ID"
+
+// OrganizationClaim é o claim do JWT com a organização ativa por padrão
+const OrganizationClaim = "org_id"
+
+// Organization agrupa usuários (membros) e itens de uma mesma empresa ou equipe
+type Organization struct {
+	ID        string    `json:"id" example:"3c1f7a52-9d4e-4b8a-a1f2-6e5d4c3b2a10"`
+	Name      string    `json:"name" example:"Acme Ltda"`
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Role é o papel do usuário autenticado na organização, preenchido nas
+	// listagens das organizações do usuário
+	Role string `json:"role,omitempty" example:"owner"`
+}
+
+// Membership vincula um usuário a uma organização com um papel
+type Membership struct {
+	OrgID    string    `json:"org_id"`
+	UserID   string    `json:"user_id"`
+	Role     string    `json:"role" example:"member"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+// IsOwner indica se o membro é dono da organização
+func (m *Membership) IsOwner() bool {
+	return m.Role == OrgRoleOwner
+}
+
+// Invitation convida um email a entrar na organização ou, sem organização
+// (convites emitidos por administradores), a se cadastrar na API. O token do
+// convite também serve de código de convite no registro. Apenas o hash do
+// token é gravado; o token é retornado uma única vez, na criação do convite
+type Invitation struct {
+	ID         string     `json:"id" example:"7b0e2d4c-1a3f-4e5d-9c8b-0a1b2c3d4e5f"`
+	OrgID      string     `json:"org_id,omitempty"`
+	Email      string     `json:"email" example:"colega@example.com"`
+	Role       string     `json:"role,omitempty" example:"user"`
+	OrgRole    string     `json:"org_role,omitempty" example:"member"`
+	InvitedBy  string     `json:"invited_by"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	AcceptedAt *time.Time `json:"accepted_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+
+	Token     string `json:"token,omitempty"`
+	TokenHash string `json:"-"`
+}
+
+// Pending indica se o convite ainda pode ser aceito
+func (i *Invitation) Pending(now time.Time) bool {
+	return i.AcceptedAt == nil && now.Before(i.ExpiresAt)
+}
+
+// CreateOrganizationInput representa a criação de uma organização; quem a
+// cria se torna o seu primeiro dono
+type CreateOrganizationInput struct {
+	Name string `json:"name" binding:"required,not_blank,min=3,max=80" example:"Acme Ltda"`
+}
+
+// InviteMemberInput representa o convite de um email para a organização. Sem
+// expires_at, o convite vale pelo prazo padrão da configuração
+type InviteMemberInput struct {
+	Email     string     `json:"email" binding:"required,valid_email" example:"colega@example.com"`
+	Role      string     `json:"role" binding:"omitempty,oneof=owner member" example:"member"`
+	ExpiresAt *time.Time `json:"expires_at" example:"2024-01-04T12:00:00Z"`
+}
+
+// CreateInvitationInput representa o convite de cadastro emitido por um
+// administrador: o email convidado, o papel da conta e a validade opcional
+type CreateInvitationInput struct {
+	Email     string     `json:"email" binding:"required,valid_email" example:"novo@example.com"`
+	Role      string     `json:"role" binding:"omitempty,oneof=user admin" example:"user"`
+	ExpiresAt *time.Time `json:"expires_at" example:"2024-01-04T12:00:00Z"`
+}
+
+// AcceptInvitationInput representa o aceite de um convite pelo usuário autenticado
+type AcceptInvitationInput struct {
+	Token string `json:"token" binding:"required,not_blank"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// QuotaUsage descreve o consumo da cota de requisições de um cliente na janela atual
+type QuotaUsage struct {
+	Limit         int       `json:"limit" example:"1000"`
+	Used          int       `json:"used" example:"42"`
+	Remaining     int       `json:"remaining" example:"958"`
+	WindowSeconds int       `json:"window_seconds" example:"3600"`
+	ResetAt       time.Time `json:"reset_at" example:"2023-05-22T15:00:00Z"`
+}
+
+// Exceeded indica se a cota da janela atual foi esgotada
+func (u *QuotaUsage) Exceeded() bool {
+	return u.Limit > 0 && u.Used > u.Limit
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// Recording é um par requisição/resposta capturado para reprodução, com dados
+// sensíveis removidos
+type Recording struct {
+	ID              string              `json:"id" example:"5f8d0e6e-6c0a-4f0a-8e0a-6c0a4f0a8e0a"`
+	Method          string              `json:"method" example:"POST"`
+	Route           string              `json:"route" example:"/api/v1/data"`
+	URL             string              `json:"url" example:"/api/v1/data?page=1"`
+	RequestHeaders  map[string][]string `json:"request_headers,omitempty"`
+	RequestBody     string              `json:"request_body,omitempty"`
+	Status          int                 `json:"status" example:"201"`
+	ResponseHeaders map[string][]string `json:"response_headers,omitempty"`
+	ResponseBody    string              `json:"response_body,omitempty"`
+	DurationMs      int64               `json:"duration_ms" example:"12"`
+	RecordedAt      time.Time           `json:"recorded_at"`
+}
+
+// RecordingSettings define se a gravação está ativa e quais rotas são gravadas
+type RecordingSettings struct {
+	Enabled bool     `json:"enabled"`
+	Routes  []string `json:"routes" example:"POST /api/v1/data"`
+}
+
+// ReplayResult compara a resposta gravada com a resposta do código atual
+type ReplayResult struct {
+	RecordingID    string `json:"recording_id"`
+	OriginalStatus int    `json:"original_status"`
+	ReplayStatus   int    `json:"replay_status"`
+	OriginalBody   string `json:"original_body,omitempty"`
+	ReplayBody     string `json:"replay_body,omitempty"`
+	Matches        bool   `json:"matches"`
+}
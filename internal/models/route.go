@@ -0,0 +1,15 @@
+package models
+
+// RouteSecurity descreve os requisitos de segurança de uma rota, para revisão
+type RouteSecurity struct {
+	Method      string   `json:"method" example:"POST"`
+	Path        string   `json:"path" example:"/api/v1/data"`
+	Auth        string   `json:"auth" example:"jwt"`
+	Roles       []string `json:"roles,omitempty" example:"admin"`
+	RateClass   string   `json:"rate_class" example:"standard"`
+	Strict      bool     `json:"strict,omitempty" example:"true"`
+	CORS        string   `json:"cors" example:"public"`
+	Anonymous   bool     `json:"anonymous,omitempty" example:"true"`
+	Scope       string   `json:"scope,omitempty" example:"data:write"` // escopo exigido dos tokens restritos
+	Description string   `json:"description,omitempty" example:"Cria um novo item"`
+}
@@ -0,0 +1,16 @@
+package models
+
+// SchemaMigration é o resultado do job de migração dos registros para a
+// versão atual do formato de cada modelo
+type SchemaMigration struct {
+	Items SchemaMigrationCount `json:"items"`
+	Users SchemaMigrationCount `json:"users"`
+}
+
+// SchemaMigrationCount resume a migração de um modelo: a versão atual, os
+// registros percorridos e os que estavam numa versão anterior e foram migrados
+type SchemaMigrationCount struct {
+	Version  int `json:"version" example:"1"`
+	Scanned  int `json:"scanned" example:"120"`
+	Migrated int `json:"migrated" example:"15"`
+}
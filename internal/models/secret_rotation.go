@@ -0,0 +1,12 @@
+package models
+
+// SecretRotation é o resultado do processamento de uma notificação do Secret
+// Manager: o segredo afetado, se foi descartado do cache e quantos
+// consumidores recarregaram a nova versão
+type SecretRotation struct {
+	Secret      string `json:"secret" example:"jwt-secret"`
+	EventType   string `json:"event_type" example:"SECRET_ROTATE"`
+	MessageID   string `json:"message_id,omitempty" example:"1234567890"`
+	Invalidated bool   `json:"invalidated" example:"true"`
+	Reloaded    int    `json:"reloaded" example:"1"`
+}
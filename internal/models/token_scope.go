@@ -0,0 +1,87 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// ScopedTokenPrefix identifica os tokens restritos (ver TokenGrant), que não
+// são JWTs: o servidor guarda apenas o hash e os escopos concedidos
+const ScopedTokenPrefix = "scoped_"
+
+// Níveis de acesso dos escopos. O escopo de uma rota é "<recurso>:<acesso>",
+// com o recurso igual ao primeiro segmento após /api/v1 (ex.: data:read para
+// GET /api/v1/data/:id). O acesso write inclui o read do mesmo recurso
+const (
+	ScopeRead  = "read"
+	ScopeWrite = "write"
+)
+
+// RouteScope retorna o escopo exigido pela rota, ou vazio para as rotas fora
+// de /api/v1
+func RouteScope(method, path string) string {
+	rest, found := strings.CutPrefix(path, "/api/v1/")
+	if !found {
+		return ""
+	}
+	resource, _, _ := strings.Cut(rest, "/")
+	if resource == "" {
+		return ""
+	}
+
+	switch method {
+	case "GET", "HEAD", "OPTIONS":
+		return resource + ":" + ScopeRead
+	}
+	return resource + ":" + ScopeWrite
+}
+
+// ValidScope indica se o escopo tem o formato "<recurso>:read" ou "<recurso>:write"
+func ValidScope(scope string) bool {
+	resource, access, found := strings.Cut(scope, ":")
+	return found && resource != "" && !strings.ContainsAny(resource, "/:") && (access == ScopeRead || access == ScopeWrite)
+}
+
+// ScopeAllows indica se os escopos concedidos atendem ao escopo exigido.
+// granted nil representa um token sem restrições
+func ScopeAllows(granted []string, required string) bool {
+	if granted == nil || required == "" {
+		return true
+	}
+	resource, access, _ := strings.Cut(required, ":")
+	for _, scope := range granted {
+		if scope == required || (access == ScopeRead && scope == resource+":"+ScopeWrite) {
+			return true
+		}
+	}
+	return false
+}
+
+// TokenGrant descreve o token apresentado na requisição: o usuário, os
+// escopos (nil para os tokens de acesso comuns, sem restrições) e a validade
+type TokenGrant struct {
+	UserID    string
+	Scopes    []string
+	ExpiresAt time.Time
+
+	// Dados atuais do usuário, preenchidos na autenticação de tokens restritos
+	Email string
+	Name  string
+	Role  string
+}
+
+// NarrowTokenInput pede um token restrito a parte dos escopos do token atual
+type NarrowTokenInput struct {
+	Scopes    []string `json:"scopes" binding:"required,min=1,dive,required" example:"data:read"`
+	ExpiresIn int      `json:"expires_in,omitempty" binding:"omitempty,min=1" example:"300"` // validade desejada, em segundos
+}
+
+// ScopedToken é o token restrito emitido por POST /api/v1/auth/token/narrow.
+// Não pode ser renovado: ao expirar, o cliente pede um novo ao dono do token original
+type ScopedToken struct {
+	AccessToken string    `json:"access_token" example:"scoped_5f2b9c..."`
+	TokenType   string    `json:"token_type" example:"Bearer"`
+	Scopes      []string  `json:"scopes" example:"data:read"`
+	ExpiresIn   int       `json:"expires_in" example:"300"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strings"
 	"time"
 )
 
@@ -9,29 +10,153 @@ type User struct {
 	ID        string    `json:"id"`
 	Email     string    `json:"email"`
 	Name      string    `json:"name"`
-	Password  string    `json:"-"` // Nunca exposta nas respostas
+	Password  string    `json:"-" redact:"true"` // Nunca exposta nas respostas
 	Role      string    `json:"role"`
+	AvatarURL string    `json:"avatar_url,omitempty"`
+	Bio       string    `json:"bio,omitempty"`
+	Phone     string    `json:"phone,omitempty"`
+	Locale    string    `json:"locale,omitempty"`
+	Timezone  string    `json:"timezone,omitempty"`
+	PageSize  int       `json:"page_size,omitempty"` // 0 usa o tamanho padrão da API
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+
+	// Estado da conta, controlado pelos administradores
+	Disabled               bool      `json:"disabled"`
+	PasswordResetRequired  bool      `json:"password_reset_required"`
+	PasswordResetTokenHash string    `json:"-" redact:"true"`
+	PasswordResetExpiresAt time.Time `json:"-"`
+
+	// Identificador do usuário no provedor de identidade que o provisionou (SCIM)
+	ExternalID string `json:"-"`
+
+	// Versão do formato do registro gravado (ver repository.UserSchema)
+	SchemaVersion int `json:"-"`
 }
 
 // RegisterUserInput representa os dados para registro de um novo usuário
 type RegisterUserInput struct {
 	Email    string `json:"email" binding:"required,email"`
 	Name     string `json:"name" binding:"required"`
-	Password string `json:"password" binding:"required,min=6"`
+	Password string `json:"password" binding:"required,min=6" redact:"true"`
+
+	// InvitationCode é o token de um convite para o email informado;
+	// obrigatório quando o cadastro aberto está desativado
+	InvitationCode string `json:"invitation_code,omitempty" redact:"true"`
+
+	// Versões aceitas dos termos de uso e da política de privacidade;
+	// obrigatórias quando o registro de aceites está configurado
+	TermsVersion   string `json:"terms_version,omitempty" example:"2024-06-01"`
+	PrivacyVersion string `json:"privacy_version,omitempty" example:"2024-01-15"`
+}
+
+// BootstrapInput representa a criação do primeiro administrador com o token
+// de configuração exibido na primeira execução
+type BootstrapInput struct {
+	Token    string `json:"token" binding:"required" redact:"true"`
+	Email    string `json:"email" binding:"required,email"`
+	Name     string `json:"name" binding:"required"`
+	Password string `json:"password" binding:"required,min=6" redact:"true"`
 }
 
 // LoginInput representa os dados para login de um usuário
 type LoginInput struct {
 	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required"`
+	Password string `json:"password" binding:"required" redact:"true"`
+
+	// Session escolhe a duração da sessão; vazio usa a duração padrão
+	Session string `json:"session,omitempty" binding:"omitempty,oneof=short standard long" example:"long"`
 }
 
+// Durações de sessão que o login pode solicitar
+const (
+	SessionShort    = "short"
+	SessionStandard = "standard"
+	SessionLong     = "long"
+)
+
 // TokenPair representa um par de tokens JWT (access e refresh)
 type TokenPair struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
+
+	Session   string `json:"session,omitempty" example:"standard"`
+	ExpiresIn int    `json:"expires_in,omitempty" example:"900"` // validade do token de acesso, em segundos
+}
+
+// Session registra a duração da sessão de um token de atualização, mantida
+// nas renovações
+type Session struct {
+	UserID    string
+	Kind      string
+	ExpiresAt time.Time
+}
+
+// UpdateProfileInput representa uma atualização parcial do perfil: apenas os
+// campos presentes no JSON são alterados. Texto vazio remove bio, telefone,
+// idioma e fuso horário; page_size 0 volta ao tamanho de página padrão
+type UpdateProfileInput struct {
+	Name     *string `json:"name" binding:"omitempty,not_blank,max=100" example:"Maria Silva"`
+	Bio      *string `json:"bio" binding:"omitempty,max=500" example:"Desenvolvedora Go"`
+	Phone    *string `json:"phone" binding:"omitempty,phone" example:"+5511999998888"`
+	Locale   *string `json:"locale" binding:"omitempty,locale" example:"pt-BR"`
+	Timezone *string `json:"timezone" binding:"omitempty,tz" example:"America/Sao_Paulo"`
+	PageSize *int    `json:"page_size" binding:"omitempty,min=0,max=100" example:"25"`
+}
+
+// IsEmpty indica se nenhum campo foi informado
+func (in *UpdateProfileInput) IsEmpty() bool {
+	return in.Name == nil && in.Bio == nil && in.Phone == nil && in.Locale == nil && in.Timezone == nil && in.PageSize == nil
+}
+
+// ApplyTo copia para o usuário os campos informados
+func (in *UpdateProfileInput) ApplyTo(u *User) {
+	if in.Name != nil {
+		u.Name = strings.TrimSpace(*in.Name)
+	}
+	if in.Bio != nil {
+		u.Bio = *in.Bio
+	}
+	if in.Phone != nil {
+		u.Phone = *in.Phone
+	}
+	if in.Locale != nil {
+		u.Locale = *in.Locale
+	}
+	if in.Timezone != nil {
+		u.Timezone = *in.Timezone
+	}
+	if in.PageSize != nil {
+		u.PageSize = *in.PageSize
+	}
+}
+
+// UserPreferences reúne as preferências do usuário aplicadas às respostas das
+// requisições autenticadas quando o cliente não as informa
+type UserPreferences struct {
+	PageSize int    // tamanho de página das listagens sem limit (0: padrão da API)
+	Locale   string // idioma das mensagens ("": negociado pelo Accept-Language)
+	Timezone string // fuso horário IANA ("": UTC)
+}
+
+// Preferences retorna as preferências salvas no perfil do usuário
+func (u *User) Preferences() *UserPreferences {
+	return &UserPreferences{
+		PageSize: u.PageSize,
+		Locale:   u.Locale,
+		Timezone: u.Timezone,
+	}
+}
+
+// ResetPasswordInput representa a redefinição de senha com o token enviado por email
+type ResetPasswordInput struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=6" redact:"true"`
+}
+
+// DisableUserInput representa o motivo (opcional) da desativação de uma conta
+type DisableUserInput struct {
+	Reason string `json:"reason" binding:"max=500" example:"Credenciais vazadas"`
 }
 
 // UserResponse representa os dados de usuário devolvidos nas respostas
@@ -40,7 +165,16 @@ type UserResponse struct {
 	Email     string    `json:"email"`
 	Name      string    `json:"name"`
 	Role      string    `json:"role"`
+	AvatarURL string    `json:"avatar_url,omitempty" example:"/api/v1/avatars/1f0c-1700000000.jpg"`
+	Bio       string    `json:"bio,omitempty" example:"Desenvolvedor Go"`
+	Phone     string    `json:"phone,omitempty" example:"+5511999998888"`
+	Locale    string    `json:"locale,omitempty" example:"pt-BR"`
+	Timezone  string    `json:"timezone,omitempty" example:"America/Sao_Paulo"`
+	PageSize  int       `json:"page_size,omitempty" example:"25"`
 	CreatedAt time.Time `json:"created_at"`
+
+	Disabled              bool `json:"disabled,omitempty"`
+	PasswordResetRequired bool `json:"password_reset_required,omitempty"`
 }
 
 // ToUserResponse converte um User para UserResponse
@@ -50,6 +184,51 @@ func (u *User) ToUserResponse() UserResponse {
 		Email:     u.Email,
 		Name:      u.Name,
 		Role:      u.Role,
+		AvatarURL: u.AvatarURL,
+		Bio:       u.Bio,
+		Phone:     u.Phone,
+		Locale:    u.Locale,
+		Timezone:  u.Timezone,
+		PageSize:  u.PageSize,
 		CreatedAt: u.CreatedAt,
+
+		Disabled:              u.Disabled,
+		PasswordResetRequired: u.PasswordResetRequired,
 	}
-}
\ No newline at end of file
+}
+// Motivos de falha registrados no histórico de login
+const (
+	LoginFailureInvalidCredentials    = "invalid_credentials"
+	LoginFailureAccountDisabled       = "account_disabled"
+	LoginFailurePasswordResetRequired = "password_reset_required"
+)
+
+// DeviceIDHeader é o cabeçalho com o identificador do dispositivo informado
+// pelo cliente, usado para vincular os tokens de atualização ao dispositivo
+const DeviceIDHeader = "X-Device-ID"
+
+// ClientInfo identifica o cliente que fez a requisição
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+	DeviceID  string
+}
+
+// DeviceBinding vincula um token de atualização ao dispositivo que o recebeu
+type DeviceBinding struct {
+	UserID        string
+	DeviceID      string
+	UserAgentHash string
+	ExpiresAt     time.Time
+}
+
+// LoginAttempt representa uma tentativa de login registrada no histórico do usuário
+type LoginAttempt struct {
+	ID            string    `json:"id"`
+	UserID        string    `json:"user_id"`
+	Success       bool      `json:"success"`
+	FailureReason string    `json:"failure_reason,omitempty" example:"invalid_credentials"`
+	IP            string    `json:"ip" example:"203.0.113.10"`
+	UserAgent     string    `json:"user_agent" example:"Mozilla/5.0"`
+	Timestamp     time.Time `json:"timestamp"`
+}
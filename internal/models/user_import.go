@@ -0,0 +1,35 @@
+package models
+
+// Situação de cada linha da importação de usuários
+const (
+	UserImportCreated = "created"
+	UserImportFailed  = "failed"
+)
+
+// UserImportRow é uma linha do CSV de importação de usuários. Line é o número
+// da linha no arquivo (a linha 1 é o cabeçalho)
+type UserImportRow struct {
+	Line  int    `json:"line" example:"2"`
+	Email string `json:"email" binding:"required,valid_email" example:"maria@example.com"`
+	Name  string `json:"name" binding:"required,not_blank" example:"Maria Silva"`
+	Role  string `json:"role" binding:"omitempty,oneof=user admin" example:"user"`
+}
+
+// UserImportResult é o resultado da importação de uma linha
+type UserImportResult struct {
+	Line    int    `json:"line" example:"2"`
+	Email   string `json:"email" example:"maria@example.com"`
+	Status  string `json:"status" example:"created"`
+	UserID  string `json:"user_id,omitempty" example:"42"`
+	Invited bool   `json:"invited,omitempty" example:"true"`
+	Error   string `json:"error,omitempty" example:"Email já está em uso"`
+}
+
+// UserImportReport é o relatório do job de importação, com o resultado de
+// cada linha na ordem do arquivo
+type UserImportReport struct {
+	Total   int                `json:"total" example:"3"`
+	Created int                `json:"created" example:"2"`
+	Failed  int                `json:"failed" example:"1"`
+	Rows    []UserImportResult `json:"rows"`
+}
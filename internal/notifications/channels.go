@@ -0,0 +1,243 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"callable-api/internal/clock"
+	"callable-api/internal/models"
+	"callable-api/pkg/httpclient"
+	"callable-api/pkg/logger"
+	"callable-api/pkg/mail"
+	"callable-api/pkg/retry"
+)
+
+// notificationTemplate é o template de email usado para notificações
+const notificationTemplate = "notification"
+
+// EmailChannel entrega notificações por email através do subsistema de mail
+type EmailChannel struct {
+	mailer *mail.Mailer
+}
+
+// NewEmailChannel cria um novo canal de email
+func NewEmailChannel(mailer *mail.Mailer) *EmailChannel {
+	return &EmailChannel{mailer: mailer}
+}
+
+// Name implementa Channel
+func (c *EmailChannel) Name() string {
+	return models.NotificationChannelEmail
+}
+
+// Deliver implementa Channel
+func (c *EmailChannel) Deliver(ctx context.Context, recipient Recipient, event Event) error {
+	if recipient.Email == "" {
+		return fmt.Errorf("usuário %s não possui email", recipient.UserID)
+	}
+
+	return c.mailer.SendTemplate(ctx, notificationTemplate, []string{recipient.Email}, map[string]interface{}{
+		"Name":  recipient.Name,
+		"Title": event.Title,
+		"Body":  event.Body,
+	})
+}
+
+// WebhookChannel entrega notificações via HTTP POST para a URL configurada pelo usuário
+type WebhookChannel struct {
+	client     *http.Client
+	timeout    time.Duration
+	deliveries DeliveryStore
+	clock      clock.Clock
+}
+
+// NewWebhookChannel cria um novo canal de webhook
+func NewWebhookChannel(timeout time.Duration) *WebhookChannel {
+	c := &WebhookChannel{timeout: timeout, clock: clock.System()}
+	return c.WithRetry(retry.DefaultPolicy())
+}
+
+// WithDeliveries registra as entregas no store, o que permite listá-las e
+// reentregá-las (Redeliver) durante a janela de retenção
+func (c *WebhookChannel) WithDeliveries(store DeliveryStore) *WebhookChannel {
+	c.deliveries = store
+	return c
+}
+
+// WithClock define o relógio usado nos horários das entregas
+func (c *WebhookChannel) WithClock(clk clock.Clock) *WebhookChannel {
+	c.clock = clk
+	return c
+}
+
+// WithRetry define a política de novas tentativas para falhas transitórias
+// (erros de rede, 429 e 5xx). Os webhooks são repetidos mesmo sendo POST:
+// os destinos identificam entregas duplicadas pelo evento
+func (c *WebhookChannel) WithRetry(policy retry.Policy) *WebhookChannel {
+	c.client = httpclient.New(httpclient.Config{Timeout: c.timeout, Retry: policy, RetryUnsafe: true})
+	return c
+}
+
+// Name implementa Channel
+func (c *WebhookChannel) Name() string {
+	return models.NotificationChannelWebhook
+}
+
+// Deliver implementa Channel
+func (c *WebhookChannel) Deliver(ctx context.Context, recipient Recipient, event Event) error {
+	if recipient.WebhookURL == "" {
+		return fmt.Errorf("usuário %s não configurou URL de webhook", recipient.UserID)
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("falha ao serializar evento: %w", err)
+	}
+
+	delivery := &models.WebhookDelivery{
+		EventID:   event.ID,
+		WebhookID: recipient.WebhookID,
+		UserID:    recipient.UserID,
+		Type:      event.Type,
+		Payload:   payload,
+	}
+	err = c.post(ctx, recipient.WebhookURL, delivery, false)
+	c.record(ctx, delivery, err)
+	return err
+}
+
+// Deliveries lista as entregas ao webhook do destinatário ainda retidas
+func (c *WebhookChannel) Deliveries(ctx context.Context, recipient Recipient) ([]models.WebhookDelivery, error) {
+	if c.deliveries == nil {
+		return []models.WebhookDelivery{}, nil
+	}
+	list, err := c.deliveries.List(ctx, recipient.WebhookID)
+	if err != nil {
+		return nil, err
+	}
+	owned := list[:0]
+	for _, delivery := range list {
+		if delivery.UserID == recipient.UserID {
+			owned = append(owned, delivery)
+		}
+	}
+	return owned, nil
+}
+
+// Redeliver reenvia o payload guardado do evento à URL atual do webhook do
+// destinatário. O resultado da nova tentativa fica registrado na entrega
+// retornada; o erro indica apenas entregas inexistentes ou falhas do store
+func (c *WebhookChannel) Redeliver(ctx context.Context, recipient Recipient, eventID string) (*models.WebhookDelivery, error) {
+	if c.deliveries == nil {
+		return nil, ErrDeliveryNotFound
+	}
+	delivery, err := c.deliveries.Find(ctx, recipient.WebhookID, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if delivery.UserID != recipient.UserID {
+		return nil, ErrDeliveryNotFound
+	}
+
+	postErr := c.post(ctx, recipient.WebhookURL, delivery, true)
+	if err := c.record(ctx, delivery, postErr); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// record registra o resultado de uma tentativa de entrega, se o canal guarda
+// as entregas. Falhas ao gravar não afetam a entrega, apenas a reentrega
+func (c *WebhookChannel) record(ctx context.Context, delivery *models.WebhookDelivery, postErr error) error {
+	if c.deliveries == nil || delivery.WebhookID == "" || delivery.EventID == "" {
+		return nil
+	}
+
+	delivery.Attempts++
+	delivery.LastAttemptAt = c.clock.Now().UTC()
+	if delivery.CreatedAt.IsZero() {
+		delivery.CreatedAt = delivery.LastAttemptAt
+	}
+	delivery.Status = models.WebhookDeliverySucceeded
+	delivery.LastError = ""
+	if postErr != nil {
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.LastError = postErr.Error()
+	}
+
+	if err := c.deliveries.Save(context.WithoutCancel(ctx), delivery); err != nil {
+		logger.Warn("Falha ao registrar entrega de webhook", map[string]interface{}{
+			"userId":  delivery.UserID,
+			"eventId": delivery.EventID,
+			"error":   err.Error(),
+		})
+		return err
+	}
+	return nil
+}
+
+// post entrega o evento; as falhas transitórias são repetidas pelo cliente
+// (ver pacote httpclient). Os destinos identificam entregas duplicadas pelo
+// header X-Callable-Event-Id; reentregas pedidas pelo usuário são marcadas
+// com X-Callable-Redelivery
+func (c *WebhookChannel) post(ctx context.Context, url string, delivery *models.WebhookDelivery, redelivery bool) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return fmt.Errorf("falha ao criar requisição de webhook: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Callable-Event", delivery.Type)
+	if delivery.EventID != "" {
+		req.Header.Set("X-Callable-Event-Id", delivery.EventID)
+	}
+	if redelivery {
+		req.Header.Set("X-Callable-Redelivery", "true")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("falha na entrega do webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook respondeu com status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Inbox armazena as notificações in-app de cada usuário
+type Inbox interface {
+	Add(ctx context.Context, notification *models.Notification) error
+}
+
+// InAppChannel entrega notificações na caixa de entrada da aplicação
+type InAppChannel struct {
+	inbox Inbox
+}
+
+// NewInAppChannel cria um novo canal in-app
+func NewInAppChannel(inbox Inbox) *InAppChannel {
+	return &InAppChannel{inbox: inbox}
+}
+
+// Name implementa Channel
+func (c *InAppChannel) Name() string {
+	return models.NotificationChannelInApp
+}
+
+// Deliver implementa Channel
+func (c *InAppChannel) Deliver(ctx context.Context, recipient Recipient, event Event) error {
+	return c.inbox.Add(ctx, &models.Notification{
+		UserID:    recipient.UserID,
+		Type:      event.Type,
+		Title:     event.Title,
+		Body:      event.Body,
+		Data:      event.Data,
+		CreatedAt: event.OccurredAt,
+	})
+}
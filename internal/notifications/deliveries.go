@@ -0,0 +1,146 @@
+package notifications
+
+import (
+	"context"
+	stderrors "errors"
+	"sort"
+	"sync"
+	"time"
+
+	"callable-api/internal/clock"
+	"callable-api/internal/models"
+)
+
+// DefaultDeliveryRetention é o tempo pelo qual as entregas de webhook ficam
+// disponíveis para reentrega
+const DefaultDeliveryRetention = 72 * time.Hour
+
+// ErrWebhookNotFound é a causa do erro NotFound de webhooks inexistentes ou
+// de outro usuário
+var ErrWebhookNotFound = stderrors.New("webhook não encontrado")
+
+// ErrDeliveryNotFound é a causa do erro NotFound de entregas inexistentes ou
+// fora da janela de retenção
+var ErrDeliveryNotFound = stderrors.New("entrega de webhook não encontrada")
+
+// DeliveryStore guarda as entregas de webhook durante a janela de retenção
+type DeliveryStore interface {
+	// Save cria ou substitui a entrega do evento ao webhook
+	Save(ctx context.Context, delivery *models.WebhookDelivery) error
+
+	// Find retorna a entrega do evento ao webhook (ErrDeliveryNotFound se
+	// inexistente ou expirada)
+	Find(ctx context.Context, webhookID, eventID string) (*models.WebhookDelivery, error)
+
+	// List retorna as entregas ao webhook ainda retidas, das mais recentes
+	// para as mais antigas
+	List(ctx context.Context, webhookID string) ([]models.WebhookDelivery, error)
+}
+
+// MemoryDeliveryStore implementa DeliveryStore em memória
+type MemoryDeliveryStore struct {
+	retention time.Duration
+	clock     clock.Clock
+
+	mu         sync.Mutex
+	deliveries map[string]map[string]models.WebhookDelivery // webhook -> evento
+}
+
+// NewMemoryDeliveryStore cria um store que mantém as entregas pelo tempo de
+// retenção informado (DefaultDeliveryRetention se não positivo)
+func NewMemoryDeliveryStore(retention time.Duration) *MemoryDeliveryStore {
+	if retention <= 0 {
+		retention = DefaultDeliveryRetention
+	}
+	return &MemoryDeliveryStore{
+		retention:  retention,
+		clock:      clock.System(),
+		deliveries: make(map[string]map[string]models.WebhookDelivery),
+	}
+}
+
+// WithClock define o relógio usado para expirar as entregas
+func (s *MemoryDeliveryStore) WithClock(c clock.Clock) *MemoryDeliveryStore {
+	s.clock = c
+	return s
+}
+
+// Save implementa DeliveryStore.Save. A retenção conta a partir da primeira
+// entrega: reentregas não prolongam a janela
+func (s *MemoryDeliveryStore) Save(ctx context.Context, delivery *models.WebhookDelivery) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	s.purge(now)
+
+	stored := *delivery
+	if stored.CreatedAt.IsZero() {
+		stored.CreatedAt = now
+	}
+	stored.ExpiresAt = stored.CreatedAt.Add(s.retention)
+	if s.deliveries[stored.WebhookID] == nil {
+		s.deliveries[stored.WebhookID] = make(map[string]models.WebhookDelivery)
+	}
+	s.deliveries[stored.WebhookID][stored.EventID] = stored
+	*delivery = stored
+	return nil
+}
+
+// Find implementa DeliveryStore.Find
+func (s *MemoryDeliveryStore) Find(ctx context.Context, webhookID, eventID string) (*models.WebhookDelivery, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delivery, ok := s.deliveries[webhookID][eventID]
+	if !ok || !s.clock.Now().Before(delivery.ExpiresAt) {
+		return nil, ErrDeliveryNotFound
+	}
+	return &delivery, nil
+}
+
+// List implementa DeliveryStore.List
+func (s *MemoryDeliveryStore) List(ctx context.Context, webhookID string) ([]models.WebhookDelivery, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.purge(s.clock.Now())
+	list := make([]models.WebhookDelivery, 0, len(s.deliveries[webhookID]))
+	for _, delivery := range s.deliveries[webhookID] {
+		list = append(list, delivery)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].CreatedAt.Equal(list[j].CreatedAt) {
+			return list[i].CreatedAt.After(list[j].CreatedAt)
+		}
+		return list[i].EventID > list[j].EventID
+	})
+	return list, nil
+}
+
+// purge remove as entregas fora da janela de retenção. Deve ser chamado com
+// o mutex travado
+func (s *MemoryDeliveryStore) purge(now time.Time) {
+	for webhookID, byEvent := range s.deliveries {
+		for eventID, delivery := range byEvent {
+			if !now.Before(delivery.ExpiresAt) {
+				delete(byEvent, eventID)
+			}
+		}
+		if len(byEvent) == 0 {
+			delete(s.deliveries, webhookID)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+
+	"callable-api/internal/correlation"
+	"callable-api/internal/events"
+	"callable-api/internal/models"
+	"callable-api/pkg/logger"
+)
+
+// HandleEvent é o consumidor do barramento de eventos (events.Subscriber) que
+// converte os eventos de domínio em notificações para os usuários afetados:
+// job concluído para o dono do job e item compartilhado para quem o recebeu.
+// A entrega é feita em segundo plano (Publish)
+func (s *Service) HandleEvent(ctx context.Context, envelope *events.Envelope) {
+	if envelope.Type != events.TypeJobCompleted && envelope.Type != events.TypeItemShared {
+		return
+	}
+
+	decoded, err := events.Decode(envelope)
+	if err != nil {
+		logger.Warn("Evento ignorado pelas notificações", correlation.Fields(ctx, map[string]interface{}{
+			"event":   envelope.Type,
+			"eventId": envelope.ID,
+			"error":   err.Error(),
+		}))
+		return
+	}
+
+	event, ok := eventNotification(decoded)
+	if !ok {
+		return
+	}
+	// O ID do envelope identifica o evento nas entregas e reentregas
+	event.ID = envelope.ID
+	event.OccurredAt = envelope.OccurredAt
+	s.Publish(event)
+}
+
+// eventNotification monta a notificação de um evento de domínio. Eventos sem
+// usuário destinatário (ex.: compartilhamentos com papéis) não notificam
+func eventNotification(event events.Event) (Event, bool) {
+	switch e := event.(type) {
+	case *events.JobCompleted:
+		if e.UserID == "" {
+			return Event{}, false
+		}
+		return Event{
+			Type:   models.NotificationEventJobCompleted,
+			UserID: e.UserID,
+			Title:  "Job concluído",
+			Body:   fmt.Sprintf("O job %s (%s) foi concluído", e.JobID, e.Kind),
+			Data: map[string]interface{}{
+				"job_id": e.JobID,
+				"type":   e.Kind,
+			},
+		}, true
+	case *events.ItemShared:
+		if e.UserID == "" {
+			return Event{}, false
+		}
+		return Event{
+			Type:   models.NotificationEventItemShared,
+			UserID: e.UserID,
+			Title:  "Item compartilhado com você",
+			Body:   fmt.Sprintf("O item %q foi compartilhado com você com acesso de %s", e.ItemName, shareLevelLabel(e.Level)),
+			Data: map[string]interface{}{
+				"item_id": e.ItemID,
+				"level":   e.Level,
+			},
+		}, true
+	}
+	return Event{}, false
+}
+
+// shareLevelLabel descreve o nível de acesso nas notificações
+func shareLevelLabel(level string) string {
+	if level == models.ShareLevelWrite {
+		return "edição"
+	}
+	return "leitura"
+}
@@ -0,0 +1,38 @@
+// Package notifications distribui eventos da aplicação (job concluído, item
+// compartilhado) para os canais de entrega (email, webhook, in-app) de acordo
+// com as preferências de cada usuário
+package notifications
+
+import (
+	"context"
+	"time"
+)
+
+// Event representa um acontecimento que deve ser notificado a um usuário
+type Event struct {
+	ID         string                 `json:"id"`
+	Type       string                 `json:"type"`
+	UserID     string                 `json:"user_id"`
+	Title      string                 `json:"title"`
+	Body       string                 `json:"body"`
+	Data       map[string]interface{} `json:"data,omitempty"`
+	OccurredAt time.Time              `json:"occurred_at"`
+}
+
+// Recipient reúne os dados do destinatário necessários aos canais
+type Recipient struct {
+	UserID     string
+	Name       string
+	Email      string
+	WebhookURL string
+	WebhookID  string
+}
+
+// Channel define um canal de entrega de notificações
+type Channel interface {
+	// Name retorna o identificador do canal (email, webhook, in_app)
+	Name() string
+
+	// Deliver entrega o evento ao destinatário
+	Deliver(ctx context.Context, recipient Recipient, event Event) error
+}
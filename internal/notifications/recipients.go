@@ -0,0 +1,36 @@
+package notifications
+
+import (
+	"context"
+
+	"callable-api/internal/repository"
+)
+
+// adminPageSize é o tamanho das páginas percorridas na busca dos administradores
+const adminPageSize = 100
+
+// AdminRecipients retorna uma função que lista os IDs dos administradores
+// ativos, destinatários dos alertas operacionais
+func AdminRecipients(users repository.UserRepository) func(ctx context.Context) ([]string, error) {
+	return func(ctx context.Context) ([]string, error) {
+		var admins []string
+		for page := 1; ; page++ {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			list, total, err := users.List(page, adminPageSize)
+			if err != nil {
+				return nil, err
+			}
+			for _, user := range list {
+				if user.Role == "admin" && !user.Disabled {
+					admins = append(admins, user.ID)
+				}
+			}
+			if len(list) == 0 || page*adminPageSize >= total {
+				return admins, nil
+			}
+		}
+	}
+}
@@ -0,0 +1,332 @@
+package notifications
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"time"
+
+	"callable-api/internal/messages"
+	"callable-api/internal/models"
+	"callable-api/internal/repository"
+	"callable-api/pkg/errors"
+	"callable-api/pkg/httpclient"
+	"callable-api/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+// defaultDeliveryTimeout é o prazo de entrega das notificações publicadas em segundo plano
+const defaultDeliveryTimeout = 30 * time.Second
+
+// Service gerencia as preferências de notificação e distribui os eventos
+// para os canais habilitados por cada usuário
+type Service struct {
+	prefsRepo       repository.NotificationPreferencesRepository
+	userRepo        repository.UserRepository
+	channels        map[string]Channel
+	inbox           repository.NotificationRepository
+	deliveryTimeout time.Duration
+}
+
+// NewService cria um novo serviço de notificações com os canais informados
+func NewService(prefsRepo repository.NotificationPreferencesRepository, userRepo repository.UserRepository, channels ...Channel) *Service {
+	s := &Service{
+		prefsRepo:       prefsRepo,
+		userRepo:        userRepo,
+		channels:        make(map[string]Channel, len(channels)),
+		deliveryTimeout: defaultDeliveryTimeout,
+	}
+	for _, ch := range channels {
+		s.channels[ch.Name()] = ch
+	}
+	return s
+}
+
+// WithInbox habilita a caixa de entrada in-app: o canal in_app grava as
+// notificações no repositório, consultado pelos usuários
+func (s *Service) WithInbox(inbox repository.NotificationRepository) *Service {
+	s.inbox = inbox
+	s.channels[models.NotificationChannelInApp] = NewInAppChannel(inbox)
+	return s
+}
+
+// ListNotifications retorna, com paginação, as notificações in-app do
+// usuário (apenas as não lidas se unreadOnly), das mais recentes para as mais
+// antigas
+func (s *Service) ListNotifications(ctx context.Context, userID string, unreadOnly bool, page, limit int) ([]models.Notification, int, error) {
+	if s.inbox == nil {
+		return []models.Notification{}, 0, nil
+	}
+
+	list, total, err := s.inbox.ListByUser(ctx, userID, unreadOnly, page, limit)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, 0, ctxErr
+		}
+		return nil, 0, errors.NewInternalServerError("Erro ao buscar notificações", err)
+	}
+	return list, total, nil
+}
+
+// MarkRead marca uma notificação do usuário como lida
+func (s *Service) MarkRead(ctx context.Context, userID, id string) (*models.Notification, error) {
+	if s.inbox == nil {
+		return nil, errors.NewNotFoundError("Notificação não encontrada", repository.ErrNotificationNotFound)
+	}
+
+	notification, err := s.inbox.MarkRead(ctx, userID, id)
+	if err != nil {
+		return nil, inboxError(ctx, err, "Erro ao marcar notificação como lida")
+	}
+	return notification, nil
+}
+
+// MarkAllRead marca todas as notificações do usuário como lidas
+func (s *Service) MarkAllRead(ctx context.Context, userID string) (*models.NotificationsRead, error) {
+	if s.inbox == nil {
+		return &models.NotificationsRead{}, nil
+	}
+
+	updated, err := s.inbox.MarkAllRead(ctx, userID)
+	if err != nil {
+		return nil, inboxError(ctx, err, "Erro ao marcar notificações como lidas")
+	}
+	return &models.NotificationsRead{Updated: updated}, nil
+}
+
+// inboxError preserva os AppErrors do repositório (ex.: NotFound) e o
+// cancelamento da requisição; os demais erros viram InternalServerError
+func inboxError(ctx context.Context, err error, message string) error {
+	if _, ok := err.(*errors.AppError); ok {
+		return err
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return errors.NewInternalServerError(message, err)
+}
+
+// GetPreferences retorna as preferências do usuário, ou as padrão se ainda não configuradas
+func (s *Service) GetPreferences(ctx context.Context, userID string) (*models.NotificationPreferences, error) {
+	prefs, err := s.prefsRepo.FindByUserID(ctx, userID)
+	if err == nil {
+		return prefs, nil
+	}
+
+	if appErr, ok := err.(*errors.AppError); ok && appErr.Type == "NOT_FOUND" {
+		return models.DefaultNotificationPreferences(userID), nil
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, ctxErr
+	}
+	return nil, errors.NewInternalServerError("Erro ao buscar preferências de notificação", err)
+}
+
+// UpdatePreferences valida e substitui as preferências do usuário
+func (s *Service) UpdatePreferences(ctx context.Context, userID string, input *models.UpdateNotificationPreferencesInput) (*models.NotificationPreferences, error) {
+	locale := messages.Locale(ctx)
+	validationErr := errors.NewValidationError("Preferências de notificação inválidas")
+	validInputs := true
+	usesWebhook := false
+
+	for eventType, channels := range input.Channels {
+		if !contains(models.NotificationEventTypes, eventType) {
+			validationErr.AddFieldError("channels."+eventType, messages.Text(locale, messages.UnknownEventType, "channels"))
+			validInputs = false
+			continue
+		}
+		for _, channel := range channels {
+			if !contains(models.NotificationChannels, channel) {
+				validationErr.AddFieldError("channels."+eventType, messages.Text(locale, messages.UnknownChannel, "channels", channel))
+				validInputs = false
+			}
+			if channel == models.NotificationChannelWebhook {
+				usesWebhook = true
+			}
+		}
+	}
+
+	if usesWebhook && input.WebhookURL == "" {
+		validationErr.AddFieldError("webhook_url", messages.Text(locale, "required", "webhook_url"))
+		validInputs = false
+	}
+	if input.WebhookURL != "" && !httpclient.AllowedURL(input.WebhookURL) {
+		validationErr.AddFieldError("webhook_url", messages.Text(locale, messages.EgressDenied, "webhook_url"))
+		validInputs = false
+	}
+
+	if !validInputs {
+		return nil, validationErr
+	}
+
+	// O ID do webhook identifica o destino nas reentregas: é mantido quando a
+	// URL muda e removido quando o webhook é desativado
+	webhookID := ""
+	if input.WebhookURL != "" {
+		current, err := s.GetPreferences(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		webhookID = current.WebhookID
+		if webhookID == "" {
+			webhookID = uuid.New().String()
+		}
+	}
+
+	prefs, err := s.prefsRepo.Save(ctx, &models.NotificationPreferences{
+		UserID:     userID,
+		Channels:   input.Channels,
+		WebhookURL: input.WebhookURL,
+		WebhookID:  webhookID,
+	})
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, errors.NewInternalServerError("Erro ao salvar preferências de notificação", err)
+	}
+
+	logger.Info("Preferências de notificação atualizadas", map[string]interface{}{
+		"userId": userID,
+	})
+
+	return prefs, nil
+}
+
+// Notify entrega o evento de forma síncrona em todos os canais habilitados
+// pelo usuário, retornando os erros de entrega agregados
+func (s *Service) Notify(ctx context.Context, event Event) error {
+	if event.ID == "" {
+		event.ID = uuid.New().String()
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	prefs, err := s.GetPreferences(ctx, event.UserID)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.FindByID(event.UserID)
+	if err != nil {
+		return err
+	}
+
+	recipient := Recipient{
+		UserID:     user.ID,
+		Name:       user.Name,
+		Email:      user.Email,
+		WebhookURL: prefs.WebhookURL,
+		WebhookID:  prefs.WebhookID,
+	}
+
+	var deliveryErrs []error
+	for _, channelName := range prefs.Channels[event.Type] {
+		channel, ok := s.channels[channelName]
+		if !ok {
+			// Canal não habilitado nesta instância (ex.: email sem provedor configurado)
+			continue
+		}
+
+		if err := channel.Deliver(ctx, recipient, event); err != nil {
+			logger.Warn("Falha na entrega de notificação", map[string]interface{}{
+				"userId":  event.UserID,
+				"event":   event.Type,
+				"channel": channelName,
+				"error":   err.Error(),
+			})
+			deliveryErrs = append(deliveryErrs, fmt.Errorf("%s: %w", channelName, err))
+		}
+	}
+
+	return stderrors.Join(deliveryErrs...)
+}
+
+// Publish entrega o evento em segundo plano, sem bloquear o chamador
+func (s *Service) Publish(event Event) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), s.deliveryTimeout)
+		defer cancel()
+
+		if err := s.Notify(ctx, event); err != nil {
+			logger.Error("Falha ao publicar notificação", map[string]interface{}{
+				"userId": event.UserID,
+				"event":  event.Type,
+				"error":  err.Error(),
+			})
+		}
+	}()
+}
+
+// ListWebhookDeliveries lista as entregas ao webhook do usuário ainda
+// disponíveis para reentrega, das mais recentes para as mais antigas
+func (s *Service) ListWebhookDeliveries(ctx context.Context, userID, webhookID string) ([]models.WebhookDelivery, error) {
+	channel, recipient, err := s.webhookRecipient(ctx, userID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := channel.Deliveries(ctx, recipient)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, errors.NewInternalServerError("Erro ao buscar entregas de webhook", err)
+	}
+	return list, nil
+}
+
+// RedeliverWebhook reenvia ao webhook do usuário o evento já entregue (com
+// ou sem sucesso), enquanto ele estiver na janela de retenção. A entrega
+// retornada indica o resultado da nova tentativa
+func (s *Service) RedeliverWebhook(ctx context.Context, userID, webhookID, eventID string) (*models.WebhookDelivery, error) {
+	channel, recipient, err := s.webhookRecipient(ctx, userID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery, err := channel.Redeliver(ctx, recipient, eventID)
+	if err != nil {
+		if stderrors.Is(err, ErrDeliveryNotFound) {
+			return nil, errors.NewNotFoundError("Entrega de webhook não encontrada", err)
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, errors.NewInternalServerError("Erro ao reentregar evento", err)
+	}
+
+	logger.Info("Evento reentregue ao webhook", map[string]interface{}{
+		"userId":  userID,
+		"eventId": eventID,
+		"status":  delivery.Status,
+	})
+	return delivery, nil
+}
+
+// webhookRecipient retorna o canal de webhook e o destinatário, se o webhook
+// informado é o configurado pelo usuário
+func (s *Service) webhookRecipient(ctx context.Context, userID, webhookID string) (*WebhookChannel, Recipient, error) {
+	prefs, err := s.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, Recipient{}, err
+	}
+	channel, ok := s.channels[models.NotificationChannelWebhook].(*WebhookChannel)
+	if !ok || prefs.WebhookID == "" || prefs.WebhookID != webhookID {
+		return nil, Recipient{}, errors.NewNotFoundError("Webhook não encontrado", ErrWebhookNotFound)
+	}
+
+	return channel, Recipient{UserID: userID, WebhookURL: prefs.WebhookURL, WebhookID: prefs.WebhookID}, nil
+}
+
+// contains verifica se o valor pertence à lista
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,296 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"callable-api/internal/clock"
+	"callable-api/internal/events"
+	"callable-api/internal/models"
+	"callable-api/internal/repository"
+	"callable-api/internal/repository/testsupport"
+	"callable-api/pkg/errors"
+	"callable-api/pkg/retry"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeChannel registra as entregas recebidas
+type fakeChannel struct {
+	name      string
+	mu        sync.Mutex
+	delivered []Event
+}
+
+func (f *fakeChannel) Name() string { return f.name }
+
+func (f *fakeChannel) Deliver(ctx context.Context, recipient Recipient, event Event) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.delivered = append(f.delivered, event)
+	return nil
+}
+
+func newTestService(t *testing.T, channels ...Channel) (*Service, *models.User) {
+	userRepo := testsupport.NewUserRepository()
+	user, err := userRepo.Create(&models.User{Email: "notify@example.com", Name: "Notify User", Role: "user"})
+	assert.NoError(t, err)
+
+	return NewService(repository.NewInMemoryNotificationPreferencesRepository(), userRepo, channels...), user
+}
+
+func TestGetPreferences_DefaultsWhenNotConfigured(t *testing.T) {
+	service, user := newTestService(t)
+
+	prefs, err := service.GetPreferences(context.Background(), user.ID)
+	assert.NoError(t, err)
+	assert.True(t, prefs.WantsChannel(models.NotificationEventItemShared, models.NotificationChannelEmail))
+}
+
+func TestUpdatePreferences(t *testing.T) {
+	service, user := newTestService(t)
+
+	t.Run("Preferências válidas são salvas", func(t *testing.T) {
+		prefs, err := service.UpdatePreferences(context.Background(), user.ID, &models.UpdateNotificationPreferencesInput{
+			Channels: map[string][]string{
+				models.NotificationEventJobCompleted: {models.NotificationChannelWebhook},
+			},
+			WebhookURL: "https://example.com/hook",
+		})
+		assert.NoError(t, err)
+		assert.True(t, prefs.WantsChannel(models.NotificationEventJobCompleted, models.NotificationChannelWebhook))
+
+		stored, err := service.GetPreferences(context.Background(), user.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "https://example.com/hook", stored.WebhookURL)
+		assert.False(t, stored.WantsChannel(models.NotificationEventItemShared, models.NotificationChannelEmail))
+	})
+
+	t.Run("Evento e canal desconhecidos são rejeitados", func(t *testing.T) {
+		_, err := service.UpdatePreferences(context.Background(), user.ID, &models.UpdateNotificationPreferencesInput{
+			Channels: map[string][]string{
+				"item.exploded":                      {models.NotificationChannelEmail},
+				models.NotificationEventJobCompleted: {"sms"},
+			},
+		})
+		validationErr, ok := err.(*errors.ValidationError)
+		assert.True(t, ok)
+		assert.Len(t, validationErr.FieldErrors, 2)
+	})
+
+	t.Run("Canal webhook exige URL", func(t *testing.T) {
+		_, err := service.UpdatePreferences(context.Background(), user.ID, &models.UpdateNotificationPreferencesInput{
+			Channels: map[string][]string{
+				models.NotificationEventJobCompleted: {models.NotificationChannelWebhook},
+			},
+		})
+		_, ok := err.(*errors.ValidationError)
+		assert.True(t, ok)
+	})
+}
+
+func TestNotify_FansOutToPreferredChannels(t *testing.T) {
+	email := &fakeChannel{name: models.NotificationChannelEmail}
+	inApp := &fakeChannel{name: models.NotificationChannelInApp}
+	service, user := newTestService(t, email, inApp)
+
+	// Com as preferências padrão, item.shared vai para email e in-app
+	err := service.Notify(context.Background(), Event{Type: models.NotificationEventItemShared, UserID: user.ID, Title: "Item compartilhado"})
+	assert.NoError(t, err)
+	assert.Len(t, email.delivered, 1)
+	assert.Len(t, inApp.delivered, 1)
+
+	// job.completed vai apenas para in-app
+	err = service.Notify(context.Background(), Event{Type: models.NotificationEventJobCompleted, UserID: user.ID, Title: "Job concluído"})
+	assert.NoError(t, err)
+	assert.Len(t, email.delivered, 1)
+	assert.Len(t, inApp.delivered, 2)
+}
+
+func TestWebhookChannel(t *testing.T) {
+	var received Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, models.NotificationEventJobCompleted, r.Header.Get("X-Callable-Event"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	channel := NewWebhookChannel(defaultDeliveryTimeout)
+	err := channel.Deliver(context.Background(), Recipient{UserID: "u1", WebhookURL: server.URL}, Event{
+		Type:   models.NotificationEventJobCompleted,
+		UserID: "u1",
+		Title:  "Job concluído",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "Job concluído", received.Title)
+
+	err = channel.Deliver(context.Background(), Recipient{UserID: "u1"}, Event{Type: models.NotificationEventJobCompleted})
+	assert.Error(t, err)
+}
+
+func TestWebhookChannelRetry(t *testing.T) {
+	var calls atomic.Int32
+	status := http.StatusServiceUnavailable
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(status)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	policy := retry.DefaultPolicy()
+	policy.InitialDelay = time.Millisecond
+	channel := NewWebhookChannel(defaultDeliveryTimeout).WithRetry(policy)
+	recipient := Recipient{UserID: "u1", WebhookURL: server.URL}
+
+	// Falhas transitórias são repetidas
+	err := channel.Deliver(context.Background(), recipient, Event{Type: models.NotificationEventJobCompleted})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), calls.Load())
+
+	// Erros do cliente não
+	calls.Store(0)
+	status = http.StatusBadRequest
+	err = channel.Deliver(context.Background(), recipient, Event{Type: models.NotificationEventJobCompleted})
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestInbox(t *testing.T) {
+	ctx := context.Background()
+	inbox := repository.NewInMemoryNotificationRepository()
+	service, user := newTestService(t)
+	service.WithInbox(inbox)
+
+	// Os eventos de domínio chegam à caixa de entrada pelo barramento
+	bus := events.NewBus()
+	bus.Subscribe(service.HandleEvent)
+	bus.Publish(ctx, events.JobCompleted{JobID: "job-1", Kind: "item.create", UserID: user.ID})
+	bus.Publish(ctx, events.ItemShared{ItemID: "item-1", ItemName: "Relatório", UserID: user.ID, Level: models.ShareLevelWrite})
+	bus.Publish(ctx, events.ItemShared{ItemID: "item-1", ItemName: "Relatório", Role: "admin", Level: models.ShareLevelRead})
+	bus.Publish(ctx, events.ItemCreated{ItemID: "item-2", OwnerID: user.ID, Name: "Outro"})
+
+	assert.Eventually(t, func() bool {
+		_, total, err := service.ListNotifications(ctx, user.ID, true, 1, 10)
+		return err == nil && total == 2
+	}, time.Second, 10*time.Millisecond)
+
+	list, _, err := service.ListNotifications(ctx, user.ID, false, 1, 10)
+	assert.NoError(t, err)
+	for _, n := range list {
+		if n.Type == models.NotificationEventItemShared {
+			assert.Contains(t, n.Body, "edição")
+		}
+	}
+
+	// Marcar como lida
+	read, err := service.MarkRead(ctx, user.ID, list[0].ID)
+	assert.NoError(t, err)
+	assert.True(t, read.Read)
+	assert.NotNil(t, read.ReadAt)
+	_, unread, err := service.ListNotifications(ctx, user.ID, true, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, unread)
+
+	// Notificações de outros usuários são tratadas como inexistentes
+	_, err = service.MarkRead(ctx, "outro", list[1].ID)
+	appErr, ok := err.(*errors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, "NOT_FOUND", appErr.Type)
+
+	result, err := service.MarkAllRead(ctx, user.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Updated)
+	_, unread, _ = service.ListNotifications(ctx, user.ID, true, 1, 10)
+	assert.Equal(t, 0, unread)
+
+	// A listagem começa pelas notificações mais recentes
+	channel := NewInAppChannel(inbox)
+	for _, title := range []string{"primeira", "segunda"} {
+		err := channel.Deliver(ctx, Recipient{UserID: "u1"}, Event{Type: models.NotificationEventJobCompleted, Title: title})
+		assert.NoError(t, err)
+	}
+	list, total, err := service.ListNotifications(ctx, "u1", false, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, "segunda", list[0].Title)
+	assert.NotEmpty(t, list[0].ID)
+}
+
+func TestRedeliverWebhook(t *testing.T) {
+	ctx := context.Background()
+	var calls atomic.Int32
+	var redelivered atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A primeira entrega encontra o destino fora do ar
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		redelivered.Store(r.Header.Get("X-Callable-Event-Id") + "|" + r.Header.Get("X-Callable-Redelivery"))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	now := clock.NewFake(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	store := NewMemoryDeliveryStore(time.Hour).WithClock(now)
+	webhook := NewWebhookChannel(defaultDeliveryTimeout).WithDeliveries(store).WithClock(now)
+	service, user := newTestService(t, webhook)
+
+	prefs, err := service.UpdatePreferences(ctx, user.ID, &models.UpdateNotificationPreferencesInput{
+		Channels:   map[string][]string{models.NotificationEventJobCompleted: {models.NotificationChannelWebhook}},
+		WebhookURL: server.URL,
+	})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, prefs.WebhookID)
+
+	err = service.Notify(ctx, Event{ID: "evt-1", Type: models.NotificationEventJobCompleted, UserID: user.ID, Title: "Job concluído"})
+	assert.Error(t, err)
+
+	list, err := service.ListWebhookDeliveries(ctx, user.ID, prefs.WebhookID)
+	assert.NoError(t, err)
+	if assert.Len(t, list, 1) {
+		assert.Equal(t, "evt-1", list[0].EventID)
+		assert.Equal(t, models.WebhookDeliveryFailed, list[0].Status)
+		assert.Contains(t, string(list[0].Payload), "Job concluído")
+	}
+
+	// A reentrega envia o mesmo payload e ID, marcada como reentrega
+	delivery, err := service.RedeliverWebhook(ctx, user.ID, prefs.WebhookID, "evt-1")
+	assert.NoError(t, err)
+	assert.Equal(t, models.WebhookDeliverySucceeded, delivery.Status)
+	assert.Equal(t, 2, delivery.Attempts)
+	assert.Empty(t, delivery.LastError)
+	assert.Equal(t, "evt-1|true", redelivered.Load())
+
+	// Mudar a URL mantém o ID do webhook
+	updated, err := service.UpdatePreferences(ctx, user.ID, &models.UpdateNotificationPreferencesInput{
+		Channels:   map[string][]string{models.NotificationEventJobCompleted: {models.NotificationChannelWebhook}},
+		WebhookURL: server.URL + "/v2",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, prefs.WebhookID, updated.WebhookID)
+
+	// Webhooks de outros usuários e eventos desconhecidos são tratados como inexistentes
+	_, err = service.RedeliverWebhook(ctx, "outro", prefs.WebhookID, "evt-1")
+	assert.ErrorIs(t, err, ErrWebhookNotFound)
+	_, err = service.RedeliverWebhook(ctx, user.ID, prefs.WebhookID, "evt-2")
+	assert.ErrorIs(t, err, ErrDeliveryNotFound)
+
+	// Fora da janela de retenção, a entrega não pode mais ser reentregue
+	now.Advance(time.Hour)
+	_, err = service.RedeliverWebhook(ctx, user.ID, prefs.WebhookID, "evt-1")
+	assert.ErrorIs(t, err, ErrDeliveryNotFound)
+	list, err = service.ListWebhookDeliveries(ctx, user.ID, prefs.WebhookID)
+	assert.NoError(t, err)
+	assert.Empty(t, list)
+}
@@ -0,0 +1,148 @@
+// Package openapi gera o documento OpenAPI (Swagger 2.0) da API a partir da
+// matriz de rotas do routes.Registry. As operações documentadas pelas
+// anotações do swag (pacote docs) são aproveitadas; as demais recebem uma
+// descrição mínima com o resumo, a autenticação e os parâmetros do caminho.
+// O documento público omite as rotas de administração e operação.
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"callable-api/internal/models"
+	"callable-api/internal/routes"
+)
+
+// Scope define quais rotas entram no documento
+type Scope string
+
+// Escopos suportados
+const (
+	ScopePublic Scope = "public" // sem as rotas de administração e operação
+	ScopeFull   Scope = "full"   // todas as rotas declaradas
+)
+
+// Public indica se a rota entra no documento público: rotas que exigem
+// papéis, as de provisionamento (SCIM), as das integrações internas e a de
+// métricas são de uso interno
+func Public(route models.RouteSecurity) bool {
+	if len(route.Roles) > 0 {
+		return false
+	}
+	switch routes.AuthMode(route.Auth) {
+	case routes.AuthSCIM, routes.AuthInternal, routes.AuthMetrics:
+		return false
+	}
+	return true
+}
+
+// Build gera o documento do escopo. base é o documento gerado pelo swag, do
+// qual vêm as informações gerais, as definições e as operações já
+// documentadas; operações que não estão na matriz de rotas são descartadas.
+// Rotas com curinga (ex.: /swagger/*any) não são representáveis e ficam de fora
+func Build(base string, matrix []models.RouteSecurity, scope Scope) (map[string]interface{}, error) {
+	doc := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(base), &doc); err != nil {
+		return nil, fmt.Errorf("openapi: documento base inválido: %w", err)
+	}
+	documented, _ := doc["paths"].(map[string]interface{})
+
+	paths := map[string]interface{}{}
+	for _, route := range matrix {
+		if strings.Contains(route.Path, "*") || (scope != ScopeFull && !Public(route)) {
+			continue
+		}
+
+		path, params := templatePath(route.Path)
+		method := strings.ToLower(route.Method)
+
+		operation, _ := lookup(documented, path, method)
+		if operation == nil {
+			operation = synthesize(route, params)
+		}
+		operation["x-auth"] = route.Auth
+		operation["x-rate-class"] = route.RateClass
+		if len(route.Roles) > 0 {
+			operation["x-roles"] = route.Roles
+		}
+
+		item, _ := paths[path].(map[string]interface{})
+		if item == nil {
+			item = map[string]interface{}{}
+			paths[path] = item
+		}
+		item[method] = operation
+	}
+
+	doc["paths"] = paths
+	return doc, nil
+}
+
+// templatePath converte um caminho do Gin (/items/:id) para o formato do
+// OpenAPI (/items/{id}), retornando os parâmetros do caminho
+func templatePath(path string) (string, []string) {
+	segments := strings.Split(path, "/")
+	var params []string
+	for i, segment := range segments {
+		if name, ok := strings.CutPrefix(segment, ":"); ok {
+			params = append(params, name)
+			segments[i] = "{" + name + "}"
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// lookup retorna a operação documentada pelo swag, se existir
+func lookup(documented map[string]interface{}, path, method string) (map[string]interface{}, bool) {
+	item, ok := documented[path].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	operation, ok := item[method].(map[string]interface{})
+	return operation, ok
+}
+
+// synthesize descreve uma operação sem anotações do swag a partir da declaração
+func synthesize(route models.RouteSecurity, params []string) map[string]interface{} {
+	operation := map[string]interface{}{
+		"summary":  route.Description,
+		"tags":     []string{tag(route.Path)},
+		"produces": []string{"application/json"},
+		"responses": map[string]interface{}{
+			"default": map[string]interface{}{"description": "Resposta da operação"},
+		},
+	}
+
+	if route.Auth == string(routes.AuthJWT) || route.Auth == string(routes.AuthOptional) {
+		operation["security"] = []map[string][]string{{"Bearer": {}}}
+	}
+
+	parameters := make([]map[string]interface{}, 0, len(params))
+	for _, name := range params {
+		parameters = append(parameters, map[string]interface{}{
+			"name":     name,
+			"in":       "path",
+			"required": true,
+			"type":     "string",
+		})
+	}
+	if len(parameters) > 0 {
+		operation["parameters"] = parameters
+	}
+	return operation
+}
+
+// tag agrupa a operação pelo primeiro segmento do caminho depois da versão
+// (ex.: /api/v1/admin/users/:id → admin)
+func tag(path string) string {
+	rest := strings.TrimPrefix(path, "/api/v1/")
+	rest = strings.TrimPrefix(rest, "/")
+	if i := strings.Index(rest, "/"); i >= 0 {
+		rest = rest[:i]
+	}
+	if rest == "" {
+		return "default"
+	}
+	return rest
+}
@@ -0,0 +1,82 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"callable-api/internal/models"
+)
+
+const base = `{
+	"swagger": "2.0",
+	"info": {"title": "Callable API"},
+	"paths": {
+		"/api/v1/data/{id}": {"get": {"summary": "Get item by ID", "tags": ["items"]}},
+		"/api/v1/removida": {"get": {"summary": "Rota que não existe mais"}}
+	},
+	"definitions": {"models.Item": {"type": "object"}}
+}`
+
+var matrix = []models.RouteSecurity{
+	{Method: "GET", Path: "/api/v1/data/:id", Auth: "optional", RateClass: "standard", Description: "Retorna um item"},
+	{Method: "DELETE", Path: "/api/v1/data/:id/shares/:shareId", Auth: "jwt", RateClass: "standard", Description: "Revoga um compartilhamento"},
+	{Method: "GET", Path: "/api/v1/admin/overview", Auth: "jwt", Roles: []string{"admin"}, RateClass: "standard", Description: "Resumo operacional"},
+	{Method: "POST", Path: "/scim/v2/Users", Auth: "scim", RateClass: "standard", Description: "Provisiona um usuário (SCIM)"},
+	{Method: "GET", Path: "/swagger/*any", Auth: "public", RateClass: "unlimited"},
+}
+
+// operation retorna a operação do documento gerado
+func operation(t *testing.T, doc map[string]interface{}, path, method string) map[string]interface{} {
+	t.Helper()
+	item, ok := doc["paths"].(map[string]interface{})[path].(map[string]interface{})
+	require.True(t, ok, path)
+	op, ok := item[method].(map[string]interface{})
+	require.True(t, ok, method+" "+path)
+	return op
+}
+
+func TestBuild_Public(t *testing.T) {
+	doc, err := Build(base, matrix, ScopePublic)
+	require.NoError(t, err)
+
+	// Sem administração, SCIM, curingas e operações que não estão no registry
+	paths := doc["paths"].(map[string]interface{})
+	assert.Len(t, paths, 2)
+	assert.NotContains(t, paths, "/api/v1/admin/overview")
+	assert.NotContains(t, paths, "/scim/v2/Users")
+	assert.NotContains(t, paths, "/api/v1/removida")
+	assert.Contains(t, doc, "definitions")
+
+	// Operação documentada pelo swag, acrescida dos requisitos da rota
+	get := operation(t, doc, "/api/v1/data/{id}", "get")
+	assert.Equal(t, "Get item by ID", get["summary"])
+	assert.Equal(t, "optional", get["x-auth"])
+
+	// Operação sem anotações, descrita a partir da declaração
+	del := operation(t, doc, "/api/v1/data/{id}/shares/{shareId}", "delete")
+	assert.Equal(t, "Revoga um compartilhamento", del["summary"])
+	assert.Equal(t, []string{"data"}, del["tags"])
+	assert.NotNil(t, del["security"])
+	params := del["parameters"].([]map[string]interface{})
+	require.Len(t, params, 2)
+	assert.Equal(t, "id", params[0]["name"])
+	assert.Equal(t, "shareId", params[1]["name"])
+}
+
+func TestBuild_Full(t *testing.T) {
+	doc, err := Build(base, matrix, ScopeFull)
+	require.NoError(t, err)
+
+	assert.Len(t, doc["paths"], 4)
+	overview := operation(t, doc, "/api/v1/admin/overview", "get")
+	assert.Equal(t, []string{"admin"}, overview["x-roles"])
+	assert.Equal(t, []string{"admin"}, overview["tags"])
+	assert.Nil(t, operation(t, doc, "/scim/v2/Users", "post")["security"])
+}
+
+func TestBuild_InvalidBase(t *testing.T) {
+	_, err := Build("{", matrix, ScopeFull)
+	assert.Error(t, err)
+}
@@ -0,0 +1,175 @@
+// Package pagination centraliza a política de paginação da API: leitura dos
+// parâmetros page/limit da query string, tamanhos padrão e máximo de página,
+// o cálculo da janela de resultados usado pelos repositórios e os headers
+// Link (RFC 5988) das respostas paginadas.
+package pagination
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Valores padrão da política de paginação
+const (
+	DefaultPageSize = 10
+	MaxPageSize     = 100
+)
+
+// Config define os limites de tamanho de página aceitos pela API
+type Config struct {
+	DefaultPageSize int
+	MaxPageSize     int
+}
+
+// DefaultConfig retorna a política padrão (10 itens por página, no máximo 100)
+func DefaultConfig() Config {
+	return Config{
+		DefaultPageSize: DefaultPageSize,
+		MaxPageSize:     MaxPageSize,
+	}
+}
+
+// withDefaults corrige valores ausentes ou inconsistentes da configuração
+func (cfg Config) withDefaults() Config {
+	if cfg.DefaultPageSize < 1 {
+		cfg.DefaultPageSize = DefaultPageSize
+	}
+	if cfg.MaxPageSize < 1 {
+		cfg.MaxPageSize = MaxPageSize
+	}
+	if cfg.DefaultPageSize > cfg.MaxPageSize {
+		cfg.DefaultPageSize = cfg.MaxPageSize
+	}
+	return cfg
+}
+
+// Params representa uma página solicitada
+type Params struct {
+	Page  int
+	Limit int
+}
+
+// New cria Params a partir de valores arbitrários: página menor que 1 vira 1 e
+// limite menor que 1 vira o tamanho padrão. Não aplica o tamanho máximo, que
+// é responsabilidade da camada HTTP (ver Config.Parse)
+func New(page, limit int) Params {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = DefaultPageSize
+	}
+	return Params{Page: page, Limit: limit}
+}
+
+// Offset retorna a posição do primeiro registro da página
+func (p Params) Offset() int {
+	return (p.Page - 1) * p.Limit
+}
+
+// Window retorna os índices [start, end) da página em uma coleção com total
+// registros. Páginas além do fim retornam start == end
+func (p Params) Window(total int) (int, int) {
+	start := p.Offset()
+	if start > total {
+		start = total
+	}
+	end := start + p.Limit
+	if end > total {
+		end = total
+	}
+	return start, end
+}
+
+// Pages retorna o número de páginas de uma coleção com total registros
+// (ao menos 1, para que a primeira página sempre exista)
+func (p Params) Pages(total int) int {
+	if total <= 0 || p.Limit < 1 {
+		return 1
+	}
+	return (total + p.Limit - 1) / p.Limit
+}
+
+// Links monta o header Link (RFC 5988) da página em uma coleção com total
+// registros, com as relações first, prev, next e last. As URLs são relativas
+// e preservam os demais parâmetros da query de u
+func Links(u *url.URL, p Params, total int) string {
+	pages := p.Pages(total)
+
+	// A query é lida uma única vez; cada link só troca page e limit
+	query := u.Query()
+	query.Set("limit", strconv.Itoa(p.Limit))
+	path := u.EscapedPath()
+
+	var b strings.Builder
+	link := func(page int, rel string) {
+		if b.Len() > 0 {
+			b.WriteString(", ")
+		}
+		query.Set("page", strconv.Itoa(page))
+		b.WriteString("<")
+		b.WriteString(path)
+		b.WriteString("?")
+		b.WriteString(query.Encode())
+		b.WriteString(`>; rel="`)
+		b.WriteString(rel)
+		b.WriteString(`"`)
+	}
+
+	link(1, "first")
+	if p.Page > 1 {
+		link(min(p.Page-1, pages), "prev")
+	}
+	if p.Page < pages {
+		link(p.Page+1, "next")
+	}
+	link(pages, "last")
+	return b.String()
+}
+
+// Normalize aplica a política aos valores informados: página menor que 1 vira
+// 1, limite ausente ou inválido vira o padrão e limites acima do máximo são
+// reduzidos ao máximo
+func (cfg Config) Normalize(page, limit int) Params {
+	cfg = cfg.withDefaults()
+	if limit < 1 {
+		limit = cfg.DefaultPageSize
+	}
+	if limit > cfg.MaxPageSize {
+		limit = cfg.MaxPageSize
+	}
+	return New(page, limit)
+}
+
+type pageSizeKey struct{}
+
+// WithPageSize retorna um contexto com o tamanho de página preferido pelo
+// usuário, usado por Parse quando a requisição não informa limit
+func WithPageSize(ctx context.Context, size int) context.Context {
+	return context.WithValue(ctx, pageSizeKey{}, size)
+}
+
+// PageSize retorna o tamanho de página preferido do contexto (0 se não houver)
+func PageSize(ctx context.Context) int {
+	size, _ := ctx.Value(pageSizeKey{}).(int)
+	return size
+}
+
+// Parse lê page e limit da query string aplicando a política. Valores não
+// numéricos são tratados como ausentes; sem limit, vale o tamanho de página
+// preferido pelo usuário (ver WithPageSize) ou o padrão
+func (cfg Config) Parse(c *gin.Context) Params {
+	page, err := strconv.Atoi(c.Query("page"))
+	if err != nil {
+		page = 1
+	}
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil {
+		limit = PageSize(c.Request.Context())
+	}
+	return cfg.Normalize(page, limit)
+}
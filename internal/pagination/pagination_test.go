@@ -0,0 +1,92 @@
+package pagination
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	cfg := Config{DefaultPageSize: 20, MaxPageSize: 50}
+
+	assert.Equal(t, Params{Page: 1, Limit: 20}, cfg.Normalize(0, 0))
+	assert.Equal(t, Params{Page: 3, Limit: 5}, cfg.Normalize(3, 5))
+	assert.Equal(t, Params{Page: 1, Limit: 50}, cfg.Normalize(-1, 500))
+
+	// Configuração vazia usa a política padrão
+	assert.Equal(t, Params{Page: 1, Limit: DefaultPageSize}, Config{}.Normalize(1, 0))
+	assert.Equal(t, Params{Page: 1, Limit: MaxPageSize}, Config{}.Normalize(1, 1000))
+}
+
+func TestParse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := DefaultConfig()
+
+	tests := []struct {
+		query    string
+		expected Params
+	}{
+		{"", Params{Page: 1, Limit: 10}},
+		{"?page=2&limit=25", Params{Page: 2, Limit: 25}},
+		{"?page=abc&limit=xyz", Params{Page: 1, Limit: 10}},
+		{"?page=1&limit=1000", Params{Page: 1, Limit: 100}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest("GET", "/api/v1/data"+tt.query, nil)
+			assert.Equal(t, tt.expected, cfg.Parse(c))
+		})
+	}
+}
+
+func TestParse_PreferredPageSize(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := DefaultConfig()
+
+	parse := func(query string, preferred int) Params {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("GET", "/api/v1/data"+query, nil)
+		c.Request = c.Request.WithContext(WithPageSize(c.Request.Context(), preferred))
+		return cfg.Parse(c)
+	}
+
+	// A preferência do usuário substitui o padrão, mas não o limit informado
+	assert.Equal(t, Params{Page: 1, Limit: 25}, parse("", 25))
+	assert.Equal(t, Params{Page: 2, Limit: 5}, parse("?page=2&limit=5", 25))
+	assert.Equal(t, Params{Page: 1, Limit: 100}, parse("", 500))
+	assert.Equal(t, Params{Page: 1, Limit: 10}, parse("", 0))
+}
+
+func TestWindow(t *testing.T) {
+	start, end := New(2, 10).Window(25)
+	assert.Equal(t, 10, start)
+	assert.Equal(t, 20, end)
+
+	start, end = New(3, 10).Window(25)
+	assert.Equal(t, 20, start)
+	assert.Equal(t, 25, end)
+
+	start, end = New(4, 10).Window(25)
+	assert.Equal(t, start, end)
+}
+
+func TestLinks(t *testing.T) {
+	u, _ := url.Parse("/api/v1/data?page=2&limit=10&q=abc")
+
+	links := Links(u, Params{Page: 2, Limit: 10}, 35)
+	assert.Equal(t, `</api/v1/data?limit=10&page=1&q=abc>; rel="first", `+
+		`</api/v1/data?limit=10&page=1&q=abc>; rel="prev", `+
+		`</api/v1/data?limit=10&page=3&q=abc>; rel="next", `+
+		`</api/v1/data?limit=10&page=4&q=abc>; rel="last"`, links)
+
+	// Na única página não há prev nem next
+	links = Links(u, Params{Page: 1, Limit: 10}, 0)
+	assert.NotContains(t, links, `rel="prev"`)
+	assert.NotContains(t, links, `rel="next"`)
+	assert.Contains(t, links, `page=1&q=abc>; rel="last"`)
+}
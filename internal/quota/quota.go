@@ -0,0 +1,159 @@
+// Package quota contabiliza as requisições de cada cliente (usuário
+// autenticado ou IP) em janelas fixas de tempo e aplica a cota configurada.
+package quota
+
+import (
+	"sync"
+	"time"
+
+	"callable-api/internal/models"
+)
+
+// Config define a cota de requisições por cliente
+type Config struct {
+	// Limit é o número máximo de requisições por janela (0 desativa a cota)
+	Limit int
+	// Window é a duração de cada janela de contagem
+	Window time.Duration
+	// CostBytes é o tamanho de corpo que vale uma requisição adicional: uma
+	// requisição com corpo de n bytes consome 1 + n/CostBytes da cota
+	// (0: toda requisição consome 1)
+	CostBytes int64
+}
+
+// DefaultConfig retorna a cota padrão: 1000 requisições por hora
+func DefaultConfig() Config {
+	return Config{
+		Limit:  1000,
+		Window: time.Hour,
+	}
+}
+
+// Enabled indica se a cota deve ser aplicada
+func (c Config) Enabled() bool {
+	return c.Limit > 0 && c.Window > 0
+}
+
+// Cost retorna quanto uma requisição com corpo de size bytes consome da cota
+func (c Config) Cost(size int64) int {
+	if c.CostBytes <= 0 || size <= 0 {
+		return 1
+	}
+	return 1 + int(size/c.CostBytes)
+}
+
+// counter guarda a contagem de um cliente na janela iniciada em start
+type counter struct {
+	start time.Time
+	count int
+}
+
+// Tracker contabiliza as requisições por chave em memória
+type Tracker struct {
+	cfg       Config
+	mutex     sync.Mutex
+	counters  map[string]*counter
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewTracker cria um Tracker com a configuração informada
+func NewTracker(cfg Config) *Tracker {
+	return &Tracker{
+		cfg:      cfg,
+		counters: make(map[string]*counter),
+		now:      time.Now,
+	}
+}
+
+// Config retorna a configuração da cota
+func (t *Tracker) Config() Config {
+	return t.cfg
+}
+
+// Consume registra uma requisição para a chave e retorna o consumo atualizado.
+// O segundo valor é false quando a cota da janela foi excedida
+func (t *Tracker) Consume(key string) (models.QuotaUsage, bool) {
+	return t.ConsumeN(key, 1)
+}
+
+// ConsumeN registra uma requisição de custo cost (ver Config.Cost) para a
+// chave e retorna o consumo atualizado
+func (t *Tracker) ConsumeN(key string, cost int) (models.QuotaUsage, bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	now := t.now()
+	t.sweep(now)
+
+	c := t.current(key, now)
+	c.count += cost
+	usage := t.usage(c, now)
+	return usage, !usage.Exceeded()
+}
+
+// Usage retorna o consumo da chave na janela atual sem registrar requisição
+func (t *Tracker) Usage(key string) models.QuotaUsage {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	now := t.now()
+	c, exists := t.counters[key]
+	if !exists || t.expired(c, now) {
+		c = &counter{start: t.windowStart(now)}
+	}
+	return t.usage(c, now)
+}
+
+// current retorna o contador da janela atual, reiniciando-o se expirou
+func (t *Tracker) current(key string, now time.Time) *counter {
+	c, exists := t.counters[key]
+	if !exists || t.expired(c, now) {
+		c = &counter{start: t.windowStart(now)}
+		t.counters[key] = c
+	}
+	return c
+}
+
+// windowStart alinha o início da janela à duração configurada
+func (t *Tracker) windowStart(now time.Time) time.Time {
+	if t.cfg.Window <= 0 {
+		return now
+	}
+	return now.Truncate(t.cfg.Window)
+}
+
+// expired indica se o contador pertence a uma janela já encerrada
+func (t *Tracker) expired(c *counter, now time.Time) bool {
+	return t.cfg.Window > 0 && !now.Before(c.start.Add(t.cfg.Window))
+}
+
+// sweep remove contadores de janelas encerradas, no máximo uma vez por janela
+func (t *Tracker) sweep(now time.Time) {
+	if t.cfg.Window <= 0 || now.Sub(t.lastSweep) < t.cfg.Window {
+		return
+	}
+	for key, c := range t.counters {
+		if t.expired(c, now) {
+			delete(t.counters, key)
+		}
+	}
+	t.lastSweep = now
+}
+
+// usage monta o resumo de consumo de um contador
+func (t *Tracker) usage(c *counter, now time.Time) models.QuotaUsage {
+	usage := models.QuotaUsage{
+		Limit:         t.cfg.Limit,
+		Used:          c.count,
+		WindowSeconds: int(t.cfg.Window.Seconds()),
+		ResetAt:       c.start.Add(t.cfg.Window).UTC(),
+	}
+	if t.cfg.Limit > 0 {
+		usage.Remaining = t.cfg.Limit - c.count
+		if usage.Remaining < 0 {
+			usage.Remaining = 0
+		}
+	}
+	return usage
+}
@@ -0,0 +1,88 @@
+package quota
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestTracker(cfg Config, now *time.Time) *Tracker {
+	t := NewTracker(cfg)
+	t.now = func() time.Time { return *now }
+	return t
+}
+
+func TestTracker_Consume(t *testing.T) {
+	now := time.Date(2023, 5, 22, 14, 10, 0, 0, time.UTC)
+	tracker := newTestTracker(Config{Limit: 2, Window: time.Hour}, &now)
+
+	usage, ok := tracker.Consume("user:1")
+	assert.True(t, ok)
+	assert.Equal(t, 1, usage.Used)
+	assert.Equal(t, 1, usage.Remaining)
+	assert.Equal(t, time.Date(2023, 5, 22, 15, 0, 0, 0, time.UTC), usage.ResetAt)
+
+	_, ok = tracker.Consume("user:1")
+	assert.True(t, ok)
+
+	usage, ok = tracker.Consume("user:1")
+	assert.False(t, ok, "A terceira requisição deve exceder a cota")
+	assert.Equal(t, 0, usage.Remaining)
+
+	// Outras chaves têm contagem independente
+	_, ok = tracker.Consume("user:2")
+	assert.True(t, ok)
+
+	// Na janela seguinte a contagem recomeça
+	now = now.Add(time.Hour)
+	usage, ok = tracker.Consume("user:1")
+	assert.True(t, ok)
+	assert.Equal(t, 1, usage.Used)
+}
+
+func TestTracker_Usage(t *testing.T) {
+	now := time.Date(2023, 5, 22, 14, 10, 0, 0, time.UTC)
+	tracker := newTestTracker(Config{Limit: 10, Window: time.Minute}, &now)
+
+	// Consultar o consumo não conta como requisição
+	usage := tracker.Usage("user:1")
+	assert.Equal(t, 0, usage.Used)
+	assert.Equal(t, 10, usage.Remaining)
+	assert.Equal(t, 60, usage.WindowSeconds)
+
+	tracker.Consume("user:1")
+	tracker.Consume("user:1")
+	assert.Equal(t, 2, tracker.Usage("user:1").Used)
+
+	now = now.Add(2 * time.Minute)
+	assert.Equal(t, 0, tracker.Usage("user:1").Used)
+}
+
+func TestTracker_Disabled(t *testing.T) {
+	tracker := NewTracker(Config{})
+	assert.False(t, tracker.Config().Enabled())
+
+	for i := 0; i < 5; i++ {
+		_, ok := tracker.Consume("ip:127.0.0.1")
+		assert.True(t, ok)
+	}
+}
+
+func TestTracker_ConsumeN(t *testing.T) {
+	cfg := Config{Limit: 10, Window: time.Hour, CostBytes: 1000}
+	assert.Equal(t, 1, cfg.Cost(-1))
+	assert.Equal(t, 1, cfg.Cost(999))
+	assert.Equal(t, 3, cfg.Cost(2500))
+	assert.Equal(t, 1, Config{}.Cost(2500))
+
+	now := time.Date(2023, 5, 22, 14, 10, 0, 0, time.UTC)
+	tracker := newTestTracker(cfg, &now)
+	usage, ok := tracker.ConsumeN("user:1", 8)
+	assert.True(t, ok)
+	assert.Equal(t, 2, usage.Remaining)
+
+	// Requisições caras esgotam a cota antes
+	_, ok = tracker.ConsumeN("user:1", 3)
+	assert.False(t, ok)
+}
@@ -0,0 +1,163 @@
+// Package recorder captura pares requisição/resposta sanitizados das rotas
+// selecionadas por um administrador e permite reproduzi-los contra o código
+// atual, para investigar problemas relatados por clientes.
+package recorder
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"callable-api/internal/models"
+	"callable-api/internal/redact"
+	"callable-api/pkg/logger"
+)
+
+// ReplayHeader marca as requisições reproduzidas, que nunca são gravadas
+const ReplayHeader = "X-Replay-Of"
+
+// MaxBodySize limita o tamanho dos corpos gravados
+const MaxBodySize = 64 * 1024
+
+// saveTimeout é o prazo para persistir cada gravação
+const saveTimeout = 10 * time.Second
+
+// Recorder decide quais requisições gravar e as persiste no Store
+type Recorder struct {
+	store   Store
+	mutex   sync.RWMutex
+	enabled bool
+	routes  map[string]bool
+}
+
+// New cria um Recorder desativado
+func New(store Store) *Recorder {
+	return &Recorder{
+		store:  store,
+		routes: make(map[string]bool),
+	}
+}
+
+// Settings retorna a configuração atual da gravação
+func (r *Recorder) Settings() models.RecordingSettings {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	routes := make([]string, 0, len(r.routes))
+	for route := range r.routes {
+		routes = append(routes, route)
+	}
+	return models.RecordingSettings{Enabled: r.enabled, Routes: routes}
+}
+
+// Configure ativa ou desativa a gravação para as rotas informadas, no formato
+// "<MÉTODO> <rota>" (ex.: "POST /api/v1/data") ou apenas "<rota>" para todos
+// os métodos
+func (r *Recorder) Configure(settings models.RecordingSettings) {
+	routes := make(map[string]bool, len(settings.Routes))
+	for _, route := range settings.Routes {
+		route = strings.TrimSpace(route)
+		if method, path, found := strings.Cut(route, " "); found {
+			route = strings.ToUpper(method) + " " + strings.TrimSpace(path)
+		}
+		if route != "" {
+			routes[route] = true
+		}
+	}
+
+	r.mutex.Lock()
+	r.enabled = settings.Enabled
+	r.routes = routes
+	r.mutex.Unlock()
+
+	logger.Info("Configuração de gravação de requisições atualizada", map[string]interface{}{
+		"enabled": settings.Enabled,
+		"routes":  settings.Routes,
+	})
+}
+
+// ShouldRecord indica se a requisição para a rota deve ser gravada
+func (r *Recorder) ShouldRecord(req *http.Request, route string) bool {
+	if route == "" || req.Header.Get(ReplayHeader) != "" {
+		return false
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.enabled && (r.routes[route] || r.routes[req.Method+" "+route])
+}
+
+// Record sanitiza e persiste, em segundo plano, um par requisição/resposta
+func (r *Recorder) Record(req *http.Request, route string, requestBody []byte, status int, responseHeaders http.Header, responseBody []byte, duration time.Duration) {
+	recording := &models.Recording{
+		ID:              uuid.New().String(),
+		Method:          req.Method,
+		Route:           route,
+		URL:             req.URL.RequestURI(),
+		RequestHeaders:  redact.Headers(req.Header),
+		RequestBody:     redact.Body(requestBody),
+		Status:          status,
+		ResponseHeaders: redact.Headers(responseHeaders),
+		ResponseBody:    redact.Body(responseBody),
+		DurationMs:      duration.Milliseconds(),
+		RecordedAt:      time.Now().UTC(),
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), saveTimeout)
+		defer cancel()
+		if err := r.store.Save(ctx, recording); err != nil {
+			logger.Error("Falha ao salvar gravação de requisição", map[string]interface{}{
+				"error": err.Error(),
+				"route": route,
+			})
+		}
+	}()
+}
+
+// List retorna as gravações mais recentes primeiro
+func (r *Recorder) List(ctx context.Context) ([]models.Recording, error) {
+	return r.store.List(ctx)
+}
+
+// Replay reproduz a requisição gravada contra o handler informado (normalmente
+// o próprio router da aplicação). Como as credenciais não são gravadas, a
+// requisição usa o cabeçalho Authorization informado por quem pede o replay
+func (r *Recorder) Replay(ctx context.Context, id string, handler http.Handler, authorization string) (*models.ReplayResult, error) {
+	recording, err := r.store.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	req := httptest.NewRequest(recording.Method, recording.URL, strings.NewReader(recording.RequestBody)).WithContext(ctx)
+	for name, values := range recording.RequestHeaders {
+		if redact.IsSensitiveHeader(name) {
+			continue
+		}
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	req.Header.Set(ReplayHeader, recording.ID)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	replayBody := redact.Body(w.Body.Bytes())
+	return &models.ReplayResult{
+		RecordingID:    recording.ID,
+		OriginalStatus: recording.Status,
+		ReplayStatus:   w.Code,
+		OriginalBody:   recording.ResponseBody,
+		ReplayBody:     replayBody,
+		Matches:        recording.Status == w.Code && recording.ResponseBody == replayBody,
+	}, nil
+}
@@ -0,0 +1,90 @@
+package recorder_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"callable-api/internal/middleware"
+	"callable-api/internal/models"
+	"callable-api/internal/recorder"
+)
+
+func setupRouter(rec *recorder.Recorder) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.RecordingMiddleware(rec))
+	router.POST("/login", func(c *gin.Context) {
+		var body map[string]string
+		_ = c.ShouldBindJSON(&body)
+		c.JSON(http.StatusOK, gin.H{"user": body["email"], "access_token": "secret-token"})
+	})
+	router.GET("/other", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	return router
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	rec := recorder.New(recorder.NewMemoryStore(10))
+	router := setupRouter(rec)
+	rec.Configure(models.RecordingSettings{Enabled: true, Routes: []string{"post /login"}})
+
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`{"email":"a@example.com","password":"123456"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer abc")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Rotas não selecionadas não são gravadas
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/other", nil))
+
+	var recordings []models.Recording
+	assert.Eventually(t, func() bool {
+		recordings, _ = rec.List(context.Background())
+		return len(recordings) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	recording := recordings[0]
+	assert.Equal(t, "/login", recording.Route)
+	assert.Equal(t, http.StatusOK, recording.Status)
+	assert.Equal(t, []string{"[REDACTED]"}, recording.RequestHeaders["Authorization"])
+	assert.NotContains(t, recording.RequestBody, "123456")
+	assert.Contains(t, recording.RequestBody, "a@example.com")
+	assert.NotContains(t, recording.ResponseBody, "secret-token")
+
+	// O replay passa pelo router sem gerar nova gravação
+	result, err := rec.Replay(context.Background(), recording.ID, router, "")
+	assert.NoError(t, err)
+	assert.True(t, result.Matches)
+	assert.Equal(t, http.StatusOK, result.ReplayStatus)
+
+	time.Sleep(50 * time.Millisecond)
+	recordings, _ = rec.List(context.Background())
+	assert.Len(t, recordings, 1)
+}
+
+func TestReplay_NotFound(t *testing.T) {
+	rec := recorder.New(recorder.NewMemoryStore(10))
+	_, err := rec.Replay(context.Background(), "missing", setupRouter(rec), "")
+	assert.Error(t, err)
+}
+
+func TestRecorder_Disabled(t *testing.T) {
+	rec := recorder.New(recorder.NewMemoryStore(10))
+	req := httptest.NewRequest(http.MethodPost, "/login", nil)
+	assert.False(t, rec.ShouldRecord(req, "/login"))
+
+	rec.Configure(models.RecordingSettings{Enabled: true, Routes: []string{"/login"}})
+	assert.True(t, rec.ShouldRecord(req, "/login"))
+
+	rec.Configure(models.RecordingSettings{Enabled: false, Routes: []string{"/login"}})
+	assert.False(t, rec.ShouldRecord(req, "/login"))
+}
@@ -0,0 +1,116 @@
+package recorder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+
+	"callable-api/internal/models"
+	"callable-api/pkg/cloud"
+	"callable-api/pkg/errors"
+	"callable-api/pkg/retry"
+)
+
+// defaultMaxRecordings é o número de gravações mantidas em memória
+const defaultMaxRecordings = 500
+
+// Store persiste as gravações
+type Store interface {
+	Save(ctx context.Context, recording *models.Recording) error
+	List(ctx context.Context) ([]models.Recording, error)
+	Get(ctx context.Context, id string) (*models.Recording, error)
+}
+
+// MemoryStore mantém as gravações mais recentes em memória
+type MemoryStore struct {
+	recordings []models.Recording
+	max        int
+	mutex      sync.RWMutex
+}
+
+// NewMemoryStore cria um MemoryStore que guarda até max gravações
+func NewMemoryStore(max int) *MemoryStore {
+	if max < 1 {
+		max = defaultMaxRecordings
+	}
+	return &MemoryStore{max: max}
+}
+
+// Save implementa Store, descartando as gravações mais antigas quando cheio
+func (s *MemoryStore) Save(ctx context.Context, recording *models.Recording) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.recordings = append(s.recordings, *recording)
+	if len(s.recordings) > s.max {
+		s.recordings = s.recordings[len(s.recordings)-s.max:]
+	}
+	return nil
+}
+
+// List implementa Store, retornando as gravações mais recentes primeiro
+func (s *MemoryStore) List(ctx context.Context) ([]models.Recording, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	result := make([]models.Recording, 0, len(s.recordings))
+	for i := len(s.recordings) - 1; i >= 0; i-- {
+		result = append(result, s.recordings[i])
+	}
+	return result, nil
+}
+
+// Get implementa Store
+func (s *MemoryStore) Get(ctx context.Context, id string) (*models.Recording, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for i := range s.recordings {
+		if s.recordings[i].ID == id {
+			recording := s.recordings[i]
+			return &recording, nil
+		}
+	}
+	return nil, errors.NewNotFoundError("Gravação não encontrada", nil)
+}
+
+// CloudStore grava cada gravação como JSON no Cloud Storage (em
+// recordings/<id>.json) e mantém um índice em memória para listagem e replay
+type CloudStore struct {
+	storage cloud.Storage
+	index   *MemoryStore
+}
+
+// NewCloudStore cria um CloudStore sobre o bucket configurado
+func NewCloudStore(cloudStorage cloud.Storage) *CloudStore {
+	return &CloudStore{
+		storage: cloudStorage,
+		index:   NewMemoryStore(defaultMaxRecordings),
+	}
+}
+
+// Save implementa Store
+func (s *CloudStore) Save(ctx context.Context, recording *models.Recording) error {
+	data, err := json.Marshal(recording)
+	if err != nil {
+		return err
+	}
+	err = retry.Do(ctx, retry.DefaultPolicy(), func(ctx context.Context) error {
+		return s.storage.UploadFile(ctx, "recordings/"+recording.ID+".json", bytes.NewReader(data))
+	})
+	if err != nil {
+		return err
+	}
+	return s.index.Save(ctx, recording)
+}
+
+// List implementa Store
+func (s *CloudStore) List(ctx context.Context) ([]models.Recording, error) {
+	return s.index.List(ctx)
+}
+
+// Get implementa Store
+func (s *CloudStore) Get(ctx context.Context, id string) (*models.Recording, error) {
+	return s.index.Get(ctx, id)
+}
@@ -0,0 +1,250 @@
+package redact
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Tag marca os campos de structs que nunca devem aparecer em respostas ou
+// linhas de log (ex.: hashes de senha e stack traces): `redact:"true"`
+const Tag = "redact"
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// walkCache guarda, por tipo, se os valores precisam ser percorridos
+var walkCache sync.Map
+
+// Value retorna v pronto para a serialização em JSON, sem os campos marcados
+// com redact:"true" (em qualquer nível). Os nomes e as opções das tags json
+// são respeitados e a ordem dos campos é mantida. Valores de tipos sem campos
+// marcados são retornados sem alteração
+func Value(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	return walk(reflect.ValueOf(v))
+}
+
+// Fields aplica Value aos valores dos campos de uma linha de log
+func Fields(fields map[string]interface{}) map[string]interface{} {
+	for key, value := range fields {
+		fields[key] = Value(value)
+	}
+	return fields
+}
+
+// Applies indica se valores do tipo podem conter campos marcados, ou seja, se
+// Value pode alterá-los
+func Applies(t reflect.Type) bool {
+	return needsWalk(t)
+}
+
+// needsWalk indica se valores do tipo podem conter campos marcados: tipos com
+// campos marcados ou com interfaces, cujo conteúdo só é conhecido em execução
+func needsWalk(t reflect.Type) bool {
+	if cached, ok := walkCache.Load(t); ok {
+		return cached.(bool)
+	}
+	result := scan(t, make(map[reflect.Type]bool))
+	walkCache.Store(t, result)
+	return result
+}
+
+// scan implementa needsWalk, evitando ciclos em tipos recursivos
+func scan(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[t] || isLeaf(t) {
+		return false
+	}
+	seen[t] = true
+
+	switch t.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		return scan(t.Elem(), seen)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if field.Tag.Get(Tag) == "true" || scan(field.Type, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isLeaf indica se o tipo define a própria serialização
+func isLeaf(t reflect.Type) bool {
+	if t.Kind() == reflect.Interface {
+		return false
+	}
+	return t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) ||
+		reflect.PointerTo(t).Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType)
+}
+
+// walk copia o valor removendo os campos marcados
+func walk(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	if !needsWalk(v.Type()) {
+		return v.Interface()
+	}
+
+	switch v.Kind() {
+	case reflect.Interface, reflect.Pointer:
+		if v.IsNil() {
+			return nil
+		}
+		return walk(v.Elem())
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		items := make([]interface{}, v.Len())
+		for i := range items {
+			items[i] = walk(v.Index(i))
+		}
+		return items
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		entries := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			entries[mapKey(iter.Key())] = walk(iter.Value())
+		}
+		return entries
+	case reflect.Struct:
+		var obj object
+		walkStruct(v, &obj)
+		return obj
+	}
+	return v.Interface()
+}
+
+// walkStruct adiciona a obj os campos serializáveis da struct, incluindo os
+// das structs exportadas embutidas sem nome na tag json (os campos não
+// exportados, inclusive os embutidos, são ignorados)
+func walkStruct(v reflect.Value, obj *object) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() || field.Tag.Get(Tag) == "true" {
+			continue
+		}
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		value := v.Field(i)
+		if field.Anonymous && name == "" {
+			embedded := value
+			if embedded.Kind() == reflect.Pointer {
+				if embedded.IsNil() {
+					continue
+				}
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct && !isLeaf(embedded.Type()) {
+				walkStruct(embedded, obj)
+				continue
+			}
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		if hasOption(opts, "omitempty") && isEmpty(value) {
+			continue
+		}
+		*obj = append(*obj, member{name: name, value: walk(value)})
+	}
+}
+
+// hasOption indica se as opções da tag json incluem a opção
+func hasOption(opts, option string) bool {
+	for _, opt := range strings.Split(opts, ",") {
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
+
+// isEmpty segue a definição de valor vazio do omitempty do encoding/json
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return v.IsZero()
+	case reflect.Interface, reflect.Pointer:
+		return v.IsNil()
+	}
+	return false
+}
+
+// mapKey converte a chave de um mapa no nome usado pelo encoding/json
+func mapKey(key reflect.Value) string {
+	if key.Kind() == reflect.String {
+		return key.String()
+	}
+	if marshaler, ok := key.Interface().(encoding.TextMarshaler); ok {
+		if text, err := marshaler.MarshalText(); err == nil {
+			return string(text)
+		}
+	}
+	return fmt.Sprint(key.Interface())
+}
+
+// object é uma struct já sem os campos marcados, serializada na ordem original
+type object []member
+
+// member é um campo de object
+type member struct {
+	name  string
+	value interface{}
+}
+
+// MarshalJSON implementa json.Marshaler
+func (o object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(m.name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(m.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
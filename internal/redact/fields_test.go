@@ -0,0 +1,92 @@
+package redact
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Audit struct {
+	By string `json:"by"`
+}
+
+type account struct {
+	Audit
+	ID        string            `json:"id"`
+	Hash      string            `json:"hash" redact:"true"`
+	Note      string            `json:"note,omitempty"`
+	Hidden    string            `json:"-"`
+	CreatedAt time.Time         `json:"created_at"`
+	Extra     interface{}       `json:"extra,omitempty"`
+	Children  []account         `json:"children,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+}
+
+type plain struct {
+	Name  string `json:"name"`
+	Value int    `json:"value"`
+}
+
+func TestValue(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	value := map[string]interface{}{
+		"account": &account{
+			Audit:     Audit{By: "admin"},
+			ID:        "1",
+			Hash:      "segredo",
+			Hidden:    "oculto",
+			CreatedAt: created,
+			Extra:     account{ID: "2", Hash: "segredo"},
+			Children:  []account{{ID: "3", Hash: "segredo"}},
+			Labels:    map[string]string{"a": "b"},
+		},
+	}
+
+	body, err := json.Marshal(Value(value))
+	require.NoError(t, err)
+	assert.NotContains(t, string(body), "segredo")
+	assert.JSONEq(t, `{"account": {
+		"by": "admin",
+		"id": "1",
+		"created_at": "2024-01-02T03:04:05Z",
+		"extra": {"by": "", "id": "2", "created_at": "0001-01-01T00:00:00Z"},
+		"children": [{"by": "", "id": "3", "created_at": "0001-01-01T00:00:00Z"}],
+		"labels": {"a": "b"}
+	}}`, string(body))
+
+	// A ordem dos campos da struct é mantida
+	body, err = json.Marshal(Value(account{ID: "1"}))
+	require.NoError(t, err)
+	assert.Equal(t, `{"by":"","id":"1","created_at":"0001-01-01T00:00:00Z"}`, string(body))
+
+	// Tipos sem campos marcados nem interfaces são retornados como estão
+	p := plain{Name: "a", Value: 1}
+	assert.Equal(t, p, Value(p))
+	assert.Nil(t, Value(nil))
+	assert.Nil(t, Value((*account)(nil)))
+}
+
+func TestFields(t *testing.T) {
+	fields := Fields(map[string]interface{}{
+		"account": account{ID: "1", Hash: "segredo"},
+		"status":  200,
+	})
+
+	body, err := json.Marshal(fields)
+	require.NoError(t, err)
+	assert.NotContains(t, string(body), "segredo")
+	assert.Equal(t, 200, fields["status"])
+}
+
+func TestMap(t *testing.T) {
+	fields := map[string]interface{}{"password": "segredo", "linha": 3}
+	result := Map(fields)
+
+	assert.NotEqual(t, "segredo", result["password"])
+	assert.Equal(t, 3, result["linha"])
+	// O mapa original não é alterado
+	assert.Equal(t, "segredo", fields["password"])
+}
@@ -0,0 +1,107 @@
+// Package redact remove dados sensíveis (credenciais, tokens, senhas) de
+// cabeçalhos e corpos de requisições antes que sejam gravados ou registrados
+package redact
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// redacted substitui os valores sensíveis
+const redacted = "[REDACTED]"
+
+// sensitiveHeaders são cabeçalhos cujo valor nunca é gravado ou registrado
+var sensitiveHeaders = map[string]bool{
+	"Authorization": true,
+	"Cookie":        true,
+	"Set-Cookie":    true,
+	"X-Api-Key":     true,
+}
+
+// sensitiveFields são trechos de nomes de campos JSON cujo valor nunca é gravado ou registrado
+var sensitiveFields = []string{"password", "token", "secret", "api_key", "apikey"}
+
+// IsSensitiveHeader indica se o valor do cabeçalho nunca deve ser gravado
+func IsSensitiveHeader(name string) bool {
+	return sensitiveHeaders[http.CanonicalHeaderKey(name)]
+}
+
+// Headers copia os cabeçalhos substituindo os valores sensíveis
+func Headers(headers http.Header) map[string][]string {
+	if len(headers) == 0 {
+		return nil
+	}
+	result := make(map[string][]string, len(headers))
+	for name, values := range headers {
+		if IsSensitiveHeader(name) {
+			result[name] = []string{redacted}
+			continue
+		}
+		result[name] = append([]string(nil), values...)
+	}
+	return result
+}
+
+// Body remove valores sensíveis de corpos JSON. Corpos em outros formatos são
+// retornados sem alteração
+func Body(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return string(body)
+	}
+
+	sanitized, err := json.Marshal(sanitizeValue(data))
+	if err != nil {
+		return string(body)
+	}
+	return string(sanitized)
+}
+
+// Map copia os campos (ex.: de uma linha de log) substituindo os valores dos
+// campos sensíveis pelo nome
+func Map(fields map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(fields))
+	for key, value := range fields {
+		if isSensitiveField(key) {
+			result[key] = redacted
+			continue
+		}
+		result[key] = value
+	}
+	return result
+}
+
+// sanitizeValue percorre o JSON substituindo os campos sensíveis
+func sanitizeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if isSensitiveField(key) {
+				v[key] = redacted
+				continue
+			}
+			v[key] = sanitizeValue(field)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = sanitizeValue(item)
+		}
+	}
+	return value
+}
+
+// isSensitiveField indica se o nome do campo sugere um dado sensível
+func isSensitiveField(name string) bool {
+	name = strings.ToLower(name)
+	for _, field := range sensitiveFields {
+		if strings.Contains(name, field) {
+			return true
+		}
+	}
+	return false
+}
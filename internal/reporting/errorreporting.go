@@ -0,0 +1,118 @@
+package reporting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"callable-api/internal/version"
+	"callable-api/pkg/httpclient"
+)
+
+// errorReportingURL é o endpoint REST do Cloud Error Reporting
+const errorReportingURL = "https://clouderrorreporting.googleapis.com/v1beta1"
+
+// defaultService identifica a API nos relatórios quando nenhum serviço é configurado
+const defaultService = "callable-api"
+
+// ErrorReportingClient implementa Reporter usando a API REST do Cloud Error Reporting
+type ErrorReportingClient struct {
+	baseURL   string
+	projectID string
+	apiKey    string
+	service   string
+	client    *http.Client
+}
+
+// NewErrorReportingClient cria um novo cliente a partir da configuração
+func NewErrorReportingClient(cfg Config) *ErrorReportingClient {
+	service := cfg.Service
+	if service == "" {
+		service = defaultService
+	}
+
+	return &ErrorReportingClient{
+		baseURL:   errorReportingURL,
+		projectID: cfg.ProjectID,
+		apiKey:    cfg.APIKey,
+		service:   service,
+		client:    httpclient.New(httpclient.Config{Timeout: 5 * time.Second}),
+	}
+}
+
+// reportedErrorEvent é o formato de evento aceito por events:report
+type reportedErrorEvent struct {
+	EventTime      string         `json:"eventTime"`
+	ServiceContext serviceContext `json:"serviceContext"`
+	Message        string         `json:"message"`
+	Context        errorContext   `json:"context"`
+}
+
+type serviceContext struct {
+	Service string `json:"service"`
+	Version string `json:"version,omitempty"`
+}
+
+type errorContext struct {
+	HTTPRequest httpRequestContext `json:"httpRequest"`
+}
+
+type httpRequestContext struct {
+	Method             string `json:"method"`
+	URL                string `json:"url"`
+	UserAgent          string `json:"userAgent,omitempty"`
+	RemoteIP           string `json:"remoteIp,omitempty"`
+	ResponseStatusCode int    `json:"responseStatusCode"`
+}
+
+// Report implementa Reporter. O Error Reporting exige que a mensagem contenha
+// o stack trace para agrupar as ocorrências
+func (e *ErrorReportingClient) Report(ctx context.Context, report Report) error {
+	event := reportedErrorEvent{
+		EventTime: report.OccurredAt.UTC().Format(time.RFC3339Nano),
+		ServiceContext: serviceContext{
+			Service: e.service,
+			Version: version.Get().Version,
+		},
+		Message: fmt.Sprintf("panic: %s [error_id=%s]\n\n%s", report.Message, report.ErrorID, report.Stack),
+		Context: errorContext{
+			HTTPRequest: httpRequestContext{
+				Method:             report.Request.Method,
+				URL:                report.Request.URL,
+				UserAgent:          report.Request.UserAgent,
+				RemoteIP:           report.Request.ClientIP,
+				ResponseStatusCode: http.StatusInternalServerError,
+			},
+		},
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("reporting: falha ao serializar evento: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/projects/%s/events:report?key=%s",
+		e.baseURL, url.PathEscape(e.projectID), url.QueryEscape(e.apiKey))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("reporting: falha ao criar requisição: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("reporting: falha na comunicação com o Error Reporting: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("reporting: Error Reporting respondeu com status %d: %s", resp.StatusCode, detail)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package reporting
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorReportingClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/projects/my-project/events:report", r.URL.Path)
+		assert.Equal(t, "secret", r.URL.Query().Get("key"))
+
+		var event reportedErrorEvent
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+		assert.Equal(t, "callable-api", event.ServiceContext.Service)
+		assert.Contains(t, event.Message, "panic: boom [error_id=abc]")
+		assert.Contains(t, event.Message, "goroutine 1 [running]")
+		assert.Equal(t, "GET", event.Context.HTTPRequest.Method)
+		assert.Equal(t, http.StatusInternalServerError, event.Context.HTTPRequest.ResponseStatusCode)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := NewErrorReportingClient(Config{ProjectID: "my-project", APIKey: "secret"})
+	client.baseURL = server.URL
+
+	err := client.Report(context.Background(), Report{
+		ErrorID:    "abc",
+		Message:    "boom",
+		Stack:      "goroutine 1 [running]:\nmain.main()",
+		Request:    Request{Method: "GET", URL: "/api/v1/data"},
+		OccurredAt: time.Now(),
+	})
+	assert.NoError(t, err)
+}
+
+func TestErrorReportingClient_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid key", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	client := NewErrorReportingClient(Config{ProjectID: "my-project", APIKey: "wrong"})
+	client.baseURL = server.URL
+
+	err := client.Report(context.Background(), Report{ErrorID: "abc", OccurredAt: time.Now()})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "403")
+}
+
+func TestNew(t *testing.T) {
+	assert.IsType(t, noopReporter{}, New(Config{ProjectID: "my-project"}))
+	assert.IsType(t, &ErrorReportingClient{}, New(Config{ProjectID: "my-project", APIKey: "secret"}))
+}
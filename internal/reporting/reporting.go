@@ -0,0 +1,70 @@
+// Package reporting encaminha falhas inesperadas (panics recuperados) para uma
+// integração de relatórios de erro, como o Google Cloud Error Reporting
+package reporting
+
+import (
+	"context"
+	"time"
+)
+
+// Request é o retrato sanitizado da requisição que causou a falha
+type Request struct {
+	Method    string              `json:"method"`
+	URL       string              `json:"url"`
+	Route     string              `json:"route,omitempty"`
+	Headers   map[string][]string `json:"headers,omitempty"`
+	Body      string              `json:"body,omitempty"`
+	ClientIP  string              `json:"client_ip,omitempty"`
+	UserAgent string              `json:"user_agent,omitempty"`
+}
+
+// Report descreve uma falha inesperada
+type Report struct {
+	ErrorID    string
+	Message    string
+	Stack      string `redact:"true"`
+	Request    Request
+	OccurredAt time.Time
+}
+
+// Reporter encaminha relatórios de erro para uma integração externa
+type Reporter interface {
+	Report(ctx context.Context, report Report) error
+}
+
+// ReporterFunc adapta uma função comum para a interface Reporter
+type ReporterFunc func(ctx context.Context, report Report) error
+
+// Report implementa Reporter
+func (f ReporterFunc) Report(ctx context.Context, report Report) error {
+	return f(ctx, report)
+}
+
+// noopReporter descarta os relatórios quando nenhuma integração foi configurada
+type noopReporter struct{}
+
+// Report implementa Reporter
+func (noopReporter) Report(context.Context, Report) error {
+	return nil
+}
+
+// Config agrupa as configurações do Cloud Error Reporting
+type Config struct {
+	ProjectID string // vazio desativa a integração
+	APIKey    string // vazio desativa a integração
+	Service   string
+}
+
+// Enabled retorna true se a integração com o Error Reporting foi configurada
+func (c Config) Enabled() bool {
+	return c.ProjectID != "" && c.APIKey != ""
+}
+
+// New cria o Reporter correspondente à configuração. Sem configuração, os
+// relatórios são descartados (o stack trace continua nos logs)
+func New(cfg Config) Reporter {
+	if !cfg.Enabled() {
+		return noopReporter{}
+	}
+	return NewErrorReportingClient(cfg)
+}
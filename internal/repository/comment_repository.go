@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"callable-api/internal/ids"
+	"callable-api/internal/models"
+	"callable-api/pkg/errors"
+	"context"
+	stderrors "errors"
+	"time"
+)
+
+// ErrCommentNotFound é a causa do erro NotFound de comentários inexistentes,
+// para diferenciá-los de itens inexistentes
+var ErrCommentNotFound = stderrors.New("comentário não encontrado")
+
+// CommentRepository define as operações de persistência dos comentários de itens
+type CommentRepository interface {
+	// Create grava o comentário, gerando o ID e a data de criação. Comentários
+	// sem ThreadID iniciam uma nova conversa
+	Create(ctx context.Context, comment *models.Comment) (*models.Comment, error)
+
+	// FindByID retorna um comentário pelo ID
+	FindByID(ctx context.Context, id string) (*models.Comment, error)
+
+	// ListThreads retorna, com paginação, os comentários que iniciam conversas
+	// no item, dos mais antigos para os mais recentes
+	ListThreads(ctx context.Context, itemID string, page, limit int) ([]models.Comment, int, error)
+
+	// ListReplies retorna as respostas das conversas informadas, das mais
+	// antigas para as mais recentes
+	ListReplies(ctx context.Context, threadIDs []string) ([]models.Comment, error)
+
+	// Update substitui um comentário existente
+	Update(ctx context.Context, comment *models.Comment) (*models.Comment, error)
+
+	// Delete remove um comentário
+	Delete(ctx context.Context, id string) error
+}
+
+// InMemoryCommentRepository implementa CommentRepository em memória
+type InMemoryCommentRepository struct {
+	store *MemoryRepository[models.Comment]
+}
+
+// NewInMemoryCommentRepository cria um novo repositório em memória
+func NewInMemoryCommentRepository() *InMemoryCommentRepository {
+	return &InMemoryCommentRepository{
+		store: NewMemoryRepository(
+			func(comment *models.Comment) *string { return &comment.ID },
+			func() error { return errors.NewNotFoundError("Comentário não encontrado", ErrCommentNotFound) },
+		).
+			WithInsertHook(func(comment *models.Comment) {
+				comment.CreatedAt = time.Now().UTC()
+				if comment.ThreadID == "" {
+					comment.ThreadID = comment.ID
+				}
+			}),
+	}
+}
+
+// WithIDGenerator define o formato dos IDs dos novos registros (padrão: UUID)
+func (r *InMemoryCommentRepository) WithIDGenerator(gen ids.Generator) *InMemoryCommentRepository {
+	r.store.WithIDGenerator(gen.NewID)
+	return r
+}
+
+// Create implementa CommentRepository.Create
+func (r *InMemoryCommentRepository) Create(ctx context.Context, comment *models.Comment) (*models.Comment, error) {
+	created := *comment
+	created.ID = ""
+	return r.store.Insert(ctx, &created)
+}
+
+// FindByID implementa CommentRepository.FindByID
+func (r *InMemoryCommentRepository) FindByID(ctx context.Context, id string) (*models.Comment, error) {
+	return r.store.Get(ctx, id)
+}
+
+// ListThreads implementa CommentRepository.ListThreads. A ordem de inserção já
+// é a ordem cronológica
+func (r *InMemoryCommentRepository) ListThreads(ctx context.Context, itemID string, page, limit int) ([]models.Comment, int, error) {
+	return r.store.List(ctx, Query[models.Comment]{
+		Match: func(c *models.Comment) bool {
+			return c.ItemID == itemID && c.ParentID == ""
+		},
+	}, page, limit)
+}
+
+// ListReplies implementa CommentRepository.ListReplies
+func (r *InMemoryCommentRepository) ListReplies(ctx context.Context, threadIDs []string) ([]models.Comment, error) {
+	threads := make(map[string]bool, len(threadIDs))
+	for _, id := range threadIDs {
+		threads[id] = true
+	}
+	return r.store.Filter(ctx, func(c *models.Comment) bool {
+		return c.ParentID != "" && threads[c.ThreadID]
+	})
+}
+
+// Update implementa CommentRepository.Update
+func (r *InMemoryCommentRepository) Update(ctx context.Context, comment *models.Comment) (*models.Comment, error) {
+	return r.store.Update(ctx, comment)
+}
+
+// Delete implementa CommentRepository.Delete
+func (r *InMemoryCommentRepository) Delete(ctx context.Context, id string) error {
+	return r.store.Delete(ctx, id)
+}
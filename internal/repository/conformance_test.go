@@ -0,0 +1,68 @@
+package repository_test
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"callable-api/internal/datakeys"
+	"callable-api/internal/repository"
+	"callable-api/internal/repository/conformancetest"
+	"callable-api/internal/repository/testsupport"
+)
+
+// nopObserver descarta as métricas dos repositórios instrumentados
+type nopObserver struct{}
+
+func (nopObserver) Observe(component, operation string, latency time.Duration, failed bool) {}
+
+func TestInMemoryItemRepository_Conformance(t *testing.T) {
+	conformancetest.RunItemRepository(t, func(t *testing.T) repository.ItemRepository {
+		return repository.NewEmptyInMemoryItemRepository()
+	})
+}
+
+func TestInMemoryUserRepository_Conformance(t *testing.T) {
+	conformancetest.RunUserRepository(t, func(t *testing.T) repository.UserRepository {
+		return testsupport.NewUserRepository()
+	})
+}
+
+// Os decoradores de instrumentação não alteram o comportamento dos repositórios
+func TestInstrumentedRepositories_Conformance(t *testing.T) {
+	instrument := repository.NewInstrumentation(nopObserver{})
+	conformancetest.RunItemRepository(t, func(t *testing.T) repository.ItemRepository {
+		return instrument.Items(repository.NewEmptyInMemoryItemRepository())
+	})
+	conformancetest.RunUserRepository(t, func(t *testing.T) repository.UserRepository {
+		return instrument.Users(testsupport.NewUserRepository())
+	})
+}
+
+// O versionamento dos registros é transparente para quem usa os repositórios
+func TestVersionedRepositories_Conformance(t *testing.T) {
+	versioning := repository.NewVersioning(repository.ItemSchema, repository.UserSchema)
+	conformancetest.RunItemRepository(t, func(t *testing.T) repository.ItemRepository {
+		return versioning.Items(repository.NewEmptyInMemoryItemRepository())
+	})
+	conformancetest.RunUserRepository(t, func(t *testing.T) repository.UserRepository {
+		return versioning.Users(testsupport.NewUserRepository())
+	})
+}
+
+// A cifragem em envelope é transparente para quem usa os repositórios
+func TestEncryptedRepositories_Conformance(t *testing.T) {
+	newEncryption := func(t *testing.T) *repository.Encryption {
+		master, err := datakeys.ParseMasterKey(base64.StdEncoding.EncodeToString(make([]byte, 32)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return repository.NewEncryption(datakeys.NewKeyring(master, datakeys.NewMemoryStore()))
+	}
+	conformancetest.RunItemRepository(t, func(t *testing.T) repository.ItemRepository {
+		return newEncryption(t).Items(repository.NewEmptyInMemoryItemRepository())
+	})
+	conformancetest.RunUserRepository(t, func(t *testing.T) repository.UserRepository {
+		return newEncryption(t).Users(testsupport.NewUserRepository())
+	})
+}
@@ -0,0 +1,483 @@
+// Package conformancetest contém a suíte de conformidade dos repositórios:
+// toda implementação de repository.ItemRepository e repository.UserRepository
+// (em memória ou em um banco) deve passar por ela, para que a API se comporte
+// da mesma forma com qualquer backend.
+//
+// Os testes de cada implementação chamam a suíte com uma função que cria um
+// repositório novo para cada caso:
+//
+//	func TestConformance(t *testing.T) {
+//		conformancetest.RunItemRepository(t, func(t *testing.T) repository.ItemRepository {
+//			return NewEmptyInMemoryItemRepository()
+//		})
+//	}
+package conformancetest
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"callable-api/internal/errcodes"
+	"callable-api/internal/filter"
+	"callable-api/internal/models"
+	"callable-api/internal/repository"
+	"callable-api/pkg/errors"
+)
+
+// ItemFactory cria um repositório de itens vazio
+type ItemFactory func(t *testing.T) repository.ItemRepository
+
+// UserFactory cria um repositório de usuários. O repositório pode conter
+// usuários pré-cadastrados, desde que não usem o domínio dos emails da suíte
+// (conformance.test)
+type UserFactory func(t *testing.T) repository.UserRepository
+
+// allItems dá acesso a todos os itens
+var allItems = models.ItemAccess{All: true}
+
+// RunItemRepository executa a suíte de conformidade de ItemRepository
+func RunItemRepository(t *testing.T, newRepo ItemFactory) {
+	t.Run("CreateAndFind", func(t *testing.T) { testItemCreateAndFind(t, newRepo(t)) })
+	t.Run("NotFound", func(t *testing.T) { testItemNotFound(t, newRepo(t)) })
+	t.Run("Update", func(t *testing.T) { testItemUpdate(t, newRepo(t)) })
+	t.Run("Pagination", func(t *testing.T) { testItemPagination(t, newRepo(t)) })
+	t.Run("Access", func(t *testing.T) { testItemAccess(t, newRepo(t)) })
+	t.Run("Filters", func(t *testing.T) { testItemFilters(t, newRepo(t)) })
+	t.Run("Lookups", func(t *testing.T) { testItemLookups(t, newRepo(t)) })
+	t.Run("Iterate", func(t *testing.T) { testItemIterate(t, newRepo(t)) })
+	t.Run("Search", func(t *testing.T) { testItemSearch(t, newRepo(t)) })
+}
+
+// RunUserRepository executa a suíte de conformidade de UserRepository
+func RunUserRepository(t *testing.T, newRepo UserFactory) {
+	t.Run("CreateAndFind", func(t *testing.T) { testUserCreateAndFind(t, newRepo(t)) })
+	t.Run("NotFound", func(t *testing.T) { testUserNotFound(t, newRepo(t)) })
+	t.Run("Conflicts", func(t *testing.T) { testUserConflicts(t, newRepo(t)) })
+	t.Run("Update", func(t *testing.T) { testUserUpdate(t, newRepo(t)) })
+	t.Run("Pagination", func(t *testing.T) { testUserPagination(t, newRepo(t)) })
+	t.Run("Delete", func(t *testing.T) { testUserDelete(t, newRepo(t)) })
+	t.Run("Authenticate", func(t *testing.T) { testUserAuthenticate(t, newRepo(t)) })
+}
+
+// assertErrorType verifica que err é um AppError do tipo informado
+func assertErrorType(t *testing.T, err error, errType string) {
+	t.Helper()
+	var appErr *errors.AppError
+	if assert.True(t, stderrors.As(err, &appErr), "esperado AppError %s, obtido %v", errType, err) {
+		assert.Equal(t, errType, appErr.Type)
+	}
+}
+
+// createItems cria n itens do dono, com nomes e valores numerados
+func createItems(t *testing.T, repo repository.ItemRepository, ownerID string, n int) []models.Item {
+	t.Helper()
+	items := make([]models.Item, 0, n)
+	for i := 1; i <= n; i++ {
+		item, err := repo.Create(context.Background(), &models.InputData{
+			Name:    fmt.Sprintf("Item %d", i),
+			Value:   fmt.Sprintf("%s-%d", ownerID, i),
+			Email:   "item@conformance.test",
+			OwnerID: ownerID,
+		})
+		require.NoError(t, err)
+		items = append(items, *item)
+	}
+	return items
+}
+
+func ids(items []models.Item) []string {
+	result := make([]string, len(items))
+	for i, item := range items {
+		result[i] = item.ID
+	}
+	return result
+}
+
+func testItemCreateAndFind(t *testing.T, repo repository.ItemRepository) {
+	ctx := context.Background()
+
+	created, err := repo.Create(ctx, &models.InputData{
+		Name:        "Caderno",
+		Value:       "CAD-1",
+		Description: "Caderno pautado",
+		Email:       "ana@conformance.test",
+		OwnerID:     "ana",
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, created.ID)
+	assert.NotEmpty(t, created.CreatedAt)
+	assert.Equal(t, "Caderno", created.Name)
+	assert.Equal(t, "CAD-1", created.Value)
+	assert.Equal(t, "Caderno pautado", created.Description)
+	assert.Equal(t, "ana@conformance.test", created.Email)
+	assert.Equal(t, "ana", created.OwnerID)
+
+	found, err := repo.FindByID(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, created, found)
+
+	// Os IDs são únicos
+	other, err := repo.Create(ctx, &models.InputData{Name: "Caneta", Value: "CAN-1"})
+	require.NoError(t, err)
+	assert.NotEqual(t, created.ID, other.ID)
+
+	// Alterar o item retornado não altera o item gravado
+	found.Name = "Alterado"
+	again, err := repo.FindByID(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Caderno", again.Name)
+}
+
+func testItemNotFound(t *testing.T, repo repository.ItemRepository) {
+	ctx := context.Background()
+
+	_, err := repo.FindByID(ctx, "inexistente")
+	assertErrorType(t, err, errcodes.TypeNotFound)
+
+	_, err = repo.Update(ctx, &models.Item{ID: "inexistente", Name: "Item", Value: "v"})
+	assertErrorType(t, err, errcodes.TypeNotFound)
+
+	// As buscas sem resultado não são erros
+	item, err := repo.FindByName(ctx, "ana", "inexistente")
+	assert.NoError(t, err)
+	assert.Nil(t, item)
+
+	item, err = repo.FindByValue(ctx, "inexistente")
+	assert.NoError(t, err)
+	assert.Nil(t, item)
+}
+
+func testItemUpdate(t *testing.T, repo repository.ItemRepository) {
+	ctx := context.Background()
+	created := createItems(t, repo, "ana", 1)[0]
+
+	// O dono e a data de criação são preservados
+	updated, err := repo.Update(ctx, &models.Item{
+		ID:        created.ID,
+		Name:      "Renomeado",
+		Value:     "NOVO",
+		OwnerID:   "bruno",
+		CreatedAt: "2000-01-01T00:00:00Z",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Renomeado", updated.Name)
+	assert.Equal(t, "ana", updated.OwnerID)
+	assert.Equal(t, created.CreatedAt, updated.CreatedAt)
+
+	found, err := repo.FindByID(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, updated, found)
+}
+
+func testItemPagination(t *testing.T, repo repository.ItemRepository) {
+	ctx := context.Background()
+	items := createItems(t, repo, "", 5)
+
+	// As páginas seguem a ordem de criação e informam o total
+	page, total, err := repo.FindAll(ctx, allItems, 1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 5, total)
+	assert.Equal(t, ids(items[0:2]), ids(page))
+
+	page, total, err = repo.FindAll(ctx, allItems, 3, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 5, total)
+	assert.Equal(t, ids(items[4:5]), ids(page))
+
+	// Páginas além do fim são vazias, com o mesmo total
+	page, total, err = repo.FindAll(ctx, allItems, 4, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 5, total)
+	assert.Empty(t, page)
+
+	// Página e limite inválidos usam os padrões
+	page, _, err = repo.FindAll(ctx, allItems, 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, ids(items), ids(page))
+}
+
+func testItemAccess(t *testing.T, repo repository.ItemRepository) {
+	ctx := context.Background()
+	public := createItems(t, repo, "", 1)[0]
+	ana := createItems(t, repo, "ana", 1)[0]
+	bruno := createItems(t, repo, "bruno", 1)[0]
+
+	visible := func(access models.ItemAccess) []string {
+		items, total, err := repo.FindAll(ctx, access, 1, 10)
+		require.NoError(t, err)
+		assert.Len(t, items, total)
+		return ids(items)
+	}
+
+	assert.Equal(t, []string{public.ID, ana.ID, bruno.ID}, visible(allItems))
+	assert.Equal(t, []string{public.ID}, visible(models.ItemAccess{}))
+	assert.Equal(t, []string{public.ID, ana.ID}, visible(models.ItemAccess{UserID: "ana"}))
+	assert.Equal(t, []string{public.ID, ana.ID, bruno.ID}, visible(models.ItemAccess{
+		UserID:    "ana",
+		SharedIDs: map[string]bool{bruno.ID: true},
+	}))
+}
+
+func testItemFilters(t *testing.T, repo repository.ItemRepository) {
+	ctx := context.Background()
+	items := createItems(t, repo, "", 3)
+
+	cond, err := filter.NewCondition(filter.Field{Name: "name", Kind: filter.String}, filter.Eq, "Item 2")
+	require.NoError(t, err)
+	page, total, err := repo.FindAll(ctx, allItems, 1, 10, cond)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, []string{items[1].ID}, ids(page))
+
+	cond, err = filter.NewCondition(filter.Field{Name: "name", Kind: filter.String}, filter.Ne, "Item 2")
+	require.NoError(t, err)
+	page, total, err = repo.FindAll(ctx, allItems, 1, 10, cond)
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, []string{items[0].ID, items[2].ID}, ids(page))
+
+	// Campos que não são filtráveis são rejeitados
+	cond, err = filter.NewCondition(filter.Field{Name: "email", Kind: filter.String}, filter.Eq, "x")
+	require.NoError(t, err)
+	_, _, err = repo.FindAll(ctx, allItems, 1, 10, cond)
+	assertErrorType(t, err, errcodes.TypeBadRequest)
+}
+
+func testItemLookups(t *testing.T, repo repository.ItemRepository) {
+	ctx := context.Background()
+	created := createItems(t, repo, "ana", 2)
+
+	// O nome é comparado sem diferenciar maiúsculas de minúsculas, por dono
+	item, err := repo.FindByName(ctx, "ana", "  item 2 ")
+	require.NoError(t, err)
+	require.NotNil(t, item)
+	assert.Equal(t, created[1].ID, item.ID)
+
+	item, err = repo.FindByName(ctx, "bruno", "Item 2")
+	require.NoError(t, err)
+	assert.Nil(t, item)
+
+	item, err = repo.FindByValue(ctx, "ana-1")
+	require.NoError(t, err)
+	require.NotNil(t, item)
+	assert.Equal(t, created[0].ID, item.ID)
+}
+
+func testItemIterate(t *testing.T, repo repository.ItemRepository) {
+	ctx := context.Background()
+	ana := createItems(t, repo, "ana", 3)
+	createItems(t, repo, "bruno", 2)
+
+	var visited []string
+	err := repo.Iterate(ctx, repository.ItemFilter{Access: allItems, OwnerID: "ana"}, func(item *models.Item) error {
+		visited = append(visited, item.ID)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ids(ana), visited)
+
+	// ErrStopIteration interrompe sem erro; outros erros são retornados
+	count := 0
+	err = repo.Iterate(ctx, repository.ItemFilter{Access: allItems}, func(item *models.Item) error {
+		count++
+		return repository.ErrStopIteration
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	failure := stderrors.New("falha")
+	err = repo.Iterate(ctx, repository.ItemFilter{Access: allItems}, func(item *models.Item) error {
+		return failure
+	})
+	assert.ErrorIs(t, err, failure)
+}
+
+func testItemSearch(t *testing.T, repo repository.ItemRepository) {
+	ctx := context.Background()
+	notebook, err := repo.Create(ctx, &models.InputData{Name: "Caderno azul", Value: "CAD-1"})
+	require.NoError(t, err)
+	_, err = repo.Create(ctx, &models.InputData{Name: "Caneta", Value: "CAN-1", Description: "Tinta azul", OwnerID: "bruno"})
+	require.NoError(t, err)
+
+	// Apenas os itens visíveis que contêm os termos
+	items, total, err := repo.Search(ctx, models.ItemAccess{UserID: "ana"}, "azul", 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, []string{notebook.ID}, ids(items))
+
+	items, total, err = repo.Search(ctx, allItems, "azul", 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.Len(t, items, 2)
+
+	items, total, err = repo.Search(ctx, allItems, "  ", 1, 10)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+	assert.Empty(t, items)
+}
+
+// newUser cria um usuário da suíte com o email local@conformance.test
+func newUser(local string) *models.User {
+	return &models.User{
+		Email: local + "@conformance.test",
+		Name:  local,
+		Role:  "user",
+	}
+}
+
+func testUserCreateAndFind(t *testing.T, repo repository.UserRepository) {
+	created, err := repo.Create(newUser("ana"))
+	require.NoError(t, err)
+	require.NotEmpty(t, created.ID)
+	assert.False(t, created.CreatedAt.IsZero())
+	assert.False(t, created.UpdatedAt.IsZero())
+
+	found, err := repo.FindByID(created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, created.Email, found.Email)
+	assert.True(t, created.CreatedAt.Equal(found.CreatedAt))
+
+	found, err = repo.FindByEmail("ana@conformance.test")
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, found.ID)
+
+	// O ID informado é mantido
+	user := newUser("bruno")
+	user.ID = "conformance-bruno"
+	created, err = repo.Create(user)
+	require.NoError(t, err)
+	assert.Equal(t, "conformance-bruno", created.ID)
+}
+
+func testUserNotFound(t *testing.T, repo repository.UserRepository) {
+	_, err := repo.FindByID("inexistente")
+	assertErrorType(t, err, errcodes.TypeNotFound)
+
+	_, err = repo.FindByEmail("inexistente@conformance.test")
+	assertErrorType(t, err, errcodes.TypeNotFound)
+
+	user := newUser("ana")
+	user.ID = "inexistente"
+	_, err = repo.Update(user)
+	assertErrorType(t, err, errcodes.TypeNotFound)
+
+	err = repo.Delete("inexistente")
+	assertErrorType(t, err, errcodes.TypeNotFound)
+}
+
+func testUserConflicts(t *testing.T, repo repository.UserRepository) {
+	ana, err := repo.Create(newUser("ana"))
+	require.NoError(t, err)
+	bruno, err := repo.Create(newUser("bruno"))
+	require.NoError(t, err)
+
+	// Emails são únicos na criação e nas atualizações
+	_, err = repo.Create(newUser("ana"))
+	assertErrorType(t, err, errcodes.TypeConflict)
+
+	bruno.Email = ana.Email
+	_, err = repo.Update(bruno)
+	assertErrorType(t, err, errcodes.TypeConflict)
+
+	found, err := repo.FindByID(bruno.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "bruno@conformance.test", found.Email)
+
+	// Manter o próprio email não é conflito
+	ana.Name = "Ana Souza"
+	_, err = repo.Update(ana)
+	assert.NoError(t, err)
+}
+
+func testUserUpdate(t *testing.T, repo repository.UserRepository) {
+	created, err := repo.Create(newUser("ana"))
+	require.NoError(t, err)
+
+	user := *created
+	user.Name = "Ana Souza"
+	updated, err := repo.Update(&user)
+	require.NoError(t, err)
+	assert.Equal(t, "Ana Souza", updated.Name)
+	assert.True(t, created.CreatedAt.Equal(updated.CreatedAt))
+	assert.False(t, updated.UpdatedAt.Before(created.UpdatedAt))
+
+	found, err := repo.FindByID(created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Ana Souza", found.Name)
+}
+
+func testUserPagination(t *testing.T, repo repository.UserRepository) {
+	// Os usuários pré-cadastrados pelo repositório vêm antes dos da suíte
+	_, base, err := repo.List(1, 1)
+	require.NoError(t, err)
+
+	var created []string
+	for i := 1; i <= 3; i++ {
+		user, err := repo.Create(newUser(fmt.Sprintf("user%d", i)))
+		require.NoError(t, err)
+		created = append(created, user.ID)
+	}
+
+	var listed []string
+	for page := 1; ; page++ {
+		users, total, err := repo.List(page, 2)
+		require.NoError(t, err)
+		assert.Equal(t, base+3, total)
+		if len(users) == 0 {
+			break
+		}
+		assert.LessOrEqual(t, len(users), 2)
+		for _, user := range users {
+			listed = append(listed, user.ID)
+		}
+	}
+	require.Len(t, listed, base+3)
+	assert.Equal(t, created, listed[base:])
+}
+
+func testUserDelete(t *testing.T, repo repository.UserRepository) {
+	created, err := repo.Create(newUser("ana"))
+	require.NoError(t, err)
+
+	require.NoError(t, repo.Delete(created.ID))
+	_, err = repo.FindByID(created.ID)
+	assertErrorType(t, err, errcodes.TypeNotFound)
+
+	// O email fica livre para um novo cadastro
+	_, err = repo.Create(newUser("ana"))
+	assert.NoError(t, err)
+}
+
+func testUserAuthenticate(t *testing.T, repo repository.UserRepository) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("segredo123"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	user := newUser("ana")
+	user.Password = string(hash)
+	created, err := repo.Create(user)
+	require.NoError(t, err)
+
+	authenticated, err := repo.Authenticate("ana@conformance.test", "segredo123")
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, authenticated.ID)
+
+	// Senha errada e email desconhecido não se distinguem
+	_, err = repo.Authenticate("ana@conformance.test", "errada")
+	assertErrorType(t, err, errcodes.TypeUnauthorized)
+	_, err = repo.Authenticate("inexistente@conformance.test", "segredo123")
+	assertErrorType(t, err, errcodes.TypeUnauthorized)
+
+	// Contas bloqueadas não entram, mesmo com a senha correta
+	created.Disabled = true
+	_, err = repo.Update(created)
+	require.NoError(t, err)
+	_, err = repo.Authenticate("ana@conformance.test", "segredo123")
+	assertErrorType(t, err, errcodes.TypeForbidden)
+	assert.ErrorIs(t, err, repository.ErrAccountDisabled)
+}
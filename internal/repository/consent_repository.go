@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"callable-api/internal/ids"
+	"callable-api/internal/models"
+	"context"
+	stderrors "errors"
+	"sort"
+	"sync"
+)
+
+// ErrConsentRequired é a causa do erro de usuários que não aceitaram a versão
+// obrigatória dos termos de uso ou da política de privacidade, para que o
+// middleware e os handlers possam identificá-lo
+var ErrConsentRequired = stderrors.New("aceite dos termos pendente")
+
+// ConsentRepository define as operações de persistência dos aceites dos
+// termos de uso e da política de privacidade. Os aceites são apenas
+// acrescentados, formando o histórico de cada usuário
+type ConsentRepository interface {
+	// Record grava um novo aceite
+	Record(ctx context.Context, consent *models.Consent) (*models.Consent, error)
+
+	// ListByUser retorna os aceites do usuário, dos mais antigos para os mais recentes
+	ListByUser(ctx context.Context, userID string) ([]models.Consent, error)
+}
+
+// InMemoryConsentRepository implementa ConsentRepository em memória
+type InMemoryConsentRepository struct {
+	consents map[string][]models.Consent // userID -> aceites
+	ids      ids.Generator
+	mutex    sync.RWMutex
+}
+
+// NewInMemoryConsentRepository cria um novo repositório em memória
+func NewInMemoryConsentRepository() *InMemoryConsentRepository {
+	return &InMemoryConsentRepository{
+		consents: make(map[string][]models.Consent),
+		ids:      ids.UUID(),
+	}
+}
+
+// WithIDGenerator define o formato dos IDs dos aceites (padrão: UUID)
+func (r *InMemoryConsentRepository) WithIDGenerator(gen ids.Generator) *InMemoryConsentRepository {
+	r.ids = gen
+	return r
+}
+
+// Record implementa ConsentRepository.Record
+func (r *InMemoryConsentRepository) Record(ctx context.Context, consent *models.Consent) (*models.Consent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	saved := *consent
+	saved.ID = r.ids.NewID()
+	r.consents[saved.UserID] = append(r.consents[saved.UserID], saved)
+
+	return &saved, nil
+}
+
+// ListByUser implementa ConsentRepository.ListByUser
+func (r *InMemoryConsentRepository) ListByUser(ctx context.Context, userID string) ([]models.Consent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	result := append(make([]models.Consent, 0, len(r.consents[userID])), r.consents[userID]...)
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].AcceptedAt.Before(result[j].AcceptedAt)
+	})
+
+	return result, nil
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"callable-api/internal/clock"
+	"callable-api/internal/models"
+	"callable-api/pkg/errors"
+	"context"
+	"sync"
+)
+
+// DeviceBindingRepository define as operações de persistência dos vínculos
+// entre tokens de atualização e dispositivos. Os tokens são identificados pelo
+// hash, nunca pelo valor original
+type DeviceBindingRepository interface {
+	// Save registra o vínculo do token
+	Save(ctx context.Context, tokenHash string, binding *models.DeviceBinding) error
+
+	// Find retorna o vínculo do token (NotFound se inexistente ou expirado)
+	Find(ctx context.Context, tokenHash string) (*models.DeviceBinding, error)
+
+	// Delete remove o vínculo do token
+	Delete(ctx context.Context, tokenHash string) error
+}
+
+// InMemoryDeviceBindingRepository implementa DeviceBindingRepository em memória
+type InMemoryDeviceBindingRepository struct {
+	bindings map[string]models.DeviceBinding
+	mutex    sync.RWMutex
+	clock    clock.Clock
+}
+
+// NewInMemoryDeviceBindingRepository cria um novo repositório em memória
+func NewInMemoryDeviceBindingRepository() *InMemoryDeviceBindingRepository {
+	return &InMemoryDeviceBindingRepository{
+		bindings: make(map[string]models.DeviceBinding),
+		clock:    clock.System(),
+	}
+}
+
+// WithClock define o relógio usado na expiração dos registros
+func (r *InMemoryDeviceBindingRepository) WithClock(c clock.Clock) *InMemoryDeviceBindingRepository {
+	r.clock = c
+	return r
+}
+
+// Save implementa DeviceBindingRepository.Save, descartando os vínculos expirados
+func (r *InMemoryDeviceBindingRepository) Save(ctx context.Context, tokenHash string, binding *models.DeviceBinding) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	now := r.clock.Now()
+	for hash, existing := range r.bindings {
+		if now.After(existing.ExpiresAt) {
+			delete(r.bindings, hash)
+		}
+	}
+	r.bindings[tokenHash] = *binding
+
+	return nil
+}
+
+// Find implementa DeviceBindingRepository.Find
+func (r *InMemoryDeviceBindingRepository) Find(ctx context.Context, tokenHash string) (*models.DeviceBinding, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	binding, exists := r.bindings[tokenHash]
+	if !exists || r.clock.Now().After(binding.ExpiresAt) {
+		return nil, errors.NewNotFoundError("Vínculo de dispositivo não encontrado", nil)
+	}
+
+	return &binding, nil
+}
+
+// Delete implementa DeviceBindingRepository.Delete
+func (r *InMemoryDeviceBindingRepository) Delete(ctx context.Context, tokenHash string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delete(r.bindings, tokenHash)
+	return nil
+}
@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"context"
+	stderrors "errors"
+	"strings"
+
+	"callable-api/internal/datakeys"
+	"callable-api/internal/filter"
+	"callable-api/internal/models"
+	"callable-api/pkg/errors"
+	"callable-api/pkg/logger"
+)
+
+// Encryption envolve os repositórios com a cifragem em envelope dos dados
+// pessoais: a descrição e o email dos itens com dono são gravados cifrados
+// com a chave de dados do dono e decifrados de forma transparente na
+// leitura. Os itens públicos (sem dono) não são cifrados. Apagar o usuário
+// apaga a sua chave, e os campos cifrados dos seus itens passam a ser lidos
+// vazios
+type Encryption struct {
+	keys *datakeys.Keyring
+}
+
+// NewEncryption cria uma Encryption com as chaves do keyring
+func NewEncryption(keys *datakeys.Keyring) *Encryption {
+	return &Encryption{keys: keys}
+}
+
+// encrypt cifra os campos pessoais do item com a chave do dono
+func (e *Encryption) encrypt(ctx context.Context, ownerID string, description, email *string) error {
+	if ownerID == "" {
+		return nil
+	}
+	var err error
+	if *description, err = e.keys.Encrypt(ctx, ownerID, *description); err != nil {
+		return err
+	}
+	*email, err = e.keys.Encrypt(ctx, ownerID, *email)
+	return err
+}
+
+// decrypt decifra os campos pessoais do item. Sem a chave do dono (conta
+// removida), os campos ficam vazios
+func (e *Encryption) decrypt(ctx context.Context, item *models.Item) error {
+	for _, field := range []*string{&item.Description, &item.Email} {
+		value, err := e.keys.Decrypt(ctx, item.OwnerID, *field)
+		if stderrors.Is(err, datakeys.ErrKeyNotFound) {
+			value, err = "", nil
+		}
+		if err != nil {
+			logger.Error("Falha ao decifrar item", map[string]interface{}{
+				"id":    item.ID,
+				"error": err.Error(),
+			})
+			return err
+		}
+		*field = value
+	}
+	return nil
+}
+
+// decryptAll decifra os itens de uma listagem
+func (e *Encryption) decryptAll(ctx context.Context, items []models.Item) error {
+	for i := range items {
+		if err := e.decrypt(ctx, &items[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Items cifra os campos pessoais de um ItemRepository
+func (e *Encryption) Items(next ItemRepository) ItemRepository {
+	return &encryptedItemRepository{next: next, enc: e}
+}
+
+type encryptedItemRepository struct {
+	next ItemRepository
+	enc  *Encryption
+}
+
+// encryptedFields são os campos gravados cifrados, que não podem ser filtrados
+var encryptedFields = map[string]bool{"description": true, "email": true}
+
+// FindAll recusa as condições sobre os campos cifrados, que o repositório
+// interno compararia com o texto cifrado
+func (r *encryptedItemRepository) FindAll(ctx context.Context, access models.ItemAccess, page, limit int, conditions ...filter.Condition) ([]models.Item, int, error) {
+	for _, cond := range conditions {
+		if encryptedFields[cond.Field] {
+			return nil, 0, errors.NewBadRequestError("Campo cifrado não filtrável: "+cond.Field, nil)
+		}
+	}
+
+	items, total, err := r.next.FindAll(ctx, access, page, limit, conditions...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, total, r.enc.decryptAll(ctx, items)
+}
+
+func (r *encryptedItemRepository) FindByID(ctx context.Context, id string) (*models.Item, error) {
+	return r.decrypted(ctx)(r.next.FindByID(ctx, id))
+}
+
+func (r *encryptedItemRepository) Create(ctx context.Context, input *models.InputData) (*models.Item, error) {
+	sealed := *input
+	if err := r.enc.encrypt(ctx, sealed.OwnerID, &sealed.Description, &sealed.Email); err != nil {
+		return nil, err
+	}
+	return r.decrypted(ctx)(r.next.Create(ctx, &sealed))
+}
+
+func (r *encryptedItemRepository) Update(ctx context.Context, item *models.Item) (*models.Item, error) {
+	sealed := *item
+	if err := r.enc.encrypt(ctx, sealed.OwnerID, &sealed.Description, &sealed.Email); err != nil {
+		return nil, err
+	}
+	return r.decrypted(ctx)(r.next.Update(ctx, &sealed))
+}
+
+func (r *encryptedItemRepository) FindByName(ctx context.Context, ownerID, name string) (*models.Item, error) {
+	return r.decrypted(ctx)(r.next.FindByName(ctx, ownerID, name))
+}
+
+func (r *encryptedItemRepository) FindByValue(ctx context.Context, value string) (*models.Item, error) {
+	return r.decrypted(ctx)(r.next.FindByValue(ctx, value))
+}
+
+func (r *encryptedItemRepository) Iterate(ctx context.Context, filter ItemFilter, fn func(*models.Item) error) error {
+	return r.next.Iterate(ctx, filter, func(item *models.Item) error {
+		plain := *item
+		if err := r.enc.decrypt(ctx, &plain); err != nil {
+			return err
+		}
+		return fn(&plain)
+	})
+}
+
+// Search decifra os itens visíveis antes de procurar os termos, para que a
+// descrição e o email continuem sendo considerados
+func (r *encryptedItemRepository) Search(ctx context.Context, access models.ItemAccess, query string, page, limit int) ([]models.Item, int, error) {
+	if len(strings.Fields(query)) == 0 {
+		return []models.Item{}, 0, ctx.Err()
+	}
+
+	var visible []models.Item
+	err := r.Iterate(ctx, ItemFilter{Access: access}, func(item *models.Item) error {
+		visible = append(visible, *item)
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	items, total := rankItems(visible, query, page, limit)
+	return items, total, nil
+}
+
+// decrypted decifra o item retornado por uma operação, se houver
+func (r *encryptedItemRepository) decrypted(ctx context.Context) func(item *models.Item, err error) (*models.Item, error) {
+	return func(item *models.Item, err error) (*models.Item, error) {
+		if err != nil || item == nil {
+			return item, err
+		}
+		plain := *item
+		if err := r.enc.decrypt(ctx, &plain); err != nil {
+			return nil, err
+		}
+		return &plain, nil
+	}
+}
+
+// Users apaga a chave de dados do usuário junto com a conta
+func (e *Encryption) Users(next UserRepository) UserRepository {
+	return &keyDeletingUserRepository{UserRepository: next, enc: e}
+}
+
+type keyDeletingUserRepository struct {
+	UserRepository
+	enc *Encryption
+}
+
+func (r *keyDeletingUserRepository) Delete(id string) error {
+	if err := r.UserRepository.Delete(id); err != nil {
+		return err
+	}
+	if err := r.enc.keys.DeleteKey(context.Background(), id); err != nil {
+		logger.Error("Falha ao apagar a chave de dados do usuário", map[string]interface{}{
+			"userId": id,
+			"error":  err.Error(),
+		})
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gopkg.in/yaml.v3"
+
+	"callable-api/internal/models"
+)
+
+// defaultFixtures são os itens de exemplo do modo demo e dos testes
+//
+//go:embed fixtures/default.json
+var defaultFixtures []byte
+
+// Fixtures são os dados iniciais gravados nos repositórios em memória na
+// inicialização, lidos de um arquivo JSON ou YAML (ver LoadFixtures)
+type Fixtures struct {
+	Users []UserFixture `json:"users" yaml:"users"`
+	Items []ItemFixture `json:"items" yaml:"items"`
+}
+
+// UserFixture é um usuário dos dados iniciais. A senha pode ser informada em
+// texto (Password) ou já como hash bcrypt (PasswordHash), para que o arquivo
+// não precise conter senhas reais
+type UserFixture struct {
+	Email        string `json:"email" yaml:"email"`
+	Name         string `json:"name" yaml:"name"`
+	Password     string `json:"password,omitempty" yaml:"password,omitempty"`
+	PasswordHash string `json:"password_hash,omitempty" yaml:"password_hash,omitempty"`
+	Role         string `json:"role,omitempty" yaml:"role,omitempty"`
+}
+
+// ItemFixture é um item dos dados iniciais. Owner é o email do dono (um
+// usuário dos dados iniciais ou já existente); sem dono o item é público
+type ItemFixture struct {
+	Name        string `json:"name" yaml:"name"`
+	Value       string `json:"value" yaml:"value"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Email       string `json:"email,omitempty" yaml:"email,omitempty"`
+	Owner       string `json:"owner,omitempty" yaml:"owner,omitempty"`
+	CreatedAt   string `json:"created_at,omitempty" yaml:"created_at,omitempty"`
+}
+
+// FixtureSummary resume os dados gravados por Fixtures.Apply
+type FixtureSummary struct {
+	Users int
+	Items int
+}
+
+// DefaultFixtures retorna os dados de exemplo embutidos. Eles não incluem
+// usuários: o primeiro administrador vem da configuração inicial (ver
+// service.AuthService.Bootstrap)
+func DefaultFixtures() *Fixtures {
+	return mustParseFixtures(defaultFixtures)
+}
+
+// mustParseFixtures interpreta os dados embutidos, validados pelos testes
+func mustParseFixtures(data []byte) *Fixtures {
+	fixtures, err := ParseFixtures(data, "json")
+	if err != nil {
+		panic("dados de exemplo embutidos inválidos: " + err.Error())
+	}
+	return fixtures
+}
+
+// LoadFixtures lê os dados iniciais do arquivo, no formato indicado pela
+// extensão (.json, .yaml ou .yml)
+func LoadFixtures(path string) (*Fixtures, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("ler os dados iniciais: %w", err)
+	}
+
+	fixtures, err := ParseFixtures(data, strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), "."))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return fixtures, nil
+}
+
+// ParseFixtures interpreta e valida os dados iniciais no formato informado
+// (json, yaml ou yml). Campos desconhecidos são recusados, para que erros de
+// digitação não passem despercebidos
+func ParseFixtures(data []byte, format string) (*Fixtures, error) {
+	fixtures := &Fixtures{}
+	switch format {
+	case "json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(fixtures); err != nil {
+			return nil, fmt.Errorf("JSON inválido: %w", err)
+		}
+	case "yaml", "yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(fixtures); err != nil {
+			return nil, fmt.Errorf("YAML inválido: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("formato dos dados iniciais não suportado %q: use json, yaml ou yml", format)
+	}
+
+	if err := fixtures.Validate(); err != nil {
+		return nil, err
+	}
+	return fixtures, nil
+}
+
+// Validate verifica os campos obrigatórios, os papéis, as datas e os emails
+// repetidos
+func (f *Fixtures) Validate() error {
+	emails := make(map[string]bool, len(f.Users))
+	for i, user := range f.Users {
+		switch {
+		case user.Email == "":
+			return fmt.Errorf("usuário %d: email obrigatório", i+1)
+		case emails[user.Email]:
+			return fmt.Errorf("usuário %d: email repetido %s", i+1, user.Email)
+		case (user.Password == "") == (user.PasswordHash == ""):
+			return fmt.Errorf("usuário %s: informe password ou password_hash", user.Email)
+		case user.Role != "" && user.Role != "admin" && user.Role != "user":
+			return fmt.Errorf("usuário %s: papel inválido %q: use admin ou user", user.Email, user.Role)
+		}
+		if user.PasswordHash != "" {
+			if _, err := bcrypt.Cost([]byte(user.PasswordHash)); err != nil {
+				return fmt.Errorf("usuário %s: password_hash não é um hash bcrypt", user.Email)
+			}
+		}
+		emails[user.Email] = true
+	}
+
+	for i, item := range f.Items {
+		if item.Name == "" || item.Value == "" {
+			return fmt.Errorf("item %d: name e value são obrigatórios", i+1)
+		}
+		if item.CreatedAt != "" {
+			if _, err := time.Parse(time.RFC3339, item.CreatedAt); err != nil {
+				return fmt.Errorf("item %s: created_at deve estar no formato RFC 3339", item.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// Apply grava os usuários e depois os itens nos repositórios. Os donos dos
+// itens são procurados pelo email entre todos os usuários do repositório
+func (f *Fixtures) Apply(ctx context.Context, users *InMemoryUserRepository, items *InMemoryItemRepository) (*FixtureSummary, error) {
+	summary := &FixtureSummary{}
+	for _, fixture := range f.Users {
+		if err := ctx.Err(); err != nil {
+			return summary, err
+		}
+		if err := users.seed(fixture); err != nil {
+			return summary, fmt.Errorf("criar o usuário %s: %w", fixture.Email, err)
+		}
+		summary.Users++
+	}
+
+	for _, fixture := range f.Items {
+		if err := ctx.Err(); err != nil {
+			return summary, err
+		}
+		ownerID := ""
+		if fixture.Owner != "" {
+			owner, err := users.findByEmail(fixture.Owner)
+			if err != nil {
+				return summary, err
+			}
+			if owner == nil {
+				return summary, fmt.Errorf("item %s: dono %s não encontrado", fixture.Name, fixture.Owner)
+			}
+			ownerID = owner.ID
+		}
+		if err := items.seed(ctx, fixture, ownerID); err != nil {
+			return summary, fmt.Errorf("criar o item %s: %w", fixture.Name, err)
+		}
+		summary.Items++
+	}
+	return summary, nil
+}
+
+// seed grava um usuário dos dados iniciais, calculando o hash da senha em texto
+func (r *InMemoryUserRepository) seed(fixture UserFixture) error {
+	hash := fixture.PasswordHash
+	if fixture.Password != "" {
+		generated, err := bcrypt.GenerateFromPassword([]byte(fixture.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return err
+		}
+		hash = string(generated)
+	}
+	role := fixture.Role
+	if role == "" {
+		role = "user"
+	}
+
+	_, err := r.Create(&models.User{
+		Email:    fixture.Email,
+		Name:     fixture.Name,
+		Password: hash,
+		Role:     role,
+	})
+	return err
+}
+
+// seed grava um item dos dados iniciais, mantendo a data de criação informada
+func (r *InMemoryItemRepository) seed(ctx context.Context, fixture ItemFixture, ownerID string) error {
+	createdAt := fixture.CreatedAt
+	if createdAt == "" {
+		createdAt = time.Now().UTC().Format(time.RFC3339)
+	}
+
+	_, err := r.store.Insert(ctx, &models.Item{
+		Name:        fixture.Name,
+		Value:       fixture.Value,
+		Description: fixture.Description,
+		Email:       fixture.Email,
+		OwnerID:     ownerID,
+		CreatedAt:   createdAt,
+	})
+	return err
+}
@@ -0,0 +1,512 @@
+package repository
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	"callable-api/internal/correlation"
+	"callable-api/internal/filter"
+	"callable-api/internal/models"
+	"callable-api/pkg/errors"
+	"callable-api/pkg/logger"
+)
+
+// Observer recebe a duração e o resultado de cada operação de repositório
+// (ver metrics.Registry)
+type Observer interface {
+	Observe(component, operation string, latency time.Duration, failed bool)
+}
+
+// Instrumentation envolve os repositórios com métricas de latência e de erros
+// e com logs de depuração que incluem os IDs da requisição ou do job. Os
+// decoradores não alteram o comportamento dos repositórios envolvidos
+type Instrumentation struct {
+	observer Observer
+}
+
+// NewInstrumentation cria uma Instrumentation que registra as operações no observer
+func NewInstrumentation(observer Observer) *Instrumentation {
+	return &Instrumentation{observer: observer}
+}
+
+// begin marca o início de uma operação. A função retornada registra o
+// resultado e devolve o próprio erro, para uso direto no return
+func (i *Instrumentation) begin(ctx context.Context, component, operation string) func(err error) error {
+	start := time.Now()
+	return func(err error) error {
+		latency := time.Since(start)
+		i.observer.Observe(component, operation, latency, isFailure(err))
+
+		fields := map[string]interface{}{
+			"component":  component,
+			"operation":  operation,
+			"latency_ms": float64(latency.Microseconds()) / 1000,
+		}
+		if err != nil {
+			fields["error"] = err.Error()
+		}
+		logger.Debug("Operação de repositório", correlation.Fields(ctx, fields))
+		return err
+	}
+}
+
+// isFailure indica se o erro é uma falha do repositório. Registros
+// inexistentes, conflitos e requisições canceladas pelo cliente são
+// resultados esperados e não contam na taxa de erros
+func isFailure(err error) bool {
+	if err == nil || stderrors.Is(err, context.Canceled) {
+		return false
+	}
+	var appErr *errors.AppError
+	if stderrors.As(err, &appErr) {
+		switch appErr.Type {
+		case "NOT_FOUND", "CONFLICT", "BAD_REQUEST", "UNAUTHORIZED", "FORBIDDEN":
+			return false
+		}
+	}
+	return true
+}
+
+// Items instrumenta um ItemRepository
+func (i *Instrumentation) Items(next ItemRepository) ItemRepository {
+	return &instrumentedItemRepository{next: next, inst: i}
+}
+
+type instrumentedItemRepository struct {
+	next ItemRepository
+	inst *Instrumentation
+}
+
+func (r *instrumentedItemRepository) FindAll(ctx context.Context, access models.ItemAccess, page, limit int, conditions ...filter.Condition) ([]models.Item, int, error) {
+	done := r.inst.begin(ctx, "items", "FindAll")
+	items, total, err := r.next.FindAll(ctx, access, page, limit, conditions...)
+	return items, total, done(err)
+}
+
+func (r *instrumentedItemRepository) FindByID(ctx context.Context, id string) (*models.Item, error) {
+	done := r.inst.begin(ctx, "items", "FindByID")
+	item, err := r.next.FindByID(ctx, id)
+	return item, done(err)
+}
+
+func (r *instrumentedItemRepository) Create(ctx context.Context, input *models.InputData) (*models.Item, error) {
+	done := r.inst.begin(ctx, "items", "Create")
+	item, err := r.next.Create(ctx, input)
+	return item, done(err)
+}
+
+func (r *instrumentedItemRepository) Update(ctx context.Context, item *models.Item) (*models.Item, error) {
+	done := r.inst.begin(ctx, "items", "Update")
+	updated, err := r.next.Update(ctx, item)
+	return updated, done(err)
+}
+
+func (r *instrumentedItemRepository) FindByName(ctx context.Context, ownerID, name string) (*models.Item, error) {
+	done := r.inst.begin(ctx, "items", "FindByName")
+	item, err := r.next.FindByName(ctx, ownerID, name)
+	return item, done(err)
+}
+
+func (r *instrumentedItemRepository) FindByValue(ctx context.Context, value string) (*models.Item, error) {
+	done := r.inst.begin(ctx, "items", "FindByValue")
+	item, err := r.next.FindByValue(ctx, value)
+	return item, done(err)
+}
+
+func (r *instrumentedItemRepository) Iterate(ctx context.Context, filter ItemFilter, fn func(*models.Item) error) error {
+	done := r.inst.begin(ctx, "items", "Iterate")
+	return done(r.next.Iterate(ctx, filter, fn))
+}
+
+func (r *instrumentedItemRepository) Search(ctx context.Context, access models.ItemAccess, query string, page, limit int) ([]models.Item, int, error) {
+	done := r.inst.begin(ctx, "items", "Search")
+	items, total, err := r.next.Search(ctx, access, query, page, limit)
+	return items, total, done(err)
+}
+
+// Users instrumenta um UserRepository. Suas operações não recebem contexto,
+// por isso os logs não trazem os IDs da requisição
+func (i *Instrumentation) Users(next UserRepository) UserRepository {
+	return &instrumentedUserRepository{next: next, inst: i}
+}
+
+type instrumentedUserRepository struct {
+	next UserRepository
+	inst *Instrumentation
+}
+
+func (r *instrumentedUserRepository) FindByID(id string) (*models.User, error) {
+	done := r.inst.begin(context.Background(), "users", "FindByID")
+	user, err := r.next.FindByID(id)
+	return user, done(err)
+}
+
+func (r *instrumentedUserRepository) FindByEmail(email string) (*models.User, error) {
+	done := r.inst.begin(context.Background(), "users", "FindByEmail")
+	user, err := r.next.FindByEmail(email)
+	return user, done(err)
+}
+
+func (r *instrumentedUserRepository) Create(user *models.User) (*models.User, error) {
+	done := r.inst.begin(context.Background(), "users", "Create")
+	created, err := r.next.Create(user)
+	return created, done(err)
+}
+
+func (r *instrumentedUserRepository) Update(user *models.User) (*models.User, error) {
+	done := r.inst.begin(context.Background(), "users", "Update")
+	updated, err := r.next.Update(user)
+	return updated, done(err)
+}
+
+func (r *instrumentedUserRepository) List(page, limit int) ([]models.User, int, error) {
+	done := r.inst.begin(context.Background(), "users", "List")
+	users, total, err := r.next.List(page, limit)
+	return users, total, done(err)
+}
+
+func (r *instrumentedUserRepository) Delete(id string) error {
+	done := r.inst.begin(context.Background(), "users", "Delete")
+	return done(r.next.Delete(id))
+}
+
+func (r *instrumentedUserRepository) Authenticate(email, password string) (*models.User, error) {
+	done := r.inst.begin(context.Background(), "users", "Authenticate")
+	user, err := r.next.Authenticate(email, password)
+	return user, done(err)
+}
+
+// Comments instrumenta um CommentRepository
+func (i *Instrumentation) Comments(next CommentRepository) CommentRepository {
+	return &instrumentedCommentRepository{next: next, inst: i}
+}
+
+type instrumentedCommentRepository struct {
+	next CommentRepository
+	inst *Instrumentation
+}
+
+func (r *instrumentedCommentRepository) Create(ctx context.Context, comment *models.Comment) (*models.Comment, error) {
+	done := r.inst.begin(ctx, "comments", "Create")
+	created, err := r.next.Create(ctx, comment)
+	return created, done(err)
+}
+
+func (r *instrumentedCommentRepository) FindByID(ctx context.Context, id string) (*models.Comment, error) {
+	done := r.inst.begin(ctx, "comments", "FindByID")
+	comment, err := r.next.FindByID(ctx, id)
+	return comment, done(err)
+}
+
+func (r *instrumentedCommentRepository) ListThreads(ctx context.Context, itemID string, page, limit int) ([]models.Comment, int, error) {
+	done := r.inst.begin(ctx, "comments", "ListThreads")
+	comments, total, err := r.next.ListThreads(ctx, itemID, page, limit)
+	return comments, total, done(err)
+}
+
+func (r *instrumentedCommentRepository) ListReplies(ctx context.Context, threadIDs []string) ([]models.Comment, error) {
+	done := r.inst.begin(ctx, "comments", "ListReplies")
+	comments, err := r.next.ListReplies(ctx, threadIDs)
+	return comments, done(err)
+}
+
+func (r *instrumentedCommentRepository) Update(ctx context.Context, comment *models.Comment) (*models.Comment, error) {
+	done := r.inst.begin(ctx, "comments", "Update")
+	updated, err := r.next.Update(ctx, comment)
+	return updated, done(err)
+}
+
+func (r *instrumentedCommentRepository) Delete(ctx context.Context, id string) error {
+	done := r.inst.begin(ctx, "comments", "Delete")
+	return done(r.next.Delete(ctx, id))
+}
+
+// ItemShares instrumenta um ItemShareRepository
+func (i *Instrumentation) ItemShares(next ItemShareRepository) ItemShareRepository {
+	return &instrumentedItemShareRepository{next: next, inst: i}
+}
+
+type instrumentedItemShareRepository struct {
+	next ItemShareRepository
+	inst *Instrumentation
+}
+
+func (r *instrumentedItemShareRepository) Save(ctx context.Context, share *models.ItemShare) (*models.ItemShare, error) {
+	done := r.inst.begin(ctx, "item_shares", "Save")
+	saved, err := r.next.Save(ctx, share)
+	return saved, done(err)
+}
+
+func (r *instrumentedItemShareRepository) ListByItem(ctx context.Context, itemID string) ([]models.ItemShare, error) {
+	done := r.inst.begin(ctx, "item_shares", "ListByItem")
+	shares, err := r.next.ListByItem(ctx, itemID)
+	return shares, done(err)
+}
+
+func (r *instrumentedItemShareRepository) ListForViewer(ctx context.Context, viewer models.Viewer) ([]models.ItemShare, error) {
+	done := r.inst.begin(ctx, "item_shares", "ListForViewer")
+	shares, err := r.next.ListForViewer(ctx, viewer)
+	return shares, done(err)
+}
+
+func (r *instrumentedItemShareRepository) Delete(ctx context.Context, itemID, shareID string) error {
+	done := r.inst.begin(ctx, "item_shares", "Delete")
+	return done(r.next.Delete(ctx, itemID, shareID))
+}
+
+// LoginHistory instrumenta um LoginHistoryRepository
+func (i *Instrumentation) LoginHistory(next LoginHistoryRepository) LoginHistoryRepository {
+	return &instrumentedLoginHistoryRepository{next: next, inst: i}
+}
+
+type instrumentedLoginHistoryRepository struct {
+	next LoginHistoryRepository
+	inst *Instrumentation
+}
+
+func (r *instrumentedLoginHistoryRepository) Record(ctx context.Context, attempt *models.LoginAttempt) error {
+	done := r.inst.begin(ctx, "login_history", "Record")
+	return done(r.next.Record(ctx, attempt))
+}
+
+func (r *instrumentedLoginHistoryRepository) ListByUser(ctx context.Context, userID string, page, limit int) ([]models.LoginAttempt, int, error) {
+	done := r.inst.begin(ctx, "login_history", "ListByUser")
+	attempts, total, err := r.next.ListByUser(ctx, userID, page, limit)
+	return attempts, total, done(err)
+}
+
+// DeviceBindings instrumenta um DeviceBindingRepository
+func (i *Instrumentation) DeviceBindings(next DeviceBindingRepository) DeviceBindingRepository {
+	return &instrumentedDeviceBindingRepository{next: next, inst: i}
+}
+
+type instrumentedDeviceBindingRepository struct {
+	next DeviceBindingRepository
+	inst *Instrumentation
+}
+
+func (r *instrumentedDeviceBindingRepository) Save(ctx context.Context, tokenHash string, binding *models.DeviceBinding) error {
+	done := r.inst.begin(ctx, "device_bindings", "Save")
+	return done(r.next.Save(ctx, tokenHash, binding))
+}
+
+func (r *instrumentedDeviceBindingRepository) Find(ctx context.Context, tokenHash string) (*models.DeviceBinding, error) {
+	done := r.inst.begin(ctx, "device_bindings", "Find")
+	binding, err := r.next.Find(ctx, tokenHash)
+	return binding, done(err)
+}
+
+func (r *instrumentedDeviceBindingRepository) Delete(ctx context.Context, tokenHash string) error {
+	done := r.inst.begin(ctx, "device_bindings", "Delete")
+	return done(r.next.Delete(ctx, tokenHash))
+}
+
+// Sessions instrumenta um SessionRepository
+func (i *Instrumentation) Sessions(next SessionRepository) SessionRepository {
+	return &instrumentedSessionRepository{next: next, inst: i}
+}
+
+type instrumentedSessionRepository struct {
+	next SessionRepository
+	inst *Instrumentation
+}
+
+func (r *instrumentedSessionRepository) Save(ctx context.Context, tokenHash string, session *models.Session) error {
+	done := r.inst.begin(ctx, "sessions", "Save")
+	return done(r.next.Save(ctx, tokenHash, session))
+}
+
+func (r *instrumentedSessionRepository) Find(ctx context.Context, tokenHash string) (*models.Session, error) {
+	done := r.inst.begin(ctx, "sessions", "Find")
+	session, err := r.next.Find(ctx, tokenHash)
+	return session, done(err)
+}
+
+func (r *instrumentedSessionRepository) Delete(ctx context.Context, tokenHash string) error {
+	done := r.inst.begin(ctx, "sessions", "Delete")
+	return done(r.next.Delete(ctx, tokenHash))
+}
+
+// ScopedTokens instrumenta um ScopedTokenRepository
+func (i *Instrumentation) ScopedTokens(next ScopedTokenRepository) ScopedTokenRepository {
+	return &instrumentedScopedTokenRepository{next: next, inst: i}
+}
+
+type instrumentedScopedTokenRepository struct {
+	next ScopedTokenRepository
+	inst *Instrumentation
+}
+
+func (r *instrumentedScopedTokenRepository) Save(ctx context.Context, tokenHash string, grant *models.TokenGrant) error {
+	done := r.inst.begin(ctx, "scoped_tokens", "Save")
+	return done(r.next.Save(ctx, tokenHash, grant))
+}
+
+func (r *instrumentedScopedTokenRepository) Find(ctx context.Context, tokenHash string) (*models.TokenGrant, error) {
+	done := r.inst.begin(ctx, "scoped_tokens", "Find")
+	grant, err := r.next.Find(ctx, tokenHash)
+	return grant, done(err)
+}
+
+// NotificationPreferences instrumenta um NotificationPreferencesRepository
+func (i *Instrumentation) NotificationPreferences(next NotificationPreferencesRepository) NotificationPreferencesRepository {
+	return &instrumentedNotificationPreferencesRepository{next: next, inst: i}
+}
+
+type instrumentedNotificationPreferencesRepository struct {
+	next NotificationPreferencesRepository
+	inst *Instrumentation
+}
+
+func (r *instrumentedNotificationPreferencesRepository) FindByUserID(ctx context.Context, userID string) (*models.NotificationPreferences, error) {
+	done := r.inst.begin(ctx, "notification_preferences", "FindByUserID")
+	prefs, err := r.next.FindByUserID(ctx, userID)
+	return prefs, done(err)
+}
+
+func (r *instrumentedNotificationPreferencesRepository) Save(ctx context.Context, prefs *models.NotificationPreferences) (*models.NotificationPreferences, error) {
+	done := r.inst.begin(ctx, "notification_preferences", "Save")
+	saved, err := r.next.Save(ctx, prefs)
+	return saved, done(err)
+}
+
+// Notifications instrumenta um NotificationRepository
+func (i *Instrumentation) Notifications(next NotificationRepository) NotificationRepository {
+	return &instrumentedNotificationRepository{next: next, inst: i}
+}
+
+type instrumentedNotificationRepository struct {
+	next NotificationRepository
+	inst *Instrumentation
+}
+
+func (r *instrumentedNotificationRepository) Add(ctx context.Context, notification *models.Notification) error {
+	done := r.inst.begin(ctx, "notifications", "Add")
+	return done(r.next.Add(ctx, notification))
+}
+
+func (r *instrumentedNotificationRepository) ListByUser(ctx context.Context, userID string, unreadOnly bool, page, limit int) ([]models.Notification, int, error) {
+	done := r.inst.begin(ctx, "notifications", "ListByUser")
+	list, total, err := r.next.ListByUser(ctx, userID, unreadOnly, page, limit)
+	return list, total, done(err)
+}
+
+func (r *instrumentedNotificationRepository) MarkRead(ctx context.Context, userID, id string) (*models.Notification, error) {
+	done := r.inst.begin(ctx, "notifications", "MarkRead")
+	notification, err := r.next.MarkRead(ctx, userID, id)
+	return notification, done(err)
+}
+
+func (r *instrumentedNotificationRepository) MarkAllRead(ctx context.Context, userID string) (int, error) {
+	done := r.inst.begin(ctx, "notifications", "MarkAllRead")
+	updated, err := r.next.MarkAllRead(ctx, userID)
+	return updated, done(err)
+}
+
+// Organizations instrumenta um OrganizationRepository
+func (i *Instrumentation) Organizations(next OrganizationRepository) OrganizationRepository {
+	return &instrumentedOrganizationRepository{next: next, inst: i}
+}
+
+type instrumentedOrganizationRepository struct {
+	next OrganizationRepository
+	inst *Instrumentation
+}
+
+func (r *instrumentedOrganizationRepository) Create(ctx context.Context, org *models.Organization) (*models.Organization, error) {
+	done := r.inst.begin(ctx, "organizations", "Create")
+	created, err := r.next.Create(ctx, org)
+	return created, done(err)
+}
+
+func (r *instrumentedOrganizationRepository) FindByID(ctx context.Context, id string) (*models.Organization, error) {
+	done := r.inst.begin(ctx, "organizations", "FindByID")
+	org, err := r.next.FindByID(ctx, id)
+	return org, done(err)
+}
+
+func (r *instrumentedOrganizationRepository) SaveMember(ctx context.Context, member *models.Membership) (*models.Membership, error) {
+	done := r.inst.begin(ctx, "organizations", "SaveMember")
+	saved, err := r.next.SaveMember(ctx, member)
+	return saved, done(err)
+}
+
+func (r *instrumentedOrganizationRepository) FindMember(ctx context.Context, orgID, userID string) (*models.Membership, error) {
+	done := r.inst.begin(ctx, "organizations", "FindMember")
+	member, err := r.next.FindMember(ctx, orgID, userID)
+	return member, done(err)
+}
+
+func (r *instrumentedOrganizationRepository) ListMembers(ctx context.Context, orgID string) ([]models.Membership, error) {
+	done := r.inst.begin(ctx, "organizations", "ListMembers")
+	members, err := r.next.ListMembers(ctx, orgID)
+	return members, done(err)
+}
+
+func (r *instrumentedOrganizationRepository) ListForUser(ctx context.Context, userID string) ([]models.Membership, error) {
+	done := r.inst.begin(ctx, "organizations", "ListForUser")
+	members, err := r.next.ListForUser(ctx, userID)
+	return members, done(err)
+}
+
+func (r *instrumentedOrganizationRepository) DeleteMember(ctx context.Context, orgID, userID string) error {
+	done := r.inst.begin(ctx, "organizations", "DeleteMember")
+	return done(r.next.DeleteMember(ctx, orgID, userID))
+}
+
+// Invitations instrumenta um InvitationRepository
+func (i *Instrumentation) Invitations(next InvitationRepository) InvitationRepository {
+	return &instrumentedInvitationRepository{next: next, inst: i}
+}
+
+type instrumentedInvitationRepository struct {
+	next InvitationRepository
+	inst *Instrumentation
+}
+
+func (r *instrumentedInvitationRepository) Create(ctx context.Context, invitation *models.Invitation) (*models.Invitation, error) {
+	done := r.inst.begin(ctx, "invitations", "Create")
+	created, err := r.next.Create(ctx, invitation)
+	return created, done(err)
+}
+
+func (r *instrumentedInvitationRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*models.Invitation, error) {
+	done := r.inst.begin(ctx, "invitations", "FindByTokenHash")
+	invitation, err := r.next.FindByTokenHash(ctx, tokenHash)
+	return invitation, done(err)
+}
+
+func (r *instrumentedInvitationRepository) ListByOrg(ctx context.Context, orgID string) ([]models.Invitation, error) {
+	done := r.inst.begin(ctx, "invitations", "ListByOrg")
+	invitations, err := r.next.ListByOrg(ctx, orgID)
+	return invitations, done(err)
+}
+
+func (r *instrumentedInvitationRepository) MarkAccepted(ctx context.Context, id string, at time.Time) (*models.Invitation, error) {
+	done := r.inst.begin(ctx, "invitations", "MarkAccepted")
+	invitation, err := r.next.MarkAccepted(ctx, id, at)
+	return invitation, done(err)
+}
+
+// Consents instrumenta um ConsentRepository
+func (i *Instrumentation) Consents(next ConsentRepository) ConsentRepository {
+	return &instrumentedConsentRepository{next: next, inst: i}
+}
+
+type instrumentedConsentRepository struct {
+	next ConsentRepository
+	inst *Instrumentation
+}
+
+func (r *instrumentedConsentRepository) Record(ctx context.Context, consent *models.Consent) (*models.Consent, error) {
+	done := r.inst.begin(ctx, "consents", "Record")
+	recorded, err := r.next.Record(ctx, consent)
+	return recorded, done(err)
+}
+
+func (r *instrumentedConsentRepository) ListByUser(ctx context.Context, userID string) ([]models.Consent, error) {
+	done := r.inst.begin(ctx, "consents", "ListByUser")
+	consents, err := r.next.ListByUser(ctx, userID)
+	return consents, done(err)
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"callable-api/internal/ids"
+	"callable-api/internal/models"
+	"callable-api/pkg/errors"
+	"context"
+	stderrors "errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrInvitationNotFound é a causa do erro NotFound de convites inexistentes
+var ErrInvitationNotFound = stderrors.New("convite não encontrado")
+
+// InvitationRepository define as operações de persistência dos convites
+type InvitationRepository interface {
+	// Create grava um novo convite
+	Create(ctx context.Context, invitation *models.Invitation) (*models.Invitation, error)
+
+	// FindByTokenHash busca o convite pelo hash do token (NotFound se inexistente)
+	FindByTokenHash(ctx context.Context, tokenHash string) (*models.Invitation, error)
+
+	// ListByOrg retorna os convites da organização, dos mais antigos para os mais recentes
+	ListByOrg(ctx context.Context, orgID string) ([]models.Invitation, error)
+
+	// MarkAccepted registra o aceite do convite. Falha com Conflict se o
+	// convite já foi aceito, para que um token não seja usado duas vezes
+	MarkAccepted(ctx context.Context, id string, at time.Time) (*models.Invitation, error)
+}
+
+// InMemoryInvitationRepository implementa InvitationRepository em memória
+type InMemoryInvitationRepository struct {
+	invitations map[string]models.Invitation
+	ids         ids.Generator
+	mutex       sync.RWMutex
+}
+
+// NewInMemoryInvitationRepository cria um novo repositório em memória
+func NewInMemoryInvitationRepository() *InMemoryInvitationRepository {
+	return &InMemoryInvitationRepository{
+		invitations: make(map[string]models.Invitation),
+		ids:         ids.UUID(),
+	}
+}
+
+// WithIDGenerator define o formato dos IDs dos convites (padrão: UUID)
+func (r *InMemoryInvitationRepository) WithIDGenerator(gen ids.Generator) *InMemoryInvitationRepository {
+	r.ids = gen
+	return r
+}
+
+// Create implementa InvitationRepository.Create. O token em claro não é gravado
+func (r *InMemoryInvitationRepository) Create(ctx context.Context, invitation *models.Invitation) (*models.Invitation, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	saved := *invitation
+	saved.ID = r.ids.NewID()
+	saved.CreatedAt = time.Now().UTC()
+	saved.Token = ""
+	r.invitations[saved.ID] = saved
+
+	return &saved, nil
+}
+
+// FindByTokenHash implementa InvitationRepository.FindByTokenHash
+func (r *InMemoryInvitationRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*models.Invitation, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, invitation := range r.invitations {
+		if invitation.TokenHash == tokenHash {
+			return &invitation, nil
+		}
+	}
+	return nil, errors.NewNotFoundError("Convite não encontrado", ErrInvitationNotFound)
+}
+
+// ListByOrg implementa InvitationRepository.ListByOrg
+func (r *InMemoryInvitationRepository) ListByOrg(ctx context.Context, orgID string) ([]models.Invitation, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	result := make([]models.Invitation, 0)
+	for _, invitation := range r.invitations {
+		if invitation.OrgID == orgID {
+			result = append(result, invitation)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+
+	return result, nil
+}
+
+// MarkAccepted implementa InvitationRepository.MarkAccepted
+func (r *InMemoryInvitationRepository) MarkAccepted(ctx context.Context, id string, at time.Time) (*models.Invitation, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	invitation, exists := r.invitations[id]
+	if !exists {
+		return nil, errors.NewNotFoundError("Convite não encontrado", ErrInvitationNotFound)
+	}
+	if invitation.AcceptedAt != nil {
+		return nil, errors.NewConflictError("Convite já aceito", nil)
+	}
+	accepted := at.UTC()
+	invitation.AcceptedAt = &accepted
+	r.invitations[id] = invitation
+
+	return &invitation, nil
+}
@@ -2,38 +2,67 @@
 package repository
 
 import (
+	"callable-api/internal/filter"
+	"callable-api/internal/ids"
 	"callable-api/internal/models"
+	"callable-api/internal/pagination"
 	"callable-api/pkg/errors"
-	"sync"
-	"fmt"
+	"context"
+	"sort"
+	"strings"
 )
 
 // ItemRepository define a interface para acessar dados de items
 type ItemRepository interface {
-	// FindAll retorna todos os itens com paginação
-	FindAll(page, limit int) ([]models.Item, int, error)
+	// FindAll retorna os itens visíveis que satisfazem as condições, com paginação
+	FindAll(ctx context.Context, access models.ItemAccess, page, limit int, conditions ...filter.Condition) ([]models.Item, int, error)
 	
 	// FindByID retorna um item pelo seu ID
-	FindByID(id string) (*models.Item, error)
+	FindByID(ctx context.Context, id string) (*models.Item, error)
 	
 	// Create cria um novo item
-	Create(input *models.InputData) (*models.Item, error)
+	Create(ctx context.Context, input *models.InputData) (*models.Item, error)
+	
+	// Update substitui os dados de um item existente
+	Update(ctx context.Context, item *models.Item) (*models.Item, error)
+	
+	// FindByName retorna o item do dono com o nome informado (sem diferenciar
+	// maiúsculas de minúsculas), ou nil se não houver
+	FindByName(ctx context.Context, ownerID, name string) (*models.Item, error)
+	
+	// FindByValue retorna um item com o valor informado, ou nil se não houver
+	FindByValue(ctx context.Context, value string) (*models.Item, error)
+	
+	// Iterate chama fn para cada item selecionado pelo filtro, na ordem de
+	// criação, sem carregar todos os itens na memória (para exportações,
+	// indexação e jobs de retenção). Um erro de fn interrompe a iteração e é
+	// retornado; ErrStopIteration interrompe sem erro
+	Iterate(ctx context.Context, filter ItemFilter, fn func(*models.Item) error) error
+	
+	// Search retorna os itens visíveis que contêm os termos da consulta, ordenados por relevância
+	Search(ctx context.Context, access models.ItemAccess, query string, page, limit int) ([]models.Item, int, error)
+}
+
+// ItemFilter seleciona os itens percorridos por Iterate
+type ItemFilter struct {
+	Access  models.ItemAccess // itens visíveis (ItemAccess{All: true} para todos)
+	OwnerID string            // apenas os itens deste dono, se informado
+}
+
+// Matches indica se o item é selecionado pelo filtro
+func (f ItemFilter) Matches(item *models.Item) bool {
+	return f.Access.CanRead(item) && (f.OwnerID == "" || item.OwnerID == f.OwnerID)
 }
 
 // InMemoryItemRepository implementa ItemRepository com armazenamento em memória
 // para simplificar demonstrações e testes
 type InMemoryItemRepository struct {
-	items      map[string]models.Item
-	mutex      sync.RWMutex
-	nextID     int
+	store *MemoryRepository[models.Item]
 }
 
 // NewInMemoryItemRepository cria uma nova instância de InMemoryItemRepository
 func NewInMemoryItemRepository() *InMemoryItemRepository {
-	repo := &InMemoryItemRepository{
-		items:  make(map[string]models.Item),
-		nextID: 1,
-	}
+	repo := NewEmptyInMemoryItemRepository()
 	
 	// Pré-popular com alguns dados de exemplo
 	repo.seedData()
@@ -41,99 +70,172 @@ func NewInMemoryItemRepository() *InMemoryItemRepository {
 	return repo
 }
 
-// seedData popula o repositório com dados iniciais de exemplo
+// NewEmptyInMemoryItemRepository cria um InMemoryItemRepository sem os dados de exemplo
+func NewEmptyInMemoryItemRepository() *InMemoryItemRepository {
+	repo := &InMemoryItemRepository{}
+	repo.store = NewMemoryRepository(
+		func(item *models.Item) *string { return &item.ID },
+		func() error { return errors.NewNotFoundError("Item não encontrado", nil) },
+	).
+		WithIDGenerator(ids.Sequence().NewID).
+		WithUpdateHook(func(existing, item *models.Item) {
+			// O dono, a organização e a data de criação não mudam nas atualizações
+			item.OwnerID = existing.OwnerID
+			item.OrgID = existing.OrgID
+			item.CreatedAt = existing.CreatedAt
+		})
+	return repo
+}
+
+// seedData popula o repositório com os itens de exemplo (ver DefaultFixtures)
 func (r *InMemoryItemRepository) seedData() {
-	// Adicionar alguns itens de exemplo
-	for i := 1; i <= 10; i++ {
-		id := r.generateID()
-		r.items[id] = models.Item{
-			ID:          id,
-			Name:        "Item " + id,
-			Value:       "Value-" + id,
-			Description: "Description for item " + id,
-			Email:       "user" + id + "@example.com",
-			CreatedAt:   "2023-06-01T09:30:00Z",
-		}
+	for _, fixture := range DefaultFixtures().Items {
+		r.seed(context.Background(), fixture, "")
 	}
 }
 
-// generateID gera um novo ID único para itens
-func (r *InMemoryItemRepository) generateID() string {
-	r.mutex.Lock()
-	defer r.mutex.Unlock()
-	id := r.nextID
-	r.nextID++
-	return fmt.Sprint(id)
+// WithIDGenerator define o formato dos IDs dos novos itens (padrão: sequência
+// numérica). Os dados de exemplo mantêm os IDs já gerados
+func (r *InMemoryItemRepository) WithIDGenerator(gen ids.Generator) *InMemoryItemRepository {
+	r.store.WithIDGenerator(gen.NewID)
+	return r
 }
 
-// FindAll implementa ItemRepository.FindAll
-func (r *InMemoryItemRepository) FindAll(page, limit int) ([]models.Item, int, error) {
-	r.mutex.RLock()
-	defer r.mutex.RUnlock()
-	
-	if page < 1 {
-		page = 1
+// FindAll implementa ItemRepository.FindAll, na ordem de criação dos itens
+func (r *InMemoryItemRepository) FindAll(ctx context.Context, access models.ItemAccess, page, limit int, conditions ...filter.Condition) ([]models.Item, int, error) {
+	if err := checkItemFilterFields(conditions); err != nil {
+		return nil, 0, err
 	}
-	if limit < 1 {
-		limit = 10
-	}
-	
-	// Calcular o índice inicial e final para paginação
-	startIdx := (page - 1) * limit
-	endIdx := startIdx + limit
-	
-	// Coletar todos os itens em um slice
-	allItems := make([]models.Item, 0, len(r.items))
-	for _, item := range r.items {
-		allItems = append(allItems, item)
-	}
-	
-	// Total de itens
-	totalItems := len(allItems)
 	
-	// Verificar se não há itens ou se está além dos limites
-	if totalItems == 0 || startIdx >= totalItems {
-		return []models.Item{}, totalItems, nil
-	}
-	
-	// Ajustar o índice final se necessário
-	if endIdx > totalItems {
-		endIdx = totalItems
+	return r.store.List(ctx, Query[models.Item]{Match: func(item *models.Item) bool {
+		return access.CanRead(item) && filter.Conditions(conditions).Match(func(field string) string {
+			return itemFilterFields[field](item)
+		})
+	}}, page, limit)
+}
+
+// itemFilterFields retorna o valor textual dos campos filtráveis dos itens
+var itemFilterFields = map[string]func(*models.Item) string{
+	"created_at": func(item *models.Item) string { return item.CreatedAt },
+	"value":      func(item *models.Item) string { return item.Value },
+	"name":       func(item *models.Item) string { return item.Name },
+}
+
+// checkItemFilterFields rejeita condições sobre campos que não são filtráveis
+func checkItemFilterFields(conditions []filter.Condition) error {
+	for _, cond := range conditions {
+		if _, ok := itemFilterFields[cond.Field]; !ok {
+			return errors.NewBadRequestError("Campo não filtrável: "+cond.Field, nil)
+		}
 	}
-	
-	// Retornar o subconjunto de itens para a página solicitada
-	return allItems[startIdx:endIdx], totalItems, nil
+	return nil
 }
 
 // FindByID implementa ItemRepository.FindByID
-func (r *InMemoryItemRepository) FindByID(id string) (*models.Item, error) {
-	r.mutex.RLock()
-	defer r.mutex.RUnlock()
+func (r *InMemoryItemRepository) FindByID(ctx context.Context, id string) (*models.Item, error) {
+	return r.store.Get(ctx, id)
+}
+
+// Create implementa ItemRepository.Create
+func (r *InMemoryItemRepository) Create(ctx context.Context, input *models.InputData) (*models.Item, error) {
+	return r.store.Insert(ctx, &models.Item{
+		Name:          input.Name,
+		Value:         input.Value,
+		Description:   input.Description,
+		Email:         input.Email,
+		OwnerID:       input.OwnerID,
+		OrgID:         input.OrgID,
+		CreatedAt:     "2023-07-01T10:00:00Z", // Normalmente você usaria time.Now()
+		SchemaVersion: ItemSchema.Current(),
+	})
+}
+
+// Update implementa ItemRepository.Update, preservando o dono e a data de criação
+func (r *InMemoryItemRepository) Update(ctx context.Context, item *models.Item) (*models.Item, error) {
+	return r.store.Update(ctx, item)
+}
+
+// FindByName implementa ItemRepository.FindByName
+func (r *InMemoryItemRepository) FindByName(ctx context.Context, ownerID, name string) (*models.Item, error) {
+	name = strings.TrimSpace(name)
+	return r.store.Find(ctx, func(item *models.Item) bool {
+		return item.OwnerID == ownerID && strings.EqualFold(strings.TrimSpace(item.Name), name)
+	})
+}
+
+// FindByValue implementa ItemRepository.FindByValue
+func (r *InMemoryItemRepository) FindByValue(ctx context.Context, value string) (*models.Item, error) {
+	return r.store.Find(ctx, func(item *models.Item) bool {
+		return item.Value == value
+	})
+}
+
+// Iterate implementa ItemRepository.Iterate
+func (r *InMemoryItemRepository) Iterate(ctx context.Context, filter ItemFilter, fn func(*models.Item) error) error {
+	return r.store.Each(ctx, filter.Matches, fn)
+}
+
+// Search implementa ItemRepository.Search com uma busca simples por termos
+// (ver rankItems)
+func (r *InMemoryItemRepository) Search(ctx context.Context, access models.ItemAccess, query string, page, limit int) ([]models.Item, int, error) {
+	if len(strings.Fields(query)) == 0 {
+		return []models.Item{}, 0, ctx.Err()
+	}
 	
-	item, exists := r.items[id]
-	if !exists {
-		return nil, errors.NewNotFoundError("Item não encontrado", nil)
+	visible, err := r.store.Filter(ctx, access.CanRead)
+	if err != nil {
+		return nil, 0, err
 	}
 	
-	return &item, nil
+	result, total := rankItems(visible, query, page, limit)
+	return result, total, nil
 }
 
-// Create implementa ItemRepository.Create
-func (r *InMemoryItemRepository) Create(input *models.InputData) (*models.Item, error) {
-	r.mutex.Lock()
-	defer r.mutex.Unlock()
-	
-	id := r.generateID()
-	newItem := models.Item{
-		ID:          id,
-		Name:        input.Name,
-		Value:       input.Value,
-		Description: input.Description,
-		Email:       input.Email,
-		CreatedAt:   "2023-07-01T10:00:00Z", // Normalmente você usaria time.Now()
+// rankItems retorna a página dos itens que contêm os termos da consulta,
+// ordenados por relevância: cada termo encontrado no nome vale mais do que
+// nos demais campos
+func rankItems(candidates []models.Item, query string, page, limit int) ([]models.Item, int) {
+	terms := strings.Fields(strings.ToLower(query))
+	
+	type scoredItem struct {
+		item  models.Item
+		score int
 	}
 	
-	r.items[id] = newItem
+	matches := make([]scoredItem, 0)
+	for _, item := range candidates {
+		score := 0
+		for _, term := range terms {
+			if strings.Contains(strings.ToLower(item.Name), term) {
+				score += 3
+			}
+			if strings.Contains(strings.ToLower(item.Description), term) {
+				score += 2
+			}
+			if strings.Contains(strings.ToLower(item.Value), term) || strings.Contains(strings.ToLower(item.Email), term) {
+				score++
+			}
+		}
+		if score > 0 {
+			matches = append(matches, scoredItem{item: item, score: score})
+		}
+	}
 	
-	return &newItem, nil
-}
\ No newline at end of file
+	// Ordenar por relevância e, em caso de empate, pelo ID para um resultado estável
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return matches[i].item.ID < matches[j].item.ID
+	})
+	
+	total := len(matches)
+	startIdx, endIdx := pagination.New(page, limit).Window(total)
+	
+	result := make([]models.Item, 0, endIdx-startIdx)
+	for _, match := range matches[startIdx:endIdx] {
+		result = append(result, match.item)
+	}
+	
+	return result, total
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"callable-api/internal/ids"
+	"callable-api/internal/models"
+	"callable-api/pkg/errors"
+	"context"
+	stderrors "errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrShareNotFound é a causa do erro NotFound de compartilhamentos inexistentes,
+// para diferenciá-los de itens inexistentes
+var ErrShareNotFound = stderrors.New("compartilhamento não encontrado")
+
+// ItemShareRepository define as operações de persistência dos compartilhamentos de itens
+type ItemShareRepository interface {
+	// Save cria o compartilhamento ou, se o item já foi compartilhado com o
+	// mesmo usuário ou papel, atualiza o nível de acesso
+	Save(ctx context.Context, share *models.ItemShare) (*models.ItemShare, error)
+
+	// ListByItem retorna os compartilhamentos do item, dos mais antigos para os mais recentes
+	ListByItem(ctx context.Context, itemID string) ([]models.ItemShare, error)
+
+	// ListForViewer retorna os compartilhamentos com o usuário ou com o seu papel
+	ListForViewer(ctx context.Context, viewer models.Viewer) ([]models.ItemShare, error)
+
+	// Delete remove um compartilhamento do item (NotFound se inexistente)
+	Delete(ctx context.Context, itemID, shareID string) error
+}
+
+// InMemoryItemShareRepository implementa ItemShareRepository em memória
+type InMemoryItemShareRepository struct {
+	shares map[string]models.ItemShare
+	ids    ids.Generator
+	mutex  sync.RWMutex
+}
+
+// NewInMemoryItemShareRepository cria um novo repositório em memória
+func NewInMemoryItemShareRepository() *InMemoryItemShareRepository {
+	return &InMemoryItemShareRepository{
+		shares: make(map[string]models.ItemShare),
+		ids:    ids.UUID(),
+	}
+}
+
+// WithIDGenerator define o formato dos IDs dos compartilhamentos (padrão: UUID)
+func (r *InMemoryItemShareRepository) WithIDGenerator(gen ids.Generator) *InMemoryItemShareRepository {
+	r.ids = gen
+	return r
+}
+
+// Save implementa ItemShareRepository.Save
+func (r *InMemoryItemShareRepository) Save(ctx context.Context, share *models.ItemShare) (*models.ItemShare, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	saved := *share
+	for _, existing := range r.shares {
+		if existing.ItemID == share.ItemID && existing.UserID == share.UserID && existing.Role == share.Role {
+			saved.ID = existing.ID
+			saved.CreatedAt = existing.CreatedAt
+			break
+		}
+	}
+	if saved.ID == "" {
+		saved.ID = r.ids.NewID()
+		saved.CreatedAt = time.Now().UTC()
+	}
+	r.shares[saved.ID] = saved
+
+	return &saved, nil
+}
+
+// ListByItem implementa ItemShareRepository.ListByItem
+func (r *InMemoryItemShareRepository) ListByItem(ctx context.Context, itemID string) ([]models.ItemShare, error) {
+	return r.list(ctx, func(share *models.ItemShare) bool {
+		return share.ItemID == itemID
+	})
+}
+
+// ListForViewer implementa ItemShareRepository.ListForViewer
+func (r *InMemoryItemShareRepository) ListForViewer(ctx context.Context, viewer models.Viewer) ([]models.ItemShare, error) {
+	return r.list(ctx, func(share *models.ItemShare) bool {
+		return (share.UserID != "" && share.UserID == viewer.UserID) || (share.Role != "" && share.Role == viewer.Role)
+	})
+}
+
+// list retorna os compartilhamentos selecionados, ordenados por data de criação
+func (r *InMemoryItemShareRepository) list(ctx context.Context, match func(*models.ItemShare) bool) ([]models.ItemShare, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	result := make([]models.ItemShare, 0)
+	for _, share := range r.shares {
+		if match(&share) {
+			result = append(result, share)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+
+	return result, nil
+}
+
+// Delete implementa ItemShareRepository.Delete
+func (r *InMemoryItemShareRepository) Delete(ctx context.Context, itemID, shareID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	share, exists := r.shares[shareID]
+	if !exists || share.ItemID != itemID {
+		return errors.NewNotFoundError("Compartilhamento não encontrado", ErrShareNotFound)
+	}
+
+	delete(r.shares, shareID)
+	return nil
+}
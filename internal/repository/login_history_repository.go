@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"callable-api/internal/ids"
+	"callable-api/internal/models"
+	"callable-api/internal/pagination"
+	"context"
+	"sync"
+)
+
+// MaxLoginAttemptsPerUser limita as tentativas de login mantidas por usuário;
+// as mais antigas são descartadas
+const MaxLoginAttemptsPerUser = 200
+
+// LoginHistoryRepository define as operações de persistência do histórico de login
+type LoginHistoryRepository interface {
+	// Record registra uma tentativa de login
+	Record(ctx context.Context, attempt *models.LoginAttempt) error
+
+	// ListByUser retorna as tentativas do usuário, das mais recentes para as
+	// mais antigas, e o total de tentativas registradas
+	ListByUser(ctx context.Context, userID string, page, limit int) ([]models.LoginAttempt, int, error)
+}
+
+// InMemoryLoginHistoryRepository implementa LoginHistoryRepository em memória
+type InMemoryLoginHistoryRepository struct {
+	attempts map[string][]models.LoginAttempt
+	ids      ids.Generator
+	mutex    sync.RWMutex
+}
+
+// NewInMemoryLoginHistoryRepository cria um novo repositório em memória
+func NewInMemoryLoginHistoryRepository() *InMemoryLoginHistoryRepository {
+	return &InMemoryLoginHistoryRepository{
+		attempts: make(map[string][]models.LoginAttempt),
+		ids:      ids.UUID(),
+	}
+}
+
+// WithIDGenerator define o formato dos IDs das tentativas (padrão: UUID)
+func (r *InMemoryLoginHistoryRepository) WithIDGenerator(gen ids.Generator) *InMemoryLoginHistoryRepository {
+	r.ids = gen
+	return r
+}
+
+// Record implementa LoginHistoryRepository.Record
+func (r *InMemoryLoginHistoryRepository) Record(ctx context.Context, attempt *models.LoginAttempt) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if attempt.ID == "" {
+		attempt.ID = r.ids.NewID()
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	attempts := append(r.attempts[attempt.UserID], *attempt)
+	if len(attempts) > MaxLoginAttemptsPerUser {
+		attempts = append([]models.LoginAttempt(nil), attempts[len(attempts)-MaxLoginAttemptsPerUser:]...)
+	}
+	r.attempts[attempt.UserID] = attempts
+
+	return nil
+}
+
+// ListByUser implementa LoginHistoryRepository.ListByUser
+func (r *InMemoryLoginHistoryRepository) ListByUser(ctx context.Context, userID string, page, limit int) ([]models.LoginAttempt, int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	attempts := r.attempts[userID]
+	total := len(attempts)
+	startIdx, endIdx := pagination.New(page, limit).Window(total)
+
+	// As tentativas são gravadas em ordem cronológica; a listagem começa pela mais recente
+	result := make([]models.LoginAttempt, 0, endIdx-startIdx)
+	for i := startIdx; i < endIdx; i++ {
+		result = append(result, attempts[total-1-i])
+	}
+
+	return result, total, nil
+}
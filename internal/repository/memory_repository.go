@@ -0,0 +1,325 @@
+package repository
+
+import (
+	"context"
+	stderrors "errors"
+	"sort"
+	"sync"
+
+	"callable-api/internal/ids"
+	"callable-api/internal/pagination"
+)
+
+// Repository define as operações comuns aos repositórios de entidades
+// identificadas por ID. Os repositórios de cada entidade o usam como base e
+// expõem apenas as operações do seu domínio
+type Repository[T any] interface {
+	// Get retorna o registro pelo ID
+	Get(ctx context.Context, id string) (*T, error)
+
+	// Insert grava um novo registro, gerando o ID se não for informado
+	Insert(ctx context.Context, record *T) (*T, error)
+
+	// Update substitui um registro existente
+	Update(ctx context.Context, record *T) (*T, error)
+
+	// Delete remove um registro pelo ID
+	Delete(ctx context.Context, id string) error
+
+	// Find retorna o primeiro registro (na ordem de inserção) que satisfaz
+	// match, ou nil se não houver
+	Find(ctx context.Context, match func(*T) bool) (*T, error)
+
+	// Filter retorna todos os registros que satisfazem match, na ordem de inserção
+	Filter(ctx context.Context, match func(*T) bool) ([]T, error)
+
+	// List retorna uma página dos registros selecionados pela consulta e o total
+	List(ctx context.Context, query Query[T], page, limit int) ([]T, int, error)
+
+	// Each chama fn para cada registro que satisfaz match, na ordem de
+	// inserção, sem carregar todos os registros na memória. Um erro de fn
+	// interrompe a iteração e é retornado (exceto ErrStopIteration)
+	Each(ctx context.Context, match func(*T) bool, fn func(*T) error) error
+}
+
+// ErrStopIteration interrompe Each (e os métodos Iterate dos repositórios)
+// sem erro
+var ErrStopIteration = stderrors.New("iteração interrompida")
+
+// iterationBatchSize é o número de registros examinados a cada travamento do
+// mutex durante Each
+const iterationBatchSize = 100
+
+// Query seleciona e ordena os registros de uma listagem
+type Query[T any] struct {
+	Match func(*T) bool      // nil seleciona todos os registros
+	Less  func(a, b *T) bool // nil mantém a ordem de inserção
+}
+
+// MemoryRepository implementa Repository em memória. Os registros são
+// guardados e retornados por cópia, então alterações só valem após Update
+type MemoryRepository[T any] struct {
+	records  map[string]T
+	order    []string          // IDs na ordem de inserção
+	seq      map[string]uint64 // posição de cada ID em order, estável entre remoções
+	lastSeq  uint64
+	idOf     func(*T) *string
+	notFound func() error
+	nextID   func() string
+	onInsert func(record *T)
+	onUpdate func(existing, record *T)
+	conflict func(a, b *T) error
+	mutex    sync.RWMutex
+}
+
+// NewMemoryRepository cria um repositório em memória. idOf aponta o campo de
+// ID do registro e notFound cria o erro retornado para IDs inexistentes
+func NewMemoryRepository[T any](idOf func(*T) *string, notFound func() error) *MemoryRepository[T] {
+	return &MemoryRepository[T]{
+		records:  make(map[string]T),
+		seq:      make(map[string]uint64),
+		idOf:     idOf,
+		notFound: notFound,
+		nextID:   ids.UUID().NewID,
+	}
+}
+
+// WithIDGenerator define como os IDs são gerados (padrão: UUID). O gerador é
+// chamado com o mutex travado
+func (r *MemoryRepository[T]) WithIDGenerator(next func() string) *MemoryRepository[T] {
+	r.nextID = next
+	return r
+}
+
+// WithInsertHook define uma função aplicada a cada novo registro, já com o ID,
+// antes de gravá-lo (por exemplo, para preencher datas de criação)
+func (r *MemoryRepository[T]) WithInsertHook(hook func(record *T)) *MemoryRepository[T] {
+	r.onInsert = hook
+	return r
+}
+
+// WithUpdateHook define uma função aplicada a cada atualização antes de
+// gravá-la, com acesso ao registro atual (por exemplo, para preservar campos)
+func (r *MemoryRepository[T]) WithUpdateHook(hook func(existing, record *T)) *MemoryRepository[T] {
+	r.onUpdate = hook
+	return r
+}
+
+// WithConstraint define uma restrição entre registros, verificada
+// atomicamente em Insert e Update: conflict recebe o registro gravado e outro
+// registro existente e retorna um erro se eles não puderem coexistir
+func (r *MemoryRepository[T]) WithConstraint(conflict func(record, existing *T) error) *MemoryRepository[T] {
+	r.conflict = conflict
+	return r
+}
+
+// Get implementa Repository.Get
+func (r *MemoryRepository[T]) Get(ctx context.Context, id string) (*T, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	record, exists := r.records[id]
+	if !exists {
+		return nil, r.notFound()
+	}
+	return &record, nil
+}
+
+// Insert implementa Repository.Insert. O ID e os campos preenchidos pelo hook
+// também são copiados para record
+func (r *MemoryRepository[T]) Insert(ctx context.Context, record *T) (*T, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	created := *record
+	id := r.idOf(&created)
+	if *id == "" {
+		*id = r.nextID()
+	}
+	if err := r.checkConflicts(&created); err != nil {
+		return nil, err
+	}
+	if r.onInsert != nil {
+		r.onInsert(&created)
+	}
+
+	if _, exists := r.records[*id]; !exists {
+		r.lastSeq++
+		r.seq[*id] = r.lastSeq
+		r.order = append(r.order, *id)
+	}
+	r.records[*id] = created
+	*record = created
+	return &created, nil
+}
+
+// Update implementa Repository.Update
+func (r *MemoryRepository[T]) Update(ctx context.Context, record *T) (*T, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	id := *r.idOf(record)
+	existing, exists := r.records[id]
+	if !exists {
+		return nil, r.notFound()
+	}
+
+	updated := *record
+	if err := r.checkConflicts(&updated); err != nil {
+		return nil, err
+	}
+	if r.onUpdate != nil {
+		r.onUpdate(&existing, &updated)
+	}
+
+	r.records[id] = updated
+	return &updated, nil
+}
+
+// checkConflicts verifica a restrição entre o registro e os demais. Deve ser
+// chamado com o mutex travado
+func (r *MemoryRepository[T]) checkConflicts(record *T) error {
+	if r.conflict == nil {
+		return nil
+	}
+	id := *r.idOf(record)
+	for otherID, other := range r.records {
+		if otherID == id {
+			continue
+		}
+		if err := r.conflict(record, &other); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete implementa Repository.Delete
+func (r *MemoryRepository[T]) Delete(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.records[id]; !exists {
+		return r.notFound()
+	}
+	delete(r.records, id)
+	i := r.position(r.seq[id])
+	r.order = append(r.order[:i], r.order[i+1:]...)
+	delete(r.seq, id)
+	return nil
+}
+
+// position retorna o índice em order do primeiro ID com sequência >= seq.
+// Deve ser chamado com o mutex travado
+func (r *MemoryRepository[T]) position(seq uint64) int {
+	return sort.Search(len(r.order), func(i int) bool {
+		return r.seq[r.order[i]] >= seq
+	})
+}
+
+// Each implementa Repository.Each. Os registros são copiados em lotes e fn é
+// chamada com o mutex liberado, então fn pode alterar o repositório; registros
+// inseridos durante a iteração também são visitados
+func (r *MemoryRepository[T]) Each(ctx context.Context, match func(*T) bool, fn func(*T) error) error {
+	batch := make([]T, 0, iterationBatchSize)
+	var last uint64
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		batch = batch[:0]
+		r.mutex.RLock()
+		for i, scanned := r.position(last+1), 0; i < len(r.order) && scanned < iterationBatchSize; i, scanned = i+1, scanned+1 {
+			id := r.order[i]
+			last = r.seq[id]
+			if record := r.records[id]; match == nil || match(&record) {
+				batch = append(batch, record)
+			}
+		}
+		exhausted := r.position(last+1) >= len(r.order)
+		r.mutex.RUnlock()
+
+		for i := range batch {
+			if err := fn(&batch[i]); err != nil {
+				if stderrors.Is(err, ErrStopIteration) {
+					return nil
+				}
+				return err
+			}
+		}
+		if exhausted {
+			return nil
+		}
+	}
+}
+
+// Find implementa Repository.Find
+func (r *MemoryRepository[T]) Find(ctx context.Context, match func(*T) bool) (*T, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, id := range r.order {
+		record := r.records[id]
+		if match(&record) {
+			return &record, nil
+		}
+	}
+	return nil, nil
+}
+
+// Filter implementa Repository.Filter
+func (r *MemoryRepository[T]) Filter(ctx context.Context, match func(*T) bool) ([]T, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	result := make([]T, 0)
+	for _, id := range r.order {
+		record := r.records[id]
+		if match == nil || match(&record) {
+			result = append(result, record)
+		}
+	}
+	return result, nil
+}
+
+// List implementa Repository.List
+func (r *MemoryRepository[T]) List(ctx context.Context, query Query[T], page, limit int) ([]T, int, error) {
+	records, err := r.Filter(ctx, query.Match)
+	if err != nil {
+		return nil, 0, err
+	}
+	if query.Less != nil {
+		sort.SliceStable(records, func(i, j int) bool {
+			return query.Less(&records[i], &records[j])
+		})
+	}
+
+	total := len(records)
+	start, end := pagination.New(page, limit).Window(total)
+	return records[start:end], total, nil
+}
@@ -0,0 +1,278 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"callable-api/internal/datakeys"
+	"callable-api/internal/filter"
+	"callable-api/internal/models"
+	"callable-api/internal/schema"
+)
+
+func TestItemRepositoryIterate(t *testing.T) {
+	ctx := context.Background()
+	repo := NewEmptyInMemoryItemRepository()
+	for i := 0; i < 250; i++ {
+		owner := "ana"
+		if i%2 == 1 {
+			owner = "bruno"
+		}
+		_, err := repo.Create(ctx, &models.InputData{Name: fmt.Sprintf("Item %d", i), Value: "v", OwnerID: owner})
+		require.NoError(t, err)
+	}
+
+	// Percorre todos os lotes na ordem de criação, mesmo removendo itens durante a iteração
+	var visited []string
+	err := repo.Iterate(ctx, ItemFilter{Access: models.ItemAccess{All: true}, OwnerID: "ana"}, func(item *models.Item) error {
+		visited = append(visited, item.ID)
+		return repo.store.Delete(ctx, item.ID)
+	})
+	require.NoError(t, err)
+	assert.Len(t, visited, 125)
+	assert.Equal(t, "1", visited[0])
+	assert.Equal(t, "249", visited[124])
+
+	// ErrStopIteration interrompe sem erro; outros erros são retornados
+	count := 0
+	err = repo.Iterate(ctx, ItemFilter{Access: models.ItemAccess{All: true}}, func(item *models.Item) error {
+		count++
+		if count == 3 {
+			return ErrStopIteration
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	failure := fmt.Errorf("falha")
+	err = repo.Iterate(ctx, ItemFilter{Access: models.ItemAccess{All: true}}, func(item *models.Item) error {
+		return failure
+	})
+	assert.ErrorIs(t, err, failure)
+
+	// Sem acesso, nenhum item privado é visitado
+	err = repo.Iterate(ctx, ItemFilter{}, func(item *models.Item) error {
+		t.Fatalf("item %s não deveria ser visitado", item.ID)
+		return nil
+	})
+	assert.NoError(t, err)
+}
+
+func TestItemRepositoryFindAllFilters(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryItemRepository()
+	for _, value := range []string{"5", "20"} {
+		_, err := repo.Create(ctx, &models.InputData{Name: "Item " + value, Value: value})
+		require.NoError(t, err)
+	}
+	all := models.ItemAccess{All: true}
+
+	below, err := filter.NewCondition(filter.Field{Name: "value", Kind: filter.Number}, filter.Lt, "10")
+	require.NoError(t, err)
+	items, total, err := repo.FindAll(ctx, all, 1, 10, below)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, "5", items[0].Value)
+
+	// Os itens de exemplo são anteriores aos criados
+	before, err := filter.NewCondition(filter.Field{Name: "created_at", Kind: filter.Time}, filter.Lt, "2023-07-01T00:00:00Z")
+	require.NoError(t, err)
+	_, total, err = repo.FindAll(ctx, all, 1, 10, before)
+	require.NoError(t, err)
+	assert.Equal(t, 10, total)
+
+	_, total, err = repo.FindAll(ctx, all, 1, 10, before, below)
+	require.NoError(t, err)
+	assert.Equal(t, 0, total)
+
+	unknown, err := filter.NewCondition(filter.Field{Name: "owner_id"}, filter.Eq, "ana")
+	require.NoError(t, err)
+	_, _, err = repo.FindAll(ctx, all, 1, 10, unknown)
+	assert.Error(t, err)
+}
+
+func TestEncryption(t *testing.T) {
+	ctx := context.Background()
+	master, err := datakeys.ParseMasterKey(base64.StdEncoding.EncodeToString(make([]byte, 32)))
+	require.NoError(t, err)
+	encryption := NewEncryption(datakeys.NewKeyring(master, datakeys.NewMemoryStore()))
+
+	users := NewEmptyInMemoryUserRepository()
+	owner, err := users.Create(&models.User{Email: "ana@example.com", Name: "Ana", Role: "user", Password: "segredo123"})
+	require.NoError(t, err)
+
+	stored := NewEmptyInMemoryItemRepository()
+	items := encryption.Items(stored)
+	created, err := items.Create(ctx, &models.InputData{Name: "Caderno", Value: "CAD-1", Description: "Anotações", Email: "ana@example.com", OwnerID: owner.ID})
+	require.NoError(t, err)
+	assert.Equal(t, "Anotações", created.Description)
+
+	// Gravado cifrado, lido decifrado
+	raw, err := stored.FindByID(ctx, created.ID)
+	require.NoError(t, err)
+	assert.True(t, datakeys.Encrypted(raw.Description))
+	assert.True(t, datakeys.Encrypted(raw.Email))
+	found, err := items.FindByID(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "ana@example.com", found.Email)
+
+	// Os campos cifrados não são filtráveis, mas continuam na busca
+	_, _, err = items.FindAll(ctx, models.ItemAccess{All: true}, 1, 10, filter.Condition{Field: "email", Value: "ana@example.com"})
+	assert.Error(t, err)
+	_, total, err := items.Search(ctx, models.ItemAccess{All: true}, "Anotações", 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+
+	// Itens públicos não são cifrados
+	public, err := items.Create(ctx, &models.InputData{Name: "Público", Value: "PUB-1", Email: "contato@example.com"})
+	require.NoError(t, err)
+	raw, err = stored.FindByID(ctx, public.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "contato@example.com", raw.Email)
+
+	// Apagar a conta apaga a chave: os campos cifrados passam a ser lidos vazios
+	require.NoError(t, encryption.Users(users).Delete(owner.ID))
+	found, err = items.FindByID(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Caderno", found.Name)
+	assert.Empty(t, found.Description)
+	assert.Empty(t, found.Email)
+}
+
+func TestVersioning(t *testing.T) {
+	ctx := context.Background()
+	items := schema.NewPipeline(
+		func(item *models.Item) *int { return &item.SchemaVersion },
+		schema.Migration[models.Item]{From: 0},
+		schema.Migration[models.Item]{From: 1, Description: "valores em maiúsculas", Up: func(item *models.Item) error {
+			item.Value = strings.ToUpper(item.Value)
+			return nil
+		}},
+	)
+	users := schema.NewPipeline(
+		func(user *models.User) *int { return &user.SchemaVersion },
+		schema.Migration[models.User]{From: 0, Description: "idioma padrão", Up: func(user *models.User) error {
+			if user.Locale == "" {
+				user.Locale = "pt-BR"
+			}
+			return nil
+		}},
+	)
+	versioning := NewVersioning(items, users)
+
+	// Os dados de exemplo são anteriores ao versionamento
+	storedItems := NewInMemoryItemRepository()
+	storedUsers := NewEmptyInMemoryUserRepository()
+	_, err := storedUsers.Create(&models.User{Email: "admin@example.com", Name: "Admin", Role: "admin", Password: "hash"})
+	require.NoError(t, err)
+	versionedItems := versioning.Items(storedItems)
+	versionedUsers := versioning.Users(storedUsers)
+
+	// Atualizados na leitura, sem alterar o registro gravado
+	found, err := versionedItems.FindByID(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, "VALUE-1", found.Value)
+	assert.Equal(t, 2, found.SchemaVersion)
+	raw, err := storedItems.FindByID(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, "Value-1", raw.Value)
+	assert.Equal(t, 0, raw.SchemaVersion)
+
+	user, err := versionedUsers.FindByEmail("admin@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "pt-BR", user.Locale)
+
+	// Gravados na versão atual quando alterados
+	found.Name = "Item renomeado"
+	_, err = versionedItems.Update(ctx, found)
+	require.NoError(t, err)
+	raw, err = storedItems.FindByID(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, "VALUE-1", raw.Value)
+	assert.Equal(t, 2, raw.SchemaVersion)
+
+	// A migração grava os registros restantes
+	result, err := versioning.Migrate(ctx, storedItems, storedUsers)
+	require.NoError(t, err)
+	assert.Equal(t, models.SchemaMigrationCount{Version: 2, Scanned: 10, Migrated: 9}, result.Items)
+	assert.Equal(t, 1, result.Users.Version)
+	assert.Equal(t, result.Users.Scanned, result.Users.Migrated)
+
+	raw, err = storedItems.FindByID(ctx, "10")
+	require.NoError(t, err)
+	assert.Equal(t, "VALUE-10", raw.Value)
+	stored, err := storedUsers.FindByEmail("admin@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "pt-BR", stored.Locale)
+	assert.Equal(t, 1, stored.SchemaVersion)
+
+	result, err = versioning.Migrate(ctx, storedItems, storedUsers)
+	require.NoError(t, err)
+	assert.Zero(t, result.Items.Migrated)
+	assert.Zero(t, result.Users.Migrated)
+}
+
+func TestFixtures(t *testing.T) {
+	ctx := context.Background()
+	fixtures, err := ParseFixtures([]byte(`
+users:
+  - email: owner@example.com
+    name: Owner
+    password: owner123
+items:
+  - name: Private
+    value: V-1
+    owner: owner@example.com
+    created_at: "2024-01-02T03:04:05Z"
+  - name: Public
+    value: V-2
+`), "yaml")
+	require.NoError(t, err)
+
+	users, items := NewEmptyInMemoryUserRepository(), NewEmptyInMemoryItemRepository()
+	summary, err := fixtures.Apply(ctx, users, items)
+	require.NoError(t, err)
+	assert.Equal(t, &FixtureSummary{Users: 1, Items: 2}, summary)
+
+	owner, err := users.Authenticate("owner@example.com", "owner123")
+	require.NoError(t, err)
+	assert.Equal(t, "user", owner.Role)
+
+	private, err := items.FindByValue(ctx, "V-1")
+	require.NoError(t, err)
+	assert.Equal(t, owner.ID, private.OwnerID)
+	assert.Equal(t, "2024-01-02T03:04:05Z", private.CreatedAt)
+	_, public, err := items.FindAll(ctx, models.ItemAccess{}, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, public)
+
+	// Os dados de exemplo embutidos são válidos
+	assert.NotEmpty(t, DefaultFixtures().Items)
+
+	for name, data := range map[string]string{
+		"campo desconhecido": `{"users": [{"email": "a@example.com", "pasword": "x"}]}`,
+		"sem senha":          `{"users": [{"email": "a@example.com"}]}`,
+		"papel inválido":     `{"users": [{"email": "a@example.com", "password": "x", "role": "root"}]}`,
+		"hash inválido":      `{"users": [{"email": "a@example.com", "password_hash": "x"}]}`,
+		"email repetido":     `{"users": [{"email": "a@example.com", "password": "x"}, {"email": "a@example.com", "password": "y"}]}`,
+		"item sem valor":     `{"items": [{"name": "Item"}]}`,
+		"data inválida":      `{"items": [{"name": "Item", "value": "V", "created_at": "ontem"}]}`,
+	} {
+		_, err := ParseFixtures([]byte(data), "json")
+		assert.Error(t, err, name)
+	}
+	_, err = ParseFixtures([]byte(`{}`), "toml")
+	assert.Error(t, err)
+
+	// Donos desconhecidos são recusados na gravação
+	unknownOwner := &Fixtures{Items: []ItemFixture{{Name: "Item", Value: "V", Owner: "ghost@example.com"}}}
+	_, err = unknownOwner.Apply(ctx, NewEmptyInMemoryUserRepository(), NewEmptyInMemoryItemRepository())
+	assert.Error(t, err)
+}
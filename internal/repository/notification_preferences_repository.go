@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"callable-api/internal/models"
+	"callable-api/pkg/errors"
+	"context"
+	"sync"
+	"time"
+)
+
+// NotificationPreferencesRepository define as operações de persistência das
+// preferências de notificação dos usuários
+type NotificationPreferencesRepository interface {
+	// FindByUserID retorna as preferências do usuário (NotFound se ainda não configuradas)
+	FindByUserID(ctx context.Context, userID string) (*models.NotificationPreferences, error)
+
+	// Save cria ou substitui as preferências do usuário
+	Save(ctx context.Context, prefs *models.NotificationPreferences) (*models.NotificationPreferences, error)
+}
+
+// InMemoryNotificationPreferencesRepository implementa NotificationPreferencesRepository em memória
+type InMemoryNotificationPreferencesRepository struct {
+	prefs map[string]models.NotificationPreferences
+	mutex sync.RWMutex
+}
+
+// NewInMemoryNotificationPreferencesRepository cria um novo repositório em memória
+func NewInMemoryNotificationPreferencesRepository() *InMemoryNotificationPreferencesRepository {
+	return &InMemoryNotificationPreferencesRepository{
+		prefs: make(map[string]models.NotificationPreferences),
+	}
+}
+
+// FindByUserID implementa NotificationPreferencesRepository.FindByUserID
+func (r *InMemoryNotificationPreferencesRepository) FindByUserID(ctx context.Context, userID string) (*models.NotificationPreferences, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	prefs, exists := r.prefs[userID]
+	if !exists {
+		return nil, errors.NewNotFoundError("Preferências de notificação não encontradas", nil)
+	}
+
+	return copyNotificationPreferences(&prefs), nil
+}
+
+// Save implementa NotificationPreferencesRepository.Save
+func (r *InMemoryNotificationPreferencesRepository) Save(ctx context.Context, prefs *models.NotificationPreferences) (*models.NotificationPreferences, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	stored := copyNotificationPreferences(prefs)
+	stored.UpdatedAt = time.Now()
+	r.prefs[prefs.UserID] = *stored
+
+	return copyNotificationPreferences(stored), nil
+}
+
+// copyNotificationPreferences cria uma cópia independente do mapa de canais,
+// evitando que o chamador altere o estado interno do repositório
+func copyNotificationPreferences(prefs *models.NotificationPreferences) *models.NotificationPreferences {
+	cp := *prefs
+	cp.Channels = make(map[string][]string, len(prefs.Channels))
+	for event, channels := range prefs.Channels {
+		cp.Channels[event] = append([]string(nil), channels...)
+	}
+	return &cp
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"callable-api/internal/ids"
+	"callable-api/internal/models"
+	"callable-api/internal/pagination"
+	"callable-api/pkg/errors"
+	"context"
+	stderrors "errors"
+	"time"
+)
+
+// ErrNotificationNotFound é a causa do erro NotFound de notificações inexistentes
+var ErrNotificationNotFound = stderrors.New("notificação não encontrada")
+
+// defaultNotificationCapacity é o número máximo de notificações mantidas por usuário
+const defaultNotificationCapacity = 100
+
+// NotificationRepository define as operações de persistência da caixa de
+// entrada de notificações in-app
+type NotificationRepository interface {
+	// Add grava a notificação, gerando o ID. Apenas as notificações mais
+	// recentes de cada usuário são mantidas
+	Add(ctx context.Context, notification *models.Notification) error
+
+	// ListByUser retorna, com paginação, as notificações do usuário (apenas as
+	// não lidas se unreadOnly), da mais recente para a mais antiga
+	ListByUser(ctx context.Context, userID string, unreadOnly bool, page, limit int) ([]models.Notification, int, error)
+
+	// MarkRead marca uma notificação do usuário como lida. Notificações de
+	// outros usuários são tratadas como inexistentes
+	MarkRead(ctx context.Context, userID, id string) (*models.Notification, error)
+
+	// MarkAllRead marca todas as notificações do usuário como lidas e retorna
+	// quantas foram alteradas
+	MarkAllRead(ctx context.Context, userID string) (int, error)
+}
+
+// InMemoryNotificationRepository implementa NotificationRepository em memória
+type InMemoryNotificationRepository struct {
+	store    *MemoryRepository[models.Notification]
+	capacity int
+}
+
+// NewInMemoryNotificationRepository cria um novo repositório em memória
+func NewInMemoryNotificationRepository() *InMemoryNotificationRepository {
+	return &InMemoryNotificationRepository{
+		store: NewMemoryRepository(
+			func(n *models.Notification) *string { return &n.ID },
+			func() error {
+				return errors.NewNotFoundError("Notificação não encontrada", ErrNotificationNotFound)
+			},
+		).
+			WithInsertHook(func(n *models.Notification) {
+				if n.CreatedAt.IsZero() {
+					n.CreatedAt = time.Now().UTC()
+				}
+			}),
+		capacity: defaultNotificationCapacity,
+	}
+}
+
+// WithIDGenerator define o formato dos IDs das notificações (padrão: UUID)
+func (r *InMemoryNotificationRepository) WithIDGenerator(gen ids.Generator) *InMemoryNotificationRepository {
+	r.store.WithIDGenerator(gen.NewID)
+	return r
+}
+
+// Add implementa NotificationRepository.Add, removendo as notificações mais
+// antigas do usuário além da capacidade
+func (r *InMemoryNotificationRepository) Add(ctx context.Context, notification *models.Notification) error {
+	if _, err := r.store.Insert(ctx, notification); err != nil {
+		return err
+	}
+
+	list, err := r.store.Filter(ctx, func(n *models.Notification) bool {
+		return n.UserID == notification.UserID
+	})
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(list)-r.capacity; i++ {
+		if err := r.store.Delete(ctx, list[i].ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListByUser implementa NotificationRepository.ListByUser
+func (r *InMemoryNotificationRepository) ListByUser(ctx context.Context, userID string, unreadOnly bool, page, limit int) ([]models.Notification, int, error) {
+	list, err := r.store.Filter(ctx, func(n *models.Notification) bool {
+		return n.UserID == userID && (!unreadOnly || !n.Read)
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// A ordem de inserção é a cronológica; a listagem começa pelas mais recentes
+	for i, j := 0, len(list)-1; i < j; i, j = i+1, j-1 {
+		list[i], list[j] = list[j], list[i]
+	}
+
+	total := len(list)
+	start, end := pagination.New(page, limit).Window(total)
+	return list[start:end], total, nil
+}
+
+// MarkRead implementa NotificationRepository.MarkRead
+func (r *InMemoryNotificationRepository) MarkRead(ctx context.Context, userID, id string) (*models.Notification, error) {
+	notification, err := r.store.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if notification.UserID != userID {
+		return nil, errors.NewNotFoundError("Notificação não encontrada", ErrNotificationNotFound)
+	}
+	if notification.Read {
+		return notification, nil
+	}
+
+	markRead(notification)
+	return r.store.Update(ctx, notification)
+}
+
+// MarkAllRead implementa NotificationRepository.MarkAllRead
+func (r *InMemoryNotificationRepository) MarkAllRead(ctx context.Context, userID string) (int, error) {
+	unread, err := r.store.Filter(ctx, func(n *models.Notification) bool {
+		return n.UserID == userID && !n.Read
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range unread {
+		markRead(&unread[i])
+		if _, err := r.store.Update(ctx, &unread[i]); err != nil {
+			return i, err
+		}
+	}
+	return len(unread), nil
+}
+
+// markRead marca a notificação como lida agora
+func markRead(notification *models.Notification) {
+	now := time.Now().UTC()
+	notification.Read = true
+	notification.ReadAt = &now
+}
@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"callable-api/internal/ids"
+	"callable-api/internal/models"
+	"callable-api/pkg/errors"
+	"context"
+	stderrors "errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrMembershipNotFound é a causa do erro NotFound de usuários que não são
+// membros da organização
+var ErrMembershipNotFound = stderrors.New("usuário não é membro da organização")
+
+// OrganizationRepository define as operações de persistência das organizações
+// e dos seus membros
+type OrganizationRepository interface {
+	// Create grava uma nova organização
+	Create(ctx context.Context, org *models.Organization) (*models.Organization, error)
+
+	// FindByID busca a organização (NotFound se inexistente)
+	FindByID(ctx context.Context, id string) (*models.Organization, error)
+
+	// SaveMember adiciona o usuário à organização ou, se ele já é membro,
+	// altera o seu papel
+	SaveMember(ctx context.Context, member *models.Membership) (*models.Membership, error)
+
+	// FindMember busca a participação do usuário na organização
+	// (NotFound com ErrMembershipNotFound se ele não é membro)
+	FindMember(ctx context.Context, orgID, userID string) (*models.Membership, error)
+
+	// ListMembers retorna os membros da organização, dos mais antigos para os mais recentes
+	ListMembers(ctx context.Context, orgID string) ([]models.Membership, error)
+
+	// ListForUser retorna as participações do usuário, das mais antigas para as mais recentes
+	ListForUser(ctx context.Context, userID string) ([]models.Membership, error)
+
+	// DeleteMember remove o usuário da organização (NotFound se ele não é membro)
+	DeleteMember(ctx context.Context, orgID, userID string) error
+}
+
+// InMemoryOrganizationRepository implementa OrganizationRepository em memória
+type InMemoryOrganizationRepository struct {
+	orgs    map[string]models.Organization
+	members map[string]map[string]models.Membership // orgID -> userID -> participação
+	ids     ids.Generator
+	mutex   sync.RWMutex
+}
+
+// NewInMemoryOrganizationRepository cria um novo repositório em memória
+func NewInMemoryOrganizationRepository() *InMemoryOrganizationRepository {
+	return &InMemoryOrganizationRepository{
+		orgs:    make(map[string]models.Organization),
+		members: make(map[string]map[string]models.Membership),
+		ids:     ids.UUID(),
+	}
+}
+
+// WithIDGenerator define o formato dos IDs das organizações (padrão: UUID)
+func (r *InMemoryOrganizationRepository) WithIDGenerator(gen ids.Generator) *InMemoryOrganizationRepository {
+	r.ids = gen
+	return r
+}
+
+// Create implementa OrganizationRepository.Create
+func (r *InMemoryOrganizationRepository) Create(ctx context.Context, org *models.Organization) (*models.Organization, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	saved := *org
+	saved.ID = r.ids.NewID()
+	saved.CreatedAt = time.Now().UTC()
+	saved.Role = ""
+	r.orgs[saved.ID] = saved
+	r.members[saved.ID] = make(map[string]models.Membership)
+
+	return &saved, nil
+}
+
+// FindByID implementa OrganizationRepository.FindByID
+func (r *InMemoryOrganizationRepository) FindByID(ctx context.Context, id string) (*models.Organization, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	org, exists := r.orgs[id]
+	if !exists {
+		return nil, errors.NewNotFoundError("Organização não encontrada", nil)
+	}
+	return &org, nil
+}
+
+// SaveMember implementa OrganizationRepository.SaveMember
+func (r *InMemoryOrganizationRepository) SaveMember(ctx context.Context, member *models.Membership) (*models.Membership, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	members, exists := r.members[member.OrgID]
+	if !exists {
+		return nil, errors.NewNotFoundError("Organização não encontrada", nil)
+	}
+
+	saved := *member
+	if existing, ok := members[member.UserID]; ok {
+		saved.JoinedAt = existing.JoinedAt
+	} else {
+		saved.JoinedAt = time.Now().UTC()
+	}
+	members[saved.UserID] = saved
+
+	return &saved, nil
+}
+
+// FindMember implementa OrganizationRepository.FindMember
+func (r *InMemoryOrganizationRepository) FindMember(ctx context.Context, orgID, userID string) (*models.Membership, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	member, exists := r.members[orgID][userID]
+	if !exists {
+		return nil, errors.NewNotFoundError("Usuário não é membro da organização", ErrMembershipNotFound)
+	}
+	return &member, nil
+}
+
+// ListMembers implementa OrganizationRepository.ListMembers
+func (r *InMemoryOrganizationRepository) ListMembers(ctx context.Context, orgID string) ([]models.Membership, error) {
+	return r.list(ctx, func(member *models.Membership) bool {
+		return member.OrgID == orgID
+	})
+}
+
+// ListForUser implementa OrganizationRepository.ListForUser
+func (r *InMemoryOrganizationRepository) ListForUser(ctx context.Context, userID string) ([]models.Membership, error) {
+	return r.list(ctx, func(member *models.Membership) bool {
+		return member.UserID == userID
+	})
+}
+
+// list retorna as participações selecionadas, ordenadas pela data de entrada
+func (r *InMemoryOrganizationRepository) list(ctx context.Context, match func(*models.Membership) bool) ([]models.Membership, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	result := make([]models.Membership, 0)
+	for _, members := range r.members {
+		for _, member := range members {
+			if match(&member) {
+				result = append(result, member)
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].JoinedAt.Before(result[j].JoinedAt)
+	})
+
+	return result, nil
+}
+
+// DeleteMember implementa OrganizationRepository.DeleteMember
+func (r *InMemoryOrganizationRepository) DeleteMember(ctx context.Context, orgID, userID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.members[orgID][userID]; !exists {
+		return errors.NewNotFoundError("Usuário não é membro da organização", ErrMembershipNotFound)
+	}
+	delete(r.members[orgID], userID)
+	return nil
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"callable-api/internal/clock"
+	"callable-api/internal/models"
+	"callable-api/pkg/errors"
+	"context"
+	"sync"
+)
+
+// ScopedTokenRepository define as operações de persistência dos tokens
+// restritos, indexados pelo hash do token
+type ScopedTokenRepository interface {
+	// Save registra o token
+	Save(ctx context.Context, tokenHash string, grant *models.TokenGrant) error
+
+	// Find retorna o token (NotFound se inexistente ou expirado)
+	Find(ctx context.Context, tokenHash string) (*models.TokenGrant, error)
+}
+
+// InMemoryScopedTokenRepository implementa ScopedTokenRepository em memória
+type InMemoryScopedTokenRepository struct {
+	grants map[string]models.TokenGrant
+	mutex  sync.RWMutex
+	clock  clock.Clock
+}
+
+// NewInMemoryScopedTokenRepository cria um novo repositório em memória
+func NewInMemoryScopedTokenRepository() *InMemoryScopedTokenRepository {
+	return &InMemoryScopedTokenRepository{
+		grants: make(map[string]models.TokenGrant),
+		clock:  clock.System(),
+	}
+}
+
+// WithClock define o relógio usado na expiração dos registros
+func (r *InMemoryScopedTokenRepository) WithClock(c clock.Clock) *InMemoryScopedTokenRepository {
+	r.clock = c
+	return r
+}
+
+// Save implementa ScopedTokenRepository.Save, descartando os tokens expirados
+func (r *InMemoryScopedTokenRepository) Save(ctx context.Context, tokenHash string, grant *models.TokenGrant) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	now := r.clock.Now()
+	for hash, existing := range r.grants {
+		if now.After(existing.ExpiresAt) {
+			delete(r.grants, hash)
+		}
+	}
+	saved := *grant
+	saved.Scopes = append([]string(nil), grant.Scopes...)
+	r.grants[tokenHash] = saved
+
+	return nil
+}
+
+// Find implementa ScopedTokenRepository.Find
+func (r *InMemoryScopedTokenRepository) Find(ctx context.Context, tokenHash string) (*models.TokenGrant, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	grant, exists := r.grants[tokenHash]
+	if !exists || r.clock.Now().After(grant.ExpiresAt) {
+		return nil, errors.NewNotFoundError("Token não encontrado", nil)
+	}
+
+	grant.Scopes = append([]string(nil), grant.Scopes...)
+	return &grant, nil
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"callable-api/internal/clock"
+	"callable-api/internal/models"
+	"callable-api/pkg/errors"
+	"context"
+	"sync"
+)
+
+// SessionRepository define as operações de persistência das sessões, indexadas
+// pelo hash do token de atualização
+type SessionRepository interface {
+	// Save registra a sessão do token
+	Save(ctx context.Context, tokenHash string, session *models.Session) error
+
+	// Find retorna a sessão do token (NotFound se inexistente ou expirada)
+	Find(ctx context.Context, tokenHash string) (*models.Session, error)
+
+	// Delete remove a sessão do token
+	Delete(ctx context.Context, tokenHash string) error
+}
+
+// InMemorySessionRepository implementa SessionRepository em memória
+type InMemorySessionRepository struct {
+	sessions map[string]models.Session
+	mutex    sync.RWMutex
+	clock    clock.Clock
+}
+
+// NewInMemorySessionRepository cria um novo repositório em memória
+func NewInMemorySessionRepository() *InMemorySessionRepository {
+	return &InMemorySessionRepository{
+		sessions: make(map[string]models.Session),
+		clock:    clock.System(),
+	}
+}
+
+// WithClock define o relógio usado na expiração dos registros
+func (r *InMemorySessionRepository) WithClock(c clock.Clock) *InMemorySessionRepository {
+	r.clock = c
+	return r
+}
+
+// Save implementa SessionRepository.Save, descartando as sessões expiradas
+func (r *InMemorySessionRepository) Save(ctx context.Context, tokenHash string, session *models.Session) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	now := r.clock.Now()
+	for hash, existing := range r.sessions {
+		if now.After(existing.ExpiresAt) {
+			delete(r.sessions, hash)
+		}
+	}
+	r.sessions[tokenHash] = *session
+
+	return nil
+}
+
+// Find implementa SessionRepository.Find
+func (r *InMemorySessionRepository) Find(ctx context.Context, tokenHash string) (*models.Session, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	session, exists := r.sessions[tokenHash]
+	if !exists || r.clock.Now().After(session.ExpiresAt) {
+		return nil, errors.NewNotFoundError("Sessão não encontrada", nil)
+	}
+
+	return &session, nil
+}
+
+// Delete implementa SessionRepository.Delete
+func (r *InMemorySessionRepository) Delete(ctx context.Context, tokenHash string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delete(r.sessions, tokenHash)
+	return nil
+}
@@ -0,0 +1,32 @@
+// Package testsupport reúne os dados de exemplo dos testes dos repositórios.
+// Só os testes importam este pacote: o binário da API não contém as contas
+// de exemplo e o primeiro administrador vem da configuração inicial (ver
+// service.AuthService.Bootstrap)
+package testsupport
+
+import (
+	"context"
+	_ "embed"
+
+	"callable-api/internal/repository"
+)
+
+// sampleUsers são as contas de exemplo dos testes
+//
+//go:embed testdata/sample_users.json
+var sampleUsers []byte
+
+// NewUserRepository cria um repositório de usuários em memória com as contas
+// de exemplo dos testes (admin@example.com e user@example.com)
+func NewUserRepository() *repository.InMemoryUserRepository {
+	fixtures, err := repository.ParseFixtures(sampleUsers, "json")
+	if err != nil {
+		panic("contas de exemplo inválidas: " + err.Error())
+	}
+
+	users := repository.NewEmptyInMemoryUserRepository()
+	if _, err := fixtures.Apply(context.Background(), users, repository.NewEmptyInMemoryItemRepository()); err != nil {
+		panic("criar as contas de exemplo: " + err.Error())
+	}
+	return users
+}
@@ -1,12 +1,13 @@
 package repository
 
 import (
+	"callable-api/internal/ids"
 	"callable-api/internal/models"
 	"callable-api/pkg/errors"
-	"sync"
+	"context"
+	stderrors "errors"
 	"time"
 
-	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -14,6 +15,26 @@ const (
 	userNotFoundMessage = "Usuário não encontrado" // Definição da constante
 )
 
+// Causas dos erros de contas bloqueadas, para que os handlers possam
+// diferenciá-las (ver CheckAccountState)
+var (
+	ErrAccountDisabled       = stderrors.New("conta desativada")
+	ErrPasswordResetRequired = stderrors.New("redefinição de senha obrigatória")
+)
+
+// CheckAccountState retorna um erro 403 se a conta estiver desativada ou com
+// redefinição de senha pendente
+func CheckAccountState(user *models.User) error {
+	switch {
+	case user.Disabled:
+		return errors.NewForbiddenError("Conta desativada", ErrAccountDisabled)
+	case user.PasswordResetRequired:
+		return errors.NewForbiddenError("Redefinição de senha obrigatória; verifique seu email", ErrPasswordResetRequired)
+	default:
+		return nil
+	}
+}
+
 // UserRepository define as operações do repositório de usuários
 type UserRepository interface {
 	FindByID(id string) (*models.User, error)
@@ -27,194 +48,104 @@ type UserRepository interface {
 
 // InMemoryUserRepository implementa um repositório de usuários em memória
 type InMemoryUserRepository struct {
-	users map[string]*models.User
-	mutex sync.RWMutex
+	store *MemoryRepository[models.User]
 }
 
-// NewInMemoryUserRepository cria um novo repositório de usuários em memória
-func NewInMemoryUserRepository() *InMemoryUserRepository {
-	// Criar com alguns usuários de exemplo
-	repo := &InMemoryUserRepository{
-		users: make(map[string]*models.User),
-	}
-
-	// Criar um usuário administrativo padrão
-	adminPassword, _ := bcrypt.GenerateFromPassword([]byte("admin123"), bcrypt.DefaultCost)
-	adminID := uuid.New().String()
-	repo.users[adminID] = &models.User{
-		ID:        adminID,
-		Email:     "admin@example.com",
-		Name:      "Admin User",
-		Password:  string(adminPassword),
-		Role:      "admin",
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-
-	// Criar um usuário normal de exemplo
-	userPassword, _ := bcrypt.GenerateFromPassword([]byte("user123"), bcrypt.DefaultCost)
-	userID := uuid.New().String()
-	repo.users[userID] = &models.User{
-		ID:        userID,
-		Email:     "user@example.com",
-		Name:      "Regular User",
-		Password:  string(userPassword),
-		Role:      "user",
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+// NewEmptyInMemoryUserRepository cria um repositório de usuários em memória sem usuários
+func NewEmptyInMemoryUserRepository() *InMemoryUserRepository {
+	return &InMemoryUserRepository{
+		store: NewMemoryRepository(
+			func(user *models.User) *string { return &user.ID },
+			func() error { return errors.NewNotFoundError(userNotFoundMessage, nil) },
+		).
+			WithConstraint(func(user, existing *models.User) error {
+				if existing.Email == user.Email {
+					return errors.NewConflictError("Email já está em uso", nil)
+				}
+				return nil
+			}).
+			WithInsertHook(func(user *models.User) {
+				now := time.Now()
+				user.CreatedAt = now
+				user.UpdatedAt = now
+			}).
+			WithUpdateHook(func(existing, user *models.User) {
+				user.UpdatedAt = time.Now()
+				user.CreatedAt = existing.CreatedAt // Preservar data de criação
+			}),
 	}
+}
 
-	return repo
+// WithIDGenerator define o formato dos IDs dos novos registros (padrão: UUID)
+func (r *InMemoryUserRepository) WithIDGenerator(gen ids.Generator) *InMemoryUserRepository {
+	r.store.WithIDGenerator(gen.NewID)
+	return r
 }
 
 // FindByID busca um usuário pelo ID
 func (r *InMemoryUserRepository) FindByID(id string) (*models.User, error) {
-	r.mutex.RLock()
-	defer r.mutex.RUnlock()
-
-	if user, exists := r.users[id]; exists {
-		return user, nil
-	}
-	return nil, errors.NewNotFoundError(userNotFoundMessage, nil) // Usando a constante
+	return r.store.Get(context.Background(), id)
 }
 
 // FindByEmail busca um usuário pelo email
 func (r *InMemoryUserRepository) FindByEmail(email string) (*models.User, error) {
-	r.mutex.RLock()
-	defer r.mutex.RUnlock()
-
-	for _, user := range r.users {
-		if user.Email == email {
-			return user, nil
-		}
-	}
-	return nil, errors.NewNotFoundError(userNotFoundMessage, nil) // Usando a constante
-}
-
-// Create cria um novo usuário
-func (r *InMemoryUserRepository) Create(user *models.User) (*models.User, error) {
-	r.mutex.Lock()
-	defer r.mutex.Unlock()
-
-	// Verificar se o email já está em uso
-	for _, existingUser := range r.users {
-		if existingUser.Email == user.Email {
-			return nil, errors.NewConflictError("Email já está em uso", nil)
-		}
+	user, err := r.findByEmail(email)
+	if err != nil {
+		return nil, err
 	}
-
-	// Gerar um novo ID se não for fornecido
-	if user.ID == "" {
-		user.ID = uuid.New().String()
+	if user == nil {
+		return nil, errors.NewNotFoundError(userNotFoundMessage, nil)
 	}
-
-	// Definir timestamps
-	now := time.Now()
-	user.CreatedAt = now
-	user.UpdatedAt = now
-
-	// Adicionar o usuário ao repositório
-	r.users[user.ID] = user
 	return user, nil
 }
 
-// Update atualiza um usuário existente
-func (r *InMemoryUserRepository) Update(user *models.User) (*models.User, error) {
-	r.mutex.Lock()
-	defer r.mutex.Unlock()
-
-	if _, exists := r.users[user.ID]; !exists {
-		return nil, errors.NewNotFoundError(userNotFoundMessage, nil) // Usando a constante
-	}
-
-	// Verificar se o email está sendo alterado e se o novo email já está em uso
-	oldUser := r.users[user.ID]
-	if user.Email != oldUser.Email {
-		for _, existingUser := range r.users {
-			if existingUser.Email == user.Email && existingUser.ID != user.ID {
-				return nil, errors.NewConflictError("Email já está em uso", nil)
-			}
-		}
-	}
+// findByEmail retorna o usuário com o email informado, ou nil se não houver
+func (r *InMemoryUserRepository) findByEmail(email string) (*models.User, error) {
+	return r.store.Find(context.Background(), func(user *models.User) bool {
+		return user.Email == email
+	})
+}
 
-	// Atualizar timestamp
-	user.UpdatedAt = time.Now()
-	user.CreatedAt = oldUser.CreatedAt // Preservar data de criação
+// Create cria um novo usuário, gerando o ID se não for fornecido
+func (r *InMemoryUserRepository) Create(user *models.User) (*models.User, error) {
+	return r.store.Insert(context.Background(), user)
+}
 
-	// Atualizar o usuário no repositório
-	r.users[user.ID] = user
-	return user, nil
+// Update atualiza um usuário existente, recusando emails já em uso
+func (r *InMemoryUserRepository) Update(user *models.User) (*models.User, error) {
+	return r.store.Update(context.Background(), user)
 }
 
-// List retorna uma lista paginada de usuários
+// List retorna uma lista paginada de usuários, na ordem de criação
 func (r *InMemoryUserRepository) List(page, limit int) ([]models.User, int, error) {
-	r.mutex.RLock()
-	defer r.mutex.RUnlock()
-
-	if page < 1 {
-		page = 1
-	}
-	if limit < 1 || limit > 100 {
-		limit = 10
-	}
-
-	offset := (page - 1) * limit
-	total := len(r.users)
-
-	// Converter o mapa para uma slice
-	users := make([]models.User, 0, len(r.users))
-	for _, user := range r.users {
-		users = append(users, *user)
-	}
-
-	// Aplicar paginação
-	end := offset + limit
-	if end > total {
-		end = total
-	}
-
-	if offset >= total {
-		return []models.User{}, total, nil
-	}
-
-	return users[offset:end], total, nil
+	return r.store.List(context.Background(), Query[models.User]{}, page, limit)
 }
 
 // Delete remove um usuário pelo ID
 func (r *InMemoryUserRepository) Delete(id string) error {
-	r.mutex.Lock()
-	defer r.mutex.Unlock()
-
-	if _, exists := r.users[id]; !exists {
-		return errors.NewNotFoundError(userNotFoundMessage, nil) // Usando a constante
-	}
-
-	delete(r.users, id)
-	return nil
+	return r.store.Delete(context.Background(), id)
 }
 
 // Authenticate verifica as credenciais do usuário e retorna o usuário se válido
 func (r *InMemoryUserRepository) Authenticate(email, password string) (*models.User, error) {
-	r.mutex.RLock()
-	defer r.mutex.RUnlock()
-
-	var user *models.User
-	for _, u := range r.users {
-		if u.Email == email {
-			user = u
-			break
-		}
+	user, err := r.findByEmail(email)
+	if err != nil {
+		return nil, err
 	}
-
 	if user == nil {
 		return nil, errors.NewUnauthorizedError("Credenciais inválidas", nil)
 	}
 
 	// Verificar a senha
-	err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
+	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
 	if err != nil {
 		return nil, errors.NewUnauthorizedError("Credenciais inválidas", nil)
 	}
 
+	// Contas bloqueadas pelos administradores não podem entrar
+	if err := CheckAccountState(user); err != nil {
+		return nil, err
+	}
+
 	return user, nil
 }
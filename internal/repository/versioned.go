@@ -0,0 +1,264 @@
+package repository
+
+import (
+	"context"
+
+	"callable-api/internal/filter"
+	"callable-api/internal/models"
+	"callable-api/internal/schema"
+	"callable-api/pkg/logger"
+)
+
+// ItemSchema é o pipeline de versões dos itens gravados. Uma mudança no
+// formato (ex.: created_at tipado, tags) acrescenta a migração da versão atual
+// para a seguinte
+var ItemSchema = schema.NewPipeline(
+	func(item *models.Item) *int { return &item.SchemaVersion },
+	schema.Migration[models.Item]{From: 0, Description: "itens gravados antes do versionamento"},
+)
+
+// UserSchema é o pipeline de versões dos usuários gravados
+var UserSchema = schema.NewPipeline(
+	func(user *models.User) *int { return &user.SchemaVersion },
+	schema.Migration[models.User]{From: 0, Description: "usuários gravados antes do versionamento"},
+)
+
+// migrationPageSize é o tamanho das páginas de usuários percorridas pela migração
+const migrationPageSize = 100
+
+// Versioning envolve os repositórios com o versionamento dos registros: as
+// gravações marcam a versão atual e as leituras atualizam em memória os
+// registros de versões anteriores, que passam a ser gravados no formato atual
+// na próxima alteração. Migrate atualiza de uma vez os registros restantes
+type Versioning struct {
+	items *schema.Pipeline[models.Item]
+	users *schema.Pipeline[models.User]
+}
+
+// NewVersioning cria um Versioning com os pipelines dos itens e dos usuários
+// (normalmente ItemSchema e UserSchema)
+func NewVersioning(items *schema.Pipeline[models.Item], users *schema.Pipeline[models.User]) *Versioning {
+	return &Versioning{items: items, users: users}
+}
+
+// upgradeItem atualiza o item lido para a versão atual
+func (v *Versioning) upgradeItem(item *models.Item) error {
+	if _, err := v.items.Upgrade(item); err != nil {
+		logger.Error("Falha ao migrar item para a versão atual", map[string]interface{}{
+			"id":    item.ID,
+			"error": err.Error(),
+		})
+		return err
+	}
+	return nil
+}
+
+// upgradeUser atualiza o usuário lido para a versão atual
+func (v *Versioning) upgradeUser(user *models.User) error {
+	if _, err := v.users.Upgrade(user); err != nil {
+		logger.Error("Falha ao migrar usuário para a versão atual", map[string]interface{}{
+			"userId": user.ID,
+			"error":  err.Error(),
+		})
+		return err
+	}
+	return nil
+}
+
+// Items versiona os registros de um ItemRepository
+func (v *Versioning) Items(next ItemRepository) ItemRepository {
+	return &versionedItemRepository{next: next, v: v}
+}
+
+type versionedItemRepository struct {
+	next ItemRepository
+	v    *Versioning
+}
+
+func (r *versionedItemRepository) FindAll(ctx context.Context, access models.ItemAccess, page, limit int, conditions ...filter.Condition) ([]models.Item, int, error) {
+	return r.upgradedList(r.next.FindAll(ctx, access, page, limit, conditions...))
+}
+
+func (r *versionedItemRepository) FindByID(ctx context.Context, id string) (*models.Item, error) {
+	return r.upgraded(r.next.FindByID(ctx, id))
+}
+
+// Create depende do repositório gravar os itens novos na versão atual, já
+// que a entrada não tem versão (ver InMemoryItemRepository.Create)
+func (r *versionedItemRepository) Create(ctx context.Context, input *models.InputData) (*models.Item, error) {
+	return r.upgraded(r.next.Create(ctx, input))
+}
+
+func (r *versionedItemRepository) Update(ctx context.Context, item *models.Item) (*models.Item, error) {
+	stamped := *item
+	r.v.items.Stamp(&stamped)
+	return r.upgraded(r.next.Update(ctx, &stamped))
+}
+
+func (r *versionedItemRepository) FindByName(ctx context.Context, ownerID, name string) (*models.Item, error) {
+	return r.upgraded(r.next.FindByName(ctx, ownerID, name))
+}
+
+func (r *versionedItemRepository) FindByValue(ctx context.Context, value string) (*models.Item, error) {
+	return r.upgraded(r.next.FindByValue(ctx, value))
+}
+
+func (r *versionedItemRepository) Iterate(ctx context.Context, filter ItemFilter, fn func(*models.Item) error) error {
+	return r.next.Iterate(ctx, filter, func(item *models.Item) error {
+		upgraded := *item
+		if err := r.v.upgradeItem(&upgraded); err != nil {
+			return err
+		}
+		return fn(&upgraded)
+	})
+}
+
+func (r *versionedItemRepository) Search(ctx context.Context, access models.ItemAccess, query string, page, limit int) ([]models.Item, int, error) {
+	return r.upgradedList(r.next.Search(ctx, access, query, page, limit))
+}
+
+// upgraded atualiza o item retornado por uma operação, se houver
+func (r *versionedItemRepository) upgraded(item *models.Item, err error) (*models.Item, error) {
+	if err != nil || item == nil {
+		return item, err
+	}
+	upgraded := *item
+	if err := r.v.upgradeItem(&upgraded); err != nil {
+		return nil, err
+	}
+	return &upgraded, nil
+}
+
+// upgradedList atualiza os itens de uma listagem
+func (r *versionedItemRepository) upgradedList(items []models.Item, total int, err error) ([]models.Item, int, error) {
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range items {
+		if err := r.v.upgradeItem(&items[i]); err != nil {
+			return nil, 0, err
+		}
+	}
+	return items, total, nil
+}
+
+// Users versiona os registros de um UserRepository
+func (v *Versioning) Users(next UserRepository) UserRepository {
+	return &versionedUserRepository{next: next, v: v}
+}
+
+type versionedUserRepository struct {
+	next UserRepository
+	v    *Versioning
+}
+
+func (r *versionedUserRepository) FindByID(id string) (*models.User, error) {
+	return r.upgraded(r.next.FindByID(id))
+}
+
+func (r *versionedUserRepository) FindByEmail(email string) (*models.User, error) {
+	return r.upgraded(r.next.FindByEmail(email))
+}
+
+func (r *versionedUserRepository) Create(user *models.User) (*models.User, error) {
+	stamped := *user
+	r.v.users.Stamp(&stamped)
+	return r.upgraded(r.next.Create(&stamped))
+}
+
+func (r *versionedUserRepository) Update(user *models.User) (*models.User, error) {
+	stamped := *user
+	r.v.users.Stamp(&stamped)
+	return r.upgraded(r.next.Update(&stamped))
+}
+
+func (r *versionedUserRepository) List(page, limit int) ([]models.User, int, error) {
+	users, total, err := r.next.List(page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range users {
+		if err := r.v.upgradeUser(&users[i]); err != nil {
+			return nil, 0, err
+		}
+	}
+	return users, total, nil
+}
+
+func (r *versionedUserRepository) Delete(id string) error {
+	return r.next.Delete(id)
+}
+
+func (r *versionedUserRepository) Authenticate(email, password string) (*models.User, error) {
+	return r.upgraded(r.next.Authenticate(email, password))
+}
+
+// upgraded atualiza o usuário retornado por uma operação, se houver
+func (r *versionedUserRepository) upgraded(user *models.User, err error) (*models.User, error) {
+	if err != nil || user == nil {
+		return user, err
+	}
+	upgraded := *user
+	if err := r.v.upgradeUser(&upgraded); err != nil {
+		return nil, err
+	}
+	return &upgraded, nil
+}
+
+// Migrate grava no formato atual os itens e usuários de versões anteriores,
+// para o job de migração. items e users devem ser os repositórios envolvidos
+// por Items e Users (os registros gravados, sem a atualização na leitura).
+// Registros alterados durante a migração são regravados a partir da versão
+// lida, então o job deve rodar fora dos horários de pico
+func (v *Versioning) Migrate(ctx context.Context, items ItemRepository, users UserRepository) (*models.SchemaMigration, error) {
+	result := &models.SchemaMigration{
+		Items: models.SchemaMigrationCount{Version: v.items.Current()},
+		Users: models.SchemaMigrationCount{Version: v.users.Current()},
+	}
+
+	err := items.Iterate(ctx, ItemFilter{Access: models.ItemAccess{All: true}}, func(item *models.Item) error {
+		result.Items.Scanned++
+		if !v.items.Outdated(item) {
+			return nil
+		}
+		upgraded := *item
+		if _, err := v.items.Upgrade(&upgraded); err != nil {
+			return err
+		}
+		if _, err := items.Update(ctx, &upgraded); err != nil {
+			return err
+		}
+		result.Items.Migrated++
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for page := 1; ; page++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		list, total, err := users.List(page, migrationPageSize)
+		if err != nil {
+			return nil, err
+		}
+		for i := range list {
+			result.Users.Scanned++
+			if !v.users.Outdated(&list[i]) {
+				continue
+			}
+			if _, err := v.users.Upgrade(&list[i]); err != nil {
+				return nil, err
+			}
+			if _, err := users.Update(&list[i]); err != nil {
+				return nil, err
+			}
+			result.Users.Migrated++
+		}
+		if len(list) == 0 || page*migrationPageSize >= total {
+			break
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,134 @@
+// Package rotation trata as notificações de rotação do Secret Manager,
+// entregues por push do Pub/Sub: o segredo rotacionado é descartado do cache
+// e os consumidores registrados (ex.: o segredo do JWT) são avisados para
+// buscar a nova versão, sem reiniciar o servidor.
+package rotation
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"callable-api/internal/models"
+)
+
+// Tipos de evento (atributo eventType) enviados pelo Secret Manager que
+// alteram o valor da versão mais recente do segredo
+const (
+	EventSecretRotate     = "SECRET_ROTATE"
+	EventSecretVersionAdd = "SECRET_VERSION_ADD"
+)
+
+// DefaultJWTSecretName é o nome padrão do segredo do JWT no Secret Manager
+const DefaultJWTSecretName = "jwt-secret"
+
+// ErrInvalidNotification indica uma mensagem sem o segredo (atributo secretId)
+var ErrInvalidNotification = stderrors.New("notificação do Secret Manager sem secretId")
+
+// Config define o acesso do Pub/Sub ao endpoint de rotação
+type Config struct {
+	// Token é o token compartilhado com a assinatura push (header
+	// Authorization: Bearer ou parâmetro token da URL de push)
+	Token string
+	// JWTSecretName é o segredo do JWT, recarregado ao ser rotacionado
+	JWTSecretName string
+}
+
+// Enabled indica se o endpoint de rotação deve ser exposto
+func (c Config) Enabled() bool {
+	return c.Token != ""
+}
+
+// PushRequest é o corpo da entrega push do Pub/Sub
+type PushRequest struct {
+	Message      PushMessage `json:"message"`
+	Subscription string      `json:"subscription"`
+}
+
+// PushMessage é a mensagem publicada pelo Secret Manager; os dados do evento
+// vêm nos atributos (eventType e secretId)
+type PushMessage struct {
+	Attributes  map[string]string `json:"attributes"`
+	Data        []byte            `json:"data,omitempty"`
+	MessageID   string            `json:"messageId"`
+	PublishTime time.Time         `json:"publishTime"`
+}
+
+// SecretName extrai o nome do segredo do secretId
+// (projects/<projeto>/secrets/<nome>)
+func SecretName(secretID string) string {
+	if _, name, found := strings.Cut(secretID, "/secrets/"); found {
+		secretID = name
+	}
+	name, _, _ := strings.Cut(secretID, "/")
+	return name
+}
+
+// Invalidator descarta um segredo do cache (ver cloud.SecretCache)
+type Invalidator interface {
+	Invalidate(name string)
+}
+
+// Hook é avisado quando um segredo muda de valor. Um erro faz a notificação
+// responder com falha, para que o Pub/Sub a entregue de novo
+type Hook func(ctx context.Context, secret string) error
+
+// Service processa as notificações de rotação
+type Service struct {
+	invalidator Invalidator
+
+	mu    sync.RWMutex
+	hooks map[string][]Hook
+}
+
+// NewService cria o serviço. invalidator pode ser nil quando os segredos não
+// estão em cache
+func NewService(invalidator Invalidator) *Service {
+	return &Service{invalidator: invalidator, hooks: make(map[string][]Hook)}
+}
+
+// OnRotate registra um hook executado quando o segredo muda de valor
+func (s *Service) OnRotate(secret string, hook Hook) *Service {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hooks[secret] = append(s.hooks[secret], hook)
+	return s
+}
+
+// Handle processa a mensagem: qualquer evento do segredo o descarta do cache
+// (versões desativadas ou destruídas também mudam a versão mais recente) e os
+// eventos de rotação e de nova versão executam os hooks do segredo
+func (s *Service) Handle(ctx context.Context, message PushMessage) (*models.SecretRotation, error) {
+	secret := SecretName(message.Attributes["secretId"])
+	if secret == "" {
+		return nil, ErrInvalidNotification
+	}
+
+	result := &models.SecretRotation{
+		Secret:    secret,
+		EventType: message.Attributes["eventType"],
+		MessageID: message.MessageID,
+	}
+	if s.invalidator != nil {
+		s.invalidator.Invalidate(secret)
+		result.Invalidated = true
+	}
+
+	if result.EventType != EventSecretRotate && result.EventType != EventSecretVersionAdd {
+		return result, nil
+	}
+
+	s.mu.RLock()
+	hooks := s.hooks[secret]
+	s.mu.RUnlock()
+	for _, hook := range hooks {
+		if err := hook(ctx, secret); err != nil {
+			return nil, fmt.Errorf("recarregar segredo %s: %w", secret, err)
+		}
+		result.Reloaded++
+	}
+	return result, nil
+}
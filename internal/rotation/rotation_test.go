@@ -0,0 +1,71 @@
+package rotation
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeInvalidator registra os segredos descartados
+type fakeInvalidator struct {
+	invalidated []string
+}
+
+func (f *fakeInvalidator) Invalidate(name string) {
+	f.invalidated = append(f.invalidated, name)
+}
+
+func TestSecretName(t *testing.T) {
+	assert.Equal(t, "jwt-secret", SecretName("projects/demo/secrets/jwt-secret"))
+	assert.Equal(t, "jwt-secret", SecretName("projects/demo/secrets/jwt-secret/versions/3"))
+	assert.Equal(t, "jwt-secret", SecretName("jwt-secret"))
+	assert.Equal(t, "", SecretName(""))
+}
+
+func TestService_Handle(t *testing.T) {
+	ctx := context.Background()
+	cache := &fakeInvalidator{}
+	var reloaded []string
+	service := NewService(cache).OnRotate("jwt-secret", func(_ context.Context, secret string) error {
+		reloaded = append(reloaded, secret)
+		return nil
+	})
+
+	message := func(eventType, secret string) PushMessage {
+		return PushMessage{MessageID: "1", Attributes: map[string]string{
+			"eventType": eventType,
+			"secretId":  "projects/demo/secrets/" + secret,
+		}}
+	}
+
+	// Rotação do segredo do JWT: descarta do cache e recarrega
+	result, err := service.Handle(ctx, message(EventSecretRotate, "jwt-secret"))
+	require.NoError(t, err)
+	assert.True(t, result.Invalidated)
+	assert.Equal(t, 1, result.Reloaded)
+	assert.Equal(t, []string{"jwt-secret"}, reloaded)
+
+	// Outros eventos apenas descartam o segredo do cache
+	result, err = service.Handle(ctx, message("SECRET_VERSION_DISABLE", "jwt-secret"))
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Reloaded)
+	assert.Equal(t, []string{"jwt-secret", "jwt-secret"}, cache.invalidated)
+
+	// Segredos sem hooks
+	result, err = service.Handle(ctx, message(EventSecretVersionAdd, "db-password"))
+	require.NoError(t, err)
+	assert.Equal(t, "db-password", result.Secret)
+	assert.Equal(t, 0, result.Reloaded)
+
+	// Mensagem sem secretId
+	_, err = service.Handle(ctx, PushMessage{Attributes: map[string]string{"eventType": EventSecretRotate}})
+	assert.ErrorIs(t, err, ErrInvalidNotification)
+
+	// Falha no hook é devolvida para que o Pub/Sub entregue de novo
+	service.OnRotate("jwt-secret", func(context.Context, string) error { return stderrors.New("indisponível") })
+	_, err = service.Handle(ctx, message(EventSecretRotate, "jwt-secret"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,404 @@
+// Package routes permite declarar as rotas da API com seus requisitos de
+// segurança (modo de autenticação, papéis exigidos e classe de limite de
+// requisições, política CORS). O Registry monta a cadeia de middlewares de cada rota a
+// partir da declaração e exporta a matriz de segurança para revisão.
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"callable-api/internal/models"
+)
+
+// AuthMode define como a rota autentica o cliente
+type AuthMode string
+
+// Modos de autenticação suportados
+const (
+	AuthPublic   AuthMode = "public"
+	AuthJWT      AuthMode = "jwt"
+	AuthOptional AuthMode = "optional" // JWT quando informado; anônimo caso contrário
+	AuthSCIM     AuthMode = "scim"     // token do provedor de identidade (provisionamento SCIM)
+	AuthInternal AuthMode = "internal" // token das integrações internas (push do Pub/Sub)
+	AuthMetrics  AuthMode = "metrics"  // token do coletor do Prometheus
+)
+
+// Classes de limite de requisições
+const (
+	RateStandard  = "standard"
+	RateStrict    = "strict"
+	RateUnlimited = "unlimited"
+	RateAnonymous = "anonymous" // clientes anônimos das rotas do catálogo (ver WithAnonymous)
+)
+
+// AnonymousMode define o acesso sem token às rotas com AuthOptional
+type AnonymousMode string
+
+// Modos de acesso anônimo
+const (
+	// AnonymousFull permite o acesso anônimo a todas as rotas AuthOptional,
+	// com o limite da rota (padrão)
+	AnonymousFull AnonymousMode = "full"
+	// AnonymousCatalog permite o acesso anônimo apenas às rotas com Anonymous,
+	// com o limite RateAnonymous e o conjunto reduzido de campos (ver Anonymous)
+	AnonymousCatalog AnonymousMode = "catalog"
+	// AnonymousOff exige o token em todas as rotas
+	AnonymousOff AnonymousMode = "off"
+)
+
+// ParseAnonymousMode interpreta o modo de acesso anônimo ("" equivale a full)
+func ParseAnonymousMode(value string) (AnonymousMode, error) {
+	switch mode := AnonymousMode(strings.ToLower(strings.TrimSpace(value))); mode {
+	case "", AnonymousFull:
+		return AnonymousFull, nil
+	case AnonymousCatalog, AnonymousOff:
+		return mode, nil
+	default:
+		return AnonymousFull, fmt.Errorf("modo de acesso anônimo desconhecido: %q", value)
+	}
+}
+
+// Políticas CORS dos grupos de rotas. CORSDefault vale para as rotas sem
+// política declarada
+const (
+	CORSDefault = "default"
+	CORSPublic  = "public" // dados públicos, consumidos pelos frontends de parceiros
+	CORSAuth    = "auth"   // autenticação e conta do usuário
+	CORSAdmin   = "admin"  // administração, usada pelas ferramentas internas
+)
+
+// Route declara uma rota e seus requisitos de segurança
+type Route struct {
+	Method      string
+	Path        string
+	Handler     gin.HandlerFunc
+	Auth        AuthMode
+	Roles       []string
+	RateClass   string
+	Description string
+
+	// Strict rejeita campos desconhecidos no corpo JSON (ver Strict)
+	Strict bool
+
+	// CORS é a política CORS da rota (ver WithCORS e CORSGroup)
+	CORS string
+
+	// Anonymous expõe a rota GET com AuthOptional aos clientes sem token no
+	// modo AnonymousCatalog (catálogo público somente leitura)
+	Anonymous bool
+}
+
+// CORSGroup atribui a política CORS às rotas que não declaram outra, para que
+// um grupo de rotas (ex.: administração) seja declarado com a sua política
+func CORSGroup(policy string, routes ...Route) []Route {
+	for i := range routes {
+		if routes[i].CORS == "" {
+			routes[i].CORS = policy
+		}
+	}
+	return routes
+}
+
+// strictKey marca no contexto as requisições das rotas com Strict
+const strictKey = "routes.strict"
+
+// Strict indica se a rota da requisição rejeita campos desconhecidos no corpo
+// JSON, para que erros de digitação do cliente não sejam descartados em silêncio
+func Strict(c *gin.Context) bool {
+	return c.GetBool(strictKey)
+}
+
+// markStrict marca a requisição para a validação estrita do corpo
+func markStrict(c *gin.Context) {
+	c.Set(strictKey, true)
+}
+
+// anonymousKey marca no contexto as requisições anônimas do catálogo
+const anonymousKey = "routes.anonymous"
+
+// Anonymous indica se a requisição é de um cliente anônimo do catálogo
+// público (AnonymousCatalog): a resposta deve trazer apenas os campos públicos
+func Anonymous(c *gin.Context) bool {
+	return c.GetBool(anonymousKey)
+}
+
+// Registry acumula as rotas declaradas e os middlewares de cada modo de
+// autenticação e classe de limite
+type Registry struct {
+	routes         []Route
+	authenticators map[AuthMode]gin.HandlerFunc
+	authenticated  []gin.HandlerFunc
+	limiters       map[string]gin.HandlerFunc
+	cors           map[string]gin.HandlerFunc
+	requireRoles   func(roles ...string) gin.HandlerFunc
+	requireScope   func(scope string) gin.HandlerFunc
+	anonymous      AnonymousMode
+}
+
+// New cria um Registry. requireRoles constrói o middleware que verifica os
+// papéis exigidos (normalmente middleware.RequireRole)
+func New(requireRoles func(roles ...string) gin.HandlerFunc) *Registry {
+	return &Registry{
+		authenticators: map[AuthMode]gin.HandlerFunc{AuthPublic: nil},
+		limiters:       map[string]gin.HandlerFunc{RateUnlimited: nil},
+		cors:           map[string]gin.HandlerFunc{CORSDefault: nil},
+		requireRoles:   requireRoles,
+		anonymous:      AnonymousFull,
+	}
+}
+
+// WithAuth registra o middleware de um modo de autenticação
+func (r *Registry) WithAuth(mode AuthMode, handler gin.HandlerFunc) *Registry {
+	r.authenticators[mode] = handler
+	return r
+}
+
+// WithAuthenticated registra um middleware executado logo após a autenticação
+// nas rotas que não são públicas (ex.: aplicar as preferências do usuário)
+func (r *Registry) WithAuthenticated(handler gin.HandlerFunc) *Registry {
+	r.authenticated = append(r.authenticated, handler)
+	return r
+}
+
+// WithScopes verifica, nas rotas com AuthJWT e AuthOptional, se o token da
+// requisição concede o escopo da rota (ver models.RouteScope). requireScope
+// constrói o middleware da verificação (normalmente middleware.RequireScope)
+func (r *Registry) WithScopes(requireScope func(scope string) gin.HandlerFunc) *Registry {
+	r.requireScope = requireScope
+	return r
+}
+
+// WithRateLimit registra o middleware de uma classe de limite de requisições
+func (r *Registry) WithRateLimit(class string, handler gin.HandlerFunc) *Registry {
+	r.limiters[class] = handler
+	return r
+}
+
+// WithCORS registra o middleware de uma política CORS (normalmente
+// middleware.CORS). Sem middleware para CORSDefault, as rotas sem política não
+// respondem a outras origens
+func (r *Registry) WithCORS(policy string, handler gin.HandlerFunc) *Registry {
+	r.cors[policy] = handler
+	return r
+}
+
+// WithAnonymous define o acesso sem token às rotas com AuthOptional. No modo
+// AnonymousCatalog o limite dos clientes anônimos é o da classe RateAnonymous,
+// que deve ser registrada com WithRateLimit
+func (r *Registry) WithAnonymous(mode AnonymousMode) *Registry {
+	r.anonymous = mode
+	return r
+}
+
+// Add declara rotas. Campos omitidos assumem autenticação pública, a classe
+// de limite padrão e a política CORS padrão
+func (r *Registry) Add(routes ...Route) {
+	for _, route := range routes {
+		if route.Auth == "" {
+			route.Auth = AuthPublic
+		}
+		if route.RateClass == "" {
+			route.RateClass = RateStandard
+		}
+		if route.CORS == "" {
+			route.CORS = CORSDefault
+		}
+		r.routes = append(r.routes, route)
+	}
+}
+
+// Mount registra as rotas no router, na ordem CORS → limite → autenticação →
+// papéis → handler. Rotas GET também respondem a HEAD (com a mesma cadeia) e
+// cada caminho responde a OPTIONS com o header Allow dos métodos declarados,
+// salvo quando HEAD ou OPTIONS forem declarados explicitamente; os preflights
+// CORS são respondidos pela política do caminho. Retorna erro se alguma
+// declaração for inconsistente, incluindo políticas CORS diferentes no mesmo
+// caminho
+func (r *Registry) Mount(router gin.IRoutes) error {
+	declared := make(map[string]bool, len(r.routes))
+	for _, route := range r.routes {
+		declared[route.Method+" "+route.Path] = true
+	}
+
+	allowed := make(map[string][]string)
+	policies := make(map[string]string)
+	var paths []string
+	allow := func(path, method string) {
+		if _, exists := allowed[path]; !exists {
+			paths = append(paths, path)
+		}
+		allowed[path] = append(allowed[path], method)
+	}
+
+	for _, route := range r.routes {
+		chain, err := r.chain(route)
+		if err != nil {
+			return err
+		}
+		if policy, exists := policies[route.Path]; exists && policy != route.CORS {
+			return fmt.Errorf("routes: %s usa a política CORS %q, mas o caminho já usa %q", route.Method+" "+route.Path, route.CORS, policy)
+		}
+		policies[route.Path] = route.CORS
+		router.Handle(route.Method, route.Path, chain...)
+		allow(route.Path, route.Method)
+
+		if route.Method == http.MethodGet && !declared[http.MethodHead+" "+route.Path] {
+			router.Handle(http.MethodHead, route.Path, chain...)
+			allow(route.Path, http.MethodHead)
+		}
+	}
+
+	for _, path := range paths {
+		if declared[http.MethodOptions+" "+path] {
+			continue
+		}
+		methods := append(allowed[path], http.MethodOptions)
+		sort.Strings(methods)
+		var chain []gin.HandlerFunc
+		if cors := r.cors[policies[path]]; cors != nil {
+			chain = append(chain, cors)
+		}
+		router.Handle(http.MethodOptions, path, append(chain, options(strings.Join(methods, ", ")))...)
+	}
+	return nil
+}
+
+// options responde a OPTIONS com os métodos aceitos pelo caminho
+func options(allow string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Allow", allow)
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// chain monta a cadeia de handlers de uma rota
+func (r *Registry) chain(route Route) ([]gin.HandlerFunc, error) {
+	name := route.Method + " " + route.Path
+	var err error
+	if route.Handler == nil {
+		return nil, fmt.Errorf("routes: %s sem handler", name)
+	}
+
+	limiter, ok := r.limiters[route.RateClass]
+	if !ok {
+		return nil, fmt.Errorf("routes: %s usa a classe de limite desconhecida %q", name, route.RateClass)
+	}
+	authenticator, ok := r.authenticators[route.Auth]
+	if !ok {
+		return nil, fmt.Errorf("routes: %s usa o modo de autenticação desconhecido %q", name, route.Auth)
+	}
+	if len(route.Roles) > 0 && route.Auth == AuthPublic {
+		return nil, fmt.Errorf("routes: %s exige papéis mas é pública", name)
+	}
+	cors, ok := r.cors[route.CORS]
+	if !ok {
+		return nil, fmt.Errorf("routes: %s usa a política CORS desconhecida %q", name, route.CORS)
+	}
+	if route.Anonymous && (route.Method != http.MethodGet || route.Auth != AuthOptional) {
+		return nil, fmt.Errorf("routes: %s é anônima mas não é um GET com autenticação opcional", name)
+	}
+	if route.Auth == AuthOptional && r.anonymous != AnonymousFull {
+		if limiter, err = r.anonymousGate(route, limiter); err != nil {
+			return nil, err
+		}
+	}
+
+	// O CORS vem primeiro para que as respostas de erro (limite, autenticação)
+	// também possam ser lidas pelo frontend da outra origem
+	var chain []gin.HandlerFunc
+	if cors != nil {
+		chain = append(chain, cors)
+	}
+	if limiter != nil {
+		chain = append(chain, limiter)
+	}
+	if authenticator != nil {
+		chain = append(chain, authenticator)
+		if scope := route.scope(); scope != "" && r.requireScope != nil {
+			chain = append(chain, r.requireScope(scope))
+		}
+		chain = append(chain, r.authenticated...)
+	}
+	if len(route.Roles) > 0 {
+		chain = append(chain, r.requireRoles(route.Roles...))
+	}
+	if route.Strict {
+		chain = append(chain, markStrict)
+	}
+	return append(chain, route.Handler), nil
+}
+
+// scope retorna o escopo exigido dos tokens restritos. Apenas as rotas
+// autenticadas pelos tokens dos usuários têm escopo
+func (route Route) scope() string {
+	if route.Auth != AuthJWT && route.Auth != AuthOptional {
+		return ""
+	}
+	return models.RouteScope(route.Method, route.Path)
+}
+
+// anonymousGate substitui o limite das rotas com AuthOptional fora do modo
+// AnonymousFull: requisições com token seguem com o limite da rota; sem
+// token, são rejeitadas ou, nas rotas do catálogo, marcadas como anônimas e
+// limitadas pela classe RateAnonymous
+func (r *Registry) anonymousGate(route Route, limiter gin.HandlerFunc) (gin.HandlerFunc, error) {
+	exposed := r.anonymous == AnonymousCatalog && route.Anonymous
+	anonymous, ok := r.limiters[RateAnonymous]
+	if exposed && !ok {
+		return nil, fmt.Errorf("routes: %s é anônima, mas a classe de limite %q não foi registrada", route.Method+" "+route.Path, RateAnonymous)
+	}
+
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") != "" {
+			next(c, limiter)
+			return
+		}
+		if !exposed {
+			c.AbortWithStatusJSON(models.ErrUnauthorized.Code, models.ErrUnauthorized)
+			return
+		}
+		c.Set(anonymousKey, true)
+		next(c, anonymous)
+	}, nil
+}
+
+// next executa o middleware opcional e segue a cadeia
+func next(c *gin.Context, handler gin.HandlerFunc) {
+	if handler == nil {
+		c.Next()
+		return
+	}
+	handler(c)
+}
+
+// Matrix retorna a matriz de segurança das rotas, ordenada por caminho e método
+func (r *Registry) Matrix() []models.RouteSecurity {
+	matrix := make([]models.RouteSecurity, 0, len(r.routes))
+	for _, route := range r.routes {
+		matrix = append(matrix, models.RouteSecurity{
+			Method:      route.Method,
+			Path:        route.Path,
+			Auth:        string(route.Auth),
+			Roles:       route.Roles,
+			RateClass:   route.RateClass,
+			Strict:      route.Strict,
+			CORS:        route.CORS,
+			Anonymous:   route.Anonymous,
+			Scope:       route.scope(),
+			Description: route.Description,
+		})
+	}
+
+	sort.Slice(matrix, func(i, j int) bool {
+		if matrix[i].Path != matrix[j].Path {
+			return matrix[i].Path < matrix[j].Path
+		}
+		return matrix[i].Method < matrix[j].Method
+	})
+	return matrix
+}
@@ -0,0 +1,301 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// marker cria um middleware que registra sua execução no cabeçalho da resposta
+func marker(name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer.Header().Add("X-Chain", name)
+		c.Next()
+	}
+}
+
+func newTestRegistry() *Registry {
+	requireRoles := func(roles ...string) gin.HandlerFunc { return marker("roles") }
+	return New(requireRoles).
+		WithAuth(AuthJWT, marker("jwt")).
+		WithRateLimit(RateStandard, marker("standard"))
+}
+
+func ok(c *gin.Context) {
+	c.Status(http.StatusOK)
+}
+
+func TestMount(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	registry := newTestRegistry()
+	registry.Add(
+		Route{Method: http.MethodGet, Path: "/public", Handler: ok},
+		Route{Method: http.MethodGet, Path: "/admin", Handler: ok, Auth: AuthJWT, Roles: []string{"admin"}},
+		Route{Method: http.MethodGet, Path: "/health", Handler: ok, RateClass: RateUnlimited},
+	)
+
+	router := gin.New()
+	assert.NoError(t, registry.Mount(router))
+
+	chain := func(path string) []string {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		return w.Header().Values("X-Chain")
+	}
+
+	assert.Equal(t, []string{"standard"}, chain("/public"))
+	assert.Equal(t, []string{"standard", "jwt", "roles"}, chain("/admin"))
+	assert.Empty(t, chain("/health"))
+}
+
+func TestMount_Authenticated(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	registry := newTestRegistry().WithAuthenticated(marker("prefs"))
+	registry.Add(
+		Route{Method: http.MethodGet, Path: "/public", Handler: ok},
+		Route{Method: http.MethodGet, Path: "/admin", Handler: ok, Auth: AuthJWT, Roles: []string{"admin"}},
+	)
+
+	router := gin.New()
+	assert.NoError(t, registry.Mount(router))
+
+	chain := func(path string) []string {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w.Header().Values("X-Chain")
+	}
+
+	// Executado logo após a autenticação, apenas nas rotas autenticadas
+	assert.Equal(t, []string{"standard"}, chain("/public"))
+	assert.Equal(t, []string{"standard", "jwt", "prefs", "roles"}, chain("/admin"))
+}
+
+func TestMount_Scopes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	registry := newTestRegistry().
+		WithAuthenticated(marker("prefs")).
+		WithScopes(func(scope string) gin.HandlerFunc { return marker("scope:" + scope) })
+	registry.Add(
+		Route{Method: http.MethodGet, Path: "/api/v1/public", Handler: ok},
+		Route{Method: http.MethodPost, Path: "/api/v1/data/:id", Handler: ok, Auth: AuthJWT},
+	)
+
+	router := gin.New()
+	assert.NoError(t, registry.Mount(router))
+
+	chain := func(method, path string) []string {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w.Header().Values("X-Chain")
+	}
+
+	// O escopo é verificado logo após a autenticação, apenas nas rotas autenticadas
+	assert.Equal(t, []string{"standard"}, chain(http.MethodGet, "/api/v1/public"))
+	assert.Equal(t, []string{"standard", "jwt", "scope:data:write", "prefs"}, chain(http.MethodPost, "/api/v1/data/1"))
+
+	matrix := registry.Matrix()
+	assert.Equal(t, "data:write", matrix[0].Scope)
+	assert.Equal(t, "", matrix[1].Scope)
+}
+
+func TestMount_HeadAndOptions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	registry := newTestRegistry()
+	registry.Add(
+		Route{Method: http.MethodGet, Path: "/items", Handler: ok, Auth: AuthJWT},
+		Route{Method: http.MethodPost, Path: "/items", Handler: ok, Auth: AuthJWT},
+		Route{Method: http.MethodPut, Path: "/items/:id", Handler: ok, Auth: AuthJWT},
+	)
+
+	router := gin.New()
+	assert.NoError(t, registry.Mount(router))
+
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+
+	// HEAD usa a mesma cadeia do GET
+	w := serve(http.MethodHead, "/items")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"standard", "jwt"}, w.Header().Values("X-Chain"))
+
+	// OPTIONS lista os métodos declarados para o caminho, sem autenticação
+	w = serve(http.MethodOptions, "/items")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "GET, HEAD, OPTIONS, POST", w.Header().Get("Allow"))
+	assert.Empty(t, w.Header().Values("X-Chain"))
+
+	w = serve(http.MethodOptions, "/items/1")
+	assert.Equal(t, "OPTIONS, PUT", w.Header().Get("Allow"))
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodHead, "/items/1").Code)
+}
+
+func TestMount_CORS(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	registry := newTestRegistry().
+		WithCORS(CORSDefault, marker("cors-default")).
+		WithCORS(CORSAdmin, func(c *gin.Context) {
+			c.Writer.Header().Add("X-Chain", "cors-admin")
+			if c.Request.Method == http.MethodOptions {
+				c.AbortWithStatus(http.StatusNoContent)
+				return
+			}
+			c.Next()
+		})
+	registry.Add(Route{Method: http.MethodGet, Path: "/public", Handler: ok})
+	registry.Add(CORSGroup(CORSAdmin,
+		Route{Method: http.MethodGet, Path: "/admin", Handler: ok, Auth: AuthJWT, Roles: []string{"admin"}},
+		Route{Method: http.MethodPost, Path: "/admin", Handler: ok, Auth: AuthJWT, Roles: []string{"admin"}},
+	)...)
+
+	router := gin.New()
+	assert.NoError(t, registry.Mount(router))
+
+	chain := func(method, path string) []string {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w.Header().Values("X-Chain")
+	}
+
+	// A política do grupo vem antes do limite e da autenticação, inclusive no OPTIONS
+	assert.Equal(t, []string{"cors-default", "standard"}, chain(http.MethodGet, "/public"))
+	assert.Equal(t, []string{"cors-admin", "standard", "jwt", "roles"}, chain(http.MethodGet, "/admin"))
+	assert.Equal(t, []string{"cors-admin"}, chain(http.MethodOptions, "/admin"))
+	assert.Equal(t, []string{"cors-default"}, chain(http.MethodOptions, "/public"))
+
+	assert.Equal(t, CORSAdmin, registry.Matrix()[0].CORS)
+	assert.Equal(t, CORSDefault, registry.Matrix()[2].CORS)
+}
+
+func TestMount_InvalidDeclarations(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	invalid := []Route{
+		{Method: http.MethodGet, Path: "/a"},
+		{Method: http.MethodGet, Path: "/b", Handler: ok, RateClass: "unknown"},
+		{Method: http.MethodGet, Path: "/c", Handler: ok, Auth: "api_key"},
+		{Method: http.MethodGet, Path: "/d", Handler: ok, Roles: []string{"admin"}},
+		{Method: http.MethodGet, Path: "/e", Handler: ok, CORS: "partners"},
+		{Method: http.MethodGet, Path: "/g", Handler: ok, Anonymous: true},
+		{Method: http.MethodPost, Path: "/h", Handler: ok, Auth: AuthOptional, Anonymous: true},
+	}
+	for _, route := range invalid {
+		registry := newTestRegistry()
+		registry.Add(route)
+		assert.Error(t, registry.Mount(gin.New()), route.Path)
+	}
+
+	// Políticas CORS diferentes no mesmo caminho
+	registry := newTestRegistry().WithCORS(CORSAdmin, nil)
+	registry.Add(
+		Route{Method: http.MethodGet, Path: "/f", Handler: ok},
+		Route{Method: http.MethodPost, Path: "/f", Handler: ok, CORS: CORSAdmin},
+	)
+	assert.Error(t, registry.Mount(gin.New()))
+
+	// Rota do catálogo sem a classe de limite dos anônimos
+	registry = newTestRegistry().WithAuth(AuthOptional, nil).WithAnonymous(AnonymousCatalog)
+	registry.Add(Route{Method: http.MethodGet, Path: "/i", Handler: ok, Auth: AuthOptional, Anonymous: true})
+	assert.Error(t, registry.Mount(gin.New()))
+}
+
+func TestMount_Anonymous(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := func(c *gin.Context) {
+		c.Header("X-Anonymous", strconv.FormatBool(Anonymous(c)))
+		c.Status(http.StatusOK)
+	}
+	mount := func(mode AnonymousMode) *gin.Engine {
+		registry := newTestRegistry().
+			WithAuth(AuthOptional, marker("optional")).
+			WithRateLimit(RateAnonymous, marker("anonymous")).
+			WithAnonymous(mode)
+		registry.Add(
+			Route{Method: http.MethodGet, Path: "/catalog", Handler: handler, Auth: AuthOptional, Anonymous: true},
+			Route{Method: http.MethodGet, Path: "/search", Handler: handler, Auth: AuthOptional},
+		)
+		router := gin.New()
+		assert.NoError(t, registry.Mount(router))
+		return router
+	}
+	serve := func(router *gin.Engine, path string, token bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if token {
+			req.Header.Set("Authorization", "Bearer token")
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// full: comportamento original, sem marcação de anônimo
+	full := mount(AnonymousFull)
+	w := serve(full, "/search", false)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"standard", "optional"}, w.Header().Values("X-Chain"))
+	assert.Equal(t, "false", w.Header().Get("X-Anonymous"))
+
+	// catalog: anônimos apenas nas rotas do catálogo, com o limite próprio
+	catalog := mount(AnonymousCatalog)
+	w = serve(catalog, "/catalog", false)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"anonymous", "optional"}, w.Header().Values("X-Chain"))
+	assert.Equal(t, "true", w.Header().Get("X-Anonymous"))
+
+	w = serve(catalog, "/catalog", true)
+	assert.Equal(t, []string{"standard", "optional"}, w.Header().Values("X-Chain"))
+	assert.Equal(t, "false", w.Header().Get("X-Anonymous"))
+
+	assert.Equal(t, http.StatusUnauthorized, serve(catalog, "/search", false).Code)
+	assert.Equal(t, http.StatusOK, serve(catalog, "/search", true).Code)
+
+	// off: o token é sempre exigido
+	off := mount(AnonymousOff)
+	assert.Equal(t, http.StatusUnauthorized, serve(off, "/catalog", false).Code)
+	assert.Equal(t, http.StatusOK, serve(off, "/catalog", true).Code)
+}
+
+func TestParseAnonymousMode(t *testing.T) {
+	mode, err := ParseAnonymousMode("")
+	assert.NoError(t, err)
+	assert.Equal(t, AnonymousFull, mode)
+
+	mode, err = ParseAnonymousMode(" Catalog ")
+	assert.NoError(t, err)
+	assert.Equal(t, AnonymousCatalog, mode)
+
+	mode, err = ParseAnonymousMode("public")
+	assert.Error(t, err)
+	assert.Equal(t, AnonymousFull, mode)
+}
+
+func TestMatrix(t *testing.T) {
+	registry := newTestRegistry()
+	registry.Add(
+		Route{Method: http.MethodPost, Path: "/b", Handler: ok, Auth: AuthJWT},
+		Route{Method: http.MethodGet, Path: "/b", Handler: ok},
+		Route{Method: http.MethodGet, Path: "/a", Handler: ok, Auth: AuthJWT, Roles: []string{"admin"}, RateClass: RateStrict},
+	)
+
+	matrix := registry.Matrix()
+	assert.Len(t, matrix, 3)
+	assert.Equal(t, "/a", matrix[0].Path)
+	assert.Equal(t, []string{"admin"}, matrix[0].Roles)
+	assert.Equal(t, RateStrict, matrix[0].RateClass)
+	assert.Equal(t, "GET", matrix[1].Method)
+	assert.Equal(t, "public", matrix[1].Auth)
+	assert.Equal(t, "jwt", matrix[2].Auth)
+}
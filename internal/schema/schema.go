@@ -0,0 +1,79 @@
+// Package schema versiona os registros persistidos. Cada mudança no formato
+// de um modelo (ex.: datas tipadas, tags) é uma migração que leva o registro
+// de uma versão à seguinte; os registros antigos são atualizados na leitura e,
+// em segundo plano, pelo job de migração, sem exigir uma migração única de
+// todos os dados antes da implantação.
+package schema
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Migration leva um registro da versão From para From+1
+type Migration[T any] struct {
+	From        int
+	Description string
+	Up          func(record *T) error
+}
+
+// Pipeline aplica as migrações de um modelo, em ordem, até a versão atual. A
+// versão 0 é a dos registros gravados antes do versionamento
+type Pipeline[T any] struct {
+	version    func(record *T) *int
+	migrations []Migration[T]
+}
+
+// NewPipeline cria o pipeline de um modelo. version retorna o campo com a
+// versão do registro. As migrações devem cobrir as versões a partir de 0, sem
+// lacunas; caso contrário NewPipeline entra em pânico, já que a declaração é
+// fixa no código
+func NewPipeline[T any](version func(record *T) *int, migrations ...Migration[T]) *Pipeline[T] {
+	sorted := append([]Migration[T](nil), migrations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].From < sorted[j].From })
+	for i, migration := range sorted {
+		if migration.From != i {
+			panic(fmt.Sprintf("schema: esperada a migração da versão %d, declarada a da versão %d", i, migration.From))
+		}
+	}
+	return &Pipeline[T]{version: version, migrations: sorted}
+}
+
+// Current retorna a versão atual do modelo
+func (p *Pipeline[T]) Current() int {
+	return len(p.migrations)
+}
+
+// Outdated indica se o registro está numa versão anterior à atual
+func (p *Pipeline[T]) Outdated(record *T) bool {
+	return *p.version(record) < p.Current()
+}
+
+// Stamp marca o registro com a versão atual (registros novos ou regravados
+// pela aplicação já estão no formato atual)
+func (p *Pipeline[T]) Stamp(record *T) {
+	*p.version(record) = p.Current()
+}
+
+// Upgrade aplica ao registro as migrações pendentes, retornando se ele foi
+// alterado. Em caso de erro, o registro fica na última versão migrada.
+// Registros de versões futuras (gravados por uma versão mais nova da
+// aplicação) não são alterados
+func (p *Pipeline[T]) Upgrade(record *T) (bool, error) {
+	version := p.version(record)
+	if *version < 0 {
+		*version = 0
+	}
+	upgraded := false
+	for *version < p.Current() {
+		migration := p.migrations[*version]
+		if migration.Up != nil {
+			if err := migration.Up(record); err != nil {
+				return upgraded, fmt.Errorf("migrar da versão %d (%s): %w", migration.From, migration.Description, err)
+			}
+		}
+		*version = migration.From + 1
+		upgraded = true
+	}
+	return upgraded, nil
+}
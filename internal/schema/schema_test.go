@@ -0,0 +1,77 @@
+package schema
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type record struct {
+	Version int
+	Tags    []string
+}
+
+func newPipeline(failing error) *Pipeline[record] {
+	return NewPipeline(
+		func(r *record) *int { return &r.Version },
+		// Declaradas fora de ordem: o pipeline as ordena
+		Migration[record]{From: 1, Description: "tags", Up: func(r *record) error {
+			if failing != nil {
+				return failing
+			}
+			if r.Tags == nil {
+				r.Tags = []string{}
+			}
+			return nil
+		}},
+		Migration[record]{From: 0, Description: "versionamento"},
+	)
+}
+
+func TestPipeline_Upgrade(t *testing.T) {
+	pipeline := newPipeline(nil)
+	assert.Equal(t, 2, pipeline.Current())
+
+	old := record{}
+	assert.True(t, pipeline.Outdated(&old))
+	upgraded, err := pipeline.Upgrade(&old)
+	require.NoError(t, err)
+	assert.True(t, upgraded)
+	assert.Equal(t, 2, old.Version)
+	assert.Equal(t, []string{}, old.Tags)
+
+	// Registros atuais e de versões futuras não são alterados
+	upgraded, err = pipeline.Upgrade(&old)
+	require.NoError(t, err)
+	assert.False(t, upgraded)
+
+	future := record{Version: 3}
+	upgraded, err = pipeline.Upgrade(&future)
+	require.NoError(t, err)
+	assert.False(t, upgraded)
+	assert.Equal(t, 3, future.Version)
+
+	fresh := record{}
+	pipeline.Stamp(&fresh)
+	assert.False(t, pipeline.Outdated(&fresh))
+	assert.Nil(t, fresh.Tags)
+}
+
+func TestPipeline_UpgradeError(t *testing.T) {
+	failure := errors.New("formato desconhecido")
+	pipeline := newPipeline(failure)
+
+	r := record{}
+	upgraded, err := pipeline.Upgrade(&r)
+	assert.ErrorIs(t, err, failure)
+	assert.True(t, upgraded)
+	assert.Equal(t, 1, r.Version, "para na última versão migrada")
+}
+
+func TestNewPipeline_Gap(t *testing.T) {
+	assert.Panics(t, func() {
+		NewPipeline(func(r *record) *int { return &r.Version }, Migration[record]{From: 1})
+	})
+}
@@ -0,0 +1,98 @@
+package scim
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"callable-api/internal/models"
+)
+
+// filterPattern reconhece expressões simples no formato `atributo operador
+// valor`, como as enviadas pelos provedores de identidade para localizar um
+// usuário antes de criá-lo (ex.: userName eq "ana@example.com")
+var filterPattern = regexp.MustCompile(`^\s*([A-Za-z][A-Za-z0-9.]*)\s+(eq|ne|co|sw|ew)\s+("(?:[^"\\]|\\.)*"|true|false)\s*$`)
+
+// Filter é uma expressão de filtro SCIM sobre um atributo do usuário
+type Filter struct {
+	Attribute string
+	Operator  string
+	Value     string
+}
+
+// ParseFilter interpreta o parâmetro filter das listagens. São suportadas
+// expressões únicas com os operadores eq, ne, co, sw e ew sobre userName,
+// externalId, displayName, emails.value e active. Filtro vazio seleciona todos
+func ParseFilter(expr string) (*Filter, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, nil
+	}
+
+	match := filterPattern.FindStringSubmatch(expr)
+	if match == nil {
+		return nil, NewError(http.StatusBadRequest, "invalidFilter", "Filtro não suportado: "+expr)
+	}
+
+	filter := &Filter{Attribute: strings.ToLower(match[1]), Operator: match[2], Value: match[3]}
+	if strings.HasPrefix(filter.Value, `"`) {
+		value, err := strconv.Unquote(filter.Value)
+		if err != nil {
+			return nil, NewError(http.StatusBadRequest, "invalidFilter", "Valor inválido no filtro: "+expr)
+		}
+		filter.Value = value
+	}
+
+	switch filter.Attribute {
+	case "username", "externalid", "displayname", "emails.value", "emails":
+	case "active":
+		if filter.Operator != "eq" && filter.Operator != "ne" {
+			return nil, NewError(http.StatusBadRequest, "invalidFilter", "O atributo active aceita apenas eq e ne")
+		}
+	default:
+		return nil, NewError(http.StatusBadRequest, "invalidFilter", "Atributo não suportado no filtro: "+match[1])
+	}
+
+	return filter, nil
+}
+
+// Matches indica se o usuário atende ao filtro. Comparações de texto ignoram
+// maiúsculas e minúsculas, exceto em externalId
+func (f *Filter) Matches(u *models.User) bool {
+	if f == nil {
+		return true
+	}
+
+	var actual string
+	caseExact := false
+	switch f.Attribute {
+	case "username", "emails.value", "emails":
+		actual = u.Email
+	case "externalid":
+		actual, caseExact = u.ExternalID, true
+	case "displayname":
+		actual = u.Name
+	case "active":
+		actual = strconv.FormatBool(!u.Disabled)
+	}
+
+	expected := f.Value
+	if !caseExact {
+		actual, expected = strings.ToLower(actual), strings.ToLower(expected)
+	}
+
+	switch f.Operator {
+	case "eq":
+		return actual == expected
+	case "ne":
+		return actual != expected
+	case "co":
+		return strings.Contains(actual, expected)
+	case "sw":
+		return strings.HasPrefix(actual, expected)
+	case "ew":
+		return strings.HasSuffix(actual, expected)
+	default:
+		return false
+	}
+}
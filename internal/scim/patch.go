@@ -0,0 +1,143 @@
+package scim
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"callable-api/internal/models"
+)
+
+// PatchRequest é o corpo das requisições PATCH (RFC 7644, seção 3.5.2)
+type PatchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []PatchOperation `json:"Operations"`
+}
+
+// PatchOperation é uma operação add, replace ou remove sobre um atributo
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Apply aplica as operações ao usuário. Operações sem path trazem em value um
+// objeto com os atributos alterados (formato usado pelo Azure AD)
+func (p *PatchRequest) Apply(u *models.User) error {
+	if len(p.Operations) == 0 {
+		return NewError(http.StatusBadRequest, "invalidValue", "Nenhuma operação informada")
+	}
+
+	for _, op := range p.Operations {
+		switch strings.ToLower(op.Op) {
+		case "add", "replace":
+			if op.Path == "" {
+				var attrs map[string]json.RawMessage
+				if err := json.Unmarshal(op.Value, &attrs); err != nil {
+					return NewError(http.StatusBadRequest, "invalidValue", "Operação sem path exige um objeto em value")
+				}
+				for path, value := range attrs {
+					if err := setAttribute(u, path, value); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			if err := setAttribute(u, op.Path, op.Value); err != nil {
+				return err
+			}
+		case "remove":
+			if err := removeAttribute(u, op.Path); err != nil {
+				return err
+			}
+		default:
+			return NewError(http.StatusBadRequest, "invalidSyntax", "Operação não suportada: "+op.Op)
+		}
+	}
+	return nil
+}
+
+// setAttribute altera um atributo do usuário
+func setAttribute(u *models.User, path string, raw json.RawMessage) error {
+	switch strings.ToLower(path) {
+	case "active":
+		var active bool
+		if err := unmarshalBool(raw, &active); err != nil {
+			return err
+		}
+		u.Disabled = !active
+	case "username":
+		return unmarshalString(raw, &u.Email)
+	case "externalid":
+		return unmarshalString(raw, &u.ExternalID)
+	case "displayname", "name.formatted":
+		return unmarshalString(raw, &u.Name)
+	case "name.givenname":
+		_, family := splitName(u.Name)
+		var given string
+		if err := unmarshalString(raw, &given); err != nil {
+			return err
+		}
+		u.Name = joinName(given, family)
+	case "name.familyname":
+		given, _ := splitName(u.Name)
+		var family string
+		if err := unmarshalString(raw, &family); err != nil {
+			return err
+		}
+		u.Name = joinName(given, family)
+	case "name":
+		var name Name
+		if err := json.Unmarshal(raw, &name); err != nil {
+			return NewError(http.StatusBadRequest, "invalidValue", "Valor inválido para name")
+		}
+		resource := User{Name: &name}
+		u.Name = resource.displayName()
+	default:
+		return NewError(http.StatusBadRequest, "invalidPath", "Atributo não suportado: "+path)
+	}
+	return nil
+}
+
+// removeAttribute remove um atributo opcional do usuário
+func removeAttribute(u *models.User, path string) error {
+	switch strings.ToLower(path) {
+	case "externalid":
+		u.ExternalID = ""
+		return nil
+	case "":
+		return NewError(http.StatusBadRequest, "noTarget", "A operação remove exige path")
+	default:
+		return NewError(http.StatusBadRequest, "mutability", "Atributo obrigatório não pode ser removido: "+path)
+	}
+}
+
+// unmarshalString lê um texto não vazio
+func unmarshalString(raw json.RawMessage, dst *string) error {
+	var value string
+	if err := json.Unmarshal(raw, &value); err != nil || strings.TrimSpace(value) == "" {
+		return NewError(http.StatusBadRequest, "invalidValue", "Valor de texto inválido")
+	}
+	*dst = value
+	return nil
+}
+
+// unmarshalBool lê um booleano, aceitando também "true" e "false" como texto
+// (alguns provedores enviam booleanos entre aspas)
+func unmarshalBool(raw json.RawMessage, dst *bool) error {
+	if err := json.Unmarshal(raw, dst); err == nil {
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		switch strings.ToLower(text) {
+		case "true":
+			*dst = true
+			return nil
+		case "false":
+			*dst = false
+			return nil
+		}
+	}
+	return NewError(http.StatusBadRequest, "invalidValue", "Valor booleano inválido")
+}
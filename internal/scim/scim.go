@@ -0,0 +1,165 @@
+// Package scim implementa o subconjunto do SCIM 2.0 (RFC 7643/7644) usado pelos
+// provedores de identidade corporativos para provisionar e desprovisionar
+// usuários: criação, consulta, listagem com filtros e alterações via PATCH,
+// inclusive a desativação (active=false).
+package scim
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"callable-api/internal/models"
+)
+
+// Esquemas SCIM usados nas requisições e respostas
+const (
+	SchemaUser         = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SchemaListResponse = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SchemaPatchOp      = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SchemaError        = "urn:ietf:params:scim:api:messages:2.0:Error"
+)
+
+// ContentType é o tipo de mídia das respostas SCIM
+const ContentType = "application/scim+json"
+
+// BasePath é o prefixo das rotas SCIM
+const BasePath = "/scim/v2"
+
+// Config define o acesso dos provedores de identidade à API SCIM
+type Config struct {
+	Token string // token Bearer compartilhado com o provedor de identidade
+}
+
+// Enabled indica se a API SCIM deve ser exposta
+func (c Config) Enabled() bool {
+	return c.Token != ""
+}
+
+// Name representa o nome de um usuário SCIM
+type Name struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+// Email representa um endereço de email de um usuário SCIM
+type Email struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// Meta contém os metadados de um recurso SCIM
+type Meta struct {
+	ResourceType string    `json:"resourceType"`
+	Created      time.Time `json:"created"`
+	LastModified time.Time `json:"lastModified"`
+	Location     string    `json:"location"`
+}
+
+// User é o recurso SCIM de usuário. userName corresponde ao email do usuário
+type User struct {
+	Schemas     []string `json:"schemas"`
+	ID          string   `json:"id,omitempty"`
+	ExternalID  string   `json:"externalId,omitempty"`
+	UserName    string   `json:"userName"`
+	Name        *Name    `json:"name,omitempty"`
+	DisplayName string   `json:"displayName,omitempty"`
+	Emails      []Email  `json:"emails,omitempty"`
+	Active      *bool    `json:"active,omitempty"`
+	Password    string   `json:"password,omitempty" redact:"true"`
+	Meta        *Meta    `json:"meta,omitempty"`
+}
+
+// ListResponse é a resposta paginada das listagens SCIM
+type ListResponse struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int      `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    []User   `json:"Resources"`
+}
+
+// Error é o erro no formato SCIM. ScimType detalha a falha (ex.: uniqueness,
+// invalidFilter, invalidValue)
+type Error struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail"`
+
+	code int
+}
+
+// NewError cria um erro SCIM
+func NewError(status int, scimType, detail string) *Error {
+	return &Error{
+		Schemas:  []string{SchemaError},
+		Status:   fmt.Sprint(status),
+		ScimType: scimType,
+		Detail:   detail,
+		code:     status,
+	}
+}
+
+// Error implementa error
+func (e *Error) Error() string {
+	return e.Detail
+}
+
+// StatusCode retorna o status HTTP do erro
+func (e *Error) StatusCode() int {
+	if e.code == 0 {
+		return http.StatusInternalServerError
+	}
+	return e.code
+}
+
+// ToResource converte um usuário no recurso SCIM
+func ToResource(u *models.User) User {
+	active := !u.Disabled
+	given, family := splitName(u.Name)
+	return User{
+		Schemas:     []string{SchemaUser},
+		ID:          u.ID,
+		ExternalID:  u.ExternalID,
+		UserName:    u.Email,
+		Name:        &Name{Formatted: u.Name, GivenName: given, FamilyName: family},
+		DisplayName: u.Name,
+		Emails:      []Email{{Value: u.Email, Type: "work", Primary: true}},
+		Active:      &active,
+		Meta: &Meta{
+			ResourceType: "User",
+			Created:      u.CreatedAt,
+			LastModified: u.UpdatedAt,
+			Location:     BasePath + "/Users/" + u.ID,
+		},
+	}
+}
+
+// displayName retorna o nome a ser gravado no usuário a partir do recurso
+func (u *User) displayName() string {
+	switch {
+	case u.DisplayName != "":
+		return u.DisplayName
+	case u.Name != nil && u.Name.Formatted != "":
+		return u.Name.Formatted
+	case u.Name != nil:
+		return joinName(u.Name.GivenName, u.Name.FamilyName)
+	default:
+		return u.UserName
+	}
+}
+
+// splitName separa o nome completo em prenome e sobrenome
+func splitName(name string) (string, string) {
+	given, family, _ := strings.Cut(strings.TrimSpace(name), " ")
+	return given, strings.TrimSpace(family)
+}
+
+// joinName monta o nome completo a partir do prenome e do sobrenome
+func joinName(given, family string) string {
+	return strings.TrimSpace(given + " " + family)
+}
@@ -0,0 +1,107 @@
+package scim
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"callable-api/internal/models"
+	"callable-api/internal/repository/testsupport"
+)
+
+func TestParseFilter(t *testing.T) {
+	filter, err := ParseFilter(`userName eq "User@Example.com"`)
+	assert.NoError(t, err)
+	assert.True(t, filter.Matches(&models.User{Email: "user@example.com"}))
+	assert.False(t, filter.Matches(&models.User{Email: "admin@example.com"}))
+
+	filter, err = ParseFilter(`externalId eq "00u1"`)
+	assert.NoError(t, err)
+	assert.True(t, filter.Matches(&models.User{ExternalID: "00u1"}))
+	assert.False(t, filter.Matches(&models.User{ExternalID: "00U1"}))
+
+	filter, err = ParseFilter(`active eq false`)
+	assert.NoError(t, err)
+	assert.True(t, filter.Matches(&models.User{Disabled: true}))
+
+	filter, err = ParseFilter("")
+	assert.NoError(t, err)
+	assert.True(t, filter.Matches(&models.User{}))
+
+	for _, expr := range []string{`password eq "x"`, `userName gt "a"`, `userName eq "a" and active eq true`, `active co "t"`} {
+		_, err := ParseFilter(expr)
+		if assert.Error(t, err, expr) {
+			assert.Equal(t, "invalidFilter", err.(*Error).ScimType)
+		}
+	}
+}
+
+func TestPatchRequest_Apply(t *testing.T) {
+	user := &models.User{Email: "ana@example.com", Name: "Ana Souza"}
+
+	var patch PatchRequest
+	assert.NoError(t, json.Unmarshal([]byte(`{
+		"schemas": ["`+SchemaPatchOp+`"],
+		"Operations": [
+			{"op": "Replace", "path": "name.familyName", "value": "Lima"},
+			{"op": "replace", "value": {"active": "False", "externalId": "abc"}}
+		]
+	}`), &patch))
+
+	assert.NoError(t, patch.Apply(user))
+	assert.Equal(t, "Ana Lima", user.Name)
+	assert.True(t, user.Disabled)
+	assert.Equal(t, "abc", user.ExternalID)
+
+	patch = PatchRequest{Operations: []PatchOperation{{Op: "remove", Path: "userName"}}}
+	assert.Error(t, patch.Apply(user))
+}
+
+func TestService(t *testing.T) {
+	service := NewService(testsupport.NewUserRepository())
+
+	created, err := service.Create(&User{
+		UserName:   "ana@example.com",
+		ExternalID: "00u1",
+		Name:       &Name{GivenName: "Ana", FamilyName: "Souza"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "Ana Souza", created.DisplayName)
+	assert.True(t, *created.Active)
+	assert.Equal(t, BasePath+"/Users/"+created.ID, created.Meta.Location)
+
+	_, err = service.Create(&User{UserName: "ana@example.com"})
+	if assert.Error(t, err) {
+		assert.Equal(t, http.StatusConflict, err.(*Error).StatusCode())
+		assert.Equal(t, "uniqueness", err.(*Error).ScimType)
+	}
+	_, err = service.Create(&User{UserName: "ana"})
+	assert.Error(t, err)
+
+	list, err := service.List(`externalId eq "00u1"`, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, list.TotalResults)
+	if assert.Len(t, list.Resources, 1) {
+		assert.Equal(t, created.ID, list.Resources[0].ID)
+	}
+
+	// Usuários de exemplo + o provisionado, paginados por startIndex/count
+	list, err = service.List("", 2, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, list.TotalResults)
+	assert.Equal(t, 1, list.ItemsPerPage)
+
+	// Desprovisionamento
+	patched, err := service.Patch(created.ID, &PatchRequest{Operations: []PatchOperation{
+		{Op: "replace", Path: "active", Value: json.RawMessage("false")},
+	}})
+	assert.NoError(t, err)
+	assert.False(t, *patched.Active)
+
+	_, err = service.Get("inexistente")
+	if assert.Error(t, err) {
+		assert.Equal(t, http.StatusNotFound, err.(*Error).StatusCode())
+	}
+}
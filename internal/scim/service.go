@@ -0,0 +1,207 @@
+package scim
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"net/mail"
+	"sort"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"callable-api/internal/models"
+	"callable-api/internal/repository"
+	"callable-api/pkg/errors"
+	"callable-api/pkg/logger"
+)
+
+// Limites de paginação das listagens (parâmetro count)
+const (
+	DefaultCount = 100
+	MaxCount     = 200
+)
+
+// listAll é o limite usado para carregar todos os usuários antes de aplicar o
+// filtro; o UserRepository não oferece consultas por atributo
+const listAll = 1 << 30
+
+// Service provisiona usuários a partir dos recursos SCIM
+type Service struct {
+	users repository.UserRepository
+}
+
+// NewService cria um Service sobre o repositório de usuários
+func NewService(users repository.UserRepository) *Service {
+	return &Service{users: users}
+}
+
+// Create provisiona um usuário. Sem senha no recurso, o usuário recebe uma
+// senha aleatória e só entra na API depois de uma redefinição de senha
+func (s *Service) Create(resource *User) (*User, error) {
+	if err := validateUserName(resource.UserName); err != nil {
+		return nil, err
+	}
+
+	password := resource.Password
+	if password == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, NewError(http.StatusInternalServerError, "", "Erro ao gerar a senha do usuário")
+		}
+		password = hex.EncodeToString(secret)
+	} else if len(password) < 6 {
+		return nil, NewError(http.StatusBadRequest, "invalidValue", "A senha deve ter pelo menos 6 caracteres")
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, NewError(http.StatusInternalServerError, "", "Erro ao processar a senha do usuário")
+	}
+
+	user := &models.User{
+		Email:      resource.UserName,
+		Name:       resource.displayName(),
+		Password:   string(hashed),
+		Role:       "user",
+		ExternalID: resource.ExternalID,
+		Disabled:   resource.Active != nil && !*resource.Active,
+	}
+
+	created, err := s.users.Create(user)
+	if err != nil {
+		return nil, translate(err)
+	}
+
+	logger.Info("Usuário provisionado via SCIM", map[string]interface{}{
+		"userId":     created.ID,
+		"externalId": created.ExternalID,
+	})
+
+	result := ToResource(created)
+	return &result, nil
+}
+
+// Get retorna o recurso do usuário
+func (s *Service) Get(id string) (*User, error) {
+	user, err := s.users.FindByID(id)
+	if err != nil {
+		return nil, translate(err)
+	}
+
+	result := ToResource(user)
+	return &result, nil
+}
+
+// List retorna os usuários que atendem ao filtro, ordenados por data de
+// criação. startIndex começa em 1, como definido pelo SCIM
+func (s *Service) List(filterExpr string, startIndex, count int) (*ListResponse, error) {
+	filter, err := ParseFilter(filterExpr)
+	if err != nil {
+		return nil, err
+	}
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	if count < 0 {
+		count = 0
+	}
+	if count > MaxCount {
+		count = MaxCount
+	}
+
+	users, _, err := s.users.List(1, listAll)
+	if err != nil {
+		return nil, translate(err)
+	}
+	sort.Slice(users, func(i, j int) bool {
+		if !users[i].CreatedAt.Equal(users[j].CreatedAt) {
+			return users[i].CreatedAt.Before(users[j].CreatedAt)
+		}
+		return users[i].ID < users[j].ID
+	})
+
+	matches := make([]models.User, 0, len(users))
+	for i := range users {
+		if filter.Matches(&users[i]) {
+			matches = append(matches, users[i])
+		}
+	}
+
+	response := &ListResponse{
+		Schemas:      []string{SchemaListResponse},
+		TotalResults: len(matches),
+		StartIndex:   startIndex,
+		Resources:    []User{},
+	}
+	for i := startIndex - 1; i < len(matches) && len(response.Resources) < count; i++ {
+		response.Resources = append(response.Resources, ToResource(&matches[i]))
+	}
+	response.ItemsPerPage = len(response.Resources)
+
+	return response, nil
+}
+
+// Patch aplica as operações ao usuário. active=false desativa a conta, o que
+// bloqueia o login e os tokens já emitidos
+func (s *Service) Patch(id string, patch *PatchRequest) (*User, error) {
+	user, err := s.users.FindByID(id)
+	if err != nil {
+		return nil, translate(err)
+	}
+
+	// Alterar uma cópia, para não modificar o usuário do repositório se a
+	// atualização falhar
+	updated := *user
+	if err := patch.Apply(&updated); err != nil {
+		return nil, err
+	}
+	if err := validateUserName(updated.Email); err != nil {
+		return nil, err
+	}
+
+	saved, err := s.users.Update(&updated)
+	if err != nil {
+		return nil, translate(err)
+	}
+
+	if saved.Disabled != user.Disabled {
+		logger.Info("Estado da conta alterado via SCIM", map[string]interface{}{
+			"userId": saved.ID,
+			"active": !saved.Disabled,
+		})
+	}
+
+	result := ToResource(saved)
+	return &result, nil
+}
+
+// validateUserName exige que o userName seja um email, usado no login
+func validateUserName(userName string) error {
+	addr, err := mail.ParseAddress(userName)
+	if err != nil || addr.Address != userName {
+		return NewError(http.StatusBadRequest, "invalidValue", "userName deve ser um endereço de email")
+	}
+	return nil
+}
+
+// translate converte os erros do repositório em erros SCIM
+func translate(err error) error {
+	appErr, ok := err.(*errors.AppError)
+	if !ok || appErr.Type == "INTERNAL_SERVER" {
+		logger.Error("Erro no provisionamento SCIM", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return NewError(http.StatusInternalServerError, "", "Erro interno do servidor")
+	}
+
+	switch appErr.Type {
+	case "NOT_FOUND":
+		return NewError(http.StatusNotFound, "", "Usuário não encontrado")
+	case "CONFLICT":
+		return NewError(http.StatusConflict, "uniqueness", appErr.Message)
+	case "BAD_REQUEST":
+		return NewError(http.StatusBadRequest, "invalidValue", appErr.Message)
+	default:
+		return NewError(http.StatusInternalServerError, "", "Erro interno do servidor")
+	}
+}
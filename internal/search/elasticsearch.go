@@ -0,0 +1,156 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"callable-api/internal/models"
+	"callable-api/internal/pagination"
+	"callable-api/pkg/httpclient"
+	"callable-api/pkg/retry"
+)
+
+// defaultIndex é o nome do índice usado quando nenhum é configurado
+const defaultIndex = "items"
+
+// ElasticsearchIndexer implementa Indexer usando a API REST do
+// Elasticsearch (compatível com OpenSearch)
+type ElasticsearchIndexer struct {
+	baseURL  string
+	index    string
+	username string
+	password string
+	apiKey   string
+	client   *http.Client
+}
+
+// NewElasticsearchIndexer cria um novo indexador a partir da configuração
+func NewElasticsearchIndexer(cfg Config) *ElasticsearchIndexer {
+	index := cfg.Index
+	if index == "" {
+		index = defaultIndex
+	}
+
+	return &ElasticsearchIndexer{
+		baseURL:  strings.TrimRight(cfg.URL, "/"),
+		index:    index,
+		username: cfg.Username,
+		password: cfg.Password,
+		apiKey:   cfg.APIKey,
+		client:   httpclient.New(httpclient.Config{Timeout: 5 * time.Second, Retry: retry.DefaultPolicy()}),
+	}
+}
+
+// document é a parte do item enviada ao índice. A descrição e o email são
+// dados pessoais, gravados cifrados no repositório quando a cifragem está
+// ativa, e por isso não saem da aplicação; o dono e a organização também
+// ficam de fora
+type document struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Value     string `json:"value"`
+	CreatedAt string `json:"created_at"`
+}
+
+// IndexItem implementa Indexer
+func (e *ElasticsearchIndexer) IndexItem(ctx context.Context, item *models.Item) error {
+	path := fmt.Sprintf("/%s/_doc/%s", url.PathEscape(e.index), url.PathEscape(item.ID))
+	doc := document{ID: item.ID, Name: item.Name, Value: item.Value, CreatedAt: item.CreatedAt}
+	return e.do(ctx, http.MethodPut, path, doc, nil)
+}
+
+// Ping verifica se o cluster está acessível
+func (e *ElasticsearchIndexer) Ping(ctx context.Context) error {
+	return e.do(ctx, http.MethodGet, "/", nil, nil)
+}
+
+// searchResponse representa a parte da resposta de _search que nos interessa
+type searchResponse struct {
+	Hits struct {
+		Total struct {
+			Value int `json:"value"`
+		} `json:"total"`
+		Hits []struct {
+			Source document `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+// Search implementa Indexer com uma consulta multi_match ponderada por campo
+func (e *ElasticsearchIndexer) Search(ctx context.Context, query string, page, limit int) ([]models.Item, int, error) {
+	p := pagination.New(page, limit)
+
+	body := map[string]interface{}{
+		"from": p.Offset(),
+		"size": p.Limit,
+		"query": map[string]interface{}{
+			"multi_match": map[string]interface{}{
+				"query":     query,
+				"fields":    []string{"name^3", "value"},
+				"fuzziness": "AUTO",
+			},
+		},
+	}
+
+	var result searchResponse
+	if err := e.do(ctx, http.MethodPost, "/"+url.PathEscape(e.index)+"/_search", body, &result); err != nil {
+		return nil, 0, err
+	}
+
+	items := make([]models.Item, 0, len(result.Hits.Hits))
+	for _, hit := range result.Hits.Hits {
+		items = append(items, models.Item{ID: hit.Source.ID, Name: hit.Source.Name, Value: hit.Source.Value, CreatedAt: hit.Source.CreatedAt})
+	}
+
+	return items, result.Hits.Total.Value, nil
+}
+
+// do executa uma requisição JSON contra o cluster
+func (e *ElasticsearchIndexer) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var payload io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("search: falha ao serializar requisição: %w", err)
+		}
+		payload = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, e.baseURL+path, payload)
+	if err != nil {
+		return fmt.Errorf("search: falha ao criar requisição: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	switch {
+	case e.apiKey != "":
+		req.Header.Set("Authorization", "ApiKey "+e.apiKey)
+	case e.username != "":
+		req.SetBasicAuth(e.username, e.password)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("search: falha na comunicação com o cluster: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("search: cluster respondeu com status %d: %s", resp.StatusCode, detail)
+	}
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("search: resposta inválida do cluster: %w", err)
+		}
+	}
+	return nil
+}
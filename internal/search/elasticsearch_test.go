@@ -0,0 +1,69 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"callable-api/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestElasticsearchIndexer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "ApiKey secret", r.Header.Get("Authorization"))
+
+		switch {
+		case r.Method == http.MethodPut && r.URL.Path == "/items-test/_doc/42":
+			var doc map[string]interface{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&doc))
+			assert.Equal(t, "Item 42", doc["name"])
+			// Os dados pessoais e o dono não são enviados ao índice
+			for _, field := range []string{"description", "email", "owner_id", "org_id"} {
+				assert.NotContains(t, doc, field)
+			}
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodPost && r.URL.Path == "/items-test/_search":
+			var body map[string]interface{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, float64(10), body["from"])
+			assert.Equal(t, float64(10), body["size"])
+			w.Write([]byte(`{"hits":{"total":{"value":11},"hits":[{"_source":{"id":"42","name":"Item 42"}}]}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	indexer := NewElasticsearchIndexer(Config{URL: server.URL + "/", Index: "items-test", APIKey: "secret"})
+
+	err := indexer.IndexItem(context.Background(), &models.Item{
+		ID:          "42",
+		Name:        "Item 42",
+		Description: "descrição privada",
+		Email:       "dono@example.com",
+		OwnerID:     "user-1",
+		OrgID:       "org-1",
+	})
+	assert.NoError(t, err)
+
+	items, total, err := indexer.Search(context.Background(), "item", 2, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 11, total)
+	assert.Len(t, items, 1)
+	assert.Equal(t, "42", items[0].ID)
+}
+
+func TestElasticsearchIndexer_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "cluster indisponível", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	indexer := NewElasticsearchIndexer(Config{URL: server.URL})
+	_, _, err := indexer.Search(context.Background(), "item", 1, 10)
+	assert.Error(t, err)
+}
@@ -0,0 +1,33 @@
+// Package search espelha os itens em um mecanismo de busca externo
+// (Elasticsearch/OpenSearch) para consultas de texto completo por relevância
+package search
+
+import (
+	"context"
+
+	"callable-api/internal/models"
+)
+
+// Indexer define as operações de um mecanismo de busca de itens
+type Indexer interface {
+	// IndexItem cria ou atualiza o documento do item no índice
+	IndexItem(ctx context.Context, item *models.Item) error
+
+	// Search executa uma busca de texto completo, ordenada por relevância. Os
+	// itens retornados trazem apenas os campos indexados
+	Search(ctx context.Context, query string, page, limit int) ([]models.Item, int, error)
+}
+
+// Config agrupa as configurações de conexão com o Elasticsearch/OpenSearch
+type Config struct {
+	URL      string // vazio desativa a integração
+	Index    string
+	Username string
+	Password string
+	APIKey   string
+}
+
+// Enabled retorna true se a integração com o mecanismo de busca foi configurada
+func (c Config) Enabled() bool {
+	return c.URL != ""
+}
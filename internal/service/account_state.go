@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"callable-api/internal/models"
+	"callable-api/internal/repository"
+	"callable-api/pkg/errors"
+	"callable-api/pkg/logger"
+)
+
+// passwordResetTemplate é o template de pkg/mail usado no email de redefinição
+const passwordResetTemplate = "password_reset"
+
+// TemplateMailer envia emails a partir dos templates de pkg/mail
+type TemplateMailer interface {
+	SendTemplate(ctx context.Context, name string, to []string, data interface{}) error
+}
+
+// PasswordResetConfig define o link enviado por email e a validade do token
+type PasswordResetConfig struct {
+	URL string        // o token é concatenado ao final da URL
+	TTL time.Duration // validade do token
+}
+
+// DefaultPasswordResetConfig retorna a configuração padrão (tokens válidos por 24 horas)
+func DefaultPasswordResetConfig() PasswordResetConfig {
+	return PasswordResetConfig{
+		URL: "http://localhost:8080/reset-password?token=",
+		TTL: 24 * time.Hour,
+	}
+}
+
+// WithPasswordReset habilita o envio do link de redefinição de senha por email
+func (s *AuthService) WithPasswordReset(mailer TemplateMailer, cfg PasswordResetConfig) *AuthService {
+	s.mailer = mailer
+	s.passwordReset = cfg
+	return s
+}
+
+// CheckAccount verifica se a conta do usuário ainda pode acessar a API. É
+// consultado a cada requisição autenticada, para que a desativação e a
+// redefinição forçada de senha valham também para tokens já emitidos
+func (s *AuthService) CheckAccount(userID string) error {
+	user, err := s.repo.FindByID(userID)
+	if err != nil {
+		return errors.NewUnauthorizedError("Usuário não encontrado", err)
+	}
+	return repository.CheckAccountState(user)
+}
+
+// Preferences retorna as preferências salvas no perfil do usuário (ver
+// middleware.PreferencesMiddleware)
+func (s *AuthService) Preferences(userID string) (*models.UserPreferences, error) {
+	user, err := s.repo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return user.Preferences(), nil
+}
+
+// DisableUser desativa a conta do usuário, bloqueando login e tokens emitidos
+func (s *AuthService) DisableUser(actorID, userID, reason string) (*models.UserResponse, error) {
+	if actorID == userID {
+		return nil, errors.NewBadRequestError("Não é possível desativar a própria conta", nil)
+	}
+
+	updated, err := s.updateAccount(userID, func(user *models.User) {
+		user.Disabled = true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Warn("Conta de usuário desativada", map[string]interface{}{
+		"userId":  userID,
+		"adminId": actorID,
+		"reason":  reason,
+	})
+	return updated, nil
+}
+
+// EnableUser reativa uma conta desativada
+func (s *AuthService) EnableUser(actorID, userID string) (*models.UserResponse, error) {
+	updated, err := s.updateAccount(userID, func(user *models.User) {
+		user.Disabled = false
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Conta de usuário reativada", map[string]interface{}{
+		"userId":  userID,
+		"adminId": actorID,
+	})
+	return updated, nil
+}
+
+// ForcePasswordReset bloqueia a conta até que o usuário escolha uma nova senha
+// pelo link enviado por email
+func (s *AuthService) ForcePasswordReset(ctx context.Context, actorID, userID string) (*models.UserResponse, error) {
+	updated, user, token, err := s.requirePasswordReset(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Warn("Redefinição de senha forçada", map[string]interface{}{
+		"userId":  userID,
+		"adminId": actorID,
+	})
+
+	if _, err := s.mailResetLink(ctx, passwordResetTemplate, user, token); err != nil {
+		return nil, errors.NewInternalServerError("Conta bloqueada, mas o email de redefinição não foi enviado", err)
+	}
+	return updated, nil
+}
+
+// requirePasswordReset bloqueia a conta até a redefinição de senha, gravando o
+// hash de um novo token. Retorna a conta atualizada e o token, que só existe
+// em memória até ser enviado por email
+func (s *AuthService) requirePasswordReset(userID string) (*models.UserResponse, *models.User, string, error) {
+	token, tokenHash, err := newResetToken(userID)
+	if err != nil {
+		return nil, nil, "", errors.NewInternalServerError("Erro ao gerar token de redefinição", err)
+	}
+
+	ttl := s.passwordReset.TTL
+	var user models.User
+	updated, err := s.updateAccount(userID, func(u *models.User) {
+		u.PasswordResetRequired = true
+		u.PasswordResetTokenHash = tokenHash
+		u.PasswordResetExpiresAt = s.clock.Now().Add(ttl)
+		user = *u
+	})
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return updated, &user, token, nil
+}
+
+// mailResetLink envia ao usuário o link de redefinição de senha com o
+// template informado. Retorna false, sem erro, se o envio de emails não
+// estiver configurado
+func (s *AuthService) mailResetLink(ctx context.Context, template string, user *models.User, token string) (bool, error) {
+	if s.mailer == nil {
+		logger.Warn("Envio de emails não configurado; o link de redefinição não foi enviado", map[string]interface{}{
+			"userId": user.ID,
+		})
+		return false, nil
+	}
+
+	err := s.mailer.SendTemplate(ctx, template, []string{user.Email}, map[string]interface{}{
+		"Name":      user.Name,
+		"ResetURL":  s.passwordReset.URL + token,
+		"ExpiresIn": formatTTL(s.passwordReset.TTL),
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ResetPassword define a nova senha usando o token enviado por email
+func (s *AuthService) ResetPassword(input *models.ResetPasswordInput) error {
+	invalid := errors.NewBadRequestError("Token de redefinição inválido ou expirado", nil)
+
+	userID, _, ok := strings.Cut(input.Token, ".")
+	if !ok {
+		return invalid
+	}
+
+	user, err := s.repo.FindByID(userID)
+	if err != nil {
+		return invalid
+	}
+
+	expected := []byte(user.PasswordResetTokenHash)
+	if !user.PasswordResetRequired || len(expected) == 0 || s.clock.Now().After(user.PasswordResetExpiresAt) ||
+		subtle.ConstantTimeCompare(expected, []byte(hashToken(input.Token))) != 1 {
+		return invalid
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return errors.NewInternalServerError("Erro ao processar senha", err)
+	}
+
+	updated := *user
+	updated.Password = string(hashedPassword)
+	updated.PasswordResetRequired = false
+	updated.PasswordResetTokenHash = ""
+	updated.PasswordResetExpiresAt = time.Time{}
+	if _, err := s.repo.Update(&updated); err != nil {
+		return errors.NewInternalServerError("Erro ao redefinir senha", err)
+	}
+
+	logger.Info("Senha redefinida", map[string]interface{}{
+		"userId": userID,
+	})
+	return nil
+}
+
+// updateAccount aplica change a uma cópia do usuário e a persiste
+func (s *AuthService) updateAccount(userID string, change func(user *models.User)) (*models.UserResponse, error) {
+	user, err := s.repo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := *user
+	change(&updated)
+
+	updatedUser, err := s.repo.Update(&updated)
+	if err != nil {
+		return nil, errors.NewInternalServerError("Erro ao atualizar conta", err)
+	}
+
+	response := updatedUser.ToUserResponse()
+	return &response, nil
+}
+
+// newResetToken gera um token "<userID>.<aleatório>" e o hash que fica gravado
+func newResetToken(userID string) (string, string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+	token := userID + "." + hex.EncodeToString(secret)
+	return token, hashToken(token), nil
+}
+
+// hashToken calcula o hash gravado no lugar do token; os tokens em si nunca são gravados
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// formatTTL descreve a validade do token para o email
+func formatTTL(ttl time.Duration) string {
+	switch {
+	case ttl == time.Hour:
+		return "1 hora"
+	case ttl > time.Hour && ttl%time.Hour == 0:
+		return fmt.Sprintf("%d horas", int(ttl/time.Hour))
+	case ttl == time.Minute:
+		return "1 minuto"
+	default:
+		return fmt.Sprintf("%d minutos", int(ttl/time.Minute))
+	}
+}
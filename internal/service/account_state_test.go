@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"callable-api/internal/clock"
+	"callable-api/internal/models"
+	"callable-api/internal/repository"
+	"callable-api/internal/repository/testsupport"
+	"callable-api/pkg/errors"
+)
+
+// fakeMailer guarda os emails enviados por template
+type fakeMailer struct {
+	template string
+	to       []string
+	data     map[string]interface{}
+}
+
+func (m *fakeMailer) SendTemplate(ctx context.Context, name string, to []string, data interface{}) error {
+	m.template = name
+	m.to = to
+	m.data = data.(map[string]interface{})
+	return nil
+}
+
+// seededUser retorna o usuário de exemplo do repositório em memória
+func seededUser(t *testing.T, repo repository.UserRepository, email string) *models.User {
+	user, err := repo.FindByEmail(email)
+	assert.NoError(t, err)
+	return user
+}
+
+func TestDisableUser(t *testing.T) {
+	repo := testsupport.NewUserRepository()
+	authService := NewAuthService(repo, getTestConfig())
+	admin := seededUser(t, repo, "admin@example.com")
+	user := seededUser(t, repo, "user@example.com")
+
+	// Um administrador não pode desativar a própria conta
+	_, err := authService.DisableUser(admin.ID, admin.ID, "")
+	assert.Error(t, err)
+
+	response, err := authService.DisableUser(admin.ID, user.ID, "credenciais vazadas")
+	assert.NoError(t, err)
+	assert.True(t, response.Disabled)
+
+	// Login, renovação e tokens já emitidos são bloqueados
+	_, _, err = authService.Login(&models.LoginInput{Email: "user@example.com", Password: "user123"}, models.ClientInfo{})
+	appErr, ok := err.(*errors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, "FORBIDDEN", appErr.Type)
+	assert.ErrorIs(t, appErr.Err, repository.ErrAccountDisabled)
+	assert.Error(t, authService.CheckAccount(user.ID))
+
+	_, err = authService.EnableUser(admin.ID, user.ID)
+	assert.NoError(t, err)
+	assert.NoError(t, authService.CheckAccount(user.ID))
+	_, _, err = authService.Login(&models.LoginInput{Email: "user@example.com", Password: "user123"}, models.ClientInfo{})
+	assert.NoError(t, err)
+}
+
+func TestForcePasswordReset(t *testing.T) {
+	repo := testsupport.NewUserRepository()
+	mailer := &fakeMailer{}
+	authService := NewAuthService(repo, getTestConfig()).
+		WithPasswordReset(mailer, PasswordResetConfig{URL: "https://app.example.com/reset?token=", TTL: time.Hour})
+	admin := seededUser(t, repo, "admin@example.com")
+	user := seededUser(t, repo, "user@example.com")
+
+	response, err := authService.ForcePasswordReset(context.Background(), admin.ID, user.ID)
+	assert.NoError(t, err)
+	assert.True(t, response.PasswordResetRequired)
+	assert.Error(t, authService.CheckAccount(user.ID))
+
+	// O link de redefinição é enviado ao usuário
+	assert.Equal(t, "password_reset", mailer.template)
+	assert.Equal(t, []string{"user@example.com"}, mailer.to)
+	assert.Equal(t, "1 hora", mailer.data["ExpiresIn"])
+	resetURL := mailer.data["ResetURL"].(string)
+	token := resetURL[len("https://app.example.com/reset?token="):]
+
+	// A senha antiga não permite mais o login
+	_, _, err = authService.Login(&models.LoginInput{Email: "user@example.com", Password: "user123"}, models.ClientInfo{})
+	assert.ErrorIs(t, err.(*errors.AppError).Err, repository.ErrPasswordResetRequired)
+
+	// Tokens adulterados são rejeitados
+	assert.Error(t, authService.ResetPassword(&models.ResetPasswordInput{Token: token + "0", NewPassword: "nova-senha"}))
+	assert.Error(t, authService.ResetPassword(&models.ResetPasswordInput{Token: "sem-ponto", NewPassword: "nova-senha"}))
+
+	assert.NoError(t, authService.ResetPassword(&models.ResetPasswordInput{Token: token, NewPassword: "nova-senha"}))
+	assert.NoError(t, authService.CheckAccount(user.ID))
+	_, _, err = authService.Login(&models.LoginInput{Email: "user@example.com", Password: "nova-senha"}, models.ClientInfo{})
+	assert.NoError(t, err)
+
+	// O token só pode ser usado uma vez
+	assert.Error(t, authService.ResetPassword(&models.ResetPasswordInput{Token: token, NewPassword: "outra-senha"}))
+}
+
+func TestPasswordResetExpiry(t *testing.T) {
+	repo := testsupport.NewUserRepository()
+	mailer := &fakeMailer{}
+	clk := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	authService := NewAuthService(repo, getTestConfig()).
+		WithPasswordReset(mailer, PasswordResetConfig{URL: "https://app.example.com/reset?token=", TTL: time.Hour}).
+		WithClock(clk)
+	admin := seededUser(t, repo, "admin@example.com")
+	user := seededUser(t, repo, "user@example.com")
+
+	_, err := authService.ForcePasswordReset(context.Background(), admin.ID, user.ID)
+	assert.NoError(t, err)
+	token := mailer.data["ResetURL"].(string)[len("https://app.example.com/reset?token="):]
+
+	// Depois do prazo, o token não vale mais
+	clk.Advance(time.Hour + time.Second)
+	assert.Error(t, authService.ResetPassword(&models.ResetPasswordInput{Token: token, NewPassword: "nova-senha"}))
+}
+
+func TestImportUsers_Invitation(t *testing.T) {
+	repo := testsupport.NewUserRepository()
+	mailer := &fakeMailer{}
+	authService := NewAuthService(repo, getTestConfig()).
+		WithPasswordReset(mailer, PasswordResetConfig{URL: "https://app.example.com/reset?token=", TTL: time.Hour})
+
+	report, err := authService.ImportUsers(context.Background(), "1", []models.UserImportRow{
+		{Line: 2, Email: " ana@example.com ", Name: "Ana"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.Created)
+	assert.True(t, report.Rows[0].Invited)
+
+	// O convite leva ao link de definição da senha
+	assert.Equal(t, "user_invitation", mailer.template)
+	assert.Equal(t, []string{"ana@example.com"}, mailer.to)
+	assert.Contains(t, mailer.data["ResetURL"], "https://app.example.com/reset?token="+report.Rows[0].UserID+".")
+
+	user := seededUser(t, repo, "ana@example.com")
+	assert.Equal(t, "user", user.Role)
+	assert.True(t, user.PasswordResetRequired)
+}
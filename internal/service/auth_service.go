@@ -1,39 +1,103 @@
 package service
 
 import (
+	"callable-api/internal/avatar"
+	"callable-api/internal/clock"
+	"callable-api/internal/events"
+	"callable-api/internal/jwtkey"
+	"callable-api/internal/messages"
 	"callable-api/internal/models"
 	"callable-api/internal/repository"
-	"callable-api/pkg/auth"
+	"callable-api/internal/validation"
 	"callable-api/pkg/config"
 	"callable-api/pkg/errors"
 	"callable-api/pkg/logger"
-	"time"
+	"context"
+	"io"
+	"strconv"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
+// minPasswordLength é o tamanho mínimo das senhas (o mesmo da tag min de RegisterUserInput)
+const minPasswordLength = 6
+
 // AuthService gerencia autenticação e usuários
 type AuthService struct {
-	repo repository.UserRepository
-	cfg  *config.Config
+	repo          repository.UserRepository
+	cfg           *config.Config
+	keys          *jwtkey.Keys
+	avatars       *avatar.Service
+	mailer        TemplateMailer
+	passwordReset PasswordResetConfig
+	loginHistory  repository.LoginHistoryRepository
+
+	deviceBindings repository.DeviceBindingRepository
+	deviceBinding  DeviceBindingConfig
+
+	sessions      repository.SessionRepository
+	sessionConfig SessionConfig
+
+	invitations  InvitationRedeemer
+	registration RegistrationMode
+
+	consents      repository.ConsentRepository
+	consentConfig ConsentConfig
+
+	scopedTokens      repository.ScopedTokenRepository
+	scopedTokenConfig ScopedTokenConfig
+
+	bootstrap bootstrapState
+
+	events events.Publisher
+	clock  clock.Clock
 }
 
 // NewAuthService cria uma nova instância do AuthService
 func NewAuthService(repo repository.UserRepository, cfg *config.Config) *AuthService {
 	return &AuthService{
-		repo: repo,
-		cfg:  cfg,
+		repo:          repo,
+		cfg:           cfg,
+		keys:          jwtkey.New(cfg),
+		passwordReset: DefaultPasswordResetConfig(),
+		clock:         clock.System(),
 	}
 }
 
+// WithKeys define o segredo vigente dos JWTs, trocado na rotação (padrão: o
+// da configuração)
+func (s *AuthService) WithKeys(keys *jwtkey.Keys) *AuthService {
+	s.keys = keys
+	return s
+}
+
+// WithClock define o relógio usado nas expirações de tokens, sessões e
+// vínculos de dispositivo (por padrão, o do sistema)
+func (s *AuthService) WithClock(c clock.Clock) *AuthService {
+	s.clock = c
+	return s
+}
+
+// WithAvatars habilita o upload de avatares de perfil
+func (s *AuthService) WithAvatars(avatars *avatar.Service) *AuthService {
+	s.avatars = avatars
+	return s
+}
+
+// WithEvents publica os eventos de domínio das contas (ex.: user.registered)
+func (s *AuthService) WithEvents(publisher events.Publisher) *AuthService {
+	s.events = publisher
+	return s
+}
+
 // Register registra um novo usuário
 func (s *AuthService) Register(input *models.RegisterUserInput) (*models.UserResponse, error) {
 	// Validação adicional pode ser feita aqui
 	validationErr := errors.NewValidationError("Dados de entrada inválidos")
 	validInputs := true
 
-	if len(input.Password) < 6 {
-		validationErr.AddFieldError("password", "Senha deve ter pelo menos 6 caracteres")
+	if len(input.Password) < minPasswordLength {
+		validationErr.AddFieldError("password", messages.Text(messages.DefaultLocale, "min", "password", strconv.Itoa(minPasswordLength)))
 		validInputs = false
 	}
 
@@ -41,8 +105,21 @@ func (s *AuthService) Register(input *models.RegisterUserInput) (*models.UserRes
 		return nil, validationErr
 	}
 
+	// Aceite das versões vigentes dos termos, se o registro de aceites está configurado
+	consents, err := s.registrationConsents(input)
+	if err != nil {
+		return nil, err
+	}
+
+	// Código de convite, obrigatório se o cadastro aberto está desativado
+	ctx := context.Background()
+	invitation, role, err := s.registrationInvitation(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
 	// Verificar se o email já está em uso
-	_, err := s.repo.FindByEmail(input.Email)
+	_, err = s.repo.FindByEmail(input.Email)
 	if err == nil {
 		return nil, errors.NewConflictError("Email já está em uso", nil)
 	}
@@ -63,7 +140,7 @@ func (s *AuthService) Register(input *models.RegisterUserInput) (*models.UserRes
 		Email:    input.Email,
 		Name:     input.Name,
 		Password: string(hashedPassword),
-		Role:     "user", // Papel padrão
+		Role:     role, // "user", exceto se o convite define outro papel
 	}
 
 	createdUser, err := s.repo.Create(user)
@@ -71,32 +148,54 @@ func (s *AuthService) Register(input *models.RegisterUserInput) (*models.UserRes
 		return nil, errors.NewInternalServerError("Erro ao criar usuário", err)
 	}
 
+	if invitation != nil {
+		if err := s.redeemRegistration(ctx, invitation, createdUser); err != nil {
+			return nil, err
+		}
+	}
+
+	// Sem o aceite gravado, o usuário será solicitado a aceitar no primeiro acesso
+	if len(consents) > 0 {
+		if err := s.recordConsents(ctx, createdUser.ID, consents, models.ConsentSourceRegistration, models.ClientInfo{}); err != nil {
+			logger.Error("Falha ao registrar o aceite dos termos no registro", map[string]interface{}{
+				"userId": createdUser.ID,
+				"error":  err.Error(),
+			})
+		}
+	}
+
 	logger.Info("Usuário registrado com sucesso", map[string]interface{}{
 		"userId": createdUser.ID,
 		"email":  createdUser.Email,
 	})
 
-	return &models.UserResponse{
-		ID:        createdUser.ID,
-		Email:     createdUser.Email,
-		Name:      createdUser.Name,
-		Role:      createdUser.Role,
-		CreatedAt: createdUser.CreatedAt,
-	}, nil
+	if s.events != nil {
+		s.events.Publish(ctx, events.UserRegistered{
+			UserID: createdUser.ID,
+			Email:  createdUser.Email,
+			Name:   createdUser.Name,
+		})
+	}
+
+	response := createdUser.ToUserResponse()
+	return &response, nil
 }
 
-// Login autentica um usuário e retorna tokens JWT
-func (s *AuthService) Login(input *models.LoginInput) (*models.TokenPair, *models.UserResponse, error) {
+// Login autentica um usuário e retorna tokens JWT com a duração de sessão
+// solicitada. A tentativa, bem-sucedida ou não, é registrada no histórico de
+// login com os dados do cliente
+func (s *AuthService) Login(input *models.LoginInput, client models.ClientInfo) (*models.TokenPair, *models.UserResponse, error) {
 	// Autenticar usuário
 	user, err := s.repo.Authenticate(input.Email, input.Password)
+	s.recordLogin(input.Email, client, err)
 	if err != nil {
 		return nil, nil, err // O repositório já retorna o erro adequado
 	}
 
-	// Gerar tokens
-	tokenPair, err := auth.GenerateTokenPair(user, s.cfg)
+	// Gerar tokens com a duração de sessão solicitada
+	tokenPair, err := s.issueTokens(user, input.Session, client)
 	if err != nil {
-		return nil, nil, errors.NewInternalServerError("Erro ao gerar tokens", err)
+		return nil, nil, err
 	}
 
 	logger.Info("Login de usuário bem-sucedido", map[string]interface{}{
@@ -104,19 +203,15 @@ func (s *AuthService) Login(input *models.LoginInput) (*models.TokenPair, *model
 		"email":  user.Email,
 	})
 
-	return tokenPair, &models.UserResponse{
-		ID:        user.ID,
-		Email:     user.Email,
-		Name:      user.Name,
-		Role:      user.Role,
-		CreatedAt: user.CreatedAt,
-	}, nil
+	response := user.ToUserResponse()
+	return tokenPair, &response, nil
 }
 
-// RefreshToken atualiza os tokens JWT usando um token de atualização
-func (s *AuthService) RefreshToken(refreshToken string) (*models.TokenPair, error) {
+// RefreshToken atualiza os tokens JWT usando um token de atualização. Com o
+// vínculo de dispositivo ativo, apenas o dispositivo que recebeu o token pode usá-lo
+func (s *AuthService) RefreshToken(refreshToken string, client models.ClientInfo) (*models.TokenPair, error) {
 	// Validar o token de atualização
-	claims, err := auth.ValidateToken(refreshToken, true, s.cfg)
+	claims, err := s.keys.ValidateToken(refreshToken, true)
 	if err != nil {
 		return nil, errors.NewUnauthorizedError("Token de atualização inválido", err)
 	}
@@ -127,10 +222,19 @@ func (s *AuthService) RefreshToken(refreshToken string) (*models.TokenPair, erro
 		return nil, errors.NewUnauthorizedError("Usuário não encontrado", err)
 	}
 
-	// Gerar novos tokens
-	tokenPair, err := auth.GenerateTokenPair(user, s.cfg)
+	// Contas bloqueadas não renovam tokens
+	if err := repository.CheckAccountState(user); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkDeviceBinding(user.ID, refreshToken, client); err != nil {
+		return nil, err
+	}
+
+	// Gerar novos tokens, mantendo a duração da sessão
+	tokenPair, err := s.issueTokens(user, s.takeSessionKind(user.ID, refreshToken), client)
 	if err != nil {
-		return nil, errors.NewInternalServerError("Erro ao gerar tokens", err)
+		return nil, err
 	}
 
 	logger.Info("Tokens atualizados com sucesso", map[string]interface{}{
@@ -148,13 +252,8 @@ func (s *AuthService) GetUserProfile(userID string) (*models.UserResponse, error
 		return nil, err // O repositório já retorna o erro adequado
 	}
 
-	return &models.UserResponse{
-		ID:        user.ID,
-		Email:     user.Email,
-		Name:      user.Name,
-		Role:      user.Role,
-		CreatedAt: user.CreatedAt,
-	}, nil
+	response := user.ToUserResponse()
+	return &response, nil
 }
 
 // UpdateUserProfile atualiza o perfil do usuário
@@ -167,7 +266,7 @@ func (s *AuthService) UpdateUserProfile(userID string, name string) (*models.Use
 
 	// Atualizar campos
 	user.Name = name
-	user.UpdatedAt = time.Now()
+	user.UpdatedAt = s.clock.Now()
 
 	// Salvar usuário
 	updatedUser, err := s.repo.Update(user)
@@ -175,11 +274,64 @@ func (s *AuthService) UpdateUserProfile(userID string, name string) (*models.Use
 		return nil, errors.NewInternalServerError("Erro ao atualizar perfil", err)
 	}
 
-	return &models.UserResponse{
-		ID:        updatedUser.ID,
-		Email:     updatedUser.Email,
-		Name:      updatedUser.Name,
-		Role:      updatedUser.Role,
-		CreatedAt: updatedUser.CreatedAt,
-	}, nil
-}
\ No newline at end of file
+	response := updatedUser.ToUserResponse()
+	return &response, nil
+}
+
+// PatchUserProfile atualiza apenas os campos do perfil informados em input
+func (s *AuthService) PatchUserProfile(userID string, input *models.UpdateProfileInput) (*models.UserResponse, error) {
+	if input.IsEmpty() {
+		return nil, errors.NewBadRequestError("Nenhum campo do perfil informado", nil)
+	}
+	if err := validation.Validate(input); err != nil {
+		return nil, newValidationError(messages.DefaultLocale, err)
+	}
+
+	user, err := s.repo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := *user
+	input.ApplyTo(&updated)
+	updated.UpdatedAt = s.clock.Now()
+
+	updatedUser, err := s.repo.Update(&updated)
+	if err != nil {
+		return nil, errors.NewInternalServerError("Erro ao atualizar perfil", err)
+	}
+
+	response := updatedUser.ToUserResponse()
+	return &response, nil
+}
+
+// UpdateAvatar redimensiona e grava a imagem enviada como avatar do usuário
+func (s *AuthService) UpdateAvatar(ctx context.Context, userID string, image io.Reader) (*models.UserResponse, error) {
+	if s.avatars == nil {
+		return nil, errors.NewInternalServerError("Upload de avatar não configurado", nil)
+	}
+
+	user, err := s.repo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	avatarURL, err := s.avatars.Upload(ctx, userID, image)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := *user
+	updated.AvatarURL = avatarURL
+	updatedUser, err := s.repo.Update(&updated)
+	if err != nil {
+		return nil, errors.NewInternalServerError("Erro ao atualizar avatar", err)
+	}
+
+	logger.Info("Avatar de usuário atualizado", map[string]interface{}{
+		"userId": userID,
+	})
+
+	response := updatedUser.ToUserResponse()
+	return &response, nil
+}
@@ -2,10 +2,16 @@
 package service
 
 import (
+	"bytes"
+	"callable-api/internal/avatar"
 	"callable-api/internal/models"
 	"callable-api/pkg/auth"
 	"callable-api/pkg/config"
 	"callable-api/pkg/errors"
+	"context"
+	"image"
+	"image/png"
+	"strings"
 	"testing"
 	"time"
 
@@ -261,7 +267,7 @@ func TestLogin_Success(t *testing.T) {
 	}
 
 	// Chamar método
-	tokenPair, userResponse, err := authService.Login(input)
+	tokenPair, userResponse, err := authService.Login(input, models.ClientInfo{})
 
 	// Verificações
 	assert.NoError(t, err)
@@ -293,7 +299,7 @@ func TestLogin_InvalidCredentials(t *testing.T) {
 	}
 
 	// Chamar método
-	tokenPair, userResponse, err := authService.Login(input)
+	tokenPair, userResponse, err := authService.Login(input, models.ClientInfo{})
 
 	// Verificações
 	assert.Error(t, err)
@@ -394,6 +400,82 @@ func TestUpdateUserProfile_Success(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestUpdateAvatar_Success(t *testing.T) {
+	// Configurar mock
+	mockRepo := new(MockUserRepository)
+	user := createTestUser()
+	mockRepo.On("FindByID", "user123").Return(user, nil)
+	// O mock devolve o usuário recebido em Update
+	updatedUser := &models.User{}
+	mockRepo.On("Update", mock.AnythingOfType("*models.User")).
+		Run(func(args mock.Arguments) { *updatedUser = *args.Get(0).(*models.User) }).
+		Return(updatedUser, nil)
+
+	// Imagem PNG de teste
+	var img bytes.Buffer
+	assert.NoError(t, png.Encode(&img, image.NewRGBA(image.Rect(0, 0, 300, 200))))
+
+	store := avatar.NewMemoryStore()
+	authService := NewAuthService(mockRepo, getTestConfig()).
+		WithAvatars(avatar.NewService(store, avatar.DefaultConfig()))
+
+	userResponse, err := authService.UpdateAvatar(context.Background(), "user123", &img)
+
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(userResponse.AvatarURL, avatar.URLPrefix+"user123-"))
+
+	object, err := store.Get(context.Background(), strings.TrimPrefix(userResponse.AvatarURL, avatar.URLPrefix))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, object.Data)
+
+	// Uma imagem inválida não altera o usuário
+	_, err = authService.UpdateAvatar(context.Background(), "user123", strings.NewReader("not an image"))
+	assert.Error(t, err)
+	mockRepo.AssertNumberOfCalls(t, "Update", 1)
+}
+
+func TestPatchUserProfile(t *testing.T) {
+	str := func(s string) *string { return &s }
+
+	mockRepo := new(MockUserRepository)
+	user := createTestUser()
+	user.Bio = "Bio antiga"
+	user.Phone = "+5511999998888"
+	mockRepo.On("FindByID", "user123").Return(user, nil)
+
+	updatedUser := &models.User{}
+	mockRepo.On("Update", mock.AnythingOfType("*models.User")).
+		Run(func(args mock.Arguments) { *updatedUser = *args.Get(0).(*models.User) }).
+		Return(updatedUser, nil)
+
+	authService := NewAuthService(mockRepo, getTestConfig())
+
+	// Apenas os campos informados mudam; texto vazio remove o valor
+	userResponse, err := authService.PatchUserProfile("user123", &models.UpdateProfileInput{
+		Bio:      str(""),
+		Locale:   str("pt-BR"),
+		Timezone: str("America/Sao_Paulo"),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, user.Name, userResponse.Name)
+	assert.Equal(t, "+5511999998888", userResponse.Phone)
+	assert.Empty(t, userResponse.Bio)
+	assert.Equal(t, "pt-BR", userResponse.Locale)
+	assert.Equal(t, "America/Sao_Paulo", userResponse.Timezone)
+
+	// Campos inválidos são rejeitados por campo, sem alterar o usuário
+	_, err = authService.PatchUserProfile("user123", &models.UpdateProfileInput{Phone: str("123")})
+	validationErr, ok := err.(*errors.ValidationError)
+	assert.True(t, ok)
+	assert.Equal(t, "phone", validationErr.FieldErrors[0].Field)
+
+	// Uma atualização sem campos é inválida
+	_, err = authService.PatchUserProfile("user123", &models.UpdateProfileInput{})
+	assert.Error(t, err)
+
+	mockRepo.AssertNumberOfCalls(t, "Update", 1)
+}
+
 func TestUpdateUserProfile_UserNotFound(t *testing.T) {
 	// Configurar mock
 	mockRepo := new(MockUserRepository)
@@ -471,7 +553,7 @@ func TestRefreshToken_Success(t *testing.T) {
 	authService := NewAuthService(mockRepo, cfg)
 
 	// Chamar método
-	newTokenPair, err := authService.RefreshToken(tokenPair.RefreshToken)
+	newTokenPair, err := authService.RefreshToken(tokenPair.RefreshToken, models.ClientInfo{})
 
 	// Verificações
 	assert.NoError(t, err)
@@ -493,7 +575,7 @@ func TestRefreshToken_InvalidToken(t *testing.T) {
 	invalidToken := "invalid.token.string"
 
 	// Chamar método
-	newTokenPair, err := authService.RefreshToken(invalidToken)
+	newTokenPair, err := authService.RefreshToken(invalidToken, models.ClientInfo{})
 
 	// Verificações
 	assert.Error(t, err)
@@ -537,7 +619,7 @@ func TestRefreshToken_UserNotFound(t *testing.T) {
 	authService := NewAuthService(mockRepo, cfg)
 
 	// Chamar método com o refresh token
-	newTokenPair, err := authService.RefreshToken(tokenPair.RefreshToken)
+	newTokenPair, err := authService.RefreshToken(tokenPair.RefreshToken, models.ClientInfo{})
 
 	// Verificações
 	assert.Error(t, err)
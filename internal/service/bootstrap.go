@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	stderrors "errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"callable-api/internal/models"
+	"callable-api/pkg/errors"
+	"callable-api/pkg/logger"
+)
+
+// Causas dos erros da configuração inicial, para que o handler possa
+// diferenciá-las
+var (
+	ErrBootstrapCompleted    = stderrors.New("configuração inicial concluída")
+	ErrBootstrapTokenInvalid = stderrors.New("token de configuração inválido")
+)
+
+// BootstrapConfig define as credenciais do primeiro administrador, criado na
+// primeira execução (sem usuários). Sem credenciais, é gerado um token de
+// configuração de uso único para POST /api/v1/auth/bootstrap
+type BootstrapConfig struct {
+	AdminEmail    string
+	AdminPassword string
+	AdminName     string
+}
+
+// Validate verifica as credenciais configuradas; a configuração vazia (token
+// de configuração) é válida
+func (c BootstrapConfig) Validate() error {
+	if c.AdminEmail == "" && c.AdminPassword == "" {
+		return nil
+	}
+	return validateBootstrapAdmin(c.AdminEmail, c.AdminPassword)
+}
+
+// bootstrapState guarda o hash do token de configuração pendente
+type bootstrapState struct {
+	mutex     sync.Mutex
+	tokenHash string
+}
+
+// Bootstrap prepara a primeira execução: sem usuários cadastrados, cria o
+// administrador com as credenciais configuradas ou, sem elas, gera o token de
+// configuração. O token é retornado para ser exibido uma única vez; o retorno
+// é vazio se já existem usuários ou se o administrador foi criado
+func (s *AuthService) Bootstrap(ctx context.Context, cfg BootstrapConfig) (string, error) {
+	_, total, err := s.repo.List(1, 1)
+	if err != nil {
+		return "", fmt.Errorf("contar os usuários: %w", err)
+	}
+	if total > 0 {
+		return "", nil
+	}
+
+	if cfg.AdminEmail != "" || cfg.AdminPassword != "" {
+		name := cfg.AdminName
+		if name == "" {
+			name = "Administrador"
+		}
+		if _, err := s.createBootstrapAdmin(ctx, cfg.AdminEmail, name, cfg.AdminPassword, "config"); err != nil {
+			return "", err
+		}
+		return "", nil
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("gerar o token de configuração: %w", err)
+	}
+	token := hex.EncodeToString(secret)
+
+	s.bootstrap.mutex.Lock()
+	s.bootstrap.tokenHash = hashToken(token)
+	s.bootstrap.mutex.Unlock()
+	return token, nil
+}
+
+// CompleteBootstrap cria o primeiro administrador com o token de configuração,
+// que deixa de valer em seguida
+func (s *AuthService) CompleteBootstrap(ctx context.Context, input *models.BootstrapInput) (*models.UserResponse, error) {
+	s.bootstrap.mutex.Lock()
+	defer s.bootstrap.mutex.Unlock()
+
+	if s.bootstrap.tokenHash == "" {
+		return nil, errors.NewConflictError("A configuração inicial já foi concluída", ErrBootstrapCompleted)
+	}
+	if subtle.ConstantTimeCompare([]byte(hashToken(input.Token)), []byte(s.bootstrap.tokenHash)) != 1 {
+		return nil, errors.NewForbiddenError("Token de configuração inválido", ErrBootstrapTokenInvalid)
+	}
+
+	user, err := s.createBootstrapAdmin(ctx, input.Email, input.Name, input.Password, "token")
+	if err != nil {
+		return nil, err
+	}
+	s.bootstrap.tokenHash = ""
+
+	response := user.ToUserResponse()
+	return &response, nil
+}
+
+// validateBootstrapAdmin verifica o email e a senha do primeiro administrador
+func validateBootstrapAdmin(email, password string) error {
+	if !strings.Contains(email, "@") {
+		return errors.NewBadRequestError("Email do administrador inválido", nil)
+	}
+	if len(password) < minPasswordLength {
+		return errors.NewBadRequestError(fmt.Sprintf("A senha do administrador deve ter ao menos %d caracteres", minPasswordLength), nil)
+	}
+	return nil
+}
+
+// createBootstrapAdmin cria o primeiro administrador
+func (s *AuthService) createBootstrapAdmin(ctx context.Context, email, name, password, source string) (*models.User, error) {
+	if err := validateBootstrapAdmin(email, password); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, errors.NewInternalServerError("Erro ao processar senha", err)
+	}
+	user, err := s.repo.Create(&models.User{
+		Email:    email,
+		Name:     name,
+		Password: string(hashedPassword),
+		Role:     "admin",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Primeiro administrador criado", map[string]interface{}{
+		"userId": user.ID,
+		"email":  user.Email,
+		"source": source,
+	})
+	return user, nil
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"callable-api/internal/models"
+	"callable-api/internal/repository"
+)
+
+func TestBootstrap(t *testing.T) {
+	ctx := context.Background()
+	users := repository.NewEmptyInMemoryUserRepository()
+	authService := NewAuthService(users, getTestConfig())
+
+	// Sem credenciais configuradas, é gerado o token de configuração
+	token, err := authService.Bootstrap(ctx, BootstrapConfig{})
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+
+	input := &models.BootstrapInput{Token: "errado", Email: "root@example.test", Name: "Root", Password: "senha-root"}
+	_, err = authService.CompleteBootstrap(ctx, input)
+	assert.Equal(t, "FORBIDDEN", appErrorType(err))
+
+	input.Token = token
+	admin, err := authService.CompleteBootstrap(ctx, input)
+	require.NoError(t, err)
+	assert.Equal(t, "admin", admin.Role)
+	_, err = users.Authenticate("root@example.test", "senha-root")
+	assert.NoError(t, err)
+
+	// O token vale uma única vez, e com usuários não há nova configuração
+	_, err = authService.CompleteBootstrap(ctx, input)
+	assert.Equal(t, "CONFLICT", appErrorType(err))
+	token, err = authService.Bootstrap(ctx, BootstrapConfig{})
+	require.NoError(t, err)
+	assert.Empty(t, token)
+}
+
+func TestBootstrap_FromConfig(t *testing.T) {
+	ctx := context.Background()
+	users := repository.NewEmptyInMemoryUserRepository()
+	authService := NewAuthService(users, getTestConfig())
+
+	_, err := authService.Bootstrap(ctx, BootstrapConfig{AdminEmail: "root@example.test", AdminPassword: "123"})
+	assert.Equal(t, "BAD_REQUEST", appErrorType(err))
+
+	token, err := authService.Bootstrap(ctx, BootstrapConfig{AdminEmail: "root@example.test", AdminPassword: "senha-root"})
+	require.NoError(t, err)
+	assert.Empty(t, token)
+
+	admin, err := users.Authenticate("root@example.test", "senha-root")
+	require.NoError(t, err)
+	assert.Equal(t, "admin", admin.Role)
+	assert.Equal(t, "Administrador", admin.Name)
+
+	// Sem token pendente, o endpoint não está disponível
+	_, err = authService.CompleteBootstrap(ctx, &models.BootstrapInput{Token: "x", Email: "b@example.test", Name: "B", Password: "senha-b"})
+	assert.Equal(t, "CONFLICT", appErrorType(err))
+}
+
+func TestBootstrapConfig_Validate(t *testing.T) {
+	assert.NoError(t, BootstrapConfig{}.Validate())
+	assert.NoError(t, BootstrapConfig{AdminEmail: "root@example.test", AdminPassword: "senha-root"}.Validate())
+	assert.Equal(t, "BAD_REQUEST", appErrorType(BootstrapConfig{AdminEmail: "root@example.test", AdminPassword: "123"}.Validate()))
+	assert.Equal(t, "BAD_REQUEST", appErrorType(BootstrapConfig{AdminPassword: "senha-root"}.Validate()))
+}
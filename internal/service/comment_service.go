@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+
+	"callable-api/internal/models"
+	"callable-api/internal/repository"
+	"callable-api/pkg/errors"
+	"callable-api/pkg/logger"
+)
+
+// ItemReader busca itens respeitando as permissões do usuário (ver ItemService.GetItemByID)
+type ItemReader interface {
+	GetItemByID(ctx context.Context, viewer models.Viewer, id string) (*models.Item, error)
+}
+
+// CommentService gerencia os comentários dos itens. Só comenta e lê os
+// comentários quem pode ler o item
+type CommentService struct {
+	comments repository.CommentRepository
+	items    ItemReader
+	users    repository.UserRepository
+}
+
+// NewCommentService cria um novo CommentService
+func NewCommentService(comments repository.CommentRepository, items ItemReader, users repository.UserRepository) *CommentService {
+	return &CommentService{
+		comments: comments,
+		items:    items,
+		users:    users,
+	}
+}
+
+// AddComment adiciona um comentário ao item ou, com ParentID, uma resposta a
+// outro comentário do mesmo item
+func (s *CommentService) AddComment(ctx context.Context, viewer models.Viewer, itemID string, input *models.CreateCommentInput) (*models.CommentResponse, error) {
+	if viewer.UserID == "" {
+		return nil, errors.NewUnauthorizedError("Autenticação necessária para comentar", nil)
+	}
+	if _, err := s.items.GetItemByID(ctx, viewer, itemID); err != nil {
+		return nil, err
+	}
+
+	comment := &models.Comment{
+		ItemID:   itemID,
+		AuthorID: viewer.UserID,
+		Body:     input.Body,
+	}
+	if input.ParentID != "" {
+		parent, err := s.comments.FindByID(ctx, input.ParentID)
+		if err != nil || parent.ItemID != itemID {
+			return nil, errors.NewBadRequestError("Comentário respondido não encontrado neste item", nil)
+		}
+		comment.ParentID = parent.ID
+		comment.ThreadID = parent.ThreadID
+	}
+
+	created, err := s.comments.Create(ctx, comment)
+	if err != nil {
+		return nil, s.repositoryError(ctx, "Falha ao gravar comentário", err)
+	}
+
+	logger.Info("Comentário adicionado", map[string]interface{}{
+		"itemId":    itemID,
+		"commentId": created.ID,
+		"userId":    viewer.UserID,
+	})
+
+	response := s.toResponse(*created, map[string]*models.CommentAuthor{})
+	return &response, nil
+}
+
+// ListComments retorna as conversas do item, com paginação pelos comentários
+// que as iniciaram. Cada comentário traz suas respostas em Replies
+func (s *CommentService) ListComments(ctx context.Context, viewer models.Viewer, itemID string, page, limit int) ([]models.CommentResponse, int, error) {
+	if _, err := s.items.GetItemByID(ctx, viewer, itemID); err != nil {
+		return nil, 0, err
+	}
+
+	threads, total, err := s.comments.ListThreads(ctx, itemID, page, limit)
+	if err != nil {
+		return nil, 0, s.repositoryError(ctx, "Falha ao buscar comentários", err)
+	}
+
+	threadIDs := make([]string, len(threads))
+	for i, thread := range threads {
+		threadIDs[i] = thread.ID
+	}
+	replies, err := s.comments.ListReplies(ctx, threadIDs)
+	if err != nil {
+		return nil, 0, s.repositoryError(ctx, "Falha ao buscar respostas", err)
+	}
+
+	children := make(map[string][]models.Comment)
+	for _, reply := range replies {
+		children[reply.ParentID] = append(children[reply.ParentID], reply)
+	}
+
+	authors := make(map[string]*models.CommentAuthor)
+	var build func(comment models.Comment) models.CommentResponse
+	build = func(comment models.Comment) models.CommentResponse {
+		response := s.toResponse(comment, authors)
+		for _, child := range children[comment.ID] {
+			response.Replies = append(response.Replies, build(child))
+		}
+		return response
+	}
+
+	result := make([]models.CommentResponse, len(threads))
+	for i, thread := range threads {
+		result[i] = build(thread)
+	}
+	return result, total, nil
+}
+
+// DeleteComment remove um comentário. Apenas o autor ou um administrador pode
+// removê-lo; comentários com respostas são apenas marcados como removidos, para
+// preservar a conversa
+func (s *CommentService) DeleteComment(ctx context.Context, viewer models.Viewer, itemID, commentID string) error {
+	if _, err := s.items.GetItemByID(ctx, viewer, itemID); err != nil {
+		return err
+	}
+
+	comment, err := s.comments.FindByID(ctx, commentID)
+	if err != nil {
+		return err
+	}
+	if comment.ItemID != itemID || comment.Deleted {
+		return errors.NewNotFoundError("Comentário não encontrado", repository.ErrCommentNotFound)
+	}
+	if comment.AuthorID != viewer.UserID && !viewer.IsAdmin() {
+		return errors.NewForbiddenError("Apenas o autor pode remover o comentário", nil)
+	}
+
+	replies, err := s.comments.ListReplies(ctx, []string{comment.ThreadID})
+	if err != nil {
+		return s.repositoryError(ctx, "Falha ao buscar respostas", err)
+	}
+	hasReplies := false
+	for _, reply := range replies {
+		if reply.ParentID == comment.ID {
+			hasReplies = true
+			break
+		}
+	}
+
+	if hasReplies {
+		removed := *comment
+		removed.Deleted = true
+		removed.Body = ""
+		_, err = s.comments.Update(ctx, &removed)
+	} else {
+		err = s.comments.Delete(ctx, comment.ID)
+	}
+	if err != nil {
+		return s.repositoryError(ctx, "Falha ao remover comentário", err)
+	}
+
+	logger.Info("Comentário removido", map[string]interface{}{
+		"itemId":    itemID,
+		"commentId": comment.ID,
+		"userId":    viewer.UserID,
+	})
+	return nil
+}
+
+// toResponse converte o comentário, buscando o autor uma única vez por listagem
+func (s *CommentService) toResponse(comment models.Comment, authors map[string]*models.CommentAuthor) models.CommentResponse {
+	response := models.CommentResponse{
+		ID:        comment.ID,
+		ItemID:    comment.ItemID,
+		ParentID:  comment.ParentID,
+		Body:      comment.Body,
+		Deleted:   comment.Deleted,
+		CreatedAt: comment.CreatedAt,
+		Replies:   []models.CommentResponse{},
+	}
+	if comment.Deleted {
+		return response
+	}
+
+	author, cached := authors[comment.AuthorID]
+	if !cached {
+		// Autores removidos aparecem só com o ID
+		author = &models.CommentAuthor{ID: comment.AuthorID}
+		if user, err := s.users.FindByID(comment.AuthorID); err == nil {
+			author.Name = user.Name
+			author.AvatarURL = user.AvatarURL
+		}
+		authors[comment.AuthorID] = author
+	}
+	response.Author = author
+	return response
+}
+
+// repositoryError preserva o erro do contexto (prazo da requisição expirado) e
+// encapsula os demais como erro interno
+func (s *CommentService) repositoryError(ctx context.Context, message string, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return errors.NewInternalServerError(message, err)
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"callable-api/internal/models"
+	"callable-api/internal/repository"
+	"callable-api/internal/repository/testsupport"
+)
+
+func TestItemComments(t *testing.T) {
+	ctx := context.Background()
+	users := testsupport.NewUserRepository()
+	itemService := NewItemService(repository.NewEmptyInMemoryItemRepository()).
+		WithSharing(repository.NewInMemoryItemShareRepository(), users)
+	comments := NewCommentService(repository.NewInMemoryCommentRepository(), itemService, users)
+
+	ownerUser := seededUser(t, users, "user@example.com")
+	owner := models.Viewer{UserID: ownerUser.ID, Role: ownerUser.Role}
+	other, err := users.Create(&models.User{Email: "outro@example.com", Name: "Outro", Role: "user"})
+	require.NoError(t, err)
+	stranger := models.Viewer{UserID: other.ID, Role: other.Role}
+	admin := models.Viewer{UserID: "admin-id", Role: "admin"}
+
+	item, err := itemService.CreateItem(ctx, owner, &models.InputData{Name: "Relatório", Value: "v1", Email: "user@example.com"})
+	require.NoError(t, err)
+
+	// Quem não pode ler o item também não vê nem cria comentários
+	_, err = comments.AddComment(ctx, stranger, item.ID, &models.CreateCommentInput{Body: "Oi"})
+	assert.Equal(t, "NOT_FOUND", appErrorType(err))
+	_, err = comments.AddComment(ctx, models.Viewer{}, item.ID, &models.CreateCommentInput{Body: "Oi"})
+	assert.Equal(t, "UNAUTHORIZED", appErrorType(err))
+
+	root, err := comments.AddComment(ctx, owner, item.ID, &models.CreateCommentInput{Body: "Primeira versão"})
+	require.NoError(t, err)
+	assert.Equal(t, ownerUser.Name, root.Author.Name)
+
+	_, err = itemService.ShareItem(ctx, owner, item.ID, &models.ShareItemInput{UserID: other.ID, Level: models.ShareLevelRead})
+	require.NoError(t, err)
+	reply, err := comments.AddComment(ctx, stranger, item.ID, &models.CreateCommentInput{Body: "Ficou ótimo", ParentID: root.ID})
+	require.NoError(t, err)
+	_, err = comments.AddComment(ctx, owner, item.ID, &models.CreateCommentInput{Body: "Obrigado", ParentID: reply.ID})
+	require.NoError(t, err)
+	_, err = comments.AddComment(ctx, owner, item.ID, &models.CreateCommentInput{Body: "?", ParentID: "inexistente"})
+	assert.Equal(t, "BAD_REQUEST", appErrorType(err))
+
+	// As respostas vêm aninhadas, com o autor de cada uma
+	threads, total, err := comments.ListComments(ctx, stranger, item.ID, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, threads, 1)
+	require.Len(t, threads[0].Replies, 1)
+	assert.Equal(t, "Outro", threads[0].Replies[0].Author.Name)
+	require.Len(t, threads[0].Replies[0].Replies, 1)
+	assert.Equal(t, "Obrigado", threads[0].Replies[0].Replies[0].Body)
+
+	// Só o autor (ou um administrador) remove o comentário
+	err = comments.DeleteComment(ctx, owner, item.ID, reply.ID)
+	assert.Equal(t, "FORBIDDEN", appErrorType(err))
+
+	// Comentários com respostas ficam na conversa, sem texto e autor
+	require.NoError(t, comments.DeleteComment(ctx, stranger, item.ID, reply.ID))
+	threads, _, err = comments.ListComments(ctx, owner, item.ID, 1, 10)
+	require.NoError(t, err)
+	removed := threads[0].Replies[0]
+	assert.True(t, removed.Deleted)
+	assert.Empty(t, removed.Body)
+	assert.Nil(t, removed.Author)
+	assert.Len(t, removed.Replies, 1)
+
+	err = comments.DeleteComment(ctx, stranger, item.ID, reply.ID)
+	assert.ErrorIs(t, err, repository.ErrCommentNotFound)
+
+	// Sem respostas, o comentário é removido de vez
+	leaf := threads[0].Replies[0].Replies[0]
+	require.NoError(t, comments.DeleteComment(ctx, admin, item.ID, leaf.ID))
+	threads, _, _ = comments.ListComments(ctx, owner, item.ID, 1, 10)
+	assert.Empty(t, threads[0].Replies[0].Replies)
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"callable-api/internal/models"
+	"callable-api/internal/repository"
+	"callable-api/pkg/errors"
+	"callable-api/pkg/logger"
+)
+
+// ConsentDocument define as versões de um documento: Version é a versão
+// vigente, a única que pode ser aceita, e Required é a versão mínima
+// obrigatória. Publicar uma versão sem atualizar Required não bloqueia quem
+// aceitou a anterior
+type ConsentDocument struct {
+	Name     string
+	Version  string
+	Required string
+}
+
+// ConsentConfig define os documentos cujo aceite é registrado; sem documentos
+// o registro de aceites fica desativado
+type ConsentConfig struct {
+	Documents []ConsentDocument
+}
+
+// Enabled indica se algum documento exige aceite
+func (c ConsentConfig) Enabled() bool {
+	return len(c.Documents) > 0
+}
+
+// WithConsent registra os aceites dos termos de uso e da política de
+// privacidade, exigidos no registro e a cada nova versão obrigatória
+func (s *AuthService) WithConsent(consents repository.ConsentRepository, cfg ConsentConfig) *AuthService {
+	s.consents = consents
+	s.consentConfig = cfg
+	return s
+}
+
+// consentEnabled indica se os aceites são registrados
+func (s *AuthService) consentEnabled() bool {
+	return s.consents != nil && s.consentConfig.Enabled()
+}
+
+// ConsentStatus retorna a situação do usuário em relação a cada documento e o
+// seu histórico de aceites
+func (s *AuthService) ConsentStatus(ctx context.Context, userID string) (*models.ConsentStatus, error) {
+	if _, err := s.repo.FindByID(userID); err != nil {
+		return nil, err
+	}
+	return s.consentStatus(ctx, userID)
+}
+
+// consentStatus monta a situação dos documentos a partir do histórico de aceites
+func (s *AuthService) consentStatus(ctx context.Context, userID string) (*models.ConsentStatus, error) {
+	if !s.consentEnabled() {
+		return &models.ConsentStatus{Documents: []models.ConsentDocumentStatus{}, History: []models.Consent{}}, nil
+	}
+
+	history, err := s.consents.ListByUser(ctx, userID)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, errors.NewInternalServerError("Falha ao buscar aceites", err)
+	}
+
+	status := &models.ConsentStatus{History: history}
+	for _, doc := range s.consentConfig.Documents {
+		docStatus := models.ConsentDocumentStatus{
+			Document:        doc.Name,
+			CurrentVersion:  doc.Version,
+			RequiredVersion: doc.Required,
+		}
+		// O aceite mais recente vale para o documento
+		for i := len(history) - 1; i >= 0; i-- {
+			if history[i].Document == doc.Name {
+				docStatus.AcceptedVersion = history[i].Version
+				docStatus.AcceptedAt = &history[i].AcceptedAt
+				break
+			}
+		}
+		docStatus.Pending = docStatus.AcceptedVersion == "" || compareVersions(docStatus.AcceptedVersion, doc.Required) < 0
+		status.Documents = append(status.Documents, docStatus)
+	}
+	return status, nil
+}
+
+// CheckConsent retorna um erro Forbidden (causa repository.ErrConsentRequired) se o
+// usuário não aceitou alguma versão obrigatória
+func (s *AuthService) CheckConsent(ctx context.Context, userID string) error {
+	if !s.consentEnabled() {
+		return nil
+	}
+
+	status, err := s.consentStatus(ctx, userID)
+	if err != nil {
+		return err
+	}
+	var pending []string
+	for _, doc := range status.Documents {
+		if doc.Pending {
+			pending = append(pending, doc.Document+" "+doc.CurrentVersion)
+		}
+	}
+	if len(pending) > 0 {
+		return errors.NewForbiddenError("Aceite a versão vigente dos documentos pendentes ("+strings.Join(pending, ", ")+") em POST /api/v1/auth/consent", repository.ErrConsentRequired)
+	}
+	return nil
+}
+
+// RecordConsent registra o aceite das versões vigentes informadas
+func (s *AuthService) RecordConsent(ctx context.Context, userID string, input *models.ConsentInput, client models.ClientInfo) (*models.ConsentStatus, error) {
+	if !s.consentEnabled() {
+		return nil, errors.NewBadRequestError("O registro de aceites não está configurado", nil)
+	}
+	versions := input.Versions()
+	if len(versions) == 0 {
+		return nil, errors.NewBadRequestError("Informe a versão aceita de ao menos um documento", nil)
+	}
+	if err := s.checkConsentVersions(versions, false); err != nil {
+		return nil, err
+	}
+
+	if err := s.recordConsents(ctx, userID, versions, models.ConsentSourceAPI, client); err != nil {
+		return nil, err
+	}
+	return s.consentStatus(ctx, userID)
+}
+
+// registrationConsents valida os aceites informados no registro: as versões
+// obrigatórias de todos os documentos precisam ser aceitas
+func (s *AuthService) registrationConsents(input *models.RegisterUserInput) (map[string]string, error) {
+	if !s.consentEnabled() {
+		return nil, nil
+	}
+	versions := models.ConsentInput{TermsVersion: input.TermsVersion, PrivacyVersion: input.PrivacyVersion}.Versions()
+	if err := s.checkConsentVersions(versions, true); err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// checkConsentVersions exige que as versões aceitas sejam as vigentes e, se
+// all for true, que todos os documentos sejam aceitos
+func (s *AuthService) checkConsentVersions(versions map[string]string, all bool) error {
+	known := make(map[string]bool, len(s.consentConfig.Documents))
+	for _, doc := range s.consentConfig.Documents {
+		known[doc.Name] = true
+		version, accepted := versions[doc.Name]
+		if !accepted {
+			if all {
+				return errors.NewBadRequestError(fmt.Sprintf("É necessário aceitar a versão %s de %s", doc.Version, doc.Name), nil)
+			}
+			continue
+		}
+		if version != doc.Version {
+			return errors.NewBadRequestError(fmt.Sprintf("Versão de %s desatualizada: a versão vigente é %s", doc.Name, doc.Version), nil)
+		}
+	}
+	for name := range versions {
+		if !known[name] {
+			return errors.NewBadRequestError(fmt.Sprintf("O aceite de %s não é registrado", name), nil)
+		}
+	}
+	return nil
+}
+
+// recordConsents grava um aceite por documento
+func (s *AuthService) recordConsents(ctx context.Context, userID string, versions map[string]string, source string, client models.ClientInfo) error {
+	now := s.clock.Now().UTC()
+	for _, doc := range s.consentConfig.Documents {
+		version, accepted := versions[doc.Name]
+		if !accepted {
+			continue
+		}
+		if _, err := s.consents.Record(ctx, &models.Consent{
+			UserID:     userID,
+			Document:   doc.Name,
+			Version:    version,
+			Source:     source,
+			IP:         client.IP,
+			UserAgent:  client.UserAgent,
+			AcceptedAt: now,
+		}); err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			return errors.NewInternalServerError("Falha ao registrar aceite", err)
+		}
+	}
+
+	logger.Info("Aceite de documentos registrado", map[string]interface{}{
+		"userId":   userID,
+		"versions": versions,
+		"source":   source,
+	})
+	return nil
+}
+
+// compareVersions compara duas versões parte a parte (ex.: "2024-06-01",
+// "3.10"): partes numéricas são comparadas como números e as demais como texto
+func compareVersions(a, b string) int {
+	split := func(version string) []string {
+		return strings.FieldsFunc(version, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+	}
+	partsA, partsB := split(a), split(b)
+	for i := 0; i < len(partsA) && i < len(partsB); i++ {
+		numA, errA := strconv.Atoi(partsA[i])
+		numB, errB := strconv.Atoi(partsB[i])
+		switch {
+		case errA == nil && errB == nil && numA != numB:
+			if numA < numB {
+				return -1
+			}
+			return 1
+		case (errA != nil || errB != nil) && partsA[i] != partsB[i]:
+			return strings.Compare(partsA[i], partsB[i])
+		}
+	}
+	switch {
+	case len(partsA) < len(partsB):
+		return -1
+	case len(partsA) > len(partsB):
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"callable-api/internal/models"
+	"callable-api/internal/repository"
+	"callable-api/internal/repository/testsupport"
+)
+
+func TestConsent(t *testing.T) {
+	ctx := context.Background()
+	users := testsupport.NewUserRepository()
+	consents := repository.NewInMemoryConsentRepository()
+	cfg := ConsentConfig{Documents: []ConsentDocument{
+		{Name: models.ConsentTerms, Version: "2024-01-01", Required: "2024-01-01"},
+		{Name: models.ConsentPrivacy, Version: "1.0", Required: "1.0"},
+	}}
+	authService := NewAuthService(users, getTestConfig()).WithConsent(consents, cfg)
+
+	// O registro exige o aceite das versões vigentes de todos os documentos
+	input := &models.RegisterUserInput{Email: "novo@example.com", Name: "Novo", Password: "senha-segura", TermsVersion: "2024-01-01"}
+	_, err := authService.Register(input)
+	assert.Equal(t, "BAD_REQUEST", appErrorType(err))
+
+	input.PrivacyVersion = "0.9"
+	_, err = authService.Register(input)
+	assert.Equal(t, "BAD_REQUEST", appErrorType(err))
+
+	input.PrivacyVersion = "1.0"
+	user, err := authService.Register(input)
+	require.NoError(t, err)
+	assert.NoError(t, authService.CheckConsent(ctx, user.ID))
+
+	status, err := authService.ConsentStatus(ctx, user.ID)
+	require.NoError(t, err)
+	require.Len(t, status.History, 2)
+	assert.Equal(t, models.ConsentSourceRegistration, status.History[0].Source)
+
+	// Uma nova versão não obrigatória pode ser aceita sem bloquear o uso
+	authService.WithConsent(consents, ConsentConfig{Documents: []ConsentDocument{
+		{Name: models.ConsentTerms, Version: "2024-06-01", Required: "2024-01-01"},
+		{Name: models.ConsentPrivacy, Version: "1.0", Required: "1.0"},
+	}})
+	assert.NoError(t, authService.CheckConsent(ctx, user.ID))
+
+	// Quando ela passa a ser obrigatória, o uso fica bloqueado até o aceite
+	authService.WithConsent(consents, ConsentConfig{Documents: []ConsentDocument{
+		{Name: models.ConsentTerms, Version: "2024-06-01", Required: "2024-06-01"},
+		{Name: models.ConsentPrivacy, Version: "1.0", Required: "1.0"},
+	}})
+	err = authService.CheckConsent(ctx, user.ID)
+	assert.Equal(t, "FORBIDDEN", appErrorType(err))
+	assert.True(t, stderrors.Is(err, repository.ErrConsentRequired))
+
+	_, err = authService.RecordConsent(ctx, user.ID, &models.ConsentInput{TermsVersion: "2024-01-01"}, models.ClientInfo{})
+	assert.Equal(t, "BAD_REQUEST", appErrorType(err))
+
+	status, err = authService.RecordConsent(ctx, user.ID, &models.ConsentInput{TermsVersion: "2024-06-01"}, models.ClientInfo{IP: "203.0.113.10"})
+	require.NoError(t, err)
+	assert.NoError(t, authService.CheckConsent(ctx, user.ID))
+	require.Len(t, status.History, 3)
+	assert.Equal(t, "203.0.113.10", status.History[2].IP)
+	assert.Equal(t, models.ConsentSourceAPI, status.History[2].Source)
+	for _, doc := range status.Documents {
+		assert.False(t, doc.Pending, doc.Document)
+	}
+
+	// Sem documentos configurados não há bloqueio
+	assert.NoError(t, NewAuthService(users, getTestConfig()).CheckConsent(ctx, user.ID))
+}
+
+func TestCompareVersions(t *testing.T) {
+	assert.Equal(t, -1, compareVersions("2024-01-01", "2024-06-01"))
+	assert.Equal(t, 1, compareVersions("3.10", "3.9"))
+	assert.Equal(t, 0, compareVersions("v2", "v2"))
+	assert.Equal(t, -1, compareVersions("1.0", "1.0.1"))
+}
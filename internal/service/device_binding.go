@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	stderrors "errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"callable-api/internal/models"
+	"callable-api/internal/repository"
+	"callable-api/pkg/errors"
+	"callable-api/pkg/logger"
+)
+
+// ErrDeviceMismatch indica uma renovação de tokens a partir de um dispositivo
+// diferente daquele que recebeu o token de atualização
+var ErrDeviceMismatch = stderrors.New("dispositivo não corresponde ao token de atualização")
+
+// DeviceBindingMode define o rigor da verificação do dispositivo na renovação de tokens
+type DeviceBindingMode string
+
+const (
+	// DeviceBindingOff não vincula os tokens de atualização a dispositivos
+	DeviceBindingOff DeviceBindingMode = "off"
+
+	// DeviceBindingLenient rejeita renovações com identificador de dispositivo
+	// diferente do vinculado (quando o login informou um); mudanças de user
+	// agent (ex.: atualização do navegador) são apenas registradas no log.
+	// Tokens sem vínculo são aceitos
+	DeviceBindingLenient DeviceBindingMode = "lenient"
+
+	// DeviceBindingStrict exige o mesmo identificador de dispositivo e o mesmo
+	// user agent, e rejeita tokens sem vínculo registrado
+	DeviceBindingStrict DeviceBindingMode = "strict"
+)
+
+// ParseDeviceBindingMode converte o texto da configuração em DeviceBindingMode
+func ParseDeviceBindingMode(value string) (DeviceBindingMode, error) {
+	switch mode := DeviceBindingMode(strings.ToLower(strings.TrimSpace(value))); mode {
+	case "", DeviceBindingOff:
+		return DeviceBindingOff, nil
+	case DeviceBindingLenient, DeviceBindingStrict:
+		return mode, nil
+	default:
+		return DeviceBindingOff, fmt.Errorf("modo de vínculo de dispositivo inválido: %q (use off, lenient ou strict)", value)
+	}
+}
+
+// DeviceBindingConfig define o vínculo dos tokens de atualização aos dispositivos
+type DeviceBindingConfig struct {
+	Mode DeviceBindingMode
+}
+
+// WithDeviceBinding vincula os tokens de atualização ao dispositivo que os
+// recebeu. Sem essa opção (ou com o modo off) qualquer cliente pode renovar
+func (s *AuthService) WithDeviceBinding(bindings repository.DeviceBindingRepository, cfg DeviceBindingConfig) *AuthService {
+	if cfg.Mode == DeviceBindingOff || cfg.Mode == "" {
+		s.deviceBindings = nil
+		return s
+	}
+	s.deviceBindings = bindings
+	s.deviceBinding = cfg
+	return s
+}
+
+// bindRefreshToken registra o dispositivo que recebeu o token de atualização,
+// mantendo o vínculo enquanto o token for válido
+func (s *AuthService) bindRefreshToken(userID, refreshToken string, client models.ClientInfo, ttl time.Duration) error {
+	if s.deviceBindings == nil {
+		return nil
+	}
+
+	binding := &models.DeviceBinding{
+		UserID:        userID,
+		DeviceID:      client.DeviceID,
+		UserAgentHash: hashToken(client.UserAgent),
+		ExpiresAt:     s.clock.Now().Add(ttl),
+	}
+	if err := s.deviceBindings.Save(context.Background(), hashToken(refreshToken), binding); err != nil {
+		return errors.NewInternalServerError("Erro ao vincular o token ao dispositivo", err)
+	}
+	return nil
+}
+
+// checkDeviceBinding verifica se o token de atualização está sendo usado pelo
+// dispositivo que o recebeu e consome o vínculo, que é renovado junto com os tokens
+func (s *AuthService) checkDeviceBinding(userID, refreshToken string, client models.ClientInfo) error {
+	if s.deviceBindings == nil {
+		return nil
+	}
+
+	ctx := context.Background()
+	tokenHash := hashToken(refreshToken)
+	binding, err := s.deviceBindings.Find(ctx, tokenHash)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); !ok || appErr.Type != "NOT_FOUND" {
+			return errors.NewInternalServerError("Erro ao verificar o dispositivo", err)
+		}
+		if s.deviceBinding.Mode == DeviceBindingStrict {
+			return s.deviceMismatch(userID, client, "token sem vínculo de dispositivo")
+		}
+		return nil
+	}
+
+	strict := s.deviceBinding.Mode == DeviceBindingStrict
+	if binding.UserID != userID {
+		return s.deviceMismatch(userID, client, "token vinculado a outro usuário")
+	}
+	if (strict || binding.DeviceID != "") && !constantTimeEqual(binding.DeviceID, client.DeviceID) {
+		return s.deviceMismatch(userID, client, "identificador de dispositivo diferente")
+	}
+	if binding.UserAgentHash != hashToken(client.UserAgent) {
+		if strict {
+			return s.deviceMismatch(userID, client, "user agent diferente")
+		}
+		logger.Warn("Renovação de tokens com user agent diferente do vinculado", map[string]interface{}{
+			"userId": userID,
+			"ip":     client.IP,
+		})
+	}
+
+	if err := s.deviceBindings.Delete(ctx, tokenHash); err != nil {
+		return errors.NewInternalServerError("Erro ao verificar o dispositivo", err)
+	}
+	return nil
+}
+
+// deviceMismatch registra e retorna a rejeição de uma renovação de tokens
+func (s *AuthService) deviceMismatch(userID string, client models.ClientInfo, reason string) error {
+	logger.Warn("Renovação de tokens rejeitada pelo vínculo de dispositivo", map[string]interface{}{
+		"userId": userID,
+		"ip":     client.IP,
+		"reason": reason,
+	})
+	return errors.NewUnauthorizedError("Token de atualização não pertence a este dispositivo", ErrDeviceMismatch)
+}
+
+// constantTimeEqual compara dois valores em tempo constante
+func constantTimeEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"callable-api/internal/models"
+	"callable-api/internal/repository"
+	"callable-api/internal/repository/testsupport"
+)
+
+func newDeviceBoundService(users repository.UserRepository, mode DeviceBindingMode) *AuthService {
+	return NewAuthService(users, getTestConfig()).
+		WithDeviceBinding(repository.NewInMemoryDeviceBindingRepository(), DeviceBindingConfig{Mode: mode})
+}
+
+func TestDeviceBinding_Strict(t *testing.T) {
+	users := testsupport.NewUserRepository()
+	authService := newDeviceBoundService(users, DeviceBindingStrict)
+	phone := models.ClientInfo{DeviceID: "device-a", UserAgent: "app/1.0"}
+
+	tokens, _, err := authService.Login(&models.LoginInput{Email: "user@example.com", Password: "user123"}, phone)
+	assert.NoError(t, err)
+
+	// Outro dispositivo ou outro user agent não renovam
+	_, err = authService.RefreshToken(tokens.RefreshToken, models.ClientInfo{DeviceID: "device-b", UserAgent: "app/1.0"})
+	assert.True(t, causeIs(err, ErrDeviceMismatch))
+	_, err = authService.RefreshToken(tokens.RefreshToken, models.ClientInfo{DeviceID: "device-a", UserAgent: "app/2.0"})
+	assert.True(t, causeIs(err, ErrDeviceMismatch))
+
+	// O dispositivo original renova, e o vínculo acompanha o novo token
+	renewed, err := authService.RefreshToken(tokens.RefreshToken, phone)
+	assert.NoError(t, err)
+	_, err = authService.RefreshToken(renewed.RefreshToken, phone)
+	assert.NoError(t, err)
+
+	// Tokens sem vínculo registrado são rejeitados
+	unbound := newDeviceBoundService(users, DeviceBindingStrict)
+	_, err = unbound.RefreshToken(tokens.RefreshToken, phone)
+	assert.True(t, causeIs(err, ErrDeviceMismatch))
+}
+
+func TestDeviceBinding_Lenient(t *testing.T) {
+	authService := newDeviceBoundService(testsupport.NewUserRepository(), DeviceBindingLenient)
+
+	tokens, _, err := authService.Login(&models.LoginInput{Email: "user@example.com", Password: "user123"},
+		models.ClientInfo{DeviceID: "device-a", UserAgent: "browser/1.0"})
+	assert.NoError(t, err)
+
+	_, err = authService.RefreshToken(tokens.RefreshToken, models.ClientInfo{DeviceID: "device-b", UserAgent: "browser/1.0"})
+	assert.True(t, causeIs(err, ErrDeviceMismatch))
+	_, err = authService.RefreshToken(tokens.RefreshToken, models.ClientInfo{UserAgent: "browser/1.0"})
+	assert.True(t, causeIs(err, ErrDeviceMismatch))
+
+	// Mudanças de user agent são toleradas
+	_, err = authService.RefreshToken(tokens.RefreshToken, models.ClientInfo{DeviceID: "device-a", UserAgent: "browser/2.0"})
+	assert.NoError(t, err)
+
+	// Logins sem identificador de dispositivo não ficam vinculados
+	tokens, _, err = authService.Login(&models.LoginInput{Email: "admin@example.com", Password: "admin123"}, models.ClientInfo{})
+	assert.NoError(t, err)
+	_, err = authService.RefreshToken(tokens.RefreshToken, models.ClientInfo{DeviceID: "device-c"})
+	assert.NoError(t, err)
+}
+
+func TestParseDeviceBindingMode(t *testing.T) {
+	for value, expected := range map[string]DeviceBindingMode{
+		"":         DeviceBindingOff,
+		"off":      DeviceBindingOff,
+		"Lenient":  DeviceBindingLenient,
+		" strict ": DeviceBindingStrict,
+	} {
+		mode, err := ParseDeviceBindingMode(value)
+		assert.NoError(t, err, value)
+		assert.Equal(t, expected, mode, value)
+	}
+
+	_, err := ParseDeviceBindingMode("paranoid")
+	assert.Error(t, err)
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"callable-api/internal/models"
+	"callable-api/pkg/errors"
+)
+
+// ItemCacheConfig define o cache de leitura dos itens por ID
+type ItemCacheConfig struct {
+	TTL         time.Duration // validade dos itens em cache (0 desativa o cache)
+	NegativeTTL time.Duration // validade dos IDs inexistentes em cache
+	MaxEntries  int           // limite de entradas em memória
+}
+
+// DefaultItemCacheConfig retorna a configuração padrão do cache de itens
+func DefaultItemCacheConfig() ItemCacheConfig {
+	return ItemCacheConfig{
+		TTL:         30 * time.Second,
+		NegativeTTL: 5 * time.Second,
+		MaxEntries:  10000,
+	}
+}
+
+// WithItemCache ativa o cache de leitura dos itens por ID. Buscas simultâneas
+// pelo mesmo ID compartilham uma única consulta ao repositório e IDs
+// inexistentes ficam em cache por NegativeTTL, para que rajadas de requisições
+// não sobrecarreguem o repositório. As permissões continuam sendo verificadas
+// a cada busca
+func (s *ItemService) WithItemCache(cfg ItemCacheConfig) *ItemService {
+	if cfg.TTL <= 0 {
+		s.cache = nil
+		return s
+	}
+	s.cache = newItemCache(cfg)
+	return s
+}
+
+// findItem busca o item pelo ID, passando pelo cache quando ativo
+func (s *ItemService) findItem(ctx context.Context, id string) (*models.Item, error) {
+	if s.cache == nil {
+		return s.repo.FindByID(ctx, id)
+	}
+	return s.cache.get(ctx, id, s.repo.FindByID)
+}
+
+// invalidateItem remove o item do cache após uma escrita
+func (s *ItemService) invalidateItem(id string) {
+	if s.cache != nil {
+		s.cache.invalidate(id)
+	}
+}
+
+// cacheEntry guarda um item ou, com item nil, a inexistência do ID
+type cacheEntry struct {
+	item    *models.Item
+	expires time.Time
+}
+
+// flight é uma consulta ao repositório em andamento, compartilhada pelas
+// buscas simultâneas pelo mesmo ID
+type flight struct {
+	done  chan struct{}
+	item  *models.Item
+	err   error
+	stale bool // o item foi alterado durante a consulta; o resultado não vai para o cache
+}
+
+// itemCache é um cache em memória dos itens por ID com deduplicação das
+// consultas simultâneas
+type itemCache struct {
+	cfg     ItemCacheConfig
+	mutex   sync.Mutex
+	entries map[string]cacheEntry
+	flights map[string]*flight
+	now     func() time.Time
+}
+
+func newItemCache(cfg ItemCacheConfig) *itemCache {
+	return &itemCache{
+		cfg:     cfg,
+		entries: make(map[string]cacheEntry),
+		flights: make(map[string]*flight),
+		now:     time.Now,
+	}
+}
+
+// get retorna o item do cache ou o carrega com load. Os itens são sempre
+// retornados por cópia, pois os chamadores podem alterá-los
+func (c *itemCache) get(ctx context.Context, id string, load func(ctx context.Context, id string) (*models.Item, error)) (*models.Item, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	c.mutex.Lock()
+	if entry, ok := c.entries[id]; ok && c.now().Before(entry.expires) {
+		c.mutex.Unlock()
+		return copyItem(entry.item, nil)
+	}
+
+	if f, ok := c.flights[id]; ok {
+		c.mutex.Unlock()
+		select {
+		case <-f.done:
+			return copyItem(f.item, f.err)
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	f := &flight{done: make(chan struct{})}
+	c.flights[id] = f
+	c.mutex.Unlock()
+
+	// A consulta compartilhada não deve falhar para todos se a requisição
+	// que a iniciou for cancelada
+	f.item, f.err = load(context.WithoutCancel(ctx), id)
+
+	c.mutex.Lock()
+	delete(c.flights, id)
+	if !f.stale {
+		switch {
+		case f.err == nil:
+			c.store(id, f.item, c.cfg.TTL)
+		case isNotFound(f.err) && c.cfg.NegativeTTL > 0:
+			c.store(id, nil, c.cfg.NegativeTTL)
+		}
+	}
+	c.mutex.Unlock()
+	close(f.done)
+
+	return copyItem(f.item, f.err)
+}
+
+// store grava a entrada, abrindo espaço se o cache estiver cheio. Deve ser
+// chamado com o mutex travado
+func (c *itemCache) store(id string, item *models.Item, ttl time.Duration) {
+	if c.cfg.MaxEntries > 0 && len(c.entries) >= c.cfg.MaxEntries {
+		now := c.now()
+		for key, entry := range c.entries {
+			if !now.Before(entry.expires) {
+				delete(c.entries, key)
+			}
+		}
+		for key := range c.entries {
+			if len(c.entries) < c.cfg.MaxEntries {
+				break
+			}
+			delete(c.entries, key)
+		}
+	}
+	c.entries[id] = cacheEntry{item: item, expires: c.now().Add(ttl)}
+}
+
+// invalidate remove o item do cache e descarta o resultado de uma consulta em andamento
+func (c *itemCache) invalidate(id string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.entries, id)
+	if f, ok := c.flights[id]; ok {
+		f.stale = true
+	}
+}
+
+// copyItem retorna uma cópia do item, ou NotFound para IDs inexistentes em cache
+func copyItem(item *models.Item, err error) (*models.Item, error) {
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, errors.NewNotFoundError("Item não encontrado", nil)
+	}
+	copied := *item
+	return &copied, nil
+}
+
+// isNotFound indica se o erro é um AppError NotFound
+func isNotFound(err error) bool {
+	appErr, ok := err.(*errors.AppError)
+	return ok && appErr.Type == "NOT_FOUND"
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"callable-api/internal/models"
+	"callable-api/internal/repository"
+)
+
+// countingItemRepository conta as buscas por ID e pode segurá-las até que gate seja fechado
+type countingItemRepository struct {
+	repository.ItemRepository
+	lookups atomic.Int32
+	gate    chan struct{}
+}
+
+func (r *countingItemRepository) FindByID(ctx context.Context, id string) (*models.Item, error) {
+	r.lookups.Add(1)
+	if r.gate != nil {
+		<-r.gate
+	}
+	return r.ItemRepository.FindByID(ctx, id)
+}
+
+func TestItemCache(t *testing.T) {
+	ctx := context.Background()
+	repo := &countingItemRepository{ItemRepository: repository.NewInMemoryItemRepository(), gate: make(chan struct{})}
+	itemService := NewItemService(repo).WithItemCache(DefaultItemCacheConfig())
+	admin := models.Viewer{UserID: "admin", Role: "admin"}
+
+	// Buscas simultâneas pelo mesmo ID compartilham uma única consulta
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			item, err := itemService.GetItemByID(ctx, models.Viewer{}, "1")
+			assert.NoError(t, err)
+			assert.Equal(t, "Item 1", item.Name)
+		}()
+	}
+	require.Eventually(t, func() bool { return repo.lookups.Load() == 1 }, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	close(repo.gate)
+	wg.Wait()
+	assert.Equal(t, int32(1), repo.lookups.Load())
+
+	// IDs inexistentes também ficam em cache
+	for i := 0; i < 3; i++ {
+		_, err := itemService.GetItemByID(ctx, models.Viewer{}, "999")
+		assert.Equal(t, "NOT_FOUND", appErrorType(err))
+	}
+	assert.Equal(t, int32(2), repo.lookups.Load())
+
+	// Alterar o item retornado não altera o cache; as escritas invalidam a entrada
+	item, _ := itemService.GetItemByID(ctx, admin, "1")
+	item.Name = "alterado"
+	_, err := itemService.UpdateItem(ctx, admin, "1", &models.InputData{Name: "Novo nome", Value: "v", Email: "user@example.com"})
+	require.NoError(t, err)
+	item, err = itemService.GetItemByID(ctx, models.Viewer{}, "1")
+	require.NoError(t, err)
+	assert.Equal(t, "Novo nome", item.Name)
+
+	// Itens criados com um ID antes inexistente deixam de ser NOT_FOUND
+	_, err = itemService.GetItemByID(ctx, admin, "11")
+	assert.Equal(t, "NOT_FOUND", appErrorType(err))
+	created, err := itemService.CreateItem(ctx, admin, &models.InputData{Name: "Novo item", Value: "v", Email: "user@example.com"})
+	require.NoError(t, err)
+	require.Equal(t, "11", created.ID)
+	_, err = itemService.GetItemByID(ctx, admin, "11")
+	assert.NoError(t, err)
+}
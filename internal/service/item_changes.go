@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+
+	"callable-api/internal/changes"
+	"callable-api/internal/models"
+)
+
+// WithChangeFeed habilita a leitura do log de alterações dos itens. O feed
+// deve estar inscrito no mesmo barramento informado em WithEvents
+func (s *ItemService) WithChangeFeed(feed *changes.Feed) *ItemService {
+	s.changes = feed
+	return s
+}
+
+// ItemChanges retorna as alterações posteriores à sequência since dos itens
+// visíveis ao usuário e as remoções dos itens que ele deixou de ler com a
+// revogação de um compartilhamento. Sem log configurado, a leitura é sempre
+// vazia
+func (s *ItemService) ItemChanges(ctx context.Context, viewer models.Viewer, since int64, limit int) (*models.ItemChangePage, error) {
+	if s.changes == nil {
+		return &models.ItemChangePage{Changes: []models.ItemChange{}, NextCursor: "0"}, nil
+	}
+
+	access, err := s.itemAccess(ctx, viewer)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.changes.Since(since, limit, func(change models.ItemChange) bool {
+		item := &models.Item{ID: change.ItemID, OwnerID: change.OwnerID, OrgID: change.OrgID}
+		if change.Operation == models.ItemChangeRemoved {
+			// Quem ainda lê o item por outro caminho (dono, organização ou
+			// outro compartilhamento) não recebe a remoção
+			return change.RemovedFor(viewer) && !access.CanRead(item)
+		}
+		return access.CanRead(item)
+	})
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"callable-api/internal/changes"
+	"callable-api/internal/events"
+	"callable-api/internal/models"
+	"callable-api/internal/repository"
+	"callable-api/internal/repository/testsupport"
+)
+
+func TestItemChanges(t *testing.T) {
+	ctx := context.Background()
+	bus := events.NewBus()
+	feed := changes.NewFeed(changes.DefaultConfig())
+	bus.Subscribe(feed.HandleEvent)
+
+	users := testsupport.NewUserRepository()
+	itemService := NewItemService(repository.NewEmptyInMemoryItemRepository()).
+		WithSharing(repository.NewInMemoryItemShareRepository(), users).
+		WithEvents(bus).
+		WithChangeFeed(feed)
+
+	ownerUser := seededUser(t, users, "user@example.com")
+	owner := models.Viewer{UserID: ownerUser.ID, Role: ownerUser.Role}
+	other := models.Viewer{UserID: "outro", Role: "user"}
+
+	item, err := itemService.CreateItem(ctx, owner, &models.InputData{Name: "Relatório", Value: "v1", Email: "user@example.com"})
+	require.NoError(t, err)
+	_, err = itemService.UpdateItem(ctx, owner, item.ID, &models.InputData{Name: "Relatório final", Value: "v2", Email: "user@example.com"})
+	require.NoError(t, err)
+
+	page, err := itemService.ItemChanges(ctx, owner, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, page.Changes, 2)
+	assert.Equal(t, models.ItemChangeCreated, page.Changes[0].Operation)
+	assert.Equal(t, models.ItemChangeUpdated, page.Changes[1].Operation)
+	assert.Equal(t, item.ID, page.Changes[1].ItemID)
+
+	// As alterações seguem a visibilidade dos itens, mas o cursor avança
+	page, err = itemService.ItemChanges(ctx, other, 0, 10)
+	require.NoError(t, err)
+	assert.Empty(t, page.Changes)
+	assert.Equal(t, "2", page.NextCursor)
+
+	page, err = itemService.ItemChanges(ctx, models.Viewer{UserID: "admin-id", Role: "admin"}, 1, 10)
+	require.NoError(t, err)
+	assert.Len(t, page.Changes, 1)
+
+	// Revogar o compartilhamento registra a remoção apenas para quem perdeu o acesso
+	collaboratorUser, err := users.Create(&models.User{Email: "colaborador@example.com", Name: "Colaborador", Role: "user"})
+	require.NoError(t, err)
+	collaborator := models.Viewer{UserID: collaboratorUser.ID, Role: collaboratorUser.Role}
+	share, err := itemService.ShareItem(ctx, owner, item.ID, &models.ShareItemInput{UserID: collaborator.UserID, Level: models.ShareLevelRead})
+	require.NoError(t, err)
+	require.NoError(t, itemService.RevokeShare(ctx, owner, item.ID, share.ID))
+
+	page, err = itemService.ItemChanges(ctx, collaborator, 2, 10)
+	require.NoError(t, err)
+	require.Len(t, page.Changes, 1)
+	assert.Equal(t, models.ItemChangeRemoved, page.Changes[0].Operation)
+	assert.Equal(t, item.ID, page.Changes[0].ItemID)
+
+	// O dono continua lendo o item e não recebe a remoção
+	page, err = itemService.ItemChanges(ctx, owner, 2, 10)
+	require.NoError(t, err)
+	assert.Empty(t, page.Changes)
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"strings"
+
+	"callable-api/internal/models"
+	"callable-api/pkg/errors"
+	"callable-api/pkg/logger"
+)
+
+// DuplicateRule define quando um novo item é considerado duplicado
+type DuplicateRule string
+
+const (
+	// DuplicateRuleNone desativa a verificação
+	DuplicateRuleNone DuplicateRule = "none"
+	// DuplicateRuleNameOwner recusa itens com o nome de outro item do mesmo dono
+	DuplicateRuleNameOwner DuplicateRule = "name_owner"
+	// DuplicateRuleValue recusa itens com o valor de qualquer item existente
+	DuplicateRuleValue DuplicateRule = "value"
+)
+
+// ErrDuplicateItem é a causa do erro Conflict de itens duplicados
+var ErrDuplicateItem = stderrors.New("item duplicado")
+
+// DuplicateItemError aponta o item existente que conflita com o novo item.
+// ItemID fica vazio quando o usuário não pode ler o item existente
+type DuplicateItemError struct {
+	ItemID string
+}
+
+func (e *DuplicateItemError) Error() string {
+	if e.ItemID == "" {
+		return ErrDuplicateItem.Error()
+	}
+	return fmt.Sprintf("%s: %s", ErrDuplicateItem, e.ItemID)
+}
+
+// Is permite reconhecer o erro com errors.Is(err, ErrDuplicateItem)
+func (e *DuplicateItemError) Is(target error) bool {
+	return target == ErrDuplicateItem
+}
+
+// ParseDuplicateRule interpreta a regra de duplicidade ("" equivale a none)
+func ParseDuplicateRule(value string) (DuplicateRule, error) {
+	switch rule := DuplicateRule(strings.ToLower(strings.TrimSpace(value))); rule {
+	case "", DuplicateRuleNone:
+		return DuplicateRuleNone, nil
+	case DuplicateRuleNameOwner, DuplicateRuleValue:
+		return rule, nil
+	default:
+		return DuplicateRuleNone, fmt.Errorf("regra de duplicidade desconhecida: %q", value)
+	}
+}
+
+// WithDuplicateRule ativa a verificação de itens duplicados na criação
+func (s *ItemService) WithDuplicateRule(rule DuplicateRule) *ItemService {
+	s.duplicateRule = rule
+	return s
+}
+
+// checkDuplicate recusa o novo item se ele conflitar com um item existente
+// segundo a regra configurada. Administradores podem forçar a criação
+func (s *ItemService) checkDuplicate(ctx context.Context, viewer models.Viewer, input *models.InputData) error {
+	if input.Force {
+		if !viewer.IsAdmin() {
+			return errors.NewForbiddenError("Apenas administradores podem ignorar a verificação de duplicidade", nil)
+		}
+		return nil
+	}
+
+	var existing *models.Item
+	var err error
+	switch s.duplicateRule {
+	case DuplicateRuleNameOwner:
+		existing, err = s.repo.FindByName(ctx, viewer.UserID, input.Name)
+	case DuplicateRuleValue:
+		existing, err = s.repo.FindByValue(ctx, input.Value)
+	default:
+		return nil
+	}
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return errors.NewInternalServerError("Falha ao verificar itens duplicados", err)
+	}
+	if existing == nil {
+		return nil
+	}
+
+	logger.Info("Item duplicado recusado", map[string]interface{}{
+		"rule":       string(s.duplicateRule),
+		"existingId": existing.ID,
+		"userId":     viewer.UserID,
+	})
+
+	// Só aponta o item existente a quem pode lê-lo, para não revelar itens privados
+	duplicate := &DuplicateItemError{}
+	level, err := s.accessLevel(ctx, viewer, existing)
+	if err != nil {
+		return err
+	}
+	if level != "" {
+		duplicate.ItemID = existing.ID
+	}
+	return errors.NewConflictError("Já existe um item equivalente", duplicate)
+}
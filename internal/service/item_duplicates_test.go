@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"callable-api/internal/models"
+	"callable-api/internal/repository"
+)
+
+func TestDuplicateItems(t *testing.T) {
+	ctx := context.Background()
+	owner := models.Viewer{UserID: "owner", Role: "user"}
+	other := models.Viewer{UserID: "other", Role: "user"}
+	admin := models.Viewer{UserID: "admin", Role: "admin"}
+	input := func(name, value string) *models.InputData {
+		return &models.InputData{Name: name, Value: value, Email: "user@example.com"}
+	}
+
+	t.Run("name_owner", func(t *testing.T) {
+		itemService := NewItemService(repository.NewEmptyInMemoryItemRepository()).WithDuplicateRule(DuplicateRuleNameOwner)
+		existing, err := itemService.CreateItem(ctx, owner, input("Relatório", "v1"))
+		require.NoError(t, err)
+
+		_, err = itemService.CreateItem(ctx, owner, input(" relatório ", "v2"))
+		assert.Equal(t, "CONFLICT", appErrorType(err))
+		var duplicate *DuplicateItemError
+		require.ErrorAs(t, err, &duplicate)
+		assert.Equal(t, existing.ID, duplicate.ItemID)
+		assert.ErrorIs(t, err, ErrDuplicateItem)
+
+		// O mesmo nome é permitido para outro dono
+		_, err = itemService.CreateItem(ctx, other, input("Relatório", "v1"))
+		assert.NoError(t, err)
+	})
+
+	t.Run("value", func(t *testing.T) {
+		itemService := NewItemService(repository.NewEmptyInMemoryItemRepository()).WithDuplicateRule(DuplicateRuleValue)
+		_, err := itemService.CreateItem(ctx, owner, input("Relatório", "ABC-1"))
+		require.NoError(t, err)
+
+		// O item privado de outro usuário não é revelado
+		_, err = itemService.CreateItem(ctx, other, input("Outro", "ABC-1"))
+		var duplicate *DuplicateItemError
+		require.ErrorAs(t, err, &duplicate)
+		assert.Empty(t, duplicate.ItemID)
+
+		// Apenas administradores forçam a criação
+		forced := input("Outro", "ABC-1")
+		forced.Force = true
+		_, err = itemService.CreateItem(ctx, other, forced)
+		assert.Equal(t, "FORBIDDEN", appErrorType(err))
+		_, err = itemService.CreateItem(ctx, admin, forced)
+		assert.NoError(t, err)
+	})
+
+	t.Run("none", func(t *testing.T) {
+		itemService := NewItemService(repository.NewEmptyInMemoryItemRepository())
+		_, err := itemService.CreateItem(ctx, owner, input("Relatório", "v1"))
+		require.NoError(t, err)
+		_, err = itemService.CreateItem(ctx, owner, input("Relatório", "v1"))
+		assert.NoError(t, err)
+	})
+
+	rule, err := ParseDuplicateRule("VALUE")
+	assert.NoError(t, err)
+	assert.Equal(t, DuplicateRuleValue, rule)
+	_, err = ParseDuplicateRule("email")
+	assert.Error(t, err)
+}
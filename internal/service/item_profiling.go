@@ -0,0 +1,50 @@
+package service
+
+import (
+	"callable-api/pkg/errors"
+	"context"
+	stderrors "errors"
+)
+
+// Profiler mede a latência e as alocações das operações dos serviços (ver
+// metrics.Profiler)
+type Profiler interface {
+	Start(component, operation string) func(failed bool)
+}
+
+// WithProfiler ativa a medição de GetItems e CreateItem, exibida no painel de
+// operações. Serve para comparar o custo das listagens e da criação de itens
+// entre os repositórios e as configurações de cache
+func (s *ItemService) WithProfiler(profiler Profiler) *ItemService {
+	s.profiler = profiler
+	return s
+}
+
+// profile marca o início de uma operação do serviço de itens. A função
+// retornada recebe o erro de retorno da operação (para uso com defer); sem
+// Profiler, não faz nada
+func (s *ItemService) profile(operation string) func(err *error) {
+	if s.profiler == nil {
+		return func(*error) {}
+	}
+	done := s.profiler.Start("items", operation)
+	return func(err *error) {
+		done(isUnexpected(*err))
+	}
+}
+
+// isUnexpected indica se o erro é uma falha inesperada. Erros de validação,
+// de acesso, de registros inexistentes e requisições canceladas fazem parte do
+// uso normal
+func isUnexpected(err error) bool {
+	if err == nil || stderrors.Is(err, context.Canceled) {
+		return false
+	}
+	switch e := err.(type) {
+	case *errors.ValidationError:
+		return false
+	case *errors.AppError:
+		return e.Type == "INTERNAL_SERVER"
+	}
+	return true
+}
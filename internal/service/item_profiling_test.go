@@ -0,0 +1,59 @@
+package service
+
+import (
+	"callable-api/internal/metrics"
+	"callable-api/internal/models"
+	"callable-api/internal/repository"
+	"callable-api/pkg/errors"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestItemService_Profiling(t *testing.T) {
+	ctx := context.Background()
+	registry := metrics.NewRegistry()
+	svc := NewItemService(repository.NewEmptyInMemoryItemRepository()).
+		WithProfiler(metrics.NewProfiler(registry, 1))
+	viewer := models.Viewer{UserID: "user-1", Role: "user"}
+
+	_, err := svc.CreateItem(ctx, viewer, &models.InputData{Name: "Caderno", Value: "100", Email: "ana@example.com"})
+	require.NoError(t, err)
+
+	// Erros de validação não contam como falha
+	_, err = svc.CreateItem(ctx, viewer, &models.InputData{Name: "", Value: "100", Email: "ana@example.com"})
+	require.Error(t, err)
+
+	_, _, err = svc.GetItems(ctx, viewer, 1, 10)
+	require.NoError(t, err)
+
+	stats := registry.Snapshot()
+	require.Len(t, stats, 2)
+	assert.Equal(t, "items", stats[0].Component)
+	assert.Equal(t, "CreateItem", stats[0].Operation)
+	assert.Equal(t, int64(2), stats[0].Calls)
+	assert.Zero(t, stats[0].Errors)
+	assert.Equal(t, int64(2), stats[0].AllocSamples)
+	assert.Greater(t, stats[0].AvgAllocs, 0.0)
+	assert.Equal(t, "GetItems", stats[1].Operation)
+	assert.Equal(t, int64(1), stats[1].Calls)
+}
+
+func TestItemService_ProfilingRecordsFailures(t *testing.T) {
+	mockRepo := new(MockItemRepository)
+	mockRepo.On("FindAll", mock.Anything, mock.Anything, 1, 10).Return(nil, 0, errors.NewInternalServerError("erro de banco de dados", nil))
+
+	registry := metrics.NewRegistry()
+	svc := NewItemService(mockRepo).WithProfiler(metrics.NewProfiler(registry, 0))
+
+	_, _, err := svc.GetItems(context.Background(), models.Viewer{}, 1, 10)
+	assert.Error(t, err)
+
+	stats := registry.Snapshot()
+	require.Len(t, stats, 1)
+	assert.Equal(t, int64(1), stats[0].Errors)
+	assert.Zero(t, stats[0].AllocSamples)
+}
@@ -1,16 +1,34 @@
 package service
 
 import (
+	"callable-api/internal/changes"
+	"callable-api/internal/events"
+	"callable-api/internal/filter"
+	"callable-api/internal/messages"
 	"callable-api/internal/models"
 	"callable-api/internal/repository"
+	"callable-api/internal/search"
+	"callable-api/internal/validation"
 	"callable-api/pkg/errors"
 	"callable-api/pkg/logger"
+	"context"
 	"strings"
 )
 
-// ItemService gerencia a lógica de negócios relacionada a itens
+// ItemService gerencia a lógica de negócios relacionada a itens. Itens criados
+// por usuários autenticados pertencem a eles e só são visíveis ao dono, aos
+// administradores e a quem o dono os compartilhou; itens sem dono são públicos
 type ItemService struct {
-	repo repository.ItemRepository
+	repo     repository.ItemRepository
+	indexer  search.Indexer
+	shares   repository.ItemShareRepository
+	users    repository.UserRepository
+	events   events.Publisher
+	changes  *changes.Feed
+	
+	duplicateRule DuplicateRule
+	cache         *itemCache
+	profiler      Profiler
 }
 
 // NewItemService cria uma nova instância do ItemService
@@ -20,23 +38,48 @@ func NewItemService(repo repository.ItemRepository) *ItemService {
 	}
 }
 
-// GetItems retorna uma lista paginada de itens
-func (s *ItemService) GetItems(page, limit int) ([]models.Item, int, error) {
+// WithSearchIndexer ativa o espelhamento dos itens em um mecanismo de busca externo
+func (s *ItemService) WithSearchIndexer(indexer search.Indexer) *ItemService {
+	s.indexer = indexer
+	return s
+}
+
+// WithEvents publica os eventos de domínio dos itens (item.created,
+// item.updated, item.shared e item.unshared)
+func (s *ItemService) WithEvents(publisher events.Publisher) *ItemService {
+	s.events = publisher
+	return s
+}
+
+// GetItems retorna uma lista paginada dos itens visíveis ao usuário
+func (s *ItemService) GetItems(ctx context.Context, viewer models.Viewer, page, limit int, conditions ...filter.Condition) (items []models.Item, total int, err error) {
+	defer s.profile("GetItems")(&err)
+	
 	logger.Info("Buscando lista de itens", map[string]interface{}{
-		"page":  page,
-		"limit": limit,
+		"page":    page,
+		"limit":   limit,
+		"filters": len(conditions),
 	})
 	
-	items, total, err := s.repo.FindAll(page, limit)
+	access, err := s.itemAccess(ctx, viewer)
+	if err != nil {
+		return nil, 0, err
+	}
+	
+	items, total, err = s.repo.FindAll(ctx, access, page, limit, conditions...)
 	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, 0, ctxErr
+		}
 		return nil, 0, errors.NewInternalServerError("Falha ao buscar itens", err)
 	}
 	
 	return items, total, nil
 }
 
-// GetItemByID retorna um item específico pelo ID
-func (s *ItemService) GetItemByID(id string) (*models.Item, error) {
+// GetItemByID retorna um item específico pelo ID. Itens que o usuário não pode
+// ler são tratados como inexistentes
+func (s *ItemService) GetItemByID(ctx context.Context, viewer models.Viewer, id string) (*models.Item, error) {
 	if id == "" {
 		return nil, errors.NewBadRequestError("ID não fornecido", nil)
 	}
@@ -45,60 +88,185 @@ func (s *ItemService) GetItemByID(id string) (*models.Item, error) {
 		"id": id,
 	})
 	
-	item, err := s.repo.FindByID(id)
+	item, _, err := s.findAccessible(ctx, viewer, id, models.ShareLevelRead)
 	if err != nil {
-		// O repositório já retorna um erro NotFound se não encontrar
 		return nil, err
 	}
 	
 	return item, nil
 }
 
-// validateEmail realiza uma validação simples de email
-func validateEmail(email string) bool {
-	return strings.Contains(email, "@") && strings.Contains(email, ".")
+// CreateItem cria um novo item pertencente ao usuário
+func (s *ItemService) CreateItem(ctx context.Context, viewer models.Viewer, input *models.InputData) (item *models.Item, err error) {
+	defer s.profile("CreateItem")(&err)
+	
+	// As regras vêm das tags binding de InputData, as mesmas usadas pelo handler
+	if err := validation.Validate(input); err != nil {
+		return nil, newValidationError(messages.Locale(ctx), err)
+	}
+	
+	logger.Info("Criando novo item", map[string]interface{}{
+		"name": input.Name,
+		"email": input.Email,
+	})
+	
+	if err := s.checkDuplicate(ctx, viewer, input); err != nil {
+		return nil, err
+	}
+	
+	input.OwnerID = viewer.UserID
+	input.OrgID = viewer.OrgID
+	item, err = s.repo.Create(ctx, input)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, errors.NewInternalServerError("Falha ao criar item", err)
+	}
+	
+	// O ID pode estar em cache como inexistente
+	s.invalidateItem(item.ID)
+	s.indexItem(ctx, item)
+	
+	if s.events != nil {
+		s.events.Publish(ctx, events.ItemCreated{ItemID: item.ID, OwnerID: item.OwnerID, OrgID: item.OrgID, Name: item.Name})
+	}
+	
+	return item, nil
 }
 
-// CreateItem cria um novo item
-func (s *ItemService) CreateItem(input *models.InputData) (*models.Item, error) {
-	// Validar input usando o novo sistema de erros de validação
-	validationErr := errors.NewValidationError("Dados de entrada inválidos")
-	validInputs := true
+// UpdateItem substitui os dados do item. Exige acesso de escrita: o dono, os
+// administradores ou um compartilhamento de nível write
+func (s *ItemService) UpdateItem(ctx context.Context, viewer models.Viewer, id string, input *models.InputData) (*models.Item, error) {
+	if err := validation.Validate(input); err != nil {
+		return nil, newValidationError(messages.Locale(ctx), err)
+	}
+	
+	item, _, err := s.findAccessible(ctx, viewer, id, models.ShareLevelWrite)
+	if err != nil {
+		return nil, err
+	}
+	
+	logger.Info("Atualizando item", map[string]interface{}{
+		"id":     id,
+		"userId": viewer.UserID,
+	})
 	
-	if input.Name == "" {
-		validationErr.AddFieldError("name", "Nome é obrigatório")
-		validInputs = false
-	} else if len(input.Name) < 3 {
-		validationErr.AddFieldError("name", "Nome deve ter pelo menos 3 caracteres")
-		validInputs = false
+	item.Name = input.Name
+	item.Value = input.Value
+	item.Description = input.Description
+	item.Email = input.Email
+	
+	updated, err := s.repo.Update(ctx, item)
+	s.invalidateItem(id)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		if appErr, ok := err.(*errors.AppError); ok && appErr.Type == "NOT_FOUND" {
+			return nil, err
+		}
+		return nil, errors.NewInternalServerError("Falha ao atualizar item", err)
 	}
 	
-	if input.Email == "" {
-		validationErr.AddFieldError("email", "Email é obrigatório")
-		validInputs = false
-	} else if !validateEmail(input.Email) {
-		validationErr.AddFieldError("email", "Email inválido")
-		validInputs = false
+	s.indexItem(ctx, updated)
+	
+	if s.events != nil {
+		s.events.Publish(ctx, events.ItemUpdated{ItemID: updated.ID, OwnerID: updated.OwnerID, OrgID: updated.OrgID, Name: updated.Name})
 	}
 	
-	if input.Value == "" {
-		validationErr.AddFieldError("value", "Valor é obrigatório")
-		validInputs = false
+	return updated, nil
+}
+
+// indexItem espelha o item no mecanismo de busca; falhas de indexação não
+// invalidam a escrita, que já foi persistida no repositório
+func (s *ItemService) indexItem(ctx context.Context, item *models.Item) {
+	if s.indexer == nil {
+		return
 	}
 	
-	if !validInputs {
-		return nil, validationErr
+	if err := s.indexer.IndexItem(ctx, item); err != nil {
+		logger.Warn("Falha ao indexar item no mecanismo de busca", map[string]interface{}{
+			"id":    item.ID,
+			"error": err.Error(),
+		})
+	}
+}
+
+// SearchItems busca, entre os itens visíveis ao usuário, itens por texto
+// completo, usando o mecanismo de busca quando configurado e o usuário pode ler
+// todos os itens, e o repositório nos demais casos
+func (s *ItemService) SearchItems(ctx context.Context, viewer models.Viewer, query string, page, limit int) ([]models.Item, int, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, 0, errors.NewBadRequestError("Parâmetro de busca 'q' não fornecido", nil)
 	}
 	
-	logger.Info("Criando novo item", map[string]interface{}{
-		"name": input.Name,
-		"email": input.Email,
+	logger.Info("Buscando itens", map[string]interface{}{
+		"query": query,
+		"page":  page,
+		"limit": limit,
 	})
 	
-	item, err := s.repo.Create(input)
+	access, err := s.itemAccess(ctx, viewer)
 	if err != nil {
-		return nil, errors.NewInternalServerError("Falha ao criar item", err)
+		return nil, 0, err
 	}
 	
-	return item, nil
-}
\ No newline at end of file
+	// O índice não conhece donos nem compartilhamentos, e só atende quem lê
+	// todos os itens: para os demais, filtrar as páginas do índice deixaria
+	// páginas incompletas e um total que revela a existência de itens privados
+	if s.indexer != nil && access.All {
+		items, total, err := s.indexer.Search(ctx, query, page, limit)
+		if err == nil {
+			err = s.loadHits(ctx, items)
+		}
+		if err == nil {
+			return items, total, nil
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, 0, ctxErr
+		}
+		logger.Warn("Mecanismo de busca indisponível, usando busca do repositório", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+	
+	items, total, err := s.repo.Search(ctx, access, query, page, limit)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, 0, ctxErr
+		}
+		return nil, 0, errors.NewInternalServerError("Falha ao buscar itens", err)
+	}
+	
+	return items, total, nil
+}
+
+// loadHits substitui os resultados do índice, que trazem apenas os campos
+// indexados, pelos itens completos do repositório
+func (s *ItemService) loadHits(ctx context.Context, hits []models.Item) error {
+	for i := range hits {
+		item, err := s.findItem(ctx, hits[i].ID)
+		if err != nil {
+			return err
+		}
+		hits[i] = *item
+	}
+	return nil
+}
+
+// newValidationError converte as falhas do pacote validation em um
+// ValidationError, com as mensagens no idioma informado
+func newValidationError(locale string, err error) error {
+	errs, ok := err.(validation.Errors)
+	if !ok {
+		return errors.NewBadRequestError("Dados de entrada inválidos", err)
+	}
+	
+	validationErr := errors.NewValidationError("Dados de entrada inválidos")
+	for _, fe := range errs.Localize(locale) {
+		validationErr.AddFieldError(fe.Field, fe.Message)
+	}
+	return validationErr
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"callable-api/internal/metrics"
+	"callable-api/internal/models"
+	"callable-api/internal/repository"
+	"callable-api/internal/repository/testsupport"
+	"callable-api/internal/synthetic"
+	"callable-api/pkg/logger"
+	"context"
+	"fmt"
+	"testing"
+)
+
+// Os benchmarks medem o serviço de itens sobre o repositório em memória, com
+// e sem o cache e o Profiler, para comparar o custo de cada camada:
+//
+//	go test -run '^$' -bench . -benchmem ./internal/service/
+
+// benchmarkItems cria um repositório com n itens sintéticos e retorna um dos
+// usuários donos dos itens
+func benchmarkItems(b *testing.B, n int) (*repository.InMemoryItemRepository, models.Viewer) {
+	b.Helper()
+	logger.SetLevel("warn")
+
+	users := testsupport.NewUserRepository()
+	items := repository.NewEmptyInMemoryItemRepository()
+	if _, err := synthetic.Seed(context.Background(), synthetic.Config{Users: 10, Items: n}, users, items); err != nil {
+		b.Fatal(err)
+	}
+	user, err := users.FindByEmail(synthetic.UserEmail(1))
+	if err != nil {
+		b.Fatal(err)
+	}
+	return items, models.Viewer{UserID: user.ID, Role: user.Role}
+}
+
+func BenchmarkItemService_GetItems(b *testing.B) {
+	ctx := context.Background()
+	for _, n := range []int{1000, 10000} {
+		items, viewer := benchmarkItems(b, n)
+		for _, tc := range []struct {
+			name   string
+			viewer models.Viewer
+		}{
+			{"admin", models.Viewer{UserID: "admin", Role: "admin"}},
+			{"owner", viewer},
+		} {
+			b.Run(fmt.Sprintf("items=%d/%s", n, tc.name), func(b *testing.B) {
+				svc := NewItemService(items)
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					if _, _, err := svc.GetItems(ctx, tc.viewer, i%10+1, 50); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkItemService_GetItemByID(b *testing.B) {
+	ctx := context.Background()
+	items, viewer := benchmarkItems(b, 10000)
+	page, _, err := items.FindAll(ctx, models.ItemAccess{UserID: viewer.UserID}, 1, 100)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name  string
+		cache ItemCacheConfig
+	}{
+		{"uncached", ItemCacheConfig{}},
+		{"cached", DefaultItemCacheConfig()},
+	} {
+		b.Run(tc.name, func(b *testing.B) {
+			svc := NewItemService(items).WithItemCache(tc.cache)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := svc.GetItemByID(ctx, viewer, page[i%len(page)].ID); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkItemService_CreateItem(b *testing.B) {
+	ctx := context.Background()
+	viewer := models.Viewer{UserID: "user-1", Role: "user"}
+
+	// O custo do Profiler, com e sem a medição das alocações de cada chamada
+	for _, tc := range []struct {
+		name     string
+		profiler func() Profiler
+	}{
+		{"profiling=off", func() Profiler { return nil }},
+		{"profiling=latency", func() Profiler { return metrics.NewProfiler(metrics.NewRegistry(), 0) }},
+		{"profiling=allocs", func() Profiler { return metrics.NewProfiler(metrics.NewRegistry(), 1) }},
+	} {
+		b.Run(tc.name, func(b *testing.B) {
+			logger.SetLevel("warn")
+			svc := NewItemService(repository.NewEmptyInMemoryItemRepository())
+			if profiler := tc.profiler(); profiler != nil {
+				svc.WithProfiler(profiler)
+			}
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				input := &models.InputData{
+					Name:  fmt.Sprintf("Item %d", i),
+					Value: fmt.Sprintf("BENCH-%d", i),
+					Email: "bench@example.com",
+				}
+				if _, err := svc.CreateItem(ctx, viewer, input); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -1,10 +1,15 @@
 package service
 
 import (
+	"callable-api/internal/events"
+	"callable-api/internal/filter"
 	"callable-api/internal/models"
+	"callable-api/internal/repository"
 	"callable-api/pkg/errors"
+	"context"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -16,30 +21,85 @@ type MockItemRepository struct {
 }
 
 // Implementação dos métodos da interface repository.ItemRepository para o mock
-func (m *MockItemRepository) FindAll(page, limit int) ([]models.Item, int, error) {
-	args := m.Called(page, limit)
+func (m *MockItemRepository) FindAll(ctx context.Context, access models.ItemAccess, page, limit int, conditions ...filter.Condition) ([]models.Item, int, error) {
+	args := m.Called(ctx, access, page, limit)
 	if args.Get(0) == nil {
 		return nil, args.Int(1), args.Error(2)
 	}
 	return args.Get(0).([]models.Item), args.Int(1), args.Error(2)
 }
 
-func (m *MockItemRepository) FindByID(id string) (*models.Item, error) {
-	args := m.Called(id)
+func (m *MockItemRepository) FindByID(ctx context.Context, id string) (*models.Item, error) {
+	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*models.Item), args.Error(1)
 }
 
-func (m *MockItemRepository) Create(input *models.InputData) (*models.Item, error) {
-	args := m.Called(input)
+func (m *MockItemRepository) Create(ctx context.Context, input *models.InputData) (*models.Item, error) {
+	args := m.Called(ctx, input)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*models.Item), args.Error(1)
 }
 
+func (m *MockItemRepository) Update(ctx context.Context, item *models.Item) (*models.Item, error) {
+	args := m.Called(ctx, item)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Item), args.Error(1)
+}
+
+func (m *MockItemRepository) FindByName(ctx context.Context, ownerID, name string) (*models.Item, error) {
+	args := m.Called(ctx, ownerID, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Item), args.Error(1)
+}
+
+func (m *MockItemRepository) FindByValue(ctx context.Context, value string) (*models.Item, error) {
+	args := m.Called(ctx, value)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Item), args.Error(1)
+}
+
+func (m *MockItemRepository) Iterate(ctx context.Context, filter repository.ItemFilter, fn func(*models.Item) error) error {
+	args := m.Called(ctx, filter, fn)
+	return args.Error(0)
+}
+
+func (m *MockItemRepository) Search(ctx context.Context, access models.ItemAccess, query string, page, limit int) ([]models.Item, int, error) {
+	args := m.Called(ctx, access, query, page, limit)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]models.Item), args.Int(1), args.Error(2)
+}
+
+// Mock do indexador de busca
+type MockSearchIndexer struct {
+	mock.Mock
+}
+
+func (m *MockSearchIndexer) IndexItem(ctx context.Context, item *models.Item) error {
+	args := m.Called(ctx, item)
+	return args.Error(0)
+}
+
+func (m *MockSearchIndexer) Search(ctx context.Context, query string, page, limit int) ([]models.Item, int, error) {
+	args := m.Called(ctx, query, page, limit)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]models.Item), args.Int(1), args.Error(2)
+}
+
 // Helper para criar um item de teste
 func createTestItem() *models.Item {
 	return &models.Item{
@@ -74,13 +134,13 @@ func TestGetItems_Success(t *testing.T) {
 	totalItems := 10
 	
 	// Configurar comportamento do mock
-	mockRepo.On("FindAll", 1, 10).Return(testItems, totalItems, nil)
+	mockRepo.On("FindAll", mock.Anything, mock.Anything, 1, 10).Return(testItems, totalItems, nil)
 	
 	// Criar serviço com mock
 	itemService := NewItemService(mockRepo)
 	
 	// Chamar método
-	items, total, err := itemService.GetItems(1, 10)
+	items, total, err := itemService.GetItems(context.Background(), models.Viewer{}, 1, 10)
 	
 	// Verificações
 	assert.NoError(t, err)
@@ -95,13 +155,13 @@ func TestGetItems_Error(t *testing.T) {
 	mockRepo := new(MockItemRepository)
 	
 	// Configurar comportamento do mock para retornar erro
-	mockRepo.On("FindAll", 1, 10).Return(nil, 0, errors.NewInternalServerError("erro de banco de dados", nil))
+	mockRepo.On("FindAll", mock.Anything, mock.Anything, 1, 10).Return(nil, 0, errors.NewInternalServerError("erro de banco de dados", nil))
 	
 	// Criar serviço com mock
 	itemService := NewItemService(mockRepo)
 	
 	// Chamar método
-	items, total, err := itemService.GetItems(1, 10)
+	items, total, err := itemService.GetItems(context.Background(), models.Viewer{}, 1, 10)
 	
 	// Verificações
 	assert.Error(t, err)
@@ -125,13 +185,13 @@ func TestGetItemByID_Success(t *testing.T) {
 	testItem := createTestItem()
 	
 	// Configurar comportamento do mock
-	mockRepo.On("FindByID", "item123").Return(testItem, nil)
+	mockRepo.On("FindByID", mock.Anything, "item123").Return(testItem, nil)
 	
 	// Criar serviço com mock
 	itemService := NewItemService(mockRepo)
 	
 	// Chamar método
-	item, err := itemService.GetItemByID("item123")
+	item, err := itemService.GetItemByID(context.Background(), models.Viewer{}, "item123")
 	
 	// Verificações
 	assert.NoError(t, err)
@@ -148,7 +208,7 @@ func TestGetItemByID_EmptyID(t *testing.T) {
 	itemService := NewItemService(mockRepo)
 	
 	// Chamar método com ID vazio
-	item, err := itemService.GetItemByID("")
+	item, err := itemService.GetItemByID(context.Background(), models.Viewer{}, "")
 	
 	// Verificações
 	assert.Error(t, err)
@@ -168,13 +228,13 @@ func TestGetItemByID_NotFound(t *testing.T) {
 	mockRepo := new(MockItemRepository)
 	
 	// Configurar mock para retornar "não encontrado"
-	mockRepo.On("FindByID", "nonexistent").Return(nil, errors.NewNotFoundError("Item não encontrado", nil))
+	mockRepo.On("FindByID", mock.Anything, "nonexistent").Return(nil, errors.NewNotFoundError("Item não encontrado", nil))
 	
 	// Criar serviço com mock
 	itemService := NewItemService(mockRepo)
 	
 	// Chamar método
-	item, err := itemService.GetItemByID("nonexistent")
+	item, err := itemService.GetItemByID(context.Background(), models.Viewer{}, "nonexistent")
 	
 	// Verificações
 	assert.Error(t, err)
@@ -193,13 +253,13 @@ func TestGetItemByID_RepositoryError(t *testing.T) {
 	mockRepo := new(MockItemRepository)
 	
 	// Configurar mock para retornar erro de repositório
-	mockRepo.On("FindByID", "error").Return(nil, errors.NewInternalServerError("Erro de banco de dados", nil))
+	mockRepo.On("FindByID", mock.Anything, "error").Return(nil, errors.NewInternalServerError("Erro de banco de dados", nil))
 	
 	// Criar serviço com mock
 	itemService := NewItemService(mockRepo)
 	
 	// Chamar método
-	item, err := itemService.GetItemByID("error")
+	item, err := itemService.GetItemByID(context.Background(), models.Viewer{}, "error")
 	
 	// Verificações
 	assert.Error(t, err)
@@ -213,28 +273,6 @@ func TestGetItemByID_RepositoryError(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
-// Teste da função validateEmail
-func TestValidateEmail(t *testing.T) {
-	tests := []struct {
-		email string
-		valid bool
-	}{
-		{"test@example.com", true},
-		{"test@example", false},
-		{"test.example.com", false},
-		{"@example.com", true},  // Esta é uma validação simples que só verifica @ e .
-		{"test@.com", true},     // Idem
-		{"", false},
-	}
-	
-	for _, test := range tests {
-		t.Run(test.email, func(t *testing.T) {
-			result := validateEmail(test.email)
-			assert.Equal(t, test.valid, result, "Email: %s", test.email)
-		})
-	}
-}
-
 // Testes para CreateItem
 func TestCreateItem_Success(t *testing.T) {
 	// Configurar mock
@@ -256,13 +294,13 @@ func TestCreateItem_Success(t *testing.T) {
 	}
 	
 	// Configurar comportamento do mock
-	mockRepo.On("Create", input).Return(createdItem, nil)
+	mockRepo.On("Create", mock.Anything, input).Return(createdItem, nil)
 	
 	// Criar serviço com mock
 	itemService := NewItemService(mockRepo)
 	
 	// Chamar método
-	item, err := itemService.CreateItem(input)
+	item, err := itemService.CreateItem(context.Background(), models.Viewer{}, input)
 	
 	// Verificações
 	assert.NoError(t, err)
@@ -271,6 +309,25 @@ func TestCreateItem_Success(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestCreateItem_PublishesEvent(t *testing.T) {
+	mockRepo := new(MockItemRepository)
+	input := &models.InputData{Name: "New Item", Email: "new@example.com", Value: "150.00"}
+	mockRepo.On("Create", mock.Anything, input).Return(&models.Item{ID: "new123", Name: "New Item", OwnerID: "u1"}, nil)
+	
+	bus := events.NewBus()
+	var published []events.Event
+	bus.Subscribe(func(ctx context.Context, envelope *events.Envelope) {
+		event, err := events.Decode(envelope)
+		assert.NoError(t, err)
+		published = append(published, event)
+	})
+	
+	_, err := NewItemService(mockRepo).WithEvents(bus).CreateItem(context.Background(), models.Viewer{UserID: "u1"}, input)
+	
+	assert.NoError(t, err)
+	assert.Equal(t, []events.Event{&events.ItemCreated{ItemID: "new123", OwnerID: "u1", Name: "New Item"}}, published)
+}
+
 func TestCreateItem_ValidationError(t *testing.T) {
 	// Vários casos de teste para validação
 	tests := []struct {
@@ -334,7 +391,7 @@ func TestCreateItem_ValidationError(t *testing.T) {
 			itemService := NewItemService(mockRepo)
 			
 			// Chamar método
-			item, err := itemService.CreateItem(tt.input)
+			item, err := itemService.CreateItem(context.Background(), models.Viewer{}, tt.input)
 			
 			// Verificações
 			assert.Error(t, err)
@@ -372,13 +429,13 @@ func TestCreateItem_RepositoryError(t *testing.T) {
 	}
 	
 	// Configurar mock para retornar erro
-	mockRepo.On("Create", input).Return(nil, errors.NewInternalServerError("erro de banco de dados", nil))
+	mockRepo.On("Create", mock.Anything, input).Return(nil, errors.NewInternalServerError("erro de banco de dados", nil))
 	
 	// Criar serviço com mock
 	itemService := NewItemService(mockRepo)
 	
 	// Chamar método
-	item, err := itemService.CreateItem(input)
+	item, err := itemService.CreateItem(context.Background(), models.Viewer{}, input)
 	
 	// Verificações
 	assert.Error(t, err)
@@ -390,4 +447,117 @@ func TestCreateItem_RepositoryError(t *testing.T) {
 	assert.Equal(t, "INTERNAL_SERVER", appErr.Type)
 	
 	mockRepo.AssertExpectations(t)
-}
\ No newline at end of file
+}
+
+// Testes de propagação de prazo (deadline) do contexto
+func TestGetItems_DeadlineExceeded(t *testing.T) {
+	// Configurar mock que simula um repositório lento respeitando o contexto
+	mockRepo := new(MockItemRepository)
+	mockRepo.On("FindAll", mock.Anything, mock.Anything, 1, 10).Return(nil, 0, context.DeadlineExceeded)
+	
+	itemService := NewItemService(mockRepo)
+	
+	// Contexto com prazo já expirado
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+	
+	items, total, err := itemService.GetItems(ctx, models.Viewer{}, 1, 10)
+	
+	// O erro do contexto deve ser propagado sem ser convertido em erro interno
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Nil(t, items)
+	assert.Equal(t, 0, total)
+}
+
+// Testes para SearchItems
+func TestSearchItems_UsesIndexer(t *testing.T) {
+	mockRepo := new(MockItemRepository)
+	mockIndexer := new(MockSearchIndexer)
+	
+	// O índice devolve apenas os campos indexados; o item completo vem do repositório
+	testItems := createTestItems(2)
+	hits := make([]models.Item, len(testItems))
+	for i := range testItems {
+		hits[i] = models.Item{ID: testItems[i].ID, Name: testItems[i].Name, Value: testItems[i].Value}
+		mockRepo.On("FindByID", mock.Anything, testItems[i].ID).Return(&testItems[i], nil)
+	}
+	mockIndexer.On("Search", mock.Anything, "item", 1, 10).Return(hits, 2, nil)
+	
+	itemService := NewItemService(mockRepo).WithSearchIndexer(mockIndexer)
+	
+	admin := models.Viewer{UserID: "admin-id", Role: "admin"}
+	items, total, err := itemService.SearchItems(context.Background(), admin, " item ", 1, 10)
+	
+	assert.NoError(t, err)
+	assert.Equal(t, testItems, items)
+	assert.Equal(t, 2, total)
+	mockRepo.AssertNotCalled(t, "Search")
+}
+
+func TestSearchItems_FallsBackToRepository(t *testing.T) {
+	mockRepo := new(MockItemRepository)
+	mockIndexer := new(MockSearchIndexer)
+	
+	testItems := createTestItems(1)
+	mockIndexer.On("Search", mock.Anything, "item", 1, 10).Return(nil, 0, assert.AnError)
+	mockRepo.On("Search", mock.Anything, mock.Anything, "item", 1, 10).Return(testItems, 1, nil)
+	
+	itemService := NewItemService(mockRepo).WithSearchIndexer(mockIndexer)
+	
+	admin := models.Viewer{UserID: "admin-id", Role: "admin"}
+	items, total, err := itemService.SearchItems(context.Background(), admin, "item", 1, 10)
+	
+	assert.NoError(t, err)
+	assert.Equal(t, testItems, items)
+	assert.Equal(t, 1, total)
+}
+
+func TestSearchItems_RestrictedViewerSkipsIndexer(t *testing.T) {
+	mockRepo := new(MockItemRepository)
+	mockIndexer := new(MockSearchIndexer)
+	
+	// O índice não conhece a visibilidade dos itens: quem não lê todos usa o repositório
+	testItems := createTestItems(1)
+	viewer := models.Viewer{UserID: "user-id", Role: "user"}
+	access := models.ItemAccess{UserID: "user-id"}
+	mockRepo.On("Search", mock.Anything, access, "item", 1, 10).Return(testItems, 1, nil)
+	
+	itemService := NewItemService(mockRepo).WithSearchIndexer(mockIndexer)
+	
+	items, total, err := itemService.SearchItems(context.Background(), viewer, "item", 1, 10)
+	
+	assert.NoError(t, err)
+	assert.Equal(t, testItems, items)
+	assert.Equal(t, 1, total)
+	mockIndexer.AssertNotCalled(t, "Search", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSearchItems_EmptyQuery(t *testing.T) {
+	itemService := NewItemService(new(MockItemRepository))
+	
+	_, _, err := itemService.SearchItems(context.Background(), models.Viewer{}, "  ", 1, 10)
+	
+	appErr, ok := err.(*errors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, "BAD_REQUEST", appErr.Type)
+}
+
+func TestCreateItem_IndexesItem(t *testing.T) {
+	mockRepo := new(MockItemRepository)
+	mockIndexer := new(MockSearchIndexer)
+	
+	input := &models.InputData{Name: "Indexed Item", Value: "IDX", Email: "idx@example.com"}
+	createdItem := &models.Item{ID: "idx1", Name: input.Name, Value: input.Value, Email: input.Email}
+	mockRepo.On("Create", mock.Anything, input).Return(createdItem, nil)
+	// Falhas de indexação não devem impedir a criação
+	mockIndexer.On("IndexItem", mock.Anything, createdItem).Return(assert.AnError)
+	
+	itemService := NewItemService(mockRepo).WithSearchIndexer(mockIndexer)
+	
+	item, err := itemService.CreateItem(context.Background(), models.Viewer{}, input)
+	
+	assert.NoError(t, err)
+	assert.Equal(t, createdItem, item)
+	mockIndexer.AssertExpectations(t)
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+
+	"callable-api/internal/events"
+	"callable-api/internal/models"
+	"callable-api/internal/repository"
+	"callable-api/pkg/errors"
+	"callable-api/pkg/logger"
+)
+
+// WithSharing habilita o compartilhamento de itens com outros usuários e papéis
+func (s *ItemService) WithSharing(shares repository.ItemShareRepository, users repository.UserRepository) *ItemService {
+	s.shares = shares
+	s.users = users
+	return s
+}
+
+// itemAccess calcula quais itens o usuário pode ler
+func (s *ItemService) itemAccess(ctx context.Context, viewer models.Viewer) (models.ItemAccess, error) {
+	access := models.ItemAccess{All: viewer.IsAdmin(), UserID: viewer.UserID, OrgID: viewer.OrgID}
+	if access.All || viewer.UserID == "" || s.shares == nil {
+		return access, nil
+	}
+
+	shares, err := s.shares.ListForViewer(ctx, viewer)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return access, ctxErr
+		}
+		return access, errors.NewInternalServerError("Falha ao buscar compartilhamentos", err)
+	}
+
+	access.SharedIDs = make(map[string]bool, len(shares))
+	for _, share := range shares {
+		access.SharedIDs[share.ItemID] = true
+	}
+	return access, nil
+}
+
+// accessLevel retorna o nível de acesso do usuário ao item ("" se nenhum).
+// Donos, administradores e os membros da organização do item (com ela ativa)
+// têm acesso de escrita; itens sem dono são de leitura pública
+func (s *ItemService) accessLevel(ctx context.Context, viewer models.Viewer, item *models.Item) (string, error) {
+	if viewer.IsAdmin() || (viewer.UserID != "" && item.OwnerID == viewer.UserID) ||
+		(viewer.OrgID != "" && item.OrgID == viewer.OrgID) {
+		return models.ShareLevelWrite, nil
+	}
+
+	level := ""
+	if item.OwnerID == "" {
+		level = models.ShareLevelRead
+	}
+	if viewer.UserID == "" || s.shares == nil {
+		return level, nil
+	}
+
+	shares, err := s.shares.ListByItem(ctx, item.ID)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return "", ctxErr
+		}
+		return "", errors.NewInternalServerError("Falha ao buscar compartilhamentos", err)
+	}
+	for _, share := range shares {
+		if share.UserID != viewer.UserID && (share.Role == "" || share.Role != viewer.Role) {
+			continue
+		}
+		if share.Grants(models.ShareLevelWrite) {
+			return models.ShareLevelWrite, nil
+		}
+		level = models.ShareLevelRead
+	}
+	return level, nil
+}
+
+// findAccessible busca o item exigindo o nível de acesso informado. Itens que
+// o usuário não pode ler são tratados como inexistentes, para não revelar sua
+// existência; acesso insuficiente a um item visível resulta em 403
+func (s *ItemService) findAccessible(ctx context.Context, viewer models.Viewer, id, required string) (*models.Item, string, error) {
+	item, err := s.findItem(ctx, id)
+	if err != nil {
+		// O repositório já retorna um erro NotFound se não encontrar
+		// (ou o erro do contexto, se o prazo da requisição expirou)
+		return nil, "", err
+	}
+
+	level, err := s.accessLevel(ctx, viewer, item)
+	if err != nil {
+		return nil, "", err
+	}
+	switch {
+	case level == "":
+		return nil, "", errors.NewNotFoundError("Item não encontrado", nil)
+	case required == models.ShareLevelWrite && level != models.ShareLevelWrite:
+		return nil, "", errors.NewForbiddenError("Acesso de escrita ao item não concedido", nil)
+	}
+	return item, level, nil
+}
+
+// findOwned busca o item exigindo que o usuário seja o dono ou administrador,
+// os únicos que gerenciam os compartilhamentos
+func (s *ItemService) findOwned(ctx context.Context, viewer models.Viewer, id string) (*models.Item, error) {
+	if s.shares == nil {
+		return nil, errors.NewInternalServerError("Compartilhamento de itens não configurado", nil)
+	}
+
+	item, _, err := s.findAccessible(ctx, viewer, id, models.ShareLevelRead)
+	if err != nil {
+		return nil, err
+	}
+	if item.OwnerID == "" {
+		return nil, errors.NewBadRequestError("Itens públicos não podem ser compartilhados", nil)
+	}
+	if item.OwnerID != viewer.UserID && !viewer.IsAdmin() {
+		return nil, errors.NewForbiddenError("Apenas o dono do item pode gerenciar os compartilhamentos", nil)
+	}
+	return item, nil
+}
+
+// ShareItem compartilha o item com um usuário ou com todos os usuários de um
+// papel. Compartilhar de novo com o mesmo destinatário altera o nível de acesso
+func (s *ItemService) ShareItem(ctx context.Context, viewer models.Viewer, id string, input *models.ShareItemInput) (*models.ItemShare, error) {
+	item, err := s.findOwned(ctx, viewer, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.UserID != "" {
+		if input.UserID == item.OwnerID {
+			return nil, errors.NewBadRequestError("O dono do item já tem acesso total", nil)
+		}
+		if _, err := s.users.FindByID(input.UserID); err != nil {
+			return nil, errors.NewBadRequestError("Usuário de destino não encontrado", err)
+		}
+	}
+
+	share, err := s.shares.Save(ctx, &models.ItemShare{
+		ItemID:    item.ID,
+		UserID:    input.UserID,
+		Role:      input.Role,
+		Level:     input.Level,
+		GrantedBy: viewer.UserID,
+	})
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, errors.NewInternalServerError("Falha ao compartilhar item", err)
+	}
+
+	logger.Info("Item compartilhado", map[string]interface{}{
+		"itemId": item.ID,
+		"userId": share.UserID,
+		"role":   share.Role,
+		"level":  share.Level,
+	})
+
+	if s.events != nil {
+		s.events.Publish(ctx, events.ItemShared{
+			ItemID:    item.ID,
+			ItemName:  item.Name,
+			ShareID:   share.ID,
+			UserID:    share.UserID,
+			Role:      share.Role,
+			Level:     share.Level,
+			GrantedBy: share.GrantedBy,
+		})
+	}
+
+	return share, nil
+}
+
+// ListShares retorna os compartilhamentos do item
+func (s *ItemService) ListShares(ctx context.Context, viewer models.Viewer, id string) ([]models.ItemShare, error) {
+	item, err := s.findOwned(ctx, viewer, id)
+	if err != nil {
+		return nil, err
+	}
+
+	shares, err := s.shares.ListByItem(ctx, item.ID)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, errors.NewInternalServerError("Falha ao buscar compartilhamentos", err)
+	}
+	return shares, nil
+}
+
+// RevokeShare remove um compartilhamento do item
+func (s *ItemService) RevokeShare(ctx context.Context, viewer models.Viewer, id, shareID string) error {
+	item, err := s.findOwned(ctx, viewer, id)
+	if err != nil {
+		return err
+	}
+
+	// O compartilhamento identifica quem perde o acesso, para o log de alterações
+	shares, err := s.shares.ListByItem(ctx, item.ID)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return errors.NewInternalServerError("Falha ao buscar compartilhamentos", err)
+	}
+	var revoked models.ItemShare
+	for _, share := range shares {
+		if share.ID == shareID {
+			revoked = share
+		}
+	}
+
+	if err := s.shares.Delete(ctx, item.ID, shareID); err != nil {
+		return err
+	}
+
+	logger.Info("Compartilhamento de item revogado", map[string]interface{}{
+		"itemId":  item.ID,
+		"shareId": shareID,
+	})
+
+	if s.events != nil {
+		s.events.Publish(ctx, events.ItemUnshared{
+			ItemID:    item.ID,
+			OwnerID:   item.OwnerID,
+			OrgID:     item.OrgID,
+			ShareID:   shareID,
+			UserID:    revoked.UserID,
+			Role:      revoked.Role,
+			RevokedBy: viewer.UserID,
+		})
+	}
+	return nil
+}
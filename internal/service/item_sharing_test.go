@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"callable-api/internal/models"
+	"callable-api/internal/repository"
+	"callable-api/internal/repository/testsupport"
+	"callable-api/pkg/errors"
+)
+
+// appErrorType retorna o tipo do AppError (ou "" para outros erros)
+func appErrorType(err error) string {
+	if appErr, ok := err.(*errors.AppError); ok {
+		return appErr.Type
+	}
+	return ""
+}
+
+func TestItemSharing(t *testing.T) {
+	ctx := context.Background()
+	users := testsupport.NewUserRepository()
+	itemService := NewItemService(repository.NewEmptyInMemoryItemRepository()).
+		WithSharing(repository.NewInMemoryItemShareRepository(), users)
+
+	ownerUser := seededUser(t, users, "user@example.com")
+	owner := models.Viewer{UserID: ownerUser.ID, Role: ownerUser.Role}
+	other, err := users.Create(&models.User{Email: "outro@example.com", Name: "Outro", Role: "user"})
+	require.NoError(t, err)
+	collaborator := models.Viewer{UserID: other.ID, Role: other.Role}
+	admin := models.Viewer{UserID: "admin-id", Role: "admin"}
+
+	input := &models.InputData{Name: "Relatório", Value: "v1", Email: "user@example.com"}
+	item, err := itemService.CreateItem(ctx, owner, input)
+	require.NoError(t, err)
+	assert.Equal(t, owner.UserID, item.OwnerID)
+
+	// Itens com dono não aparecem para anônimos nem para outros usuários
+	_, total, err := itemService.GetItems(ctx, models.Viewer{}, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, total)
+	_, err = itemService.GetItemByID(ctx, collaborator, item.ID)
+	assert.Equal(t, "NOT_FOUND", appErrorType(err))
+	_, total, _ = itemService.GetItems(ctx, admin, 1, 10)
+	assert.Equal(t, 1, total)
+
+	// Apenas o dono compartilha
+	_, err = itemService.ShareItem(ctx, collaborator, item.ID, &models.ShareItemInput{UserID: other.ID, Level: models.ShareLevelWrite})
+	assert.Equal(t, "NOT_FOUND", appErrorType(err))
+	_, err = itemService.ShareItem(ctx, owner, item.ID, &models.ShareItemInput{UserID: "inexistente", Level: models.ShareLevelRead})
+	assert.Equal(t, "BAD_REQUEST", appErrorType(err))
+
+	// Leitura: o item fica visível, mas não editável
+	share, err := itemService.ShareItem(ctx, owner, item.ID, &models.ShareItemInput{UserID: other.ID, Level: models.ShareLevelRead})
+	require.NoError(t, err)
+	_, err = itemService.GetItemByID(ctx, collaborator, item.ID)
+	assert.NoError(t, err)
+	items, total, err := itemService.SearchItems(ctx, collaborator, "relatório", 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Len(t, items, 1)
+	update := &models.InputData{Name: "Relatório final", Value: "v2", Email: "user@example.com"}
+	_, err = itemService.UpdateItem(ctx, collaborator, item.ID, update)
+	assert.Equal(t, "FORBIDDEN", appErrorType(err))
+	_, err = itemService.ListShares(ctx, collaborator, item.ID)
+	assert.Equal(t, "FORBIDDEN", appErrorType(err))
+
+	// Compartilhar de novo altera o nível
+	upgraded, err := itemService.ShareItem(ctx, owner, item.ID, &models.ShareItemInput{UserID: other.ID, Level: models.ShareLevelWrite})
+	require.NoError(t, err)
+	assert.Equal(t, share.ID, upgraded.ID)
+	updated, err := itemService.UpdateItem(ctx, collaborator, item.ID, update)
+	assert.NoError(t, err)
+	assert.Equal(t, "Relatório final", updated.Name)
+	assert.Equal(t, owner.UserID, updated.OwnerID)
+
+	shares, err := itemService.ListShares(ctx, owner, item.ID)
+	assert.NoError(t, err)
+	assert.Len(t, shares, 1)
+
+	// Revogado o compartilhamento, o item volta a ser invisível
+	assert.NoError(t, itemService.RevokeShare(ctx, owner, item.ID, share.ID))
+	_, err = itemService.GetItemByID(ctx, collaborator, item.ID)
+	assert.Equal(t, "NOT_FOUND", appErrorType(err))
+	assert.Error(t, itemService.RevokeShare(ctx, owner, item.ID, share.ID))
+
+	// Compartilhamento por papel
+	_, err = itemService.ShareItem(ctx, owner, item.ID, &models.ShareItemInput{Role: "user", Level: models.ShareLevelRead})
+	require.NoError(t, err)
+	_, total, _ = itemService.GetItems(ctx, collaborator, 1, 10)
+	assert.Equal(t, 1, total)
+}
+
+func TestItemSharing_PublicItems(t *testing.T) {
+	ctx := context.Background()
+	users := testsupport.NewUserRepository()
+	itemService := NewItemService(repository.NewInMemoryItemRepository()).
+		WithSharing(repository.NewInMemoryItemShareRepository(), users)
+	user := seededUser(t, users, "user@example.com")
+	viewer := models.Viewer{UserID: user.ID, Role: user.Role}
+
+	// Os itens de exemplo não têm dono: leitura pública, escrita só para administradores
+	_, total, err := itemService.GetItems(ctx, models.Viewer{}, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, total)
+
+	update := &models.InputData{Name: "Item alterado", Value: "v", Email: "user@example.com"}
+	_, err = itemService.UpdateItem(ctx, viewer, "1", update)
+	assert.Equal(t, "FORBIDDEN", appErrorType(err))
+	_, err = itemService.UpdateItem(ctx, models.Viewer{UserID: "admin-id", Role: "admin"}, "1", update)
+	assert.NoError(t, err)
+
+	_, err = itemService.ShareItem(ctx, viewer, "1", &models.ShareItemInput{Role: "user", Level: models.ShareLevelRead})
+	assert.Equal(t, "BAD_REQUEST", appErrorType(err))
+}
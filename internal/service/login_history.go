@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	stderrors "errors"
+
+	"callable-api/internal/models"
+	"callable-api/internal/repository"
+	"callable-api/pkg/errors"
+	"callable-api/pkg/logger"
+)
+
+// WithLoginHistory habilita o registro das tentativas de login
+func (s *AuthService) WithLoginHistory(history repository.LoginHistoryRepository) *AuthService {
+	s.loginHistory = history
+	return s
+}
+
+// recordLogin registra a tentativa de login no histórico do usuário. Tentativas
+// com emails desconhecidos não pertencem a nenhum usuário e não são registradas.
+// Falhas ao gravar o histórico não impedem o login
+func (s *AuthService) recordLogin(email string, client models.ClientInfo, loginErr error) {
+	if s.loginHistory == nil {
+		return
+	}
+
+	user, err := s.repo.FindByEmail(email)
+	if err != nil {
+		return
+	}
+
+	attempt := &models.LoginAttempt{
+		UserID:    user.ID,
+		Success:   loginErr == nil,
+		IP:        client.IP,
+		UserAgent: client.UserAgent,
+		Timestamp: s.clock.Now().UTC(),
+	}
+	if loginErr != nil {
+		attempt.FailureReason = loginFailureReason(loginErr)
+	}
+
+	if err := s.loginHistory.Record(context.Background(), attempt); err != nil {
+		logger.Error("Falha ao registrar tentativa de login", map[string]interface{}{
+			"error":  err.Error(),
+			"userId": user.ID,
+		})
+	}
+}
+
+// loginFailureReason classifica o erro retornado pela autenticação
+func loginFailureReason(err error) string {
+	switch {
+	case causeIs(err, repository.ErrAccountDisabled):
+		return models.LoginFailureAccountDisabled
+	case causeIs(err, repository.ErrPasswordResetRequired):
+		return models.LoginFailurePasswordResetRequired
+	default:
+		return models.LoginFailureInvalidCredentials
+	}
+}
+
+// causeIs indica se err, ou o erro encapsulado por um AppError, é target
+func causeIs(err, target error) bool {
+	if stderrors.Is(err, target) {
+		return true
+	}
+	var appErr *errors.AppError
+	return stderrors.As(err, &appErr) && appErr.Err != nil && stderrors.Is(appErr.Err, target)
+}
+
+// LoginHistory retorna as tentativas de login do usuário, das mais recentes
+// para as mais antigas. Retorna NotFound se o usuário não existir
+func (s *AuthService) LoginHistory(ctx context.Context, userID string, page, limit int) ([]models.LoginAttempt, int, error) {
+	if _, err := s.repo.FindByID(userID); err != nil {
+		return nil, 0, err
+	}
+	if s.loginHistory == nil {
+		return []models.LoginAttempt{}, 0, nil
+	}
+	return s.loginHistory.ListByUser(ctx, userID, page, limit)
+}
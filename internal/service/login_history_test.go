@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"callable-api/internal/models"
+	"callable-api/internal/repository"
+	"callable-api/internal/repository/testsupport"
+)
+
+func TestLoginHistory(t *testing.T) {
+	repo := testsupport.NewUserRepository()
+	authService := NewAuthService(repo, getTestConfig()).
+		WithLoginHistory(repository.NewInMemoryLoginHistoryRepository())
+	admin := seededUser(t, repo, "admin@example.com")
+	user := seededUser(t, repo, "user@example.com")
+	client := models.ClientInfo{IP: "203.0.113.10", UserAgent: "curl/8.0"}
+
+	_, _, err := authService.Login(&models.LoginInput{Email: "user@example.com", Password: "errada"}, client)
+	assert.Error(t, err)
+	_, _, err = authService.Login(&models.LoginInput{Email: "user@example.com", Password: "user123"}, client)
+	assert.NoError(t, err)
+	_, err = authService.DisableUser(admin.ID, user.ID, "")
+	assert.NoError(t, err)
+	_, _, err = authService.Login(&models.LoginInput{Email: "user@example.com", Password: "user123"}, client)
+	assert.Error(t, err)
+
+	// Emails desconhecidos não entram no histórico de nenhum usuário
+	_, _, err = authService.Login(&models.LoginInput{Email: "ninguem@example.com", Password: "x"}, client)
+	assert.Error(t, err)
+
+	attempts, total, err := authService.LoginHistory(context.Background(), user.ID, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, total)
+	if assert.Len(t, attempts, 3) {
+		// Mais recentes primeiro
+		assert.False(t, attempts[0].Success)
+		assert.Equal(t, models.LoginFailureAccountDisabled, attempts[0].FailureReason)
+		assert.True(t, attempts[1].Success)
+		assert.Empty(t, attempts[1].FailureReason)
+		assert.Equal(t, models.LoginFailureInvalidCredentials, attempts[2].FailureReason)
+		assert.Equal(t, "203.0.113.10", attempts[2].IP)
+		assert.Equal(t, "curl/8.0", attempts[2].UserAgent)
+	}
+
+	attempts, total, err = authService.LoginHistory(context.Background(), user.ID, 2, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.Len(t, attempts, 1)
+
+	_, _, err = authService.LoginHistory(context.Background(), "inexistente", 1, 10)
+	assert.Error(t, err)
+}
@@ -0,0 +1,387 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	stderrors "errors"
+	"strings"
+	"time"
+
+	"callable-api/internal/clock"
+	"callable-api/internal/models"
+	"callable-api/internal/repository"
+	"callable-api/pkg/errors"
+	"callable-api/pkg/logger"
+)
+
+// DefaultInvitationTTL é a validade padrão dos convites (de organização e de cadastro)
+const DefaultInvitationTTL = 72 * time.Hour
+
+// ErrLastOwner é a causa do erro ao remover o último dono de uma organização
+var ErrLastOwner = stderrors.New("a organização precisa de ao menos um dono")
+
+// OrganizationService gerencia as organizações, os seus membros e os convites.
+// Os itens criados com uma organização ativa pertencem a ela e são lidos e
+// alterados por todos os seus membros (ver ItemService.accessLevel)
+type OrganizationService struct {
+	orgs          repository.OrganizationRepository
+	invitations   repository.InvitationRepository
+	users         repository.UserRepository
+	clock         clock.Clock
+	invitationTTL time.Duration
+}
+
+// NewOrganizationService cria um novo OrganizationService
+func NewOrganizationService(orgs repository.OrganizationRepository, invitations repository.InvitationRepository, users repository.UserRepository) *OrganizationService {
+	return &OrganizationService{
+		orgs:          orgs,
+		invitations:   invitations,
+		users:         users,
+		clock:         clock.System(),
+		invitationTTL: DefaultInvitationTTL,
+	}
+}
+
+// WithClock define o relógio usado na expiração dos convites (padrão: o do sistema)
+func (s *OrganizationService) WithClock(c clock.Clock) *OrganizationService {
+	s.clock = c
+	return s
+}
+
+// WithInvitationTTL define a validade dos convites (padrão: DefaultInvitationTTL)
+func (s *OrganizationService) WithInvitationTTL(ttl time.Duration) *OrganizationService {
+	if ttl > 0 {
+		s.invitationTTL = ttl
+	}
+	return s
+}
+
+// Membership retorna a participação do usuário na organização, usada pelo
+// middleware que ativa a organização da requisição
+func (s *OrganizationService) Membership(ctx context.Context, orgID, userID string) (*models.Membership, error) {
+	return s.orgs.FindMember(ctx, orgID, userID)
+}
+
+// CreateOrganization cria a organização com o usuário como seu primeiro dono
+func (s *OrganizationService) CreateOrganization(ctx context.Context, viewer models.Viewer, input *models.CreateOrganizationInput) (*models.Organization, error) {
+	if viewer.UserID == "" {
+		return nil, errors.NewUnauthorizedError("Autenticação necessária para criar organizações", nil)
+	}
+
+	org, err := s.orgs.Create(ctx, &models.Organization{
+		Name:      strings.TrimSpace(input.Name),
+		CreatedBy: viewer.UserID,
+	})
+	if err != nil {
+		return nil, s.repositoryError(ctx, "Falha ao criar organização", err)
+	}
+	if _, err := s.orgs.SaveMember(ctx, &models.Membership{OrgID: org.ID, UserID: viewer.UserID, Role: models.OrgRoleOwner}); err != nil {
+		return nil, s.repositoryError(ctx, "Falha ao gravar o dono da organização", err)
+	}
+
+	logger.Info("Organização criada", map[string]interface{}{
+		"orgId":  org.ID,
+		"userId": viewer.UserID,
+	})
+
+	org.Role = models.OrgRoleOwner
+	return org, nil
+}
+
+// ListOrganizations retorna as organizações do usuário, com o seu papel em cada uma
+func (s *OrganizationService) ListOrganizations(ctx context.Context, viewer models.Viewer) ([]models.Organization, error) {
+	memberships, err := s.orgs.ListForUser(ctx, viewer.UserID)
+	if err != nil {
+		return nil, s.repositoryError(ctx, "Falha ao buscar organizações", err)
+	}
+
+	orgs := make([]models.Organization, 0, len(memberships))
+	for _, membership := range memberships {
+		org, err := s.orgs.FindByID(ctx, membership.OrgID)
+		if err != nil {
+			return nil, s.repositoryError(ctx, "Falha ao buscar organizações", err)
+		}
+		org.Role = membership.Role
+		orgs = append(orgs, *org)
+	}
+	return orgs, nil
+}
+
+// ListMembers retorna os membros da organização; apenas membros os consultam
+func (s *OrganizationService) ListMembers(ctx context.Context, viewer models.Viewer, orgID string) ([]models.Membership, error) {
+	if _, err := s.requireMember(ctx, viewer, orgID, false); err != nil {
+		return nil, err
+	}
+
+	members, err := s.orgs.ListMembers(ctx, orgID)
+	if err != nil {
+		return nil, s.repositoryError(ctx, "Falha ao buscar membros", err)
+	}
+	return members, nil
+}
+
+// InviteMember convida um email para a organização. Apenas donos convidam; o
+// token do convite é retornado uma única vez, para ser enviado ao convidado
+func (s *OrganizationService) InviteMember(ctx context.Context, viewer models.Viewer, orgID string, input *models.InviteMemberInput) (*models.Invitation, error) {
+	if _, err := s.requireMember(ctx, viewer, orgID, true); err != nil {
+		return nil, err
+	}
+
+	email := strings.ToLower(strings.TrimSpace(input.Email))
+	if user, err := s.users.FindByEmail(email); err == nil {
+		if _, err := s.orgs.FindMember(ctx, orgID, user.ID); err == nil {
+			return nil, errors.NewConflictError("O usuário já é membro da organização", nil)
+		}
+	}
+
+	role := input.Role
+	if role == "" {
+		role = models.OrgRoleMember
+	}
+	invitation, err := s.createInvitation(ctx, &models.Invitation{
+		OrgID:     orgID,
+		Email:     email,
+		OrgRole:   role,
+		InvitedBy: viewer.UserID,
+	}, input.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Convite para organização criado", map[string]interface{}{
+		"orgId":        orgID,
+		"invitationId": invitation.ID,
+		"role":         role,
+		"invitedBy":    viewer.UserID,
+	})
+	return invitation, nil
+}
+
+// CreateInvitation emite um convite de cadastro, sem organização, para um
+// email ainda não cadastrado. O token é o código de convite do registro
+func (s *OrganizationService) CreateInvitation(ctx context.Context, viewer models.Viewer, input *models.CreateInvitationInput) (*models.Invitation, error) {
+	email := strings.ToLower(strings.TrimSpace(input.Email))
+	if _, err := s.users.FindByEmail(email); err == nil {
+		return nil, errors.NewConflictError("Email já está em uso", nil)
+	}
+
+	role := input.Role
+	if role == "" {
+		role = "user"
+	}
+	invitation, err := s.createInvitation(ctx, &models.Invitation{
+		Email:     email,
+		Role:      role,
+		InvitedBy: viewer.UserID,
+	}, input.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Convite de cadastro criado", map[string]interface{}{
+		"invitationId": invitation.ID,
+		"role":         role,
+		"invitedBy":    viewer.UserID,
+	})
+	return invitation, nil
+}
+
+// ListRegistrationInvitations retorna os convites de cadastro emitidos pelos administradores
+func (s *OrganizationService) ListRegistrationInvitations(ctx context.Context) ([]models.Invitation, error) {
+	invitations, err := s.invitations.ListByOrg(ctx, "")
+	if err != nil {
+		return nil, s.repositoryError(ctx, "Falha ao buscar convites", err)
+	}
+	return invitations, nil
+}
+
+// createInvitation grava o convite com um novo token, válido até expiresAt
+// (ou pelo prazo padrão), e o retorna com o token em claro
+func (s *OrganizationService) createInvitation(ctx context.Context, invitation *models.Invitation, expiresAt *time.Time) (*models.Invitation, error) {
+	now := s.clock.Now()
+	invitation.ExpiresAt = now.Add(s.invitationTTL).UTC()
+	if expiresAt != nil {
+		if !expiresAt.After(now) {
+			return nil, errors.NewBadRequestError("expires_at deve ser uma data futura", nil)
+		}
+		invitation.ExpiresAt = expiresAt.UTC()
+	}
+
+	token, tokenHash, err := newInvitationToken()
+	if err != nil {
+		return nil, errors.NewInternalServerError("Falha ao gerar o token do convite", err)
+	}
+	invitation.TokenHash = tokenHash
+
+	created, err := s.invitations.Create(ctx, invitation)
+	if err != nil {
+		return nil, s.repositoryError(ctx, "Falha ao gravar convite", err)
+	}
+	created.Token = token
+	return created, nil
+}
+
+// ListInvitations retorna os convites da organização; apenas donos os consultam
+func (s *OrganizationService) ListInvitations(ctx context.Context, viewer models.Viewer, orgID string) ([]models.Invitation, error) {
+	if _, err := s.requireMember(ctx, viewer, orgID, true); err != nil {
+		return nil, err
+	}
+
+	invitations, err := s.invitations.ListByOrg(ctx, orgID)
+	if err != nil {
+		return nil, s.repositoryError(ctx, "Falha ao buscar convites", err)
+	}
+	return invitations, nil
+}
+
+// PendingInvitation busca o convite do token, que ainda precisa poder ser
+// aceito (não expirado nem usado)
+func (s *OrganizationService) PendingInvitation(ctx context.Context, token string) (*models.Invitation, error) {
+	invitation, err := s.invitations.FindByTokenHash(ctx, hashToken(strings.TrimSpace(token)))
+	if err != nil {
+		if stderrors.Is(err, repository.ErrInvitationNotFound) {
+			return nil, errors.NewBadRequestError("Convite inválido ou expirado", err)
+		}
+		return nil, s.repositoryError(ctx, "Falha ao buscar convite", err)
+	}
+	if !invitation.Pending(s.clock.Now()) {
+		return nil, errors.NewBadRequestError("Convite inválido ou expirado", repository.ErrInvitationNotFound)
+	}
+	return invitation, nil
+}
+
+// RedeemInvitation marca o convite como aceito pelo usuário e, nos convites
+// de organização, o adiciona como membro. Membros que já participam da
+// organização mantêm o papel, a não ser que o convite os promova a donos
+func (s *OrganizationService) RedeemInvitation(ctx context.Context, invitation *models.Invitation, userID string) (*models.Membership, error) {
+	if _, err := s.invitations.MarkAccepted(ctx, invitation.ID, s.clock.Now()); err != nil {
+		var appErr *errors.AppError
+		if stderrors.As(err, &appErr) && appErr.Type == "CONFLICT" {
+			return nil, errors.NewBadRequestError("Convite inválido ou expirado", repository.ErrInvitationNotFound)
+		}
+		return nil, s.repositoryError(ctx, "Falha ao aceitar convite", err)
+	}
+	if invitation.OrgID == "" {
+		return nil, nil
+	}
+
+	role := invitation.OrgRole
+	if existing, err := s.orgs.FindMember(ctx, invitation.OrgID, userID); err == nil && existing.IsOwner() {
+		role = models.OrgRoleOwner
+	}
+	member, err := s.orgs.SaveMember(ctx, &models.Membership{OrgID: invitation.OrgID, UserID: userID, Role: role})
+	if err != nil {
+		return nil, s.repositoryError(ctx, "Falha ao adicionar membro", err)
+	}
+
+	logger.Info("Convite para organização aceito", map[string]interface{}{
+		"orgId":        invitation.OrgID,
+		"invitationId": invitation.ID,
+		"userId":       userID,
+		"role":         member.Role,
+	})
+	return member, nil
+}
+
+// AcceptInvitation adiciona o usuário à organização do convite. O convite
+// vale uma única vez, até expirar, e apenas para a conta com o email
+// convidado. Convites de cadastro (sem organização) só valem no registro
+func (s *OrganizationService) AcceptInvitation(ctx context.Context, viewer models.Viewer, token string) (*models.Membership, error) {
+	invitation, err := s.PendingInvitation(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if invitation.OrgID == "" {
+		return nil, errors.NewBadRequestError("Convite de cadastro: informe-o como invitation_code no registro", repository.ErrInvitationNotFound)
+	}
+
+	user, err := s.users.FindByID(viewer.UserID)
+	if err != nil {
+		return nil, errors.NewUnauthorizedError("Usuário não encontrado", err)
+	}
+	if !strings.EqualFold(user.Email, invitation.Email) {
+		return nil, errors.NewForbiddenError("O convite foi enviado para outro email", nil)
+	}
+
+	return s.RedeemInvitation(ctx, invitation, user.ID)
+}
+
+// RemoveMember remove um membro da organização. Donos removem qualquer membro
+// e os demais podem apenas sair; a organização não fica sem dono
+func (s *OrganizationService) RemoveMember(ctx context.Context, viewer models.Viewer, orgID, userID string) error {
+	actor, err := s.requireMember(ctx, viewer, orgID, userID != viewer.UserID)
+	if err != nil {
+		return err
+	}
+
+	target := actor
+	if userID != viewer.UserID {
+		if target, err = s.orgs.FindMember(ctx, orgID, userID); err != nil {
+			return err
+		}
+	}
+
+	if target.IsOwner() {
+		members, err := s.orgs.ListMembers(ctx, orgID)
+		if err != nil {
+			return s.repositoryError(ctx, "Falha ao buscar membros", err)
+		}
+		owners := 0
+		for _, member := range members {
+			if member.IsOwner() {
+				owners++
+			}
+		}
+		if owners <= 1 {
+			return errors.NewConflictError("A organização precisa de ao menos um dono", ErrLastOwner)
+		}
+	}
+
+	if err := s.orgs.DeleteMember(ctx, orgID, userID); err != nil {
+		return err
+	}
+
+	logger.Info("Membro removido da organização", map[string]interface{}{
+		"orgId":     orgID,
+		"userId":    userID,
+		"removedBy": viewer.UserID,
+	})
+	return nil
+}
+
+// requireMember exige que o usuário seja membro (ou dono, se owner for true)
+// da organização. Quem não é membro recebe NotFound, para não revelar a
+// existência da organização
+func (s *OrganizationService) requireMember(ctx context.Context, viewer models.Viewer, orgID string, owner bool) (*models.Membership, error) {
+	member, err := s.orgs.FindMember(ctx, orgID, viewer.UserID)
+	if err != nil {
+		if stderrors.Is(err, repository.ErrMembershipNotFound) {
+			return nil, errors.NewNotFoundError("Organização não encontrada", nil)
+		}
+		return nil, s.repositoryError(ctx, "Falha ao buscar membros", err)
+	}
+	if owner && !member.IsOwner() {
+		return nil, errors.NewForbiddenError("Apenas os donos da organização podem realizar esta operação", nil)
+	}
+	return member, nil
+}
+
+// repositoryError converte falhas do repositório em erro interno, exceto o
+// cancelamento da requisição
+func (s *OrganizationService) repositoryError(ctx context.Context, message string, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return errors.NewInternalServerError(message, err)
+}
+
+// newInvitationToken gera um token aleatório de convite e o hash que fica gravado
+func newInvitationToken() (string, string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+	token := hex.EncodeToString(secret)
+	return token, hashToken(token), nil
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"callable-api/internal/clock"
+	"callable-api/internal/models"
+	"callable-api/internal/repository"
+	"callable-api/internal/repository/testsupport"
+)
+
+func TestOrganizations(t *testing.T) {
+	ctx := context.Background()
+	users := testsupport.NewUserRepository()
+	clk := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	orgService := NewOrganizationService(repository.NewInMemoryOrganizationRepository(), repository.NewInMemoryInvitationRepository(), users).
+		WithClock(clk).
+		WithInvitationTTL(time.Hour)
+
+	ownerUser := seededUser(t, users, "user@example.com")
+	owner := models.Viewer{UserID: ownerUser.ID, Role: ownerUser.Role}
+	other, err := users.Create(&models.User{Email: "colega@example.com", Name: "Colega", Role: "user"})
+	require.NoError(t, err)
+	colleague := models.Viewer{UserID: other.ID, Role: other.Role}
+
+	org, err := orgService.CreateOrganization(ctx, owner, &models.CreateOrganizationInput{Name: " Acme "})
+	require.NoError(t, err)
+	assert.Equal(t, "Acme", org.Name)
+	assert.Equal(t, models.OrgRoleOwner, org.Role)
+
+	// Quem não é membro não vê a organização
+	_, err = orgService.ListMembers(ctx, colleague, org.ID)
+	assert.Equal(t, "NOT_FOUND", appErrorType(err))
+
+	invitation, err := orgService.InviteMember(ctx, owner, org.ID, &models.InviteMemberInput{Email: "Colega@Example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, models.OrgRoleMember, invitation.OrgRole)
+	assert.NotEmpty(t, invitation.Token)
+
+	// O token não é gravado nem volta na listagem
+	invitations, err := orgService.ListInvitations(ctx, owner, org.ID)
+	require.NoError(t, err)
+	require.Len(t, invitations, 1)
+	assert.Empty(t, invitations[0].Token)
+
+	// Apenas a conta do email convidado aceita o convite
+	_, err = orgService.AcceptInvitation(ctx, owner, invitation.Token)
+	assert.Equal(t, "FORBIDDEN", appErrorType(err))
+
+	member, err := orgService.AcceptInvitation(ctx, colleague, invitation.Token)
+	require.NoError(t, err)
+	assert.Equal(t, models.OrgRoleMember, member.Role)
+
+	// O convite vale uma única vez
+	_, err = orgService.AcceptInvitation(ctx, colleague, invitation.Token)
+	assert.True(t, stderrors.Is(err, repository.ErrInvitationNotFound))
+
+	members, err := orgService.ListMembers(ctx, colleague, org.ID)
+	require.NoError(t, err)
+	assert.Len(t, members, 2)
+
+	// Membros não convidam nem removem outros membros
+	_, err = orgService.InviteMember(ctx, colleague, org.ID, &models.InviteMemberInput{Email: "novo@example.com"})
+	assert.Equal(t, "FORBIDDEN", appErrorType(err))
+	assert.Equal(t, "FORBIDDEN", appErrorType(orgService.RemoveMember(ctx, colleague, org.ID, owner.UserID)))
+
+	// O último dono não sai da organização
+	err = orgService.RemoveMember(ctx, owner, org.ID, owner.UserID)
+	assert.True(t, stderrors.Is(err, ErrLastOwner))
+
+	// Convites expirados não valem mais
+	late, err := orgService.InviteMember(ctx, owner, org.ID, &models.InviteMemberInput{Email: "admin@example.com", Role: models.OrgRoleOwner})
+	require.NoError(t, err)
+	clk.Advance(time.Hour + time.Second)
+	adminUser := seededUser(t, users, "admin@example.com")
+	_, err = orgService.AcceptInvitation(ctx, models.Viewer{UserID: adminUser.ID, Role: adminUser.Role}, late.Token)
+	assert.True(t, stderrors.Is(err, repository.ErrInvitationNotFound))
+
+	// Membros podem sair da organização
+	require.NoError(t, orgService.RemoveMember(ctx, colleague, org.ID, colleague.UserID))
+	orgs, err := orgService.ListOrganizations(ctx, colleague)
+	require.NoError(t, err)
+	assert.Empty(t, orgs)
+}
+
+func TestItemSharing_Organization(t *testing.T) {
+	ctx := context.Background()
+	users := testsupport.NewUserRepository()
+	itemService := NewItemService(repository.NewEmptyInMemoryItemRepository()).
+		WithSharing(repository.NewInMemoryItemShareRepository(), users)
+
+	author := models.Viewer{UserID: "autor", Role: "user", OrgID: "org-1"}
+	item, err := itemService.CreateItem(ctx, author, &models.InputData{Name: "Contrato", Value: "v1", Email: "user@example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, "org-1", item.OrgID)
+
+	// Os membros leem e alteram os itens da organização ativa
+	teammate := models.Viewer{UserID: "colega", Role: "user", OrgID: "org-1"}
+	_, err = itemService.UpdateItem(ctx, teammate, item.ID, &models.InputData{Name: "Contrato v2", Value: "v2", Email: "user@example.com"})
+	require.NoError(t, err)
+	items, total, err := itemService.GetItems(ctx, teammate, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, "org-1", items[0].OrgID)
+
+	// Sem a organização ativa, o item não é visível
+	outsider := models.Viewer{UserID: "colega", Role: "user"}
+	_, err = itemService.GetItemByID(ctx, outsider, item.ID)
+	assert.Equal(t, "NOT_FOUND", appErrorType(err))
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"strings"
+
+	"callable-api/internal/models"
+	"callable-api/pkg/errors"
+	"callable-api/pkg/logger"
+)
+
+// ErrRegistrationClosed indica um registro sem código de convite com o
+// cadastro aberto desativado
+var ErrRegistrationClosed = stderrors.New("cadastro aberto desativado")
+
+// RegistrationMode define quem pode se cadastrar na API
+type RegistrationMode string
+
+const (
+	// RegistrationOpen aceita qualquer cadastro; o código de convite é opcional
+	RegistrationOpen RegistrationMode = "open"
+
+	// RegistrationInvite aceita apenas cadastros com um código de convite
+	// válido para o email informado
+	RegistrationInvite RegistrationMode = "invite"
+)
+
+// ParseRegistrationMode converte o texto da configuração em RegistrationMode
+func ParseRegistrationMode(value string) (RegistrationMode, error) {
+	switch mode := RegistrationMode(strings.ToLower(strings.TrimSpace(value))); mode {
+	case "", RegistrationOpen:
+		return RegistrationOpen, nil
+	case RegistrationInvite:
+		return mode, nil
+	default:
+		return RegistrationOpen, fmt.Errorf("modo de cadastro inválido: %q (use open ou invite)", value)
+	}
+}
+
+// InvitationRedeemer valida e consome os códigos de convite usados no
+// registro (ver OrganizationService)
+type InvitationRedeemer interface {
+	PendingInvitation(ctx context.Context, token string) (*models.Invitation, error)
+	RedeemInvitation(ctx context.Context, invitation *models.Invitation, userID string) (*models.Membership, error)
+}
+
+// WithInvitations permite o registro com código de convite: a conta recebe o
+// papel do convite e, nos convites de organização, entra na organização. No
+// modo RegistrationInvite o código passa a ser obrigatório
+func (s *AuthService) WithInvitations(invitations InvitationRedeemer, mode RegistrationMode) *AuthService {
+	s.invitations = invitations
+	s.registration = mode
+	return s
+}
+
+// registrationInvitation retorna o convite do registro (nil se o registro não
+// informa código e o cadastro é aberto) e o papel da nova conta
+func (s *AuthService) registrationInvitation(ctx context.Context, input *models.RegisterUserInput) (*models.Invitation, string, error) {
+	code := strings.TrimSpace(input.InvitationCode)
+	if code == "" {
+		if s.registration == RegistrationInvite {
+			return nil, "", errors.NewForbiddenError("O cadastro exige um código de convite", ErrRegistrationClosed)
+		}
+		return nil, "user", nil
+	}
+	if s.invitations == nil {
+		return nil, "", errors.NewBadRequestError("Códigos de convite não são aceitos por esta API", nil)
+	}
+
+	invitation, err := s.invitations.PendingInvitation(ctx, code)
+	if err != nil {
+		return nil, "", err
+	}
+	if !strings.EqualFold(strings.TrimSpace(input.Email), invitation.Email) {
+		return nil, "", errors.NewForbiddenError("O convite foi enviado para outro email", nil)
+	}
+
+	role := invitation.Role
+	if role == "" {
+		role = "user"
+	}
+	return invitation, role, nil
+}
+
+// redeemRegistration consome o convite usado no registro. Se o convite não
+// puder mais ser usado (ex.: aceito por outra requisição), a conta recém-criada
+// é removida e o registro falha
+func (s *AuthService) redeemRegistration(ctx context.Context, invitation *models.Invitation, user *models.User) error {
+	if _, err := s.invitations.RedeemInvitation(ctx, invitation, user.ID); err != nil {
+		if deleteErr := s.repo.Delete(user.ID); deleteErr != nil {
+			logger.Error("Falha ao desfazer registro com convite inválido", map[string]interface{}{
+				"userId": user.ID,
+				"error":  deleteErr.Error(),
+			})
+		}
+		return err
+	}
+
+	logger.Info("Registro com convite", map[string]interface{}{
+		"userId":       user.ID,
+		"invitationId": invitation.ID,
+		"orgId":        invitation.OrgID,
+	})
+	return nil
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"callable-api/internal/models"
+	"callable-api/internal/repository"
+	"callable-api/internal/repository/testsupport"
+)
+
+func TestRegister_Invitation(t *testing.T) {
+	ctx := context.Background()
+	users := testsupport.NewUserRepository()
+	orgService := NewOrganizationService(repository.NewInMemoryOrganizationRepository(), repository.NewInMemoryInvitationRepository(), users)
+	authService := NewAuthService(users, getTestConfig()).WithInvitations(orgService, RegistrationInvite)
+
+	admin := seededUser(t, users, "admin@example.com")
+	adminViewer := models.Viewer{UserID: admin.ID, Role: admin.Role}
+
+	// Sem código, o cadastro fechado recusa o registro
+	_, err := authService.Register(&models.RegisterUserInput{Email: "novo@example.com", Name: "Novo", Password: "senha-segura"})
+	assert.True(t, stderrors.Is(err, ErrRegistrationClosed))
+
+	// Emails já cadastrados não recebem convite de cadastro
+	_, err = orgService.CreateInvitation(ctx, adminViewer, &models.CreateInvitationInput{Email: "user@example.com"})
+	assert.Equal(t, "CONFLICT", appErrorType(err))
+
+	invitation, err := orgService.CreateInvitation(ctx, adminViewer, &models.CreateInvitationInput{Email: "novo@example.com", Role: "admin"})
+	require.NoError(t, err)
+
+	// O código vale apenas para o email convidado
+	_, err = authService.Register(&models.RegisterUserInput{Email: "outro@example.com", Name: "Outro", Password: "senha-segura", InvitationCode: invitation.Token})
+	assert.Equal(t, "FORBIDDEN", appErrorType(err))
+
+	user, err := authService.Register(&models.RegisterUserInput{Email: "Novo@Example.com", Name: "Novo", Password: "senha-segura", InvitationCode: invitation.Token})
+	require.NoError(t, err)
+	assert.Equal(t, "admin", user.Role)
+
+	// O código não pode ser reutilizado
+	_, err = authService.Register(&models.RegisterUserInput{Email: "novo2@example.com", Name: "Novo", Password: "senha-segura", InvitationCode: invitation.Token})
+	assert.True(t, stderrors.Is(err, repository.ErrInvitationNotFound))
+
+	// Convites de organização cadastram a conta e a adicionam à organização
+	org, err := orgService.CreateOrganization(ctx, adminViewer, &models.CreateOrganizationInput{Name: "Acme"})
+	require.NoError(t, err)
+	orgInvitation, err := orgService.InviteMember(ctx, adminViewer, org.ID, &models.InviteMemberInput{Email: "membro@example.com"})
+	require.NoError(t, err)
+	member, err := authService.Register(&models.RegisterUserInput{Email: "membro@example.com", Name: "Membro", Password: "senha-segura", InvitationCode: orgInvitation.Token})
+	require.NoError(t, err)
+	assert.Equal(t, "user", member.Role)
+	membership, err := orgService.Membership(ctx, org.ID, member.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.OrgRoleMember, membership.Role)
+
+	// Convites de cadastro não são aceitos por contas existentes
+	pending, err := orgService.CreateInvitation(ctx, adminViewer, &models.CreateInvitationInput{Email: "depois@example.com"})
+	require.NoError(t, err)
+	_, err = orgService.AcceptInvitation(ctx, models.Viewer{UserID: member.ID}, pending.Token)
+	assert.True(t, stderrors.Is(err, repository.ErrInvitationNotFound))
+}
+
+func TestParseRegistrationMode(t *testing.T) {
+	for value, expected := range map[string]RegistrationMode{"": RegistrationOpen, "open": RegistrationOpen, " Invite ": RegistrationInvite} {
+		mode, err := ParseRegistrationMode(value)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, mode)
+	}
+
+	mode, err := ParseRegistrationMode("closed")
+	assert.Error(t, err)
+	assert.Equal(t, RegistrationOpen, mode)
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+
+	"callable-api/internal/models"
+	"callable-api/internal/repository"
+	"callable-api/pkg/errors"
+	"callable-api/pkg/logger"
+)
+
+// ScopedTokenConfig define a validade dos tokens restritos: DefaultTTL quando
+// o cliente não informa expires_in e MaxTTL como limite. O token restrito
+// nunca vale mais que o token usado para pedi-lo
+type ScopedTokenConfig struct {
+	DefaultTTL time.Duration
+	MaxTTL     time.Duration
+}
+
+// DefaultScopedTokenConfig retorna a validade padrão: 5 minutos, até 1 hora
+func DefaultScopedTokenConfig() ScopedTokenConfig {
+	return ScopedTokenConfig{DefaultTTL: 5 * time.Minute, MaxTTL: time.Hour}
+}
+
+// WithScopedTokens permite trocar o token de acesso por tokens restritos a
+// parte dos escopos, com validade menor
+func (s *AuthService) WithScopedTokens(tokens repository.ScopedTokenRepository, cfg ScopedTokenConfig) *AuthService {
+	s.scopedTokens = tokens
+	s.scopedTokenConfig = cfg
+	return s
+}
+
+// NarrowToken emite um token restrito aos escopos pedidos. Os escopos devem
+// estar contidos nos do token atual e a validade não ultrapassa a dele
+func (s *AuthService) NarrowToken(ctx context.Context, current models.TokenGrant, input *models.NarrowTokenInput) (*models.ScopedToken, error) {
+	if s.scopedTokens == nil {
+		return nil, errors.NewBadRequestError("A emissão de tokens restritos não está configurada", nil)
+	}
+
+	scopes := make([]string, 0, len(input.Scopes))
+	seen := make(map[string]bool, len(input.Scopes))
+	for _, scope := range input.Scopes {
+		if !models.ValidScope(scope) {
+			return nil, errors.NewBadRequestError(fmt.Sprintf("Escopo inválido: %q (use <recurso>:read ou <recurso>:write)", scope), nil)
+		}
+		if !models.ScopeAllows(current.Scopes, scope) {
+			return nil, errors.NewForbiddenError(fmt.Sprintf("O token atual não concede o escopo %s", scope), nil)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+	sort.Strings(scopes)
+
+	now := s.clock.Now()
+	ttl := s.scopedTokenConfig.DefaultTTL
+	if input.ExpiresIn > 0 {
+		ttl = time.Duration(input.ExpiresIn) * time.Second
+	}
+	ttl = min(ttl, s.scopedTokenConfig.MaxTTL)
+	expiresAt := now.Add(ttl)
+	if !current.ExpiresAt.IsZero() && current.ExpiresAt.Before(expiresAt) {
+		expiresAt = current.ExpiresAt
+	}
+	if !expiresAt.After(now) {
+		return nil, errors.NewUnauthorizedError("O token atual está expirado", nil)
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, errors.NewInternalServerError("Erro ao gerar o token", err)
+	}
+	token := models.ScopedTokenPrefix + hex.EncodeToString(secret)
+
+	grant := &models.TokenGrant{UserID: current.UserID, Scopes: scopes, ExpiresAt: expiresAt}
+	if err := s.scopedTokens.Save(ctx, hashToken(token), grant); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, errors.NewInternalServerError("Erro ao registrar o token", err)
+	}
+
+	logger.Info("Token restrito emitido", map[string]interface{}{
+		"userId":    current.UserID,
+		"scopes":    scopes,
+		"expiresAt": expiresAt,
+	})
+
+	return &models.ScopedToken{
+		AccessToken: token,
+		TokenType:   "Bearer",
+		Scopes:      scopes,
+		ExpiresIn:   int(expiresAt.Sub(now).Seconds()),
+		ExpiresAt:   expiresAt,
+	}, nil
+}
+
+// AuthenticateScopedToken valida um token restrito e retorna os escopos com
+// os dados atuais do usuário. Assim como nos tokens JWT, contas bloqueadas
+// depois da emissão são recusadas
+func (s *AuthService) AuthenticateScopedToken(ctx context.Context, token string) (*models.TokenGrant, error) {
+	if s.scopedTokens == nil {
+		return nil, errors.NewUnauthorizedError("Token inválido ou expirado", nil)
+	}
+
+	grant, err := s.scopedTokens.Find(ctx, hashToken(token))
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, errors.NewUnauthorizedError("Token inválido ou expirado", nil)
+	}
+
+	user, err := s.repo.FindByID(grant.UserID)
+	if err != nil {
+		return nil, errors.NewUnauthorizedError("Usuário não encontrado", err)
+	}
+	if err := repository.CheckAccountState(user); err != nil {
+		return nil, err
+	}
+
+	grant.Email = user.Email
+	grant.Name = user.Name
+	grant.Role = user.Role
+	return grant, nil
+}
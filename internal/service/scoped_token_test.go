@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"callable-api/internal/clock"
+	"callable-api/internal/models"
+	"callable-api/internal/repository"
+	"callable-api/internal/repository/testsupport"
+)
+
+func TestNarrowToken(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewFake(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+	users := testsupport.NewUserRepository()
+	tokens := repository.NewInMemoryScopedTokenRepository().WithClock(clk)
+	authService := NewAuthService(users, getTestConfig()).
+		WithClock(clk).
+		WithScopedTokens(tokens, DefaultScopedTokenConfig())
+
+	user := seededUser(t, users, "user@example.com")
+	full := models.TokenGrant{UserID: user.ID, ExpiresAt: clk.Now().Add(15 * time.Minute)}
+
+	_, err := authService.NarrowToken(ctx, full, &models.NarrowTokenInput{Scopes: []string{"data"}})
+	assert.Equal(t, "BAD_REQUEST", appErrorType(err))
+
+	// A validade pedida é limitada pela do token atual
+	narrowed, err := authService.NarrowToken(ctx, full, &models.NarrowTokenInput{Scopes: []string{"data:write", "auth:read", "data:write"}, ExpiresIn: 3600})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"auth:read", "data:write"}, narrowed.Scopes)
+	assert.Equal(t, 900, narrowed.ExpiresIn)
+
+	grant, err := authService.AuthenticateScopedToken(ctx, narrowed.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, grant.UserID)
+	assert.Equal(t, user.Role, grant.Role)
+
+	// Um token restrito só gera tokens com escopos que ele mesmo concede
+	_, err = authService.NarrowToken(ctx, *grant, &models.NarrowTokenInput{Scopes: []string{"admin:read"}})
+	assert.Equal(t, "FORBIDDEN", appErrorType(err))
+	again, err := authService.NarrowToken(ctx, *grant, &models.NarrowTokenInput{Scopes: []string{"data:read"}})
+	require.NoError(t, err)
+	assert.Equal(t, int(DefaultScopedTokenConfig().DefaultTTL.Seconds()), again.ExpiresIn)
+
+	// Tokens expirados ou desconhecidos são recusados
+	clk.Advance(16 * time.Minute)
+	_, err = authService.AuthenticateScopedToken(ctx, narrowed.AccessToken)
+	assert.Equal(t, "UNAUTHORIZED", appErrorType(err))
+	_, err = authService.AuthenticateScopedToken(ctx, models.ScopedTokenPrefix+"desconhecido")
+	assert.Equal(t, "UNAUTHORIZED", appErrorType(err))
+}
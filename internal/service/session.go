@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"callable-api/internal/models"
+	"callable-api/internal/repository"
+	"callable-api/pkg/auth"
+	"callable-api/pkg/errors"
+	"callable-api/pkg/logger"
+)
+
+// SessionLifetime define a validade dos tokens de uma sessão
+type SessionLifetime struct {
+	AccessMinutes int
+	RefreshDays   int
+}
+
+// refreshTTL retorna a validade do token de atualização
+func (l SessionLifetime) refreshTTL() time.Duration {
+	return time.Duration(l.RefreshDays) * 24 * time.Hour
+}
+
+// SessionConfig define as durações das sessões curtas e longas que o login
+// pode solicitar. A sessão padrão usa JWTExpirationMinutes e
+// JWTRefreshExpirationDays da configuração global
+type SessionConfig struct {
+	Short SessionLifetime
+	Long  SessionLifetime
+}
+
+// DefaultSessionConfig retorna as durações padrão: sessões curtas com tokens
+// de acesso de 15 minutos renováveis por 1 dia e sessões longas com tokens de
+// acesso de 60 minutos renováveis por 30 dias
+func DefaultSessionConfig() SessionConfig {
+	return SessionConfig{
+		Short: SessionLifetime{AccessMinutes: 15, RefreshDays: 1},
+		Long:  SessionLifetime{AccessMinutes: 60, RefreshDays: 30},
+	}
+}
+
+// WithSessions permite ao login escolher a duração da sessão, mantida nas
+// renovações de tokens. Sem essa opção todas as sessões usam a duração padrão
+func (s *AuthService) WithSessions(sessions repository.SessionRepository, cfg SessionConfig) *AuthService {
+	s.sessions = sessions
+	s.sessionConfig = cfg
+	return s
+}
+
+// sessionLifetime retorna a validade dos tokens para o tipo de sessão. Valores
+// não configurados usam os da sessão padrão
+func (s *AuthService) sessionLifetime(kind string) SessionLifetime {
+	lifetime := SessionLifetime{
+		AccessMinutes: s.cfg.JWTExpirationMinutes,
+		RefreshDays:   s.cfg.JWTRefreshExpirationDays,
+	}
+
+	var custom SessionLifetime
+	switch kind {
+	case models.SessionShort:
+		custom = s.sessionConfig.Short
+	case models.SessionLong:
+		custom = s.sessionConfig.Long
+	}
+	if custom.AccessMinutes > 0 {
+		lifetime.AccessMinutes = custom.AccessMinutes
+	}
+	if custom.RefreshDays > 0 {
+		lifetime.RefreshDays = custom.RefreshDays
+	}
+	return lifetime
+}
+
+// issueTokens gera os tokens da sessão, registrando o tipo da sessão e o
+// dispositivo vinculados ao token de atualização
+func (s *AuthService) issueTokens(user *models.User, kind string, client models.ClientInfo) (*models.TokenPair, error) {
+	if s.sessions == nil || kind == "" {
+		kind = models.SessionStandard
+	}
+	lifetime := s.sessionLifetime(kind)
+
+	// O segredo vem da configuração vigente; cada sessão usa uma cópia com as
+	// durações do seu tipo
+	sessionCfg := *s.keys.Config()
+	sessionCfg.JWTExpirationMinutes = lifetime.AccessMinutes
+	sessionCfg.JWTRefreshExpirationDays = lifetime.RefreshDays
+
+	tokenPair, err := auth.GenerateTokenPair(user, &sessionCfg)
+	if err != nil {
+		return nil, errors.NewInternalServerError("Erro ao gerar tokens", err)
+	}
+	tokenPair.Session = kind
+	tokenPair.ExpiresIn = lifetime.AccessMinutes * 60
+
+	if s.sessions != nil {
+		session := &models.Session{
+			UserID:    user.ID,
+			Kind:      kind,
+			ExpiresAt: s.clock.Now().Add(lifetime.refreshTTL()),
+		}
+		if err := s.sessions.Save(context.Background(), hashToken(tokenPair.RefreshToken), session); err != nil {
+			return nil, errors.NewInternalServerError("Erro ao registrar a sessão", err)
+		}
+	}
+
+	if err := s.bindRefreshToken(user.ID, tokenPair.RefreshToken, client, lifetime.refreshTTL()); err != nil {
+		return nil, err
+	}
+
+	return tokenPair, nil
+}
+
+// takeSessionKind retorna o tipo da sessão do token de atualização e encerra o
+// registro, substituído pelo do novo token. Tokens sem registro (emitidos antes
+// de uma reinicialização, por exemplo) continuam com a sessão padrão
+func (s *AuthService) takeSessionKind(userID, refreshToken string) string {
+	if s.sessions == nil {
+		return models.SessionStandard
+	}
+
+	ctx := context.Background()
+	tokenHash := hashToken(refreshToken)
+	session, err := s.sessions.Find(ctx, tokenHash)
+	if err != nil || session.UserID != userID {
+		return models.SessionStandard
+	}
+
+	if err := s.sessions.Delete(ctx, tokenHash); err != nil {
+		logger.Warn("Falha ao encerrar o registro da sessão", map[string]interface{}{
+			"error":  err.Error(),
+			"userId": userID,
+		})
+	}
+	return session.Kind
+}
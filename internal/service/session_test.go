@@ -0,0 +1,45 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"callable-api/internal/models"
+	"callable-api/internal/repository"
+	"callable-api/internal/repository/testsupport"
+)
+
+func TestSessionLifetimes(t *testing.T) {
+	authService := NewAuthService(testsupport.NewUserRepository(), getTestConfig()).
+		WithSessions(repository.NewInMemorySessionRepository(), SessionConfig{
+			Short: SessionLifetime{AccessMinutes: 5, RefreshDays: 1},
+			Long:  SessionLifetime{RefreshDays: 30},
+		})
+
+	tokens, _, err := authService.Login(&models.LoginInput{Email: "user@example.com", Password: "user123"}, models.ClientInfo{})
+	assert.NoError(t, err)
+	assert.Equal(t, models.SessionStandard, tokens.Session)
+	assert.Equal(t, 15*60, tokens.ExpiresIn)
+
+	tokens, _, err = authService.Login(&models.LoginInput{Email: "user@example.com", Password: "user123", Session: models.SessionShort}, models.ClientInfo{})
+	assert.NoError(t, err)
+	assert.Equal(t, models.SessionShort, tokens.Session)
+	assert.Equal(t, 5*60, tokens.ExpiresIn)
+
+	// Valores não configurados seguem a sessão padrão
+	assert.Equal(t, SessionLifetime{AccessMinutes: 15, RefreshDays: 30}, authService.sessionLifetime(models.SessionLong))
+
+	// A renovação mantém a duração escolhida no login
+	tokens, _, err = authService.Login(&models.LoginInput{Email: "user@example.com", Password: "user123", Session: models.SessionLong}, models.ClientInfo{})
+	assert.NoError(t, err)
+	renewed, err := authService.RefreshToken(tokens.RefreshToken, models.ClientInfo{})
+	assert.NoError(t, err)
+	assert.Equal(t, models.SessionLong, renewed.Session)
+
+	// Sem o registro de sessões, o login sempre usa a sessão padrão
+	plain := NewAuthService(testsupport.NewUserRepository(), getTestConfig())
+	tokens, _, err = plain.Login(&models.LoginInput{Email: "user@example.com", Password: "user123", Session: models.SessionLong}, models.ClientInfo{})
+	assert.NoError(t, err)
+	assert.Equal(t, models.SessionStandard, tokens.Session)
+}
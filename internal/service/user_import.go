@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	stderrors "errors"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"callable-api/internal/models"
+	"callable-api/internal/validation"
+	"callable-api/pkg/errors"
+	"callable-api/pkg/logger"
+)
+
+// userInvitationTemplate é o template de pkg/mail do convite enviado às
+// contas criadas pela importação
+const userInvitationTemplate = "user_invitation"
+
+// defaultImportRole é o papel das linhas importadas sem a coluna role
+const defaultImportRole = "user"
+
+// ImportUsers cria as contas das linhas importadas por um administrador. Cada
+// conta é criada com uma senha aleatória e com a redefinição de senha
+// obrigatória, e o usuário recebe por email o convite com o link para escolher
+// a senha. A falha de uma linha (dados inválidos, email em uso) não interrompe
+// as demais e fica registrada no relatório
+func (s *AuthService) ImportUsers(ctx context.Context, actorID string, rows []models.UserImportRow) (*models.UserImportReport, error) {
+	report := &models.UserImportReport{Total: len(rows), Rows: make([]models.UserImportResult, 0, len(rows))}
+	for _, row := range rows {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		result := s.importUser(ctx, row)
+		if result.Status == models.UserImportCreated {
+			report.Created++
+		} else {
+			report.Failed++
+		}
+		report.Rows = append(report.Rows, result)
+	}
+
+	logger.Info("Importação de usuários concluída", map[string]interface{}{
+		"adminId": actorID,
+		"total":   report.Total,
+		"created": report.Created,
+		"failed":  report.Failed,
+	})
+	return report, nil
+}
+
+// importUser cria a conta de uma linha e envia o convite
+func (s *AuthService) importUser(ctx context.Context, row models.UserImportRow) models.UserImportResult {
+	row.Email = strings.TrimSpace(row.Email)
+	row.Name = strings.TrimSpace(row.Name)
+	row.Role = strings.ToLower(strings.TrimSpace(row.Role))
+	result := models.UserImportResult{Line: row.Line, Email: row.Email, Status: models.UserImportFailed}
+
+	if err := validation.Validate(&row); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if row.Role == "" {
+		row.Role = defaultImportRole
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		result.Error = "Erro ao gerar a senha do usuário"
+		return result
+	}
+	hashed, err := bcrypt.GenerateFromPassword([]byte(hex.EncodeToString(secret)), bcrypt.DefaultCost)
+	if err != nil {
+		result.Error = "Erro ao processar a senha do usuário"
+		return result
+	}
+
+	created, err := s.repo.Create(&models.User{
+		Email:                 row.Email,
+		Name:                  row.Name,
+		Password:              string(hashed),
+		Role:                  row.Role,
+		PasswordResetRequired: true,
+	})
+	if err != nil {
+		result.Error = importErrorMessage(err, "Erro ao criar usuário")
+		return result
+	}
+	result.Status = models.UserImportCreated
+	result.UserID = created.ID
+
+	_, user, token, err := s.requirePasswordReset(created.ID)
+	if err == nil {
+		result.Invited, err = s.mailResetLink(ctx, userInvitationTemplate, user, token)
+	}
+	if err != nil {
+		logger.Error("Conta importada, mas o convite não foi enviado", map[string]interface{}{
+			"userId": created.ID,
+			"error":  err.Error(),
+		})
+		result.Error = importErrorMessage(err, "Conta criada, mas o convite não foi enviado")
+	}
+	return result
+}
+
+// importErrorMessage descreve a falha de uma linha sem expor os erros internos
+func importErrorMessage(err error, fallback string) string {
+	var appErr *errors.AppError
+	if stderrors.As(err, &appErr) && appErr.Type != "INTERNAL_SERVER" {
+		return appErr.Message
+	}
+	return fallback
+}
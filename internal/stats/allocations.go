@@ -0,0 +1,141 @@
+package stats
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"callable-api/internal/metrics"
+	"callable-api/internal/models"
+)
+
+// AllocationBudgetConfig define o orçamento de memória por requisição
+// (experimental). Bytes 0 desativa a medição
+type AllocationBudgetConfig struct {
+	// Bytes é o máximo de bytes alocados por uma requisição
+	Bytes uint64
+
+	// SampleEvery mede uma a cada SampleEvery requisições (0 ou 1 mede todas)
+	SampleEvery int
+}
+
+// DefaultAllocationBudgetConfig retorna a configuração padrão: desativada,
+// medindo 1% das requisições quando ativada
+func DefaultAllocationBudgetConfig() AllocationBudgetConfig {
+	return AllocationBudgetConfig{SampleEvery: 100}
+}
+
+// routeAllocations acumula as medições de uma rota
+type routeAllocations struct {
+	samples    int64
+	overBudget int64
+	allocs     uint64
+	bytes      uint64
+	maxBytes   uint64
+}
+
+// AllocationBudget mede, por amostragem, quanta memória cada requisição aloca
+// e aponta as rotas acima do orçamento, em geral handlers que leem o corpo
+// inteiro da requisição ou da resposta em memória. Apenas uma requisição é
+// medida por vez; ainda assim a medição é aproximada, pois inclui o que as
+// requisições simultâneas alocaram no mesmo período
+type AllocationBudget struct {
+	cfg      AllocationBudgetConfig
+	requests atomic.Int64
+	sampling atomic.Bool
+
+	mutex  sync.Mutex
+	routes map[string]*routeAllocations
+}
+
+// NewAllocationBudget cria um AllocationBudget com a configuração
+func NewAllocationBudget(cfg AllocationBudgetConfig) *AllocationBudget {
+	if cfg.SampleEvery < 1 {
+		cfg.SampleEvery = 1
+	}
+	return &AllocationBudget{cfg: cfg, routes: make(map[string]*routeAllocations)}
+}
+
+// Enabled indica se o orçamento está configurado
+func (b *AllocationBudget) Enabled() bool {
+	return b.cfg.Bytes > 0
+}
+
+// Budget retorna o orçamento em bytes
+func (b *AllocationBudget) Budget() uint64 {
+	return b.cfg.Bytes
+}
+
+// Start decide se a requisição será medida. Se sim, retorna a função que
+// encerra a medição, registra o resultado na rota ("GET /api/v1/data") e
+// informa os bytes alocados e se o orçamento foi excedido; se não, retorna nil
+func (b *AllocationBudget) Start() func(route string) (bytes uint64, exceeded bool) {
+	if !b.Enabled() || b.requests.Add(1)%int64(b.cfg.SampleEvery) != 0 {
+		return nil
+	}
+	if !b.sampling.CompareAndSwap(false, true) {
+		return nil // outra requisição já está sendo medida
+	}
+
+	mallocs, startBytes := metrics.ReadAllocations()
+	return func(route string) (uint64, bool) {
+		afterMallocs, afterBytes := metrics.ReadAllocations()
+		b.sampling.Store(false)
+
+		bytes := afterBytes - startBytes
+		exceeded := bytes > b.cfg.Bytes
+		b.observe(route, afterMallocs-mallocs, bytes, exceeded)
+		return bytes, exceeded
+	}
+}
+
+// observe acumula uma medição da rota
+func (b *AllocationBudget) observe(route string, allocs, bytes uint64, exceeded bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	r, exists := b.routes[route]
+	if !exists {
+		r = &routeAllocations{}
+		b.routes[route] = r
+	}
+	r.samples++
+	r.allocs += allocs
+	r.bytes += bytes
+	if bytes > r.maxBytes {
+		r.maxBytes = bytes
+	}
+	if exceeded {
+		r.overBudget++
+	}
+}
+
+// OverBudget lista as rotas que excederam o orçamento ao menos uma vez, das
+// que mais excederam para as que menos excederam
+func (b *AllocationBudget) OverBudget() []models.RouteAllocationStats {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	result := make([]models.RouteAllocationStats, 0)
+	for route, r := range b.routes {
+		if r.overBudget == 0 {
+			continue
+		}
+		result = append(result, models.RouteAllocationStats{
+			Route:       route,
+			Samples:     r.samples,
+			OverBudget:  r.overBudget,
+			AvgAllocs:   float64(r.allocs) / float64(r.samples),
+			AvgBytes:    float64(r.bytes) / float64(r.samples),
+			MaxBytes:    r.maxBytes,
+			BudgetBytes: b.cfg.Bytes,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].OverBudget != result[j].OverBudget {
+			return result[i].OverBudget > result[j].OverBudget
+		}
+		return result[i].Route < result[j].Route
+	})
+	return result
+}
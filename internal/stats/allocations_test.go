@@ -0,0 +1,56 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// allocationSink mantém as alocações dos testes no heap
+var allocationSink [][]byte
+
+func TestAllocationBudget(t *testing.T) {
+	budget := NewAllocationBudget(AllocationBudgetConfig{Bytes: 1 << 20, SampleEvery: 1})
+	assert.True(t, budget.Enabled())
+
+	measure := func(route string, size int) bool {
+		done := budget.Start()
+		require.NotNil(t, done)
+		allocationSink = append(allocationSink, make([]byte, size))
+		bytes, exceeded := done(route)
+		assert.GreaterOrEqual(t, bytes, uint64(size))
+		return exceeded
+	}
+
+	assert.True(t, measure("POST /api/v1/upload", 4<<20))
+	assert.False(t, measure("POST /api/v1/upload", 1024))
+	assert.False(t, measure("GET /api/v1/data", 1024))
+
+	// Apenas as rotas que excederam o orçamento são apontadas
+	over := budget.OverBudget()
+	require.Len(t, over, 1)
+	assert.Equal(t, "POST /api/v1/upload", over[0].Route)
+	assert.Equal(t, int64(2), over[0].Samples)
+	assert.Equal(t, int64(1), over[0].OverBudget)
+	assert.GreaterOrEqual(t, over[0].MaxBytes, uint64(4<<20))
+	assert.Equal(t, uint64(1<<20), over[0].BudgetBytes)
+	allocationSink = nil
+}
+
+func TestAllocationBudget_Sampling(t *testing.T) {
+	// Sem orçamento, nenhuma requisição é medida
+	assert.Nil(t, NewAllocationBudget(DefaultAllocationBudgetConfig()).Start())
+
+	budget := NewAllocationBudget(AllocationBudgetConfig{Bytes: 1 << 20, SampleEvery: 2})
+	assert.Nil(t, budget.Start())
+	done := budget.Start()
+	require.NotNil(t, done)
+
+	// Uma requisição é medida por vez
+	assert.Nil(t, budget.Start())
+	assert.Nil(t, budget.Start())
+	done("GET /api/v1/data")
+	assert.Nil(t, budget.Start())
+	assert.NotNil(t, budget.Start())
+}
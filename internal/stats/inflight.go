@@ -0,0 +1,61 @@
+package stats
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// InFlight conta as requisições em andamento, para acompanhar o
+// encerramento gracioso do servidor
+type InFlight struct {
+	count atomic.Int64
+
+	mutex  sync.Mutex
+	routes map[string]int
+}
+
+// NewInFlight cria um contador vazio
+func NewInFlight() *InFlight {
+	return &InFlight{routes: make(map[string]int)}
+}
+
+// Start registra o início de uma requisição na rota ("GET /api/v1/data") e
+// retorna a função que registra o seu fim
+func (f *InFlight) Start(route string) func() {
+	f.count.Add(1)
+	f.mutex.Lock()
+	f.routes[route]++
+	f.mutex.Unlock()
+
+	return func() {
+		f.mutex.Lock()
+		if f.routes[route]--; f.routes[route] == 0 {
+			delete(f.routes, route)
+		}
+		f.mutex.Unlock()
+		f.count.Add(-1)
+	}
+}
+
+// Count retorna o número de requisições em andamento
+func (f *InFlight) Count() int64 {
+	return f.count.Load()
+}
+
+// Routes lista as rotas com requisições em andamento e quantas são, em ordem
+func (f *InFlight) Routes() []string {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	routes := make([]string, 0, len(f.routes))
+	for route, count := range f.routes {
+		if count > 1 {
+			route = fmt.Sprintf("%s (%d)", route, count)
+		}
+		routes = append(routes, route)
+	}
+	sort.Strings(routes)
+	return routes
+}
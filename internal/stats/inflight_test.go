@@ -0,0 +1,27 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInFlight(t *testing.T) {
+	inFlight := NewInFlight()
+
+	doneList := inFlight.Start("GET /api/v1/data")
+	doneExport := inFlight.Start("POST /api/v1/export")
+	doneList2 := inFlight.Start("GET /api/v1/data")
+
+	assert.Equal(t, int64(3), inFlight.Count())
+	assert.Equal(t, []string{"GET /api/v1/data (2)", "POST /api/v1/export"}, inFlight.Routes())
+
+	doneList()
+	doneExport()
+	assert.Equal(t, int64(1), inFlight.Count())
+	assert.Equal(t, []string{"GET /api/v1/data"}, inFlight.Routes())
+
+	doneList2()
+	assert.Zero(t, inFlight.Count())
+	assert.Empty(t, inFlight.Routes())
+}
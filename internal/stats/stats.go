@@ -0,0 +1,116 @@
+// Package stats acumula estatísticas de tráfego em memória (taxa de
+// requisições, latência média e erros recentes) para o painel de operações.
+package stats
+
+import (
+	"sync"
+	"time"
+
+	"callable-api/internal/models"
+)
+
+const (
+	// windowSeconds é o período coberto pelas estatísticas de taxa (5 minutos)
+	windowSeconds = 300
+	// maxRecentErrors é o número de erros recentes mantidos
+	maxRecentErrors = 20
+)
+
+// bucket acumula as requisições de um segundo
+type bucket struct {
+	second    int64
+	requests  int
+	errors    int
+	latencyMs float64
+}
+
+// Collector acumula as estatísticas das requisições observadas
+type Collector struct {
+	mutex        sync.Mutex
+	total        int64
+	buckets      [windowSeconds]bucket
+	recentErrors []models.RequestError
+	now          func() time.Time
+}
+
+// NewCollector cria um Collector vazio
+func NewCollector() *Collector {
+	return &Collector{now: time.Now}
+}
+
+// Observe registra uma requisição concluída. Respostas 5xx são contadas como
+// erros e mantidas na lista de erros recentes
+func (c *Collector) Observe(method, route string, status int, latency time.Duration, message string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.now()
+	b := c.bucket(now.Unix())
+	b.requests++
+	b.latencyMs += float64(latency.Microseconds()) / 1000
+	c.total++
+
+	if status >= 500 {
+		b.errors++
+		c.recentErrors = append(c.recentErrors, models.RequestError{
+			Time:    now.UTC(),
+			Method:  method,
+			Route:   route,
+			Status:  status,
+			Message: message,
+		})
+		if len(c.recentErrors) > maxRecentErrors {
+			c.recentErrors = c.recentErrors[len(c.recentErrors)-maxRecentErrors:]
+		}
+	}
+}
+
+// bucket retorna o bucket do segundo informado, reiniciando-o se for antigo
+func (c *Collector) bucket(second int64) *bucket {
+	b := &c.buckets[second%windowSeconds]
+	if b.second != second {
+		*b = bucket{second: second}
+	}
+	return b
+}
+
+// Snapshot retorna o resumo do tráfego recente
+func (c *Collector) Snapshot() models.RequestStats {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.now().Unix()
+	stats := models.RequestStats{Total: c.total}
+	latencyMs := 0.0
+	for i := range c.buckets {
+		b := &c.buckets[i]
+		age := now - b.second
+		if age < 0 || age >= windowSeconds {
+			continue
+		}
+		stats.LastFiveMinutes += b.requests
+		latencyMs += b.latencyMs
+		if age < 60 {
+			stats.LastMinute += b.requests
+			stats.ErrorsLastMinute += b.errors
+		}
+	}
+
+	stats.PerSecond = float64(stats.LastMinute) / 60
+	if stats.LastFiveMinutes > 0 {
+		stats.AvgLatencyMs = latencyMs / float64(stats.LastFiveMinutes)
+	}
+	return stats
+}
+
+// RecentErrors retorna os erros recentes, mais recentes primeiro
+func (c *Collector) RecentErrors() []models.RequestError {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	result := make([]models.RequestError, 0, len(c.recentErrors))
+	for i := len(c.recentErrors) - 1; i >= 0; i-- {
+		result = append(result, c.recentErrors[i])
+	}
+	return result
+}
@@ -0,0 +1,39 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollector(t *testing.T) {
+	now := time.Date(2023, 5, 22, 14, 0, 0, 0, time.UTC)
+	collector := NewCollector()
+	collector.now = func() time.Time { return now }
+
+	collector.Observe("GET", "/api/v1/data", 200, 10*time.Millisecond, "")
+	collector.Observe("POST", "/api/v1/data", 500, 30*time.Millisecond, "falha")
+
+	// Requisição de dois minutos atrás conta apenas nos últimos 5 minutos
+	now = now.Add(2 * time.Minute)
+	collector.Observe("GET", "/api/v1/data/:id", 503, 20*time.Millisecond, "")
+
+	snapshot := collector.Snapshot()
+	assert.Equal(t, int64(3), snapshot.Total)
+	assert.Equal(t, 1, snapshot.LastMinute)
+	assert.Equal(t, 3, snapshot.LastFiveMinutes)
+	assert.Equal(t, 1, snapshot.ErrorsLastMinute)
+	assert.InDelta(t, 20.0, snapshot.AvgLatencyMs, 0.001)
+
+	errs := collector.RecentErrors()
+	assert.Len(t, errs, 2)
+	assert.Equal(t, "/api/v1/data/:id", errs[0].Route)
+	assert.Equal(t, "falha", errs[1].Message)
+
+	// Após a janela de 5 minutos as contagens zeram, mas o total permanece
+	now = now.Add(10 * time.Minute)
+	snapshot = collector.Snapshot()
+	assert.Equal(t, 0, snapshot.LastFiveMinutes)
+	assert.Equal(t, int64(3), snapshot.Total)
+}
@@ -0,0 +1,147 @@
+// Package streams mantém as conexões de streaming abertas (Server-Sent
+// Events) e repassa a cada uma os eventos de domínio que ela acompanha. No
+// encerramento do servidor, Drain avisa todas as conexões, para que os
+// clientes se reconectem a outra instância antes de a conexão ser fechada.
+package streams
+
+import (
+	"context"
+	stderrors "errors"
+	"sync"
+	"time"
+
+	"callable-api/internal/events"
+)
+
+// ErrDraining indica que o servidor está em encerramento e não aceita novas
+// conexões de streaming
+var ErrDraining = stderrors.New("servidor em encerramento")
+
+// Config define o comportamento das conexões de streaming
+type Config struct {
+	// ReconnectAfter é o intervalo sugerido aos clientes para a reconexão
+	// (campo retry do SSE), inclusive no aviso de encerramento
+	ReconnectAfter time.Duration
+
+	// Heartbeat é o intervalo dos comentários que mantêm a conexão aberta
+	// nos proxies
+	Heartbeat time.Duration
+
+	// Buffer é o número de eventos aguardando envio por conexão; eventos
+	// além dele são descartados para não bloquear quem publica
+	Buffer int
+}
+
+// DefaultConfig retorna a configuração padrão: reconexão em 5s, heartbeat a
+// cada 15s e até 16 eventos pendentes por conexão
+func DefaultConfig() Config {
+	return Config{ReconnectAfter: 5 * time.Second, Heartbeat: 15 * time.Second, Buffer: 16}
+}
+
+// Hub registra as conexões de streaming abertas
+type Hub struct {
+	cfg Config
+
+	mu            sync.Mutex
+	subscriptions map[*Subscription]struct{}
+	draining      chan struct{}
+	drainOnce     sync.Once
+}
+
+// NewHub cria um Hub sem conexões
+func NewHub(cfg Config) *Hub {
+	if cfg.Buffer <= 0 {
+		cfg.Buffer = DefaultConfig().Buffer
+	}
+	return &Hub{
+		cfg:           cfg,
+		subscriptions: make(map[*Subscription]struct{}),
+		draining:      make(chan struct{}),
+	}
+}
+
+// Config retorna a configuração do Hub
+func (h *Hub) Config() Config {
+	return h.cfg
+}
+
+// Subscribe registra uma conexão que recebe os eventos aceitos por filter.
+// Retorna ErrDraining se o servidor já está em encerramento
+func (h *Hub) Subscribe(filter func(envelope *events.Envelope) bool) (*Subscription, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	select {
+	case <-h.draining:
+		return nil, ErrDraining
+	default:
+	}
+
+	sub := &Subscription{
+		hub:    h,
+		filter: filter,
+		events: make(chan *events.Envelope, h.cfg.Buffer),
+	}
+	h.subscriptions[sub] = struct{}{}
+	return sub, nil
+}
+
+// Publish implementa events.Subscriber, repassando o envelope às conexões
+// que o acompanham sem bloquear quem publicou
+func (h *Hub) Publish(_ context.Context, envelope *events.Envelope) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subscriptions {
+		if sub.filter != nil && !sub.filter(envelope) {
+			continue
+		}
+		select {
+		case sub.events <- envelope:
+		default:
+		}
+	}
+}
+
+// Drain inicia o encerramento: novas conexões são recusadas e as abertas são
+// avisadas (ver Subscription.Draining). Pode ser chamado mais de uma vez;
+// normalmente é registrado com http.Server.RegisterOnShutdown
+func (h *Hub) Drain() {
+	h.drainOnce.Do(func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		close(h.draining)
+	})
+}
+
+// Open retorna o número de conexões abertas
+func (h *Hub) Open() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subscriptions)
+}
+
+// Subscription é uma conexão de streaming registrada no Hub
+type Subscription struct {
+	hub    *Hub
+	filter func(envelope *events.Envelope) bool
+	events chan *events.Envelope
+}
+
+// Events retorna os eventos acompanhados pela conexão
+func (s *Subscription) Events() <-chan *events.Envelope {
+	return s.events
+}
+
+// Draining é fechado quando o servidor inicia o encerramento: a conexão deve
+// avisar o cliente e terminar
+func (s *Subscription) Draining() <-chan struct{} {
+	return s.hub.draining
+}
+
+// Close remove a conexão do Hub
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	delete(s.hub.subscriptions, s)
+}
@@ -0,0 +1,56 @@
+package streams
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"callable-api/internal/events"
+)
+
+func TestHub_Publish(t *testing.T) {
+	hub := NewHub(Config{Buffer: 1})
+
+	jobs, err := hub.Subscribe(func(envelope *events.Envelope) bool {
+		return envelope.Type == events.TypeJobCompleted
+	})
+	require.NoError(t, err)
+	all, err := hub.Subscribe(nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, hub.Open())
+
+	ctx := context.Background()
+	hub.Publish(ctx, &events.Envelope{ID: "1", Type: events.TypeItemCreated})
+	hub.Publish(ctx, &events.Envelope{ID: "2", Type: events.TypeJobCompleted})
+
+	assert.Equal(t, "2", (<-jobs.Events()).ID)
+	// Com o buffer cheio, o segundo evento é descartado sem bloquear
+	assert.Equal(t, "1", (<-all.Events()).ID)
+	assert.Empty(t, all.Events())
+
+	jobs.Close()
+	all.Close()
+	assert.Zero(t, hub.Open())
+}
+
+func TestHub_Drain(t *testing.T) {
+	hub := NewHub(DefaultConfig())
+	sub, err := hub.Subscribe(nil)
+	require.NoError(t, err)
+
+	select {
+	case <-sub.Draining():
+		t.Fatal("conexão avisada antes do encerramento")
+	default:
+	}
+
+	hub.Drain()
+	hub.Drain()
+
+	// As conexões abertas são avisadas e as novas, recusadas
+	<-sub.Draining()
+	_, err = hub.Subscribe(nil)
+	assert.ErrorIs(t, err, ErrDraining)
+}
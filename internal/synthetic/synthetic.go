@@ -0,0 +1,126 @@
+// Package synthetic gera grandes volumes de usuários e itens diretamente nos
+// repositórios, para os testes de carga das listagens, da busca e do cache
+// (ver o comando loadgen em cmd/api). Os dados são determinísticos: a mesma
+// semente gera os mesmos nomes, donos e valores.
+package synthetic
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"callable-api/internal/models"
+	"callable-api/internal/repository"
+)
+
+// Password é a senha de todos os usuários sintéticos
+const Password = "synthetic123"
+
+// Config define o volume dos dados gerados
+type Config struct {
+	Users int
+	Items int
+
+	// Seed é a semente do gerador (0 usa 1)
+	Seed int64
+}
+
+// Enabled indica se algum dado deve ser gerado
+func (c Config) Enabled() bool {
+	return c.Users > 0 || c.Items > 0
+}
+
+// Summary resume os dados gerados
+type Summary struct {
+	Users       int
+	Items       int
+	PublicItems int
+}
+
+// UserEmail retorna o email do i-ésimo usuário sintético (a partir de 1)
+func UserEmail(i int) string {
+	return fmt.Sprintf("synthetic-%d@example.test", i)
+}
+
+// adjectives e nouns compõem os nomes dos itens, para que as buscas por um
+// termo encontrem parte dos itens
+var (
+	adjectives = []string{"azul", "verde", "antigo", "novo", "grande", "pequeno", "leve", "pesado", "raro", "comum"}
+	nouns      = []string{"caderno", "caneta", "mesa", "cadeira", "lâmpada", "mochila", "relógio", "garrafa", "teclado", "livro", "quadro", "vaso"}
+)
+
+// Words retorna os termos usados nos nomes dos itens
+func Words() []string {
+	words := make([]string, 0, len(adjectives)+len(nouns))
+	words = append(words, nouns...)
+	return append(words, adjectives...)
+}
+
+// Seed grava os usuários e os itens nos repositórios. Um quarto dos itens é
+// público; os demais pertencem aos usuários sintéticos (ou são públicos,
+// quando nenhum usuário é gerado)
+func Seed(ctx context.Context, cfg Config, users repository.UserRepository, items repository.ItemRepository) (*Summary, error) {
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = 1
+	}
+	rng := rand.New(rand.NewSource(seed))
+	summary := &Summary{}
+
+	// Todos os usuários compartilham o mesmo hash, para que gerar milhares de
+	// usuários não custe milhares de bcrypts
+	var hash []byte
+	if cfg.Users > 0 {
+		var err error
+		if hash, err = bcrypt.GenerateFromPassword([]byte(Password), bcrypt.MinCost); err != nil {
+			return nil, err
+		}
+	}
+
+	ownerIDs := make([]string, 0, cfg.Users)
+	for i := 1; i <= cfg.Users; i++ {
+		if err := ctx.Err(); err != nil {
+			return summary, err
+		}
+		user, err := users.Create(&models.User{
+			Email:    UserEmail(i),
+			Name:     fmt.Sprintf("Usuário sintético %d", i),
+			Password: string(hash),
+			Role:     "user",
+		})
+		if err != nil {
+			return summary, fmt.Errorf("criar o usuário sintético %d: %w", i, err)
+		}
+		ownerIDs = append(ownerIDs, user.ID)
+		summary.Users++
+	}
+
+	for i := 1; i <= cfg.Items; i++ {
+		if err := ctx.Err(); err != nil {
+			return summary, err
+		}
+
+		ownerID := ""
+		if len(ownerIDs) > 0 && rng.Intn(4) != 0 {
+			ownerID = ownerIDs[rng.Intn(len(ownerIDs))]
+		}
+		noun, adjective := nouns[rng.Intn(len(nouns))], adjectives[rng.Intn(len(adjectives))]
+		_, err := items.Create(ctx, &models.InputData{
+			Name:        fmt.Sprintf("%s %s %d", noun, adjective, i),
+			Value:       fmt.Sprintf("SYN-%07d", i),
+			Description: fmt.Sprintf("Item sintético: %s %s", noun, adjective),
+			Email:       fmt.Sprintf("item-%d@example.test", i),
+			OwnerID:     ownerID,
+		})
+		if err != nil {
+			return summary, fmt.Errorf("criar o item sintético %d: %w", i, err)
+		}
+		summary.Items++
+		if ownerID == "" {
+			summary.PublicItems++
+		}
+	}
+	return summary, nil
+}
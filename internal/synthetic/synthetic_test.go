@@ -0,0 +1,63 @@
+package synthetic
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"callable-api/internal/models"
+	"callable-api/internal/repository"
+	"callable-api/internal/repository/testsupport"
+)
+
+func TestSeed(t *testing.T) {
+	ctx := context.Background()
+	seed := func() (*Summary, *repository.InMemoryUserRepository, *repository.InMemoryItemRepository) {
+		users := testsupport.NewUserRepository()
+		items := repository.NewEmptyInMemoryItemRepository()
+		summary, err := Seed(ctx, Config{Users: 5, Items: 200, Seed: 42}, users, items)
+		require.NoError(t, err)
+		return summary, users, items
+	}
+
+	summary, users, items := seed()
+	assert.Equal(t, 5, summary.Users)
+	assert.Equal(t, 200, summary.Items)
+	assert.Greater(t, summary.PublicItems, 0)
+	assert.Less(t, summary.PublicItems, 200)
+
+	// Os itens públicos são visíveis a clientes anônimos
+	_, public, err := items.FindAll(ctx, models.ItemAccess{}, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, summary.PublicItems, public)
+
+	// Os usuários entram com a senha padrão
+	_, err = users.Authenticate(UserEmail(3), Password)
+	assert.NoError(t, err)
+
+	// A busca por um termo encontra parte dos itens
+	_, found, err := items.Search(ctx, models.ItemAccess{All: true}, Words()[0], 1, 10)
+	require.NoError(t, err)
+	assert.Greater(t, found, 0)
+	assert.Less(t, found, 200)
+
+	// A mesma semente gera os mesmos dados
+	_, _, again := seed()
+	first, _, err := items.FindAll(ctx, models.ItemAccess{All: true}, 1, 20)
+	require.NoError(t, err)
+	second, _, err := again.FindAll(ctx, models.ItemAccess{All: true}, 1, 20)
+	require.NoError(t, err)
+	for i := range first {
+		assert.Equal(t, first[i].Name, second[i].Name)
+	}
+}
+
+func TestSeed_ItemsOnly(t *testing.T) {
+	items := repository.NewEmptyInMemoryItemRepository()
+	summary, err := Seed(context.Background(), Config{Items: 10}, testsupport.NewUserRepository(), items)
+	require.NoError(t, err)
+	assert.Equal(t, 10, summary.PublicItems)
+	assert.False(t, Config{}.Enabled())
+}
@@ -0,0 +1,99 @@
+package testsupport
+
+import (
+	"context"
+	"testing"
+
+	"callable-api/client"
+)
+
+// UserFixture é um usuário cadastrado pelo endpoint de registro
+type UserFixture struct {
+	Email    string
+	Name     string
+	Password string
+}
+
+// ItemFixture é um item criado pelo usuário Owner (o email de um dos
+// usuários das fixtures; vazio cria o item como administrador)
+type ItemFixture struct {
+	Owner string
+	Input client.InputData
+}
+
+// Fixtures são os dados semeados antes de um teste
+type Fixtures struct {
+	Users []UserFixture
+	Items []ItemFixture
+}
+
+// DefaultFixtures retorna dois usuários comuns com um item cada
+func DefaultFixtures() Fixtures {
+	return Fixtures{
+		Users: []UserFixture{
+			{Email: "ana@example.test", Name: "Ana", Password: "senha-ana"},
+			{Email: "bruno@example.test", Name: "Bruno", Password: "senha-bruno"},
+		},
+		Items: []ItemFixture{
+			{Owner: "ana@example.test", Input: client.InputData{Name: "Caderno da Ana", Value: "ANA-1", Email: "ana@example.test"}},
+			{Owner: "bruno@example.test", Input: client.InputData{Name: "Caneta do Bruno", Value: "BRUNO-1", Email: "bruno@example.test"}},
+		},
+	}
+}
+
+// Seeded reúne os dados criados por Seed
+type Seeded struct {
+	// Users e Clients são indexados pelo email dos usuários; os clientes já
+	// estão autenticados
+	Users   map[string]*client.UserResponse
+	Clients map[string]*client.Client
+
+	// Items segue a ordem das fixtures
+	Items []client.Item
+}
+
+// Seed cria as fixtures pelos endpoints da API. O teste falha se algum dado
+// não puder ser criado
+func (s *Server) Seed(t testing.TB, fixtures Fixtures) *Seeded {
+	t.Helper()
+	ctx := context.Background()
+
+	seeded := &Seeded{
+		Users:   make(map[string]*client.UserResponse, len(fixtures.Users)),
+		Clients: make(map[string]*client.Client, len(fixtures.Users)),
+	}
+	for _, fixture := range fixtures.Users {
+		user, err := s.Client().Register(ctx, &client.RegisterUserInput{
+			Email:    fixture.Email,
+			Name:     fixture.Name,
+			Password: fixture.Password,
+		})
+		if err != nil {
+			t.Fatalf("cadastro de %s: %v", fixture.Email, err)
+		}
+		seeded.Users[fixture.Email] = user
+		seeded.Clients[fixture.Email] = s.Login(t, fixture.Email, fixture.Password)
+	}
+
+	var admin *client.Client
+	for _, fixture := range fixtures.Items {
+		owner, ok := seeded.Clients[fixture.Owner]
+		if fixture.Owner == "" {
+			if admin == nil {
+				admin = s.Admin(t)
+			}
+			owner, ok = admin, true
+		}
+		if !ok {
+			t.Fatalf("item %q: usuário %s não está nas fixtures", fixture.Input.Name, fixture.Owner)
+		}
+
+		input := fixture.Input
+		item, err := owner.CreateItem(ctx, &input)
+		if err != nil {
+			t.Fatalf("criação do item %q: %v", fixture.Input.Name, err)
+		}
+		seeded.Items = append(seeded.Items, *item)
+	}
+	return seeded
+}
@@ -0,0 +1,215 @@
+// Package validation centraliza as regras de validação da aplicação: registra
+// validadores customizados no validador usado pelo binding do Gin, de modo que
+// as tags `binding` das structs sejam a única fonte das regras, e descreve as
+// falhas por campo de forma uniforme, com as mensagens do catálogo do pacote
+// messages.
+//
+// O pacote não depende de pkg/errors para poder ser usado pelos models; a
+// conversão para errors.ValidationError fica a cargo de services e handlers.
+package validation
+
+import (
+	"fmt"
+	"net/mail"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+
+	catalog "callable-api/internal/messages"
+)
+
+// Tags dos validadores customizados
+const (
+	TagEmail    = "valid_email"
+	TagRFC3339  = "rfc3339"
+	TagNotBlank = "not_blank"
+	TagPhone    = "phone"
+	TagLocale   = "locale"
+	TagTimezone = "tz"
+)
+
+// phonePattern aceita telefones no formato E.164 (ex.: +5511999998888)
+var phonePattern = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+
+// localePattern aceita tags de idioma BCP 47 simples (ex.: pt, pt-BR, zh-Hant-TW)
+var localePattern = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+// FieldError descreve a falha de validação de um campo
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+	Param   string `json:"-"` // parâmetro da regra (ex.: 3 em min=3)
+}
+
+// Errors agrupa as falhas de validação de uma struct
+type Errors []FieldError
+
+// Error implementa error listando os campos inválidos
+func (e Errors) Error() string {
+	parts := make([]string, 0, len(e))
+	for _, fe := range e {
+		parts = append(parts, fe.Field+": "+fe.Message)
+	}
+	return "dados inválidos (" + strings.Join(parts, "; ") + ")"
+}
+
+var registerOnce sync.Once
+
+func init() {
+	Register()
+}
+
+// Register registra os validadores customizados e o uso dos nomes JSON dos
+// campos no validador do Gin. É chamado na inicialização do pacote e é seguro
+// chamar mais de uma vez
+func Register() {
+	registerOnce.Do(func() {
+		v, ok := binding.Validator.Engine().(*validator.Validate)
+		if !ok {
+			panic("validation: o validador do Gin não é o go-playground/validator")
+		}
+
+		// Reportar os campos pelo nome usado no JSON (ex.: created_at)
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+			if name == "-" {
+				return ""
+			}
+			if name == "" {
+				return field.Name
+			}
+			return name
+		})
+
+		mustRegister(v, TagEmail, isValidEmail)
+		mustRegister(v, TagRFC3339, isRFC3339)
+		mustRegister(v, TagNotBlank, isNotBlank)
+		mustRegister(v, TagPhone, isPhone)
+		mustRegister(v, TagLocale, isLocale)
+		mustRegister(v, TagTimezone, isTimezone)
+	})
+}
+
+// mustRegister registra um validador, abortando em caso de tag inválida
+func mustRegister(v *validator.Validate, tag string, fn validator.Func) {
+	if err := v.RegisterValidation(tag, fn); err != nil {
+		panic(fmt.Sprintf("validation: falha ao registrar %q: %v", tag, err))
+	}
+}
+
+// isValidEmail valida endereços de email no formato "usuario@dominio.tld"
+func isValidEmail(fl validator.FieldLevel) bool {
+	value := fl.Field().String()
+	addr, err := mail.ParseAddress(value)
+	if err != nil || addr.Address != value {
+		return false
+	}
+	at := strings.LastIndex(value, "@")
+	return strings.Contains(value[at+1:], ".")
+}
+
+// isRFC3339 valida datas no formato RFC3339 (ex.: 2023-05-22T14:56:32Z)
+func isRFC3339(fl validator.FieldLevel) bool {
+	_, err := time.Parse(time.RFC3339, fl.Field().String())
+	return err == nil
+}
+
+// isNotBlank rejeita textos compostos apenas por espaços
+func isNotBlank(fl validator.FieldLevel) bool {
+	return strings.TrimSpace(fl.Field().String()) != ""
+}
+
+// isPhone valida telefones E.164. O texto vazio é aceito e remove o valor
+func isPhone(fl validator.FieldLevel) bool {
+	value := fl.Field().String()
+	return value == "" || phonePattern.MatchString(value)
+}
+
+// isLocale valida tags de idioma BCP 47. O texto vazio é aceito e remove o valor
+func isLocale(fl validator.FieldLevel) bool {
+	value := fl.Field().String()
+	return value == "" || localePattern.MatchString(value)
+}
+
+// isTimezone valida nomes de fuso horário IANA (ex.: America/Sao_Paulo). O
+// texto vazio é aceito e remove o valor
+func isTimezone(fl validator.FieldLevel) bool {
+	value := fl.Field().String()
+	if value == "" {
+		return true
+	}
+	if value == "Local" {
+		return false
+	}
+	_, err := time.LoadLocation(value)
+	return err == nil
+}
+
+// Validate valida a struct com as mesmas regras aplicadas pelo binding do Gin,
+// retornando Errors (ou nil se a struct for válida)
+func Validate(obj interface{}) error {
+	if err := binding.Validator.ValidateStruct(obj); err != nil {
+		if errs, ok := Translate(err); ok {
+			return errs
+		}
+		return err
+	}
+	return nil
+}
+
+// Translate converte erros do validador (inclusive os retornados por
+// c.ShouldBindJSON) em Errors. Retorna false para erros que não são de
+// validação, como JSON malformado
+func Translate(err error) (Errors, bool) {
+	fieldErrs, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return nil, false
+	}
+
+	errs := make(Errors, 0, len(fieldErrs))
+	for _, fe := range fieldErrs {
+		errs = append(errs, FieldError{
+			Field:   fe.Field(),
+			Rule:    fe.Tag(),
+			Message: Message(fe),
+			Param:   fe.Param(),
+		})
+	}
+	return errs, true
+}
+
+// Message retorna a mensagem legível para a falha de validação de um campo,
+// no idioma padrão
+func Message(fe validator.FieldError) string {
+	return render(catalog.DefaultLocale, fe.Tag(), fe.Field(), fe.Param())
+}
+
+// Localize retorna as falhas com as mensagens no idioma informado
+func (e Errors) Localize(locale string) Errors {
+	localized := make(Errors, len(e))
+	for i, fe := range e {
+		fe.Message = render(locale, fe.Rule, fe.Field, fe.Param)
+		localized[i] = fe
+	}
+	return localized
+}
+
+// render busca a mensagem da regra no catálogo, formatando o parâmetro da regra
+func render(locale, rule, field, param string) string {
+	switch rule {
+	case "min", "max":
+		return catalog.Text(locale, rule, field, param)
+	case "oneof":
+		return catalog.Text(locale, rule, field, strings.ReplaceAll(param, " ", ", "))
+	case "required_without", "excluded_with":
+		return catalog.Text(locale, rule, field, strings.ToLower(param))
+	default:
+		return catalog.Text(locale, rule, field)
+	}
+}
@@ -0,0 +1,97 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type sample struct {
+	Name      string `json:"name" binding:"required,not_blank,min=3"`
+	Email     string `json:"email" binding:"omitempty,valid_email"`
+	CreatedAt string `json:"created_at" binding:"omitempty,rfc3339"`
+}
+
+// messages indexa as mensagens de erro pelo nome do campo
+func messages(err error) map[string]string {
+	result := map[string]string{}
+	if errs, ok := err.(Errors); ok {
+		for _, fe := range errs {
+			result[fe.Field] = fe.Message
+		}
+	}
+	return result
+}
+
+func TestValidate(t *testing.T) {
+	t.Run("Struct válida", func(t *testing.T) {
+		err := Validate(&sample{Name: "Valid", Email: "user@example.com", CreatedAt: "2023-05-22T14:56:32Z"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("Erros usam o nome JSON dos campos", func(t *testing.T) {
+		err := Validate(&sample{Name: "   ", Email: "invalid-email", CreatedAt: "2023-13-42T99:99:99Z"})
+
+		errs, ok := err.(Errors)
+		assert.True(t, ok)
+		assert.Len(t, errs, 3)
+
+		msgs := messages(err)
+		assert.Equal(t, "Não pode conter apenas espaços", msgs["name"])
+		assert.Equal(t, "Email inválido", msgs["email"])
+		assert.Contains(t, msgs["created_at"], "RFC3339")
+		assert.Contains(t, err.Error(), "created_at")
+	})
+
+	t.Run("Campo obrigatório", func(t *testing.T) {
+		err := Validate(&sample{})
+		assert.Equal(t, "Campo obrigatório", messages(err)["name"])
+		assert.Equal(t, "required", err.(Errors)[0].Rule)
+	})
+
+	t.Run("Mensagens no idioma da requisição", func(t *testing.T) {
+		err := Validate(&sample{Name: "ab"})
+		localized := err.(Errors).Localize("en")
+		assert.Equal(t, "min", localized[0].Rule)
+		assert.Equal(t, "Must have at least 3 characters", localized[0].Message)
+		assert.Equal(t, "Deve ter pelo menos 3 caracteres", messages(err)["name"])
+	})
+}
+
+func TestEmailValidator(t *testing.T) {
+	for _, email := range []string{"user@example.com", "first.last@sub.example.org"} {
+		assert.NoError(t, Validate(&sample{Name: "Valid", Email: email}), email)
+	}
+	for _, email := range []string{"invalid-email", "user@localhost", "Nome <user@example.com>", "user@@example.com"} {
+		assert.Error(t, Validate(&sample{Name: "Valid", Email: email}), email)
+	}
+}
+
+func TestTranslate_NonValidationError(t *testing.T) {
+	_, ok := Translate(assert.AnError)
+	assert.False(t, ok)
+}
+
+type profileSample struct {
+	Phone    *string `json:"phone" binding:"omitempty,phone"`
+	Locale   *string `json:"locale" binding:"omitempty,locale"`
+	Timezone *string `json:"timezone" binding:"omitempty,tz"`
+}
+
+func TestProfileValidators(t *testing.T) {
+	str := func(s string) *string { return &s }
+
+	assert.NoError(t, Validate(&profileSample{}))
+	assert.NoError(t, Validate(&profileSample{Phone: str("+5511999998888"), Locale: str("pt-BR"), Timezone: str("America/Sao_Paulo")}))
+
+	// Texto vazio é aceito para remover o valor
+	assert.NoError(t, Validate(&profileSample{Phone: str(""), Locale: str(""), Timezone: str("")}))
+
+	err := Validate(&profileSample{Phone: str("11 99999-8888"), Locale: str("português"), Timezone: str("Mars/Olympus")})
+	msgs := messages(err)
+	assert.Contains(t, msgs["phone"], "E.164")
+	assert.Contains(t, msgs["locale"], "BCP 47")
+	assert.Contains(t, msgs["timezone"], "IANA")
+
+	assert.Error(t, Validate(&profileSample{Timezone: str("Local")}))
+}
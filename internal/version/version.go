@@ -0,0 +1,48 @@
+// Package version expõe as informações de build da aplicação e o instante em
+// que o processo foi iniciado. Os valores de build são definidos via ldflags:
+//
+//	go build -ldflags "-X callable-api/internal/version.Version=1.2.0 -X callable-api/internal/version.Commit=$(git rev-parse --short HEAD)"
+package version
+
+import (
+	"runtime"
+	"time"
+)
+
+// Informações de build, sobrescritas via -ldflags
+var (
+	Version   = "dev"
+	Commit    = "unknown"
+	BuildTime = "unknown"
+)
+
+// startedAt é o instante de inicialização do processo
+var startedAt = time.Now()
+
+// Info descreve a versão em execução
+type Info struct {
+	Version   string `json:"version" example:"1.2.0"`
+	Commit    string `json:"commit" example:"a1b2c3d"`
+	BuildTime string `json:"build_time" example:"2023-05-22T14:56:32Z"`
+	GoVersion string `json:"go_version" example:"go1.23.0"`
+}
+
+// Get retorna as informações de build
+func Get() Info {
+	return Info{
+		Version:   Version,
+		Commit:    Commit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+	}
+}
+
+// StartedAt retorna o instante de inicialização do processo
+func StartedAt() time.Time {
+	return startedAt
+}
+
+// Uptime retorna há quanto tempo o processo está em execução
+func Uptime() time.Duration {
+	return time.Since(startedAt)
+}
@@ -0,0 +1,31 @@
+// Demonstração: lista os itens do catálogo (GET /api/v1/data). Sem token, a
+// listagem depende do acesso anônimo (ANONYMOUS_READ=full ou catalog)
+(function () {
+  var status = document.getElementById("status");
+  var list = document.getElementById("items");
+
+  fetch("/api/v1/data?limit=20", { headers: { Accept: "application/json" } })
+    .then(function (response) {
+      return response.json().then(function (body) {
+        if (!response.ok) {
+          throw new Error(body.message || response.statusText);
+        }
+        return body;
+      });
+    })
+    .then(function (body) {
+      var items = body.data || [];
+      status.textContent = items.length ? items.length + " itens" : "Nenhum item encontrado";
+      items.forEach(function (item) {
+        var li = document.createElement("li");
+        li.textContent = item.name + " ";
+        var small = document.createElement("small");
+        small.textContent = item.description || "";
+        li.appendChild(small);
+        list.appendChild(li);
+      });
+    })
+    .catch(function (err) {
+      status.textContent = "Não foi possível carregar os itens: " + err.message;
+    });
+})();
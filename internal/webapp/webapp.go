@@ -0,0 +1,164 @@
+// Package webapp serve a interface web de demonstração (uma SPA) pelo mesmo
+// binário da API, em /app/*. Os arquivos vêm do diretório configurado ou, sem
+// diretório, dos arquivos embutidos no binário. As requisições da SPA não
+// passam pelos middlewares da API (cota, estatísticas, gravação, caos).
+package webapp
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed dist
+var embedded embed.FS
+
+// Prefix é o caminho sob o qual a SPA é servida
+const Prefix = "/app"
+
+// indexFile é o documento da SPA, servido também nas rotas do cliente
+const indexFile = "index.html"
+
+// Config define a hospedagem da SPA
+type Config struct {
+	// Enabled ativa a hospedagem em /app/*
+	Enabled bool
+
+	// Dir é o diretório com os arquivos da SPA (o build do frontend). Vazio
+	// usa a interface de demonstração embutida no binário
+	Dir string
+
+	// AssetMaxAge é a validade no cache dos arquivos estáticos. O index.html
+	// é sempre revalidado, para que um novo build seja carregado imediatamente
+	AssetMaxAge time.Duration
+}
+
+// DefaultConfig retorna a configuração padrão: desativada, com a interface
+// embutida e arquivos em cache por 1 hora
+func DefaultConfig() Config {
+	return Config{AssetMaxAge: time.Hour}
+}
+
+// Handler serve os arquivos da SPA com o fallback para o index.html
+type Handler struct {
+	files       fs.FS
+	assetMaxAge time.Duration
+}
+
+// New cria o Handler a partir da configuração, validando que os arquivos
+// contêm o index.html
+func New(cfg Config) (*Handler, error) {
+	var files fs.FS
+	if cfg.Dir != "" {
+		files = os.DirFS(cfg.Dir)
+	} else {
+		sub, err := fs.Sub(embedded, "dist")
+		if err != nil {
+			return nil, err
+		}
+		files = sub
+	}
+
+	if _, err := fs.Stat(files, indexFile); err != nil {
+		return nil, fmt.Errorf("webapp: %s não encontrado em %q: %w", indexFile, cfg.Dir, err)
+	}
+	return &Handler{files: files, assetMaxAge: cfg.AssetMaxAge}, nil
+}
+
+// Mount direciona as requisições de /app/* ao Handler e as demais à API
+func Mount(api http.Handler, app *Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == Prefix || strings.HasPrefix(r.URL.Path, Prefix+"/") {
+			app.ServeHTTP(w, r)
+			return
+		}
+		api.ServeHTTP(w, r)
+	})
+}
+
+// ServeHTTP serve o arquivo pedido ou, nas rotas do cliente (caminhos sem
+// extensão que não correspondem a arquivos), o index.html. Arquivos com
+// extensão inexistentes respondem 404, para que um asset ausente não seja
+// confundido com HTML
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	if r.URL.Path == Prefix {
+		http.Redirect(w, r, Prefix+"/", http.StatusMovedPermanently)
+		return
+	}
+
+	name := strings.TrimPrefix(path.Clean(strings.TrimPrefix(r.URL.Path, Prefix)), "/")
+	if name == "" {
+		name = indexFile
+	}
+
+	if h.serveFile(w, r, name) {
+		return
+	}
+	if path.Ext(name) != "" {
+		http.NotFound(w, r)
+		return
+	}
+	if !h.serveFile(w, r, indexFile) {
+		http.NotFound(w, r)
+	}
+}
+
+// serveFile serve o arquivo, se existir, com os headers de cache. Retorna
+// false se o arquivo não existe ou é um diretório
+func (h *Handler) serveFile(w http.ResponseWriter, r *http.Request, name string) bool {
+	file, err := h.files.Open(name)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil || info.IsDir() {
+		return false
+	}
+	content, ok := file.(io.ReadSeeker)
+	if !ok {
+		return false
+	}
+
+	if name == indexFile || h.assetMaxAge <= 0 {
+		w.Header().Set("Cache-Control", "no-cache")
+	} else {
+		w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(int(h.assetMaxAge.Seconds())))
+	}
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	// Os arquivos embutidos não têm data de modificação: o ETag, calculado
+	// sobre o conteúdo, permite a revalidação em qualquer caso
+	if etag, err := contentETag(content); err == nil {
+		w.Header().Set("ETag", etag)
+	}
+	http.ServeContent(w, r, name, info.ModTime(), content)
+	return true
+}
+
+// contentETag calcula o ETag do conteúdo e volta ao início do arquivo
+func contentETag(content io.ReadSeeker) (string, error) {
+	hash := sha256.New()
+	_, copyErr := io.Copy(hash, content)
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	if copyErr != nil {
+		return "", copyErr
+	}
+	return `"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`, nil
+}
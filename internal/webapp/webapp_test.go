@@ -0,0 +1,77 @@
+package webapp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "index.html"), []byte("<html>spa</html>"), 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "assets"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "assets", "app.js"), []byte("console.log(1)"), 0o644))
+
+	app, err := New(Config{Enabled: true, Dir: dir, AssetMaxAge: time.Hour})
+	require.NoError(t, err)
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	handler := Mount(api, app)
+
+	call := func(method, path string, headers ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		for i := 0; i+1 < len(headers); i += 2 {
+			req.Header.Set(headers[i], headers[i+1])
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	// Os arquivos existentes são servidos com cache
+	w := call(http.MethodGet, "/app/assets/app.js")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "console.log(1)", w.Body.String())
+	assert.Equal(t, "public, max-age=3600", w.Header().Get("Cache-Control"))
+
+	etag := w.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.Equal(t, http.StatusNotModified, call(http.MethodGet, "/app/assets/app.js", "If-None-Match", etag).Code)
+
+	// As rotas do cliente recebem o index.html, sempre revalidado
+	for _, path := range []string{"/app/", "/app/items/42", "/app/../app/settings"} {
+		w = call(http.MethodGet, path)
+		assert.Equal(t, http.StatusOK, w.Code, path)
+		assert.Equal(t, "<html>spa</html>", w.Body.String(), path)
+		assert.Equal(t, "no-cache", w.Header().Get("Cache-Control"), path)
+	}
+
+	// Assets ausentes não recebem o index.html
+	assert.Equal(t, http.StatusNotFound, call(http.MethodGet, "/app/assets/missing.js").Code)
+	assert.Equal(t, http.StatusMovedPermanently, call(http.MethodGet, "/app").Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, call(http.MethodPost, "/app/items").Code)
+
+	// As demais requisições seguem para a API
+	assert.Equal(t, http.StatusTeapot, call(http.MethodGet, "/application").Code)
+	assert.Equal(t, http.StatusTeapot, call(http.MethodGet, "/api/v1/data").Code)
+}
+
+func TestNew(t *testing.T) {
+	// Sem diretório, a interface de demonstração embutida é usada
+	app, err := New(DefaultConfig())
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/app/", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Callable API")
+
+	_, err = New(Config{Dir: t.TempDir()})
+	assert.Error(t, err)
+}
@@ -0,0 +1,104 @@
+// Package cloud reúne as integrações com o GCP (logging, Secret Manager e
+// Cloud Storage) atrás de interfaces. Os clientes são criados em um único
+// lugar (New) e repassados juntos em Services, de modo que o restante da
+// aplicação não dependa dos tipos concretos do SDK e possa usar fakes nos
+// testes. Cada integração é opcional: o campo fica nil quando não configurada.
+// As integrações configuradas têm o estado acompanhado pelo Monitor, e suas
+// falhas são devolvidas como ErrUnavailable, para que apenas a funcionalidade
+// afetada responda 503
+package cloud
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"callable-api/pkg/config"
+	"callable-api/pkg/logger"
+	"callable-api/pkg/secrets"
+	"callable-api/pkg/storage"
+)
+
+// Logger é o logger estruturado enviado ao Cloud Logging
+type Logger = logger.Logger
+
+// Secrets é o acesso aos segredos do Secret Manager
+type Secrets = secrets.SecretManager
+
+// Storage é o acesso aos objetos de um bucket do Cloud Storage
+type Storage interface {
+	UploadFile(ctx context.Context, name string, r io.Reader) error
+	GetSignedURL(ctx context.Context, name string, ttl time.Duration) (string, error)
+}
+
+// Verificação em tempo de compilação
+var _ Storage = (*storage.CloudStorage)(nil)
+
+// Services agrupa os clientes do GCP usados pela aplicação
+type Services struct {
+	Logger  Logger
+	Secrets Secrets
+	Storage Storage
+
+	// Monitor acompanha o estado das integrações (ver Monitor.States)
+	Monitor *Monitor
+}
+
+// New cria os clientes do GCP conforme a configuração. Falhas na criação do
+// logger são registradas e o logger padrão da aplicação passa a ser usado no
+// lugar, com o logging marcado como degradado
+func New(ctx context.Context, cfg *config.Config) Services {
+	services := Services{Logger: localLogger{}, Monitor: NewMonitor()}
+
+	// Inicializar o logger com suporte a GCP
+	log, err := logger.NewGCPLogger(ctx, cfg.GCPProjectID, cfg.LoggingName, cfg.UseCloudLogging)
+	switch {
+	case err != nil:
+		services.Monitor.Set(ServiceLogging, StateDegraded, err)
+		logger.Error("Erro ao inicializar logger GCP", map[string]interface{}{
+			"error": err.Error(),
+		})
+	case log == nil:
+		services.Monitor.Set(ServiceLogging, StateDisabled, nil)
+	default:
+		services.Monitor.Set(ServiceLogging, StateUp, nil)
+		services.Logger = log
+		logger.Info("GCP Logger inicializado com sucesso", map[string]interface{}{
+			"useCloudLogging": cfg.UseCloudLogging,
+		})
+	}
+
+	// Inicializar Secret Manager se GCP estiver configurado
+	if cfg.GCPProjectID != "" && cfg.UseSecretManager {
+		services.Secrets = monitoredSecrets{secrets: secrets.NewGCPSecretManager(cfg.GCPProjectID), monitor: services.Monitor}
+		services.Monitor.Set(ServiceSecretManager, StateUp, nil)
+		logger.Info("Secret Manager inicializado", map[string]interface{}{
+			"project_id": cfg.GCPProjectID,
+		})
+	} else {
+		services.Monitor.Set(ServiceSecretManager, StateDisabled, nil)
+		logger.Info("Secret Manager não configurado, usando valores locais", nil)
+	}
+
+	// Inicializar Cloud Storage se bucket estiver configurado
+	if cfg.GCPStorageBucket != "" {
+		services.Storage = monitoredStorage{storage: storage.NewCloudStorage(cfg.GCPStorageBucket), monitor: services.Monitor}
+		services.Monitor.Set(ServiceStorage, StateUp, nil)
+		logger.Info("Cloud Storage inicializado", map[string]interface{}{
+			"bucket": cfg.GCPStorageBucket,
+		})
+	} else {
+		services.Monitor.Set(ServiceStorage, StateDisabled, nil)
+		logger.Info("Cloud Storage não configurado", nil)
+	}
+
+	return services
+}
+
+// Close libera os clientes que mantêm recursos abertos (o logger do GCP)
+func (s Services) Close() error {
+	if s.Logger == nil {
+		return nil
+	}
+	return s.Logger.Close()
+}
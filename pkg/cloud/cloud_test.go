@@ -0,0 +1,26 @@
+package cloud
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"callable-api/pkg/config"
+)
+
+func TestNew(t *testing.T) {
+	// Sem projeto nem bucket, as integrações opcionais ficam desligadas
+	services := New(context.Background(), &config.Config{})
+	assert.Nil(t, services.Secrets)
+	assert.Nil(t, services.Storage)
+	assert.NoError(t, services.Close())
+
+	services = New(context.Background(), &config.Config{
+		GCPProjectID:     "projeto",
+		UseSecretManager: true,
+		GCPStorageBucket: "bucket",
+	})
+	assert.NotNil(t, services.Secrets)
+	assert.NotNil(t, services.Storage)
+}
@@ -0,0 +1,220 @@
+package cloud
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"callable-api/internal/models"
+	"callable-api/pkg/logger"
+)
+
+// Estados das integrações com o GCP
+const (
+	StateUp       = "up"
+	StateDegraded = "degraded" // configurada, mas falhando: a funcionalidade responde 503
+	StateDisabled = "disabled" // não configurada: a aplicação usa os valores locais
+)
+
+// Nomes das integrações acompanhadas pelo Monitor
+const (
+	ServiceLogging       = "cloud_logging"
+	ServiceSecretManager = "secret_manager"
+	ServiceStorage       = "cloud_storage"
+)
+
+// ErrUnavailable indica que a integração com o GCP falhou. As funcionalidades
+// que dependem dela respondem 503, enquanto o restante da API segue atendendo
+var ErrUnavailable = stderrors.New("serviço do GCP indisponível")
+
+// UnavailableError é a falha de uma chamada ao GCP (errors.Is(err, ErrUnavailable))
+type UnavailableError struct {
+	Service string
+	Err     error
+}
+
+// Error implementa error
+func (e *UnavailableError) Error() string {
+	return fmt.Sprintf("%s indisponível: %v", e.Service, e.Err)
+}
+
+// Unwrap retorna o erro original do cliente do GCP
+func (e *UnavailableError) Unwrap() error {
+	return e.Err
+}
+
+// Is faz o erro corresponder a ErrUnavailable
+func (e *UnavailableError) Is(target error) bool {
+	return target == ErrUnavailable
+}
+
+// Monitor acompanha o estado de cada integração a partir do resultado das
+// chamadas, para a verificação de prontidão. O valor nil é válido e não
+// acompanha nada
+type Monitor struct {
+	mu     sync.RWMutex
+	order  []string
+	states map[string]models.ServiceState
+}
+
+// NewMonitor cria um Monitor vazio
+func NewMonitor() *Monitor {
+	return &Monitor{states: make(map[string]models.ServiceState)}
+}
+
+// Set define o estado da integração; err descreve a falha dos estados
+// degradados
+func (m *Monitor) Set(service, state string, err error) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, exists := m.states[service]
+	if !exists {
+		m.order = append(m.order, service)
+	}
+	if exists && current.State == state && state != StateDegraded {
+		return
+	}
+
+	next := models.ServiceState{Name: service, State: state}
+	if err != nil {
+		next.Error = err.Error()
+	}
+	if state == StateDegraded {
+		next.Since = current.Since
+		if current.State != StateDegraded || next.Since == nil {
+			now := time.Now().UTC()
+			next.Since = &now
+		}
+	}
+	m.states[service] = next
+}
+
+// observe registra o resultado de uma chamada à integração, retornando o erro
+// como UnavailableError. Cancelamentos do próprio cliente não degradam a
+// integração
+func (m *Monitor) observe(ctx context.Context, service string, err error) error {
+	if err == nil {
+		m.Set(service, StateUp, nil)
+		return nil
+	}
+	if ctx.Err() != nil {
+		return err
+	}
+
+	if m.state(service) != StateDegraded {
+		logger.Warn("Integração com o GCP degradada", map[string]interface{}{
+			"service": service,
+			"error":   err.Error(),
+		})
+	}
+	m.Set(service, StateDegraded, err)
+	return &UnavailableError{Service: service, Err: err}
+}
+
+// state retorna o estado atual da integração
+func (m *Monitor) state(service string) string {
+	if m == nil {
+		return ""
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.states[service].State
+}
+
+// States retorna o estado das integrações, na ordem em que foram registradas
+func (m *Monitor) States() []models.ServiceState {
+	if m == nil {
+		return nil
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	states := make([]models.ServiceState, 0, len(m.order))
+	for _, service := range m.order {
+		states = append(states, m.states[service])
+	}
+	return states
+}
+
+// monitoredSecrets registra no Monitor o resultado das leituras de segredos
+type monitoredSecrets struct {
+	secrets Secrets
+	monitor *Monitor
+}
+
+func (s monitoredSecrets) GetSecret(ctx context.Context, secretName string) (string, error) {
+	value, err := s.secrets.GetSecret(ctx, secretName)
+	return value, s.monitor.observe(ctx, ServiceSecretManager, err)
+}
+
+func (s monitoredSecrets) GetSecretWithCache(ctx context.Context, secretName string, ttl time.Duration) (string, error) {
+	value, err := s.secrets.GetSecretWithCache(ctx, secretName, ttl)
+	return value, s.monitor.observe(ctx, ServiceSecretManager, err)
+}
+
+// monitoredStorage registra no Monitor o resultado das chamadas ao bucket
+type monitoredStorage struct {
+	storage Storage
+	monitor *Monitor
+}
+
+func (s monitoredStorage) UploadFile(ctx context.Context, name string, r io.Reader) error {
+	return s.monitor.observe(ctx, ServiceStorage, s.storage.UploadFile(ctx, name, r))
+}
+
+func (s monitoredStorage) GetSignedURL(ctx context.Context, name string, ttl time.Duration) (string, error) {
+	url, err := s.storage.GetSignedURL(ctx, name, ttl)
+	return url, s.monitor.observe(ctx, ServiceStorage, err)
+}
+
+// localLogger substitui o logger do GCP quando ele não pode ser criado,
+// escrevendo no logger padrão da aplicação
+type localLogger struct{}
+
+// LocalLogger retorna o Logger que escreve no logger padrão da aplicação,
+// usado no lugar do logger do GCP quando ele não está disponível
+func LocalLogger() Logger {
+	return localLogger{}
+}
+
+func (localLogger) Debug(msg string, data ...map[string]interface{}) { logger.Debug(msg, merge(data)) }
+func (localLogger) Info(msg string, data ...map[string]interface{})  { logger.Info(msg, merge(data)) }
+func (localLogger) Warn(msg string, data ...map[string]interface{})  { logger.Warn(msg, merge(data)) }
+
+func (localLogger) Error(msg string, err error, data ...map[string]interface{}) {
+	logger.Error(msg, withError(merge(data), err))
+}
+
+func (localLogger) Fatal(msg string, err error, data ...map[string]interface{}) {
+	logger.Error(msg, withError(merge(data), err))
+	os.Exit(1)
+}
+
+func (localLogger) Close() error { return nil }
+
+// merge junta os campos informados ao logger
+func merge(data []map[string]interface{}) map[string]interface{} {
+	fields := make(map[string]interface{})
+	for _, d := range data {
+		for key, value := range d {
+			fields[key] = value
+		}
+	}
+	return fields
+}
+
+// withError inclui o erro nos campos do log
+func withError(fields map[string]interface{}, err error) map[string]interface{} {
+	if err != nil {
+		fields["error"] = err.Error()
+	}
+	return fields
+}
@@ -0,0 +1,60 @@
+package cloud
+
+import (
+	"context"
+	stderrors "errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStorage falha enquanto err estiver definido
+type fakeStorage struct {
+	err error
+}
+
+func (f *fakeStorage) UploadFile(context.Context, string, io.Reader) error { return f.err }
+
+func (f *fakeStorage) GetSignedURL(context.Context, string, time.Duration) (string, error) {
+	return "https://storage/obj", f.err
+}
+
+func TestMonitoredStorage(t *testing.T) {
+	monitor := NewMonitor()
+	monitor.Set(ServiceStorage, StateUp, nil)
+	inner := &fakeStorage{err: stderrors.New("connection refused")}
+	storage := monitoredStorage{storage: inner, monitor: monitor}
+
+	// A falha é devolvida como ErrUnavailable e degrada a integração
+	err := storage.UploadFile(context.Background(), "a", strings.NewReader("x"))
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrUnavailable)
+	states := monitor.States()
+	require.Len(t, states, 1)
+	assert.Equal(t, StateDegraded, states[0].State)
+	assert.Equal(t, "connection refused", states[0].Error)
+	require.NotNil(t, states[0].Since)
+
+	// Cancelamentos do cliente não contam como falha da integração
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = storage.UploadFile(ctx, "a", strings.NewReader("x"))
+	assert.NotErrorIs(t, err, ErrUnavailable)
+
+	// A primeira chamada bem-sucedida recupera a integração
+	inner.err = nil
+	url, err := storage.GetSignedURL(context.Background(), "a", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, "https://storage/obj", url)
+	assert.Equal(t, StateUp, monitor.States()[0].State)
+	assert.Empty(t, monitor.States()[0].Error)
+
+	// Um Monitor nil não acompanha nada
+	var none *Monitor
+	none.Set(ServiceStorage, StateUp, nil)
+	assert.Nil(t, none.States())
+}
@@ -0,0 +1,225 @@
+package cloud
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"sync"
+	"time"
+
+	"callable-api/internal/clock"
+)
+
+// Valores padrão do cache de segredos
+const (
+	DefaultSecretCacheTTL        = 5 * time.Minute
+	DefaultSecretCacheMaxEntries = 100
+)
+
+// SecretCacheConfig define a retenção dos segredos em memória
+type SecretCacheConfig struct {
+	// TTL é o tempo que um segredo fica em cache quando o chamador não
+	// informa outro (GetSecret, ou GetSecretWithCache com ttl <= 0)
+	TTL time.Duration
+	// MaxEntries limita o número de segredos em cache; ao exceder, o usado
+	// há mais tempo é descartado
+	MaxEntries int
+	// Encrypt mantém os valores em cache cifrados (AES-GCM) com uma chave
+	// gerada na criação do cache, que nunca sai da memória do processo
+	Encrypt bool
+}
+
+// DefaultSecretCacheConfig retorna a configuração padrão (sem cifragem)
+func DefaultSecretCacheConfig() SecretCacheConfig {
+	return SecretCacheConfig{TTL: DefaultSecretCacheTTL, MaxEntries: DefaultSecretCacheMaxEntries}
+}
+
+// secretEntry é um segredo em cache; value é o texto puro ou, com cifragem,
+// o nonce seguido do texto cifrado
+type secretEntry struct {
+	value     []byte
+	expiresAt time.Time
+	lastUsed  time.Time
+}
+
+// SecretCache é um cache de segredos com expiração, limite de entradas e
+// cifragem opcional em memória, na frente de um Secrets. Os segredos são
+// sempre lidos do Secrets com GetSecret, de modo que o cache interno do
+// cliente (sem expiração nem limite) não é usado
+type SecretCache struct {
+	secrets Secrets
+	cfg     SecretCacheConfig
+	aead    cipher.AEAD
+	clock   clock.Clock
+
+	mu      sync.Mutex
+	entries map[string]*secretEntry
+}
+
+// Verificação em tempo de compilação
+var _ Secrets = (*SecretCache)(nil)
+
+// NewSecretCache cria o cache na frente de secrets. Retorna erro apenas se a
+// chave de cifragem não puder ser gerada
+func NewSecretCache(secrets Secrets, cfg SecretCacheConfig) (*SecretCache, error) {
+	if cfg.TTL <= 0 {
+		cfg.TTL = DefaultSecretCacheTTL
+	}
+	if cfg.MaxEntries <= 0 {
+		cfg.MaxEntries = DefaultSecretCacheMaxEntries
+	}
+
+	cache := &SecretCache{
+		secrets: secrets,
+		cfg:     cfg,
+		clock:   clock.System(),
+		entries: make(map[string]*secretEntry),
+	}
+	if cfg.Encrypt {
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("gerar chave do cache de segredos: %w", err)
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		if cache.aead, err = cipher.NewGCM(block); err != nil {
+			return nil, err
+		}
+	}
+	return cache, nil
+}
+
+// WithClock substitui o relógio usado na expiração (útil em testes)
+func (c *SecretCache) WithClock(clk clock.Clock) *SecretCache {
+	c.clock = clk
+	return c
+}
+
+// GetSecret retorna o segredo, do cache enquanto não expirar pelo TTL padrão
+func (c *SecretCache) GetSecret(ctx context.Context, secretName string) (string, error) {
+	return c.GetSecretWithCache(ctx, secretName, c.cfg.TTL)
+}
+
+// GetSecretWithCache retorna o segredo, mantendo-o em cache por ttl (ttl <= 0
+// usa o TTL padrão)
+func (c *SecretCache) GetSecretWithCache(ctx context.Context, secretName string, ttl time.Duration) (string, error) {
+	if ttl <= 0 {
+		ttl = c.cfg.TTL
+	}
+
+	if value, ok, err := c.lookup(secretName); err != nil || ok {
+		return value, err
+	}
+
+	value, err := c.secrets.GetSecret(ctx, secretName)
+	if err != nil {
+		return "", err
+	}
+	if err := c.store(secretName, value, ttl); err != nil {
+		return "", err
+	}
+	return value, nil
+}
+
+// Invalidate descarta o segredo do cache, para que a próxima leitura busque
+// a versão atual (ex.: ao receber o aviso de rotação)
+func (c *SecretCache) Invalidate(secretName string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, secretName)
+}
+
+// Len retorna o número de segredos em cache, incluindo os já expirados
+// ainda não descartados
+func (c *SecretCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// lookup retorna o segredo em cache, descartando-o se já expirou
+func (c *SecretCache) lookup(secretName string) (string, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[secretName]
+	if !ok {
+		return "", false, nil
+	}
+	now := c.clock.Now()
+	if !now.Before(entry.expiresAt) {
+		delete(c.entries, secretName)
+		return "", false, nil
+	}
+	entry.lastUsed = now
+
+	value, err := c.open(entry.value)
+	if err != nil {
+		delete(c.entries, secretName)
+		return "", false, err
+	}
+	return value, true, nil
+}
+
+// store grava o segredo no cache, descartando os expirados e, acima do
+// limite, os usados há mais tempo
+func (c *SecretCache) store(secretName, value string, ttl time.Duration) error {
+	sealed, err := c.seal(value)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.clock.Now()
+	c.entries[secretName] = &secretEntry{value: sealed, expiresAt: now.Add(ttl), lastUsed: now}
+
+	for name, entry := range c.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(c.entries, name)
+		}
+	}
+	for len(c.entries) > c.cfg.MaxEntries {
+		var oldest string
+		for name, entry := range c.entries {
+			if name != secretName && (oldest == "" || entry.lastUsed.Before(c.entries[oldest].lastUsed)) {
+				oldest = name
+			}
+		}
+		delete(c.entries, oldest)
+	}
+	return nil
+}
+
+// seal prepara o valor para o cache, cifrando-o se habilitado
+func (c *SecretCache) seal(value string) ([]byte, error) {
+	if c.aead == nil {
+		return []byte(value), nil
+	}
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("gerar nonce do cache de segredos: %w", err)
+	}
+	return c.aead.Seal(nonce, nonce, []byte(value), nil), nil
+}
+
+// open recupera o valor guardado por seal
+func (c *SecretCache) open(sealed []byte) (string, error) {
+	if c.aead == nil {
+		return string(sealed), nil
+	}
+	size := c.aead.NonceSize()
+	if len(sealed) < size {
+		return "", fmt.Errorf("segredo em cache corrompido")
+	}
+	plain, err := c.aead.Open(nil, sealed[:size], sealed[size:], nil)
+	if err != nil {
+		return "", fmt.Errorf("decifrar segredo em cache: %w", err)
+	}
+	return string(plain), nil
+}
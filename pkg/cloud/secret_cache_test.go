@@ -0,0 +1,78 @@
+package cloud
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"callable-api/internal/clock"
+)
+
+// fakeSecrets conta as leituras ao Secret Manager
+type fakeSecrets struct {
+	values map[string]string
+	reads  map[string]int
+}
+
+func (f *fakeSecrets) GetSecret(_ context.Context, name string) (string, error) {
+	f.reads[name]++
+	return f.values[name], nil
+}
+
+func (f *fakeSecrets) GetSecretWithCache(ctx context.Context, name string, _ time.Duration) (string, error) {
+	return f.GetSecret(ctx, name)
+}
+
+func TestSecretCache(t *testing.T) {
+	ctx := context.Background()
+	inner := &fakeSecrets{
+		values: map[string]string{"jwt": "segredo-1", "db": "senha", "smtp": "chave"},
+		reads:  map[string]int{},
+	}
+	clk := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	cache, err := NewSecretCache(inner, SecretCacheConfig{TTL: time.Minute, MaxEntries: 2, Encrypt: true})
+	require.NoError(t, err)
+	cache.WithClock(clk)
+
+	// Leituras dentro do TTL usam o cache
+	for i := 0; i < 3; i++ {
+		value, err := cache.GetSecret(ctx, "jwt")
+		require.NoError(t, err)
+		assert.Equal(t, "segredo-1", value)
+	}
+	assert.Equal(t, 1, inner.reads["jwt"])
+
+	// O valor em memória fica cifrado
+	assert.False(t, strings.Contains(string(cache.entries["jwt"].value), "segredo-1"))
+
+	// Após o TTL, o segredo é buscado de novo
+	clk.Advance(time.Minute)
+	_, err = cache.GetSecret(ctx, "jwt")
+	require.NoError(t, err)
+	assert.Equal(t, 2, inner.reads["jwt"])
+
+	// Invalidate força a leitura da versão atual
+	inner.values["jwt"] = "segredo-2"
+	cache.Invalidate("jwt")
+	value, err := cache.GetSecret(ctx, "jwt")
+	require.NoError(t, err)
+	assert.Equal(t, "segredo-2", value)
+	assert.Equal(t, 3, inner.reads["jwt"])
+
+	// Acima do limite, o usado há mais tempo é descartado
+	clk.Advance(time.Second)
+	_, err = cache.GetSecretWithCache(ctx, "db", time.Hour)
+	require.NoError(t, err)
+	clk.Advance(time.Second)
+	_, err = cache.GetSecret(ctx, "db")
+	require.NoError(t, err)
+	_, err = cache.GetSecret(ctx, "smtp")
+	require.NoError(t, err)
+	assert.Equal(t, 2, cache.Len())
+	assert.NotContains(t, cache.entries, "jwt")
+	assert.Equal(t, 1, inner.reads["db"])
+}
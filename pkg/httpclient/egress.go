@@ -0,0 +1,117 @@
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// ErrEgressDenied indica uma chamada para um destino fora da lista de permitidos
+var ErrEgressDenied = errors.New("destino não permitido pela política de saída")
+
+// Egress controla o destino das chamadas externas, para redes corporativas
+// que só liberam a saída por um proxy e para destinos conhecidos
+type Egress struct {
+	// ProxyURL é o proxy HTTP(S) usado pelas chamadas ("" usa as variáveis
+	// HTTP_PROXY, HTTPS_PROXY e NO_PROXY)
+	ProxyURL string
+
+	// AllowedHosts lista os destinos permitidos ("api.sendgrid.com" ou
+	// "*.example.com" para os subdomínios). Vazia, permite qualquer destino
+	AllowedHosts []string
+}
+
+// Validate verifica o proxy e os destinos configurados
+func (e Egress) Validate() error {
+	if _, err := e.proxy(); err != nil {
+		return err
+	}
+	for _, host := range e.AllowedHosts {
+		name := strings.TrimPrefix(host, "*.")
+		if name == "" || strings.ContainsAny(name, "/:*@ ") {
+			return fmt.Errorf("destino inválido na lista de permitidos: %q", host)
+		}
+	}
+	return nil
+}
+
+// Allowed indica se as chamadas para host são permitidas
+func (e Egress) Allowed(host string) bool {
+	if len(e.AllowedHosts) == 0 {
+		return true
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, allowed := range e.AllowedHosts {
+		allowed = strings.ToLower(allowed)
+		if suffix, ok := strings.CutPrefix(allowed, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+		} else if host == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// proxy retorna a função de proxy do transporte
+func (e Egress) proxy() (func(*http.Request) (*url.URL, error), error) {
+	if e.ProxyURL == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+	proxyURL, err := url.Parse(e.ProxyURL)
+	if err != nil || proxyURL.Host == "" {
+		return nil, fmt.Errorf("URL de proxy inválida: %q", e.ProxyURL)
+	}
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("esquema de proxy não suportado: %q", proxyURL.Scheme)
+	}
+	return http.ProxyURL(proxyURL), nil
+}
+
+var (
+	egressMu        sync.RWMutex
+	egress          Egress
+	egressTransport http.RoundTripper = http.DefaultTransport
+)
+
+// SetEgress define a política de saída dos clientes criados a partir de
+// então. Deve ser chamada na inicialização, antes de criar as integrações
+func SetEgress(e Egress) error {
+	if err := e.Validate(); err != nil {
+		return err
+	}
+	proxy, _ := e.proxy()
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxy
+
+	egressMu.Lock()
+	defer egressMu.Unlock()
+	egress = e
+	egressTransport = transport
+	return nil
+}
+
+// currentEgress retorna a política de saída e o transporte correspondente
+func currentEgress() (Egress, http.RoundTripper) {
+	egressMu.RLock()
+	defer egressMu.RUnlock()
+	return egress, egressTransport
+}
+
+// AllowedURL indica se a política de saída atual permite chamadas para a URL,
+// para validar destinos informados pelos usuários antes de aceitá-los
+func AllowedURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Hostname() == "" {
+		return false
+	}
+	e, _ := currentEgress()
+	return e.Allowed(u.Hostname())
+}
@@ -0,0 +1,161 @@
+// Package httpclient cria os clientes HTTP das integrações externas
+// (webhooks, Elasticsearch, Error Reporting, SendGrid).
+//
+// Todas as chamadas propagam o ID da requisição (X-Request-ID) e o trace W3C
+// (traceparent) do contexto, para que a chamada externa possa ser associada à
+// requisição que a originou, e repetem as falhas transitórias (erros de rede,
+// 429 e 5xx) conforme a política configurada. O proxy e os destinos
+// permitidos valem para todos os clientes (ver SetEgress).
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"callable-api/internal/correlation"
+	"callable-api/pkg/retry"
+)
+
+// RequestIDHeader é o header que leva o ID da requisição de origem
+const RequestIDHeader = "X-Request-ID"
+
+// Config define o comportamento de um cliente
+type Config struct {
+	Timeout time.Duration // prazo total de cada chamada, incluindo as novas tentativas
+	Retry   retry.Policy  // política para falhas transitórias
+
+	// RetryUnsafe repete também os métodos não idempotentes (POST, PATCH),
+	// para destinos que toleram entregas duplicadas
+	RetryUnsafe bool
+
+	// Transport é o transporte usado pelas chamadas (nil usa o transporte da
+	// política de saída, com o proxy configurado)
+	Transport http.RoundTripper
+}
+
+// DefaultConfig retorna a configuração padrão: prazo de 10s e a política
+// padrão de novas tentativas, apenas para métodos idempotentes
+func DefaultConfig() Config {
+	return Config{
+		Timeout: 10 * time.Second,
+		Retry:   retry.DefaultPolicy(),
+	}
+}
+
+// New cria um cliente HTTP instrumentado a partir da configuração
+func New(cfg Config) *http.Client {
+	egress, base := currentEgress()
+	if cfg.Transport != nil {
+		base = cfg.Transport
+	}
+
+	return &http.Client{
+		Timeout: cfg.Timeout,
+		Transport: &transport{
+			base:        base,
+			egress:      egress,
+			retry:       cfg.Retry,
+			retryUnsafe: cfg.RetryUnsafe,
+		},
+	}
+}
+
+// transport propaga o contexto de correlação e repete as falhas transitórias
+type transport struct {
+	base        http.RoundTripper
+	egress      Egress
+	retry       retry.Policy
+	retryUnsafe bool
+}
+
+// statusError representa uma resposta transitória que será repetida
+type statusError struct {
+	status int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("resposta transitória com status %d", e.status)
+}
+
+// RoundTrip implementa http.RoundTripper
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Os redirecionamentos também passam por aqui e são verificados
+	if !t.egress.Allowed(req.URL.Hostname()) {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, fmt.Errorf("%w: %s", ErrEgressDenied, req.URL.Hostname())
+	}
+
+	ctx := req.Context()
+	header := req.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	if id := correlation.RequestID(ctx); id != "" && header.Get(RequestIDHeader) == "" {
+		header.Set(RequestIDHeader, id)
+	}
+	if traceParent := correlation.ChildTraceParent(ctx); traceParent != "" && header.Get(correlation.TraceParentHeader) == "" {
+		header.Set(correlation.TraceParentHeader, traceParent)
+	}
+
+	if !t.canRetry(req) {
+		out := req.Clone(ctx)
+		out.Header = header
+		return t.base.RoundTrip(out)
+	}
+
+	attempts := max(t.retry.MaxAttempts, 1)
+	attempt := 0
+	return retry.DoValue(ctx, t.retry, func(ctx context.Context) (*http.Response, error) {
+		attempt++
+		out := req.Clone(ctx)
+		out.Header = header.Clone()
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, retry.Permanent(err)
+			}
+			out.Body = body
+		}
+
+		resp, err := t.base.RoundTrip(out)
+		if err != nil {
+			return nil, err
+		}
+		if attempt < attempts && transientStatus(resp.StatusCode) {
+			// Descarta a resposta para que a conexão seja reaproveitada
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			return nil, &statusError{status: resp.StatusCode}
+		}
+		return resp, nil
+	})
+}
+
+// canRetry indica se a requisição pode ser repetida: o método precisa ser
+// idempotente (ou RetryUnsafe estar ativo) e o corpo, se houver, reproduzível
+func (t *transport) canRetry(req *http.Request) bool {
+	if t.retry.MaxAttempts <= 1 {
+		return false
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	if t.retryUnsafe {
+		return true
+	}
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// transientStatus indica se o status pode mudar numa nova tentativa
+func transientStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
@@ -0,0 +1,110 @@
+package httpclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"callable-api/internal/correlation"
+	"callable-api/pkg/retry"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientPropagation(t *testing.T) {
+	var requestID, traceParent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID = r.Header.Get(RequestIDHeader)
+		traceParent = r.Header.Get(correlation.TraceParentHeader)
+	}))
+	defer server.Close()
+
+	parent := correlation.NewTraceParent()
+	ctx := correlation.WithTraceParent(correlation.WithRequestID(context.Background(), "req-1"), parent)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+
+	resp, err := New(DefaultConfig()).Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	// Mesmo trace, com um novo span para a chamada
+	assert.Equal(t, "req-1", requestID)
+	assert.True(t, correlation.ValidTraceParent(traceParent))
+	assert.Equal(t, parent[:35], traceParent[:35])
+	assert.NotEqual(t, parent, traceParent)
+}
+
+func TestClientRetry(t *testing.T) {
+	var calls atomic.Int32
+	var bodies []string
+	status := http.StatusServiceUnavailable
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		calls.Add(1)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	policy := retry.DefaultPolicy()
+	policy.InitialDelay = time.Millisecond
+	call := func(cfg Config, method string) *http.Response {
+		calls.Store(0)
+		bodies = nil
+		req, err := http.NewRequest(method, server.URL, strings.NewReader("payload"))
+		require.NoError(t, err)
+		resp, err := New(cfg).Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp
+	}
+
+	// Esgotadas as tentativas, a última resposta é retornada, com o corpo reenviado
+	resp := call(Config{Retry: policy}, http.MethodPut)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, int32(3), calls.Load())
+	assert.Equal(t, []string{"payload", "payload", "payload"}, bodies)
+
+	// POST só é repetido com RetryUnsafe
+	call(Config{Retry: policy}, http.MethodPost)
+	assert.Equal(t, int32(1), calls.Load())
+	call(Config{Retry: policy, RetryUnsafe: true}, http.MethodPost)
+	assert.Equal(t, int32(3), calls.Load())
+
+	// Erros do cliente não são repetidos
+	status = http.StatusBadRequest
+	resp = call(Config{Retry: policy}, http.MethodPut)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestEgress(t *testing.T) {
+	egress := Egress{AllowedHosts: []string{"api.sendgrid.com", "*.example.com"}}
+	require.NoError(t, egress.Validate())
+	assert.True(t, egress.Allowed("api.sendgrid.com"))
+	assert.True(t, egress.Allowed("hooks.example.com"))
+	assert.False(t, egress.Allowed("example.com"))
+	assert.False(t, egress.Allowed("evil.com"))
+	assert.True(t, Egress{}.Allowed("evil.com"))
+
+	assert.Error(t, Egress{ProxyURL: "ftp://proxy:21"}.Validate())
+	assert.Error(t, Egress{AllowedHosts: []string{"https://example.com"}}.Validate())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	// Destinos fora da lista são recusados antes da conexão
+	require.NoError(t, SetEgress(Egress{AllowedHosts: []string{"api.sendgrid.com"}}))
+	t.Cleanup(func() { _ = SetEgress(Egress{}) })
+	_, err := New(DefaultConfig()).Get(server.URL)
+	assert.ErrorIs(t, err, ErrEgressDenied)
+	assert.False(t, AllowedURL(server.URL))
+	assert.True(t, AllowedURL("https://api.sendgrid.com/v3/mail/send"))
+}
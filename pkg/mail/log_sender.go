@@ -0,0 +1,40 @@
+package mail
+
+import (
+	"context"
+
+	"callable-api/pkg/logger"
+)
+
+// LogSender é o provedor de desenvolvimento: não envia nada, apenas registra
+// a mensagem no log estruturado
+type LogSender struct {
+	from string
+}
+
+// NewLogSender cria um novo LogSender
+func NewLogSender(from string) *LogSender {
+	return &LogSender{from: from}
+}
+
+// Send implementa Sender
+func (s *LogSender) Send(ctx context.Context, msg *Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if msg.From == "" {
+		msg.From = s.from
+	}
+	if err := msg.Validate(); err != nil {
+		return err
+	}
+
+	logger.Info("Email (modo desenvolvimento, não enviado)", map[string]interface{}{
+		"from":      msg.From,
+		"to":        msg.To,
+		"subject":   msg.Subject,
+		"text_body": msg.TextBody,
+		"has_html":  msg.HTMLBody != "",
+	})
+	return nil
+}
@@ -0,0 +1,93 @@
+// Package mail fornece o envio de emails da aplicação com múltiplos provedores
+// (SMTP, SendGrid e um provedor de desenvolvimento que apenas registra em log)
+package mail
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Provedores de envio suportados
+const (
+	ProviderLog      = "log"
+	ProviderSMTP     = "smtp"
+	ProviderSendGrid = "sendgrid"
+)
+
+// Message representa um email pronto para envio
+type Message struct {
+	From     string
+	To       []string
+	Subject  string
+	TextBody string
+	HTMLBody string
+}
+
+// Validate verifica se a mensagem possui os campos mínimos para envio
+func (m *Message) Validate() error {
+	if len(m.To) == 0 {
+		return fmt.Errorf("mail: nenhum destinatário informado")
+	}
+	for _, to := range m.To {
+		if !strings.Contains(to, "@") {
+			return fmt.Errorf("mail: destinatário inválido: %q", to)
+		}
+	}
+	if m.Subject == "" {
+		return fmt.Errorf("mail: assunto é obrigatório")
+	}
+	if m.TextBody == "" && m.HTMLBody == "" {
+		return fmt.Errorf("mail: corpo da mensagem é obrigatório")
+	}
+	return nil
+}
+
+// Sender define a interface comum a todos os provedores de email
+type Sender interface {
+	// Send envia a mensagem, respeitando o prazo do contexto
+	Send(ctx context.Context, msg *Message) error
+}
+
+// Config agrupa as configurações do subsistema de email
+type Config struct {
+	Provider       string // log, smtp ou sendgrid
+	From           string // remetente padrão
+	SMTPHost       string
+	SMTPPort       int
+	SMTPUsername   string
+	SMTPPassword   string
+	SendGridAPIKey string
+	Async          bool   // envia pela fila de jobs (ver Mailer.WithQueue)
+	TemplatesDir   string // diretório com templates próprios; vazio usa os embutidos
+}
+
+// DefaultConfig retorna a configuração padrão (provedor de log, envio assíncrono)
+func DefaultConfig() Config {
+	return Config{
+		Provider: ProviderLog,
+		From:     "no-reply@callable-api.local",
+		SMTPPort: 587,
+		Async:    true,
+	}
+}
+
+// NewSender cria o Sender correspondente ao provedor configurado
+func NewSender(cfg Config) (Sender, error) {
+	switch cfg.Provider {
+	case "", ProviderLog:
+		return NewLogSender(cfg.From), nil
+	case ProviderSMTP:
+		if cfg.SMTPHost == "" {
+			return nil, fmt.Errorf("mail: SMTP host não configurado")
+		}
+		return NewSMTPSender(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.From), nil
+	case ProviderSendGrid:
+		if cfg.SendGridAPIKey == "" {
+			return nil, fmt.Errorf("mail: chave de API do SendGrid não configurada")
+		}
+		return NewSendGridSender(cfg.SendGridAPIKey, cfg.From), nil
+	default:
+		return nil, fmt.Errorf("mail: provedor desconhecido: %q", cfg.Provider)
+	}
+}
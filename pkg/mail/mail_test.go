@@ -0,0 +1,246 @@
+package mail
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingSender registra as mensagens recebidas para verificação nos testes
+type recordingSender struct {
+	mu       sync.Mutex
+	messages []*Message
+}
+
+func (r *recordingSender) Send(ctx context.Context, msg *Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages = append(r.messages, msg)
+	return nil
+}
+
+func (r *recordingSender) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.messages)
+}
+
+func TestNewSender(t *testing.T) {
+	t.Run("Provedor padrão é o de log", func(t *testing.T) {
+		sender, err := NewSender(DefaultConfig())
+		assert.NoError(t, err)
+		assert.IsType(t, &LogSender{}, sender)
+	})
+
+	t.Run("SMTP exige host", func(t *testing.T) {
+		_, err := NewSender(Config{Provider: ProviderSMTP})
+		assert.Error(t, err)
+	})
+
+	t.Run("SendGrid exige chave de API", func(t *testing.T) {
+		_, err := NewSender(Config{Provider: ProviderSendGrid})
+		assert.Error(t, err)
+	})
+
+	t.Run("Provedor desconhecido retorna erro", func(t *testing.T) {
+		_, err := NewSender(Config{Provider: "pombo-correio"})
+		assert.Error(t, err)
+	})
+}
+
+func TestMessageValidate(t *testing.T) {
+	msg := &Message{To: []string{"user@example.com"}, Subject: "Olá", TextBody: "corpo"}
+	assert.NoError(t, msg.Validate())
+
+	assert.Error(t, (&Message{Subject: "Olá", TextBody: "corpo"}).Validate())
+	assert.Error(t, (&Message{To: []string{"invalido"}, Subject: "Olá", TextBody: "corpo"}).Validate())
+	assert.Error(t, (&Message{To: []string{"user@example.com"}, TextBody: "corpo"}).Validate())
+	assert.Error(t, (&Message{To: []string{"user@example.com"}, Subject: "Olá"}).Validate())
+}
+
+func TestTemplatesRender(t *testing.T) {
+	templates, err := DefaultTemplates()
+	assert.NoError(t, err)
+	assert.Contains(t, templates.Names(), "password_reset")
+
+	msg, err := templates.Render("password_reset", map[string]interface{}{
+		"Name":      "Maria <script>",
+		"ResetURL":  "https://example.com/reset?token=abc",
+		"ExpiresIn": "1 hora",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "Redefinição de senha", msg.Subject)
+	assert.Contains(t, msg.TextBody, "https://example.com/reset?token=abc")
+	// O template HTML deve escapar o conteúdo
+	assert.Contains(t, msg.HTMLBody, "Maria &lt;script&gt;")
+
+	_, err = templates.Render("inexistente", nil)
+	assert.Error(t, err)
+}
+
+func TestTemplatesVersionsAndPreview(t *testing.T) {
+	fsys := fstest.MapFS{
+		"tpl/welcome.txt":         {Data: []byte(`{{define "subject"}}Oi{{end}}Olá, {{.Name}}!`)},
+		"tpl/welcome.v2.txt":      {Data: []byte(`{{define "subject"}}Bem-vindo{{end}}Bem-vindo, {{.Name}}!`)},
+		"tpl/welcome.v2.html":     {Data: []byte(`<p>Bem-vindo, {{.Name}}!</p>`)},
+		"tpl/welcome.sample.json": {Data: []byte(`{"Name": "Maria"}`)},
+	}
+	templates, err := LoadTemplates(fsys, "tpl")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"welcome"}, templates.Names())
+	assert.Equal(t, []int{1, 2}, templates.Versions("welcome"))
+
+	// Os envios usam a versão mais recente
+	msg, err := templates.Render("welcome", map[string]string{"Name": "Ana"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Bem-vindo", msg.Subject)
+
+	msg, err = templates.RenderVersion("welcome", 1, map[string]string{"Name": "Ana"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Olá, Ana!", msg.TextBody)
+	assert.Empty(t, msg.HTMLBody)
+
+	// A pré-visualização usa os dados de exemplo
+	msg, version, err := templates.Preview("welcome", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, version)
+	assert.Equal(t, "Bem-vindo, Maria!", msg.TextBody)
+	assert.Equal(t, "<p>Bem-vindo, Maria!</p>", msg.HTMLBody)
+
+	_, _, err = templates.Preview("welcome", 3)
+	assert.ErrorIs(t, err, ErrTemplateNotFound)
+
+	// Os templates embutidos possuem dados de exemplo completos
+	templates, err = DefaultTemplates()
+	assert.NoError(t, err)
+	for _, name := range templates.Names() {
+		msg, _, err := templates.Preview(name, 0)
+		assert.NoError(t, err, name)
+		assert.NotContains(t, msg.TextBody+msg.HTMLBody, "<no value>", name)
+	}
+}
+
+func TestSMTPSenderBuildsMultipartMessage(t *testing.T) {
+	sender := NewSMTPSender("smtp.example.com", 587, "user", "pass", "from@example.com")
+
+	var sent []byte
+	sender.sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		assert.Equal(t, "smtp.example.com:587", addr)
+		assert.Equal(t, "from@example.com", from)
+		sent = msg
+		return nil
+	}
+
+	err := sender.Send(context.Background(), &Message{
+		To:       []string{"user@example.com"},
+		Subject:  "Assunto",
+		TextBody: "texto",
+		HTMLBody: "<p>html</p>",
+	})
+	assert.NoError(t, err)
+	assert.Contains(t, string(sent), "multipart/alternative")
+	assert.Contains(t, string(sent), "texto")
+	assert.Contains(t, string(sent), "<p>html</p>")
+}
+
+func TestSendGridSender(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer sg-key", r.Header.Get("Authorization"))
+
+		body, _ := io.ReadAll(r.Body)
+		var payload sendGridPayload
+		assert.NoError(t, json.Unmarshal(body, &payload))
+		assert.Equal(t, "user@example.com", payload.Personalizations[0].To[0].Email)
+		assert.Equal(t, "text/plain", payload.Content[0].Type)
+
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	sender := NewSendGridSender("sg-key", "from@example.com")
+	sender.endpoint = server.URL
+
+	err := sender.Send(context.Background(), &Message{
+		To:       []string{"user@example.com"},
+		Subject:  "Assunto",
+		TextBody: "texto",
+	})
+	assert.NoError(t, err)
+
+	// Respostas de erro do provedor devem ser propagadas
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad key", http.StatusUnauthorized)
+	}))
+	defer failing.Close()
+
+	sender.endpoint = failing.URL
+	err = sender.Send(context.Background(), &Message{
+		To:       []string{"user@example.com"},
+		Subject:  "Assunto",
+		TextBody: "texto",
+	})
+	assert.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "401"))
+}
+
+func TestMailerQueue(t *testing.T) {
+	templates, err := DefaultTemplates()
+	assert.NoError(t, err)
+
+	recorder := &recordingSender{}
+	var queued []*Message
+	mailer := NewMailer(recorder, templates).WithQueue(func(ctx context.Context, msg *Message) error {
+		queued = append(queued, msg)
+		return nil
+	})
+
+	// Com a fila, o envio só enfileira; Deliver envia pelo provedor
+	msg := &Message{To: []string{"user@example.com"}, Subject: "x", TextBody: "y"}
+	assert.NoError(t, mailer.Send(context.Background(), msg))
+	assert.Len(t, queued, 1)
+	assert.Equal(t, 0, recorder.count())
+	assert.NoError(t, mailer.Deliver(context.Background(), queued[0]))
+	assert.Equal(t, 1, recorder.count())
+
+	// Mensagens inválidas são recusadas antes de entrar na fila
+	assert.Error(t, mailer.Send(context.Background(), &Message{Subject: "x", TextBody: "y"}))
+	assert.Len(t, queued, 1)
+}
+
+func TestMailerDeliverKeepsMessage(t *testing.T) {
+	templates, err := DefaultTemplates()
+	assert.NoError(t, err)
+
+	// O remetente padrão do provedor não altera a mensagem do chamador
+	mailer := NewMailer(NewLogSender("noreply@example.com"), templates)
+	msg := &Message{To: []string{"user@example.com"}, Subject: "x", TextBody: "y"}
+	assert.NoError(t, mailer.Send(context.Background(), msg))
+	assert.Empty(t, msg.From)
+}
+
+func TestMailerSendTemplate(t *testing.T) {
+	templates, err := DefaultTemplates()
+	assert.NoError(t, err)
+
+	recorder := &recordingSender{}
+	mailer := NewMailer(recorder, templates)
+
+	err = mailer.SendTemplate(context.Background(), "notification", []string{"user@example.com"}, map[string]string{
+		"Name":  "Maria",
+		"Title": "Job concluído",
+		"Body":  "O seu job terminou.",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, recorder.count())
+	assert.Equal(t, "Job concluído", recorder.messages[0].Subject)
+	assert.Equal(t, []string{"user@example.com"}, recorder.messages[0].To)
+}
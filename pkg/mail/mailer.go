@@ -0,0 +1,94 @@
+package mail
+
+import (
+	"context"
+	"os"
+)
+
+// Mailer combina um Sender com os templates da aplicação e é o ponto de
+// entrada usado pelos serviços (redefinição de senha, verificação de email,
+// notificações)
+type Mailer struct {
+	sender    Sender
+	templates *Templates
+	queue     Queue
+}
+
+// JobType é o tipo de job que envia as mensagens enfileiradas (ver WithQueue)
+const JobType = "mail.send"
+
+// Queue enfileira o envio de uma mensagem em segundo plano. Na aplicação, é
+// um job JobType do gerenciador de jobs, que chama Deliver
+type Queue func(ctx context.Context, msg *Message) error
+
+// New cria um Mailer a partir da configuração, usando os templates de
+// TemplatesDir (ou os embutidos). O envio é síncrono até WithQueue
+func New(cfg Config) (*Mailer, error) {
+	sender, err := NewSender(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	templates, err := loadConfiguredTemplates(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Mailer{sender: sender, templates: templates}, nil
+}
+
+// loadConfiguredTemplates carrega os templates de TemplatesDir, se definido,
+// ou os embutidos no binário
+func loadConfiguredTemplates(cfg Config) (*Templates, error) {
+	if cfg.TemplatesDir == "" {
+		return DefaultTemplates()
+	}
+	return LoadTemplates(os.DirFS(cfg.TemplatesDir), ".")
+}
+
+// NewMailer cria um Mailer com um Sender e templates já construídos
+func NewMailer(sender Sender, templates *Templates) *Mailer {
+	return &Mailer{sender: sender, templates: templates}
+}
+
+// Templates retorna os templates carregados
+func (m *Mailer) Templates() *Templates {
+	return m.templates
+}
+
+// WithQueue enfileira as mensagens em vez de enviá-las durante a requisição,
+// para que ela não espere pelo provedor de email
+func (m *Mailer) WithQueue(queue Queue) *Mailer {
+	m.queue = queue
+	return m
+}
+
+// Send envia uma mensagem já montada, pela fila se configurada
+func (m *Mailer) Send(ctx context.Context, msg *Message) error {
+	if m.queue != nil {
+		if err := msg.Validate(); err != nil {
+			return err
+		}
+		return m.queue(ctx, msg)
+	}
+	return m.Deliver(ctx, msg)
+}
+
+// Deliver envia a mensagem pelo provedor, sem passar pela fila. É chamado
+// pelos jobs de envio
+func (m *Mailer) Deliver(ctx context.Context, msg *Message) error {
+	// O provedor recebe uma cópia, pois preenche o remetente padrão: a
+	// mensagem do chamador pode estar em uso por outro envio
+	copied := *msg
+	return m.sender.Send(ctx, &copied)
+}
+
+// SendTemplate renderiza o template indicado e o envia para os destinatários
+func (m *Mailer) SendTemplate(ctx context.Context, name string, to []string, data interface{}) error {
+	msg, err := m.templates.Render(name, data)
+	if err != nil {
+		return err
+	}
+	msg.To = to
+	return m.Send(ctx, msg)
+}
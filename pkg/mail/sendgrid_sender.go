@@ -0,0 +1,115 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"callable-api/pkg/httpclient"
+)
+
+// sendGridEndpoint é o endpoint da API v3 do SendGrid
+const sendGridEndpoint = "https://api.sendgrid.com/v3/mail/send"
+
+// SendGridSender envia emails através da API HTTP do SendGrid
+type SendGridSender struct {
+	apiKey   string
+	from     string
+	endpoint string
+	client   *http.Client
+}
+
+// NewSendGridSender cria um novo SendGridSender
+func NewSendGridSender(apiKey, from string) *SendGridSender {
+	return &SendGridSender{
+		apiKey:   apiKey,
+		from:     from,
+		endpoint: sendGridEndpoint,
+		client:   httpclient.New(httpclient.DefaultConfig()),
+	}
+}
+
+// sendGridAddress representa um endereço no payload do SendGrid
+type sendGridAddress struct {
+	Email string `json:"email"`
+}
+
+// sendGridPayload representa o corpo da requisição para a API do SendGrid
+type sendGridPayload struct {
+	Personalizations []struct {
+		To []sendGridAddress `json:"to"`
+	} `json:"personalizations"`
+	From    sendGridAddress `json:"from"`
+	Subject string          `json:"subject"`
+	Content []struct {
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	} `json:"content"`
+}
+
+// Send implementa Sender
+func (s *SendGridSender) Send(ctx context.Context, msg *Message) error {
+	if msg.From == "" {
+		msg.From = s.from
+	}
+	if err := msg.Validate(); err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(s.buildPayload(msg))
+	if err != nil {
+		return fmt.Errorf("mail: falha ao serializar payload do SendGrid: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("mail: falha ao criar requisição para o SendGrid: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("mail: falha no envio via SendGrid: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("mail: SendGrid respondeu com status %d: %s", resp.StatusCode, detail)
+	}
+	return nil
+}
+
+// buildPayload converte a mensagem no formato esperado pelo SendGrid
+func (s *SendGridSender) buildPayload(msg *Message) sendGridPayload {
+	var payload sendGridPayload
+
+	recipients := make([]sendGridAddress, 0, len(msg.To))
+	for _, to := range msg.To {
+		recipients = append(recipients, sendGridAddress{Email: to})
+	}
+	payload.Personalizations = append(payload.Personalizations, struct {
+		To []sendGridAddress `json:"to"`
+	}{To: recipients})
+
+	payload.From = sendGridAddress{Email: msg.From}
+	payload.Subject = msg.Subject
+
+	// O SendGrid exige que text/plain venha antes de text/html
+	type content = struct {
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	}
+	if msg.TextBody != "" {
+		payload.Content = append(payload.Content, content{Type: "text/plain", Value: msg.TextBody})
+	}
+	if msg.HTMLBody != "" {
+		payload.Content = append(payload.Content, content{Type: "text/html", Value: msg.HTMLBody})
+	}
+
+	return payload
+}
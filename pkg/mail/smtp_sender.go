@@ -0,0 +1,99 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMTPSender envia emails através de um servidor SMTP
+type SMTPSender struct {
+	addr     string
+	host     string
+	username string
+	password string
+	from     string
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewSMTPSender cria um novo SMTPSender
+func NewSMTPSender(host string, port int, username, password, from string) *SMTPSender {
+	return &SMTPSender{
+		addr:     net.JoinHostPort(host, strconv.Itoa(port)),
+		host:     host,
+		username: username,
+		password: password,
+		from:     from,
+		sendMail: smtp.SendMail,
+	}
+}
+
+// Send implementa Sender
+func (s *SMTPSender) Send(ctx context.Context, msg *Message) error {
+	if msg.From == "" {
+		msg.From = s.from
+	}
+	if err := msg.Validate(); err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if s.username != "" {
+		auth = smtp.PlainAuth("", s.username, s.password, s.host)
+	}
+
+	// net/smtp não aceita contexto; executamos em goroutine para respeitar o prazo
+	done := make(chan error, 1)
+	go func() {
+		done <- s.sendMail(s.addr, auth, msg.From, msg.To, buildMIMEMessage(msg))
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("mail: falha no envio SMTP: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// buildMIMEMessage monta a mensagem no formato MIME, usando multipart/alternative
+// quando existem versões texto e HTML
+func buildMIMEMessage(msg *Message) []byte {
+	var buf bytes.Buffer
+
+	buf.WriteString("From: " + msg.From + "\r\n")
+	buf.WriteString("To: " + strings.Join(msg.To, ", ") + "\r\n")
+	buf.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", msg.Subject) + "\r\n")
+	buf.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	buf.WriteString("MIME-Version: 1.0\r\n")
+
+	switch {
+	case msg.TextBody != "" && msg.HTMLBody != "":
+		boundary := fmt.Sprintf("callable-api-%d", time.Now().UnixNano())
+		buf.WriteString("Content-Type: multipart/alternative; boundary=" + boundary + "\r\n\r\n")
+		buf.WriteString("--" + boundary + "\r\n")
+		buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+		buf.WriteString(msg.TextBody + "\r\n")
+		buf.WriteString("--" + boundary + "\r\n")
+		buf.WriteString("Content-Type: text/html; charset=utf-8\r\n\r\n")
+		buf.WriteString(msg.HTMLBody + "\r\n")
+		buf.WriteString("--" + boundary + "--\r\n")
+	case msg.HTMLBody != "":
+		buf.WriteString("Content-Type: text/html; charset=utf-8\r\n\r\n")
+		buf.WriteString(msg.HTMLBody + "\r\n")
+	default:
+		buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+		buf.WriteString(msg.TextBody + "\r\n")
+	}
+
+	return buf.Bytes()
+}
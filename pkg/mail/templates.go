@@ -0,0 +1,206 @@
+package mail
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	texttemplate "text/template"
+)
+
+//go:embed templates/*.txt templates/*.html templates/*.json
+var embeddedTemplates embed.FS
+
+// ErrTemplateNotFound indica que o template (ou a versão) não existe
+var ErrTemplateNotFound = errors.New("mail: template não encontrado")
+
+// sampleSuffix identifica os arquivos com os dados de exemplo de um template
+// (<nome>.sample.json), usados nas pré-visualizações
+const sampleSuffix = ".sample"
+
+// Templates reúne os templates de email. Cada template possui uma versão texto
+// (<nome>.txt, que também define o bloco "subject") e opcionalmente uma versão
+// HTML (<nome>.html). Novas versões convivem com as anteriores com o sufixo
+// .v<N> (<nome>.v2.txt, <nome>.v2.html); arquivos sem sufixo são a versão 1 e
+// os envios usam sempre a versão mais recente
+type Templates struct {
+	text    map[templateKey]*texttemplate.Template
+	html    map[templateKey]*htmltemplate.Template
+	latest  map[string]int
+	samples map[string]map[string]interface{}
+}
+
+// templateKey identifica uma versão de um template
+type templateKey struct {
+	name    string
+	version int
+}
+
+// DefaultTemplates carrega os templates embutidos no binário
+func DefaultTemplates() (*Templates, error) {
+	return LoadTemplates(embeddedTemplates, "templates")
+}
+
+// LoadTemplates carrega os templates de um sistema de arquivos
+func LoadTemplates(fsys fs.FS, dir string) (*Templates, error) {
+	t := &Templates{
+		text:    make(map[templateKey]*texttemplate.Template),
+		html:    make(map[templateKey]*htmltemplate.Template),
+		latest:  make(map[string]int),
+		samples: make(map[string]map[string]interface{}),
+	}
+
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("mail: falha ao listar templates: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		file := path.Join(dir, entry.Name())
+		ext := path.Ext(entry.Name())
+		base := strings.TrimSuffix(entry.Name(), ext)
+		key := parseTemplateKey(base)
+
+		content, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, fmt.Errorf("mail: falha ao ler template %s: %w", file, err)
+		}
+
+		switch ext {
+		case ".txt":
+			tmpl, err := texttemplate.New(key.name).Parse(string(content))
+			if err != nil {
+				return nil, fmt.Errorf("mail: template %s inválido: %w", file, err)
+			}
+			if tmpl.Lookup("subject") == nil {
+				return nil, fmt.Errorf("mail: template %s não define o bloco \"subject\"", file)
+			}
+			t.text[key] = tmpl
+			if key.version > t.latest[key.name] {
+				t.latest[key.name] = key.version
+			}
+		case ".html":
+			tmpl, err := htmltemplate.New(key.name).Parse(string(content))
+			if err != nil {
+				return nil, fmt.Errorf("mail: template %s inválido: %w", file, err)
+			}
+			t.html[key] = tmpl
+		case ".json":
+			if !strings.HasSuffix(base, sampleSuffix) {
+				continue
+			}
+			var sample map[string]interface{}
+			if err := json.Unmarshal(content, &sample); err != nil {
+				return nil, fmt.Errorf("mail: dados de exemplo %s inválidos: %w", file, err)
+			}
+			t.samples[strings.TrimSuffix(base, sampleSuffix)] = sample
+		}
+	}
+
+	return t, nil
+}
+
+// parseTemplateKey separa o nome e a versão (sufixo .v<N>) do nome do arquivo
+func parseTemplateKey(base string) templateKey {
+	if i := strings.LastIndex(base, ".v"); i > 0 {
+		if version, err := strconv.Atoi(base[i+2:]); err == nil && version > 0 {
+			return templateKey{name: base[:i], version: version}
+		}
+	}
+	return templateKey{name: base, version: 1}
+}
+
+// Names retorna os nomes dos templates disponíveis em ordem alfabética
+func (t *Templates) Names() []string {
+	names := make([]string, 0, len(t.latest))
+	for name := range t.latest {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Versions retorna as versões disponíveis do template em ordem crescente
+func (t *Templates) Versions(name string) []int {
+	var versions []int
+	for key := range t.text {
+		if key.name == name {
+			versions = append(versions, key.version)
+		}
+	}
+	sort.Ints(versions)
+	return versions
+}
+
+// Render executa a versão mais recente do template indicado e retorna a
+// mensagem resultante (sem destinatários, que devem ser preenchidos pelo
+// chamador)
+func (t *Templates) Render(name string, data interface{}) (*Message, error) {
+	return t.RenderVersion(name, 0, data)
+}
+
+// RenderVersion executa uma versão específica do template (0 usa a mais
+// recente)
+func (t *Templates) RenderVersion(name string, version int, data interface{}) (*Message, error) {
+	if version == 0 {
+		version = t.latest[name]
+	}
+	key := templateKey{name: name, version: version}
+
+	textTmpl, ok := t.text[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q (versão %d)", ErrTemplateNotFound, name, version)
+	}
+
+	var subject, textBody bytes.Buffer
+	if err := textTmpl.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return nil, fmt.Errorf("mail: falha ao renderizar assunto de %q: %w", name, err)
+	}
+	if err := textTmpl.Execute(&textBody, data); err != nil {
+		return nil, fmt.Errorf("mail: falha ao renderizar %q: %w", name, err)
+	}
+
+	msg := &Message{
+		Subject:  strings.TrimSpace(subject.String()),
+		TextBody: strings.TrimSpace(textBody.String()),
+	}
+
+	if htmlTmpl, ok := t.html[key]; ok {
+		var htmlBody bytes.Buffer
+		if err := htmlTmpl.Execute(&htmlBody, data); err != nil {
+			return nil, fmt.Errorf("mail: falha ao renderizar HTML de %q: %w", name, err)
+		}
+		msg.HTMLBody = htmlBody.String()
+	}
+
+	return msg, nil
+}
+
+// Preview renderiza uma versão do template (0 usa a mais recente) com os seus
+// dados de exemplo, para conferência antes do envio. Retorna também a versão
+// renderizada
+func (t *Templates) Preview(name string, version int) (*Message, int, error) {
+	if version == 0 {
+		version = t.latest[name]
+	}
+	sample := t.samples[name]
+	if sample == nil {
+		sample = map[string]interface{}{}
+	}
+
+	msg, err := t.RenderVersion(name, version, sample)
+	if err != nil {
+		return nil, 0, err
+	}
+	return msg, version, nil
+}
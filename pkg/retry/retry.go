@@ -0,0 +1,144 @@
+// Package retry repete chamadas a serviços externos que falham de forma
+// transitória, com espera exponencial e jitter entre as tentativas.
+//
+// As tentativas respeitam o contexto: o cancelamento interrompe a espera e os
+// erros do próprio contexto nunca são repetidos.
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// Policy define quantas vezes e com que espera uma chamada é repetida
+type Policy struct {
+	MaxAttempts  int           // total de tentativas, incluindo a primeira
+	InitialDelay time.Duration // espera antes da segunda tentativa
+	MaxDelay     time.Duration // limite da espera entre tentativas
+	Multiplier   float64       // fator de crescimento da espera
+	Jitter       float64       // fração aleatória da espera (0 a 1), para espalhar as tentativas
+
+	// Retryable indica se o erro é transitório (nil considera todos, exceto
+	// os marcados com Permanent)
+	Retryable func(err error) bool
+}
+
+// DefaultPolicy retorna a política padrão: 3 tentativas, começando com 200ms
+func DefaultPolicy() Policy {
+	return Policy{
+		MaxAttempts:  3,
+		InitialDelay: 200 * time.Millisecond,
+		MaxDelay:     5 * time.Second,
+		Multiplier:   2,
+		Jitter:       0.5,
+	}
+}
+
+// WithRetryable retorna uma cópia da política com outro critério de erros transitórios
+func (p Policy) WithRetryable(retryable func(err error) bool) Policy {
+	p.Retryable = retryable
+	return p
+}
+
+// permanentError marca um erro que não deve ser repetido
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marca o erro como definitivo: Do o retorna (sem a marca) sem novas tentativas
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// sleep espera d ou até o contexto terminar (substituída nos testes)
+var sleep = func(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Do executa fn até que ela tenha sucesso, retorne um erro definitivo ou as
+// tentativas se esgotem, retornando o último erro. Se o contexto terminar
+// durante a espera, o erro combina o erro do contexto e a última falha
+func Do(ctx context.Context, policy Policy, fn func(ctx context.Context) error) error {
+	_, err := DoValue(ctx, policy, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	})
+	return err
+}
+
+// DoValue é como Do, para funções que retornam um valor
+func DoValue[T any](ctx context.Context, policy Policy, fn func(ctx context.Context) (T, error)) (T, error) {
+	attempts := policy.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	delay := policy.InitialDelay
+	for attempt := 1; ; attempt++ {
+		value, err := fn(ctx)
+		if err == nil {
+			return value, nil
+		}
+
+		var permanent *permanentError
+		if errors.As(err, &permanent) {
+			return value, permanent.err
+		}
+		if attempt >= attempts || !policy.retryable(ctx, err) {
+			return value, err
+		}
+
+		if sleepErr := sleep(ctx, policy.jittered(delay)); sleepErr != nil {
+			return value, fmt.Errorf("%w (última falha: %w)", sleepErr, err)
+		}
+		delay = policy.next(delay)
+	}
+}
+
+// retryable indica se o erro deve ser repetido
+func (p Policy) retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	return p.Retryable == nil || p.Retryable(err)
+}
+
+// next calcula a espera da próxima tentativa
+func (p Policy) next(delay time.Duration) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	next := time.Duration(float64(delay) * multiplier)
+	if p.MaxDelay > 0 && next > p.MaxDelay {
+		next = p.MaxDelay
+	}
+	return next
+}
+
+// jittered sorteia a espera entre delay*(1-Jitter) e delay
+func (p Policy) jittered(delay time.Duration) time.Duration {
+	if p.Jitter <= 0 || delay <= 0 {
+		return delay
+	}
+	jitter := p.Jitter
+	if jitter > 1 {
+		jitter = 1
+	}
+	return delay - time.Duration(rand.Float64()*jitter*float64(delay))
+}
@@ -0,0 +1,99 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordSleeps substitui a espera por um registro das durações
+func recordSleeps(t *testing.T) *[]time.Duration {
+	var sleeps []time.Duration
+	original := sleep
+	sleep = func(ctx context.Context, d time.Duration) error {
+		sleeps = append(sleeps, d)
+		return ctx.Err()
+	}
+	t.Cleanup(func() { sleep = original })
+	return &sleeps
+}
+
+func TestDo(t *testing.T) {
+	sleeps := recordSleeps(t)
+	policy := Policy{MaxAttempts: 4, InitialDelay: 100 * time.Millisecond, MaxDelay: 250 * time.Millisecond, Multiplier: 2}
+	transient := errors.New("indisponível")
+
+	// Repete até o sucesso, com espera exponencial limitada por MaxDelay
+	calls := 0
+	err := Do(context.Background(), policy, func(ctx context.Context) error {
+		calls++
+		if calls < 4 {
+			return transient
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 4, calls)
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 250 * time.Millisecond}, *sleeps)
+
+	// Esgotadas as tentativas, retorna o último erro
+	calls = 0
+	err = Do(context.Background(), policy, func(ctx context.Context) error {
+		calls++
+		return transient
+	})
+	assert.Equal(t, transient, err)
+	assert.Equal(t, 4, calls)
+
+	// Erros definitivos e não transitórios não são repetidos
+	calls = 0
+	err = Do(context.Background(), policy, func(ctx context.Context) error {
+		calls++
+		return Permanent(transient)
+	})
+	assert.Equal(t, transient, err)
+	assert.Equal(t, 1, calls)
+
+	calls = 0
+	err = Do(context.Background(), policy.WithRetryable(func(err error) bool { return false }), func(ctx context.Context) error {
+		calls++
+		return transient
+	})
+	assert.Equal(t, transient, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestDoContext(t *testing.T) {
+	recordSleeps(t)
+	transient := errors.New("indisponível")
+
+	// O cancelamento interrompe as tentativas, preservando a última falha
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	err := Do(ctx, DefaultPolicy(), func(ctx context.Context) error {
+		calls++
+		cancel()
+		return transient
+	})
+	assert.Equal(t, 1, calls)
+	assert.ErrorIs(t, err, transient)
+
+	// DoValue retorna o valor da tentativa bem-sucedida
+	value, err := DoValue(context.Background(), DefaultPolicy(), func(ctx context.Context) (string, error) {
+		return "ok", nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", value)
+}
+
+func TestJitter(t *testing.T) {
+	policy := Policy{Jitter: 0.5}
+	for i := 0; i < 100; i++ {
+		d := policy.jittered(time.Second)
+		assert.GreaterOrEqual(t, d, 500*time.Millisecond)
+		assert.LessOrEqual(t, d, time.Second)
+	}
+}